@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/config"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/platform/email"
 	"github.com/munchies/platform/backend/internal/platform/sms"
 	"github.com/munchies/platform/backend/internal/server"
 	redisclient "github.com/munchies/platform/backend/internal/platform/redis"
@@ -64,11 +65,25 @@ func main() {
 		smsSender = &sms.NoopSender{}
 	}
 
+	// Email sender
+	var emailSender email.Sender
+	if cfg.Services.EmailAPIKey != "" && cfg.Services.EmailFrom != "" {
+		emailSender = email.New(email.Config{
+			Provider: cfg.Services.EmailProvider,
+			APIKey:   cfg.Services.EmailAPIKey,
+			From:     cfg.Services.EmailFrom,
+		})
+	} else {
+		log.Warn().Msg("email credentials not configured — using noop sender")
+		emailSender = &email.NoopClient{}
+	}
+
 	deps := server.Deps{
 		Queries: queries,
 		Pool:    pool,
 		Redis:   redisClient,
 		SMS:     smsSender,
+		Email:   emailSender,
 	}
 
 	srv := server.New(cfg, deps)
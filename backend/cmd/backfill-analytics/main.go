@@ -0,0 +1,78 @@
+// Command backfill-analytics rebuilds order_analytics facts for historical
+// terminal orders. Existing facts in the range are overwritten, so it is safe
+// to re-run.
+//
+//	go run ./cmd/backfill-analytics -from 2025-01-01 -to 2025-07-01 -tenant <uuid>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/munchies/platform/backend/internal/config"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/analytics"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+)
+
+func main() {
+	from := flag.String("from", "2000-01-01", "first order date to rebuild (YYYY-MM-DD, Asia/Dhaka)")
+	to := flag.String("to", "", "last order date to rebuild, inclusive (default today)")
+	tenant := flag.String("tenant", "", "only rebuild this tenant's orders")
+	batch := flag.Int("batch", 500, "orders per query")
+	flag.Parse()
+
+	opts := analytics.BackfillOptions{BatchSize: int32(*batch)}
+	var err error
+	if opts.From, err = timeutil.ParseBD("2006-01-02", *from); err != nil {
+		fail("invalid -from: %v", err)
+	}
+	opts.To = timeutil.StartOfDayBD(time.Now()).AddDate(0, 0, 1)
+	if *to != "" {
+		end, err := timeutil.ParseBD("2006-01-02", *to)
+		if err != nil {
+			fail("invalid -to: %v", err)
+		}
+		opts.To = end.AddDate(0, 0, 1)
+	}
+	if *tenant != "" {
+		id, err := uuid.Parse(*tenant)
+		if err != nil {
+			fail("invalid -tenant: %v", err)
+		}
+		opts.TenantID = &id
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fail("load config: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := pgxpool.New(ctx, cfg.Database.URL)
+	if err != nil {
+		fail("connect db: %v", err)
+	}
+	defer pool.Close()
+
+	started := time.Now()
+	n, err := analytics.NewService(sqlc.New(pool), pool).BackfillOrderFacts(ctx, opts)
+	if err != nil {
+		fail("backfill stopped after %d orders: %v", n, err)
+	}
+	fmt.Printf("rebuilt facts for %d orders in %s\n", n, time.Since(started).Round(time.Second))
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
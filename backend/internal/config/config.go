@@ -35,6 +35,8 @@ type Config struct {
 	JWT      JWTConfig
 	Storage  StorageConfig
 	Services ExternalServicesConfig
+	Tracking TrackingConfig
+	PDF      PDFConfig
 }
 
 type ServerConfig struct {
@@ -68,6 +70,27 @@ type StorageConfig struct {
 	S3Endpoint string
 	AWSKey     string
 	AWSSecret  string
+	// LocalDir is where generated files such as report exports are kept
+	// until object storage is wired up.
+	LocalDir string
+}
+
+// TrackingConfig tunes the rider location pipeline.
+type TrackingConfig struct {
+	FlushInterval      time.Duration // how often buffered locations are written
+	HistoryInterval    time.Duration // longest gap between history points of a rider
+	HistoryMinDistance float64       // metres moved before a new history point is kept
+	MaxAccuracy        float64       // fixes less accurate than this (metres) are dropped
+	MaxSpeedKmh        float64       // implied speeds above this are treated as GPS jumps
+	StaleAfter         time.Duration // on-duty riders silent this long are marked stale
+	HistoryRetention   time.Duration // history older than this is deleted
+}
+
+// PDFConfig points at the TrueType fonts used for Bangla text in generated
+// invoices and receipts. Latin text uses the built-in Helvetica.
+type PDFConfig struct {
+	BanglaFont     string
+	BanglaBoldFont string // optional; regular glyphs are emboldened without it
 }
 
 type ExternalServicesConfig struct {
@@ -82,6 +105,9 @@ type ExternalServicesConfig struct {
 	FirebaseEmail   string
 	SMSAPIKey       string
 	SMSBaseURL      string
+	EmailProvider   string
+	EmailAPIKey     string
+	EmailFrom       string
 	BarikoiAPIKey   string
 	SentryDSN       string
 }
@@ -103,6 +129,16 @@ func Load() (*Config, error) {
 	v.SetDefault("DB_CONN_MAX_LIFETIME", "5m")
 	v.SetDefault("JWT_ACCESS_EXPIRY", "15m")
 	v.SetDefault("JWT_REFRESH_EXPIRY", "168h")
+	v.SetDefault("RIDER_LOCATION_FLUSH_INTERVAL", "5s")
+	v.SetDefault("RIDER_LOCATION_HISTORY_INTERVAL", "30s")
+	v.SetDefault("RIDER_LOCATION_HISTORY_MIN_DISTANCE_M", 25)
+	v.SetDefault("RIDER_LOCATION_MAX_ACCURACY_M", 100)
+	v.SetDefault("RIDER_LOCATION_MAX_SPEED_KMH", 120)
+	v.SetDefault("RIDER_LOCATION_STALE_AFTER", "2m")
+	v.SetDefault("RIDER_LOCATION_RETENTION", "720h")
+	v.SetDefault("STORAGE_LOCAL_DIR", "storage")
+	v.SetDefault("PDF_FONT_BANGLA", "assets/fonts/NotoSansBengali-Regular.ttf")
+	v.SetDefault("PDF_FONT_BANGLA_BOLD", "assets/fonts/NotoSansBengali-Bold.ttf")
 
 	// Read .env file (ignore if not found)
 	_ = v.ReadInConfig()
@@ -153,6 +189,7 @@ func Load() (*Config, error) {
 			S3Endpoint: v.GetString("S3_ENDPOINT"),
 			AWSKey:     v.GetString("AWS_ACCESS_KEY_ID"),
 			AWSSecret:  v.GetString("AWS_SECRET_ACCESS_KEY"),
+			LocalDir:   v.GetString("STORAGE_LOCAL_DIR"),
 		},
 		Services: ExternalServicesConfig{
 			BkashAppKey:     v.GetString("BKASH_APP_KEY"),
@@ -166,14 +203,39 @@ func Load() (*Config, error) {
 			FirebaseEmail:   v.GetString("FIREBASE_CLIENT_EMAIL"),
 			SMSAPIKey:       v.GetString("SMS_API_KEY"),
 			SMSBaseURL:      v.GetString("SMS_BASE_URL"),
+			EmailProvider:   v.GetString("EMAIL_PROVIDER"),
+			EmailAPIKey:     v.GetString("EMAIL_API_KEY"),
+			EmailFrom:       v.GetString("EMAIL_FROM"),
 			BarikoiAPIKey:   v.GetString("BARIKOI_API_KEY"),
 			SentryDSN:       v.GetString("SENTRY_DSN"),
 		},
+		Tracking: TrackingConfig{
+			FlushInterval:      parseDuration(v.GetString("RIDER_LOCATION_FLUSH_INTERVAL"), 5*time.Second),
+			HistoryInterval:    parseDuration(v.GetString("RIDER_LOCATION_HISTORY_INTERVAL"), 30*time.Second),
+			HistoryMinDistance: v.GetFloat64("RIDER_LOCATION_HISTORY_MIN_DISTANCE_M"),
+			MaxAccuracy:        v.GetFloat64("RIDER_LOCATION_MAX_ACCURACY_M"),
+			MaxSpeedKmh:        v.GetFloat64("RIDER_LOCATION_MAX_SPEED_KMH"),
+			StaleAfter:         parseDuration(v.GetString("RIDER_LOCATION_STALE_AFTER"), 2*time.Minute),
+			HistoryRetention:   parseDuration(v.GetString("RIDER_LOCATION_RETENTION"), 30*24*time.Hour),
+		},
+		PDF: PDFConfig{
+			BanglaFont:     v.GetString("PDF_FONT_BANGLA"),
+			BanglaBoldFont: v.GetString("PDF_FONT_BANGLA_BOLD"),
+		},
 	}
 
 	return cfg, nil
 }
 
+// parseDuration parses a positive duration, falling back on bad input.
+func parseDuration(raw string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
 func parseEnvironment(env string) Environment {
 	switch strings.ToLower(strings.TrimSpace(env)) {
 	case "development", "dev":
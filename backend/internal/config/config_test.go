@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoad_Defaults(t *testing.T) {
@@ -105,3 +106,22 @@ func TestEnvironment_IsDevelopment(t *testing.T) {
 		t.Error("expected EnvProduction.IsDevelopment() to be false")
 	}
 }
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"5s", 5 * time.Second},
+		{"2m", 2 * time.Minute},
+		{"", time.Minute},     // default
+		{"soon", time.Minute}, // invalid
+		{"-5s", time.Minute},  // not positive
+	}
+
+	for _, tc := range tests {
+		if result := parseDuration(tc.input, time.Minute); result != tc.expected {
+			t.Errorf("parseDuration(%q) = %s, want %s", tc.input, result, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS purchase_order_items;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS suppliers;
+DROP TYPE IF EXISTS purchase_order_status;
@@ -0,0 +1,75 @@
+-- ============================================================
+-- 000022_create_purchasing.up.sql
+-- Suppliers, purchase orders and receiving for inventory replenishment
+-- ============================================================
+
+CREATE TYPE purchase_order_status AS ENUM (
+    'draft', 'sent', 'partially_received', 'received', 'cancelled'
+);
+
+-- ---- Suppliers ----
+CREATE TABLE suppliers (
+    id              UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID            NOT NULL REFERENCES tenants(id),
+    name            TEXT            NOT NULL,
+    contact_name    TEXT,
+    phone           TEXT,
+    email           TEXT,
+    address         TEXT,
+    lead_time_days  INT             NOT NULL DEFAULT 1 CHECK (lead_time_days >= 0),
+    notes           TEXT,
+    is_active       BOOLEAN         NOT NULL DEFAULT true,
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_suppliers_tenant ON suppliers(tenant_id, name);
+
+CREATE TRIGGER trg_suppliers_updated_at
+    BEFORE UPDATE ON suppliers
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Purchase Orders ----
+-- Lifecycle: draft -> sent -> partially_received -> received (or cancelled before receiving).
+CREATE TABLE purchase_orders (
+    id              UUID                    PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID                    NOT NULL REFERENCES tenants(id),
+    restaurant_id   UUID                    NOT NULL REFERENCES restaurants(id),
+    supplier_id     UUID                    NOT NULL REFERENCES suppliers(id),
+    po_number       TEXT                    NOT NULL,
+    status          purchase_order_status   NOT NULL DEFAULT 'draft',
+    expected_at     TIMESTAMPTZ,
+    total_amount    NUMERIC(12,2)           NOT NULL DEFAULT 0.00,
+    notes           TEXT,
+    created_by      UUID                    REFERENCES users(id),
+    sent_at         TIMESTAMPTZ,
+    received_at     TIMESTAMPTZ,
+    cancelled_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ             NOT NULL DEFAULT NOW(),
+    UNIQUE(tenant_id, po_number)
+);
+
+CREATE INDEX idx_purchase_orders_restaurant ON purchase_orders(tenant_id, restaurant_id, created_at DESC);
+CREATE INDEX idx_purchase_orders_supplier   ON purchase_orders(supplier_id);
+
+CREATE TRIGGER trg_purchase_orders_updated_at
+    BEFORE UPDATE ON purchase_orders
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Purchase Order Items ----
+CREATE TABLE purchase_order_items (
+    id                  UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    purchase_order_id   UUID            NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    tenant_id           UUID            NOT NULL REFERENCES tenants(id),
+    inventory_item_id   UUID            NOT NULL REFERENCES inventory_items(id),
+    product_id          UUID            NOT NULL REFERENCES products(id),
+    qty_ordered         INT             NOT NULL CHECK (qty_ordered > 0),
+    qty_received        INT             NOT NULL DEFAULT 0 CHECK (qty_received >= 0),
+    unit_cost           NUMERIC(10,2)   NOT NULL CHECK (unit_cost >= 0),
+    created_at          TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    UNIQUE(purchase_order_id, inventory_item_id),
+    CHECK (qty_received <= qty_ordered)
+);
+
+CREATE INDEX idx_purchase_order_items_po ON purchase_order_items(purchase_order_id);
@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS promo_time_windows;
+DROP TABLE IF EXISTS promo_tiers;
+DROP TABLE IF EXISTS promo_product_restrictions;
+
+ALTER TABLE promos
+    DROP CONSTRAINT IF EXISTS chk_promos_new_customer_days,
+    DROP CONSTRAINT IF EXISTS chk_promos_bogo_qty,
+    DROP CONSTRAINT IF EXISTS promos_discount_amount_check,
+    ADD CONSTRAINT promos_discount_amount_check CHECK (discount_amount > 0);
+
+ALTER TABLE promos
+    DROP COLUMN IF EXISTS new_customer_days,
+    DROP COLUMN IF EXISTS first_order_only,
+    DROP COLUMN IF EXISTS get_qty,
+    DROP COLUMN IF EXISTS buy_qty,
+    DROP COLUMN IF EXISTS rule_type;
+
+DROP TYPE IF EXISTS promo_rule;
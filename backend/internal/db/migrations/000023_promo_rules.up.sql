@@ -0,0 +1,60 @@
+-- ============================================================
+-- 000023_promo_rules.up.sql
+-- Rule-based promotions: BOGO, tiered spend, product restrictions,
+-- first-order / new-customer conditions and time-of-day windows
+-- ============================================================
+
+CREATE TYPE promo_rule AS ENUM ('standard', 'bogo', 'tiered');
+
+-- ---- Promo rule columns ----
+-- standard: discount_amount (fixed/percent) over eligible items or delivery
+-- bogo:     buy buy_qty eligible units, get get_qty discounted by discount_amount
+-- tiered:   discount taken from the highest promo_tiers row reached
+ALTER TABLE promos
+    ADD COLUMN rule_type         promo_rule NOT NULL DEFAULT 'standard',
+    ADD COLUMN buy_qty           INT,
+    ADD COLUMN get_qty           INT,
+    ADD COLUMN first_order_only  BOOLEAN    NOT NULL DEFAULT false,
+    ADD COLUMN new_customer_days INT;                     -- NULL = any account age
+
+-- Tiered promos carry their amounts on the tiers.
+ALTER TABLE promos
+    DROP CONSTRAINT promos_discount_amount_check,
+    ADD CONSTRAINT promos_discount_amount_check CHECK (discount_amount >= 0),
+    ADD CONSTRAINT chk_promos_bogo_qty CHECK (rule_type <> 'bogo' OR (buy_qty > 0 AND get_qty > 0)),
+    ADD CONSTRAINT chk_promos_new_customer_days CHECK (new_customer_days IS NULL OR new_customer_days > 0);
+
+-- ---- Promo Product Restrictions ----
+-- Limits the discount to these products (combined with restaurant/category restrictions).
+CREATE TABLE promo_product_restrictions (
+    promo_id   UUID NOT NULL REFERENCES promos(id)   ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    PRIMARY KEY (promo_id, product_id)
+);
+
+-- ---- Promo Tiers ----
+-- Spend thresholds for rule_type = 'tiered'. discount_amount follows promo_type.
+CREATE TABLE promo_tiers (
+    id              UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    promo_id        UUID          NOT NULL REFERENCES promos(id) ON DELETE CASCADE,
+    min_subtotal    NUMERIC(10,2) NOT NULL CHECK (min_subtotal >= 0),
+    discount_amount NUMERIC(10,2) NOT NULL CHECK (discount_amount > 0),
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+
+    UNIQUE(promo_id, min_subtotal)
+);
+
+-- ---- Promo Time Windows ----
+-- Time-of-day windows in Asia/Dhaka local time, as minutes from midnight.
+-- day_of_week follows Go's time.Weekday (0 = Sunday); NULL = every day.
+-- A window with start_minute > end_minute runs past midnight.
+CREATE TABLE promo_time_windows (
+    id           UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    promo_id     UUID        NOT NULL REFERENCES promos(id) ON DELETE CASCADE,
+    day_of_week  INT         CHECK (day_of_week BETWEEN 0 AND 6),
+    start_minute INT         NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute   INT         NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_promo_time_windows_promo ON promo_time_windows(promo_id);
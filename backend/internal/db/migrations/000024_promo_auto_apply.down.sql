@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_promos_auto_apply;
+
+ALTER TABLE promos
+    DROP COLUMN IF EXISTS stacking,
+    DROP COLUMN IF EXISTS auto_apply;
+
+DROP TYPE IF EXISTS promo_stacking;
@@ -0,0 +1,16 @@
+-- ============================================================
+-- 000024_promo_auto_apply.up.sql
+-- Codeless (auto-applied) promotions and stacking policy
+-- ============================================================
+
+CREATE TYPE promo_stacking AS ENUM ('exclusive', 'stackable');
+
+-- auto_apply: applied at checkout without the customer entering the code.
+-- stacking:   exclusive promos are never combined; stackable promos combine
+--             with other stackable promos of a different funder.
+ALTER TABLE promos
+    ADD COLUMN auto_apply BOOLEAN        NOT NULL DEFAULT false,
+    ADD COLUMN stacking   promo_stacking NOT NULL DEFAULT 'exclusive';
+
+CREATE INDEX idx_promos_auto_apply ON promos(tenant_id)
+    WHERE auto_apply = true AND is_active = true;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_promo_usages_tenant_created;
+DROP INDEX IF EXISTS idx_promo_usages_order_id;
+
+ALTER TABLE promo_usages
+    DROP COLUMN IF EXISTS reversed_at,
+    DROP COLUMN IF EXISTS funded_by;
+
+ALTER TABLE promos
+    DROP COLUMN IF EXISTS budget_exhausted_at,
+    DROP COLUMN IF EXISTS budget_cap;
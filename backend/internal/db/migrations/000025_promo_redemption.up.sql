@@ -0,0 +1,26 @@
+-- ============================================================
+-- 000025_promo_redemption.up.sql
+-- Promo budget caps, usage reversal and funder snapshot on usages
+-- ============================================================
+
+-- ---- Promos: budget cap ----
+-- budget_cap:          total discount the promo may give; NULL = unlimited.
+-- budget_exhausted_at: set when redemptions spend the budget and the promo is
+--                      deactivated automatically; cleared when a reversal
+--                      frees budget again or the promo is deactivated by hand.
+ALTER TABLE promos
+    ADD COLUMN budget_cap          NUMERIC(14,2) CHECK (budget_cap > 0),
+    ADD COLUMN budget_exhausted_at TIMESTAMPTZ;
+
+-- ---- Promo usages: reversal and funder ----
+ALTER TABLE promo_usages
+    ADD COLUMN funded_by   promo_funder NOT NULL DEFAULT 'platform',
+    ADD COLUMN reversed_at TIMESTAMPTZ;
+
+UPDATE promo_usages pu
+SET funded_by = p.funded_by
+FROM promos p
+WHERE p.id = pu.promo_id;
+
+CREATE INDEX idx_promo_usages_order_id ON promo_usages(order_id);
+CREATE INDEX idx_promo_usages_tenant_created ON promo_usages(tenant_id, created_at);
@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS idx_rider_penalties_unsettled;
+
+ALTER TABLE rider_penalties
+    DROP COLUMN IF EXISTS payout_id;
+
+DROP INDEX IF EXISTS idx_rider_payouts_tenant;
+DROP INDEX IF EXISTS idx_rider_payouts_batch;
+
+ALTER TABLE rider_payouts
+    DROP COLUMN IF EXISTS payout_account,
+    DROP COLUMN IF EXISTS cod_offset,
+    DROP COLUMN IF EXISTS penalty_deductions,
+    DROP COLUMN IF EXISTS gross_earnings,
+    DROP COLUMN IF EXISTS batch_id,
+    DROP CONSTRAINT IF EXISTS rider_payouts_amount_check;
+
+ALTER TABLE rider_payouts
+    ADD CONSTRAINT rider_payouts_amount_check CHECK (amount > 0) NOT VALID;
+
+DROP TABLE IF EXISTS rider_payout_batches;
+
+ALTER TABLE riders
+    DROP COLUMN IF EXISTS cash_in_hand;
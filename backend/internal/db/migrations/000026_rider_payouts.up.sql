@@ -0,0 +1,53 @@
+-- ============================================================
+-- 000026_rider_payouts.up.sql
+-- Rider payout batches, penalty and COD netting, cash in hand
+-- ============================================================
+
+-- ---- Riders: cash in hand ----
+-- COD cash collected at delivery that the rider has not yet handed over.
+ALTER TABLE riders
+    ADD COLUMN cash_in_hand NUMERIC(12,2) NOT NULL DEFAULT 0.00;
+
+-- ---- Rider Payout Batches ----
+-- One batch per settlement run (weekly schedule or on demand by a partner).
+CREATE TABLE rider_payout_batches (
+    id              UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID            NOT NULL REFERENCES tenants(id),
+    kind            TEXT            NOT NULL CHECK (kind IN ('weekly', 'on_demand')),
+    period_from     DATE            NOT NULL,
+    period_to       DATE            NOT NULL,
+    payout_count    INT             NOT NULL DEFAULT 0,
+    total_amount    NUMERIC(14,2)   NOT NULL DEFAULT 0.00,
+    created_by      UUID            REFERENCES users(id),
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    CHECK (period_to >= period_from)
+);
+
+CREATE INDEX idx_rider_payout_batches_tenant ON rider_payout_batches(tenant_id, created_at DESC);
+CREATE UNIQUE INDEX uniq_rider_payout_batches_weekly
+    ON rider_payout_batches(tenant_id, period_from)
+    WHERE kind = 'weekly';
+
+-- ---- Rider Payouts: breakdown and batch ----
+-- amount = gross_earnings - penalty_deductions - cod_offset. A payout fully
+-- offset by penalties and cash in hand settles at zero.
+ALTER TABLE rider_payouts
+    DROP CONSTRAINT IF EXISTS rider_payouts_amount_check;
+
+ALTER TABLE rider_payouts
+    ADD CONSTRAINT rider_payouts_amount_check CHECK (amount >= 0),
+    ADD COLUMN batch_id           UUID          REFERENCES rider_payout_batches(id),
+    ADD COLUMN gross_earnings     NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN penalty_deductions NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN cod_offset         NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN payout_account     TEXT;
+
+CREATE INDEX idx_rider_payouts_batch ON rider_payouts(batch_id) WHERE batch_id IS NOT NULL;
+CREATE INDEX idx_rider_payouts_tenant ON rider_payouts(tenant_id, status, created_at DESC);
+
+-- ---- Rider Penalties: payout deduction ----
+ALTER TABLE rider_penalties
+    ADD COLUMN payout_id UUID REFERENCES rider_payouts(id);
+
+CREATE INDEX idx_rider_penalties_unsettled ON rider_penalties(rider_id)
+    WHERE status = 'pending' AND payout_id IS NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE rider_earnings
+    DROP COLUMN IF EXISTS breakdown,
+    DROP COLUMN IF EXISTS surge_bonus,
+    DROP COLUMN IF EXISTS surge_multiplier,
+    DROP COLUMN IF EXISTS multi_pickup_bonus,
+    DROP COLUMN IF EXISTS pickup_count,
+    DROP COLUMN IF EXISTS distance_km,
+    DROP COLUMN IF EXISTS vehicle_type,
+    DROP COLUMN IF EXISTS rule_id;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS rider_tip;
+
+DROP TABLE IF EXISTS rider_earning_surges;
+DROP TABLE IF EXISTS rider_earning_peak_windows;
+DROP TRIGGER IF EXISTS trg_rider_earning_rules_updated_at ON rider_earning_rules;
+DROP TABLE IF EXISTS rider_earning_rules;
@@ -0,0 +1,89 @@
+-- ============================================================
+-- 000027_rider_earning_rules.up.sql
+-- Configurable rider earning rules, peak windows, surges and tips
+-- ============================================================
+
+-- ---- Rider Earning Rules ----
+-- A rule applies to a tenant, optionally narrowed to a hub and/or a vehicle
+-- type. The most specific active rule wins: hub + vehicle, hub, vehicle,
+-- tenant-wide.
+CREATE TABLE rider_earning_rules (
+    id                   UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id            UUID          NOT NULL REFERENCES tenants(id),
+    hub_id               UUID          REFERENCES hubs(id) ON DELETE CASCADE,
+    vehicle_type         vehicle_type,
+    name                 TEXT          NOT NULL,
+    base_pay             NUMERIC(10,2) NOT NULL CHECK (base_pay >= 0),
+    per_km_rate          NUMERIC(10,2) NOT NULL DEFAULT 0.00 CHECK (per_km_rate >= 0),
+    included_km          NUMERIC(6,2)  NOT NULL DEFAULT 0.00 CHECK (included_km >= 0),
+    max_distance_pay     NUMERIC(10,2) CHECK (max_distance_pay >= 0),
+    extra_pickup_bonus   NUMERIC(10,2) NOT NULL DEFAULT 0.00 CHECK (extra_pickup_bonus >= 0),
+    is_active            BOOLEAN       NOT NULL DEFAULT true,
+    created_by           UUID          REFERENCES users(id),
+    created_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uniq_rider_earning_rules_scope ON rider_earning_rules (
+    tenant_id,
+    COALESCE(hub_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(vehicle_type::text, '')
+);
+
+CREATE TRIGGER trg_rider_earning_rules_updated_at
+    BEFORE UPDATE ON rider_earning_rules
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Peak Windows ----
+-- Minutes are Asia/Dhaka wall-clock time; day_of_week NULL means every day
+-- (0 = Sunday). The largest matching bonus applies.
+CREATE TABLE rider_earning_peak_windows (
+    id           UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule_id      UUID          NOT NULL REFERENCES rider_earning_rules(id) ON DELETE CASCADE,
+    day_of_week  INT           CHECK (day_of_week BETWEEN 0 AND 6),
+    start_minute INT           NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute   INT           NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    bonus_amount NUMERIC(10,2) NOT NULL CHECK (bonus_amount >= 0),
+    created_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CHECK (end_minute > start_minute)
+);
+
+CREATE INDEX idx_rider_earning_peak_windows_rule ON rider_earning_peak_windows(rule_id);
+
+-- ---- Surges ----
+-- Rain and demand surges declared by the operations team. While active, the
+-- delivery pay (everything except tips) is multiplied; the highest rain and
+-- the highest surge multiplier compound.
+CREATE TABLE rider_earning_surges (
+    id           UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    UUID          NOT NULL REFERENCES tenants(id),
+    hub_id       UUID          REFERENCES hubs(id) ON DELETE CASCADE,
+    kind         TEXT          NOT NULL CHECK (kind IN ('rain', 'surge')),
+    multiplier   NUMERIC(4,2)  NOT NULL CHECK (multiplier >= 1 AND multiplier <= 5),
+    reason       TEXT,
+    starts_at    TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    ends_at      TIMESTAMPTZ,
+    created_by   UUID          REFERENCES users(id),
+    created_at   TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX idx_rider_earning_surges_tenant ON rider_earning_surges(tenant_id, starts_at DESC);
+
+-- ---- Orders: rider tip ----
+-- Paid by the customer at checkout and passed to the rider in full.
+ALTER TABLE orders
+    ADD COLUMN rider_tip NUMERIC(10,2) NOT NULL DEFAULT 0.00 CHECK (rider_tip >= 0);
+
+-- ---- Rider Earnings: audit breakdown ----
+-- total_earning = (base_earning + distance_bonus + multi_pickup_bonus
+--                  + peak_bonus) * surge_multiplier + tip_amount
+ALTER TABLE rider_earnings
+    ADD COLUMN rule_id            UUID          REFERENCES rider_earning_rules(id) ON DELETE SET NULL,
+    ADD COLUMN vehicle_type       vehicle_type,
+    ADD COLUMN distance_km        NUMERIC(8,3)  NOT NULL DEFAULT 0.000,
+    ADD COLUMN pickup_count       INT           NOT NULL DEFAULT 1,
+    ADD COLUMN multi_pickup_bonus NUMERIC(10,2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN surge_multiplier   NUMERIC(4,2)  NOT NULL DEFAULT 1.00,
+    ADD COLUMN surge_bonus        NUMERIC(10,2) NOT NULL DEFAULT 0.00,
+    ADD COLUMN breakdown          JSONB         NOT NULL DEFAULT '{}';
@@ -0,0 +1,9 @@
+-- Enum values added to ledger_entry_type cannot be dropped and are left in place.
+DELETE FROM ledger_accounts a
+WHERE a.code IN ('COD_RECEIVABLE', 'RIDER_CASH', 'HUB_CASH')
+  AND NOT EXISTS (SELECT 1 FROM ledger_entries e WHERE e.account_id = a.id);
+
+DROP TABLE IF EXISTS rider_cash_deposits;
+DROP TABLE IF EXISTS cod_collections;
+
+ALTER TABLE hubs DROP COLUMN IF EXISTS rider_cash_limit;
@@ -0,0 +1,66 @@
+-- ============================================================
+-- 000028_cod_cash.up.sql
+-- COD cash collection, rider cash deposits at hubs, cash limits
+-- ============================================================
+
+-- ---- Hubs: rider cash limit ----
+-- Riders holding more COD cash than this get no new orders until they
+-- deposit at the hub. NULL means no limit.
+ALTER TABLE hubs
+    ADD COLUMN rider_cash_limit NUMERIC(12,2) CHECK (rider_cash_limit IS NULL OR rider_cash_limit >= 0);
+
+-- ---- COD Collections ----
+-- One row per delivered COD order: what the customer owed and what the rider
+-- actually collected.
+CREATE TABLE cod_collections (
+    id                  UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id           UUID            NOT NULL REFERENCES tenants(id),
+    order_id            UUID            NOT NULL UNIQUE REFERENCES orders(id),
+    rider_id            UUID            NOT NULL REFERENCES riders(id),
+    hub_id              UUID            REFERENCES hubs(id),
+    expected_amount     NUMERIC(12,2)   NOT NULL CHECK (expected_amount >= 0),
+    collected_amount    NUMERIC(12,2)   NOT NULL CHECK (collected_amount >= 0),
+    variance            NUMERIC(12,2)   GENERATED ALWAYS AS (collected_amount - expected_amount) STORED,
+    note                TEXT,
+    collected_at        TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_cod_collections_hub ON cod_collections(tenant_id, hub_id, collected_at DESC);
+CREATE INDEX idx_cod_collections_rider ON cod_collections(rider_id, collected_at DESC);
+
+-- ---- Rider Cash Deposits ----
+-- Cash a rider hands over at a hub. Only a confirmed deposit reduces the
+-- rider's cash in hand.
+CREATE TABLE rider_cash_deposits (
+    id                  UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id           UUID            NOT NULL REFERENCES tenants(id),
+    rider_id            UUID            NOT NULL REFERENCES riders(id),
+    hub_id              UUID            NOT NULL REFERENCES hubs(id),
+    amount              NUMERIC(12,2)   NOT NULL CHECK (amount > 0),
+    status              TEXT            NOT NULL DEFAULT 'pending'
+                                        CHECK (status IN ('pending', 'confirmed', 'rejected')),
+    reference           TEXT,
+    note                TEXT,
+    reviewed_by         UUID            REFERENCES users(id),
+    reviewed_at         TIMESTAMPTZ,
+    rejection_reason    TEXT,
+    created_at          TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_rider_cash_deposits_hub ON rider_cash_deposits(tenant_id, hub_id, status, created_at DESC);
+CREATE INDEX idx_rider_cash_deposits_rider ON rider_cash_deposits(rider_id, created_at DESC);
+
+CREATE TRIGGER trg_rider_cash_deposits_updated_at
+    BEFORE UPDATE ON rider_cash_deposits
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Ledger ----
+ALTER TYPE ledger_entry_type ADD VALUE IF NOT EXISTS 'cod_collection';
+ALTER TYPE ledger_entry_type ADD VALUE IF NOT EXISTS 'cash_deposit';
+
+INSERT INTO ledger_accounts (code, name, account_type, description, is_system) VALUES
+    ('COD_RECEIVABLE', 'COD Receivable', 'asset', 'Cash due from customers on COD orders', true),
+    ('RIDER_CASH', 'Rider Cash in Hand', 'asset', 'COD cash collected and held by riders', true),
+    ('HUB_CASH', 'Hub Cash', 'asset', 'Cash deposited by riders at hubs', true)
+ON CONFLICT (code) DO NOTHING;
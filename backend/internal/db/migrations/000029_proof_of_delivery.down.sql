@@ -0,0 +1,10 @@
+-- The not_delivered issue_type value cannot be dropped and is left in place.
+DROP TABLE IF EXISTS delivery_proofs;
+
+ALTER TABLE restaurants DROP COLUMN IF EXISTS require_pod;
+
+ALTER TABLE delivery_zone_configs
+    DROP COLUMN IF EXISTS pod_min_order_value,
+    DROP COLUMN IF EXISTS pod_geofence_radius_m,
+    DROP COLUMN IF EXISTS pod_photo_required,
+    DROP COLUMN IF EXISTS pod_otp_enabled;
@@ -0,0 +1,53 @@
+-- ============================================================
+-- 000029_proof_of_delivery.up.sql
+-- Delivery OTP handover, photo and geofenced proof of delivery
+-- ============================================================
+
+-- ---- Tenant POD Policy ----
+-- Proof of delivery is required when any restaurant on the order has
+-- require_pod set, or when the order total reaches pod_min_order_value.
+-- While required, the delivery code is checked when pod_otp_enabled, a photo
+-- when pod_photo_required, and the rider's last location when
+-- pod_geofence_radius_m is set.
+ALTER TABLE delivery_zone_configs
+    ADD COLUMN pod_otp_enabled       BOOLEAN       NOT NULL DEFAULT false,
+    ADD COLUMN pod_photo_required    BOOLEAN       NOT NULL DEFAULT false,
+    ADD COLUMN pod_geofence_radius_m INT           CHECK (pod_geofence_radius_m > 0),
+    ADD COLUMN pod_min_order_value   NUMERIC(10,2) CHECK (pod_min_order_value >= 0);
+
+ALTER TABLE restaurants
+    ADD COLUMN require_pod BOOLEAN NOT NULL DEFAULT false;
+
+-- ---- Delivery Proofs ----
+-- One record per order. The row is opened with the delivery code when the
+-- rider collects the order and completed when it is marked delivered.
+CREATE TABLE delivery_proofs (
+    id                   UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id             UUID          NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    tenant_id            UUID          NOT NULL REFERENCES tenants(id),
+    rider_id             UUID          REFERENCES riders(id),
+    pod_required         BOOLEAN       NOT NULL DEFAULT false,
+    otp_code             TEXT,                          -- 4 digits, shown to the customer
+    otp_attempts         INT           NOT NULL DEFAULT 0,
+    otp_verified_at      TIMESTAMPTZ,
+    photo_url            TEXT,
+    rider_geo_lat        NUMERIC(10,8),
+    rider_geo_lng        NUMERIC(11,8),
+    location_recorded_at TIMESTAMPTZ,
+    distance_meters      NUMERIC(10,2),
+    geofence_radius_m    INT,
+    within_geofence      BOOLEAN,                       -- NULL when no geofence check ran
+    delivered_at         TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_delivery_proofs_tenant ON delivery_proofs(tenant_id, created_at DESC);
+CREATE INDEX idx_delivery_proofs_rider  ON delivery_proofs(rider_id) WHERE rider_id IS NOT NULL;
+
+CREATE TRIGGER trg_delivery_proofs_updated_at
+    BEFORE UPDATE ON delivery_proofs
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Issue Types ----
+ALTER TYPE issue_type ADD VALUE IF NOT EXISTS 'not_delivered';
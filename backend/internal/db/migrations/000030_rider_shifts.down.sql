@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS rider_shift_swaps;
+DROP TABLE IF EXISTS rider_shifts;
+DROP TABLE IF EXISTS rider_shift_templates;
+
+ALTER TABLE hubs DROP COLUMN IF EXISTS checkin_radius_m;
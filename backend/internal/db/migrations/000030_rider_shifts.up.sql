@@ -0,0 +1,103 @@
+-- ============================================================
+-- 000030_rider_shifts.up.sql
+-- Shift templates, rider rosters, shift swaps and check-in geofence
+-- ============================================================
+
+-- ---- Hub Check-in Geofence ----
+-- Riders must check in within this many metres of the hub. NULL disables the
+-- check.
+ALTER TABLE hubs
+    ADD COLUMN checkin_radius_m INT CHECK (checkin_radius_m > 0);
+
+-- ---- Shift Templates ----
+-- A recurring shift at a hub. Minutes are Asia/Dhaka wall-clock time and a
+-- shift never crosses midnight; day_of_week values use 0 = Sunday. Once a hub
+-- has an active template, its riders can only check in for a rostered shift.
+CREATE TABLE rider_shift_templates (
+    id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id        UUID          NOT NULL REFERENCES tenants(id),
+    hub_id           UUID          NOT NULL REFERENCES hubs(id) ON DELETE CASCADE,
+    name             TEXT          NOT NULL,
+    start_minute     INT           NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute       INT           NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    days_of_week     INT[]         NOT NULL DEFAULT '{0,1,2,3,4,5,6}',
+    required_riders  INT           NOT NULL DEFAULT 1 CHECK (required_riders >= 0),
+    grace_minutes    INT           NOT NULL DEFAULT 10 CHECK (grace_minutes >= 0),
+    late_penalty     NUMERIC(10,2) NOT NULL DEFAULT 0.00 CHECK (late_penalty >= 0),
+    absence_penalty  NUMERIC(10,2) NOT NULL DEFAULT 0.00 CHECK (absence_penalty >= 0),
+    is_active        BOOLEAN       NOT NULL DEFAULT true,
+    created_by       UUID          REFERENCES users(id),
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (hub_id, name),
+    CHECK (end_minute > start_minute)
+);
+
+CREATE INDEX idx_rider_shift_templates_tenant ON rider_shift_templates(tenant_id, hub_id);
+
+CREATE TRIGGER trg_rider_shift_templates_updated_at
+    BEFORE UPDATE ON rider_shift_templates
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Rider Shifts ----
+-- One rostered shift for one rider. Grace period and penalties are copied from
+-- the template so later template edits don't change past shifts.
+CREATE TABLE rider_shifts (
+    id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id        UUID          NOT NULL REFERENCES tenants(id),
+    hub_id           UUID          NOT NULL REFERENCES hubs(id) ON DELETE CASCADE,
+    template_id      UUID          REFERENCES rider_shift_templates(id) ON DELETE SET NULL,
+    rider_id         UUID          NOT NULL REFERENCES riders(id) ON DELETE CASCADE,
+    shift_date       DATE          NOT NULL,
+    starts_at        TIMESTAMPTZ   NOT NULL,
+    ends_at          TIMESTAMPTZ   NOT NULL,
+    status           TEXT          NOT NULL DEFAULT 'scheduled'
+                                   CHECK (status IN ('scheduled', 'checked_in', 'completed', 'absent', 'cancelled')),
+    grace_minutes    INT           NOT NULL DEFAULT 10,
+    late_penalty     NUMERIC(10,2) NOT NULL DEFAULT 0.00,
+    absence_penalty  NUMERIC(10,2) NOT NULL DEFAULT 0.00,
+    checked_in_at    TIMESTAMPTZ,
+    late_minutes     INT           NOT NULL DEFAULT 0,
+    attendance_id    UUID          REFERENCES rider_attendance(id) ON DELETE SET NULL,
+    penalty_id       UUID          REFERENCES rider_penalties(id) ON DELETE SET NULL,
+    created_by       UUID          REFERENCES users(id),
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (rider_id, starts_at),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_rider_shifts_hub_date ON rider_shifts(tenant_id, hub_id, shift_date);
+CREATE INDEX idx_rider_shifts_rider    ON rider_shifts(rider_id, starts_at);
+CREATE INDEX idx_rider_shifts_open     ON rider_shifts(ends_at) WHERE status = 'scheduled';
+
+CREATE TRIGGER trg_rider_shifts_updated_at
+    BEFORE UPDATE ON rider_shifts
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Shift Swaps ----
+-- A rider offers a shift to a colleague; the colleague accepts and the hub
+-- manager approves before the shift changes hands.
+CREATE TABLE rider_shift_swaps (
+    id               UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id        UUID          NOT NULL REFERENCES tenants(id),
+    shift_id         UUID          NOT NULL REFERENCES rider_shifts(id) ON DELETE CASCADE,
+    requester_id     UUID          NOT NULL REFERENCES riders(id) ON DELETE CASCADE,
+    target_rider_id  UUID          NOT NULL REFERENCES riders(id) ON DELETE CASCADE,
+    status           TEXT          NOT NULL DEFAULT 'pending'
+                                   CHECK (status IN ('pending', 'accepted', 'declined', 'approved', 'rejected', 'cancelled')),
+    reason           TEXT,
+    review_note      TEXT,
+    reviewed_by      UUID          REFERENCES users(id),
+    reviewed_at      TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uniq_rider_shift_swaps_open ON rider_shift_swaps(shift_id)
+    WHERE status IN ('pending', 'accepted');
+CREATE INDEX idx_rider_shift_swaps_tenant ON rider_shift_swaps(tenant_id, status, created_at DESC);
+
+CREATE TRIGGER trg_rider_shift_swaps_updated_at
+    BEFORE UPDATE ON rider_shift_swaps
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
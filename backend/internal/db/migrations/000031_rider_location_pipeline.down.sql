@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_rider_location_history_order_id;
+DROP INDEX IF EXISTS idx_rider_location_history_created_at;
+
+ALTER TABLE rider_locations
+    DROP COLUMN IF EXISTS stale_since,
+    DROP COLUMN IF EXISTS is_stale;
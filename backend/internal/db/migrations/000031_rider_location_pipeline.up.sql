@@ -0,0 +1,16 @@
+-- ============================================================
+-- 000031_rider_location_pipeline.up.sql
+-- Stale rider detection and location history retention
+-- ============================================================
+
+-- ---- Stale Locations ----
+-- An on-duty rider whose app stops reporting is marked stale by the worker
+-- and left out of dispatch until the next location arrives.
+ALTER TABLE rider_locations
+    ADD COLUMN is_stale    BOOLEAN     NOT NULL DEFAULT false,
+    ADD COLUMN stale_since TIMESTAMPTZ;
+
+-- ---- Location History Retention ----
+-- The retention job deletes history by age.
+CREATE INDEX idx_rider_location_history_created_at ON rider_location_history(created_at);
+CREATE INDEX idx_rider_location_history_order_id ON rider_location_history(order_id) WHERE order_id IS NOT NULL;
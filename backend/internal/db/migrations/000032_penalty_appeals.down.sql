@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS invoice_adjustments;
+
+DROP INDEX IF EXISTS uniq_rider_penalties_issue;
+DROP INDEX IF EXISTS idx_rider_penalties_appealed;
+
+ALTER TABLE rider_penalties
+    DROP COLUMN IF EXISTS appeal_reviewed_at,
+    DROP COLUMN IF EXISTS appeal_reviewed_by,
+    DROP COLUMN IF EXISTS appeal_review_note,
+    DROP COLUMN IF EXISTS appeal_outcome,
+    DROP COLUMN IF EXISTS appeal_evidence_urls;
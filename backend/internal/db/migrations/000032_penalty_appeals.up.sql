@@ -0,0 +1,51 @@
+-- ============================================================
+-- 000032_penalty_appeals.up.sql
+-- Rider penalty appeals, penalties from issue resolution and restaurant
+-- invoice adjustments
+-- ============================================================
+
+-- ---- Rider Penalty Appeals ----
+-- A rider appeals a pending penalty with a note and evidence; the penalty is
+-- held out of payouts until a partner reviews it. An overturned appeal clears
+-- the penalty, an upheld one returns it to pending.
+ALTER TABLE rider_penalties
+    ADD COLUMN appeal_evidence_urls TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN appeal_outcome       TEXT        CHECK (appeal_outcome IN ('upheld','overturned')),
+    ADD COLUMN appeal_review_note   TEXT,
+    ADD COLUMN appeal_reviewed_by   UUID        REFERENCES users(id),
+    ADD COLUMN appeal_reviewed_at   TIMESTAMPTZ;
+
+CREATE INDEX idx_rider_penalties_appealed ON rider_penalties(tenant_id, appealed_at)
+    WHERE status = 'appealed';
+
+-- One penalty per rider-accountable issue.
+CREATE UNIQUE INDEX uniq_rider_penalties_issue ON rider_penalties(issue_id)
+    WHERE issue_id IS NOT NULL;
+
+-- ---- Invoice Adjustments ----
+-- Charges against a restaurant that are settled on its next invoice. A
+-- positive amount is owed by the restaurant.
+CREATE TABLE invoice_adjustments (
+    id              UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID          NOT NULL REFERENCES tenants(id),
+    restaurant_id   UUID          NOT NULL REFERENCES restaurants(id),
+    order_id        UUID          REFERENCES orders(id),
+    issue_id        UUID          REFERENCES order_issues(id),
+    amount          NUMERIC(12,2) NOT NULL CHECK (amount <> 0),
+    reason          TEXT          NOT NULL,
+    invoice_id      UUID          REFERENCES invoices(id) ON DELETE SET NULL,
+    created_by      UUID          REFERENCES users(id),
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX uniq_invoice_adjustments_issue ON invoice_adjustments(issue_id, restaurant_id)
+    WHERE issue_id IS NOT NULL;
+CREATE INDEX idx_invoice_adjustments_unsettled ON invoice_adjustments(tenant_id, restaurant_id, created_at)
+    WHERE invoice_id IS NULL;
+CREATE INDEX idx_invoice_adjustments_invoice ON invoice_adjustments(invoice_id)
+    WHERE invoice_id IS NOT NULL;
+
+CREATE TRIGGER trg_invoice_adjustments_updated_at
+    BEFORE UPDATE ON invoice_adjustments
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
@@ -0,0 +1,7 @@
+ALTER TABLE riders
+    DROP COLUMN IF EXISTS tier_updated_at,
+    DROP COLUMN IF EXISTS performance_score,
+    DROP COLUMN IF EXISTS tier;
+
+DROP TABLE IF EXISTS rider_scorecards;
+DROP TABLE IF EXISTS rider_assignment_offers;
@@ -0,0 +1,95 @@
+-- ============================================================
+-- 000033_rider_scorecards.up.sql
+-- Rider assignment offers, daily performance scorecards and tiers
+-- ============================================================
+
+-- ---- Rider Assignment Offers ----
+-- One row per order offered to a rider by dispatch. Offers the rider never
+-- answers expire; offers taken by another rider first are withdrawn and do
+-- not count against the acceptance rate.
+CREATE TABLE rider_assignment_offers (
+    id              UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID          NOT NULL REFERENCES tenants(id),
+    order_id        UUID          NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    rider_id        UUID          NOT NULL REFERENCES riders(id) ON DELETE CASCADE,
+    status          TEXT          NOT NULL DEFAULT 'offered'
+                                  CHECK (status IN ('offered', 'accepted', 'declined', 'expired', 'withdrawn')),
+    offered_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    responded_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (order_id, rider_id)
+);
+
+CREATE INDEX idx_rider_assignment_offers_rider ON rider_assignment_offers(rider_id, offered_at);
+CREATE INDEX idx_rider_assignment_offers_open  ON rider_assignment_offers(offered_at)
+    WHERE status = 'offered';
+
+CREATE TRIGGER trg_rider_assignment_offers_updated_at
+    BEFORE UPDATE ON rider_assignment_offers
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Rider Scorecards ----
+-- Raw counts for one rider and one Asia/Dhaka day. Rates and tiers are
+-- derived from a rolling window of these rows so a quiet day does not swing
+-- a rider's tier.
+CREATE TABLE rider_scorecards (
+    id                    UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id             UUID          NOT NULL REFERENCES tenants(id),
+    rider_id              UUID          NOT NULL REFERENCES riders(id) ON DELETE CASCADE,
+    hub_id                UUID          REFERENCES hubs(id) ON DELETE SET NULL,
+    scorecard_date        DATE          NOT NULL,
+
+    offers_received       INT           NOT NULL DEFAULT 0,
+    offers_accepted       INT           NOT NULL DEFAULT 0,
+    offers_declined       INT           NOT NULL DEFAULT 0,
+    offers_expired        INT           NOT NULL DEFAULT 0,
+    pickups               INT           NOT NULL DEFAULT 0,
+    pickups_on_time       INT           NOT NULL DEFAULT 0,
+    deliveries            INT           NOT NULL DEFAULT 0,
+    deliveries_on_time    INT           NOT NULL DEFAULT 0,
+    cancelled_orders      INT           NOT NULL DEFAULT 0,   -- cancelled while assigned
+    rider_cancellations   INT           NOT NULL DEFAULT 0,   -- of those, cancelled by the rider
+    ratings_count         INT           NOT NULL DEFAULT 0,
+    ratings_sum           INT           NOT NULL DEFAULT 0,
+    penalties_count       INT           NOT NULL DEFAULT 0,
+    penalties_amount      NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    shifts_rostered       INT           NOT NULL DEFAULT 0,
+    shifts_attended       INT           NOT NULL DEFAULT 0,
+    shifts_on_time        INT           NOT NULL DEFAULT 0,
+
+    score                 NUMERIC(5,2),                       -- NULL on a day without activity
+    created_at            TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (rider_id, scorecard_date)
+);
+
+CREATE INDEX idx_rider_scorecards_tenant_date ON rider_scorecards(tenant_id, scorecard_date);
+
+CREATE TRIGGER trg_rider_scorecards_updated_at
+    BEFORE UPDATE ON rider_scorecards
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Rider Tiers ----
+-- Recomputed daily from the rolling scorecard; dispatch favours higher tiers.
+ALTER TABLE riders
+    ADD COLUMN tier              TEXT          NOT NULL DEFAULT 'new'
+                                               CHECK (tier IN ('new', 'bronze', 'silver', 'gold', 'platinum')),
+    ADD COLUMN performance_score NUMERIC(5,2),
+    ADD COLUMN tier_updated_at   TIMESTAMPTZ;
+
+-- ---- Rider Ratings Backfill ----
+-- rating_avg and rating_count were never maintained; seed them from reviews.
+UPDATE riders r SET
+    rating_avg   = agg.avg_rating,
+    rating_count = agg.rating_count
+FROM (
+    SELECT o.rider_id,
+           AVG(rv.rider_rating)::NUMERIC(3,2) AS avg_rating,
+           COUNT(*)::INT                      AS rating_count
+    FROM reviews rv
+    JOIN orders o ON o.id = rv.order_id
+    WHERE rv.rider_rating IS NOT NULL AND o.rider_id IS NOT NULL
+    GROUP BY o.rider_id
+) agg
+WHERE agg.rider_id = r.id;
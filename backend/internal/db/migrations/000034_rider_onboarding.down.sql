@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS rider_documents;
+DROP TABLE IF EXISTS rider_applications;
+
+ALTER TABLE riders
+    DROP COLUMN IF EXISTS verified_by,
+    DROP COLUMN IF EXISTS verified_at,
+    DROP COLUMN IF EXISTS verification_status;
@@ -0,0 +1,88 @@
+-- ============================================================
+-- 000034_rider_onboarding.up.sql
+-- Rider applications, identity and vehicle documents, and the
+-- verification status that gates check-in
+-- ============================================================
+
+-- ---- Rider Verification ----
+-- Only verified riders can check in. A rider drops to expired when a required
+-- document lapses and returns to verified once a renewal is approved.
+ALTER TABLE riders
+    ADD COLUMN verification_status TEXT        NOT NULL DEFAULT 'unverified'
+                                               CHECK (verification_status IN ('unverified', 'verified', 'expired')),
+    ADD COLUMN verified_at         TIMESTAMPTZ,
+    ADD COLUMN verified_by         UUID        REFERENCES users(id);
+
+-- nid_verified was the only verification signal before this workflow.
+UPDATE riders SET verification_status = 'verified', verified_at = NOW()
+WHERE nid_verified = true;
+
+-- ---- Rider Applications ----
+-- Submitted from the rider app and reviewed by the manager of the chosen hub.
+-- rider_id is set when the applicant already has a profile (created by a
+-- partner) or once the application is approved.
+CREATE TABLE rider_applications (
+    id                   UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id            UUID          NOT NULL REFERENCES tenants(id),
+    user_id              UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rider_id             UUID          REFERENCES riders(id) ON DELETE SET NULL,
+    hub_id               UUID          NOT NULL REFERENCES hubs(id),
+    vehicle_type         vehicle_type  NOT NULL,
+    vehicle_registration TEXT,
+    license_number       TEXT,
+    nid_number           TEXT          NOT NULL,
+    status               TEXT          NOT NULL DEFAULT 'submitted'
+                                       CHECK (status IN ('submitted', 'approved', 'rejected')),
+    rejection_reason     TEXT,
+    reviewed_by          UUID          REFERENCES users(id),
+    reviewed_at          TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- One open application per applicant.
+CREATE UNIQUE INDEX uniq_rider_applications_open ON rider_applications(user_id)
+    WHERE status = 'submitted';
+CREATE INDEX idx_rider_applications_queue ON rider_applications(tenant_id, hub_id, status, created_at);
+
+CREATE TRIGGER trg_rider_applications_updated_at
+    BEFORE UPDATE ON rider_applications
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Rider Documents ----
+-- Uploaded with an application, or later by a verified rider as a renewal.
+-- An approved renewal supersedes the rider's previous document of the same
+-- type.
+CREATE TABLE rider_documents (
+    id                   UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id            UUID          NOT NULL REFERENCES tenants(id),
+    user_id              UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rider_id             UUID          REFERENCES riders(id) ON DELETE CASCADE,
+    application_id       UUID          REFERENCES rider_applications(id) ON DELETE CASCADE,
+    doc_type             TEXT          NOT NULL
+                                       CHECK (doc_type IN ('nid_front', 'nid_back', 'driving_licence', 'vehicle_registration')),
+    file_url             TEXT          NOT NULL,
+    document_number      TEXT,
+    expires_on           DATE,
+    status               TEXT          NOT NULL DEFAULT 'pending'
+                                       CHECK (status IN ('pending', 'approved', 'rejected', 'expired', 'superseded')),
+    review_note          TEXT,
+    reviewed_by          UUID          REFERENCES users(id),
+    reviewed_at          TIMESTAMPTZ,
+    reminded_days        INT,                                   -- smallest reminder threshold already sent
+    created_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at           TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_rider_documents_application ON rider_documents(application_id)
+    WHERE application_id IS NOT NULL;
+CREATE INDEX idx_rider_documents_rider       ON rider_documents(rider_id, doc_type)
+    WHERE rider_id IS NOT NULL;
+CREATE INDEX idx_rider_documents_pending     ON rider_documents(tenant_id, created_at)
+    WHERE status = 'pending' AND application_id IS NULL;
+CREATE INDEX idx_rider_documents_expiry      ON rider_documents(expires_on)
+    WHERE status = 'approved' AND expires_on IS NOT NULL;
+
+CREATE TRIGGER trg_rider_documents_updated_at
+    BEFORE UPDATE ON rider_documents
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
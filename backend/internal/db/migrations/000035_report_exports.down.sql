@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_report_exports_updated_at ON report_exports;
+DROP TABLE IF EXISTS report_exports;
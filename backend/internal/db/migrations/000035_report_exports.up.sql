@@ -0,0 +1,48 @@
+-- ============================================================
+-- 000035_report_exports.up.sql
+-- Asynchronous CSV/XLSX report exports generated by the worker
+-- ============================================================
+
+-- ---- Report Exports ----
+-- One row per requested export. The worker claims queued rows, writes the
+-- file to storage and records where it went. restaurant_ids narrows the
+-- export to the requester's restaurants; NULL covers the whole tenant.
+CREATE TABLE report_exports (
+    id              UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID        NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    requested_by    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requester_role  user_role   NOT NULL,
+    report_type     TEXT        NOT NULL
+                                CHECK (report_type IN ('sales', 'orders', 'rider_analytics', 'invoices', 'refunds')),
+    format          TEXT        NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    start_date      DATE        NOT NULL,
+    end_date        DATE        NOT NULL,
+    restaurant_ids  UUID[],
+    email_on_completion BOOLEAN NOT NULL DEFAULT false,
+
+    status          TEXT        NOT NULL DEFAULT 'queued'
+                                CHECK (status IN ('queued', 'running', 'completed', 'failed', 'expired')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    error           TEXT,
+    file_key        TEXT,
+    file_name       TEXT,
+    content_type    TEXT,
+    row_count       INT,
+    size_bytes      BIGINT,
+
+    started_at      TIMESTAMPTZ,
+    completed_at    TIMESTAMPTZ,
+    expires_at      TIMESTAMPTZ,
+    emailed_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_report_exports_tenant  ON report_exports(tenant_id, created_at DESC);
+CREATE INDEX idx_report_exports_queue   ON report_exports(created_at) WHERE status = 'queued';
+CREATE INDEX idx_report_exports_expires ON report_exports(expires_at) WHERE status = 'completed';
+
+CREATE TRIGGER trg_report_exports_updated_at
+    BEFORE UPDATE ON report_exports
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS report_deliveries;
+DROP TRIGGER IF EXISTS trg_report_subscriptions_updated_at ON report_subscriptions;
+DROP TABLE IF EXISTS report_subscriptions;
@@ -0,0 +1,52 @@
+-- ============================================================
+-- 000036_report_subscriptions.up.sql
+-- Scheduled email reports and their delivery log
+-- ============================================================
+
+-- ---- Report Subscriptions ----
+-- A report emailed on a schedule in the tenant's timezone: daily at
+-- send_hour, or weekly on send_weekday (0 = Sunday) at send_hour.
+-- next_run_at is the next scheduled instant; the worker advances it when it
+-- sends. restaurant_ids narrows the report; NULL covers the whole tenant.
+CREATE TABLE report_subscriptions (
+    id              UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID        NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_by      UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    report_type     TEXT        NOT NULL CHECK (report_type IN ('sales_summary', 'pnl')),
+    frequency       TEXT        NOT NULL CHECK (frequency IN ('daily', 'weekly')),
+    send_hour       SMALLINT    NOT NULL DEFAULT 23 CHECK (send_hour BETWEEN 0 AND 23),
+    send_weekday    SMALLINT    NOT NULL DEFAULT 0 CHECK (send_weekday BETWEEN 0 AND 6),
+    recipients      TEXT[]      NOT NULL CHECK (cardinality(recipients) > 0),
+    restaurant_ids  UUID[],
+    is_active       BOOLEAN     NOT NULL DEFAULT true,
+    next_run_at     TIMESTAMPTZ NOT NULL,
+    last_run_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_subscriptions_tenant ON report_subscriptions(tenant_id, created_at DESC);
+CREATE INDEX idx_report_subscriptions_due    ON report_subscriptions(next_run_at) WHERE is_active;
+
+CREATE TRIGGER trg_report_subscriptions_updated_at
+    BEFORE UPDATE ON report_subscriptions
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Report Deliveries ----
+-- One row per recipient per scheduled report. The unique key stops a
+-- period being mailed twice to the same address.
+CREATE TABLE report_deliveries (
+    id              UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID        NOT NULL REFERENCES report_subscriptions(id) ON DELETE CASCADE,
+    tenant_id       UUID        NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    report_type     TEXT        NOT NULL,
+    period_start    TIMESTAMPTZ NOT NULL,
+    period_end      TIMESTAMPTZ NOT NULL,
+    recipient       TEXT        NOT NULL,
+    status          TEXT        NOT NULL CHECK (status IN ('sent', 'failed')),
+    error           TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, period_end, recipient)
+);
+
+CREATE INDEX idx_report_deliveries_tenant ON report_deliveries(tenant_id, created_at DESC);
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS invoice_line_items;
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS refund_amount;
+
+DROP INDEX IF EXISTS uniq_invoice_adjustments_refund;
+
+ALTER TABLE invoice_adjustments
+    DROP COLUMN IF EXISTS refund_id,
+    DROP COLUMN IF EXISTS kind;
+
+DROP TYPE IF EXISTS invoice_adjustment_kind;
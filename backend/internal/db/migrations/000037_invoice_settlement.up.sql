@@ -0,0 +1,51 @@
+-- ============================================================
+-- 000037_invoice_settlement.up.sql
+-- Adjustment kinds (penalties, manual adjustments, refunds charged to a
+-- restaurant), refund totals on invoices and invoice line items
+-- ============================================================
+
+-- ---- Invoice Adjustments: kinds ----
+-- penalty: charged when an issue is resolved against the restaurant.
+-- manual:  entered by finance with a reason; negative amounts are credits.
+-- refund:  a customer refund the restaurant is accountable for. It is only
+--          settled once the refund is approved or processed.
+CREATE TYPE invoice_adjustment_kind AS ENUM ('penalty','manual','refund');
+
+ALTER TABLE invoice_adjustments
+    ADD COLUMN kind      invoice_adjustment_kind NOT NULL DEFAULT 'penalty',
+    ADD COLUMN refund_id UUID                    REFERENCES refunds(id);
+
+CREATE UNIQUE INDEX uniq_invoice_adjustments_refund ON invoice_adjustments(refund_id, restaurant_id)
+    WHERE refund_id IS NOT NULL;
+
+-- ---- Invoices: refunds ----
+ALTER TABLE invoices
+    ADD COLUMN refund_amount NUMERIC(14,2) NOT NULL DEFAULT 0.00;
+
+-- ---- Invoice Line Items ----
+-- One line per delivered order the restaurant fulfilled and one per settled
+-- adjustment. amount is the line's effect on net_payable, so the lines of an
+-- invoice sum to it.
+CREATE TABLE invoice_line_items (
+    id                      UUID          PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id               UUID          NOT NULL REFERENCES tenants(id),
+    invoice_id              UUID          NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    line_type               TEXT          NOT NULL CHECK (line_type IN ('order','penalty','manual','refund')),
+    order_id                UUID          REFERENCES orders(id),
+    adjustment_id           UUID          REFERENCES invoice_adjustments(id),
+    description             TEXT          NOT NULL,
+    gross_sales             NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    item_discount           NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    vendor_promo_discount   NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    net_sales               NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    vat                     NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    commission_rate         NUMERIC(5,2)  NOT NULL DEFAULT 0.00,
+    commission_amount       NUMERIC(12,2) NOT NULL DEFAULT 0.00,
+    amount                  NUMERIC(12,2) NOT NULL,
+    occurred_at             TIMESTAMPTZ   NOT NULL,
+    created_at              TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invoice_line_items_invoice ON invoice_line_items(invoice_id, line_type, occurred_at);
+CREATE INDEX idx_invoice_line_items_order ON invoice_line_items(order_id)
+    WHERE order_id IS NOT NULL;
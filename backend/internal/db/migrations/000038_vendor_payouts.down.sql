@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS vendor_payouts;
+DROP TABLE IF EXISTS vendor_payout_batches;
+DROP TABLE IF EXISTS restaurant_payout_accounts;
+DROP TABLE IF EXISTS settlement_schedules;
@@ -0,0 +1,101 @@
+-- ============================================================
+-- 000038_vendor_payouts.up.sql
+-- Settlement schedules, restaurant payout accounts and vendor payout
+-- batches built from finalized invoices
+-- ============================================================
+
+-- ---- Settlement Schedules ----
+-- How often a tenant's restaurants are invoiced. Periods are cycle_days long
+-- (7 for weekly, 14 for fortnightly) and start on anchor_date + n periods,
+-- Asia/Dhaka dates. Draft invoices are finalized automatically review_days
+-- after they are generated when auto_finalize is set. last_period_end is the
+-- end of the last period the worker invoiced.
+CREATE TABLE settlement_schedules (
+    tenant_id        UUID        PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    cycle            TEXT        NOT NULL DEFAULT 'weekly' CHECK (cycle IN ('weekly', 'fortnightly')),
+    anchor_date      DATE        NOT NULL DEFAULT DATE '2024-01-01',
+    review_days      SMALLINT    NOT NULL DEFAULT 3 CHECK (review_days BETWEEN 0 AND 30),
+    auto_finalize    BOOLEAN     NOT NULL DEFAULT true,
+    last_period_end  DATE,
+    updated_by       UUID        REFERENCES users(id),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER trg_settlement_schedules_updated_at
+    BEFORE UPDATE ON settlement_schedules
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Restaurant Payout Accounts ----
+-- Where a restaurant's payouts are sent: a bank account (BEFTN) or a bKash
+-- wallet, in which case account_number is the wallet number.
+CREATE TABLE restaurant_payout_accounts (
+    restaurant_id    UUID        PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    tenant_id        UUID        NOT NULL REFERENCES tenants(id),
+    method           TEXT        NOT NULL CHECK (method IN ('bank', 'bkash')),
+    account_name     TEXT        NOT NULL,
+    account_number   TEXT        NOT NULL,
+    bank_name        TEXT,
+    branch_name      TEXT,
+    routing_number   TEXT,
+    updated_by       UUID        REFERENCES users(id),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (method = 'bkash' OR (bank_name IS NOT NULL AND routing_number IS NOT NULL))
+);
+
+CREATE INDEX idx_restaurant_payout_accounts_tenant ON restaurant_payout_accounts(tenant_id);
+
+CREATE TRIGGER trg_restaurant_payout_accounts_updated_at
+    BEFORE UPDATE ON restaurant_payout_accounts
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Vendor Payout Batches ----
+-- One batch per payout run (the settlement schedule or on demand by finance).
+CREATE TABLE vendor_payout_batches (
+    id              UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID            NOT NULL REFERENCES tenants(id),
+    kind            TEXT            NOT NULL CHECK (kind IN ('scheduled', 'on_demand')),
+    payout_count    INT             NOT NULL DEFAULT 0,
+    total_amount    NUMERIC(14,2)   NOT NULL DEFAULT 0.00,
+    created_by      UUID            REFERENCES users(id),
+    created_at      TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_vendor_payout_batches_tenant ON vendor_payout_batches(tenant_id, created_at DESC);
+
+-- ---- Vendor Payouts ----
+-- One transfer per finalized invoice. The account is copied from the
+-- restaurant's payout account when the batch is built so the disbursement
+-- file matches what was approved. A failed payout releases its invoice to
+-- the next batch.
+CREATE TABLE vendor_payouts (
+    id                UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id         UUID            NOT NULL REFERENCES tenants(id),
+    batch_id          UUID            NOT NULL REFERENCES vendor_payout_batches(id),
+    restaurant_id     UUID            NOT NULL REFERENCES restaurants(id),
+    invoice_id        UUID            NOT NULL REFERENCES invoices(id),
+    amount            NUMERIC(14,2)   NOT NULL CHECK (amount > 0),
+    method            TEXT            NOT NULL CHECK (method IN ('bank', 'bkash')),
+    account_name      TEXT            NOT NULL,
+    account_number    TEXT            NOT NULL,
+    bank_name         TEXT,
+    branch_name       TEXT,
+    routing_number    TEXT,
+    status            payout_status   NOT NULL DEFAULT 'pending',
+    payment_reference TEXT,
+    processed_by      UUID            REFERENCES users(id),
+    processed_at      TIMESTAMPTZ,
+    note              TEXT,
+    created_at        TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ     NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_vendor_payouts_batch ON vendor_payouts(batch_id);
+CREATE INDEX idx_vendor_payouts_restaurant ON vendor_payouts(restaurant_id, created_at DESC);
+CREATE UNIQUE INDEX uniq_vendor_payouts_open_invoice ON vendor_payouts(invoice_id)
+    WHERE status <> 'failed';
+
+CREATE TRIGGER trg_vendor_payouts_updated_at
+    BEFORE UPDATE ON vendor_payouts
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tax_invoices;
+DROP TABLE IF EXISTS tax_invoice_serials;
+DROP TABLE IF EXISTS restaurant_vat_registrations;
+DROP TABLE IF EXISTS tax_settings;
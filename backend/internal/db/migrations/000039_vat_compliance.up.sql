@@ -0,0 +1,84 @@
+-- ============================================================
+-- 000039_vat_compliance.up.sql
+-- VAT registrations, Mushak-6.3 tax invoices issued per order and the
+-- per-seller serials they are numbered from
+-- ============================================================
+
+-- ---- Tax Settings ----
+-- A tenant's VAT registration as the seller of delivery and service fees.
+-- Fees are VAT-inclusive prices: the VAT at these rates is decomposed out of
+-- what the customer paid.
+CREATE TABLE tax_settings (
+    tenant_id              UUID          PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    bin                    TEXT,
+    registered_name        TEXT,
+    registered_address     TEXT,
+    delivery_fee_vat_rate  NUMERIC(5,2)  NOT NULL DEFAULT 15.00 CHECK (delivery_fee_vat_rate BETWEEN 0 AND 100),
+    service_fee_vat_rate   NUMERIC(5,2)  NOT NULL DEFAULT 15.00 CHECK (service_fee_vat_rate BETWEEN 0 AND 100),
+    updated_by             UUID          REFERENCES users(id),
+    created_at             TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER trg_tax_settings_updated_at
+    BEFORE UPDATE ON tax_settings
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Restaurant VAT Registrations ----
+-- The Business Identification Number (BIN) a restaurant issues tax
+-- invoices under.
+CREATE TABLE restaurant_vat_registrations (
+    restaurant_id       UUID        PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    tenant_id           UUID        NOT NULL REFERENCES tenants(id),
+    bin                 TEXT        NOT NULL,
+    registered_name     TEXT        NOT NULL,
+    registered_address  TEXT,
+    updated_by          UUID        REFERENCES users(id),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_restaurant_vat_registrations_tenant ON restaurant_vat_registrations(tenant_id);
+
+CREATE TRIGGER trg_restaurant_vat_registrations_updated_at
+    BEFORE UPDATE ON restaurant_vat_registrations
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Tax Invoice Serials ----
+-- The last serial number issued by each seller: a restaurant, or the tenant
+-- itself (seller_id = tenant_id) for delivery and service fees.
+CREATE TABLE tax_invoice_serials (
+    tenant_id    UUID    NOT NULL REFERENCES tenants(id),
+    seller_id    UUID    NOT NULL,
+    last_serial  INT     NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, seller_id)
+);
+
+-- ---- Tax Invoices ----
+-- Mushak-6.3 tax invoices, one per seller on a delivered order: each
+-- restaurant for its items, and the tenant (restaurant_id NULL) for the
+-- delivery charge and service fee. Seller and buyer details and the lines
+-- are copied at issue so the invoice never changes.
+CREATE TABLE tax_invoices (
+    id                  UUID            PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id           UUID            NOT NULL REFERENCES tenants(id),
+    order_id            UUID            NOT NULL REFERENCES orders(id),
+    restaurant_id       UUID            REFERENCES restaurants(id),
+    invoice_number      TEXT            NOT NULL,
+    seller_name         TEXT            NOT NULL,
+    seller_bin          TEXT,
+    seller_address      TEXT,
+    buyer_name          TEXT            NOT NULL,
+    buyer_phone         TEXT,
+    buyer_address       TEXT,
+    taxable_value       NUMERIC(14,2)   NOT NULL,
+    vat_amount          NUMERIC(14,2)   NOT NULL,
+    total_amount        NUMERIC(14,2)   NOT NULL,
+    lines               JSONB           NOT NULL DEFAULT '[]',
+    supplied_at         TIMESTAMPTZ     NOT NULL,
+    created_at          TIMESTAMPTZ     NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, invoice_number)
+);
+
+CREATE UNIQUE INDEX uq_tax_invoices_order_seller ON tax_invoices(order_id, COALESCE(restaurant_id, tenant_id));
+CREATE INDEX idx_tax_invoices_seller_period ON tax_invoices(tenant_id, restaurant_id, supplied_at);
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS invoice_notes;
+DROP TABLE IF EXISTS invoice_note_serials;
+DROP TYPE IF EXISTS invoice_note_type;
+
+DROP TABLE IF EXISTS invoice_dispute_comments;
+DROP TABLE IF EXISTS invoice_disputes;
+DROP TYPE IF EXISTS invoice_dispute_status;
+
+-- Enum values added to invoice_adjustment_kind cannot be dropped and are
+-- left in place; note adjustments become manual ones.
+UPDATE invoice_line_items SET line_type = 'manual' WHERE line_type IN ('credit_note','debit_note');
+UPDATE invoice_adjustments SET kind = 'manual' WHERE kind IN ('credit_note','debit_note');
+
+ALTER TABLE invoice_line_items
+    DROP CONSTRAINT IF EXISTS invoice_line_items_line_type_check,
+    ADD CONSTRAINT invoice_line_items_line_type_check
+        CHECK (line_type IN ('order','penalty','manual','refund'));
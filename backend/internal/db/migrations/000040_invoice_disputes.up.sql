@@ -0,0 +1,94 @@
+-- ============================================================
+-- 000040_invoice_disputes.up.sql
+-- Restaurant disputes against finalized invoices, their comment threads,
+-- and the credit and debit notes finance issues to correct an invoice
+-- ============================================================
+
+-- ---- Invoice Adjustments: note kinds ----
+-- credit_note: an amount owed to the restaurant (stored negative).
+-- debit_note:  an amount owed by the restaurant (stored positive).
+ALTER TYPE invoice_adjustment_kind ADD VALUE IF NOT EXISTS 'credit_note';
+ALTER TYPE invoice_adjustment_kind ADD VALUE IF NOT EXISTS 'debit_note';
+
+ALTER TABLE invoice_line_items
+    DROP CONSTRAINT IF EXISTS invoice_line_items_line_type_check,
+    ADD CONSTRAINT invoice_line_items_line_type_check
+        CHECK (line_type IN ('order','penalty','manual','refund','credit_note','debit_note'));
+
+-- ---- Invoice Disputes ----
+-- Raised by a restaurant against a finalized or paid invoice, optionally on
+-- one line item. open -> under_review -> resolved | rejected.
+CREATE TYPE invoice_dispute_status AS ENUM ('open','under_review','resolved','rejected');
+
+CREATE TABLE invoice_disputes (
+    id                  UUID                   PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id           UUID                   NOT NULL REFERENCES tenants(id),
+    invoice_id          UUID                   NOT NULL REFERENCES invoices(id),
+    restaurant_id       UUID                   NOT NULL REFERENCES restaurants(id),
+    line_item_id        UUID                   REFERENCES invoice_line_items(id),
+    reason              TEXT                   NOT NULL,
+    disputed_amount     NUMERIC(12,2)          CHECK (disputed_amount > 0),
+    status              invoice_dispute_status NOT NULL DEFAULT 'open',
+    raised_by           UUID                   NOT NULL REFERENCES users(id),
+    resolution_note     TEXT,
+    resolved_by         UUID                   REFERENCES users(id),
+    resolved_at         TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ            NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ            NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invoice_disputes_invoice ON invoice_disputes(invoice_id, created_at);
+CREATE INDEX idx_invoice_disputes_tenant_status ON invoice_disputes(tenant_id, status, created_at DESC);
+-- One dispute in progress per line item.
+CREATE UNIQUE INDEX uniq_invoice_disputes_open_line ON invoice_disputes(line_item_id)
+    WHERE line_item_id IS NOT NULL AND status IN ('open','under_review');
+
+CREATE TRIGGER trg_invoice_disputes_updated_at
+    BEFORE UPDATE ON invoice_disputes
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+-- ---- Invoice Dispute Comments ----
+-- attachments are URLs of files uploaded through /media/upload.
+CREATE TABLE invoice_dispute_comments (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    dispute_id  UUID        NOT NULL REFERENCES invoice_disputes(id) ON DELETE CASCADE,
+    tenant_id   UUID        NOT NULL REFERENCES tenants(id),
+    author_id   UUID        NOT NULL REFERENCES users(id),
+    message     TEXT        NOT NULL,
+    attachments TEXT[]      NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invoice_dispute_comments_dispute ON invoice_dispute_comments(dispute_id, created_at);
+
+-- ---- Credit and Debit Notes ----
+-- A correction to an invoice. Each note creates an invoice adjustment, so it
+-- is settled on the restaurant's next invoice and paid out with it.
+CREATE TYPE invoice_note_type AS ENUM ('credit','debit');
+
+CREATE TABLE invoice_note_serials (
+    tenant_id    UUID              NOT NULL REFERENCES tenants(id),
+    note_type    invoice_note_type NOT NULL,
+    last_serial  INTEGER           NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, note_type)
+);
+
+CREATE TABLE invoice_notes (
+    id              UUID              PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       UUID              NOT NULL REFERENCES tenants(id),
+    invoice_id      UUID              NOT NULL REFERENCES invoices(id),
+    restaurant_id   UUID              NOT NULL REFERENCES restaurants(id),
+    dispute_id      UUID              REFERENCES invoice_disputes(id),
+    note_type       invoice_note_type NOT NULL,
+    note_number     TEXT              NOT NULL,
+    amount          NUMERIC(12,2)     NOT NULL CHECK (amount > 0),
+    reason          TEXT              NOT NULL,
+    adjustment_id   UUID              NOT NULL REFERENCES invoice_adjustments(id),
+    issued_by       UUID              NOT NULL REFERENCES users(id),
+    created_at      TIMESTAMPTZ       NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, note_number)
+);
+
+CREATE INDEX idx_invoice_notes_invoice ON invoice_notes(invoice_id, created_at);
+CREATE INDEX idx_invoice_notes_dispute ON invoice_notes(dispute_id)
+    WHERE dispute_id IS NOT NULL;
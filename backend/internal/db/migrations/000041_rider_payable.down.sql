@@ -0,0 +1,4 @@
+-- Enum values added to ledger_entry_type cannot be dropped and are left in place.
+DELETE FROM ledger_accounts a
+WHERE a.code = 'RIDER_PAYABLE'
+  AND NOT EXISTS (SELECT 1 FROM ledger_entries e WHERE e.account_id = a.id);
@@ -0,0 +1,11 @@
+-- ============================================================
+-- 000041_rider_payable.up.sql
+-- Ledger account for rider earnings, so COD cash netted against a rider
+-- payout leaves RIDER_CASH in step with riders.cash_in_hand
+-- ============================================================
+
+ALTER TYPE ledger_entry_type ADD VALUE IF NOT EXISTS 'rider_payout';
+
+INSERT INTO ledger_accounts (code, name, account_type, description, is_system) VALUES
+    ('RIDER_PAYABLE', 'Rider Payable', 'liability', 'Earnings owed to riders', true)
+ON CONFLICT (code) DO NOTHING;
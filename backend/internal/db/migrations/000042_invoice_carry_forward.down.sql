@@ -0,0 +1,9 @@
+-- Enum values added to invoice_adjustment_kind cannot be dropped and are
+-- left in place; carried balances become manual adjustments.
+UPDATE invoice_line_items SET line_type = 'manual' WHERE line_type = 'carry_forward';
+UPDATE invoice_adjustments SET kind = 'manual' WHERE kind = 'carry_forward';
+
+ALTER TABLE invoice_line_items
+    DROP CONSTRAINT IF EXISTS invoice_line_items_line_type_check,
+    ADD CONSTRAINT invoice_line_items_line_type_check
+        CHECK (line_type IN ('order','penalty','manual','refund','credit_note','debit_note'));
@@ -0,0 +1,13 @@
+-- ============================================================
+-- 000042_invoice_carry_forward.up.sql
+-- Negative invoice balances carried into the restaurant's next invoice
+-- ============================================================
+
+-- carry_forward: what a restaurant still owed when an invoice closed with a
+-- negative net payable (stored positive, deducted from the next invoice).
+ALTER TYPE invoice_adjustment_kind ADD VALUE IF NOT EXISTS 'carry_forward';
+
+ALTER TABLE invoice_line_items
+    DROP CONSTRAINT IF EXISTS invoice_line_items_line_type_check,
+    ADD CONSTRAINT invoice_line_items_line_type_check
+        CHECK (line_type IN ('order','penalty','manual','refund','credit_note','debit_note','carry_forward'));
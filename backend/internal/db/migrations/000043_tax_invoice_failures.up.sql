@@ -0,0 +1,17 @@
+-- ============================================================
+-- 000043_tax_invoice_failures.up.sql
+-- Orders the background job could not issue tax invoices for, so they are
+-- not retried on every run
+-- ============================================================
+
+-- ---- Tax Invoice Failures ----
+-- The last error issuing an order's tax invoices. The job skips these
+-- orders; issuing on demand still works and clears the row.
+CREATE TABLE tax_invoice_failures (
+    order_id    UUID        PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    tenant_id   UUID        NOT NULL REFERENCES tenants(id),
+    error       TEXT        NOT NULL,
+    failed_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_tax_invoice_failures_tenant ON tax_invoice_failures(tenant_id, failed_at);
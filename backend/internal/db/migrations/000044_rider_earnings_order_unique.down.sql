@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_rider_earnings_order;
+CREATE INDEX idx_rider_earnings_order_id ON rider_earnings(order_id);
@@ -0,0 +1,27 @@
+-- ============================================================
+-- 000044_rider_earnings_order_unique.up.sql
+-- One rider earning per order, so a repeated delivery cannot pay twice
+-- ============================================================
+
+-- Drop unpaid duplicates, keeping the first earning of each order, and take
+-- them back out of the rider's totals.
+WITH dupes AS (
+    DELETE FROM rider_earnings e
+    USING rider_earnings first
+    WHERE first.order_id = e.order_id
+      AND (first.created_at, first.id) < (e.created_at, e.id)
+      AND NOT e.is_paid_out
+    RETURNING e.rider_id, e.total_earning
+)
+UPDATE riders r SET
+    total_order_count = r.total_order_count - d.orders,
+    total_earnings = r.total_earnings - d.amount,
+    pending_balance = r.pending_balance - d.amount
+FROM (
+    SELECT rider_id, COUNT(*)::INT AS orders, SUM(total_earning) AS amount
+    FROM dupes GROUP BY rider_id
+) d
+WHERE r.id = d.rider_id;
+
+DROP INDEX IF EXISTS idx_rider_earnings_order_id;
+CREATE UNIQUE INDEX uq_rider_earnings_order ON rider_earnings(order_id);
@@ -0,0 +1,14 @@
+-- ============================================================
+-- 000045_promo_optional_code.down.sql
+-- Codeless promos get a generated code so the column can be NOT NULL again
+-- ============================================================
+
+UPDATE promos SET code = 'AUTO-' || upper(replace(id::text, '-', ''))
+WHERE code IS NULL;
+
+DROP INDEX IF EXISTS uq_promos_tenant_code;
+
+ALTER TABLE promos ADD CONSTRAINT promos_tenant_id_code_key UNIQUE (tenant_id, code);
+CREATE INDEX idx_promos_code ON promos(tenant_id, code);
+
+ALTER TABLE promos ALTER COLUMN code SET NOT NULL;
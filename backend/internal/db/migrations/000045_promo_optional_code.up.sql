@@ -0,0 +1,12 @@
+-- ============================================================
+-- 000045_promo_optional_code.up.sql
+-- Auto-applied promos need no code; codes stay unique per tenant
+-- ============================================================
+
+ALTER TABLE promos ALTER COLUMN code DROP NOT NULL;
+
+ALTER TABLE promos DROP CONSTRAINT promos_tenant_id_code_key;
+DROP INDEX IF EXISTS idx_promos_code;
+
+CREATE UNIQUE INDEX uq_promos_tenant_code ON promos(tenant_id, code)
+    WHERE code IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_terminal_updated;
+DROP TABLE IF EXISTS job_watermarks;
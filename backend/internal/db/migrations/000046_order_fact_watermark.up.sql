@@ -0,0 +1,20 @@
+-- ============================================================
+-- 000046_order_fact_watermark.up.sql
+-- Analytics facts are written from order.completed outbox events; a
+-- watermark-based catch-up picks up terminal orders that never got one.
+-- ============================================================
+
+-- Progress of keyset catch-up jobs: the (updated_at, id) of the last row seen.
+CREATE TABLE job_watermarks (
+    job             TEXT          PRIMARY KEY,
+    last_updated_at TIMESTAMPTZ   NOT NULL,
+    last_id         UUID          NOT NULL,
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER trg_job_watermarks_updated_at
+    BEFORE UPDATE ON job_watermarks
+    FOR EACH ROW EXECUTE FUNCTION fn_set_updated_at();
+
+CREATE INDEX idx_orders_terminal_updated ON orders(updated_at, id)
+    WHERE status IN ('delivered', 'cancelled', 'rejected') AND deleted_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE tax_invoice_failures
+    DROP COLUMN IF EXISTS permanent,
+    DROP COLUMN IF EXISTS next_retry_at,
+    DROP COLUMN IF EXISTS attempts;
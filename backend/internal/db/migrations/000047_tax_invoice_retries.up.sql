@@ -0,0 +1,16 @@
+-- ============================================================
+-- 000047_tax_invoice_retries.up.sql
+-- Failed tax invoice issues are retried with backoff and only given up on
+-- after several attempts or a validation error
+-- ============================================================
+
+-- attempts:      failed issue attempts so far
+-- next_retry_at: when the job may try again; NULL once permanent
+-- permanent:     the job no longer retries; issuing on demand still works
+ALTER TABLE tax_invoice_failures
+    ADD COLUMN attempts      INT         NOT NULL DEFAULT 1,
+    ADD COLUMN next_retry_at TIMESTAMPTZ,
+    ADD COLUMN permanent     BOOLEAN     NOT NULL DEFAULT false;
+
+-- Failures so far were recorded on the first error; give them their retries.
+UPDATE tax_invoice_failures SET next_retry_at = NOW();
@@ -0,0 +1,11 @@
+-- ============================================================
+-- 000048_purchase_order_serials.up.sql
+-- Sequential purchase order numbers per tenant
+-- ============================================================
+
+-- ---- Purchase Order Serials ----
+-- The last purchase order serial issued by each tenant.
+CREATE TABLE purchase_order_serials (
+    tenant_id    UUID    PRIMARY KEY REFERENCES tenants(id),
+    last_serial  INT     NOT NULL DEFAULT 0
+);
@@ -0,0 +1,40 @@
+-- name: ListOrderRestaurantIDs :many
+-- Restaurants an order is picked up from; empty when the order is not in the tenant.
+SELECT p.restaurant_id FROM order_pickups p
+WHERE p.order_id = sqlc.arg(order_id) AND p.tenant_id = sqlc.arg(tenant_id)
+ORDER BY p.restaurant_id;
+
+-- name: ListOrderIssueRestaurantIDs :many
+SELECT p.restaurant_id FROM order_issues i
+JOIN order_pickups p ON p.order_id = i.order_id
+WHERE i.id = sqlc.arg(issue_id) AND i.tenant_id = sqlc.arg(tenant_id)
+ORDER BY p.restaurant_id;
+
+-- name: GetProductRestaurantID :one
+SELECT restaurant_id FROM products
+WHERE id = sqlc.arg(product_id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: GetCategoryRestaurantID :one
+-- NULL for tenant-wide categories.
+SELECT restaurant_id FROM categories
+WHERE id = sqlc.arg(category_id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: GetReviewRestaurantID :one
+SELECT restaurant_id FROM reviews
+WHERE id = sqlc.arg(review_id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: GetPurchaseOrderRestaurantID :one
+SELECT restaurant_id FROM purchase_orders
+WHERE id = sqlc.arg(purchase_order_id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: GetInvoiceRestaurantID :one
+SELECT restaurant_id FROM invoices
+WHERE id = sqlc.arg(invoice_id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: GetInvoiceDisputeRestaurantID :one
+SELECT restaurant_id FROM invoice_disputes
+WHERE id = sqlc.arg(dispute_id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: GetInvoiceNoteRestaurantID :one
+SELECT restaurant_id FROM invoice_notes
+WHERE id = sqlc.arg(note_id) AND tenant_id = sqlc.arg(tenant_id);
@@ -1,4 +1,5 @@
--- name: CreateOrderAnalytics :one
+-- name: UpsertOrderAnalytics :one
+-- Facts are rebuilt from the order, so a re-run overwrites the whole row.
 INSERT INTO order_analytics (
     tenant_id, order_id, restaurant_ids, customer_id, rider_id, hub_id,
     delivery_area, payment_method, platform, promo_code,
@@ -10,12 +11,83 @@ INSERT INTO order_analytics (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
     $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
-) RETURNING *;
+)
+ON CONFLICT (order_id) DO UPDATE SET
+    restaurant_ids          = EXCLUDED.restaurant_ids,
+    customer_id             = EXCLUDED.customer_id,
+    rider_id                = EXCLUDED.rider_id,
+    hub_id                  = EXCLUDED.hub_id,
+    delivery_area           = EXCLUDED.delivery_area,
+    payment_method          = EXCLUDED.payment_method,
+    platform                = EXCLUDED.platform,
+    promo_code              = EXCLUDED.promo_code,
+    subtotal                = EXCLUDED.subtotal,
+    item_discount           = EXCLUDED.item_discount,
+    promo_discount          = EXCLUDED.promo_discount,
+    delivery_charge         = EXCLUDED.delivery_charge,
+    vat_total               = EXCLUDED.vat_total,
+    total_amount            = EXCLUDED.total_amount,
+    commission_total        = EXCLUDED.commission_total,
+    confirmation_duration_s = EXCLUDED.confirmation_duration_s,
+    preparation_duration_s  = EXCLUDED.preparation_duration_s,
+    pickup_to_delivery_s    = EXCLUDED.pickup_to_delivery_s,
+    total_fulfillment_s     = EXCLUDED.total_fulfillment_s,
+    final_status            = EXCLUDED.final_status,
+    cancellation_reason     = EXCLUDED.cancellation_reason,
+    order_date              = EXCLUDED.order_date,
+    order_hour              = EXCLUDED.order_hour,
+    order_day_of_week       = EXCLUDED.order_day_of_week,
+    order_week              = EXCLUDED.order_week,
+    order_month             = EXCLUDED.order_month,
+    order_year              = EXCLUDED.order_year,
+    completed_at            = EXCLUDED.completed_at
+RETURNING *;
+
+-- name: ListTerminalOrdersAfterWatermark :many
+-- Keyset-paginated on (updated_at, id) past the catch-up watermark. Orders
+-- that already have a fact or an order.completed event are skipped.
+SELECT * FROM orders
+WHERE status IN ('delivered', 'cancelled', 'rejected')
+  AND deleted_at IS NULL
+  AND (updated_at, id) > (sqlc.arg(after_updated_at)::timestamptz, sqlc.arg(after_id)::uuid)
+  AND updated_at < sqlc.arg(before)::timestamptz
+  AND NOT EXISTS (SELECT 1 FROM order_analytics oa WHERE oa.order_id = orders.id)
+  AND NOT EXISTS (
+      SELECT 1 FROM outbox_events e
+      WHERE e.aggregate_type = 'order' AND e.aggregate_id = orders.id
+        AND e.event_type = 'order.completed'
+  )
+ORDER BY updated_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetJobWatermark :one
+SELECT * FROM job_watermarks WHERE job = $1;
+
+-- name: UpsertJobWatermark :exec
+INSERT INTO job_watermarks (job, last_updated_at, last_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (job) DO UPDATE SET
+    last_updated_at = EXCLUDED.last_updated_at,
+    last_id         = EXCLUDED.last_id;
+
+-- name: ListTerminalOrdersForBackfill :many
+-- Keyset-paginated on (created_at, id) so a long backfill can resume.
+SELECT * FROM orders
+WHERE status IN ('delivered', 'cancelled', 'rejected')
+  AND deleted_at IS NULL
+  AND (sqlc.narg(tenant_id)::uuid IS NULL OR tenant_id = sqlc.narg(tenant_id)::uuid)
+  AND created_at >= sqlc.arg(from_time)::timestamptz
+  AND created_at < sqlc.arg(to_time)::timestamptz
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
 
 -- name: GetOrderAnalyticsByOrderID :one
 SELECT * FROM order_analytics WHERE order_id = $1 AND tenant_id = $2 LIMIT 1;
 
 -- name: GetDashboardToday :one
+-- restaurant_ids is NULL for tenant-wide users; otherwise only orders that
+-- touch one of the given restaurants are counted.
 SELECT
     COUNT(*)::INT AS total_orders,
     COUNT(CASE WHEN final_status = 'delivered' THEN 1 END)::INT AS delivered_orders,
@@ -24,7 +96,8 @@ SELECT
     COALESCE(SUM(CASE WHEN final_status = 'delivered' THEN total_amount ELSE 0 END), 0)::NUMERIC(14,2) AS today_revenue,
     COALESCE(AVG(CASE WHEN final_status = 'delivered' THEN total_fulfillment_s END), 0)::INT AS avg_delivery_time_s
 FROM order_analytics
-WHERE tenant_id = $1 AND order_date = CURRENT_DATE;
+WHERE tenant_id = sqlc.arg(tenant_id) AND order_date = CURRENT_DATE
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_ids && sqlc.narg(restaurant_ids)::uuid[]);
 
 -- name: GetDashboardTrend :many
 SELECT
@@ -32,7 +105,8 @@ SELECT
     COUNT(*)::INT AS order_count,
     COALESCE(SUM(CASE WHEN final_status = 'delivered' THEN total_amount ELSE 0 END), 0)::NUMERIC(14,2) AS revenue
 FROM order_analytics
-WHERE tenant_id = $1 AND order_date >= sqlc.arg(start_date)::date AND order_date <= sqlc.arg(end_date)::date
+WHERE tenant_id = sqlc.arg(tenant_id) AND order_date >= sqlc.arg(start_date)::date AND order_date <= sqlc.arg(end_date)::date
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_ids && sqlc.narg(restaurant_ids)::uuid[])
 GROUP BY order_date
 ORDER BY order_date ASC;
 
@@ -44,13 +118,14 @@ SELECT
     SUM(oi.item_total)::NUMERIC(14,2) AS total_revenue
 FROM order_items oi
 JOIN orders o ON oi.order_id = o.id
-WHERE o.tenant_id = $1
+WHERE o.tenant_id = sqlc.arg(tenant_id)
   AND o.status = 'delivered'
   AND o.delivered_at >= sqlc.arg(start_date)::timestamptz
   AND o.delivered_at < sqlc.arg(end_date)::timestamptz
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR oi.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[]))
 GROUP BY oi.product_id, oi.product_name
 ORDER BY total_quantity DESC
-LIMIT $2;
+LIMIT sqlc.arg('limit');
 
 -- name: GetSalesReport :many
 SELECT
@@ -66,9 +141,10 @@ SELECT
     COALESCE(AVG(total_amount), 0)::NUMERIC(14,2) AS avg_order_value,
     COALESCE(AVG(total_fulfillment_s), 0)::INT AS avg_delivery_time_s
 FROM order_analytics
-WHERE tenant_id = $1
+WHERE tenant_id = sqlc.arg(tenant_id)
   AND order_date >= sqlc.arg(start_date)::date
   AND order_date <= sqlc.arg(end_date)::date
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_ids && sqlc.narg(restaurant_ids)::uuid[])
   AND final_status = 'delivered'
 GROUP BY order_date
 ORDER BY order_date ASC;
@@ -78,9 +154,10 @@ SELECT
     order_hour,
     COUNT(*)::INT AS order_count
 FROM order_analytics
-WHERE tenant_id = $1
+WHERE tenant_id = sqlc.arg(tenant_id)
   AND order_date >= sqlc.arg(start_date)::date
   AND order_date <= sqlc.arg(end_date)::date
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_ids && sqlc.narg(restaurant_ids)::uuid[])
 GROUP BY order_hour
 ORDER BY order_hour ASC;
 
@@ -89,9 +166,10 @@ SELECT
     final_status,
     COUNT(*)::INT AS order_count
 FROM order_analytics
-WHERE tenant_id = $1
+WHERE tenant_id = sqlc.arg(tenant_id)
   AND order_date >= sqlc.arg(start_date)::date
   AND order_date <= sqlc.arg(end_date)::date
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_ids && sqlc.narg(restaurant_ids)::uuid[])
 GROUP BY final_status;
 
 -- name: GetRiderAnalytics :many
@@ -153,5 +231,8 @@ WHERE tenant_id = $1
   AND order_date <= sqlc.arg(end_date)::date;
 
 -- name: GetPendingOrderCount :one
-SELECT COUNT(*)::INT FROM orders
-WHERE tenant_id = $1 AND status IN ('pending', 'created') AND deleted_at IS NULL;
+SELECT COUNT(*)::INT FROM orders o
+WHERE o.tenant_id = sqlc.arg(tenant_id) AND o.status IN ('pending', 'created') AND o.deleted_at IS NULL
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups p
+        WHERE p.order_id = o.id AND p.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[])));
@@ -0,0 +1,95 @@
+-- ============================================================
+-- COD cash collections and rider cash deposits
+-- ============================================================
+
+-- name: CreateCodCollection :one
+INSERT INTO cod_collections (tenant_id, order_id, rider_id, hub_id, expected_amount, collected_amount, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetCodCollectionByOrder :one
+SELECT * FROM cod_collections WHERE order_id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: ListCodCollectionsByRider :many
+SELECT * FROM cod_collections
+WHERE rider_id = $1 AND tenant_id = $2
+ORDER BY collected_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CreateRiderCashDeposit :one
+INSERT INTO rider_cash_deposits (tenant_id, rider_id, hub_id, amount, reference, note)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetRiderCashDeposit :one
+SELECT * FROM rider_cash_deposits WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- Locks a pending deposit while it is confirmed or rejected.
+-- name: GetPendingRiderCashDepositForUpdate :one
+SELECT * FROM rider_cash_deposits
+WHERE id = $1 AND tenant_id = $2 AND status = 'pending'
+LIMIT 1 FOR UPDATE;
+
+-- name: ListRiderCashDepositsByRider :many
+SELECT * FROM rider_cash_deposits
+WHERE rider_id = $1 AND tenant_id = $2
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: ListRiderCashDeposits :many
+SELECT d.*, u.name AS rider_name, u.phone AS rider_phone
+FROM rider_cash_deposits d
+JOIN riders r ON r.id = d.rider_id
+JOIN users u ON u.id = r.user_id
+WHERE d.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR d.hub_id = sqlc.narg(hub_id))
+  AND (sqlc.narg(status)::text IS NULL OR d.status = sqlc.narg(status))
+ORDER BY d.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: SumPendingRiderCashDeposits :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS amount
+FROM rider_cash_deposits
+WHERE rider_id = $1 AND tenant_id = $2 AND status = 'pending';
+
+-- name: ReviewRiderCashDeposit :one
+UPDATE rider_cash_deposits SET
+    status = sqlc.arg(status),
+    reviewed_by = sqlc.arg(reviewed_by),
+    reviewed_at = NOW(),
+    rejection_reason = sqlc.narg(rejection_reason)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'pending'
+RETURNING *;
+
+-- COD collected per hub and rider within a time range.
+-- name: SummarizeCodCollections :many
+SELECT c.hub_id, c.rider_id, COUNT(*) AS orders,
+    COALESCE(SUM(c.expected_amount), 0)::numeric AS expected_amount,
+    COALESCE(SUM(c.collected_amount), 0)::numeric AS collected_amount
+FROM cod_collections c
+WHERE c.tenant_id = sqlc.arg(tenant_id)
+  AND c.collected_at >= sqlc.arg(from_time) AND c.collected_at < sqlc.arg(to_time)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR c.hub_id = sqlc.narg(hub_id))
+GROUP BY c.hub_id, c.rider_id;
+
+-- Deposits confirmed within a time range and deposits still awaiting a hub
+-- manager, per hub and rider.
+-- name: SummarizeRiderCashDeposits :many
+SELECT d.hub_id, d.rider_id,
+    COALESCE(SUM(d.amount) FILTER (WHERE d.status = 'confirmed'), 0)::numeric AS confirmed_amount,
+    COALESCE(SUM(d.amount) FILTER (WHERE d.status = 'pending'), 0)::numeric AS pending_amount
+FROM rider_cash_deposits d
+WHERE d.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR d.hub_id = sqlc.narg(hub_id))
+  AND ((d.status = 'confirmed' AND d.reviewed_at >= sqlc.arg(from_time) AND d.reviewed_at < sqlc.arg(to_time))
+    OR (d.status = 'pending' AND d.created_at < sqlc.arg(to_time)))
+GROUP BY d.hub_id, d.rider_id;
+
+-- Current cash in hand of every rider, for the reconciliation report.
+-- name: ListRiderCashBalances :many
+SELECT r.id, r.hub_id, r.cash_in_hand, u.name AS rider_name
+FROM riders r
+JOIN users u ON u.id = r.user_id
+WHERE r.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR r.hub_id = sqlc.narg(hub_id))
+ORDER BY u.name;
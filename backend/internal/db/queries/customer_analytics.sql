@@ -0,0 +1,149 @@
+-- name: RefreshCustomerOrderStats :exec
+-- Recomputed from delivered orders so re-running the fact builder is harmless.
+UPDATE users SET
+    total_order_count = (
+        SELECT COUNT(*) FROM orders o
+        WHERE o.customer_id = users.id AND o.status = 'delivered' AND o.deleted_at IS NULL),
+    total_spent_amount = (
+        SELECT COALESCE(SUM(o.total_amount), 0) FROM orders o
+        WHERE o.customer_id = users.id AND o.status = 'delivered' AND o.deleted_at IS NULL),
+    last_order_at = (
+        SELECT MAX(o.created_at) FROM orders o
+        WHERE o.customer_id = users.id AND o.status = 'delivered' AND o.deleted_at IS NULL)
+WHERE id = sqlc.arg(customer_id);
+
+-- name: GetCustomerActivitySummary :one
+-- Customers who ordered in the range; new ones placed their first delivered
+-- order inside it.
+SELECT
+    COUNT(DISTINCT oa.customer_id)::INT AS active_customers,
+    COUNT(DISTINCT oa.customer_id) FILTER (WHERE f.first_date >= sqlc.arg(start_date)::date)::INT AS new_customers,
+    COUNT(*)::INT AS order_count,
+    COALESCE(SUM(oa.total_amount), 0)::NUMERIC(14,2) AS revenue,
+    COALESCE(AVG(oa.total_amount), 0)::NUMERIC(14,2) AS avg_order_value
+FROM order_analytics oa
+JOIN (
+    SELECT customer_id, MIN(order_date) AS first_date
+    FROM order_analytics
+    WHERE tenant_id = sqlc.arg(tenant_id) AND final_status = 'delivered'
+    GROUP BY customer_id
+) f ON f.customer_id = oa.customer_id
+WHERE oa.tenant_id = sqlc.arg(tenant_id)
+  AND oa.final_status = 'delivered'
+  AND oa.order_date >= sqlc.arg(start_date)::date
+  AND oa.order_date <= sqlc.arg(end_date)::date;
+
+-- name: GetCustomerBaseSummary :one
+-- Lifetime figures from the per-customer counters on users.
+SELECT
+    COUNT(*)::INT AS total_customers,
+    COUNT(*) FILTER (WHERE total_order_count > 0)::INT AS ordering_customers,
+    COUNT(*) FILTER (WHERE total_order_count > 1)::INT AS repeat_customers,
+    COUNT(*) FILTER (WHERE total_order_count > 0 AND last_order_at < sqlc.arg(churned_before)::timestamptz)::INT AS churned_customers,
+    COALESCE(AVG(total_spent_amount) FILTER (WHERE total_order_count > 0), 0)::NUMERIC(14,2) AS avg_lifetime_value
+FROM users
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND role = 'customer'
+  AND deleted_at IS NULL;
+
+-- name: ListMonthlyCustomerTrend :many
+SELECT
+    date_trunc('month', oa.order_date)::date AS month,
+    COUNT(DISTINCT oa.customer_id)::INT AS active_customers,
+    COUNT(DISTINCT oa.customer_id) FILTER (WHERE date_trunc('month', f.first_date) = date_trunc('month', oa.order_date))::INT AS new_customers,
+    COUNT(*)::INT AS order_count,
+    COALESCE(SUM(oa.total_amount), 0)::NUMERIC(14,2) AS revenue,
+    COALESCE(AVG(oa.total_amount), 0)::NUMERIC(14,2) AS avg_order_value
+FROM order_analytics oa
+JOIN (
+    SELECT customer_id, MIN(order_date) AS first_date
+    FROM order_analytics
+    WHERE tenant_id = sqlc.arg(tenant_id) AND final_status = 'delivered'
+    GROUP BY customer_id
+) f ON f.customer_id = oa.customer_id
+WHERE oa.tenant_id = sqlc.arg(tenant_id)
+  AND oa.final_status = 'delivered'
+  AND oa.order_date >= sqlc.arg(start_date)::date
+  AND oa.order_date <= sqlc.arg(end_date)::date
+GROUP BY 1
+ORDER BY 1;
+
+-- name: ListSignupCohortSizes :many
+-- Customers by the Bangladesh month they signed up in.
+SELECT
+    date_trunc('month', created_at AT TIME ZONE 'Asia/Dhaka')::date AS cohort_month,
+    COUNT(*)::INT AS customers
+FROM users
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND role = 'customer'
+  AND deleted_at IS NULL
+  AND created_at >= sqlc.arg(from_time)::timestamptz
+GROUP BY 1
+ORDER BY 1;
+
+-- name: ListSignupCohortActivity :many
+-- Distinct customers of each signup cohort with a delivered order in each month.
+SELECT
+    date_trunc('month', u.created_at AT TIME ZONE 'Asia/Dhaka')::date AS cohort_month,
+    date_trunc('month', oa.order_date)::date AS activity_month,
+    COUNT(DISTINCT oa.customer_id)::INT AS active_customers
+FROM users u
+JOIN order_analytics oa ON oa.customer_id = u.id AND oa.final_status = 'delivered'
+WHERE u.tenant_id = sqlc.arg(tenant_id)
+  AND u.role = 'customer'
+  AND u.deleted_at IS NULL
+  AND u.created_at >= sqlc.arg(from_time)::timestamptz
+GROUP BY 1, 2
+ORDER BY 1, 2;
+
+-- name: ListCustomerRFMInputs :many
+-- Every customer with at least one delivered order; scoring happens in Go.
+SELECT
+    id,
+    name,
+    phone,
+    email,
+    total_order_count,
+    total_spent_amount,
+    last_order_at
+FROM users
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND role = 'customer'
+  AND deleted_at IS NULL
+  AND total_order_count > 0
+  AND last_order_at IS NOT NULL
+ORDER BY id;
+
+-- name: ListCustomerLTVByAcquisitionPromo :many
+-- Customers grouped by the promo code on their first delivered order
+-- (empty for organic customers), for customers acquired in the range.
+SELECT
+    COALESCE(f.promo_code, '')::TEXT AS acquisition_promo,
+    COUNT(*)::INT AS customers,
+    COUNT(*) FILTER (WHERE u.total_order_count > 1)::INT AS repeat_customers,
+    COALESCE(AVG(u.total_order_count), 0)::NUMERIC(10,2) AS avg_orders,
+    COALESCE(SUM(u.total_spent_amount), 0)::NUMERIC(14,2) AS lifetime_revenue,
+    COALESCE(AVG(u.total_spent_amount), 0)::NUMERIC(14,2) AS avg_lifetime_value
+FROM (
+    SELECT DISTINCT ON (customer_id) customer_id, promo_code, order_date
+    FROM order_analytics
+    WHERE tenant_id = sqlc.arg(tenant_id) AND final_status = 'delivered'
+    ORDER BY customer_id, order_date, completed_at
+) f
+JOIN users u ON u.id = f.customer_id
+WHERE f.order_date >= sqlc.arg(start_date)::date
+  AND f.order_date <= sqlc.arg(end_date)::date
+GROUP BY 1
+ORDER BY customers DESC;
+
+-- name: AddPromoUserEligibilityBulk :execrows
+INSERT INTO promo_user_eligibility (promo_id, user_id)
+SELECT sqlc.arg(promo_id)::uuid, unnest(sqlc.arg(user_ids)::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: RemovePromoUserEligibilityExcept :execrows
+-- Trims a promo's eligibility list to the given users without ever leaving it
+-- empty, which would open the promo to everyone.
+DELETE FROM promo_user_eligibility
+WHERE promo_id = sqlc.arg(promo_id)
+  AND NOT (user_id = ANY(sqlc.arg(user_ids)::uuid[]));
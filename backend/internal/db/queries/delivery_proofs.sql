@@ -0,0 +1,49 @@
+-- ============================================================
+-- Proof of delivery records
+-- ============================================================
+
+-- Opens the proof record when the rider collects the order. An existing
+-- delivery code is never replaced, so the customer keeps seeing the same one.
+-- name: OpenDeliveryProof :one
+INSERT INTO delivery_proofs (tenant_id, order_id, rider_id, pod_required, otp_code)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (order_id) DO UPDATE SET
+  rider_id = COALESCE(EXCLUDED.rider_id, delivery_proofs.rider_id),
+  pod_required = EXCLUDED.pod_required,
+  otp_code = COALESCE(delivery_proofs.otp_code, EXCLUDED.otp_code)
+RETURNING *;
+
+-- name: GetDeliveryProofByOrder :one
+SELECT * FROM delivery_proofs WHERE order_id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: IncrementDeliveryProofOtpAttempts :one
+UPDATE delivery_proofs SET otp_attempts = otp_attempts + 1
+WHERE order_id = $1 AND tenant_id = $2
+RETURNING otp_attempts;
+
+-- name: CompleteDeliveryProof :one
+INSERT INTO delivery_proofs (
+  tenant_id, order_id, rider_id, pod_required, otp_verified_at, photo_url,
+  rider_geo_lat, rider_geo_lng, location_recorded_at, distance_meters,
+  geofence_radius_m, within_geofence, delivered_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
+ON CONFLICT (order_id) DO UPDATE SET
+  rider_id = EXCLUDED.rider_id,
+  pod_required = EXCLUDED.pod_required,
+  otp_verified_at = EXCLUDED.otp_verified_at,
+  photo_url = EXCLUDED.photo_url,
+  rider_geo_lat = EXCLUDED.rider_geo_lat,
+  rider_geo_lng = EXCLUDED.rider_geo_lng,
+  location_recorded_at = EXCLUDED.location_recorded_at,
+  distance_meters = EXCLUDED.distance_meters,
+  geofence_radius_m = EXCLUDED.geofence_radius_m,
+  within_geofence = EXCLUDED.within_geofence,
+  delivered_at = EXCLUDED.delivered_at
+RETURNING *;
+
+-- True when any restaurant on the order demands proof of delivery.
+-- name: OrderRestaurantsRequirePod :one
+SELECT COALESCE(bool_or(r.require_pod), false)::boolean AS require_pod
+FROM order_pickups p
+JOIN restaurants r ON r.id = p.restaurant_id
+WHERE p.order_id = $1 AND p.tenant_id = $2;
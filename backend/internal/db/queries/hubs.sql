@@ -1,6 +1,6 @@
 -- name: CreateHub :one
-INSERT INTO hubs (tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+INSERT INTO hubs (tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, rider_cash_limit, checkin_radius_m)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 RETURNING *;
 
 -- name: GetHubByID :one
@@ -19,7 +19,10 @@ UPDATE hubs SET
   contact_phone = COALESCE(sqlc.narg(contact_phone), contact_phone),
   contact_email = COALESCE(sqlc.narg(contact_email), contact_email),
   is_active = COALESCE(sqlc.narg(is_active), is_active),
-  sort_order = COALESCE(sqlc.narg(sort_order), sort_order)
+  sort_order = COALESCE(sqlc.narg(sort_order), sort_order),
+  manager_id = COALESCE(sqlc.narg(manager_id), manager_id),
+  rider_cash_limit = COALESCE(sqlc.narg(rider_cash_limit), rider_cash_limit),
+  checkin_radius_m = COALESCE(sqlc.narg(checkin_radius_m), checkin_radius_m)
 WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
 RETURNING *;
 
@@ -58,10 +61,16 @@ DELETE FROM hub_coverage_areas WHERE id = $1;
 SELECT * FROM delivery_zone_configs WHERE tenant_id = $1 LIMIT 1;
 
 -- name: UpsertDeliveryZoneConfig :one
-INSERT INTO delivery_zone_configs (tenant_id, model, distance_tiers, free_delivery_threshold)
-VALUES ($1, $2, $3, $4)
+INSERT INTO delivery_zone_configs (
+  tenant_id, model, distance_tiers, free_delivery_threshold,
+  pod_otp_enabled, pod_photo_required, pod_geofence_radius_m, pod_min_order_value
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (tenant_id) DO UPDATE SET
   model = EXCLUDED.model,
   distance_tiers = EXCLUDED.distance_tiers,
-  free_delivery_threshold = EXCLUDED.free_delivery_threshold
+  free_delivery_threshold = EXCLUDED.free_delivery_threshold,
+  pod_otp_enabled = EXCLUDED.pod_otp_enabled,
+  pod_photo_required = EXCLUDED.pod_photo_required,
+  pod_geofence_radius_m = EXCLUDED.pod_geofence_radius_m,
+  pod_min_order_value = EXCLUDED.pod_min_order_value
 RETURNING *;
@@ -66,6 +66,11 @@ WHERE product_id = sqlc.arg(product_id) AND restaurant_id = sqlc.arg(restaurant_
   AND stock_qty >= sqlc.arg(qty)::INT
 RETURNING *;
 
+-- name: GetInventoryItemForUpdate :one
+SELECT * FROM inventory_items
+WHERE id = $1 AND tenant_id = $2
+FOR UPDATE;
+
 -- name: GetInventoryForUpdate :one
 SELECT * FROM inventory_items
 WHERE product_id = $1 AND restaurant_id = $2
@@ -91,3 +96,34 @@ INSERT INTO inventory_items (
     reserved_qty, cost_price, reorder_threshold
 ) VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
+
+-- name: UpdateProductStockAvailability :exec
+UPDATE products SET availability = sqlc.arg(availability)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND availability = sqlc.arg(current_availability);
+
+-- name: ListInventoryConsumption :many
+SELECT oi.product_id, SUM(oi.quantity)::BIGINT AS units_sold
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE oi.restaurant_id = sqlc.arg(restaurant_id) AND oi.tenant_id = sqlc.arg(tenant_id)
+  AND oi.product_id = ANY(sqlc.arg(product_ids)::UUID[])
+  AND oi.created_at >= sqlc.arg(since)
+  AND o.status NOT IN ('cancelled', 'rejected')
+GROUP BY oi.product_id;
+
+-- name: ReceiveStock :one
+UPDATE inventory_items
+SET stock_qty = stock_qty + sqlc.arg(qty)::INT,
+    cost_price = sqlc.arg(cost_price)::NUMERIC,
+    last_restocked_at = NOW()
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: GetStockValuation :many
+SELECT i.id, i.product_id, p.name AS product_name, i.stock_qty, i.reserved_qty, i.cost_price,
+       (i.stock_qty * COALESCE(i.cost_price, 0))::NUMERIC(14,2) AS stock_value
+FROM inventory_items i
+JOIN products p ON p.id = i.product_id
+WHERE i.tenant_id = $1 AND i.restaurant_id = $2
+ORDER BY stock_value DESC, p.name;
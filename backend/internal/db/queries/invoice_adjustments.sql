@@ -0,0 +1,67 @@
+-- name: CreateIssueInvoiceAdjustment :one
+INSERT INTO invoice_adjustments (tenant_id, restaurant_id, order_id, issue_id, amount, reason, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (issue_id, restaurant_id) WHERE issue_id IS NOT NULL DO NOTHING
+RETURNING *;
+
+-- Adjustments waiting for the restaurant's next invoice. Refund charges wait
+-- until the refund itself has gone through. The rows are locked so
+-- overlapping invoice runs cannot both settle them.
+-- name: ListUnsettledInvoiceAdjustments :many
+SELECT * FROM invoice_adjustments ia
+WHERE ia.tenant_id = $1 AND ia.restaurant_id = $2 AND ia.invoice_id IS NULL
+  AND ia.created_at < sqlc.arg(before)::timestamptz
+  AND (ia.refund_id IS NULL OR EXISTS (
+      SELECT 1 FROM refunds r
+      WHERE r.id = ia.refund_id AND r.status IN ('approved','processed')
+  ))
+ORDER BY ia.created_at
+FOR UPDATE OF ia;
+
+-- name: AttachInvoiceAdjustments :execrows
+UPDATE invoice_adjustments SET invoice_id = sqlc.arg(invoice_id)
+WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND invoice_id IS NULL;
+
+-- name: ListInvoiceAdjustmentsByInvoice :many
+SELECT * FROM invoice_adjustments
+WHERE invoice_id = $1 AND tenant_id = $2
+ORDER BY created_at;
+
+-- name: CreateManualInvoiceAdjustment :one
+INSERT INTO invoice_adjustments (tenant_id, restaurant_id, order_id, amount, reason, created_by, kind)
+VALUES ($1, $2, $3, $4, $5, $6, 'manual')
+RETURNING *;
+
+-- name: CreateRefundInvoiceAdjustment :one
+INSERT INTO invoice_adjustments (tenant_id, restaurant_id, order_id, refund_id, amount, reason, created_by, kind)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 'refund')
+ON CONFLICT (refund_id, restaurant_id) WHERE refund_id IS NOT NULL DO NOTHING
+RETURNING *;
+
+-- What is left of a restaurant's share of an order to charge refunds against:
+-- the pickup total less refunds already charged to the restaurant for it.
+-- The pickup is locked so concurrent refunds cannot charge past it.
+-- name: GetPickupRefundableAmount :one
+SELECT (p.items_total - COALESCE(r.charged, 0))::NUMERIC AS refundable
+FROM order_pickups p
+LEFT JOIN (
+    SELECT a.order_id, a.restaurant_id, SUM(a.amount) AS charged
+    FROM invoice_adjustments a
+    WHERE a.kind = 'refund'
+    GROUP BY a.order_id, a.restaurant_id
+) r ON r.order_id = p.order_id AND r.restaurant_id = p.restaurant_id
+WHERE p.order_id = $1 AND p.restaurant_id = $2 AND p.tenant_id = $3
+FOR UPDATE OF p;
+
+-- name: ListPendingInvoiceAdjustments :many
+SELECT * FROM invoice_adjustments
+WHERE tenant_id = $1 AND restaurant_id = $2 AND invoice_id IS NULL
+ORDER BY created_at;
+
+-- name: CreateNoteInvoiceAdjustment :one
+INSERT INTO invoice_adjustments (tenant_id, restaurant_id, amount, reason, created_by, kind)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetInvoiceAdjustment :one
+SELECT * FROM invoice_adjustments WHERE id = $1 AND tenant_id = $2 LIMIT 1;
@@ -0,0 +1,75 @@
+-- ============================================================
+-- Invoice Dispute and Credit/Debit Note SQLC Queries
+-- ============================================================
+
+-- name: CreateInvoiceDispute :one
+INSERT INTO invoice_disputes (
+    tenant_id, invoice_id, restaurant_id, line_item_id, reason, disputed_amount, raised_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetInvoiceDispute :one
+SELECT * FROM invoice_disputes WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: GetInvoiceDisputeForUpdate :one
+SELECT * FROM invoice_disputes WHERE id = $1 AND tenant_id = $2 FOR UPDATE;
+
+-- name: ListInvoiceDisputesByInvoice :many
+SELECT * FROM invoice_disputes
+WHERE invoice_id = $1 AND tenant_id = $2
+ORDER BY created_at;
+
+-- name: ListInvoiceDisputes :many
+-- restaurant_ids is NULL for tenant-wide users.
+SELECT * FROM invoice_disputes
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(status)::text IS NULL OR status::text = sqlc.narg(status))
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[]))
+ORDER BY created_at DESC
+LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
+
+-- name: CountInvoiceDisputes :one
+SELECT COUNT(*) FROM invoice_disputes
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(status)::text IS NULL OR status::text = sqlc.narg(status))
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[]));
+
+-- name: UpdateInvoiceDisputeStatus :one
+UPDATE invoice_disputes SET
+  status = sqlc.arg(status),
+  resolution_note = COALESCE(sqlc.narg(resolution_note), resolution_note),
+  resolved_by = COALESCE(sqlc.narg(resolved_by)::uuid, resolved_by),
+  resolved_at = CASE WHEN sqlc.arg(status)::invoice_dispute_status IN ('resolved','rejected') THEN NOW() ELSE resolved_at END
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: CreateInvoiceDisputeComment :one
+INSERT INTO invoice_dispute_comments (dispute_id, tenant_id, author_id, message, attachments)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListInvoiceDisputeComments :many
+SELECT * FROM invoice_dispute_comments
+WHERE dispute_id = $1 AND tenant_id = $2
+ORDER BY created_at;
+
+-- name: NextInvoiceNoteSerial :one
+INSERT INTO invoice_note_serials (tenant_id, note_type, last_serial)
+VALUES ($1, $2, 1)
+ON CONFLICT (tenant_id, note_type) DO UPDATE SET last_serial = invoice_note_serials.last_serial + 1
+RETURNING last_serial;
+
+-- name: CreateInvoiceNote :one
+INSERT INTO invoice_notes (
+    tenant_id, invoice_id, restaurant_id, dispute_id, note_type, note_number,
+    amount, reason, adjustment_id, issued_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetInvoiceNote :one
+SELECT * FROM invoice_notes WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: ListInvoiceNotesByInvoice :many
+SELECT * FROM invoice_notes
+WHERE invoice_id = $1 AND tenant_id = $2
+ORDER BY created_at;
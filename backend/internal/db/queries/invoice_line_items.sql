@@ -0,0 +1,54 @@
+-- name: ListSettlementPickups :many
+-- The restaurant's share of orders delivered in the period. Pickups the
+-- restaurant rejected are not settled, and orders already on one of the
+-- restaurant's invoices are not settled again.
+SELECT
+    op.order_id,
+    o.order_number,
+    o.delivered_at,
+    op.items_subtotal,
+    op.items_discount,
+    op.items_vat,
+    op.commission_rate,
+    op.commission_amount,
+    o.promo_snapshot
+FROM order_pickups op
+JOIN orders o ON o.id = op.order_id
+WHERE o.tenant_id = sqlc.arg(tenant_id)
+  AND op.restaurant_id = sqlc.arg(restaurant_id)
+  AND op.status <> 'rejected'
+  AND o.status = 'delivered'
+  AND o.deleted_at IS NULL
+  AND o.delivered_at >= sqlc.arg(period_start)::timestamptz
+  AND o.delivered_at < sqlc.arg(period_end)::timestamptz
+  AND NOT EXISTS (
+      SELECT 1 FROM invoice_line_items li
+      JOIN invoices i ON i.id = li.invoice_id
+      WHERE li.order_id = op.order_id
+        AND li.line_type = 'order'
+        AND i.restaurant_id = op.restaurant_id
+  )
+ORDER BY o.delivered_at, o.id;
+
+-- name: CreateInvoiceLineItem :exec
+INSERT INTO invoice_line_items (
+    tenant_id, invoice_id, line_type, order_id, adjustment_id, description,
+    gross_sales, item_discount, vendor_promo_discount, net_sales, vat,
+    commission_rate, commission_amount, amount, occurred_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+
+-- name: ListInvoiceLineItems :many
+SELECT * FROM invoice_line_items
+WHERE invoice_id = sqlc.arg(invoice_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(line_type)::text IS NULL OR line_type = sqlc.narg(line_type))
+ORDER BY line_type, occurred_at, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountInvoiceLineItems :one
+SELECT COUNT(*) FROM invoice_line_items
+WHERE invoice_id = sqlc.arg(invoice_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(line_type)::text IS NULL OR line_type = sqlc.narg(line_type));
+
+-- name: GetInvoiceLineItem :one
+SELECT * FROM invoice_line_items
+WHERE id = $1 AND invoice_id = $2 AND tenant_id = $3 LIMIT 1;
@@ -4,9 +4,9 @@ INSERT INTO invoices (
     gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected,
     commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note,
     net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders,
-    status, generated_by, notes
+    status, generated_by, notes, refund_amount
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
 ) RETURNING *;
 
 -- name: GetInvoiceByID :one
@@ -73,25 +73,59 @@ WHERE o.tenant_id = $1
 -- name: CountOrdersByRestaurantAndPeriod :one
 SELECT
     COUNT(*)::INT AS total_orders,
-    COUNT(CASE WHEN status = 'delivered' THEN 1 END)::INT AS delivered_orders,
-    COUNT(CASE WHEN status = 'cancelled' THEN 1 END)::INT AS cancelled_orders,
-    COUNT(CASE WHEN status = 'rejected' THEN 1 END)::INT AS rejected_orders
-FROM orders
-WHERE tenant_id = $1
-  AND deleted_at IS NULL
-  AND created_at >= sqlc.arg(period_start)::timestamptz
-  AND created_at < sqlc.arg(period_end)::timestamptz;
-
--- name: GetOrderPickupsByRestaurantAndPeriod :many
-SELECT op.* FROM order_pickups op
-JOIN orders o ON op.order_id = o.id
-WHERE o.tenant_id = $1
-  AND op.restaurant_id = $2
-  AND o.status = 'delivered'
+    COUNT(CASE WHEN o.status = 'delivered' THEN 1 END)::INT AS delivered_orders,
+    COUNT(CASE WHEN o.status = 'cancelled' THEN 1 END)::INT AS cancelled_orders,
+    COUNT(CASE WHEN o.status = 'rejected' THEN 1 END)::INT AS rejected_orders
+FROM orders o
+WHERE o.tenant_id = sqlc.arg(tenant_id)
   AND o.deleted_at IS NULL
-  AND o.delivered_at >= sqlc.arg(period_start)::timestamptz
-  AND o.delivered_at < sqlc.arg(period_end)::timestamptz;
+  AND o.created_at >= sqlc.arg(period_start)::timestamptz
+  AND o.created_at < sqlc.arg(period_end)::timestamptz
+  AND EXISTS (
+      SELECT 1 FROM order_pickups op
+      WHERE op.order_id = o.id AND op.restaurant_id = sqlc.arg(restaurant_id)
+  );
 
 -- name: ListActiveRestaurantsByTenant :many
 SELECT id, tenant_id, name FROM restaurants
 WHERE tenant_id = $1 AND deleted_at IS NULL AND is_active = true;
+
+-- name: ListRestaurantsToInvoice :many
+-- Restaurants with delivered orders in the period or adjustments created
+-- before its end that no invoice has settled.
+SELECT r.id FROM restaurants r
+WHERE r.tenant_id = sqlc.arg(tenant_id)
+  AND (
+      EXISTS (
+          SELECT 1 FROM order_pickups op
+          JOIN orders o ON o.id = op.order_id
+          WHERE op.restaurant_id = r.id
+            AND o.status = 'delivered'
+            AND o.delivered_at >= sqlc.arg(period_start)::timestamptz
+            AND o.delivered_at < sqlc.arg(period_end)::timestamptz
+      )
+      OR EXISTS (
+          SELECT 1 FROM invoice_adjustments a
+          WHERE a.restaurant_id = r.id
+            AND a.invoice_id IS NULL
+            AND a.created_at < sqlc.arg(period_end)::timestamptz
+      )
+  )
+ORDER BY r.name;
+
+-- name: ListInvoicesDueForFinalize :many
+SELECT * FROM invoices
+WHERE tenant_id = sqlc.arg(tenant_id) AND status = 'draft' AND created_at < sqlc.arg(before)
+ORDER BY created_at;
+
+-- name: ListInvoicesReadyForPayout :many
+-- Finalized invoices without a payout in flight. Invoices whose payout
+-- failed come back here for the next batch.
+SELECT * FROM invoices i
+WHERE i.tenant_id = $1
+  AND i.status = 'finalized'
+  AND NOT EXISTS (
+      SELECT 1 FROM vendor_payouts p
+      WHERE p.invoice_id = i.id AND p.status <> 'failed'
+  )
+ORDER BY i.period_end, i.restaurant_id;
@@ -11,10 +11,10 @@ INSERT INTO orders (
     promo_discount_total, vat_total, delivery_charge, service_fee,
     total_amount, promo_id, promo_code, promo_snapshot,
     is_priority, is_reorder, customer_note, auto_confirm_at,
-    estimated_delivery_minutes
+    estimated_delivery_minutes, rider_tip
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
-    $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29
+    $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
 )
 RETURNING *;
 
@@ -209,6 +209,12 @@ UPDATE orders SET rider_id = $3
 WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
+-- Only the first rider to accept an offer gets the order.
+-- name: ClaimOrderForRider :one
+UPDATE orders SET rider_id = $3
+WHERE id = $1 AND tenant_id = $2 AND (rider_id IS NULL OR rider_id = $3)
+RETURNING *;
+
 -- name: GetOrderForReconciliation :many
 SELECT * FROM orders
 WHERE payment_status = 'unpaid' AND status IN ('pending', 'created')
@@ -98,13 +98,21 @@ DELETE FROM search_logs WHERE created_at < sqlc.arg(before)::timestamptz;
 DELETE FROM audit_logs WHERE created_at < sqlc.arg(before)::timestamptz;
 
 -- name: ListOrderIssuesByTenant :many
-SELECT * FROM order_issues
-WHERE tenant_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+-- restaurant_ids limits the list to issues on orders picked up from those restaurants.
+SELECT * FROM order_issues i
+WHERE i.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups p
+        WHERE p.order_id = i.order_id AND p.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[])))
+ORDER BY i.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountOrderIssuesByTenant :one
-SELECT COUNT(*) FROM order_issues WHERE tenant_id = $1;
+SELECT COUNT(*) FROM order_issues i
+WHERE i.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups p
+        WHERE p.order_id = i.order_id AND p.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[])));
 
 -- name: ListOrderIssuesByOrder :many
 SELECT * FROM order_issues
@@ -116,6 +124,18 @@ UPDATE order_issues SET status = $3, resolution_note = $4, resolved_by_id = $5,
 WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
+-- name: ResolveOrderIssue :one
+UPDATE order_issues SET
+  status = 'resolved',
+  accountable_party = sqlc.arg(accountable_party),
+  rider_penalty_amount = sqlc.arg(rider_penalty_amount),
+  restaurant_penalty_amount = sqlc.arg(restaurant_penalty_amount),
+  resolution_note = sqlc.narg(resolution_note),
+  resolved_by_id = sqlc.arg(resolved_by_id),
+  resolved_at = NOW()
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'open'
+RETURNING *;
+
 -- name: UpdateOrderIssueRefund :one
 UPDATE order_issues SET refund_status = $3, refund_amount = $4
 WHERE id = $1 AND tenant_id = $2
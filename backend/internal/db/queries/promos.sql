@@ -7,8 +7,11 @@ INSERT INTO promos (
     tenant_id, code, title, description, promo_type,
     discount_amount, max_discount_cap, cashback_amount, funded_by,
     applies_to, min_order_amount, max_total_uses, max_uses_per_user,
-    include_stores, is_active, starts_at, ends_at, created_by
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+    include_stores, is_active, starts_at, ends_at, created_by,
+    rule_type, buy_qty, get_qty, first_order_only, new_customer_days,
+    auto_apply, stacking, budget_cap
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+    $19, $20, $21, $22, $23, $24, $25, $26)
 RETURNING *;
 
 -- name: GetPromoByID :one
@@ -40,32 +43,58 @@ UPDATE promos SET
     max_total_uses = COALESCE(sqlc.narg(max_total_uses), max_total_uses),
     max_uses_per_user = COALESCE(sqlc.narg(max_uses_per_user), max_uses_per_user),
     starts_at = COALESCE(sqlc.narg(starts_at), starts_at),
-    ends_at = COALESCE(sqlc.narg(ends_at), ends_at)
+    ends_at = COALESCE(sqlc.narg(ends_at), ends_at),
+    buy_qty = COALESCE(sqlc.narg(buy_qty), buy_qty),
+    get_qty = COALESCE(sqlc.narg(get_qty), get_qty),
+    first_order_only = COALESCE(sqlc.narg(first_order_only), first_order_only),
+    new_customer_days = COALESCE(sqlc.narg(new_customer_days), new_customer_days),
+    auto_apply = COALESCE(sqlc.narg(auto_apply), auto_apply),
+    stacking = COALESCE(sqlc.narg(stacking), stacking),
+    budget_cap = COALESCE(sqlc.narg(budget_cap), budget_cap)
 WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
 RETURNING *;
 
 -- name: DeactivatePromo :one
 UPDATE promos
-SET is_active = false
+SET is_active = false,
+    budget_exhausted_at = NULL
 WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
--- name: IncrementPromoUsage :exec
+-- Claims one use and the discount against the promo's limits in a single
+-- statement. The row lock it takes is held until the order transaction
+-- commits, which serialises concurrent checkouts of the same promo. No row
+-- means a limit or the budget was reached in the meantime.
+-- name: RedeemPromo :one
 UPDATE promos
 SET total_uses = total_uses + 1,
-    total_discount_given = total_discount_given + sqlc.arg(discount_amount)
-WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id);
+    total_discount_given = total_discount_given + sqlc.arg(discount_amount),
+    is_active = CASE
+        WHEN budget_cap IS NOT NULL AND total_discount_given + sqlc.arg(discount_amount) >= budget_cap THEN false
+        ELSE is_active
+    END,
+    budget_exhausted_at = CASE
+        WHEN budget_cap IS NOT NULL AND total_discount_given + sqlc.arg(discount_amount) >= budget_cap THEN NOW()
+        ELSE budget_exhausted_at
+    END
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND is_active = true
+  AND starts_at <= NOW()
+  AND (ends_at IS NULL OR ends_at > NOW())
+  AND (max_total_uses IS NULL OR total_uses < max_total_uses)
+  AND (budget_cap IS NULL OR total_discount_given + sqlc.arg(discount_amount) <= budget_cap)
+RETURNING *;
 
 -- name: CreatePromoUsage :one
 INSERT INTO promo_usages (
     promo_id, user_id, order_id, tenant_id,
-    discount_amount, cashback_amount
-) VALUES ($1, $2, $3, $4, $5, $6)
+    discount_amount, cashback_amount, funded_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: GetUsageCountByUserAndPromo :one
 SELECT COUNT(*) FROM promo_usages
-WHERE user_id = $1 AND promo_id = $2;
+WHERE user_id = $1 AND promo_id = $2 AND reversed_at IS NULL;
 
 -- name: ListPromoRestaurantRestrictions :many
 SELECT restaurant_id FROM promo_restaurant_restrictions
@@ -116,3 +145,100 @@ WHERE code = $1 AND tenant_id = $2
   AND is_active = true
   AND starts_at <= NOW()
   AND (ends_at IS NULL OR ends_at > NOW());
+
+-- name: ListPromoProductRestrictions :many
+SELECT product_id FROM promo_product_restrictions
+WHERE promo_id = $1;
+
+-- name: AddPromoProductRestriction :exec
+INSERT INTO promo_product_restrictions (promo_id, product_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemovePromoProductRestrictions :exec
+DELETE FROM promo_product_restrictions
+WHERE promo_id = $1;
+
+-- name: CreatePromoTier :one
+INSERT INTO promo_tiers (promo_id, min_subtotal, discount_amount)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListPromoTiers :many
+SELECT * FROM promo_tiers
+WHERE promo_id = $1
+ORDER BY min_subtotal;
+
+-- name: RemovePromoTiers :exec
+DELETE FROM promo_tiers
+WHERE promo_id = $1;
+
+-- name: CreatePromoTimeWindow :one
+INSERT INTO promo_time_windows (promo_id, day_of_week, start_minute, end_minute)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListPromoTimeWindows :many
+SELECT * FROM promo_time_windows
+WHERE promo_id = $1
+ORDER BY day_of_week NULLS FIRST, start_minute;
+
+-- name: RemovePromoTimeWindows :exec
+DELETE FROM promo_time_windows
+WHERE promo_id = $1;
+
+-- name: GetPromoCustomerHistory :one
+SELECT u.created_at AS registered_at, COUNT(o.id) AS order_count
+FROM users u
+LEFT JOIN orders o ON o.customer_id = u.id
+    AND o.tenant_id = sqlc.arg(tenant_id)
+    AND o.status NOT IN ('cancelled', 'rejected')
+    AND o.deleted_at IS NULL
+WHERE u.id = sqlc.arg(user_id)
+GROUP BY u.id;
+
+-- name: ListActiveAutoApplyPromos :many
+SELECT * FROM promos
+WHERE tenant_id = $1
+  AND auto_apply = true
+  AND is_active = true
+  AND starts_at <= NOW()
+  AND (ends_at IS NULL OR ends_at > NOW())
+ORDER BY created_at;
+
+-- Reverses an order's promo usages and gives the uses and discount back to
+-- each promo. Promos deactivated for spending their budget become active
+-- again.
+-- name: ReleasePromoUsagesForOrder :exec
+WITH reversed AS (
+    UPDATE promo_usages
+    SET reversed_at = NOW()
+    WHERE order_id = sqlc.arg(order_id) AND tenant_id = sqlc.arg(tenant_id) AND reversed_at IS NULL
+    RETURNING promo_id, discount_amount
+)
+UPDATE promos p
+SET total_uses = GREATEST(p.total_uses - r.uses, 0),
+    total_discount_given = GREATEST(p.total_discount_given - r.amount, 0),
+    is_active = p.is_active OR p.budget_exhausted_at IS NOT NULL,
+    budget_exhausted_at = NULL
+FROM (
+    SELECT promo_id, COUNT(*)::int AS uses, SUM(discount_amount) AS amount
+    FROM reversed
+    GROUP BY promo_id
+) r
+WHERE p.id = r.promo_id;
+
+-- name: GetPromoRedemptionReport :many
+SELECT p.id AS promo_id, p.code, p.title, pu.funded_by,
+    COUNT(*) AS redemptions,
+    COUNT(DISTINCT pu.user_id) AS customers,
+    COALESCE(SUM(pu.discount_amount), 0)::numeric AS discount_total,
+    COALESCE(SUM(pu.cashback_amount), 0)::numeric AS cashback_total
+FROM promo_usages pu
+JOIN promos p ON p.id = pu.promo_id
+WHERE pu.tenant_id = sqlc.arg(tenant_id)
+  AND pu.reversed_at IS NULL
+  AND pu.created_at >= sqlc.arg(from_date)
+  AND pu.created_at < sqlc.arg(to_date)
+GROUP BY p.id, p.code, p.title, pu.funded_by
+ORDER BY discount_total DESC;
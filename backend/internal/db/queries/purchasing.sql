@@ -0,0 +1,101 @@
+-- ============================================================
+-- Purchasing SQLC Queries
+-- ============================================================
+
+-- name: CreateSupplier :one
+INSERT INTO suppliers (
+    tenant_id, name, contact_name, phone, email, address, lead_time_days, notes
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetSupplier :one
+SELECT * FROM suppliers
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListSuppliers :many
+SELECT * FROM suppliers
+WHERE tenant_id = $1
+ORDER BY name
+LIMIT $2 OFFSET $3;
+
+-- name: CountSuppliers :one
+SELECT COUNT(*) FROM suppliers
+WHERE tenant_id = $1;
+
+-- name: UpdateSupplier :one
+UPDATE suppliers SET
+    name = COALESCE(sqlc.narg(name), name),
+    contact_name = COALESCE(sqlc.narg(contact_name), contact_name),
+    phone = COALESCE(sqlc.narg(phone), phone),
+    email = COALESCE(sqlc.narg(email), email),
+    address = COALESCE(sqlc.narg(address), address),
+    lead_time_days = COALESCE(sqlc.narg(lead_time_days), lead_time_days),
+    notes = COALESCE(sqlc.narg(notes), notes),
+    is_active = COALESCE(sqlc.narg(is_active), is_active)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: NextPurchaseOrderSerial :one
+INSERT INTO purchase_order_serials (tenant_id, last_serial)
+VALUES ($1, 1)
+ON CONFLICT (tenant_id) DO UPDATE SET last_serial = purchase_order_serials.last_serial + 1
+RETURNING last_serial;
+
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (
+    tenant_id, restaurant_id, supplier_id, po_number,
+    expected_at, total_amount, notes, created_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetPurchaseOrder :one
+SELECT * FROM purchase_orders
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: GetPurchaseOrderForUpdate :one
+SELECT * FROM purchase_orders
+WHERE id = $1 AND tenant_id = $2
+FOR UPDATE;
+
+-- name: ListPurchaseOrders :many
+SELECT * FROM purchase_orders
+WHERE tenant_id = $1 AND restaurant_id = $2
+  AND (sqlc.narg(status)::purchase_order_status IS NULL OR status = sqlc.narg(status))
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountPurchaseOrders :one
+SELECT COUNT(*) FROM purchase_orders
+WHERE tenant_id = $1 AND restaurant_id = $2
+  AND (sqlc.narg(status)::purchase_order_status IS NULL OR status = sqlc.narg(status));
+
+-- name: UpdatePurchaseOrderStatus :one
+UPDATE purchase_orders SET
+    status = sqlc.arg(status),
+    sent_at = CASE WHEN sqlc.arg(status) = 'sent' THEN NOW() ELSE sent_at END,
+    received_at = CASE WHEN sqlc.arg(status) = 'received' THEN NOW() ELSE received_at END,
+    cancelled_at = CASE WHEN sqlc.arg(status) = 'cancelled' THEN NOW() ELSE cancelled_at END
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (
+    purchase_order_id, tenant_id, inventory_item_id, product_id, qty_ordered, unit_cost
+) VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListPurchaseOrderItems :many
+SELECT * FROM purchase_order_items
+WHERE purchase_order_id = $1
+ORDER BY created_at;
+
+-- name: ReceivePurchaseOrderItem :one
+UPDATE purchase_order_items
+SET qty_received = qty_received + sqlc.arg(qty)::INT
+WHERE id = sqlc.arg(id) AND purchase_order_id = sqlc.arg(purchase_order_id)
+  AND qty_received + sqlc.arg(qty)::INT <= qty_ordered
+RETURNING *;
+
+-- name: CountOutstandingPurchaseOrderItems :one
+SELECT COUNT(*) FROM purchase_order_items
+WHERE purchase_order_id = $1 AND qty_received < qty_ordered;
@@ -0,0 +1,207 @@
+-- name: CreateReportExport :one
+INSERT INTO report_exports (
+    tenant_id, requested_by, requester_role, report_type, format,
+    start_date, end_date, restaurant_ids, email_on_completion
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetReportExport :one
+SELECT * FROM report_exports WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListReportExports :many
+-- requested_by is NULL for tenant owners and admins, who see every export.
+SELECT * FROM report_exports
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(requested_by)::uuid IS NULL OR requested_by = sqlc.narg(requested_by)::uuid)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ClaimReportExport :one
+-- Takes the oldest queued export; SKIP LOCKED lets several workers share the queue.
+UPDATE report_exports SET
+    status = 'running',
+    attempts = attempts + 1,
+    started_at = NOW(),
+    error = NULL
+WHERE id = (
+    SELECT id FROM report_exports
+    WHERE status = 'queued'
+    ORDER BY created_at
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteReportExport :one
+UPDATE report_exports SET
+    status = 'completed',
+    file_key = sqlc.arg(file_key),
+    file_name = sqlc.arg(file_name),
+    content_type = sqlc.arg(content_type),
+    row_count = sqlc.arg(row_count),
+    size_bytes = sqlc.arg(size_bytes),
+    completed_at = NOW(),
+    expires_at = sqlc.arg(expires_at)::timestamptz
+WHERE id = sqlc.arg(id) AND status = 'running'
+RETURNING *;
+
+-- name: FailReportExport :exec
+-- Failed attempts go back to the queue until max_attempts is reached.
+UPDATE report_exports SET
+    status = CASE WHEN attempts >= sqlc.arg(max_attempts)::INT THEN 'failed' ELSE 'queued' END,
+    error = sqlc.arg(error)
+WHERE id = sqlc.arg(id) AND status = 'running';
+
+-- name: RequeueStaleReportExports :execrows
+-- Exports left running by a worker that died.
+UPDATE report_exports SET status = 'queued'
+WHERE status = 'running' AND started_at < sqlc.arg(before)::timestamptz;
+
+-- name: MarkReportExportEmailed :exec
+UPDATE report_exports SET emailed_at = NOW() WHERE id = $1;
+
+-- name: ListExpiredReportExports :many
+SELECT * FROM report_exports
+WHERE status = 'completed' AND expires_at < sqlc.arg(now)::timestamptz
+ORDER BY expires_at
+LIMIT sqlc.arg('limit');
+
+-- name: MarkReportExportExpired :exec
+UPDATE report_exports SET status = 'expired', file_key = NULL WHERE id = $1;
+
+-- name: ListStaffRestaurantIDs :many
+SELECT restaurant_id FROM restaurant_staff_assignments
+WHERE user_id = $1 AND tenant_id = $2
+ORDER BY restaurant_id;
+
+-- ---- Report data ----
+
+-- name: ExportRestaurantSalesByDay :many
+-- Sales of the given restaurants only, from their share of each order.
+SELECT
+    (o.created_at AT TIME ZONE 'Asia/Dhaka')::date AS order_date,
+    COUNT(DISTINCT o.id)::INT AS order_count,
+    COALESCE(SUM(p.items_subtotal), 0)::NUMERIC(14,2) AS gross_sales,
+    COALESCE(SUM(p.items_discount), 0)::NUMERIC(14,2) AS item_discounts,
+    COALESCE(SUM(p.items_vat), 0)::NUMERIC(14,2) AS vat_total,
+    COALESCE(SUM(p.items_total), 0)::NUMERIC(14,2) AS total_sales,
+    COALESCE(SUM(p.commission_amount), 0)::NUMERIC(14,2) AS commission
+FROM order_pickups p
+JOIN orders o ON o.id = p.order_id
+WHERE o.tenant_id = sqlc.arg(tenant_id)
+  AND p.restaurant_id = ANY(sqlc.arg(restaurant_ids)::uuid[])
+  AND o.status = 'delivered'
+  AND p.status <> 'rejected'
+  AND o.deleted_at IS NULL
+  AND (o.created_at AT TIME ZONE 'Asia/Dhaka')::date >= sqlc.arg(start_date)::date
+  AND (o.created_at AT TIME ZONE 'Asia/Dhaka')::date <= sqlc.arg(end_date)::date
+GROUP BY 1
+ORDER BY 1;
+
+-- name: ExportOrders :many
+-- Keyset-paginated on (created_at, id) so large ranges stream in batches.
+SELECT
+    o.id,
+    o.order_number,
+    o.created_at,
+    o.status,
+    o.payment_method,
+    o.payment_status,
+    o.platform,
+    o.delivery_area,
+    o.subtotal,
+    o.item_discount_total,
+    o.promo_discount_total,
+    o.vat_total,
+    o.delivery_charge,
+    o.service_fee,
+    o.total_amount,
+    o.promo_code,
+    o.delivered_at,
+    o.cancelled_at,
+    COALESCE(u.name, '')::TEXT AS customer_name,
+    COALESCE(rn.names, '')::TEXT AS restaurant_names
+FROM orders o
+LEFT JOIN users u ON u.id = o.customer_id
+LEFT JOIN LATERAL (
+    SELECT string_agg(r.name, ', ' ORDER BY r.name) AS names
+    FROM order_pickups p JOIN restaurants r ON r.id = p.restaurant_id
+    WHERE p.order_id = o.id
+) rn ON true
+WHERE o.tenant_id = sqlc.arg(tenant_id)
+  AND o.deleted_at IS NULL
+  AND o.created_at >= sqlc.arg(from_time)::timestamptz
+  AND o.created_at < sqlc.arg(to_time)::timestamptz
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups sp
+        WHERE sp.order_id = o.id AND sp.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[])))
+  AND (o.created_at, o.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::uuid)
+ORDER BY o.created_at, o.id
+LIMIT sqlc.arg('limit');
+
+-- name: ExportRiderAnalytics :many
+SELECT
+    oa.rider_id,
+    COALESCE(u.name, '')::TEXT AS rider_name,
+    u.phone AS rider_phone,
+    COUNT(*)::INT AS total_orders,
+    COUNT(CASE WHEN oa.final_status = 'delivered' THEN 1 END)::INT AS delivered_orders,
+    COALESCE(AVG(oa.total_fulfillment_s), 0)::INT AS avg_delivery_time_s,
+    COALESCE(AVG(oa.pickup_to_delivery_s), 0)::INT AS avg_pickup_to_delivery_s,
+    COALESCE(SUM(oa.delivery_charge), 0)::NUMERIC(14,2) AS total_delivery_revenue
+FROM order_analytics oa
+LEFT JOIN riders r ON r.id = oa.rider_id
+LEFT JOIN users u ON u.id = r.user_id
+WHERE oa.tenant_id = sqlc.arg(tenant_id)
+  AND oa.rider_id IS NOT NULL
+  AND oa.order_date >= sqlc.arg(start_date)::date
+  AND oa.order_date <= sqlc.arg(end_date)::date
+GROUP BY oa.rider_id, u.name, u.phone
+ORDER BY total_orders DESC;
+
+-- name: ExportInvoices :many
+-- Invoices whose period overlaps the range.
+SELECT
+    i.invoice_number,
+    r.name AS restaurant_name,
+    i.period_start,
+    i.period_end,
+    i.status,
+    i.total_orders,
+    i.delivered_orders,
+    i.gross_sales,
+    i.item_discounts,
+    i.vendor_promo_discounts,
+    i.net_sales,
+    i.vat_collected,
+    i.commission_rate,
+    i.commission_amount,
+    i.penalty_amount,
+    i.adjustment_amount,
+    i.net_payable,
+    i.finalized_at,
+    i.paid_at
+FROM invoices i
+JOIN restaurants r ON r.id = i.restaurant_id
+WHERE i.tenant_id = sqlc.arg(tenant_id)
+  AND i.period_start <= sqlc.arg(end_date)::date
+  AND i.period_end >= sqlc.arg(start_date)::date
+ORDER BY i.period_start, r.name;
+
+-- name: ExportRefunds :many
+SELECT
+    o.order_number,
+    rf.amount,
+    rf.reason,
+    rf.status,
+    o.payment_method,
+    rf.gateway_refund_id,
+    rf.created_at,
+    rf.approved_at,
+    rf.processed_at
+FROM refunds rf
+JOIN orders o ON o.id = rf.order_id
+WHERE rf.tenant_id = sqlc.arg(tenant_id)
+  AND rf.created_at >= sqlc.arg(from_time)::timestamptz
+  AND rf.created_at < sqlc.arg(to_time)::timestamptz
+ORDER BY rf.created_at;
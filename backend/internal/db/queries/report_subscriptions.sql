@@ -0,0 +1,146 @@
+-- name: CreateReportSubscription :one
+INSERT INTO report_subscriptions (
+    tenant_id, created_by, report_type, frequency, send_hour, send_weekday,
+    recipients, restaurant_ids, next_run_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetReportSubscription :one
+SELECT * FROM report_subscriptions WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListReportSubscriptions :many
+-- created_by is NULL for tenant owners and admins, who see every subscription.
+SELECT * FROM report_subscriptions
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(created_by)::uuid IS NULL OR created_by = sqlc.narg(created_by)::uuid)
+ORDER BY created_at DESC;
+
+-- name: UpdateReportSubscription :one
+UPDATE report_subscriptions SET
+    report_type = sqlc.arg(report_type),
+    frequency = sqlc.arg(frequency),
+    send_hour = sqlc.arg(send_hour),
+    send_weekday = sqlc.arg(send_weekday),
+    recipients = sqlc.arg(recipients),
+    restaurant_ids = sqlc.arg(restaurant_ids),
+    is_active = sqlc.arg(is_active),
+    next_run_at = sqlc.arg(next_run_at)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: DeleteReportSubscription :execrows
+DELETE FROM report_subscriptions WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListDueReportSubscriptions :many
+-- Subscriptions whose scheduled time has passed, with the tenant's
+-- timezone so the next run can be computed.
+SELECT
+    s.id,
+    s.tenant_id,
+    s.report_type,
+    s.frequency,
+    s.send_hour,
+    s.send_weekday,
+    s.recipients,
+    s.restaurant_ids,
+    s.next_run_at,
+    t.name AS tenant_name,
+    t.timezone AS tenant_timezone,
+    t.currency AS tenant_currency
+FROM report_subscriptions s
+JOIN tenants t ON t.id = s.tenant_id
+WHERE s.is_active
+  AND s.next_run_at <= sqlc.arg(now)::timestamptz
+  AND t.status = 'active'
+ORDER BY s.next_run_at
+LIMIT sqlc.arg('limit');
+
+-- name: AdvanceReportSubscription :execrows
+-- Moves a due subscription to its next run. Matching on the old next_run_at
+-- means only one worker wins a given run.
+UPDATE report_subscriptions SET
+    next_run_at = sqlc.arg(next_run_at)::timestamptz,
+    last_run_at = NOW()
+WHERE id = sqlc.arg(id) AND next_run_at = sqlc.arg(due_at)::timestamptz;
+
+-- name: CreateReportDelivery :exec
+INSERT INTO report_deliveries (
+    subscription_id, tenant_id, report_type, period_start, period_end,
+    recipient, status, error
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (subscription_id, period_end, recipient) DO NOTHING;
+
+-- name: ListReportDeliveries :many
+SELECT * FROM report_deliveries
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND subscription_id = sqlc.arg(subscription_id)
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- ---- Report data ----
+
+-- name: GetScheduledSalesSummary :one
+-- Orders placed in the window, from each restaurant's share of the order so
+-- that restaurant_ids can narrow it. Money columns count delivered orders.
+SELECT
+    COUNT(DISTINCT o.id)::INT AS order_count,
+    COUNT(DISTINCT o.id) FILTER (WHERE o.status = 'delivered')::INT AS delivered_orders,
+    COUNT(DISTINCT o.id) FILTER (WHERE o.status IN ('cancelled', 'rejected'))::INT AS cancelled_orders,
+    COALESCE(SUM(p.items_subtotal) FILTER (WHERE o.status = 'delivered' AND p.status <> 'rejected'), 0)::NUMERIC(14,2) AS gross_sales,
+    COALESCE(SUM(p.items_discount) FILTER (WHERE o.status = 'delivered' AND p.status <> 'rejected'), 0)::NUMERIC(14,2) AS item_discounts,
+    COALESCE(SUM(p.items_vat) FILTER (WHERE o.status = 'delivered' AND p.status <> 'rejected'), 0)::NUMERIC(14,2) AS vat_total,
+    COALESCE(SUM(p.items_total) FILTER (WHERE o.status = 'delivered' AND p.status <> 'rejected'), 0)::NUMERIC(14,2) AS total_sales,
+    COALESCE(SUM(p.commission_amount) FILTER (WHERE o.status = 'delivered' AND p.status <> 'rejected'), 0)::NUMERIC(14,2) AS commission
+FROM orders o
+JOIN order_pickups p ON p.order_id = o.id
+WHERE o.tenant_id = sqlc.arg(tenant_id)
+  AND o.deleted_at IS NULL
+  AND o.created_at >= sqlc.arg(from_time)::timestamptz
+  AND o.created_at < sqlc.arg(to_time)::timestamptz
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR p.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[]));
+
+-- name: GetScheduledTopProducts :many
+SELECT
+    oi.product_name,
+    SUM(oi.quantity)::INT AS total_quantity,
+    SUM(oi.item_total)::NUMERIC(14,2) AS total_revenue
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.tenant_id = sqlc.arg(tenant_id)
+  AND o.status = 'delivered'
+  AND o.deleted_at IS NULL
+  AND o.created_at >= sqlc.arg(from_time)::timestamptz
+  AND o.created_at < sqlc.arg(to_time)::timestamptz
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR oi.restaurant_id = ANY(sqlc.narg(restaurant_ids)::uuid[]))
+GROUP BY oi.product_id, oi.product_name
+ORDER BY total_quantity DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetScheduledOrderCharges :one
+-- Order-level amounts that belong to the tenant rather than a restaurant.
+SELECT
+    COALESCE(SUM(promo_discount_total), 0)::NUMERIC(14,2) AS promo_discounts,
+    COALESCE(SUM(delivery_charge), 0)::NUMERIC(14,2) AS delivery_charges,
+    COALESCE(SUM(service_fee), 0)::NUMERIC(14,2) AS service_fees,
+    COALESCE(SUM(total_amount), 0)::NUMERIC(14,2) AS total_collected
+FROM orders
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND status = 'delivered'
+  AND deleted_at IS NULL
+  AND created_at >= sqlc.arg(from_time)::timestamptz
+  AND created_at < sqlc.arg(to_time)::timestamptz;
+
+-- name: GetProcessedRefundTotal :one
+SELECT COALESCE(SUM(amount), 0)::NUMERIC(14,2) AS total
+FROM refunds
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND status = 'processed'
+  AND processed_at >= sqlc.arg(from_time)::timestamptz
+  AND processed_at < sqlc.arg(to_time)::timestamptz;
+
+-- name: GetRiderEarningTotal :one
+SELECT COALESCE(SUM(total_earning), 0)::NUMERIC(14,2) AS total
+FROM rider_earnings
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND created_at >= sqlc.arg(from_time)::timestamptz
+  AND created_at < sqlc.arg(to_time)::timestamptz;
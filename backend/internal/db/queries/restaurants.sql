@@ -10,10 +10,17 @@ SELECT * FROM restaurants WHERE id = $1 AND tenant_id = $2 LIMIT 1;
 SELECT * FROM restaurants WHERE tenant_id = $1 AND slug = $2 LIMIT 1;
 
 -- name: ListRestaurantsByTenant :many
-SELECT * FROM restaurants WHERE tenant_id = $1 AND is_active = true ORDER BY sort_order, name LIMIT $2 OFFSET $3;
+-- restaurant_ids limits the list to a staff member's assigned restaurants; NULL lists all.
+SELECT * FROM restaurants
+WHERE tenant_id = sqlc.arg(tenant_id) AND is_active = true
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR id = ANY(sqlc.narg(restaurant_ids)::uuid[]))
+ORDER BY sort_order, name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountRestaurantsByTenant :one
-SELECT COUNT(*) FROM restaurants WHERE tenant_id = $1 AND is_active = true;
+SELECT COUNT(*) FROM restaurants
+WHERE tenant_id = sqlc.arg(tenant_id) AND is_active = true
+  AND (sqlc.narg(restaurant_ids)::uuid[] IS NULL OR id = ANY(sqlc.narg(restaurant_ids)::uuid[]));
 
 -- name: ListAvailableByHubAndArea :many
 SELECT r.* FROM restaurants r
@@ -40,7 +47,8 @@ UPDATE restaurants SET
   avg_prep_time_minutes = COALESCE(sqlc.narg(avg_prep_time_minutes), avg_prep_time_minutes),
   auto_accept_orders = COALESCE(sqlc.narg(auto_accept_orders), auto_accept_orders),
   is_featured = COALESCE(sqlc.narg(is_featured), is_featured),
-  sort_order = COALESCE(sqlc.narg(sort_order), sort_order)
+  sort_order = COALESCE(sqlc.narg(sort_order), sort_order),
+  require_pod = COALESCE(sqlc.narg(require_pod), require_pod)
 WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
 RETURNING *;
 
@@ -64,3 +72,6 @@ SELECT * FROM restaurant_operating_hours WHERE restaurant_id = $1 ORDER BY day_o
 
 -- name: DeleteOperatingHours :exec
 DELETE FROM restaurant_operating_hours WHERE restaurant_id = $1;
+
+-- name: ListRestaurantStaffUserIDs :many
+SELECT user_id FROM restaurant_staff_assignments WHERE restaurant_id = $1 AND tenant_id = $2;
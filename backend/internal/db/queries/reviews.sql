@@ -41,3 +41,17 @@ WHERE restaurant_id = $1 AND is_published = true;
 -- name: UpdateRestaurantRating :exec
 UPDATE restaurants SET rating_avg = $2, rating_count = $3
 WHERE id = $1;
+
+-- Rider ratings count whether or not the review is published: moderation is
+-- about the public comment, not the score.
+-- name: GetRiderAvgRating :one
+SELECT
+    COALESCE(AVG(rv.rider_rating), 5)::NUMERIC(3,2) AS avg_rating,
+    COUNT(rv.rider_rating)::INT AS rating_count
+FROM reviews rv
+JOIN orders o ON o.id = rv.order_id
+WHERE o.rider_id = $1 AND rv.rider_rating IS NOT NULL;
+
+-- name: UpdateRiderRating :exec
+UPDATE riders SET rating_avg = $2, rating_count = $3
+WHERE id = $1;
@@ -34,3 +34,11 @@ SELECT * FROM rider_attendance
 WHERE tenant_id = $1 AND work_date = sqlc.arg(work_date)::date
 ORDER BY checked_in_at DESC
 LIMIT $2 OFFSET $3;
+
+-- Attendance records overlapping a time window, oldest first.
+-- name: ListAttendanceInRange :many
+SELECT * FROM rider_attendance
+WHERE rider_id = sqlc.arg(rider_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND checked_in_at <= sqlc.arg(to_time)::timestamptz
+  AND (checked_out_at IS NULL OR checked_out_at >= sqlc.arg(from_time)::timestamptz)
+ORDER BY checked_in_at;
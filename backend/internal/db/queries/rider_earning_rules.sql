@@ -0,0 +1,86 @@
+-- ============================================================
+-- Rider Earning Rules SQLC Queries
+-- ============================================================
+
+-- name: CreateEarningRule :one
+INSERT INTO rider_earning_rules (
+    tenant_id, hub_id, vehicle_type, name, base_pay, per_km_rate,
+    included_km, max_distance_pay, extra_pickup_bonus, is_active, created_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetEarningRule :one
+SELECT * FROM rider_earning_rules WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListEarningRules :many
+SELECT * FROM rider_earning_rules
+WHERE tenant_id = $1
+ORDER BY hub_id NULLS FIRST, vehicle_type NULLS FIRST, name;
+
+-- name: UpdateEarningRule :one
+UPDATE rider_earning_rules SET
+    name = COALESCE(sqlc.narg(name), name),
+    base_pay = COALESCE(sqlc.narg(base_pay), base_pay),
+    per_km_rate = COALESCE(sqlc.narg(per_km_rate), per_km_rate),
+    included_km = COALESCE(sqlc.narg(included_km), included_km),
+    max_distance_pay = COALESCE(sqlc.narg(max_distance_pay), max_distance_pay),
+    extra_pickup_bonus = COALESCE(sqlc.narg(extra_pickup_bonus), extra_pickup_bonus),
+    is_active = COALESCE(sqlc.narg(is_active), is_active)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: DeleteEarningRule :exec
+DELETE FROM rider_earning_rules WHERE id = $1 AND tenant_id = $2;
+
+-- Picks the most specific active rule for a rider: hub and vehicle, hub
+-- only, vehicle only, then tenant-wide.
+-- name: GetApplicableEarningRule :one
+SELECT * FROM rider_earning_rules
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND is_active = true
+  AND (hub_id IS NULL OR hub_id = sqlc.narg(hub_id))
+  AND (vehicle_type IS NULL OR vehicle_type = sqlc.arg(vehicle_type))
+ORDER BY hub_id IS NULL, vehicle_type IS NULL
+LIMIT 1;
+
+-- name: CreateEarningPeakWindow :one
+INSERT INTO rider_earning_peak_windows (rule_id, day_of_week, start_minute, end_minute, bonus_amount)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListEarningPeakWindows :many
+SELECT * FROM rider_earning_peak_windows
+WHERE rule_id = $1
+ORDER BY day_of_week NULLS FIRST, start_minute;
+
+-- name: RemoveEarningPeakWindows :exec
+DELETE FROM rider_earning_peak_windows WHERE rule_id = $1;
+
+-- name: CreateEarningSurge :one
+INSERT INTO rider_earning_surges (tenant_id, hub_id, kind, multiplier, reason, starts_at, ends_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListEarningSurges :many
+SELECT * FROM rider_earning_surges
+WHERE tenant_id = $1
+ORDER BY starts_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListActiveEarningSurges :many
+SELECT * FROM rider_earning_surges
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (hub_id IS NULL OR hub_id = sqlc.narg(hub_id))
+  AND starts_at <= sqlc.arg(at)
+  AND (ends_at IS NULL OR ends_at > sqlc.arg(at));
+
+-- name: EndEarningSurge :one
+UPDATE rider_earning_surges SET ends_at = NOW()
+WHERE id = $1 AND tenant_id = $2
+  AND starts_at < NOW()
+  AND (ends_at IS NULL OR ends_at > NOW())
+RETURNING *;
+
+-- name: DeleteScheduledEarningSurge :execrows
+DELETE FROM rider_earning_surges
+WHERE id = $1 AND tenant_id = $2 AND starts_at > NOW();
@@ -1,6 +1,8 @@
 -- name: CreateRiderEarning :one
-INSERT INTO rider_earnings (rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO rider_earnings (
+    rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning,
+    rule_id, vehicle_type, distance_km, pickup_count, multi_pickup_bonus, surge_multiplier, surge_bonus, breakdown
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 RETURNING *;
 
 -- name: ListEarningsByRider :many
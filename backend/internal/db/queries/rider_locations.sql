@@ -7,6 +7,8 @@ ON CONFLICT (rider_id) DO UPDATE SET
   heading = EXCLUDED.heading,
   speed_kmh = EXCLUDED.speed_kmh,
   accuracy_meters = EXCLUDED.accuracy_meters,
+  is_stale = false,
+  stale_since = NULL,
   updated_at = NOW()
 RETURNING *;
 
@@ -17,8 +19,8 @@ SELECT * FROM rider_locations WHERE rider_id = $1 LIMIT 1;
 SELECT * FROM rider_locations WHERE tenant_id = $1 ORDER BY updated_at DESC;
 
 -- name: AppendLocationHistory :one
-INSERT INTO rider_location_history (rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO rider_location_history (rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
 -- name: ListLocationHistoryByRider :many
@@ -26,3 +28,38 @@ SELECT * FROM rider_location_history
 WHERE rider_id = $1 AND created_at >= sqlc.arg(since)::timestamptz
 ORDER BY created_at DESC
 LIMIT $2;
+
+-- name: ListLocationHistoryBetween :many
+SELECT * FROM rider_location_history
+WHERE rider_id = sqlc.arg(rider_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND created_at >= sqlc.arg(from_time) AND created_at <= sqlc.arg(to_time)
+ORDER BY created_at
+LIMIT sqlc.arg('limit');
+
+-- name: SumLocationDistance :one
+SELECT COALESCE(SUM(distance_from_prev_km), 0)::numeric AS distance_km
+FROM rider_location_history
+WHERE rider_id = sqlc.arg(rider_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND created_at > sqlc.arg(from_time) AND created_at <= sqlc.arg(to_time);
+
+-- On-duty riders whose last location is older than the cutoff.
+-- name: MarkStaleRiderLocations :many
+UPDATE rider_locations SET is_stale = true, stale_since = NOW()
+WHERE is_stale = false AND updated_at < $1
+  AND rider_id IN (SELECT id FROM riders WHERE is_on_duty = true)
+RETURNING rider_id, tenant_id, updated_at;
+
+-- name: DeleteLocationHistoryBefore :execrows
+DELETE FROM rider_location_history
+WHERE id IN (SELECT id FROM rider_location_history WHERE created_at < $1 LIMIT $2);
+
+-- Restaurants a rider still has to collect from, for live ETAs.
+-- name: ListRiderPendingPickups :many
+SELECT op.order_id, op.restaurant_id, r.geo_lat, r.geo_lng
+FROM order_pickups op
+JOIN orders o ON o.id = op.order_id
+JOIN restaurants r ON r.id = op.restaurant_id
+WHERE o.rider_id = $1 AND o.tenant_id = $2
+  AND o.status IN ('confirmed', 'preparing', 'ready')
+  AND o.deleted_at IS NULL
+  AND op.status NOT IN ('picked', 'rejected');
@@ -0,0 +1,128 @@
+-- ============================================================
+-- Rider applications, documents and verification
+-- ============================================================
+
+-- name: CreateRiderApplication :one
+INSERT INTO rider_applications (
+  tenant_id, user_id, rider_id, hub_id, vehicle_type,
+  vehicle_registration, license_number, nid_number
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetRiderApplication :one
+SELECT * FROM rider_applications WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: GetLatestRiderApplication :one
+SELECT * FROM rider_applications
+WHERE user_id = $1 AND tenant_id = $2
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListRiderApplications :many
+SELECT a.*, u.name AS applicant_name, u.phone AS applicant_phone
+FROM rider_applications a
+JOIN users u ON u.id = a.user_id
+WHERE a.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR a.hub_id = sqlc.narg(hub_id))
+  AND a.status = sqlc.arg(status)
+ORDER BY a.created_at
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ReviewRiderApplication :one
+UPDATE rider_applications SET
+  status = sqlc.arg(status),
+  rejection_reason = sqlc.narg(rejection_reason),
+  rider_id = COALESCE(sqlc.narg(rider_id), rider_id),
+  reviewed_by = sqlc.arg(reviewed_by),
+  reviewed_at = NOW()
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'submitted'
+RETURNING *;
+
+-- name: CreateRiderDocument :one
+INSERT INTO rider_documents (
+  tenant_id, user_id, rider_id, application_id, doc_type,
+  file_url, document_number, expires_on
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetRiderDocument :one
+SELECT * FROM rider_documents WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: ListApplicationDocuments :many
+SELECT * FROM rider_documents WHERE application_id = $1 ORDER BY doc_type;
+
+-- name: ListUserRiderDocuments :many
+SELECT * FROM rider_documents
+WHERE user_id = $1 AND tenant_id = $2
+ORDER BY created_at DESC;
+
+-- Renewals uploaded by riders outside an application.
+-- name: ListRiderDocumentRenewals :many
+SELECT d.*, u.name AS rider_name, u.phone AS rider_phone, r.hub_id
+FROM rider_documents d
+JOIN riders r ON r.id = d.rider_id
+JOIN users u ON u.id = d.user_id
+WHERE d.tenant_id = sqlc.arg(tenant_id)
+  AND d.application_id IS NULL
+  AND d.status = sqlc.arg(status)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR r.hub_id = sqlc.narg(hub_id))
+ORDER BY d.created_at
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ReviewApplicationDocuments :exec
+UPDATE rider_documents SET
+  status = sqlc.arg(status),
+  rider_id = COALESCE(sqlc.narg(rider_id), rider_id),
+  reviewed_by = sqlc.arg(reviewed_by),
+  reviewed_at = NOW()
+WHERE application_id = sqlc.arg(application_id) AND status = 'pending';
+
+-- name: ReviewRiderDocument :one
+UPDATE rider_documents SET
+  status = sqlc.arg(status),
+  review_note = sqlc.narg(review_note),
+  reviewed_by = sqlc.arg(reviewed_by),
+  reviewed_at = NOW()
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'pending'
+RETURNING *;
+
+-- name: SupersedeRiderDocuments :exec
+UPDATE rider_documents SET status = 'superseded'
+WHERE rider_id = sqlc.arg(rider_id)
+  AND doc_type = sqlc.arg(doc_type)
+  AND id <> sqlc.arg(keep_id)
+  AND status IN ('approved', 'expired');
+
+-- name: ListValidRiderDocumentTypes :many
+SELECT DISTINCT doc_type FROM rider_documents
+WHERE rider_id = sqlc.arg(rider_id)
+  AND status = 'approved'
+  AND (expires_on IS NULL OR expires_on >= sqlc.arg(today)::date);
+
+-- name: ListExpiringRiderDocuments :many
+SELECT * FROM rider_documents
+WHERE status = 'approved'
+  AND rider_id IS NOT NULL
+  AND expires_on IS NOT NULL
+  AND expires_on <= sqlc.arg(until)::date
+ORDER BY expires_on
+LIMIT sqlc.arg('limit');
+
+-- name: MarkRiderDocumentReminded :exec
+UPDATE rider_documents SET reminded_days = $2 WHERE id = $1;
+
+-- name: ExpireRiderDocument :execrows
+UPDATE rider_documents SET status = 'expired' WHERE id = $1 AND status = 'approved';
+
+-- name: VerifyRider :one
+UPDATE riders SET
+  verification_status = 'verified',
+  nid_verified = true,
+  verified_at = NOW(),
+  verified_by = $3
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: ExpireRiderVerification :execrows
+UPDATE riders SET verification_status = 'expired'
+WHERE id = $1 AND verification_status = 'verified';
@@ -0,0 +1,136 @@
+-- ============================================================
+-- Rider Payouts SQLC Queries
+-- ============================================================
+
+-- name: CreateRiderPayoutBatch :one
+INSERT INTO rider_payout_batches (tenant_id, kind, period_from, period_to, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetRiderPayoutBatch :one
+SELECT * FROM rider_payout_batches WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: GetWeeklyRiderPayoutBatch :one
+SELECT * FROM rider_payout_batches
+WHERE tenant_id = $1 AND kind = 'weekly' AND period_from = $2
+LIMIT 1;
+
+-- name: ListRiderPayoutBatches :many
+SELECT * FROM rider_payout_batches
+WHERE tenant_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountRiderPayoutBatches :one
+SELECT COUNT(*) FROM rider_payout_batches WHERE tenant_id = $1;
+
+-- name: UpdateRiderPayoutBatchTotals :one
+UPDATE rider_payout_batches SET
+  period_from = sqlc.arg(period_from),
+  payout_count = sqlc.arg(payout_count),
+  total_amount = sqlc.arg(total_amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListRidersWithUnsettledEarnings :many
+SELECT DISTINCT rider_id FROM rider_earnings
+WHERE tenant_id = $1 AND is_paid_out = false AND payout_id IS NULL AND created_at < sqlc.arg(before);
+
+-- name: CreateRiderPayout :one
+INSERT INTO rider_payouts (rider_id, tenant_id, batch_id, amount, earnings_from, earnings_to, payment_method, payout_account)
+VALUES ($1, $2, $3, 0, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: DeleteRiderPayout :exec
+DELETE FROM rider_payouts WHERE id = $1;
+
+-- name: AttachEarningsToPayout :many
+UPDATE rider_earnings SET payout_id = sqlc.arg(payout_id)
+WHERE rider_id = sqlc.arg(rider_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND is_paid_out = false AND payout_id IS NULL AND created_at < sqlc.arg(before)
+RETURNING total_earning, created_at;
+
+-- Penalties under appeal are held back until a partner reviews them.
+-- name: ListUnsettledPenaltiesForRider :many
+SELECT * FROM rider_penalties
+WHERE rider_id = $1 AND tenant_id = $2 AND status = 'pending' AND payout_id IS NULL
+ORDER BY created_at
+FOR UPDATE;
+
+-- name: AttachPenaltyToPayout :exec
+UPDATE rider_penalties SET payout_id = $2 WHERE id = $1;
+
+-- name: SetRiderPayoutBreakdown :one
+UPDATE rider_payouts SET
+  amount = sqlc.arg(amount),
+  gross_earnings = sqlc.arg(gross_earnings),
+  penalty_deductions = sqlc.arg(penalty_deductions),
+  cod_offset = sqlc.arg(cod_offset),
+  earnings_from = sqlc.arg(earnings_from),
+  earnings_to = sqlc.arg(earnings_to)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetRiderPayout :one
+SELECT * FROM rider_payouts WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: GetRiderPayoutForUpdate :one
+SELECT * FROM rider_payouts WHERE id = $1 AND tenant_id = $2 LIMIT 1 FOR UPDATE;
+
+-- name: ListRiderPayoutsByRider :many
+SELECT * FROM rider_payouts
+WHERE rider_id = $1 AND tenant_id = $2
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: CountRiderPayoutsByRider :one
+SELECT COUNT(*) FROM rider_payouts WHERE rider_id = $1 AND tenant_id = $2;
+
+-- name: ListRiderPayoutsByBatch :many
+SELECT p.id, p.rider_id, p.amount, p.gross_earnings, p.penalty_deductions, p.cod_offset,
+  p.payment_method, p.payout_account, p.payment_reference, p.status,
+  u.name AS rider_name, u.phone AS rider_phone
+FROM rider_payouts p
+JOIN riders r ON r.id = p.rider_id
+JOIN users u ON u.id = r.user_id
+WHERE p.batch_id = $1 AND p.tenant_id = $2
+ORDER BY u.name;
+
+-- name: MarkBatchPayoutsProcessing :exec
+UPDATE rider_payouts SET status = 'processing'
+WHERE batch_id = $1 AND tenant_id = $2 AND status = 'pending' AND amount > 0;
+
+-- name: CompleteRiderPayout :one
+UPDATE rider_payouts SET
+  status = 'completed',
+  payment_reference = sqlc.narg(payment_reference),
+  processed_by = sqlc.narg(processed_by),
+  processed_at = NOW(),
+  note = COALESCE(sqlc.narg(note), note)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: FailRiderPayout :one
+UPDATE rider_payouts SET
+  status = 'failed',
+  processed_by = sqlc.narg(processed_by),
+  processed_at = NOW(),
+  note = sqlc.narg(note)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: MarkPayoutEarningsPaid :exec
+UPDATE rider_earnings SET is_paid_out = true WHERE payout_id = $1;
+
+-- name: DetachPayoutEarnings :exec
+UPDATE rider_earnings SET payout_id = NULL WHERE payout_id = $1 AND is_paid_out = false;
+
+-- name: DetachPayoutPenalties :exec
+UPDATE rider_penalties SET payout_id = NULL WHERE payout_id = $1;
+
+-- name: ClearPayoutPenalties :exec
+UPDATE rider_penalties SET
+  status = 'cleared',
+  cleared_at = NOW(),
+  cleared_by = sqlc.narg(cleared_by)
+WHERE payout_id = sqlc.arg(payout_id) AND status = 'pending';
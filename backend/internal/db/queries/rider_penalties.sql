@@ -20,10 +20,44 @@ UPDATE rider_penalties SET
 WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
 RETURNING *;
 
+-- Only a pending penalty not yet taken by a payout can be appealed, once.
 -- name: AppealPenalty :one
 UPDATE rider_penalties SET
   status = 'appealed',
-  appeal_note = $3,
+  appeal_note = sqlc.arg(appeal_note),
+  appeal_evidence_urls = sqlc.arg(appeal_evidence_urls),
   appealed_at = NOW()
-WHERE id = $1 AND tenant_id = $2
+WHERE id = sqlc.arg(id) AND rider_id = sqlc.arg(rider_id)
+  AND status = 'pending' AND payout_id IS NULL AND appealed_at IS NULL
+RETURNING *;
+
+-- name: ListPenaltyAppeals :many
+SELECT p.*, u.name AS rider_name, u.phone AS rider_phone, r.hub_id
+FROM rider_penalties p
+JOIN riders r ON r.id = p.rider_id
+JOIN users u ON u.id = r.user_id
+WHERE p.tenant_id = sqlc.arg(tenant_id) AND p.appealed_at IS NOT NULL
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR r.hub_id = sqlc.narg(hub_id))
+  AND (sqlc.narg(status)::penalty_status IS NULL OR p.status = sqlc.narg(status))
+ORDER BY p.appealed_at
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ReviewPenaltyAppeal :one
+UPDATE rider_penalties SET
+  status = sqlc.arg(status),
+  appeal_outcome = sqlc.arg(appeal_outcome),
+  appeal_review_note = sqlc.narg(appeal_review_note),
+  appeal_reviewed_by = sqlc.arg(reviewed_by),
+  appeal_reviewed_at = NOW(),
+  cleared_at = CASE WHEN sqlc.arg(status) = 'cleared'::penalty_status THEN NOW() ELSE cleared_at END,
+  cleared_by = CASE WHEN sqlc.arg(status) = 'cleared'::penalty_status THEN sqlc.arg(reviewed_by) ELSE cleared_by END
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'appealed'
+RETURNING *;
+
+-- Penalties raised when an issue is resolved against the rider. Resolving
+-- the same issue again leaves the first penalty in place.
+-- name: CreateIssuePenalty :one
+INSERT INTO rider_penalties (rider_id, tenant_id, order_id, issue_id, reason, amount)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (issue_id) WHERE issue_id IS NOT NULL DO NOTHING
 RETURNING *;
@@ -0,0 +1,195 @@
+-- ============================================================
+-- Rider assignment offers, daily scorecards and tiers
+-- ============================================================
+
+-- name: CreateAssignmentOffer :exec
+INSERT INTO rider_assignment_offers (tenant_id, order_id, rider_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (order_id, rider_id) DO NOTHING;
+
+-- A late answer to an expired offer still counts as accepted if the order was
+-- still free.
+-- name: AcceptAssignmentOffer :exec
+UPDATE rider_assignment_offers SET status = 'accepted', responded_at = NOW()
+WHERE order_id = $1 AND rider_id = $2 AND status IN ('offered', 'expired');
+
+-- name: DeclineAssignmentOffer :one
+UPDATE rider_assignment_offers SET status = 'declined', responded_at = NOW()
+WHERE order_id = $1 AND rider_id = $2 AND tenant_id = $3 AND status = 'offered'
+RETURNING *;
+
+-- name: WithdrawAssignmentOffers :exec
+UPDATE rider_assignment_offers SET status = 'withdrawn', responded_at = NOW()
+WHERE order_id = $1 AND status = 'offered';
+
+-- name: ExpireAssignmentOffers :execrows
+UPDATE rider_assignment_offers SET status = 'expired', responded_at = NOW()
+WHERE status = 'offered' AND offered_at < sqlc.arg(before)::timestamptz;
+
+-- name: ListOfferCountsForDay :many
+SELECT
+  rider_id,
+  SUM(CASE WHEN status IN ('accepted', 'declined', 'expired') THEN 1 ELSE 0 END)::INT AS received,
+  SUM(CASE WHEN status = 'accepted' THEN 1 ELSE 0 END)::INT AS accepted,
+  SUM(CASE WHEN status = 'declined' THEN 1 ELSE 0 END)::INT AS declined,
+  SUM(CASE WHEN status = 'expired' THEN 1 ELSE 0 END)::INT AS expired
+FROM rider_assignment_offers
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND offered_at >= sqlc.arg(from_time)::timestamptz
+  AND offered_at < sqlc.arg(to_time)::timestamptz
+GROUP BY rider_id;
+
+-- name: ListPickupTimingsForDay :many
+SELECT
+  o.rider_id::uuid AS rider_id,
+  p.picked_at,
+  p.ready_at,
+  o.confirmed_at,
+  o.created_at,
+  r.avg_prep_time_minutes
+FROM order_pickups p
+JOIN orders o ON o.id = p.order_id
+JOIN restaurants r ON r.id = p.restaurant_id
+WHERE o.tenant_id = sqlc.arg(tenant_id)
+  AND o.rider_id IS NOT NULL
+  AND p.picked_at >= sqlc.arg(from_time)::timestamptz
+  AND p.picked_at < sqlc.arg(to_time)::timestamptz;
+
+-- name: ListDeliveryTimingsForDay :many
+SELECT
+  rider_id::uuid AS rider_id,
+  created_at,
+  estimated_delivery_minutes,
+  delivered_at
+FROM orders
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND rider_id IS NOT NULL
+  AND status = 'delivered'
+  AND delivered_at >= sqlc.arg(from_time)::timestamptz
+  AND delivered_at < sqlc.arg(to_time)::timestamptz;
+
+-- name: ListCancellationCountsForDay :many
+SELECT
+  rider_id::uuid AS rider_id,
+  COUNT(*)::INT AS cancelled,
+  SUM(CASE WHEN cancelled_by = 'rider' THEN 1 ELSE 0 END)::INT AS by_rider
+FROM orders
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND rider_id IS NOT NULL
+  AND status = 'cancelled'
+  AND cancelled_at >= sqlc.arg(from_time)::timestamptz
+  AND cancelled_at < sqlc.arg(to_time)::timestamptz
+GROUP BY rider_id;
+
+-- name: ListRiderRatingCountsForDay :many
+SELECT
+  o.rider_id::uuid AS rider_id,
+  COUNT(*)::INT AS ratings,
+  SUM(rv.rider_rating)::INT AS rating_sum
+FROM reviews rv
+JOIN orders o ON o.id = rv.order_id
+WHERE rv.tenant_id = sqlc.arg(tenant_id)
+  AND o.rider_id IS NOT NULL
+  AND rv.rider_rating IS NOT NULL
+  AND rv.created_at >= sqlc.arg(from_time)::timestamptz
+  AND rv.created_at < sqlc.arg(to_time)::timestamptz
+GROUP BY o.rider_id;
+
+-- Overturned appeals clear the penalty, so cleared penalties don't count.
+-- name: ListPenaltyCountsForDay :many
+SELECT
+  rider_id,
+  COUNT(*)::INT AS penalties,
+  COALESCE(SUM(amount), 0)::NUMERIC(12,2) AS amount
+FROM rider_penalties
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND status <> 'cleared'
+  AND created_at >= sqlc.arg(from_time)::timestamptz
+  AND created_at < sqlc.arg(to_time)::timestamptz
+GROUP BY rider_id;
+
+-- name: ListShiftCountsForDay :many
+SELECT
+  rider_id,
+  COUNT(*)::INT AS rostered,
+  SUM(CASE WHEN status IN ('checked_in', 'completed') THEN 1 ELSE 0 END)::INT AS attended,
+  SUM(CASE WHEN status IN ('checked_in', 'completed') AND late_minutes = 0 THEN 1 ELSE 0 END)::INT AS on_time
+FROM rider_shifts
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND status <> 'cancelled'
+  AND starts_at >= sqlc.arg(from_time)::timestamptz
+  AND starts_at < sqlc.arg(to_time)::timestamptz
+GROUP BY rider_id;
+
+-- name: UpsertRiderScorecard :one
+INSERT INTO rider_scorecards (
+  tenant_id, rider_id, hub_id, scorecard_date,
+  offers_received, offers_accepted, offers_declined, offers_expired,
+  pickups, pickups_on_time, deliveries, deliveries_on_time,
+  cancelled_orders, rider_cancellations, ratings_count, ratings_sum,
+  penalties_count, penalties_amount, shifts_rostered, shifts_attended, shifts_on_time,
+  score
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+  $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+)
+ON CONFLICT (rider_id, scorecard_date) DO UPDATE SET
+  hub_id = EXCLUDED.hub_id,
+  offers_received = EXCLUDED.offers_received,
+  offers_accepted = EXCLUDED.offers_accepted,
+  offers_declined = EXCLUDED.offers_declined,
+  offers_expired = EXCLUDED.offers_expired,
+  pickups = EXCLUDED.pickups,
+  pickups_on_time = EXCLUDED.pickups_on_time,
+  deliveries = EXCLUDED.deliveries,
+  deliveries_on_time = EXCLUDED.deliveries_on_time,
+  cancelled_orders = EXCLUDED.cancelled_orders,
+  rider_cancellations = EXCLUDED.rider_cancellations,
+  ratings_count = EXCLUDED.ratings_count,
+  ratings_sum = EXCLUDED.ratings_sum,
+  penalties_count = EXCLUDED.penalties_count,
+  penalties_amount = EXCLUDED.penalties_amount,
+  shifts_rostered = EXCLUDED.shifts_rostered,
+  shifts_attended = EXCLUDED.shifts_attended,
+  shifts_on_time = EXCLUDED.shifts_on_time,
+  score = EXCLUDED.score
+RETURNING *;
+
+-- name: ListRiderScorecards :many
+SELECT * FROM rider_scorecards
+WHERE rider_id = sqlc.arg(rider_id) AND tenant_id = sqlc.arg(tenant_id)
+  AND scorecard_date >= sqlc.arg(from_date)::date
+  AND scorecard_date <= sqlc.arg(to_date)::date
+ORDER BY scorecard_date;
+
+-- name: ListTenantScorecardsSince :many
+SELECT * FROM rider_scorecards
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND scorecard_date >= sqlc.arg(from_date)::date
+ORDER BY rider_id, scorecard_date;
+
+-- name: ListRiderTiers :many
+SELECT
+  r.id,
+  r.hub_id,
+  u.name AS rider_name,
+  u.phone AS rider_phone,
+  r.tier,
+  r.performance_score,
+  r.rating_avg,
+  r.rating_count,
+  r.tier_updated_at
+FROM riders r
+JOIN users u ON u.id = r.user_id
+WHERE r.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR r.hub_id = sqlc.narg(hub_id))
+  AND (sqlc.narg(tier)::text IS NULL OR r.tier = sqlc.narg(tier))
+ORDER BY r.performance_score DESC NULLS LAST, u.name
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListTenantRiderHubs :many
+SELECT id, hub_id FROM riders WHERE tenant_id = $1;
+
+-- name: UpdateRiderTier :exec
+UPDATE riders SET tier = $3, performance_score = $4, tier_updated_at = NOW()
+WHERE id = $1 AND tenant_id = $2;
@@ -0,0 +1,165 @@
+-- ============================================================
+-- Rider shift templates, rosters and swaps
+-- ============================================================
+
+-- name: CreateShiftTemplate :one
+INSERT INTO rider_shift_templates (
+  tenant_id, hub_id, name, start_minute, end_minute, days_of_week, required_riders,
+  grace_minutes, late_penalty, absence_penalty, is_active, created_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetShiftTemplate :one
+SELECT * FROM rider_shift_templates WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: ListShiftTemplates :many
+SELECT * FROM rider_shift_templates
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR hub_id = sqlc.narg(hub_id))
+ORDER BY hub_id, start_minute, name;
+
+-- name: UpdateShiftTemplate :one
+UPDATE rider_shift_templates SET
+  name = COALESCE(sqlc.narg(name), name),
+  start_minute = COALESCE(sqlc.narg(start_minute), start_minute),
+  end_minute = COALESCE(sqlc.narg(end_minute), end_minute),
+  days_of_week = COALESCE(sqlc.narg(days_of_week), days_of_week),
+  required_riders = COALESCE(sqlc.narg(required_riders), required_riders),
+  grace_minutes = COALESCE(sqlc.narg(grace_minutes), grace_minutes),
+  late_penalty = COALESCE(sqlc.narg(late_penalty), late_penalty),
+  absence_penalty = COALESCE(sqlc.narg(absence_penalty), absence_penalty),
+  is_active = COALESCE(sqlc.narg(is_active), is_active)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+RETURNING *;
+
+-- name: DeleteShiftTemplate :execrows
+DELETE FROM rider_shift_templates WHERE id = $1 AND tenant_id = $2;
+
+-- name: CountActiveShiftTemplates :one
+SELECT COUNT(*) FROM rider_shift_templates
+WHERE hub_id = $1 AND tenant_id = $2 AND is_active = true;
+
+-- name: CreateRiderShift :one
+INSERT INTO rider_shifts (
+  tenant_id, hub_id, template_id, rider_id, shift_date, starts_at, ends_at,
+  grace_minutes, late_penalty, absence_penalty, created_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- Shifts of a rider that overlap the given time range.
+-- name: CountOverlappingRiderShifts :one
+SELECT COUNT(*) FROM rider_shifts
+WHERE rider_id = $1 AND status <> 'cancelled'
+  AND starts_at < sqlc.arg(ends_at) AND ends_at > sqlc.arg(starts_at);
+
+-- name: GetRiderShift :one
+SELECT * FROM rider_shifts WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: GetRiderShiftForUpdate :one
+SELECT * FROM rider_shifts WHERE id = $1 AND tenant_id = $2 LIMIT 1 FOR UPDATE;
+
+-- name: ListRiderShifts :many
+SELECT s.*, u.name AS rider_name, COALESCE(t.name, '')::text AS template_name
+FROM rider_shifts s
+JOIN riders r ON r.id = s.rider_id
+JOIN users u ON u.id = r.user_id
+LEFT JOIN rider_shift_templates t ON t.id = s.template_id
+WHERE s.tenant_id = sqlc.arg(tenant_id)
+  AND s.shift_date >= sqlc.arg(from_date) AND s.shift_date <= sqlc.arg(to_date)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR s.hub_id = sqlc.narg(hub_id))
+  AND (sqlc.narg(rider_id)::uuid IS NULL OR s.rider_id = sqlc.narg(rider_id))
+ORDER BY s.starts_at, u.name;
+
+-- name: ListUpcomingRiderShifts :many
+SELECT * FROM rider_shifts
+WHERE rider_id = $1 AND tenant_id = $2 AND ends_at > $3 AND status IN ('scheduled', 'checked_in')
+ORDER BY starts_at
+LIMIT 50;
+
+-- The scheduled shift a rider may check in for now: it opens a little before
+-- its start and stays open until its end.
+-- name: GetCheckInRiderShift :one
+SELECT * FROM rider_shifts
+WHERE rider_id = sqlc.arg(rider_id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'scheduled'
+  AND starts_at <= sqlc.arg(opens_before) AND ends_at > sqlc.arg(now)
+ORDER BY starts_at
+LIMIT 1;
+
+-- name: MarkRiderShiftCheckedIn :one
+UPDATE rider_shifts SET
+  status = 'checked_in',
+  checked_in_at = $2,
+  late_minutes = $3,
+  attendance_id = $4,
+  penalty_id = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: CompleteCheckedInRiderShifts :exec
+UPDATE rider_shifts SET status = 'completed'
+WHERE rider_id = $1 AND tenant_id = $2 AND status = 'checked_in';
+
+-- name: CancelRiderShift :one
+UPDATE rider_shifts SET status = 'cancelled'
+WHERE id = $1 AND tenant_id = $2 AND status = 'scheduled'
+RETURNING *;
+
+-- name: ReassignRiderShift :one
+UPDATE rider_shifts SET rider_id = $3
+WHERE id = $1 AND tenant_id = $2 AND status = 'scheduled'
+RETURNING *;
+
+-- Scheduled shifts that ended without a check-in, across tenants.
+-- name: ListMissedRiderShifts :many
+SELECT * FROM rider_shifts
+WHERE status = 'scheduled' AND ends_at <= $1
+ORDER BY ends_at
+LIMIT $2;
+
+-- name: MarkRiderShiftAbsent :one
+UPDATE rider_shifts SET status = 'absent'
+WHERE id = $1 AND status = 'scheduled'
+RETURNING *;
+
+-- name: SetRiderShiftPenalty :exec
+UPDATE rider_shifts SET penalty_id = $2 WHERE id = $1;
+
+-- name: CreateShiftSwap :one
+INSERT INTO rider_shift_swaps (tenant_id, shift_id, requester_id, target_rider_id, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetShiftSwapForUpdate :one
+SELECT * FROM rider_shift_swaps WHERE id = $1 AND tenant_id = $2 LIMIT 1 FOR UPDATE;
+
+-- name: ListShiftSwapsForRider :many
+SELECT w.*, s.hub_id, s.starts_at, s.ends_at
+FROM rider_shift_swaps w
+JOIN rider_shifts s ON s.id = w.shift_id
+WHERE w.tenant_id = $2 AND (w.requester_id = $1 OR w.target_rider_id = $1)
+ORDER BY w.created_at DESC
+LIMIT $3 OFFSET $4;
+
+-- name: ListShiftSwaps :many
+SELECT w.*, s.hub_id, s.starts_at, s.ends_at,
+  ru.name AS requester_name, tu.name AS target_rider_name
+FROM rider_shift_swaps w
+JOIN rider_shifts s ON s.id = w.shift_id
+JOIN riders rr ON rr.id = w.requester_id
+JOIN users ru ON ru.id = rr.user_id
+JOIN riders tr ON tr.id = w.target_rider_id
+JOIN users tu ON tu.id = tr.user_id
+WHERE w.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(hub_id)::uuid IS NULL OR s.hub_id = sqlc.narg(hub_id))
+  AND (sqlc.narg(status)::text IS NULL OR w.status = sqlc.narg(status))
+ORDER BY w.created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateShiftSwapStatus :one
+UPDATE rider_shift_swaps SET
+  status = sqlc.arg(status),
+  review_note = COALESCE(sqlc.narg(review_note), review_note),
+  reviewed_by = COALESCE(sqlc.narg(reviewed_by), reviewed_by),
+  reviewed_at = CASE WHEN sqlc.narg(reviewed_by)::uuid IS NULL THEN reviewed_at ELSE NOW() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -15,8 +15,10 @@ SELECT * FROM riders WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $2 OFFS
 -- name: ListRidersByHub :many
 SELECT * FROM riders WHERE hub_id = $1 AND tenant_id = $2 ORDER BY created_at DESC;
 
+-- Riders whose location has gone stale are left out until they report again.
 -- name: ListAvailableRidersByHub :many
-SELECT * FROM riders WHERE hub_id = $1 AND tenant_id = $2 AND is_available = true AND is_on_duty = true;
+SELECT * FROM riders WHERE hub_id = $1 AND tenant_id = $2 AND is_available = true AND is_on_duty = true
+  AND NOT EXISTS (SELECT 1 FROM rider_locations rl WHERE rl.rider_id = riders.id AND rl.is_stale);
 
 -- name: UpdateRider :one
 UPDATE riders SET
@@ -53,3 +55,18 @@ WHERE id = $1 AND tenant_id = $2;
 
 -- name: CountRidersByTenant :one
 SELECT COUNT(*) FROM riders WHERE tenant_id = $1;
+
+-- name: GetRiderForUpdate :one
+SELECT * FROM riders WHERE id = $1 AND tenant_id = $2 LIMIT 1 FOR UPDATE;
+
+-- name: AddRiderCashInHand :exec
+UPDATE riders SET cash_in_hand = cash_in_hand + sqlc.arg(amount)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: DeductRiderCashInHand :exec
+UPDATE riders SET cash_in_hand = cash_in_hand - sqlc.arg(amount)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id);
+
+-- name: DeductRiderPendingBalance :exec
+UPDATE riders SET pending_balance = pending_balance - sqlc.arg(amount)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id);
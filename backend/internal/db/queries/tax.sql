@@ -0,0 +1,117 @@
+-- ============================================================
+-- VAT Compliance SQLC Queries
+-- ============================================================
+
+-- name: GetTaxSettings :one
+SELECT * FROM tax_settings WHERE tenant_id = $1 LIMIT 1;
+
+-- name: UpsertTaxSettings :one
+INSERT INTO tax_settings (
+    tenant_id, bin, registered_name, registered_address,
+    delivery_fee_vat_rate, service_fee_vat_rate, updated_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (tenant_id) DO UPDATE SET
+  bin = EXCLUDED.bin,
+  registered_name = EXCLUDED.registered_name,
+  registered_address = EXCLUDED.registered_address,
+  delivery_fee_vat_rate = EXCLUDED.delivery_fee_vat_rate,
+  service_fee_vat_rate = EXCLUDED.service_fee_vat_rate,
+  updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: GetRestaurantVatRegistration :one
+SELECT * FROM restaurant_vat_registrations WHERE restaurant_id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: UpsertRestaurantVatRegistration :one
+INSERT INTO restaurant_vat_registrations (
+    restaurant_id, tenant_id, bin, registered_name, registered_address, updated_by
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (restaurant_id) DO UPDATE SET
+  bin = EXCLUDED.bin,
+  registered_name = EXCLUDED.registered_name,
+  registered_address = EXCLUDED.registered_address,
+  updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: NextTaxInvoiceSerial :one
+INSERT INTO tax_invoice_serials (tenant_id, seller_id, last_serial)
+VALUES ($1, $2, 1)
+ON CONFLICT (tenant_id, seller_id) DO UPDATE SET last_serial = tax_invoice_serials.last_serial + 1
+RETURNING last_serial;
+
+-- name: CreateTaxInvoice :one
+INSERT INTO tax_invoices (
+    tenant_id, order_id, restaurant_id, invoice_number,
+    seller_name, seller_bin, seller_address,
+    buyer_name, buyer_phone, buyer_address,
+    taxable_value, vat_amount, total_amount, lines, supplied_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING *;
+
+-- name: ListTaxInvoicesByOrder :many
+SELECT * FROM tax_invoices
+WHERE order_id = $1 AND tenant_id = $2
+ORDER BY restaurant_id NULLS LAST, invoice_number;
+
+-- name: ListRestaurantTaxInvoices :many
+SELECT * FROM tax_invoices
+WHERE tenant_id = sqlc.arg(tenant_id) AND restaurant_id = sqlc.arg(restaurant_id)
+  AND supplied_at >= sqlc.arg(period_start) AND supplied_at < sqlc.arg(period_end)
+ORDER BY supplied_at, invoice_number;
+
+-- name: ListFeeTaxInvoices :many
+SELECT * FROM tax_invoices
+WHERE tenant_id = sqlc.arg(tenant_id) AND restaurant_id IS NULL
+  AND supplied_at >= sqlc.arg(period_start) AND supplied_at < sqlc.arg(period_end)
+ORDER BY supplied_at, invoice_number;
+
+-- Delivered orders without tax invoices, delivered since the given time and
+-- not already failed.
+-- name: ListOrdersAwaitingTaxInvoices :many
+SELECT o.id, o.tenant_id FROM orders o
+WHERE o.status = 'delivered' AND o.deleted_at IS NULL
+  AND o.delivered_at >= sqlc.arg(delivered_since)::timestamptz
+  AND NOT EXISTS (SELECT 1 FROM tax_invoices t WHERE t.order_id = o.id)
+  AND NOT EXISTS (
+      SELECT 1 FROM tax_invoice_failures f
+      WHERE f.order_id = o.id AND (f.permanent OR f.next_retry_at > NOW())
+  )
+ORDER BY o.delivered_at
+LIMIT sqlc.arg('limit');
+
+-- Retries back off from 5 minutes, doubling per attempt. A failure becomes
+-- permanent when the caller says so or after max_attempts attempts.
+-- name: RecordTaxInvoiceFailure :one
+INSERT INTO tax_invoice_failures (order_id, tenant_id, error, permanent, next_retry_at)
+VALUES (
+    sqlc.arg(order_id), sqlc.arg(tenant_id), sqlc.arg(error),
+    sqlc.arg(permanent)::boolean OR sqlc.arg(max_attempts)::int <= 1,
+    CASE WHEN sqlc.arg(permanent)::boolean OR sqlc.arg(max_attempts)::int <= 1 THEN NULL ELSE NOW() + INTERVAL '5 minutes' END
+)
+ON CONFLICT (order_id) DO UPDATE SET
+    error         = EXCLUDED.error,
+    attempts      = tax_invoice_failures.attempts + 1,
+    permanent     = EXCLUDED.permanent OR tax_invoice_failures.attempts + 1 >= sqlc.arg(max_attempts)::int,
+    next_retry_at = CASE
+        WHEN EXCLUDED.permanent OR tax_invoice_failures.attempts + 1 >= sqlc.arg(max_attempts)::int THEN NULL
+        ELSE NOW() + (5 * POWER(2, tax_invoice_failures.attempts) || ' minutes')::interval
+    END,
+    failed_at     = NOW()
+RETURNING *;
+
+-- name: ListTaxInvoiceFailuresForPeriod :many
+-- Delivered orders of the period without tax invoices because issuing them
+-- failed; restaurant_id limits them to orders with a pickup from it.
+SELECT f.order_id, o.order_number, o.delivered_at, f.error, f.attempts, f.permanent, f.failed_at
+FROM tax_invoice_failures f
+JOIN orders o ON o.id = f.order_id
+WHERE f.tenant_id = sqlc.arg(tenant_id)
+  AND o.delivered_at >= sqlc.arg(period_start)::timestamptz
+  AND o.delivered_at < sqlc.arg(period_end)::timestamptz
+  AND (sqlc.narg(restaurant_id)::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM order_pickups p WHERE p.order_id = f.order_id AND p.restaurant_id = sqlc.narg(restaurant_id)::uuid
+  ))
+ORDER BY o.delivered_at;
+
+-- name: ClearTaxInvoiceFailure :exec
+DELETE FROM tax_invoice_failures WHERE order_id = $1;
@@ -35,3 +35,6 @@ UPDATE tenants SET status = $2 WHERE id = $1 RETURNING *;
 
 -- name: ListTenants :many
 SELECT * FROM tenants ORDER BY created_at DESC LIMIT $1 OFFSET $2;
+
+-- name: ListActiveTenantIDs :many
+SELECT id FROM tenants WHERE status = 'active' ORDER BY created_at;
@@ -0,0 +1,116 @@
+-- ============================================================
+-- Vendor Payouts SQLC Queries
+-- ============================================================
+
+-- name: GetSettlementSchedule :one
+SELECT * FROM settlement_schedules WHERE tenant_id = $1 LIMIT 1;
+
+-- name: UpsertSettlementSchedule :one
+INSERT INTO settlement_schedules (tenant_id, cycle, anchor_date, review_days, auto_finalize, updated_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id) DO UPDATE SET
+  cycle = EXCLUDED.cycle,
+  anchor_date = EXCLUDED.anchor_date,
+  review_days = EXCLUDED.review_days,
+  auto_finalize = EXCLUDED.auto_finalize,
+  updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: SetSettlementLastPeriodEnd :exec
+INSERT INTO settlement_schedules (tenant_id, last_period_end)
+VALUES ($1, $2)
+ON CONFLICT (tenant_id) DO UPDATE SET last_period_end = EXCLUDED.last_period_end;
+
+-- name: GetRestaurantPayoutAccount :one
+SELECT * FROM restaurant_payout_accounts WHERE restaurant_id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: UpsertRestaurantPayoutAccount :one
+INSERT INTO restaurant_payout_accounts (
+    restaurant_id, tenant_id, method, account_name, account_number,
+    bank_name, branch_name, routing_number, updated_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (restaurant_id) DO UPDATE SET
+  method = EXCLUDED.method,
+  account_name = EXCLUDED.account_name,
+  account_number = EXCLUDED.account_number,
+  bank_name = EXCLUDED.bank_name,
+  branch_name = EXCLUDED.branch_name,
+  routing_number = EXCLUDED.routing_number,
+  updated_by = EXCLUDED.updated_by
+RETURNING *;
+
+-- name: CreateVendorPayoutBatch :one
+INSERT INTO vendor_payout_batches (tenant_id, kind, created_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateVendorPayoutBatchTotals :one
+UPDATE vendor_payout_batches SET
+  payout_count = sqlc.arg(payout_count),
+  total_amount = sqlc.arg(total_amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteVendorPayoutBatch :exec
+DELETE FROM vendor_payout_batches WHERE id = $1;
+
+-- name: GetVendorPayoutBatch :one
+SELECT * FROM vendor_payout_batches WHERE id = $1 AND tenant_id = $2 LIMIT 1;
+
+-- name: ListVendorPayoutBatches :many
+SELECT * FROM vendor_payout_batches
+WHERE tenant_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountVendorPayoutBatches :one
+SELECT COUNT(*) FROM vendor_payout_batches WHERE tenant_id = $1;
+
+-- name: CreateVendorPayout :one
+INSERT INTO vendor_payouts (
+    tenant_id, batch_id, restaurant_id, invoice_id, amount, method,
+    account_name, account_number, bank_name, branch_name, routing_number
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: ListVendorPayoutsByBatch :many
+SELECT p.id, p.restaurant_id, p.invoice_id, p.amount, p.method, p.account_name, p.account_number,
+  p.bank_name, p.branch_name, p.routing_number, p.status, p.payment_reference, p.processed_at, p.note,
+  r.name AS restaurant_name, i.invoice_number
+FROM vendor_payouts p
+JOIN restaurants r ON r.id = p.restaurant_id
+JOIN invoices i ON i.id = p.invoice_id
+WHERE p.batch_id = $1 AND p.tenant_id = $2
+ORDER BY r.name;
+
+-- name: MarkVendorPayoutsProcessing :exec
+UPDATE vendor_payouts SET status = 'processing'
+WHERE batch_id = $1 AND tenant_id = $2 AND method = $3 AND status = 'pending';
+
+-- name: GetVendorPayoutForUpdate :one
+SELECT * FROM vendor_payouts WHERE id = $1 AND tenant_id = $2 LIMIT 1 FOR UPDATE;
+
+-- name: GetOpenVendorPayoutByInvoice :one
+SELECT * FROM vendor_payouts
+WHERE invoice_id = $1 AND tenant_id = $2 AND status IN ('pending', 'processing')
+LIMIT 1
+FOR UPDATE;
+
+-- name: CompleteVendorPayout :one
+UPDATE vendor_payouts SET
+  status = 'completed',
+  payment_reference = sqlc.narg(payment_reference),
+  processed_by = sqlc.narg(processed_by),
+  processed_at = NOW(),
+  note = COALESCE(sqlc.narg(note), note)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: FailVendorPayout :one
+UPDATE vendor_payouts SET
+  status = 'failed',
+  processed_by = sqlc.narg(processed_by),
+  processed_at = NOW(),
+  note = sqlc.narg(note)
+WHERE id = sqlc.arg(id)
+RETURNING *;
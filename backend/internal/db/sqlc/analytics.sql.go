@@ -14,125 +14,6 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createOrderAnalytics = `-- name: CreateOrderAnalytics :one
-INSERT INTO order_analytics (
-    tenant_id, order_id, restaurant_ids, customer_id, rider_id, hub_id,
-    delivery_area, payment_method, platform, promo_code,
-    subtotal, item_discount, promo_discount, delivery_charge, vat_total, total_amount, commission_total,
-    confirmation_duration_s, preparation_duration_s, pickup_to_delivery_s, total_fulfillment_s,
-    final_status, cancellation_reason,
-    order_date, order_hour, order_day_of_week, order_week, order_month, order_year,
-    completed_at
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
-    $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
-) RETURNING id, tenant_id, order_id, restaurant_ids, customer_id, rider_id, hub_id, delivery_area, payment_method, platform, promo_code, subtotal, item_discount, promo_discount, delivery_charge, vat_total, total_amount, commission_total, confirmation_duration_s, preparation_duration_s, pickup_to_delivery_s, total_fulfillment_s, final_status, cancellation_reason, order_date, order_hour, order_day_of_week, order_week, order_month, order_year, completed_at, created_at
-`
-
-type CreateOrderAnalyticsParams struct {
-	TenantID              uuid.UUID          `json:"tenant_id"`
-	OrderID               uuid.UUID          `json:"order_id"`
-	RestaurantIds         []uuid.UUID        `json:"restaurant_ids"`
-	CustomerID            uuid.UUID          `json:"customer_id"`
-	RiderID               pgtype.UUID        `json:"rider_id"`
-	HubID                 pgtype.UUID        `json:"hub_id"`
-	DeliveryArea          sql.NullString     `json:"delivery_area"`
-	PaymentMethod         string             `json:"payment_method"`
-	Platform              string             `json:"platform"`
-	PromoCode             sql.NullString     `json:"promo_code"`
-	Subtotal              pgtype.Numeric     `json:"subtotal"`
-	ItemDiscount          pgtype.Numeric     `json:"item_discount"`
-	PromoDiscount         pgtype.Numeric     `json:"promo_discount"`
-	DeliveryCharge        pgtype.Numeric     `json:"delivery_charge"`
-	VatTotal              pgtype.Numeric     `json:"vat_total"`
-	TotalAmount           pgtype.Numeric     `json:"total_amount"`
-	CommissionTotal       pgtype.Numeric     `json:"commission_total"`
-	ConfirmationDurationS *int32             `json:"confirmation_duration_s"`
-	PreparationDurationS  *int32             `json:"preparation_duration_s"`
-	PickupToDeliveryS     *int32             `json:"pickup_to_delivery_s"`
-	TotalFulfillmentS     *int32             `json:"total_fulfillment_s"`
-	FinalStatus           string             `json:"final_status"`
-	CancellationReason    sql.NullString     `json:"cancellation_reason"`
-	OrderDate             pgtype.Date        `json:"order_date"`
-	OrderHour             int16              `json:"order_hour"`
-	OrderDayOfWeek        int16              `json:"order_day_of_week"`
-	OrderWeek             int32              `json:"order_week"`
-	OrderMonth            int16              `json:"order_month"`
-	OrderYear             int16              `json:"order_year"`
-	CompletedAt           pgtype.Timestamptz `json:"completed_at"`
-}
-
-func (q *Queries) CreateOrderAnalytics(ctx context.Context, arg CreateOrderAnalyticsParams) (OrderAnalytic, error) {
-	row := q.db.QueryRow(ctx, createOrderAnalytics,
-		arg.TenantID,
-		arg.OrderID,
-		arg.RestaurantIds,
-		arg.CustomerID,
-		arg.RiderID,
-		arg.HubID,
-		arg.DeliveryArea,
-		arg.PaymentMethod,
-		arg.Platform,
-		arg.PromoCode,
-		arg.Subtotal,
-		arg.ItemDiscount,
-		arg.PromoDiscount,
-		arg.DeliveryCharge,
-		arg.VatTotal,
-		arg.TotalAmount,
-		arg.CommissionTotal,
-		arg.ConfirmationDurationS,
-		arg.PreparationDurationS,
-		arg.PickupToDeliveryS,
-		arg.TotalFulfillmentS,
-		arg.FinalStatus,
-		arg.CancellationReason,
-		arg.OrderDate,
-		arg.OrderHour,
-		arg.OrderDayOfWeek,
-		arg.OrderWeek,
-		arg.OrderMonth,
-		arg.OrderYear,
-		arg.CompletedAt,
-	)
-	var i OrderAnalytic
-	err := row.Scan(
-		&i.ID,
-		&i.TenantID,
-		&i.OrderID,
-		&i.RestaurantIds,
-		&i.CustomerID,
-		&i.RiderID,
-		&i.HubID,
-		&i.DeliveryArea,
-		&i.PaymentMethod,
-		&i.Platform,
-		&i.PromoCode,
-		&i.Subtotal,
-		&i.ItemDiscount,
-		&i.PromoDiscount,
-		&i.DeliveryCharge,
-		&i.VatTotal,
-		&i.TotalAmount,
-		&i.CommissionTotal,
-		&i.ConfirmationDurationS,
-		&i.PreparationDurationS,
-		&i.PickupToDeliveryS,
-		&i.TotalFulfillmentS,
-		&i.FinalStatus,
-		&i.CancellationReason,
-		&i.OrderDate,
-		&i.OrderHour,
-		&i.OrderDayOfWeek,
-		&i.OrderWeek,
-		&i.OrderMonth,
-		&i.OrderYear,
-		&i.CompletedAt,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
 const getAdminOrderVolume = `-- name: GetAdminOrderVolume :many
 SELECT
     order_date,
@@ -278,8 +159,14 @@ SELECT
     COALESCE(AVG(CASE WHEN final_status = 'delivered' THEN total_fulfillment_s END), 0)::INT AS avg_delivery_time_s
 FROM order_analytics
 WHERE tenant_id = $1 AND order_date = CURRENT_DATE
+  AND ($2::uuid[] IS NULL OR restaurant_ids && $2::uuid[])
 `
 
+type GetDashboardTodayParams struct {
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
+}
+
 type GetDashboardTodayRow struct {
 	TotalOrders      int32          `json:"total_orders"`
 	DeliveredOrders  int32          `json:"delivered_orders"`
@@ -289,8 +176,8 @@ type GetDashboardTodayRow struct {
 	AvgDeliveryTimeS int32          `json:"avg_delivery_time_s"`
 }
 
-func (q *Queries) GetDashboardToday(ctx context.Context, tenantID uuid.UUID) (GetDashboardTodayRow, error) {
-	row := q.db.QueryRow(ctx, getDashboardToday, tenantID)
+func (q *Queries) GetDashboardToday(ctx context.Context, arg GetDashboardTodayParams) (GetDashboardTodayRow, error) {
+	row := q.db.QueryRow(ctx, getDashboardToday, arg.TenantID, arg.RestaurantIds)
 	var i GetDashboardTodayRow
 	err := row.Scan(
 		&i.TotalOrders,
@@ -310,14 +197,16 @@ SELECT
     COALESCE(SUM(CASE WHEN final_status = 'delivered' THEN total_amount ELSE 0 END), 0)::NUMERIC(14,2) AS revenue
 FROM order_analytics
 WHERE tenant_id = $1 AND order_date >= $2::date AND order_date <= $3::date
+  AND ($4::uuid[] IS NULL OR restaurant_ids && $4::uuid[])
 GROUP BY order_date
 ORDER BY order_date ASC
 `
 
 type GetDashboardTrendParams struct {
-	TenantID  uuid.UUID   `json:"tenant_id"`
-	StartDate pgtype.Date `json:"start_date"`
-	EndDate   pgtype.Date `json:"end_date"`
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	StartDate     pgtype.Date `json:"start_date"`
+	EndDate       pgtype.Date `json:"end_date"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
 }
 
 type GetDashboardTrendRow struct {
@@ -327,7 +216,12 @@ type GetDashboardTrendRow struct {
 }
 
 func (q *Queries) GetDashboardTrend(ctx context.Context, arg GetDashboardTrendParams) ([]GetDashboardTrendRow, error) {
-	rows, err := q.db.Query(ctx, getDashboardTrend, arg.TenantID, arg.StartDate, arg.EndDate)
+	rows, err := q.db.Query(ctx, getDashboardTrend,
+		arg.TenantID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.RestaurantIds,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -346,6 +240,22 @@ func (q *Queries) GetDashboardTrend(ctx context.Context, arg GetDashboardTrendPa
 	return items, nil
 }
 
+const getJobWatermark = `-- name: GetJobWatermark :one
+SELECT job, last_updated_at, last_id, updated_at FROM job_watermarks WHERE job = $1
+`
+
+func (q *Queries) GetJobWatermark(ctx context.Context, job string) (JobWatermark, error) {
+	row := q.db.QueryRow(ctx, getJobWatermark, job)
+	var i JobWatermark
+	err := row.Scan(
+		&i.Job,
+		&i.LastUpdatedAt,
+		&i.LastID,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getOrderAnalyticsByOrderID = `-- name: GetOrderAnalyticsByOrderID :one
 SELECT id, tenant_id, order_id, restaurant_ids, customer_id, rider_id, hub_id, delivery_area, payment_method, platform, promo_code, subtotal, item_discount, promo_discount, delivery_charge, vat_total, total_amount, commission_total, confirmation_duration_s, preparation_duration_s, pickup_to_delivery_s, total_fulfillment_s, final_status, cancellation_reason, order_date, order_hour, order_day_of_week, order_week, order_month, order_year, completed_at, created_at FROM order_analytics WHERE order_id = $1 AND tenant_id = $2 LIMIT 1
 `
@@ -403,13 +313,15 @@ FROM order_analytics
 WHERE tenant_id = $1
   AND order_date >= $2::date
   AND order_date <= $3::date
+  AND ($4::uuid[] IS NULL OR restaurant_ids && $4::uuid[])
 GROUP BY final_status
 `
 
 type GetOrderStatusBreakdownParams struct {
-	TenantID  uuid.UUID   `json:"tenant_id"`
-	StartDate pgtype.Date `json:"start_date"`
-	EndDate   pgtype.Date `json:"end_date"`
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	StartDate     pgtype.Date `json:"start_date"`
+	EndDate       pgtype.Date `json:"end_date"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
 }
 
 type GetOrderStatusBreakdownRow struct {
@@ -418,7 +330,12 @@ type GetOrderStatusBreakdownRow struct {
 }
 
 func (q *Queries) GetOrderStatusBreakdown(ctx context.Context, arg GetOrderStatusBreakdownParams) ([]GetOrderStatusBreakdownRow, error) {
-	rows, err := q.db.Query(ctx, getOrderStatusBreakdown, arg.TenantID, arg.StartDate, arg.EndDate)
+	rows, err := q.db.Query(ctx, getOrderStatusBreakdown,
+		arg.TenantID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.RestaurantIds,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -445,14 +362,16 @@ FROM order_analytics
 WHERE tenant_id = $1
   AND order_date >= $2::date
   AND order_date <= $3::date
+  AND ($4::uuid[] IS NULL OR restaurant_ids && $4::uuid[])
 GROUP BY order_hour
 ORDER BY order_hour ASC
 `
 
 type GetPeakHoursParams struct {
-	TenantID  uuid.UUID   `json:"tenant_id"`
-	StartDate pgtype.Date `json:"start_date"`
-	EndDate   pgtype.Date `json:"end_date"`
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	StartDate     pgtype.Date `json:"start_date"`
+	EndDate       pgtype.Date `json:"end_date"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
 }
 
 type GetPeakHoursRow struct {
@@ -461,7 +380,12 @@ type GetPeakHoursRow struct {
 }
 
 func (q *Queries) GetPeakHours(ctx context.Context, arg GetPeakHoursParams) ([]GetPeakHoursRow, error) {
-	rows, err := q.db.Query(ctx, getPeakHours, arg.TenantID, arg.StartDate, arg.EndDate)
+	rows, err := q.db.Query(ctx, getPeakHours,
+		arg.TenantID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.RestaurantIds,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -481,12 +405,20 @@ func (q *Queries) GetPeakHours(ctx context.Context, arg GetPeakHoursParams) ([]G
 }
 
 const getPendingOrderCount = `-- name: GetPendingOrderCount :one
-SELECT COUNT(*)::INT FROM orders
-WHERE tenant_id = $1 AND status IN ('pending', 'created') AND deleted_at IS NULL
+SELECT COUNT(*)::INT FROM orders o
+WHERE o.tenant_id = $1 AND o.status IN ('pending', 'created') AND o.deleted_at IS NULL
+  AND ($2::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups p
+        WHERE p.order_id = o.id AND p.restaurant_id = ANY($2::uuid[])))
 `
 
-func (q *Queries) GetPendingOrderCount(ctx context.Context, tenantID uuid.UUID) (int32, error) {
-	row := q.db.QueryRow(ctx, getPendingOrderCount, tenantID)
+type GetPendingOrderCountParams struct {
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
+}
+
+func (q *Queries) GetPendingOrderCount(ctx context.Context, arg GetPendingOrderCountParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getPendingOrderCount, arg.TenantID, arg.RestaurantIds)
 	var column_1 int32
 	err := row.Scan(&column_1)
 	return column_1, err
@@ -562,15 +494,17 @@ FROM order_analytics
 WHERE tenant_id = $1
   AND order_date >= $2::date
   AND order_date <= $3::date
+  AND ($4::uuid[] IS NULL OR restaurant_ids && $4::uuid[])
   AND final_status = 'delivered'
 GROUP BY order_date
 ORDER BY order_date ASC
 `
 
 type GetSalesReportParams struct {
-	TenantID  uuid.UUID   `json:"tenant_id"`
-	StartDate pgtype.Date `json:"start_date"`
-	EndDate   pgtype.Date `json:"end_date"`
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	StartDate     pgtype.Date `json:"start_date"`
+	EndDate       pgtype.Date `json:"end_date"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
 }
 
 type GetSalesReportRow struct {
@@ -588,7 +522,12 @@ type GetSalesReportRow struct {
 }
 
 func (q *Queries) GetSalesReport(ctx context.Context, arg GetSalesReportParams) ([]GetSalesReportRow, error) {
-	rows, err := q.db.Query(ctx, getSalesReport, arg.TenantID, arg.StartDate, arg.EndDate)
+	rows, err := q.db.Query(ctx, getSalesReport,
+		arg.TenantID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.RestaurantIds,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -669,18 +608,20 @@ FROM order_items oi
 JOIN orders o ON oi.order_id = o.id
 WHERE o.tenant_id = $1
   AND o.status = 'delivered'
-  AND o.delivered_at >= $3::timestamptz
-  AND o.delivered_at < $4::timestamptz
+  AND o.delivered_at >= $2::timestamptz
+  AND o.delivered_at < $3::timestamptz
+  AND ($4::uuid[] IS NULL OR oi.restaurant_id = ANY($4::uuid[]))
 GROUP BY oi.product_id, oi.product_name
 ORDER BY total_quantity DESC
-LIMIT $2
+LIMIT $5
 `
 
 type GetTopProductsParams struct {
-	TenantID  uuid.UUID `json:"tenant_id"`
-	Limit     int32     `json:"limit"`
-	StartDate time.Time `json:"start_date"`
-	EndDate   time.Time `json:"end_date"`
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	StartDate     time.Time   `json:"start_date"`
+	EndDate       time.Time   `json:"end_date"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
+	Limit         int32       `json:"limit"`
 }
 
 type GetTopProductsRow struct {
@@ -693,9 +634,10 @@ type GetTopProductsRow struct {
 func (q *Queries) GetTopProducts(ctx context.Context, arg GetTopProductsParams) ([]GetTopProductsRow, error) {
 	rows, err := q.db.Query(ctx, getTopProducts,
 		arg.TenantID,
-		arg.Limit,
 		arg.StartDate,
 		arg.EndDate,
+		arg.RestaurantIds,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -719,3 +661,365 @@ func (q *Queries) GetTopProducts(ctx context.Context, arg GetTopProductsParams)
 	}
 	return items, nil
 }
+
+const listTerminalOrdersAfterWatermark = `-- name: ListTerminalOrdersAfterWatermark :many
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
+WHERE status IN ('delivered', 'cancelled', 'rejected')
+  AND deleted_at IS NULL
+  AND (updated_at, id) > ($1::timestamptz, $2::uuid)
+  AND updated_at < $3::timestamptz
+  AND NOT EXISTS (SELECT 1 FROM order_analytics oa WHERE oa.order_id = orders.id)
+  AND NOT EXISTS (
+      SELECT 1 FROM outbox_events e
+      WHERE e.aggregate_type = 'order' AND e.aggregate_id = orders.id
+        AND e.event_type = 'order.completed'
+  )
+ORDER BY updated_at ASC, id ASC
+LIMIT $4
+`
+
+type ListTerminalOrdersAfterWatermarkParams struct {
+	AfterUpdatedAt time.Time `json:"after_updated_at"`
+	AfterID        uuid.UUID `json:"after_id"`
+	Before         time.Time `json:"before"`
+	Limit          int32     `json:"limit"`
+}
+
+func (q *Queries) ListTerminalOrdersAfterWatermark(ctx context.Context, arg ListTerminalOrdersAfterWatermarkParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listTerminalOrdersAfterWatermark,
+		arg.AfterUpdatedAt,
+		arg.AfterID,
+		arg.Before,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.OrderNumber,
+			&i.CustomerID,
+			&i.RiderID,
+			&i.HubID,
+			&i.Status,
+			&i.PaymentStatus,
+			&i.PaymentMethod,
+			&i.Platform,
+			&i.DeliveryAddressID,
+			&i.DeliveryAddress,
+			&i.DeliveryRecipientName,
+			&i.DeliveryRecipientPhone,
+			&i.DeliveryArea,
+			&i.DeliveryGeoLat,
+			&i.DeliveryGeoLng,
+			&i.Subtotal,
+			&i.ItemDiscountTotal,
+			&i.PromoDiscountTotal,
+			&i.VatTotal,
+			&i.DeliveryCharge,
+			&i.ServiceFee,
+			&i.TotalAmount,
+			&i.PromoID,
+			&i.PromoCode,
+			&i.PromoSnapshot,
+			&i.IsPriority,
+			&i.IsReorder,
+			&i.CustomerNote,
+			&i.RiderNote,
+			&i.InternalNote,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.RejectionReason,
+			&i.RejectedBy,
+			&i.AutoConfirmAt,
+			&i.EstimatedDeliveryMinutes,
+			&i.ConfirmedAt,
+			&i.PreparingAt,
+			&i.ReadyAt,
+			&i.PickedAt,
+			&i.DeliveredAt,
+			&i.CancelledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.RiderTip,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTerminalOrdersForBackfill = `-- name: ListTerminalOrdersForBackfill :many
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
+WHERE status IN ('delivered', 'cancelled', 'rejected')
+  AND deleted_at IS NULL
+  AND ($1::uuid IS NULL OR tenant_id = $1::uuid)
+  AND created_at >= $2::timestamptz
+  AND created_at < $3::timestamptz
+  AND (created_at, id) > ($4::timestamptz, $5::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT $6
+`
+
+type ListTerminalOrdersForBackfillParams struct {
+	TenantID       pgtype.UUID `json:"tenant_id"`
+	FromTime       time.Time   `json:"from_time"`
+	ToTime         time.Time   `json:"to_time"`
+	AfterCreatedAt time.Time   `json:"after_created_at"`
+	AfterID        uuid.UUID   `json:"after_id"`
+	Limit          int32       `json:"limit"`
+}
+
+func (q *Queries) ListTerminalOrdersForBackfill(ctx context.Context, arg ListTerminalOrdersForBackfillParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listTerminalOrdersForBackfill,
+		arg.TenantID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Order{}
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.OrderNumber,
+			&i.CustomerID,
+			&i.RiderID,
+			&i.HubID,
+			&i.Status,
+			&i.PaymentStatus,
+			&i.PaymentMethod,
+			&i.Platform,
+			&i.DeliveryAddressID,
+			&i.DeliveryAddress,
+			&i.DeliveryRecipientName,
+			&i.DeliveryRecipientPhone,
+			&i.DeliveryArea,
+			&i.DeliveryGeoLat,
+			&i.DeliveryGeoLng,
+			&i.Subtotal,
+			&i.ItemDiscountTotal,
+			&i.PromoDiscountTotal,
+			&i.VatTotal,
+			&i.DeliveryCharge,
+			&i.ServiceFee,
+			&i.TotalAmount,
+			&i.PromoID,
+			&i.PromoCode,
+			&i.PromoSnapshot,
+			&i.IsPriority,
+			&i.IsReorder,
+			&i.CustomerNote,
+			&i.RiderNote,
+			&i.InternalNote,
+			&i.CancellationReason,
+			&i.CancelledBy,
+			&i.RejectionReason,
+			&i.RejectedBy,
+			&i.AutoConfirmAt,
+			&i.EstimatedDeliveryMinutes,
+			&i.ConfirmedAt,
+			&i.PreparingAt,
+			&i.ReadyAt,
+			&i.PickedAt,
+			&i.DeliveredAt,
+			&i.CancelledAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.RiderTip,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertJobWatermark = `-- name: UpsertJobWatermark :exec
+INSERT INTO job_watermarks (job, last_updated_at, last_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (job) DO UPDATE SET
+    last_updated_at = EXCLUDED.last_updated_at,
+    last_id         = EXCLUDED.last_id
+`
+
+type UpsertJobWatermarkParams struct {
+	Job           string    `json:"job"`
+	LastUpdatedAt time.Time `json:"last_updated_at"`
+	LastID        uuid.UUID `json:"last_id"`
+}
+
+func (q *Queries) UpsertJobWatermark(ctx context.Context, arg UpsertJobWatermarkParams) error {
+	_, err := q.db.Exec(ctx, upsertJobWatermark, arg.Job, arg.LastUpdatedAt, arg.LastID)
+	return err
+}
+
+const upsertOrderAnalytics = `-- name: UpsertOrderAnalytics :one
+INSERT INTO order_analytics (
+    tenant_id, order_id, restaurant_ids, customer_id, rider_id, hub_id,
+    delivery_area, payment_method, platform, promo_code,
+    subtotal, item_discount, promo_discount, delivery_charge, vat_total, total_amount, commission_total,
+    confirmation_duration_s, preparation_duration_s, pickup_to_delivery_s, total_fulfillment_s,
+    final_status, cancellation_reason,
+    order_date, order_hour, order_day_of_week, order_week, order_month, order_year,
+    completed_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+    $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
+)
+ON CONFLICT (order_id) DO UPDATE SET
+    restaurant_ids          = EXCLUDED.restaurant_ids,
+    customer_id             = EXCLUDED.customer_id,
+    rider_id                = EXCLUDED.rider_id,
+    hub_id                  = EXCLUDED.hub_id,
+    delivery_area           = EXCLUDED.delivery_area,
+    payment_method          = EXCLUDED.payment_method,
+    platform                = EXCLUDED.platform,
+    promo_code              = EXCLUDED.promo_code,
+    subtotal                = EXCLUDED.subtotal,
+    item_discount           = EXCLUDED.item_discount,
+    promo_discount          = EXCLUDED.promo_discount,
+    delivery_charge         = EXCLUDED.delivery_charge,
+    vat_total               = EXCLUDED.vat_total,
+    total_amount            = EXCLUDED.total_amount,
+    commission_total        = EXCLUDED.commission_total,
+    confirmation_duration_s = EXCLUDED.confirmation_duration_s,
+    preparation_duration_s  = EXCLUDED.preparation_duration_s,
+    pickup_to_delivery_s    = EXCLUDED.pickup_to_delivery_s,
+    total_fulfillment_s     = EXCLUDED.total_fulfillment_s,
+    final_status            = EXCLUDED.final_status,
+    cancellation_reason     = EXCLUDED.cancellation_reason,
+    order_date              = EXCLUDED.order_date,
+    order_hour              = EXCLUDED.order_hour,
+    order_day_of_week       = EXCLUDED.order_day_of_week,
+    order_week              = EXCLUDED.order_week,
+    order_month             = EXCLUDED.order_month,
+    order_year              = EXCLUDED.order_year,
+    completed_at            = EXCLUDED.completed_at
+RETURNING id, tenant_id, order_id, restaurant_ids, customer_id, rider_id, hub_id, delivery_area, payment_method, platform, promo_code, subtotal, item_discount, promo_discount, delivery_charge, vat_total, total_amount, commission_total, confirmation_duration_s, preparation_duration_s, pickup_to_delivery_s, total_fulfillment_s, final_status, cancellation_reason, order_date, order_hour, order_day_of_week, order_week, order_month, order_year, completed_at, created_at
+`
+
+type UpsertOrderAnalyticsParams struct {
+	TenantID              uuid.UUID          `json:"tenant_id"`
+	OrderID               uuid.UUID          `json:"order_id"`
+	RestaurantIds         []uuid.UUID        `json:"restaurant_ids"`
+	CustomerID            uuid.UUID          `json:"customer_id"`
+	RiderID               pgtype.UUID        `json:"rider_id"`
+	HubID                 pgtype.UUID        `json:"hub_id"`
+	DeliveryArea          sql.NullString     `json:"delivery_area"`
+	PaymentMethod         string             `json:"payment_method"`
+	Platform              string             `json:"platform"`
+	PromoCode             sql.NullString     `json:"promo_code"`
+	Subtotal              pgtype.Numeric     `json:"subtotal"`
+	ItemDiscount          pgtype.Numeric     `json:"item_discount"`
+	PromoDiscount         pgtype.Numeric     `json:"promo_discount"`
+	DeliveryCharge        pgtype.Numeric     `json:"delivery_charge"`
+	VatTotal              pgtype.Numeric     `json:"vat_total"`
+	TotalAmount           pgtype.Numeric     `json:"total_amount"`
+	CommissionTotal       pgtype.Numeric     `json:"commission_total"`
+	ConfirmationDurationS *int32             `json:"confirmation_duration_s"`
+	PreparationDurationS  *int32             `json:"preparation_duration_s"`
+	PickupToDeliveryS     *int32             `json:"pickup_to_delivery_s"`
+	TotalFulfillmentS     *int32             `json:"total_fulfillment_s"`
+	FinalStatus           string             `json:"final_status"`
+	CancellationReason    sql.NullString     `json:"cancellation_reason"`
+	OrderDate             pgtype.Date        `json:"order_date"`
+	OrderHour             int16              `json:"order_hour"`
+	OrderDayOfWeek        int16              `json:"order_day_of_week"`
+	OrderWeek             int32              `json:"order_week"`
+	OrderMonth            int16              `json:"order_month"`
+	OrderYear             int16              `json:"order_year"`
+	CompletedAt           pgtype.Timestamptz `json:"completed_at"`
+}
+
+func (q *Queries) UpsertOrderAnalytics(ctx context.Context, arg UpsertOrderAnalyticsParams) (OrderAnalytic, error) {
+	row := q.db.QueryRow(ctx, upsertOrderAnalytics,
+		arg.TenantID,
+		arg.OrderID,
+		arg.RestaurantIds,
+		arg.CustomerID,
+		arg.RiderID,
+		arg.HubID,
+		arg.DeliveryArea,
+		arg.PaymentMethod,
+		arg.Platform,
+		arg.PromoCode,
+		arg.Subtotal,
+		arg.ItemDiscount,
+		arg.PromoDiscount,
+		arg.DeliveryCharge,
+		arg.VatTotal,
+		arg.TotalAmount,
+		arg.CommissionTotal,
+		arg.ConfirmationDurationS,
+		arg.PreparationDurationS,
+		arg.PickupToDeliveryS,
+		arg.TotalFulfillmentS,
+		arg.FinalStatus,
+		arg.CancellationReason,
+		arg.OrderDate,
+		arg.OrderHour,
+		arg.OrderDayOfWeek,
+		arg.OrderWeek,
+		arg.OrderMonth,
+		arg.OrderYear,
+		arg.CompletedAt,
+	)
+	var i OrderAnalytic
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.OrderID,
+		&i.RestaurantIds,
+		&i.CustomerID,
+		&i.RiderID,
+		&i.HubID,
+		&i.DeliveryArea,
+		&i.PaymentMethod,
+		&i.Platform,
+		&i.PromoCode,
+		&i.Subtotal,
+		&i.ItemDiscount,
+		&i.PromoDiscount,
+		&i.DeliveryCharge,
+		&i.VatTotal,
+		&i.TotalAmount,
+		&i.CommissionTotal,
+		&i.ConfirmationDurationS,
+		&i.PreparationDurationS,
+		&i.PickupToDeliveryS,
+		&i.TotalFulfillmentS,
+		&i.FinalStatus,
+		&i.CancellationReason,
+		&i.OrderDate,
+		&i.OrderHour,
+		&i.OrderDayOfWeek,
+		&i.OrderWeek,
+		&i.OrderMonth,
+		&i.OrderYear,
+		&i.CompletedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
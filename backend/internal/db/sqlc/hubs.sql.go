@@ -15,25 +15,27 @@ import (
 )
 
 const createHub = `-- name: CreateHub :one
-INSERT INTO hubs (tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-RETURNING id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at
+INSERT INTO hubs (tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, rider_cash_limit, checkin_radius_m)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at, rider_cash_limit, checkin_radius_m
 `
 
 type CreateHubParams struct {
-	TenantID     uuid.UUID      `json:"tenant_id"`
-	Name         string         `json:"name"`
-	Code         sql.NullString `json:"code"`
-	ManagerID    pgtype.UUID    `json:"manager_id"`
-	AddressLine1 sql.NullString `json:"address_line1"`
-	AddressLine2 sql.NullString `json:"address_line2"`
-	City         string         `json:"city"`
-	GeoLat       pgtype.Numeric `json:"geo_lat"`
-	GeoLng       pgtype.Numeric `json:"geo_lng"`
-	ContactPhone sql.NullString `json:"contact_phone"`
-	ContactEmail sql.NullString `json:"contact_email"`
-	IsActive     bool           `json:"is_active"`
-	SortOrder    int32          `json:"sort_order"`
+	TenantID       uuid.UUID      `json:"tenant_id"`
+	Name           string         `json:"name"`
+	Code           sql.NullString `json:"code"`
+	ManagerID      pgtype.UUID    `json:"manager_id"`
+	AddressLine1   sql.NullString `json:"address_line1"`
+	AddressLine2   sql.NullString `json:"address_line2"`
+	City           string         `json:"city"`
+	GeoLat         pgtype.Numeric `json:"geo_lat"`
+	GeoLng         pgtype.Numeric `json:"geo_lng"`
+	ContactPhone   sql.NullString `json:"contact_phone"`
+	ContactEmail   sql.NullString `json:"contact_email"`
+	IsActive       bool           `json:"is_active"`
+	SortOrder      int32          `json:"sort_order"`
+	RiderCashLimit pgtype.Numeric `json:"rider_cash_limit"`
+	CheckinRadiusM *int32         `json:"checkin_radius_m"`
 }
 
 func (q *Queries) CreateHub(ctx context.Context, arg CreateHubParams) (Hub, error) {
@@ -51,6 +53,8 @@ func (q *Queries) CreateHub(ctx context.Context, arg CreateHubParams) (Hub, erro
 		arg.ContactEmail,
 		arg.IsActive,
 		arg.SortOrder,
+		arg.RiderCashLimit,
+		arg.CheckinRadiusM,
 	)
 	var i Hub
 	err := row.Scan(
@@ -70,6 +74,8 @@ func (q *Queries) CreateHub(ctx context.Context, arg CreateHubParams) (Hub, erro
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RiderCashLimit,
+		&i.CheckinRadiusM,
 	)
 	return i, err
 }
@@ -145,7 +151,7 @@ func (q *Queries) DeleteHubArea(ctx context.Context, id uuid.UUID) error {
 }
 
 const getDeliveryZoneConfig = `-- name: GetDeliveryZoneConfig :one
-SELECT id, tenant_id, model, distance_tiers, free_delivery_threshold, created_at, updated_at FROM delivery_zone_configs WHERE tenant_id = $1 LIMIT 1
+SELECT id, tenant_id, model, distance_tiers, free_delivery_threshold, created_at, updated_at, pod_otp_enabled, pod_photo_required, pod_geofence_radius_m, pod_min_order_value FROM delivery_zone_configs WHERE tenant_id = $1 LIMIT 1
 `
 
 func (q *Queries) GetDeliveryZoneConfig(ctx context.Context, tenantID uuid.UUID) (DeliveryZoneConfig, error) {
@@ -159,6 +165,10 @@ func (q *Queries) GetDeliveryZoneConfig(ctx context.Context, tenantID uuid.UUID)
 		&i.FreeDeliveryThreshold,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PodOtpEnabled,
+		&i.PodPhotoRequired,
+		&i.PodGeofenceRadiusM,
+		&i.PodMinOrderValue,
 	)
 	return i, err
 }
@@ -215,7 +225,7 @@ func (q *Queries) GetHubAreaByName(ctx context.Context, arg GetHubAreaByNamePara
 }
 
 const getHubByID = `-- name: GetHubByID :one
-SELECT id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at FROM hubs WHERE id = $1 AND tenant_id = $2 LIMIT 1
+SELECT id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at, rider_cash_limit, checkin_radius_m FROM hubs WHERE id = $1 AND tenant_id = $2 LIMIT 1
 `
 
 type GetHubByIDParams struct {
@@ -243,6 +253,8 @@ func (q *Queries) GetHubByID(ctx context.Context, arg GetHubByIDParams) (Hub, er
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RiderCashLimit,
+		&i.CheckinRadiusM,
 	)
 	return i, err
 }
@@ -284,7 +296,7 @@ func (q *Queries) ListHubAreas(ctx context.Context, hubID uuid.UUID) ([]HubCover
 }
 
 const listHubsByTenant = `-- name: ListHubsByTenant :many
-SELECT id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at FROM hubs WHERE tenant_id = $1 ORDER BY sort_order, name
+SELECT id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at, rider_cash_limit, checkin_radius_m FROM hubs WHERE tenant_id = $1 ORDER BY sort_order, name
 `
 
 func (q *Queries) ListHubsByTenant(ctx context.Context, tenantID uuid.UUID) ([]Hub, error) {
@@ -313,6 +325,8 @@ func (q *Queries) ListHubsByTenant(ctx context.Context, tenantID uuid.UUID) ([]H
 			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RiderCashLimit,
+			&i.CheckinRadiusM,
 		); err != nil {
 			return nil, err
 		}
@@ -334,23 +348,29 @@ UPDATE hubs SET
   contact_phone = COALESCE($6, contact_phone),
   contact_email = COALESCE($7, contact_email),
   is_active = COALESCE($8, is_active),
-  sort_order = COALESCE($9, sort_order)
-WHERE id = $10 AND tenant_id = $11
-RETURNING id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at
+  sort_order = COALESCE($9, sort_order),
+  manager_id = COALESCE($10, manager_id),
+  rider_cash_limit = COALESCE($11, rider_cash_limit),
+  checkin_radius_m = COALESCE($12, checkin_radius_m)
+WHERE id = $13 AND tenant_id = $14
+RETURNING id, tenant_id, name, code, manager_id, address_line1, address_line2, city, geo_lat, geo_lng, contact_phone, contact_email, is_active, sort_order, created_at, updated_at, rider_cash_limit, checkin_radius_m
 `
 
 type UpdateHubParams struct {
-	Name         sql.NullString `json:"name"`
-	Code         sql.NullString `json:"code"`
-	AddressLine1 sql.NullString `json:"address_line1"`
-	AddressLine2 sql.NullString `json:"address_line2"`
-	City         sql.NullString `json:"city"`
-	ContactPhone sql.NullString `json:"contact_phone"`
-	ContactEmail sql.NullString `json:"contact_email"`
-	IsActive     *bool          `json:"is_active"`
-	SortOrder    *int32         `json:"sort_order"`
-	ID           uuid.UUID      `json:"id"`
-	TenantID     uuid.UUID      `json:"tenant_id"`
+	Name           sql.NullString `json:"name"`
+	Code           sql.NullString `json:"code"`
+	AddressLine1   sql.NullString `json:"address_line1"`
+	AddressLine2   sql.NullString `json:"address_line2"`
+	City           sql.NullString `json:"city"`
+	ContactPhone   sql.NullString `json:"contact_phone"`
+	ContactEmail   sql.NullString `json:"contact_email"`
+	IsActive       *bool          `json:"is_active"`
+	SortOrder      *int32         `json:"sort_order"`
+	ManagerID      pgtype.UUID    `json:"manager_id"`
+	RiderCashLimit pgtype.Numeric `json:"rider_cash_limit"`
+	CheckinRadiusM *int32         `json:"checkin_radius_m"`
+	ID             uuid.UUID      `json:"id"`
+	TenantID       uuid.UUID      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateHub(ctx context.Context, arg UpdateHubParams) (Hub, error) {
@@ -364,6 +384,9 @@ func (q *Queries) UpdateHub(ctx context.Context, arg UpdateHubParams) (Hub, erro
 		arg.ContactEmail,
 		arg.IsActive,
 		arg.SortOrder,
+		arg.ManagerID,
+		arg.RiderCashLimit,
+		arg.CheckinRadiusM,
 		arg.ID,
 		arg.TenantID,
 	)
@@ -385,6 +408,8 @@ func (q *Queries) UpdateHub(ctx context.Context, arg UpdateHubParams) (Hub, erro
 		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RiderCashLimit,
+		&i.CheckinRadiusM,
 	)
 	return i, err
 }
@@ -439,13 +464,19 @@ func (q *Queries) UpdateHubArea(ctx context.Context, arg UpdateHubAreaParams) (H
 }
 
 const upsertDeliveryZoneConfig = `-- name: UpsertDeliveryZoneConfig :one
-INSERT INTO delivery_zone_configs (tenant_id, model, distance_tiers, free_delivery_threshold)
-VALUES ($1, $2, $3, $4)
+INSERT INTO delivery_zone_configs (
+  tenant_id, model, distance_tiers, free_delivery_threshold,
+  pod_otp_enabled, pod_photo_required, pod_geofence_radius_m, pod_min_order_value
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 ON CONFLICT (tenant_id) DO UPDATE SET
   model = EXCLUDED.model,
   distance_tiers = EXCLUDED.distance_tiers,
-  free_delivery_threshold = EXCLUDED.free_delivery_threshold
-RETURNING id, tenant_id, model, distance_tiers, free_delivery_threshold, created_at, updated_at
+  free_delivery_threshold = EXCLUDED.free_delivery_threshold,
+  pod_otp_enabled = EXCLUDED.pod_otp_enabled,
+  pod_photo_required = EXCLUDED.pod_photo_required,
+  pod_geofence_radius_m = EXCLUDED.pod_geofence_radius_m,
+  pod_min_order_value = EXCLUDED.pod_min_order_value
+RETURNING id, tenant_id, model, distance_tiers, free_delivery_threshold, created_at, updated_at, pod_otp_enabled, pod_photo_required, pod_geofence_radius_m, pod_min_order_value
 `
 
 type UpsertDeliveryZoneConfigParams struct {
@@ -453,6 +484,10 @@ type UpsertDeliveryZoneConfigParams struct {
 	Model                 DeliveryModel   `json:"model"`
 	DistanceTiers         json.RawMessage `json:"distance_tiers"`
 	FreeDeliveryThreshold pgtype.Numeric  `json:"free_delivery_threshold"`
+	PodOtpEnabled         bool            `json:"pod_otp_enabled"`
+	PodPhotoRequired      bool            `json:"pod_photo_required"`
+	PodGeofenceRadiusM    *int32          `json:"pod_geofence_radius_m"`
+	PodMinOrderValue      pgtype.Numeric  `json:"pod_min_order_value"`
 }
 
 func (q *Queries) UpsertDeliveryZoneConfig(ctx context.Context, arg UpsertDeliveryZoneConfigParams) (DeliveryZoneConfig, error) {
@@ -461,6 +496,10 @@ func (q *Queries) UpsertDeliveryZoneConfig(ctx context.Context, arg UpsertDelive
 		arg.Model,
 		arg.DistanceTiers,
 		arg.FreeDeliveryThreshold,
+		arg.PodOtpEnabled,
+		arg.PodPhotoRequired,
+		arg.PodGeofenceRadiusM,
+		arg.PodMinOrderValue,
 	)
 	var i DeliveryZoneConfig
 	err := row.Scan(
@@ -471,6 +510,10 @@ func (q *Queries) UpsertDeliveryZoneConfig(ctx context.Context, arg UpsertDelive
 		&i.FreeDeliveryThreshold,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PodOtpEnabled,
+		&i.PodPhotoRequired,
+		&i.PodGeofenceRadiusM,
+		&i.PodMinOrderValue,
 	)
 	return i, err
 }
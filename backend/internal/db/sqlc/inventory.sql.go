@@ -8,6 +8,7 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -312,6 +313,87 @@ func (q *Queries) GetInventoryItem(ctx context.Context, arg GetInventoryItemPara
 	return i, err
 }
 
+const getInventoryItemForUpdate = `-- name: GetInventoryItemForUpdate :one
+SELECT id, product_id, restaurant_id, tenant_id, stock_qty, reserved_qty, cost_price, reorder_threshold, last_restocked_at, updated_at FROM inventory_items
+WHERE id = $1 AND tenant_id = $2
+FOR UPDATE
+`
+
+type GetInventoryItemForUpdateParams struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+func (q *Queries) GetInventoryItemForUpdate(ctx context.Context, arg GetInventoryItemForUpdateParams) (InventoryItem, error) {
+	row := q.db.QueryRow(ctx, getInventoryItemForUpdate, arg.ID, arg.TenantID)
+	var i InventoryItem
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.RestaurantID,
+		&i.TenantID,
+		&i.StockQty,
+		&i.ReservedQty,
+		&i.CostPrice,
+		&i.ReorderThreshold,
+		&i.LastRestockedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getStockValuation = `-- name: GetStockValuation :many
+SELECT i.id, i.product_id, p.name AS product_name, i.stock_qty, i.reserved_qty, i.cost_price,
+       (i.stock_qty * COALESCE(i.cost_price, 0))::NUMERIC(14,2) AS stock_value
+FROM inventory_items i
+JOIN products p ON p.id = i.product_id
+WHERE i.tenant_id = $1 AND i.restaurant_id = $2
+ORDER BY stock_value DESC, p.name
+`
+
+type GetStockValuationParams struct {
+	TenantID     uuid.UUID `json:"tenant_id"`
+	RestaurantID uuid.UUID `json:"restaurant_id"`
+}
+
+type GetStockValuationRow struct {
+	ID          uuid.UUID      `json:"id"`
+	ProductID   uuid.UUID      `json:"product_id"`
+	ProductName string         `json:"product_name"`
+	StockQty    int32          `json:"stock_qty"`
+	ReservedQty int32          `json:"reserved_qty"`
+	CostPrice   pgtype.Numeric `json:"cost_price"`
+	StockValue  pgtype.Numeric `json:"stock_value"`
+}
+
+func (q *Queries) GetStockValuation(ctx context.Context, arg GetStockValuationParams) ([]GetStockValuationRow, error) {
+	rows, err := q.db.Query(ctx, getStockValuation, arg.TenantID, arg.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetStockValuationRow{}
+	for rows.Next() {
+		var i GetStockValuationRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.ProductName,
+			&i.StockQty,
+			&i.ReservedQty,
+			&i.CostPrice,
+			&i.StockValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listInventoryAdjustments = `-- name: ListInventoryAdjustments :many
 SELECT id, inventory_item_id, tenant_id, restaurant_id, order_id, adjustment_type, qty_before, qty_change, qty_after, cost_price, note, adjusted_by, created_at FROM inventory_adjustments
 WHERE inventory_item_id = $1 AND tenant_id = $2
@@ -415,6 +497,54 @@ func (q *Queries) ListInventoryByRestaurant(ctx context.Context, arg ListInvento
 	return items, nil
 }
 
+const listInventoryConsumption = `-- name: ListInventoryConsumption :many
+SELECT oi.product_id, SUM(oi.quantity)::BIGINT AS units_sold
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE oi.restaurant_id = $1 AND oi.tenant_id = $2
+  AND oi.product_id = ANY($3::UUID[])
+  AND oi.created_at >= $4
+  AND o.status NOT IN ('cancelled', 'rejected')
+GROUP BY oi.product_id
+`
+
+type ListInventoryConsumptionParams struct {
+	RestaurantID uuid.UUID   `json:"restaurant_id"`
+	TenantID     uuid.UUID   `json:"tenant_id"`
+	ProductIds   []uuid.UUID `json:"product_ids"`
+	Since        time.Time   `json:"since"`
+}
+
+type ListInventoryConsumptionRow struct {
+	ProductID uuid.UUID `json:"product_id"`
+	UnitsSold int64     `json:"units_sold"`
+}
+
+func (q *Queries) ListInventoryConsumption(ctx context.Context, arg ListInventoryConsumptionParams) ([]ListInventoryConsumptionRow, error) {
+	rows, err := q.db.Query(ctx, listInventoryConsumption,
+		arg.RestaurantID,
+		arg.TenantID,
+		arg.ProductIds,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListInventoryConsumptionRow{}
+	for rows.Next() {
+		var i ListInventoryConsumptionRow
+		if err := rows.Scan(&i.ProductID, &i.UnitsSold); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLowStock = `-- name: ListLowStock :many
 SELECT id, product_id, restaurant_id, tenant_id, stock_qty, reserved_qty, cost_price, reorder_threshold, last_restocked_at, updated_at FROM inventory_items
 WHERE tenant_id = $1 AND restaurant_id = $2
@@ -466,6 +596,45 @@ func (q *Queries) ListLowStock(ctx context.Context, arg ListLowStockParams) ([]I
 	return items, nil
 }
 
+const receiveStock = `-- name: ReceiveStock :one
+UPDATE inventory_items
+SET stock_qty = stock_qty + $1::INT,
+    cost_price = $2::NUMERIC,
+    last_restocked_at = NOW()
+WHERE id = $3 AND tenant_id = $4
+RETURNING id, product_id, restaurant_id, tenant_id, stock_qty, reserved_qty, cost_price, reorder_threshold, last_restocked_at, updated_at
+`
+
+type ReceiveStockParams struct {
+	Qty       int32          `json:"qty"`
+	CostPrice pgtype.Numeric `json:"cost_price"`
+	ID        uuid.UUID      `json:"id"`
+	TenantID  uuid.UUID      `json:"tenant_id"`
+}
+
+func (q *Queries) ReceiveStock(ctx context.Context, arg ReceiveStockParams) (InventoryItem, error) {
+	row := q.db.QueryRow(ctx, receiveStock,
+		arg.Qty,
+		arg.CostPrice,
+		arg.ID,
+		arg.TenantID,
+	)
+	var i InventoryItem
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.RestaurantID,
+		&i.TenantID,
+		&i.StockQty,
+		&i.ReservedQty,
+		&i.CostPrice,
+		&i.ReorderThreshold,
+		&i.LastRestockedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const releaseStock = `-- name: ReleaseStock :one
 UPDATE inventory_items
 SET reserved_qty = reserved_qty - $1::INT
@@ -543,3 +712,26 @@ func (q *Queries) ReserveStock(ctx context.Context, arg ReserveStockParams) (Inv
 	)
 	return i, err
 }
+
+const updateProductStockAvailability = `-- name: UpdateProductStockAvailability :exec
+UPDATE products SET availability = $1
+WHERE id = $2 AND tenant_id = $3
+  AND availability = $4
+`
+
+type UpdateProductStockAvailabilityParams struct {
+	Availability        ProductAvail `json:"availability"`
+	ID                  uuid.UUID    `json:"id"`
+	TenantID            uuid.UUID    `json:"tenant_id"`
+	CurrentAvailability ProductAvail `json:"current_availability"`
+}
+
+func (q *Queries) UpdateProductStockAvailability(ctx context.Context, arg UpdateProductStockAvailabilityParams) error {
+	_, err := q.db.Exec(ctx, updateProductStockAvailability,
+		arg.Availability,
+		arg.ID,
+		arg.TenantID,
+		arg.CurrentAvailability,
+	)
+	return err
+}
@@ -44,20 +44,25 @@ func (q *Queries) CountInvoicesByTenant(ctx context.Context, tenantID uuid.UUID)
 const countOrdersByRestaurantAndPeriod = `-- name: CountOrdersByRestaurantAndPeriod :one
 SELECT
     COUNT(*)::INT AS total_orders,
-    COUNT(CASE WHEN status = 'delivered' THEN 1 END)::INT AS delivered_orders,
-    COUNT(CASE WHEN status = 'cancelled' THEN 1 END)::INT AS cancelled_orders,
-    COUNT(CASE WHEN status = 'rejected' THEN 1 END)::INT AS rejected_orders
-FROM orders
-WHERE tenant_id = $1
-  AND deleted_at IS NULL
-  AND created_at >= $2::timestamptz
-  AND created_at < $3::timestamptz
+    COUNT(CASE WHEN o.status = 'delivered' THEN 1 END)::INT AS delivered_orders,
+    COUNT(CASE WHEN o.status = 'cancelled' THEN 1 END)::INT AS cancelled_orders,
+    COUNT(CASE WHEN o.status = 'rejected' THEN 1 END)::INT AS rejected_orders
+FROM orders o
+WHERE o.tenant_id = $1
+  AND o.deleted_at IS NULL
+  AND o.created_at >= $2::timestamptz
+  AND o.created_at < $3::timestamptz
+  AND EXISTS (
+      SELECT 1 FROM order_pickups op
+      WHERE op.order_id = o.id AND op.restaurant_id = $4
+  )
 `
 
 type CountOrdersByRestaurantAndPeriodParams struct {
-	TenantID    uuid.UUID `json:"tenant_id"`
-	PeriodStart time.Time `json:"period_start"`
-	PeriodEnd   time.Time `json:"period_end"`
+	TenantID     uuid.UUID `json:"tenant_id"`
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	RestaurantID uuid.UUID `json:"restaurant_id"`
 }
 
 type CountOrdersByRestaurantAndPeriodRow struct {
@@ -68,7 +73,12 @@ type CountOrdersByRestaurantAndPeriodRow struct {
 }
 
 func (q *Queries) CountOrdersByRestaurantAndPeriod(ctx context.Context, arg CountOrdersByRestaurantAndPeriodParams) (CountOrdersByRestaurantAndPeriodRow, error) {
-	row := q.db.QueryRow(ctx, countOrdersByRestaurantAndPeriod, arg.TenantID, arg.PeriodStart, arg.PeriodEnd)
+	row := q.db.QueryRow(ctx, countOrdersByRestaurantAndPeriod,
+		arg.TenantID,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.RestaurantID,
+	)
 	var i CountOrdersByRestaurantAndPeriodRow
 	err := row.Scan(
 		&i.TotalOrders,
@@ -85,10 +95,10 @@ INSERT INTO invoices (
     gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected,
     commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note,
     net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders,
-    status, generated_by, notes
+    status, generated_by, notes, refund_amount
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23
-) RETURNING id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
+) RETURNING id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount
 `
 
 type CreateInvoiceParams struct {
@@ -115,6 +125,7 @@ type CreateInvoiceParams struct {
 	Status               InvoiceStatus  `json:"status"`
 	GeneratedBy          pgtype.UUID    `json:"generated_by"`
 	Notes                sql.NullString `json:"notes"`
+	RefundAmount         pgtype.Numeric `json:"refund_amount"`
 }
 
 func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error) {
@@ -142,6 +153,7 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		arg.Status,
 		arg.GeneratedBy,
 		arg.Notes,
+		arg.RefundAmount,
 	)
 	var i Invoice
 	err := row.Scan(
@@ -176,6 +188,7 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundAmount,
 	)
 	return i, err
 }
@@ -183,7 +196,7 @@ func (q *Queries) CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (I
 const finalizeInvoice = `-- name: FinalizeInvoice :one
 UPDATE invoices SET status = 'finalized', finalized_by = $3, finalized_at = NOW(), notes = COALESCE($4, notes)
 WHERE id = $1 AND tenant_id = $2 AND status = 'draft'
-RETURNING id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at
+RETURNING id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount
 `
 
 type FinalizeInvoiceParams struct {
@@ -233,6 +246,7 @@ func (q *Queries) FinalizeInvoice(ctx context.Context, arg FinalizeInvoiceParams
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundAmount,
 	)
 	return i, err
 }
@@ -272,7 +286,7 @@ func (q *Queries) GetFinanceSummary(ctx context.Context, tenantID uuid.UUID) (Ge
 }
 
 const getInvoiceByID = `-- name: GetInvoiceByID :one
-SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at FROM invoices WHERE id = $1 AND tenant_id = $2 LIMIT 1
+SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount FROM invoices WHERE id = $1 AND tenant_id = $2 LIMIT 1
 `
 
 type GetInvoiceByIDParams struct {
@@ -315,12 +329,13 @@ func (q *Queries) GetInvoiceByID(ctx context.Context, arg GetInvoiceByIDParams)
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundAmount,
 	)
 	return i, err
 }
 
 const getInvoiceByPeriod = `-- name: GetInvoiceByPeriod :one
-SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at FROM invoices
+SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount FROM invoices
 WHERE tenant_id = $1 AND restaurant_id = $2 AND period_start = $3 AND period_end = $4
 LIMIT 1
 `
@@ -372,74 +387,11 @@ func (q *Queries) GetInvoiceByPeriod(ctx context.Context, arg GetInvoiceByPeriod
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundAmount,
 	)
 	return i, err
 }
 
-const getOrderPickupsByRestaurantAndPeriod = `-- name: GetOrderPickupsByRestaurantAndPeriod :many
-SELECT op.id, op.order_id, op.restaurant_id, op.tenant_id, op.pickup_number, op.status, op.items_subtotal, op.items_discount, op.items_vat, op.items_total, op.commission_rate, op.commission_amount, op.confirmed_at, op.preparing_at, op.ready_at, op.picked_at, op.rejected_at, op.rejection_reason, op.created_at, op.updated_at FROM order_pickups op
-JOIN orders o ON op.order_id = o.id
-WHERE o.tenant_id = $1
-  AND op.restaurant_id = $2
-  AND o.status = 'delivered'
-  AND o.deleted_at IS NULL
-  AND o.delivered_at >= $3::timestamptz
-  AND o.delivered_at < $4::timestamptz
-`
-
-type GetOrderPickupsByRestaurantAndPeriodParams struct {
-	TenantID     uuid.UUID `json:"tenant_id"`
-	RestaurantID uuid.UUID `json:"restaurant_id"`
-	PeriodStart  time.Time `json:"period_start"`
-	PeriodEnd    time.Time `json:"period_end"`
-}
-
-func (q *Queries) GetOrderPickupsByRestaurantAndPeriod(ctx context.Context, arg GetOrderPickupsByRestaurantAndPeriodParams) ([]OrderPickup, error) {
-	rows, err := q.db.Query(ctx, getOrderPickupsByRestaurantAndPeriod,
-		arg.TenantID,
-		arg.RestaurantID,
-		arg.PeriodStart,
-		arg.PeriodEnd,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []OrderPickup{}
-	for rows.Next() {
-		var i OrderPickup
-		if err := rows.Scan(
-			&i.ID,
-			&i.OrderID,
-			&i.RestaurantID,
-			&i.TenantID,
-			&i.PickupNumber,
-			&i.Status,
-			&i.ItemsSubtotal,
-			&i.ItemsDiscount,
-			&i.ItemsVat,
-			&i.ItemsTotal,
-			&i.CommissionRate,
-			&i.CommissionAmount,
-			&i.ConfirmedAt,
-			&i.PreparingAt,
-			&i.ReadyAt,
-			&i.PickedAt,
-			&i.RejectedAt,
-			&i.RejectionReason,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const listActiveRestaurantsByTenant = `-- name: ListActiveRestaurantsByTenant :many
 SELECT id, tenant_id, name FROM restaurants
 WHERE tenant_id = $1 AND deleted_at IS NULL AND is_active = true
@@ -472,7 +424,7 @@ func (q *Queries) ListActiveRestaurantsByTenant(ctx context.Context, tenantID uu
 }
 
 const listInvoicesByRestaurant = `-- name: ListInvoicesByRestaurant :many
-SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at FROM invoices
+SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount FROM invoices
 WHERE restaurant_id = $1 AND tenant_id = $2
 ORDER BY period_end DESC
 LIMIT $3 OFFSET $4
@@ -531,6 +483,7 @@ func (q *Queries) ListInvoicesByRestaurant(ctx context.Context, arg ListInvoices
 			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RefundAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -543,7 +496,7 @@ func (q *Queries) ListInvoicesByRestaurant(ctx context.Context, arg ListInvoices
 }
 
 const listInvoicesByTenant = `-- name: ListInvoicesByTenant :many
-SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at FROM invoices
+SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount FROM invoices
 WHERE tenant_id = $1
 ORDER BY period_end DESC
 LIMIT $2 OFFSET $3
@@ -596,6 +549,135 @@ func (q *Queries) ListInvoicesByTenant(ctx context.Context, arg ListInvoicesByTe
 			&i.Notes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RefundAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoicesDueForFinalize = `-- name: ListInvoicesDueForFinalize :many
+SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount FROM invoices
+WHERE tenant_id = $1 AND status = 'draft' AND created_at < $2
+ORDER BY created_at
+`
+
+type ListInvoicesDueForFinalizeParams struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Before   time.Time `json:"before"`
+}
+
+func (q *Queries) ListInvoicesDueForFinalize(ctx context.Context, arg ListInvoicesDueForFinalizeParams) ([]Invoice, error) {
+	rows, err := q.db.Query(ctx, listInvoicesDueForFinalize, arg.TenantID, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Invoice{}
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.RestaurantID,
+			&i.InvoiceNumber,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.GrossSales,
+			&i.ItemDiscounts,
+			&i.VendorPromoDiscounts,
+			&i.NetSales,
+			&i.VatCollected,
+			&i.CommissionRate,
+			&i.CommissionAmount,
+			&i.PenaltyAmount,
+			&i.AdjustmentAmount,
+			&i.AdjustmentNote,
+			&i.NetPayable,
+			&i.TotalOrders,
+			&i.DeliveredOrders,
+			&i.CancelledOrders,
+			&i.RejectedOrders,
+			&i.Status,
+			&i.GeneratedBy,
+			&i.FinalizedBy,
+			&i.FinalizedAt,
+			&i.PaidBy,
+			&i.PaidAt,
+			&i.PaymentReference,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefundAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInvoicesReadyForPayout = `-- name: ListInvoicesReadyForPayout :many
+SELECT id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount FROM invoices i
+WHERE i.tenant_id = $1
+  AND i.status = 'finalized'
+  AND NOT EXISTS (
+      SELECT 1 FROM vendor_payouts p
+      WHERE p.invoice_id = i.id AND p.status <> 'failed'
+  )
+ORDER BY i.period_end, i.restaurant_id
+`
+
+func (q *Queries) ListInvoicesReadyForPayout(ctx context.Context, tenantID uuid.UUID) ([]Invoice, error) {
+	rows, err := q.db.Query(ctx, listInvoicesReadyForPayout, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Invoice{}
+	for rows.Next() {
+		var i Invoice
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.RestaurantID,
+			&i.InvoiceNumber,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.GrossSales,
+			&i.ItemDiscounts,
+			&i.VendorPromoDiscounts,
+			&i.NetSales,
+			&i.VatCollected,
+			&i.CommissionRate,
+			&i.CommissionAmount,
+			&i.PenaltyAmount,
+			&i.AdjustmentAmount,
+			&i.AdjustmentNote,
+			&i.NetPayable,
+			&i.TotalOrders,
+			&i.DeliveredOrders,
+			&i.CancelledOrders,
+			&i.RejectedOrders,
+			&i.Status,
+			&i.GeneratedBy,
+			&i.FinalizedBy,
+			&i.FinalizedAt,
+			&i.PaidBy,
+			&i.PaidAt,
+			&i.PaymentReference,
+			&i.Notes,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefundAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -719,10 +801,58 @@ func (q *Queries) ListOrderItemsByRestaurantAndPeriod(ctx context.Context, arg L
 	return items, nil
 }
 
+const listRestaurantsToInvoice = `-- name: ListRestaurantsToInvoice :many
+SELECT r.id FROM restaurants r
+WHERE r.tenant_id = $1
+  AND (
+      EXISTS (
+          SELECT 1 FROM order_pickups op
+          JOIN orders o ON o.id = op.order_id
+          WHERE op.restaurant_id = r.id
+            AND o.status = 'delivered'
+            AND o.delivered_at >= $2::timestamptz
+            AND o.delivered_at < $3::timestamptz
+      )
+      OR EXISTS (
+          SELECT 1 FROM invoice_adjustments a
+          WHERE a.restaurant_id = r.id
+            AND a.invoice_id IS NULL
+            AND a.created_at < $3::timestamptz
+      )
+  )
+ORDER BY r.name
+`
+
+type ListRestaurantsToInvoiceParams struct {
+	TenantID    uuid.UUID `json:"tenant_id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+func (q *Queries) ListRestaurantsToInvoice(ctx context.Context, arg ListRestaurantsToInvoiceParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listRestaurantsToInvoice, arg.TenantID, arg.PeriodStart, arg.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markInvoicePaid = `-- name: MarkInvoicePaid :one
 UPDATE invoices SET status = 'paid', paid_by = $3, paid_at = NOW(), payment_reference = $4, notes = COALESCE($5, notes)
 WHERE id = $1 AND tenant_id = $2 AND status = 'finalized'
-RETURNING id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at
+RETURNING id, tenant_id, restaurant_id, invoice_number, period_start, period_end, gross_sales, item_discounts, vendor_promo_discounts, net_sales, vat_collected, commission_rate, commission_amount, penalty_amount, adjustment_amount, adjustment_note, net_payable, total_orders, delivered_orders, cancelled_orders, rejected_orders, status, generated_by, finalized_by, finalized_at, paid_by, paid_at, payment_reference, notes, created_at, updated_at, refund_amount
 `
 
 type MarkInvoicePaidParams struct {
@@ -774,6 +904,7 @@ func (q *Queries) MarkInvoicePaid(ctx context.Context, arg MarkInvoicePaidParams
 		&i.Notes,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundAmount,
 	)
 	return i, err
 }
@@ -322,6 +322,138 @@ func (ns NullInventoryAdjustmentReason) Value() (driver.Value, error) {
 	return string(ns.InventoryAdjustmentReason), nil
 }
 
+type InvoiceAdjustmentKind string
+
+const (
+	InvoiceAdjustmentKindPenalty      InvoiceAdjustmentKind = "penalty"
+	InvoiceAdjustmentKindManual       InvoiceAdjustmentKind = "manual"
+	InvoiceAdjustmentKindRefund       InvoiceAdjustmentKind = "refund"
+	InvoiceAdjustmentKindCreditNote   InvoiceAdjustmentKind = "credit_note"
+	InvoiceAdjustmentKindDebitNote    InvoiceAdjustmentKind = "debit_note"
+	InvoiceAdjustmentKindCarryForward InvoiceAdjustmentKind = "carry_forward"
+)
+
+func (e *InvoiceAdjustmentKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InvoiceAdjustmentKind(s)
+	case string:
+		*e = InvoiceAdjustmentKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InvoiceAdjustmentKind: %T", src)
+	}
+	return nil
+}
+
+type NullInvoiceAdjustmentKind struct {
+	InvoiceAdjustmentKind InvoiceAdjustmentKind `json:"invoice_adjustment_kind"`
+	Valid                 bool                  `json:"valid"` // Valid is true if InvoiceAdjustmentKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInvoiceAdjustmentKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.InvoiceAdjustmentKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InvoiceAdjustmentKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInvoiceAdjustmentKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InvoiceAdjustmentKind), nil
+}
+
+type InvoiceDisputeStatus string
+
+const (
+	InvoiceDisputeStatusOpen        InvoiceDisputeStatus = "open"
+	InvoiceDisputeStatusUnderReview InvoiceDisputeStatus = "under_review"
+	InvoiceDisputeStatusResolved    InvoiceDisputeStatus = "resolved"
+	InvoiceDisputeStatusRejected    InvoiceDisputeStatus = "rejected"
+)
+
+func (e *InvoiceDisputeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InvoiceDisputeStatus(s)
+	case string:
+		*e = InvoiceDisputeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InvoiceDisputeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullInvoiceDisputeStatus struct {
+	InvoiceDisputeStatus InvoiceDisputeStatus `json:"invoice_dispute_status"`
+	Valid                bool                 `json:"valid"` // Valid is true if InvoiceDisputeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInvoiceDisputeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.InvoiceDisputeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InvoiceDisputeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInvoiceDisputeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InvoiceDisputeStatus), nil
+}
+
+type InvoiceNoteType string
+
+const (
+	InvoiceNoteTypeCredit InvoiceNoteType = "credit"
+	InvoiceNoteTypeDebit  InvoiceNoteType = "debit"
+)
+
+func (e *InvoiceNoteType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InvoiceNoteType(s)
+	case string:
+		*e = InvoiceNoteType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InvoiceNoteType: %T", src)
+	}
+	return nil
+}
+
+type NullInvoiceNoteType struct {
+	InvoiceNoteType InvoiceNoteType `json:"invoice_note_type"`
+	Valid           bool            `json:"valid"` // Valid is true if InvoiceNoteType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInvoiceNoteType) Scan(value interface{}) error {
+	if value == nil {
+		ns.InvoiceNoteType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InvoiceNoteType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInvoiceNoteType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InvoiceNoteType), nil
+}
+
 type InvoiceStatus string
 
 const (
@@ -416,6 +548,7 @@ const (
 	IssueTypeQualityIssue IssueType = "quality_issue"
 	IssueTypeLateDelivery IssueType = "late_delivery"
 	IssueTypeOther        IssueType = "other"
+	IssueTypeNotDelivered IssueType = "not_delivered"
 )
 
 func (e *IssueType) Scan(src interface{}) error {
@@ -500,15 +633,18 @@ func (ns NullLedgerAccountType) Value() (driver.Value, error) {
 type LedgerEntryType string
 
 const (
-	LedgerEntryTypeOrderRevenue LedgerEntryType = "order_revenue"
-	LedgerEntryTypeCommission   LedgerEntryType = "commission"
-	LedgerEntryTypeRefund       LedgerEntryType = "refund"
-	LedgerEntryTypeWalletCredit LedgerEntryType = "wallet_credit"
-	LedgerEntryTypeWalletDebit  LedgerEntryType = "wallet_debit"
-	LedgerEntryTypeVendorPayout LedgerEntryType = "vendor_payout"
-	LedgerEntryTypeDeliveryFee  LedgerEntryType = "delivery_fee"
-	LedgerEntryTypePenalty      LedgerEntryType = "penalty"
-	LedgerEntryTypeAdjustment   LedgerEntryType = "adjustment"
+	LedgerEntryTypeOrderRevenue  LedgerEntryType = "order_revenue"
+	LedgerEntryTypeCommission    LedgerEntryType = "commission"
+	LedgerEntryTypeRefund        LedgerEntryType = "refund"
+	LedgerEntryTypeWalletCredit  LedgerEntryType = "wallet_credit"
+	LedgerEntryTypeWalletDebit   LedgerEntryType = "wallet_debit"
+	LedgerEntryTypeVendorPayout  LedgerEntryType = "vendor_payout"
+	LedgerEntryTypeDeliveryFee   LedgerEntryType = "delivery_fee"
+	LedgerEntryTypePenalty       LedgerEntryType = "penalty"
+	LedgerEntryTypeAdjustment    LedgerEntryType = "adjustment"
+	LedgerEntryTypeCodCollection LedgerEntryType = "cod_collection"
+	LedgerEntryTypeCashDeposit   LedgerEntryType = "cash_deposit"
+	LedgerEntryTypeRiderPayout   LedgerEntryType = "rider_payout"
 )
 
 func (e *LedgerEntryType) Scan(src interface{}) error {
@@ -1255,6 +1391,91 @@ func (ns NullPromoFunder) Value() (driver.Value, error) {
 	return string(ns.PromoFunder), nil
 }
 
+type PromoRule string
+
+const (
+	PromoRuleStandard PromoRule = "standard"
+	PromoRuleBogo     PromoRule = "bogo"
+	PromoRuleTiered   PromoRule = "tiered"
+)
+
+func (e *PromoRule) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromoRule(s)
+	case string:
+		*e = PromoRule(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromoRule: %T", src)
+	}
+	return nil
+}
+
+type NullPromoRule struct {
+	PromoRule PromoRule `json:"promo_rule"`
+	Valid     bool      `json:"valid"` // Valid is true if PromoRule is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromoRule) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromoRule, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromoRule.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromoRule) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromoRule), nil
+}
+
+type PromoStacking string
+
+const (
+	PromoStackingExclusive PromoStacking = "exclusive"
+	PromoStackingStackable PromoStacking = "stackable"
+)
+
+func (e *PromoStacking) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PromoStacking(s)
+	case string:
+		*e = PromoStacking(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PromoStacking: %T", src)
+	}
+	return nil
+}
+
+type NullPromoStacking struct {
+	PromoStacking PromoStacking `json:"promo_stacking"`
+	Valid         bool          `json:"valid"` // Valid is true if PromoStacking is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPromoStacking) Scan(value interface{}) error {
+	if value == nil {
+		ns.PromoStacking, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PromoStacking.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPromoStacking) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PromoStacking), nil
+}
+
 type PromoType string
 
 const (
@@ -1297,6 +1518,51 @@ func (ns NullPromoType) Value() (driver.Value, error) {
 	return string(ns.PromoType), nil
 }
 
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+func (e *PurchaseOrderStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PurchaseOrderStatus(s)
+	case string:
+		*e = PurchaseOrderStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PurchaseOrderStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPurchaseOrderStatus struct {
+	PurchaseOrderStatus PurchaseOrderStatus `json:"purchase_order_status"`
+	Valid               bool                `json:"valid"` // Valid is true if PurchaseOrderStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPurchaseOrderStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PurchaseOrderStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PurchaseOrderStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPurchaseOrderStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PurchaseOrderStatus), nil
+}
+
 type RefundStatus string
 
 const (
@@ -1881,6 +2147,40 @@ type Category struct {
 	UpdatedAt            time.Time      `json:"updated_at"`
 }
 
+type CodCollection struct {
+	ID              uuid.UUID      `json:"id"`
+	TenantID        uuid.UUID      `json:"tenant_id"`
+	OrderID         uuid.UUID      `json:"order_id"`
+	RiderID         uuid.UUID      `json:"rider_id"`
+	HubID           pgtype.UUID    `json:"hub_id"`
+	ExpectedAmount  pgtype.Numeric `json:"expected_amount"`
+	CollectedAmount pgtype.Numeric `json:"collected_amount"`
+	Variance        pgtype.Numeric `json:"variance"`
+	Note            sql.NullString `json:"note"`
+	CollectedAt     time.Time      `json:"collected_at"`
+}
+
+type DeliveryProof struct {
+	ID                 uuid.UUID          `json:"id"`
+	OrderID            uuid.UUID          `json:"order_id"`
+	TenantID           uuid.UUID          `json:"tenant_id"`
+	RiderID            pgtype.UUID        `json:"rider_id"`
+	PodRequired        bool               `json:"pod_required"`
+	OtpCode            sql.NullString     `json:"otp_code"`
+	OtpAttempts        int32              `json:"otp_attempts"`
+	OtpVerifiedAt      pgtype.Timestamptz `json:"otp_verified_at"`
+	PhotoUrl           sql.NullString     `json:"photo_url"`
+	RiderGeoLat        pgtype.Numeric     `json:"rider_geo_lat"`
+	RiderGeoLng        pgtype.Numeric     `json:"rider_geo_lng"`
+	LocationRecordedAt pgtype.Timestamptz `json:"location_recorded_at"`
+	DistanceMeters     pgtype.Numeric     `json:"distance_meters"`
+	GeofenceRadiusM    *int32             `json:"geofence_radius_m"`
+	WithinGeofence     *bool              `json:"within_geofence"`
+	DeliveredAt        pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
 type DeliveryZoneConfig struct {
 	ID                    uuid.UUID       `json:"id"`
 	TenantID              uuid.UUID       `json:"tenant_id"`
@@ -1889,6 +2189,10 @@ type DeliveryZoneConfig struct {
 	FreeDeliveryThreshold pgtype.Numeric  `json:"free_delivery_threshold"`
 	CreatedAt             time.Time       `json:"created_at"`
 	UpdatedAt             time.Time       `json:"updated_at"`
+	PodOtpEnabled         bool            `json:"pod_otp_enabled"`
+	PodPhotoRequired      bool            `json:"pod_photo_required"`
+	PodGeofenceRadiusM    *int32          `json:"pod_geofence_radius_m"`
+	PodMinOrderValue      pgtype.Numeric  `json:"pod_min_order_value"`
 }
 
 type HomepageSection struct {
@@ -1907,22 +2211,24 @@ type HomepageSection struct {
 }
 
 type Hub struct {
-	ID           uuid.UUID      `json:"id"`
-	TenantID     uuid.UUID      `json:"tenant_id"`
-	Name         string         `json:"name"`
-	Code         sql.NullString `json:"code"`
-	ManagerID    pgtype.UUID    `json:"manager_id"`
-	AddressLine1 sql.NullString `json:"address_line1"`
-	AddressLine2 sql.NullString `json:"address_line2"`
-	City         string         `json:"city"`
-	GeoLat       pgtype.Numeric `json:"geo_lat"`
-	GeoLng       pgtype.Numeric `json:"geo_lng"`
-	ContactPhone sql.NullString `json:"contact_phone"`
-	ContactEmail sql.NullString `json:"contact_email"`
-	IsActive     bool           `json:"is_active"`
-	SortOrder    int32          `json:"sort_order"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
+	ID             uuid.UUID      `json:"id"`
+	TenantID       uuid.UUID      `json:"tenant_id"`
+	Name           string         `json:"name"`
+	Code           sql.NullString `json:"code"`
+	ManagerID      pgtype.UUID    `json:"manager_id"`
+	AddressLine1   sql.NullString `json:"address_line1"`
+	AddressLine2   sql.NullString `json:"address_line2"`
+	City           string         `json:"city"`
+	GeoLat         pgtype.Numeric `json:"geo_lat"`
+	GeoLng         pgtype.Numeric `json:"geo_lng"`
+	ContactPhone   sql.NullString `json:"contact_phone"`
+	ContactEmail   sql.NullString `json:"contact_email"`
+	IsActive       bool           `json:"is_active"`
+	SortOrder      int32          `json:"sort_order"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	RiderCashLimit pgtype.Numeric `json:"rider_cash_limit"`
+	CheckinRadiusM *int32         `json:"checkin_radius_m"`
 }
 
 type HubCoverageArea struct {
@@ -2013,6 +2319,98 @@ type Invoice struct {
 	Notes                sql.NullString     `json:"notes"`
 	CreatedAt            time.Time          `json:"created_at"`
 	UpdatedAt            time.Time          `json:"updated_at"`
+	RefundAmount         pgtype.Numeric     `json:"refund_amount"`
+}
+
+type InvoiceAdjustment struct {
+	ID           uuid.UUID             `json:"id"`
+	TenantID     uuid.UUID             `json:"tenant_id"`
+	RestaurantID uuid.UUID             `json:"restaurant_id"`
+	OrderID      pgtype.UUID           `json:"order_id"`
+	IssueID      pgtype.UUID           `json:"issue_id"`
+	Amount       pgtype.Numeric        `json:"amount"`
+	Reason       string                `json:"reason"`
+	InvoiceID    pgtype.UUID           `json:"invoice_id"`
+	CreatedBy    pgtype.UUID           `json:"created_by"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+	Kind         InvoiceAdjustmentKind `json:"kind"`
+	RefundID     pgtype.UUID           `json:"refund_id"`
+}
+
+type InvoiceDispute struct {
+	ID             uuid.UUID            `json:"id"`
+	TenantID       uuid.UUID            `json:"tenant_id"`
+	InvoiceID      uuid.UUID            `json:"invoice_id"`
+	RestaurantID   uuid.UUID            `json:"restaurant_id"`
+	LineItemID     pgtype.UUID          `json:"line_item_id"`
+	Reason         string               `json:"reason"`
+	DisputedAmount pgtype.Numeric       `json:"disputed_amount"`
+	Status         InvoiceDisputeStatus `json:"status"`
+	RaisedBy       uuid.UUID            `json:"raised_by"`
+	ResolutionNote sql.NullString       `json:"resolution_note"`
+	ResolvedBy     pgtype.UUID          `json:"resolved_by"`
+	ResolvedAt     pgtype.Timestamptz   `json:"resolved_at"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+}
+
+type InvoiceDisputeComment struct {
+	ID          uuid.UUID `json:"id"`
+	DisputeID   uuid.UUID `json:"dispute_id"`
+	TenantID    uuid.UUID `json:"tenant_id"`
+	AuthorID    uuid.UUID `json:"author_id"`
+	Message     string    `json:"message"`
+	Attachments []string  `json:"attachments"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type InvoiceLineItem struct {
+	ID                  uuid.UUID      `json:"id"`
+	TenantID            uuid.UUID      `json:"tenant_id"`
+	InvoiceID           uuid.UUID      `json:"invoice_id"`
+	LineType            string         `json:"line_type"`
+	OrderID             pgtype.UUID    `json:"order_id"`
+	AdjustmentID        pgtype.UUID    `json:"adjustment_id"`
+	Description         string         `json:"description"`
+	GrossSales          pgtype.Numeric `json:"gross_sales"`
+	ItemDiscount        pgtype.Numeric `json:"item_discount"`
+	VendorPromoDiscount pgtype.Numeric `json:"vendor_promo_discount"`
+	NetSales            pgtype.Numeric `json:"net_sales"`
+	Vat                 pgtype.Numeric `json:"vat"`
+	CommissionRate      pgtype.Numeric `json:"commission_rate"`
+	CommissionAmount    pgtype.Numeric `json:"commission_amount"`
+	Amount              pgtype.Numeric `json:"amount"`
+	OccurredAt          time.Time      `json:"occurred_at"`
+	CreatedAt           time.Time      `json:"created_at"`
+}
+
+type InvoiceNote struct {
+	ID           uuid.UUID       `json:"id"`
+	TenantID     uuid.UUID       `json:"tenant_id"`
+	InvoiceID    uuid.UUID       `json:"invoice_id"`
+	RestaurantID uuid.UUID       `json:"restaurant_id"`
+	DisputeID    pgtype.UUID     `json:"dispute_id"`
+	NoteType     InvoiceNoteType `json:"note_type"`
+	NoteNumber   string          `json:"note_number"`
+	Amount       pgtype.Numeric  `json:"amount"`
+	Reason       string          `json:"reason"`
+	AdjustmentID uuid.UUID       `json:"adjustment_id"`
+	IssuedBy     uuid.UUID       `json:"issued_by"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type InvoiceNoteSerial struct {
+	TenantID   uuid.UUID       `json:"tenant_id"`
+	NoteType   InvoiceNoteType `json:"note_type"`
+	LastSerial int32           `json:"last_serial"`
+}
+
+type JobWatermark struct {
+	Job           string    `json:"job"`
+	LastUpdatedAt time.Time `json:"last_updated_at"`
+	LastID        uuid.UUID `json:"last_id"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 type LedgerAccount struct {
@@ -2122,6 +2520,7 @@ type Order struct {
 	CreatedAt                time.Time          `json:"created_at"`
 	UpdatedAt                time.Time          `json:"updated_at"`
 	DeletedAt                pgtype.Timestamptz `json:"deleted_at"`
+	RiderTip                 pgtype.Numeric     `json:"rider_tip"`
 }
 
 type OrderAnalytic struct {
@@ -2376,7 +2775,7 @@ type ProductModifierOption struct {
 type Promo struct {
 	ID                 uuid.UUID          `json:"id"`
 	TenantID           uuid.UUID          `json:"tenant_id"`
-	Code               string             `json:"code"`
+	Code               sql.NullString     `json:"code"`
 	Title              string             `json:"title"`
 	Description        sql.NullString     `json:"description"`
 	PromoType          PromoType          `json:"promo_type"`
@@ -2397,6 +2796,15 @@ type Promo struct {
 	CreatedBy          pgtype.UUID        `json:"created_by"`
 	CreatedAt          time.Time          `json:"created_at"`
 	UpdatedAt          time.Time          `json:"updated_at"`
+	RuleType           PromoRule          `json:"rule_type"`
+	BuyQty             *int32             `json:"buy_qty"`
+	GetQty             *int32             `json:"get_qty"`
+	FirstOrderOnly     bool               `json:"first_order_only"`
+	NewCustomerDays    *int32             `json:"new_customer_days"`
+	AutoApply          bool               `json:"auto_apply"`
+	Stacking           PromoStacking      `json:"stacking"`
+	BudgetCap          pgtype.Numeric     `json:"budget_cap"`
+	BudgetExhaustedAt  pgtype.Timestamptz `json:"budget_exhausted_at"`
 }
 
 type PromoCategoryRestriction struct {
@@ -2404,27 +2812,86 @@ type PromoCategoryRestriction struct {
 	CategoryID uuid.UUID `json:"category_id"`
 }
 
+type PromoProductRestriction struct {
+	PromoID   uuid.UUID `json:"promo_id"`
+	ProductID uuid.UUID `json:"product_id"`
+}
+
 type PromoRestaurantRestriction struct {
 	PromoID      uuid.UUID `json:"promo_id"`
 	RestaurantID uuid.UUID `json:"restaurant_id"`
 }
 
-type PromoUsage struct {
+type PromoTier struct {
 	ID             uuid.UUID      `json:"id"`
 	PromoID        uuid.UUID      `json:"promo_id"`
-	UserID         uuid.UUID      `json:"user_id"`
-	OrderID        uuid.UUID      `json:"order_id"`
-	TenantID       uuid.UUID      `json:"tenant_id"`
+	MinSubtotal    pgtype.Numeric `json:"min_subtotal"`
 	DiscountAmount pgtype.Numeric `json:"discount_amount"`
-	CashbackAmount pgtype.Numeric `json:"cashback_amount"`
 	CreatedAt      time.Time      `json:"created_at"`
 }
 
+type PromoTimeWindow struct {
+	ID          uuid.UUID `json:"id"`
+	PromoID     uuid.UUID `json:"promo_id"`
+	DayOfWeek   *int32    `json:"day_of_week"`
+	StartMinute int32     `json:"start_minute"`
+	EndMinute   int32     `json:"end_minute"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type PromoUsage struct {
+	ID             uuid.UUID          `json:"id"`
+	PromoID        uuid.UUID          `json:"promo_id"`
+	UserID         uuid.UUID          `json:"user_id"`
+	OrderID        uuid.UUID          `json:"order_id"`
+	TenantID       uuid.UUID          `json:"tenant_id"`
+	DiscountAmount pgtype.Numeric     `json:"discount_amount"`
+	CashbackAmount pgtype.Numeric     `json:"cashback_amount"`
+	CreatedAt      time.Time          `json:"created_at"`
+	FundedBy       PromoFunder        `json:"funded_by"`
+	ReversedAt     pgtype.Timestamptz `json:"reversed_at"`
+}
+
 type PromoUserEligibility struct {
 	PromoID uuid.UUID `json:"promo_id"`
 	UserID  uuid.UUID `json:"user_id"`
 }
 
+type PurchaseOrder struct {
+	ID           uuid.UUID           `json:"id"`
+	TenantID     uuid.UUID           `json:"tenant_id"`
+	RestaurantID uuid.UUID           `json:"restaurant_id"`
+	SupplierID   uuid.UUID           `json:"supplier_id"`
+	PoNumber     string              `json:"po_number"`
+	Status       PurchaseOrderStatus `json:"status"`
+	ExpectedAt   pgtype.Timestamptz  `json:"expected_at"`
+	TotalAmount  pgtype.Numeric      `json:"total_amount"`
+	Notes        sql.NullString      `json:"notes"`
+	CreatedBy    pgtype.UUID         `json:"created_by"`
+	SentAt       pgtype.Timestamptz  `json:"sent_at"`
+	ReceivedAt   pgtype.Timestamptz  `json:"received_at"`
+	CancelledAt  pgtype.Timestamptz  `json:"cancelled_at"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID              uuid.UUID      `json:"id"`
+	PurchaseOrderID uuid.UUID      `json:"purchase_order_id"`
+	TenantID        uuid.UUID      `json:"tenant_id"`
+	InventoryItemID uuid.UUID      `json:"inventory_item_id"`
+	ProductID       uuid.UUID      `json:"product_id"`
+	QtyOrdered      int32          `json:"qty_ordered"`
+	QtyReceived     int32          `json:"qty_received"`
+	UnitCost        pgtype.Numeric `json:"unit_cost"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type PurchaseOrderSerial struct {
+	TenantID   uuid.UUID `json:"tenant_id"`
+	LastSerial int32     `json:"last_serial"`
+}
+
 type RefreshToken struct {
 	ID         uuid.UUID          `json:"id"`
 	UserID     uuid.UUID          `json:"user_id"`
@@ -2454,6 +2921,63 @@ type Refund struct {
 	UpdatedAt       time.Time          `json:"updated_at"`
 }
 
+type ReportDelivery struct {
+	ID             uuid.UUID      `json:"id"`
+	SubscriptionID uuid.UUID      `json:"subscription_id"`
+	TenantID       uuid.UUID      `json:"tenant_id"`
+	ReportType     string         `json:"report_type"`
+	PeriodStart    time.Time      `json:"period_start"`
+	PeriodEnd      time.Time      `json:"period_end"`
+	Recipient      string         `json:"recipient"`
+	Status         string         `json:"status"`
+	Error          sql.NullString `json:"error"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type ReportExport struct {
+	ID                uuid.UUID          `json:"id"`
+	TenantID          uuid.UUID          `json:"tenant_id"`
+	RequestedBy       uuid.UUID          `json:"requested_by"`
+	RequesterRole     UserRole           `json:"requester_role"`
+	ReportType        string             `json:"report_type"`
+	Format            string             `json:"format"`
+	StartDate         pgtype.Date        `json:"start_date"`
+	EndDate           pgtype.Date        `json:"end_date"`
+	RestaurantIds     []uuid.UUID        `json:"restaurant_ids"`
+	EmailOnCompletion bool               `json:"email_on_completion"`
+	Status            string             `json:"status"`
+	Attempts          int32              `json:"attempts"`
+	Error             sql.NullString     `json:"error"`
+	FileKey           sql.NullString     `json:"file_key"`
+	FileName          sql.NullString     `json:"file_name"`
+	ContentType       sql.NullString     `json:"content_type"`
+	RowCount          *int32             `json:"row_count"`
+	SizeBytes         *int64             `json:"size_bytes"`
+	StartedAt         pgtype.Timestamptz `json:"started_at"`
+	CompletedAt       pgtype.Timestamptz `json:"completed_at"`
+	ExpiresAt         pgtype.Timestamptz `json:"expires_at"`
+	EmailedAt         pgtype.Timestamptz `json:"emailed_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+type ReportSubscription struct {
+	ID            uuid.UUID          `json:"id"`
+	TenantID      uuid.UUID          `json:"tenant_id"`
+	CreatedBy     uuid.UUID          `json:"created_by"`
+	ReportType    string             `json:"report_type"`
+	Frequency     string             `json:"frequency"`
+	SendHour      int16              `json:"send_hour"`
+	SendWeekday   int16              `json:"send_weekday"`
+	Recipients    []string           `json:"recipients"`
+	RestaurantIds []uuid.UUID        `json:"restaurant_ids"`
+	IsActive      bool               `json:"is_active"`
+	NextRunAt     time.Time          `json:"next_run_at"`
+	LastRunAt     pgtype.Timestamptz `json:"last_run_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
 type Restaurant struct {
 	ID                  uuid.UUID      `json:"id"`
 	TenantID            uuid.UUID      `json:"tenant_id"`
@@ -2498,6 +3022,7 @@ type Restaurant struct {
 	TotalOrderCount     int32          `json:"total_order_count"`
 	CreatedAt           time.Time      `json:"created_at"`
 	UpdatedAt           time.Time      `json:"updated_at"`
+	RequirePod          bool           `json:"require_pod"`
 }
 
 type RestaurantOperatingHour struct {
@@ -2510,6 +3035,20 @@ type RestaurantOperatingHour struct {
 	IsClosed     bool        `json:"is_closed"`
 }
 
+type RestaurantPayoutAccount struct {
+	RestaurantID  uuid.UUID      `json:"restaurant_id"`
+	TenantID      uuid.UUID      `json:"tenant_id"`
+	Method        string         `json:"method"`
+	AccountName   string         `json:"account_name"`
+	AccountNumber string         `json:"account_number"`
+	BankName      sql.NullString `json:"bank_name"`
+	BranchName    sql.NullString `json:"branch_name"`
+	RoutingNumber sql.NullString `json:"routing_number"`
+	UpdatedBy     pgtype.UUID    `json:"updated_by"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
 type RestaurantStaffAssignment struct {
 	ID           uuid.UUID   `json:"id"`
 	RestaurantID uuid.UUID   `json:"restaurant_id"`
@@ -2520,6 +3059,17 @@ type RestaurantStaffAssignment struct {
 	AssignedAt   time.Time   `json:"assigned_at"`
 }
 
+type RestaurantVatRegistration struct {
+	RestaurantID      uuid.UUID      `json:"restaurant_id"`
+	TenantID          uuid.UUID      `json:"tenant_id"`
+	Bin               string         `json:"bin"`
+	RegisteredName    string         `json:"registered_name"`
+	RegisteredAddress sql.NullString `json:"registered_address"`
+	UpdatedBy         pgtype.UUID    `json:"updated_by"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
 type Review struct {
 	ID                uuid.UUID          `json:"id"`
 	TenantID          uuid.UUID          `json:"tenant_id"`
@@ -2538,24 +3088,61 @@ type Review struct {
 }
 
 type Rider struct {
-	ID                  uuid.UUID      `json:"id"`
-	UserID              uuid.UUID      `json:"user_id"`
-	TenantID            uuid.UUID      `json:"tenant_id"`
-	HubID               pgtype.UUID    `json:"hub_id"`
-	VehicleType         VehicleType    `json:"vehicle_type"`
-	VehicleRegistration sql.NullString `json:"vehicle_registration"`
-	LicenseNumber       sql.NullString `json:"license_number"`
-	NidNumber           sql.NullString `json:"nid_number"`
-	NidVerified         bool           `json:"nid_verified"`
-	IsAvailable         bool           `json:"is_available"`
-	IsOnDuty            bool           `json:"is_on_duty"`
-	TotalOrderCount     int32          `json:"total_order_count"`
-	TotalEarnings       pgtype.Numeric `json:"total_earnings"`
-	PendingBalance      pgtype.Numeric `json:"pending_balance"`
-	RatingAvg           pgtype.Numeric `json:"rating_avg"`
-	RatingCount         int32          `json:"rating_count"`
-	CreatedAt           time.Time      `json:"created_at"`
-	UpdatedAt           time.Time      `json:"updated_at"`
+	ID                  uuid.UUID          `json:"id"`
+	UserID              uuid.UUID          `json:"user_id"`
+	TenantID            uuid.UUID          `json:"tenant_id"`
+	HubID               pgtype.UUID        `json:"hub_id"`
+	VehicleType         VehicleType        `json:"vehicle_type"`
+	VehicleRegistration sql.NullString     `json:"vehicle_registration"`
+	LicenseNumber       sql.NullString     `json:"license_number"`
+	NidNumber           sql.NullString     `json:"nid_number"`
+	NidVerified         bool               `json:"nid_verified"`
+	IsAvailable         bool               `json:"is_available"`
+	IsOnDuty            bool               `json:"is_on_duty"`
+	TotalOrderCount     int32              `json:"total_order_count"`
+	TotalEarnings       pgtype.Numeric     `json:"total_earnings"`
+	PendingBalance      pgtype.Numeric     `json:"pending_balance"`
+	RatingAvg           pgtype.Numeric     `json:"rating_avg"`
+	RatingCount         int32              `json:"rating_count"`
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+	CashInHand          pgtype.Numeric     `json:"cash_in_hand"`
+	Tier                string             `json:"tier"`
+	PerformanceScore    pgtype.Numeric     `json:"performance_score"`
+	TierUpdatedAt       pgtype.Timestamptz `json:"tier_updated_at"`
+	VerificationStatus  string             `json:"verification_status"`
+	VerifiedAt          pgtype.Timestamptz `json:"verified_at"`
+	VerifiedBy          pgtype.UUID        `json:"verified_by"`
+}
+
+type RiderApplication struct {
+	ID                  uuid.UUID          `json:"id"`
+	TenantID            uuid.UUID          `json:"tenant_id"`
+	UserID              uuid.UUID          `json:"user_id"`
+	RiderID             pgtype.UUID        `json:"rider_id"`
+	HubID               uuid.UUID          `json:"hub_id"`
+	VehicleType         VehicleType        `json:"vehicle_type"`
+	VehicleRegistration sql.NullString     `json:"vehicle_registration"`
+	LicenseNumber       sql.NullString     `json:"license_number"`
+	NidNumber           string             `json:"nid_number"`
+	Status              string             `json:"status"`
+	RejectionReason     sql.NullString     `json:"rejection_reason"`
+	ReviewedBy          pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt          pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+}
+
+type RiderAssignmentOffer struct {
+	ID          uuid.UUID          `json:"id"`
+	TenantID    uuid.UUID          `json:"tenant_id"`
+	OrderID     uuid.UUID          `json:"order_id"`
+	RiderID     uuid.UUID          `json:"rider_id"`
+	Status      string             `json:"status"`
+	OfferedAt   time.Time          `json:"offered_at"`
+	RespondedAt pgtype.Timestamptz `json:"responded_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
 }
 
 type RiderAttendance struct {
@@ -2574,30 +3161,115 @@ type RiderAttendance struct {
 	UpdatedAt       time.Time          `json:"updated_at"`
 }
 
+type RiderCashDeposit struct {
+	ID              uuid.UUID          `json:"id"`
+	TenantID        uuid.UUID          `json:"tenant_id"`
+	RiderID         uuid.UUID          `json:"rider_id"`
+	HubID           uuid.UUID          `json:"hub_id"`
+	Amount          pgtype.Numeric     `json:"amount"`
+	Status          string             `json:"status"`
+	Reference       sql.NullString     `json:"reference"`
+	Note            sql.NullString     `json:"note"`
+	ReviewedBy      pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt      pgtype.Timestamptz `json:"reviewed_at"`
+	RejectionReason sql.NullString     `json:"rejection_reason"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+type RiderDocument struct {
+	ID             uuid.UUID          `json:"id"`
+	TenantID       uuid.UUID          `json:"tenant_id"`
+	UserID         uuid.UUID          `json:"user_id"`
+	RiderID        pgtype.UUID        `json:"rider_id"`
+	ApplicationID  pgtype.UUID        `json:"application_id"`
+	DocType        string             `json:"doc_type"`
+	FileUrl        string             `json:"file_url"`
+	DocumentNumber sql.NullString     `json:"document_number"`
+	ExpiresOn      pgtype.Date        `json:"expires_on"`
+	Status         string             `json:"status"`
+	ReviewNote     sql.NullString     `json:"review_note"`
+	ReviewedBy     pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt     pgtype.Timestamptz `json:"reviewed_at"`
+	RemindedDays   *int32             `json:"reminded_days"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
 type RiderEarning struct {
-	ID            uuid.UUID      `json:"id"`
-	RiderID       uuid.UUID      `json:"rider_id"`
-	TenantID      uuid.UUID      `json:"tenant_id"`
-	OrderID       uuid.UUID      `json:"order_id"`
-	BaseEarning   pgtype.Numeric `json:"base_earning"`
-	DistanceBonus pgtype.Numeric `json:"distance_bonus"`
-	PeakBonus     pgtype.Numeric `json:"peak_bonus"`
-	TipAmount     pgtype.Numeric `json:"tip_amount"`
-	TotalEarning  pgtype.Numeric `json:"total_earning"`
-	IsPaidOut     bool           `json:"is_paid_out"`
-	PayoutID      pgtype.UUID    `json:"payout_id"`
-	CreatedAt     time.Time      `json:"created_at"`
+	ID               uuid.UUID       `json:"id"`
+	RiderID          uuid.UUID       `json:"rider_id"`
+	TenantID         uuid.UUID       `json:"tenant_id"`
+	OrderID          uuid.UUID       `json:"order_id"`
+	BaseEarning      pgtype.Numeric  `json:"base_earning"`
+	DistanceBonus    pgtype.Numeric  `json:"distance_bonus"`
+	PeakBonus        pgtype.Numeric  `json:"peak_bonus"`
+	TipAmount        pgtype.Numeric  `json:"tip_amount"`
+	TotalEarning     pgtype.Numeric  `json:"total_earning"`
+	IsPaidOut        bool            `json:"is_paid_out"`
+	PayoutID         pgtype.UUID     `json:"payout_id"`
+	CreatedAt        time.Time       `json:"created_at"`
+	RuleID           pgtype.UUID     `json:"rule_id"`
+	VehicleType      NullVehicleType `json:"vehicle_type"`
+	DistanceKm       pgtype.Numeric  `json:"distance_km"`
+	PickupCount      int32           `json:"pickup_count"`
+	MultiPickupBonus pgtype.Numeric  `json:"multi_pickup_bonus"`
+	SurgeMultiplier  pgtype.Numeric  `json:"surge_multiplier"`
+	SurgeBonus       pgtype.Numeric  `json:"surge_bonus"`
+	Breakdown        json.RawMessage `json:"breakdown"`
+}
+
+type RiderEarningPeakWindow struct {
+	ID          uuid.UUID      `json:"id"`
+	RuleID      uuid.UUID      `json:"rule_id"`
+	DayOfWeek   *int32         `json:"day_of_week"`
+	StartMinute int32          `json:"start_minute"`
+	EndMinute   int32          `json:"end_minute"`
+	BonusAmount pgtype.Numeric `json:"bonus_amount"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type RiderEarningRule struct {
+	ID               uuid.UUID       `json:"id"`
+	TenantID         uuid.UUID       `json:"tenant_id"`
+	HubID            pgtype.UUID     `json:"hub_id"`
+	VehicleType      NullVehicleType `json:"vehicle_type"`
+	Name             string          `json:"name"`
+	BasePay          pgtype.Numeric  `json:"base_pay"`
+	PerKmRate        pgtype.Numeric  `json:"per_km_rate"`
+	IncludedKm       pgtype.Numeric  `json:"included_km"`
+	MaxDistancePay   pgtype.Numeric  `json:"max_distance_pay"`
+	ExtraPickupBonus pgtype.Numeric  `json:"extra_pickup_bonus"`
+	IsActive         bool            `json:"is_active"`
+	CreatedBy        pgtype.UUID     `json:"created_by"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+type RiderEarningSurge struct {
+	ID         uuid.UUID          `json:"id"`
+	TenantID   uuid.UUID          `json:"tenant_id"`
+	HubID      pgtype.UUID        `json:"hub_id"`
+	Kind       string             `json:"kind"`
+	Multiplier pgtype.Numeric     `json:"multiplier"`
+	Reason     sql.NullString     `json:"reason"`
+	StartsAt   time.Time          `json:"starts_at"`
+	EndsAt     pgtype.Timestamptz `json:"ends_at"`
+	CreatedBy  pgtype.UUID        `json:"created_by"`
+	CreatedAt  time.Time          `json:"created_at"`
 }
 
 type RiderLocation struct {
-	RiderID        uuid.UUID      `json:"rider_id"`
-	TenantID       uuid.UUID      `json:"tenant_id"`
-	GeoLat         pgtype.Numeric `json:"geo_lat"`
-	GeoLng         pgtype.Numeric `json:"geo_lng"`
-	Heading        pgtype.Numeric `json:"heading"`
-	SpeedKmh       pgtype.Numeric `json:"speed_kmh"`
-	AccuracyMeters pgtype.Numeric `json:"accuracy_meters"`
-	UpdatedAt      time.Time      `json:"updated_at"`
+	RiderID        uuid.UUID          `json:"rider_id"`
+	TenantID       uuid.UUID          `json:"tenant_id"`
+	GeoLat         pgtype.Numeric     `json:"geo_lat"`
+	GeoLng         pgtype.Numeric     `json:"geo_lng"`
+	Heading        pgtype.Numeric     `json:"heading"`
+	SpeedKmh       pgtype.Numeric     `json:"speed_kmh"`
+	AccuracyMeters pgtype.Numeric     `json:"accuracy_meters"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	IsStale        bool               `json:"is_stale"`
+	StaleSince     pgtype.Timestamptz `json:"stale_since"`
 }
 
 type RiderLocationHistory struct {
@@ -2613,37 +3285,143 @@ type RiderLocationHistory struct {
 }
 
 type RiderPayout struct {
-	ID               uuid.UUID          `json:"id"`
-	RiderID          uuid.UUID          `json:"rider_id"`
-	TenantID         uuid.UUID          `json:"tenant_id"`
-	Amount           pgtype.Numeric     `json:"amount"`
-	EarningsFrom     pgtype.Date        `json:"earnings_from"`
-	EarningsTo       pgtype.Date        `json:"earnings_to"`
-	PaymentMethod    string             `json:"payment_method"`
-	PaymentReference sql.NullString     `json:"payment_reference"`
-	Status           PayoutStatus       `json:"status"`
-	ProcessedBy      pgtype.UUID        `json:"processed_by"`
-	ProcessedAt      pgtype.Timestamptz `json:"processed_at"`
-	Note             sql.NullString     `json:"note"`
-	CreatedAt        time.Time          `json:"created_at"`
-	UpdatedAt        time.Time          `json:"updated_at"`
+	ID                uuid.UUID          `json:"id"`
+	RiderID           uuid.UUID          `json:"rider_id"`
+	TenantID          uuid.UUID          `json:"tenant_id"`
+	Amount            pgtype.Numeric     `json:"amount"`
+	EarningsFrom      pgtype.Date        `json:"earnings_from"`
+	EarningsTo        pgtype.Date        `json:"earnings_to"`
+	PaymentMethod     string             `json:"payment_method"`
+	PaymentReference  sql.NullString     `json:"payment_reference"`
+	Status            PayoutStatus       `json:"status"`
+	ProcessedBy       pgtype.UUID        `json:"processed_by"`
+	ProcessedAt       pgtype.Timestamptz `json:"processed_at"`
+	Note              sql.NullString     `json:"note"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	BatchID           pgtype.UUID        `json:"batch_id"`
+	GrossEarnings     pgtype.Numeric     `json:"gross_earnings"`
+	PenaltyDeductions pgtype.Numeric     `json:"penalty_deductions"`
+	CodOffset         pgtype.Numeric     `json:"cod_offset"`
+	PayoutAccount     sql.NullString     `json:"payout_account"`
+}
+
+type RiderPayoutBatch struct {
+	ID          uuid.UUID      `json:"id"`
+	TenantID    uuid.UUID      `json:"tenant_id"`
+	Kind        string         `json:"kind"`
+	PeriodFrom  pgtype.Date    `json:"period_from"`
+	PeriodTo    pgtype.Date    `json:"period_to"`
+	PayoutCount int32          `json:"payout_count"`
+	TotalAmount pgtype.Numeric `json:"total_amount"`
+	CreatedBy   pgtype.UUID    `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
 }
 
 type RiderPenalty struct {
-	ID         uuid.UUID          `json:"id"`
-	RiderID    uuid.UUID          `json:"rider_id"`
-	TenantID   uuid.UUID          `json:"tenant_id"`
-	OrderID    pgtype.UUID        `json:"order_id"`
-	IssueID    pgtype.UUID        `json:"issue_id"`
-	Reason     string             `json:"reason"`
-	Amount     pgtype.Numeric     `json:"amount"`
-	Status     PenaltyStatus      `json:"status"`
-	AppealNote sql.NullString     `json:"appeal_note"`
-	AppealedAt pgtype.Timestamptz `json:"appealed_at"`
-	ClearedAt  pgtype.Timestamptz `json:"cleared_at"`
-	ClearedBy  pgtype.UUID        `json:"cleared_by"`
-	CreatedAt  time.Time          `json:"created_at"`
-	UpdatedAt  time.Time          `json:"updated_at"`
+	ID                 uuid.UUID          `json:"id"`
+	RiderID            uuid.UUID          `json:"rider_id"`
+	TenantID           uuid.UUID          `json:"tenant_id"`
+	OrderID            pgtype.UUID        `json:"order_id"`
+	IssueID            pgtype.UUID        `json:"issue_id"`
+	Reason             string             `json:"reason"`
+	Amount             pgtype.Numeric     `json:"amount"`
+	Status             PenaltyStatus      `json:"status"`
+	AppealNote         sql.NullString     `json:"appeal_note"`
+	AppealedAt         pgtype.Timestamptz `json:"appealed_at"`
+	ClearedAt          pgtype.Timestamptz `json:"cleared_at"`
+	ClearedBy          pgtype.UUID        `json:"cleared_by"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	PayoutID           pgtype.UUID        `json:"payout_id"`
+	AppealEvidenceUrls []string           `json:"appeal_evidence_urls"`
+	AppealOutcome      sql.NullString     `json:"appeal_outcome"`
+	AppealReviewNote   sql.NullString     `json:"appeal_review_note"`
+	AppealReviewedBy   pgtype.UUID        `json:"appeal_reviewed_by"`
+	AppealReviewedAt   pgtype.Timestamptz `json:"appeal_reviewed_at"`
+}
+
+type RiderScorecard struct {
+	ID                 uuid.UUID      `json:"id"`
+	TenantID           uuid.UUID      `json:"tenant_id"`
+	RiderID            uuid.UUID      `json:"rider_id"`
+	HubID              pgtype.UUID    `json:"hub_id"`
+	ScorecardDate      pgtype.Date    `json:"scorecard_date"`
+	OffersReceived     int32          `json:"offers_received"`
+	OffersAccepted     int32          `json:"offers_accepted"`
+	OffersDeclined     int32          `json:"offers_declined"`
+	OffersExpired      int32          `json:"offers_expired"`
+	Pickups            int32          `json:"pickups"`
+	PickupsOnTime      int32          `json:"pickups_on_time"`
+	Deliveries         int32          `json:"deliveries"`
+	DeliveriesOnTime   int32          `json:"deliveries_on_time"`
+	CancelledOrders    int32          `json:"cancelled_orders"`
+	RiderCancellations int32          `json:"rider_cancellations"`
+	RatingsCount       int32          `json:"ratings_count"`
+	RatingsSum         int32          `json:"ratings_sum"`
+	PenaltiesCount     int32          `json:"penalties_count"`
+	PenaltiesAmount    pgtype.Numeric `json:"penalties_amount"`
+	ShiftsRostered     int32          `json:"shifts_rostered"`
+	ShiftsAttended     int32          `json:"shifts_attended"`
+	ShiftsOnTime       int32          `json:"shifts_on_time"`
+	Score              pgtype.Numeric `json:"score"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+type RiderShift struct {
+	ID             uuid.UUID          `json:"id"`
+	TenantID       uuid.UUID          `json:"tenant_id"`
+	HubID          uuid.UUID          `json:"hub_id"`
+	TemplateID     pgtype.UUID        `json:"template_id"`
+	RiderID        uuid.UUID          `json:"rider_id"`
+	ShiftDate      pgtype.Date        `json:"shift_date"`
+	StartsAt       time.Time          `json:"starts_at"`
+	EndsAt         time.Time          `json:"ends_at"`
+	Status         string             `json:"status"`
+	GraceMinutes   int32              `json:"grace_minutes"`
+	LatePenalty    pgtype.Numeric     `json:"late_penalty"`
+	AbsencePenalty pgtype.Numeric     `json:"absence_penalty"`
+	CheckedInAt    pgtype.Timestamptz `json:"checked_in_at"`
+	LateMinutes    int32              `json:"late_minutes"`
+	AttendanceID   pgtype.UUID        `json:"attendance_id"`
+	PenaltyID      pgtype.UUID        `json:"penalty_id"`
+	CreatedBy      pgtype.UUID        `json:"created_by"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+type RiderShiftSwap struct {
+	ID            uuid.UUID          `json:"id"`
+	TenantID      uuid.UUID          `json:"tenant_id"`
+	ShiftID       uuid.UUID          `json:"shift_id"`
+	RequesterID   uuid.UUID          `json:"requester_id"`
+	TargetRiderID uuid.UUID          `json:"target_rider_id"`
+	Status        string             `json:"status"`
+	Reason        sql.NullString     `json:"reason"`
+	ReviewNote    sql.NullString     `json:"review_note"`
+	ReviewedBy    pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt    pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+type RiderShiftTemplate struct {
+	ID             uuid.UUID      `json:"id"`
+	TenantID       uuid.UUID      `json:"tenant_id"`
+	HubID          uuid.UUID      `json:"hub_id"`
+	Name           string         `json:"name"`
+	StartMinute    int32          `json:"start_minute"`
+	EndMinute      int32          `json:"end_minute"`
+	DaysOfWeek     []int32        `json:"days_of_week"`
+	RequiredRiders int32          `json:"required_riders"`
+	GraceMinutes   int32          `json:"grace_minutes"`
+	LatePenalty    pgtype.Numeric `json:"late_penalty"`
+	AbsencePenalty pgtype.Numeric `json:"absence_penalty"`
+	IsActive       bool           `json:"is_active"`
+	CreatedBy      pgtype.UUID    `json:"created_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
 }
 
 type SearchLog struct {
@@ -2657,6 +3435,18 @@ type SearchLog struct {
 	CreatedAt   time.Time       `json:"created_at"`
 }
 
+type SettlementSchedule struct {
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	Cycle         string      `json:"cycle"`
+	AnchorDate    pgtype.Date `json:"anchor_date"`
+	ReviewDays    int16       `json:"review_days"`
+	AutoFinalize  bool        `json:"auto_finalize"`
+	LastPeriodEnd pgtype.Date `json:"last_period_end"`
+	UpdatedBy     pgtype.UUID `json:"updated_by"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
 type Story struct {
 	ID           uuid.UUID          `json:"id"`
 	TenantID     uuid.UUID          `json:"tenant_id"`
@@ -2691,6 +3481,69 @@ type SubscriptionPlan struct {
 	UpdatedAt      time.Time       `json:"updated_at"`
 }
 
+type Supplier struct {
+	ID           uuid.UUID      `json:"id"`
+	TenantID     uuid.UUID      `json:"tenant_id"`
+	Name         string         `json:"name"`
+	ContactName  sql.NullString `json:"contact_name"`
+	Phone        sql.NullString `json:"phone"`
+	Email        sql.NullString `json:"email"`
+	Address      sql.NullString `json:"address"`
+	LeadTimeDays int32          `json:"lead_time_days"`
+	Notes        sql.NullString `json:"notes"`
+	IsActive     bool           `json:"is_active"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type TaxInvoice struct {
+	ID            uuid.UUID       `json:"id"`
+	TenantID      uuid.UUID       `json:"tenant_id"`
+	OrderID       uuid.UUID       `json:"order_id"`
+	RestaurantID  pgtype.UUID     `json:"restaurant_id"`
+	InvoiceNumber string          `json:"invoice_number"`
+	SellerName    string          `json:"seller_name"`
+	SellerBin     sql.NullString  `json:"seller_bin"`
+	SellerAddress sql.NullString  `json:"seller_address"`
+	BuyerName     string          `json:"buyer_name"`
+	BuyerPhone    sql.NullString  `json:"buyer_phone"`
+	BuyerAddress  sql.NullString  `json:"buyer_address"`
+	TaxableValue  pgtype.Numeric  `json:"taxable_value"`
+	VatAmount     pgtype.Numeric  `json:"vat_amount"`
+	TotalAmount   pgtype.Numeric  `json:"total_amount"`
+	Lines         json.RawMessage `json:"lines"`
+	SuppliedAt    time.Time       `json:"supplied_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type TaxInvoiceFailure struct {
+	OrderID     uuid.UUID          `json:"order_id"`
+	TenantID    uuid.UUID          `json:"tenant_id"`
+	Error       string             `json:"error"`
+	FailedAt    time.Time          `json:"failed_at"`
+	Attempts    int32              `json:"attempts"`
+	NextRetryAt pgtype.Timestamptz `json:"next_retry_at"`
+	Permanent   bool               `json:"permanent"`
+}
+
+type TaxInvoiceSerial struct {
+	TenantID   uuid.UUID `json:"tenant_id"`
+	SellerID   uuid.UUID `json:"seller_id"`
+	LastSerial int32     `json:"last_serial"`
+}
+
+type TaxSetting struct {
+	TenantID           uuid.UUID      `json:"tenant_id"`
+	Bin                sql.NullString `json:"bin"`
+	RegisteredName     sql.NullString `json:"registered_name"`
+	RegisteredAddress  sql.NullString `json:"registered_address"`
+	DeliveryFeeVatRate pgtype.Numeric `json:"delivery_fee_vat_rate"`
+	ServiceFeeVatRate  pgtype.Numeric `json:"service_fee_vat_rate"`
+	UpdatedBy          pgtype.UUID    `json:"updated_by"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
 type Tenant struct {
 	ID                 uuid.UUID       `json:"id"`
 	Slug               string          `json:"slug"`
@@ -2802,6 +3655,38 @@ type UserFavourite struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type VendorPayout struct {
+	ID               uuid.UUID          `json:"id"`
+	TenantID         uuid.UUID          `json:"tenant_id"`
+	BatchID          uuid.UUID          `json:"batch_id"`
+	RestaurantID     uuid.UUID          `json:"restaurant_id"`
+	InvoiceID        uuid.UUID          `json:"invoice_id"`
+	Amount           pgtype.Numeric     `json:"amount"`
+	Method           string             `json:"method"`
+	AccountName      string             `json:"account_name"`
+	AccountNumber    string             `json:"account_number"`
+	BankName         sql.NullString     `json:"bank_name"`
+	BranchName       sql.NullString     `json:"branch_name"`
+	RoutingNumber    sql.NullString     `json:"routing_number"`
+	Status           PayoutStatus       `json:"status"`
+	PaymentReference sql.NullString     `json:"payment_reference"`
+	ProcessedBy      pgtype.UUID        `json:"processed_by"`
+	ProcessedAt      pgtype.Timestamptz `json:"processed_at"`
+	Note             sql.NullString     `json:"note"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+type VendorPayoutBatch struct {
+	ID          uuid.UUID      `json:"id"`
+	TenantID    uuid.UUID      `json:"tenant_id"`
+	Kind        string         `json:"kind"`
+	PayoutCount int32          `json:"payout_count"`
+	TotalAmount pgtype.Numeric `json:"total_amount"`
+	CreatedBy   pgtype.UUID    `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
 type WalletTransaction struct {
 	ID           uuid.UUID          `json:"id"`
 	UserID       uuid.UUID          `json:"user_id"`
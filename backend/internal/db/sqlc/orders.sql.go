@@ -83,6 +83,74 @@ func (q *Queries) CheckAllPickupsInStatus(ctx context.Context, arg CheckAllPicku
 	return all_in_status, err
 }
 
+const claimOrderForRider = `-- name: ClaimOrderForRider :one
+UPDATE orders SET rider_id = $3
+WHERE id = $1 AND tenant_id = $2 AND (rider_id IS NULL OR rider_id = $3)
+RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip
+`
+
+type ClaimOrderForRiderParams struct {
+	ID       uuid.UUID   `json:"id"`
+	TenantID uuid.UUID   `json:"tenant_id"`
+	RiderID  pgtype.UUID `json:"rider_id"`
+}
+
+func (q *Queries) ClaimOrderForRider(ctx context.Context, arg ClaimOrderForRiderParams) (Order, error) {
+	row := q.db.QueryRow(ctx, claimOrderForRider, arg.ID, arg.TenantID, arg.RiderID)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.OrderNumber,
+		&i.CustomerID,
+		&i.RiderID,
+		&i.HubID,
+		&i.Status,
+		&i.PaymentStatus,
+		&i.PaymentMethod,
+		&i.Platform,
+		&i.DeliveryAddressID,
+		&i.DeliveryAddress,
+		&i.DeliveryRecipientName,
+		&i.DeliveryRecipientPhone,
+		&i.DeliveryArea,
+		&i.DeliveryGeoLat,
+		&i.DeliveryGeoLng,
+		&i.Subtotal,
+		&i.ItemDiscountTotal,
+		&i.PromoDiscountTotal,
+		&i.VatTotal,
+		&i.DeliveryCharge,
+		&i.ServiceFee,
+		&i.TotalAmount,
+		&i.PromoID,
+		&i.PromoCode,
+		&i.PromoSnapshot,
+		&i.IsPriority,
+		&i.IsReorder,
+		&i.CustomerNote,
+		&i.RiderNote,
+		&i.InternalNote,
+		&i.CancellationReason,
+		&i.CancelledBy,
+		&i.RejectionReason,
+		&i.RejectedBy,
+		&i.AutoConfirmAt,
+		&i.EstimatedDeliveryMinutes,
+		&i.ConfirmedAt,
+		&i.PreparingAt,
+		&i.ReadyAt,
+		&i.PickedAt,
+		&i.DeliveredAt,
+		&i.CancelledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.RiderTip,
+	)
+	return i, err
+}
+
 const countOrdersByCustomer = `-- name: CountOrdersByCustomer :one
 SELECT COUNT(*) FROM orders
 WHERE customer_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
@@ -140,12 +208,12 @@ INSERT INTO orders (
     promo_discount_total, vat_total, delivery_charge, service_fee,
     total_amount, promo_id, promo_code, promo_snapshot,
     is_priority, is_reorder, customer_note, auto_confirm_at,
-    estimated_delivery_minutes
+    estimated_delivery_minutes, rider_tip
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
-    $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29
+    $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
 )
-RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at
+RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip
 `
 
 type CreateOrderParams struct {
@@ -178,6 +246,7 @@ type CreateOrderParams struct {
 	CustomerNote             sql.NullString     `json:"customer_note"`
 	AutoConfirmAt            pgtype.Timestamptz `json:"auto_confirm_at"`
 	EstimatedDeliveryMinutes *int32             `json:"estimated_delivery_minutes"`
+	RiderTip                 pgtype.Numeric     `json:"rider_tip"`
 }
 
 // ============================================================
@@ -214,6 +283,7 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.CustomerNote,
 		arg.AutoConfirmAt,
 		arg.EstimatedDeliveryMinutes,
+		arg.RiderTip,
 	)
 	var i Order
 	err := row.Scan(
@@ -264,6 +334,7 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
@@ -406,7 +477,7 @@ func (q *Queries) CreateOrderPickup(ctx context.Context, arg CreateOrderPickupPa
 const updateOrderStatus = `-- name: UpdateOrderStatus :one
 UPDATE orders SET status = $3
 WHERE id = $1 AND tenant_id = $2
-RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at
+RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip
 `
 
 type UpdateOrderStatusParams struct {
@@ -466,6 +537,7 @@ func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusPa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
@@ -493,7 +565,7 @@ func (q *Queries) GenerateOrderNumber(ctx context.Context, arg GenerateOrderNumb
 }
 
 const getOrderByID = `-- name: GetOrderByID :one
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 `
 
@@ -553,12 +625,13 @@ func (q *Queries) GetOrderByID(ctx context.Context, arg GetOrderByIDParams) (Ord
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
 
 const getOrderByNumber = `-- name: GetOrderByNumber :one
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE order_number = $1 AND tenant_id = $2 AND deleted_at IS NULL
 `
 
@@ -618,12 +691,13 @@ func (q *Queries) GetOrderByNumber(ctx context.Context, arg GetOrderByNumberPara
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
 
 const getOrderForUpdate = `-- name: GetOrderForUpdate :one
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 FOR UPDATE
 `
@@ -684,6 +758,7 @@ func (q *Queries) GetOrderForUpdate(ctx context.Context, arg GetOrderForUpdatePa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
@@ -881,7 +956,7 @@ func (q *Queries) GetPickupCountByOrder(ctx context.Context, orderID uuid.UUID)
 }
 
 const listOrdersByCustomer = `-- name: ListOrdersByCustomer :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE customer_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -956,6 +1031,7 @@ func (q *Queries) ListOrdersByCustomer(ctx context.Context, arg ListOrdersByCust
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -968,7 +1044,7 @@ func (q *Queries) ListOrdersByCustomer(ctx context.Context, arg ListOrdersByCust
 }
 
 const listOrdersByRestaurant = `-- name: ListOrdersByRestaurant :many
-SELECT DISTINCT o.id, o.tenant_id, o.order_number, o.customer_id, o.rider_id, o.hub_id, o.status, o.payment_status, o.payment_method, o.platform, o.delivery_address_id, o.delivery_address, o.delivery_recipient_name, o.delivery_recipient_phone, o.delivery_area, o.delivery_geo_lat, o.delivery_geo_lng, o.subtotal, o.item_discount_total, o.promo_discount_total, o.vat_total, o.delivery_charge, o.service_fee, o.total_amount, o.promo_id, o.promo_code, o.promo_snapshot, o.is_priority, o.is_reorder, o.customer_note, o.rider_note, o.internal_note, o.cancellation_reason, o.cancelled_by, o.rejection_reason, o.rejected_by, o.auto_confirm_at, o.estimated_delivery_minutes, o.confirmed_at, o.preparing_at, o.ready_at, o.picked_at, o.delivered_at, o.cancelled_at, o.created_at, o.updated_at, o.deleted_at FROM orders o
+SELECT DISTINCT o.id, o.tenant_id, o.order_number, o.customer_id, o.rider_id, o.hub_id, o.status, o.payment_status, o.payment_method, o.platform, o.delivery_address_id, o.delivery_address, o.delivery_recipient_name, o.delivery_recipient_phone, o.delivery_area, o.delivery_geo_lat, o.delivery_geo_lng, o.subtotal, o.item_discount_total, o.promo_discount_total, o.vat_total, o.delivery_charge, o.service_fee, o.total_amount, o.promo_id, o.promo_code, o.promo_snapshot, o.is_priority, o.is_reorder, o.customer_note, o.rider_note, o.internal_note, o.cancellation_reason, o.cancelled_by, o.rejection_reason, o.rejected_by, o.auto_confirm_at, o.estimated_delivery_minutes, o.confirmed_at, o.preparing_at, o.ready_at, o.picked_at, o.delivered_at, o.cancelled_at, o.created_at, o.updated_at, o.deleted_at, o.rider_tip FROM orders o
 JOIN order_pickups op ON o.id = op.order_id
 WHERE op.restaurant_id = $1 AND o.tenant_id = $2 AND o.deleted_at IS NULL
 ORDER BY o.created_at DESC
@@ -1044,6 +1120,7 @@ func (q *Queries) ListOrdersByRestaurant(ctx context.Context, arg ListOrdersByRe
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -1056,7 +1133,7 @@ func (q *Queries) ListOrdersByRestaurant(ctx context.Context, arg ListOrdersByRe
 }
 
 const listOrdersByTenant = `-- name: ListOrdersByTenant :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE tenant_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -1125,6 +1202,7 @@ func (q *Queries) ListOrdersByTenant(ctx context.Context, arg ListOrdersByTenant
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -1137,7 +1215,7 @@ func (q *Queries) ListOrdersByTenant(ctx context.Context, arg ListOrdersByTenant
 }
 
 const listPendingAutoConfirmOrders = `-- name: ListPendingAutoConfirmOrders :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE status = 'created'
   AND auto_confirm_at IS NOT NULL
   AND auto_confirm_at <= NOW()
@@ -1203,6 +1281,7 @@ func (q *Queries) ListPendingAutoConfirmOrders(ctx context.Context, limit int32)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -1286,7 +1365,7 @@ UPDATE orders SET
     rejection_reason = CASE WHEN $1 = 'rejected' THEN $4 ELSE rejection_reason END,
     rejected_by = CASE WHEN $1 = 'rejected' THEN $5 ELSE rejected_by END
 WHERE id = $6 AND tenant_id = $7 AND deleted_at IS NULL
-RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at
+RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip
 `
 
 type TransitionOrderStatusParams struct {
@@ -1358,6 +1437,7 @@ func (q *Queries) TransitionOrderStatus(ctx context.Context, arg TransitionOrder
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
@@ -1419,7 +1499,7 @@ const updateOrderPaymentStatus = `-- name: UpdateOrderPaymentStatus :one
 UPDATE orders SET
     payment_status = $1
 WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL
-RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at
+RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip
 `
 
 type UpdateOrderPaymentStatusParams struct {
@@ -1479,6 +1559,7 @@ func (q *Queries) UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderP
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
@@ -1486,7 +1567,7 @@ func (q *Queries) UpdateOrderPaymentStatus(ctx context.Context, arg UpdateOrderP
 const assignRiderToOrder = `-- name: AssignRiderToOrder :one
 UPDATE orders SET rider_id = $3
 WHERE id = $1 AND tenant_id = $2
-RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at
+RETURNING id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip
 `
 
 type AssignRiderToOrderParams struct {
@@ -1546,12 +1627,13 @@ func (q *Queries) AssignRiderToOrder(ctx context.Context, arg AssignRiderToOrder
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.RiderTip,
 	)
 	return i, err
 }
 
 const getOrderForReconciliation = `-- name: GetOrderForReconciliation :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE payment_status = 'unpaid' AND status IN ('pending', 'created')
     AND created_at < $2::timestamptz
 ORDER BY created_at ASC
@@ -1620,6 +1702,7 @@ func (q *Queries) GetOrderForReconciliation(ctx context.Context, arg GetOrderFor
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -1632,7 +1715,7 @@ func (q *Queries) GetOrderForReconciliation(ctx context.Context, arg GetOrderFor
 }
 
 const listActiveOrdersByRider = `-- name: ListActiveOrdersByRider :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE rider_id = $1 AND tenant_id = $2
   AND status IN ('confirmed', 'preparing', 'ready', 'picked')
   AND deleted_at IS NULL
@@ -1701,6 +1784,7 @@ func (q *Queries) ListActiveOrdersByRider(ctx context.Context, arg ListActiveOrd
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -1713,7 +1797,7 @@ func (q *Queries) ListActiveOrdersByRider(ctx context.Context, arg ListActiveOrd
 }
 
 const listDeliveredOrdersByRider = `-- name: ListDeliveredOrdersByRider :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE rider_id = $1 AND tenant_id = $2
   AND status = 'delivered'
   AND deleted_at IS NULL
@@ -1790,6 +1874,7 @@ func (q *Queries) ListDeliveredOrdersByRider(ctx context.Context, arg ListDelive
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -1802,7 +1887,7 @@ func (q *Queries) ListDeliveredOrdersByRider(ctx context.Context, arg ListDelive
 }
 
 const listPendingPaymentOrders = `-- name: ListPendingPaymentOrders :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE tenant_id = $1 AND payment_status = 'unpaid' AND status = 'pending'
     AND created_at < $3::timestamptz
 ORDER BY created_at ASC
@@ -1872,6 +1957,7 @@ func (q *Queries) ListPendingPaymentOrders(ctx context.Context, arg ListPendingP
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -26,11 +26,20 @@ func (q *Queries) ClearUserPushToken(ctx context.Context, id uuid.UUID) error {
 }
 
 const countOrderIssuesByTenant = `-- name: CountOrderIssuesByTenant :one
-SELECT COUNT(*) FROM order_issues WHERE tenant_id = $1
+SELECT COUNT(*) FROM order_issues i
+WHERE i.tenant_id = $1
+  AND ($2::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups p
+        WHERE p.order_id = i.order_id AND p.restaurant_id = ANY($2::uuid[])))
 `
 
-func (q *Queries) CountOrderIssuesByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, countOrderIssuesByTenant, tenantID)
+type CountOrderIssuesByTenantParams struct {
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
+}
+
+func (q *Queries) CountOrderIssuesByTenant(ctx context.Context, arg CountOrderIssuesByTenantParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrderIssuesByTenant, arg.TenantID, arg.RestaurantIds)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -410,7 +419,7 @@ func (q *Queries) ListAuditLogsByResource(ctx context.Context, arg ListAuditLogs
 }
 
 const listCreatedOrdersPastTimeout = `-- name: ListCreatedOrdersPastTimeout :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE status = 'created'
   AND auto_confirm_at IS NOT NULL
   AND auto_confirm_at < NOW()
@@ -475,6 +484,7 @@ func (q *Queries) ListCreatedOrdersPastTimeout(ctx context.Context, limit int32)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -577,20 +587,29 @@ func (q *Queries) ListOrderIssuesByOrder(ctx context.Context, arg ListOrderIssue
 }
 
 const listOrderIssuesByTenant = `-- name: ListOrderIssuesByTenant :many
-SELECT id, order_id, tenant_id, issue_type, reported_by_id, details, evidence_urls, accountable_party, refund_items, refund_amount, refund_status, restaurant_penalty_amount, rider_penalty_amount, status, resolution_note, resolved_by_id, resolved_at, created_at, updated_at FROM order_issues
-WHERE tenant_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+SELECT id, order_id, tenant_id, issue_type, reported_by_id, details, evidence_urls, accountable_party, refund_items, refund_amount, refund_status, restaurant_penalty_amount, rider_penalty_amount, status, resolution_note, resolved_by_id, resolved_at, created_at, updated_at FROM order_issues i
+WHERE i.tenant_id = $1
+  AND ($2::uuid[] IS NULL OR EXISTS (
+        SELECT 1 FROM order_pickups p
+        WHERE p.order_id = i.order_id AND p.restaurant_id = ANY($2::uuid[])))
+ORDER BY i.created_at DESC
+LIMIT $3 OFFSET $4
 `
 
 type ListOrderIssuesByTenantParams struct {
-	TenantID uuid.UUID `json:"tenant_id"`
-	Limit    int32     `json:"limit"`
-	Offset   int32     `json:"offset"`
+	TenantID      uuid.UUID   `json:"tenant_id"`
+	RestaurantIds []uuid.UUID `json:"restaurant_ids"`
+	Limit         int32       `json:"limit"`
+	Offset        int32       `json:"offset"`
 }
 
 func (q *Queries) ListOrderIssuesByTenant(ctx context.Context, arg ListOrderIssuesByTenantParams) ([]OrderIssue, error) {
-	rows, err := q.db.Query(ctx, listOrderIssuesByTenant, arg.TenantID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listOrderIssuesByTenant,
+		arg.TenantID,
+		arg.RestaurantIds,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -630,7 +649,7 @@ func (q *Queries) ListOrderIssuesByTenant(ctx context.Context, arg ListOrderIssu
 }
 
 const listOrdersByStatus = `-- name: ListOrdersByStatus :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE tenant_id = $1 AND status = $4::order_status AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -705,6 +724,7 @@ func (q *Queries) ListOrdersByStatus(ctx context.Context, arg ListOrdersByStatus
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -717,7 +737,7 @@ func (q *Queries) ListOrdersByStatus(ctx context.Context, arg ListOrdersByStatus
 }
 
 const listPendingOrdersPastTimeout = `-- name: ListPendingOrdersPastTimeout :many
-SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at FROM orders
+SELECT id, tenant_id, order_number, customer_id, rider_id, hub_id, status, payment_status, payment_method, platform, delivery_address_id, delivery_address, delivery_recipient_name, delivery_recipient_phone, delivery_area, delivery_geo_lat, delivery_geo_lng, subtotal, item_discount_total, promo_discount_total, vat_total, delivery_charge, service_fee, total_amount, promo_id, promo_code, promo_snapshot, is_priority, is_reorder, customer_note, rider_note, internal_note, cancellation_reason, cancelled_by, rejection_reason, rejected_by, auto_confirm_at, estimated_delivery_minutes, confirmed_at, preparing_at, ready_at, picked_at, delivered_at, cancelled_at, created_at, updated_at, deleted_at, rider_tip FROM orders
 WHERE status = 'pending'
   AND created_at < $2::timestamptz
   AND deleted_at IS NULL
@@ -786,6 +806,7 @@ func (q *Queries) ListPendingOrdersPastTimeout(ctx context.Context, arg ListPend
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.RiderTip,
 		); err != nil {
 			return nil, err
 		}
@@ -905,6 +926,64 @@ func (q *Queries) PurgeOldSearchLogs(ctx context.Context, before time.Time) erro
 	return err
 }
 
+const resolveOrderIssue = `-- name: ResolveOrderIssue :one
+UPDATE order_issues SET
+  status = 'resolved',
+  accountable_party = $1,
+  rider_penalty_amount = $2,
+  restaurant_penalty_amount = $3,
+  resolution_note = $4,
+  resolved_by_id = $5,
+  resolved_at = NOW()
+WHERE id = $6 AND tenant_id = $7 AND status = 'open'
+RETURNING id, order_id, tenant_id, issue_type, reported_by_id, details, evidence_urls, accountable_party, refund_items, refund_amount, refund_status, restaurant_penalty_amount, rider_penalty_amount, status, resolution_note, resolved_by_id, resolved_at, created_at, updated_at
+`
+
+type ResolveOrderIssueParams struct {
+	AccountableParty        Accountable    `json:"accountable_party"`
+	RiderPenaltyAmount      pgtype.Numeric `json:"rider_penalty_amount"`
+	RestaurantPenaltyAmount pgtype.Numeric `json:"restaurant_penalty_amount"`
+	ResolutionNote          sql.NullString `json:"resolution_note"`
+	ResolvedByID            pgtype.UUID    `json:"resolved_by_id"`
+	ID                      uuid.UUID      `json:"id"`
+	TenantID                uuid.UUID      `json:"tenant_id"`
+}
+
+func (q *Queries) ResolveOrderIssue(ctx context.Context, arg ResolveOrderIssueParams) (OrderIssue, error) {
+	row := q.db.QueryRow(ctx, resolveOrderIssue,
+		arg.AccountableParty,
+		arg.RiderPenaltyAmount,
+		arg.RestaurantPenaltyAmount,
+		arg.ResolutionNote,
+		arg.ResolvedByID,
+		arg.ID,
+		arg.TenantID,
+	)
+	var i OrderIssue
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.TenantID,
+		&i.IssueType,
+		&i.ReportedByID,
+		&i.Details,
+		&i.EvidenceUrls,
+		&i.AccountableParty,
+		&i.RefundItems,
+		&i.RefundAmount,
+		&i.RefundStatus,
+		&i.RestaurantPenaltyAmount,
+		&i.RiderPenaltyAmount,
+		&i.Status,
+		&i.ResolutionNote,
+		&i.ResolvedByID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const searchProducts = `-- name: SearchProducts :many
 SELECT p.id, p.tenant_id, p.restaurant_id, p.category_id, p.name, p.slug, p.description, p.base_price, p.vat_rate, p.has_modifiers, p.availability, p.images, p.tags, p.is_featured, p.is_inv_tracked, p.sort_order, p.meta_title, p.meta_description, p.rating_avg, p.rating_count, p.order_count, p.created_at, p.updated_at FROM products p
 JOIN restaurants r ON p.restaurant_id = r.id
@@ -969,7 +1048,7 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 }
 
 const searchRestaurants = `-- name: SearchRestaurants :many
-SELECT id, tenant_id, hub_id, owner_id, name, slug, type, description, short_description, banner_image_url, logo_url, gallery_urls, phone, email, address_line1, address_line2, area, city, geo_lat, geo_lng, cuisines, tags, commission_rate, vat_rate, is_vat_inclusive, min_order_amount, avg_prep_time_minutes, max_concurrent_orders, auto_accept_orders, order_prefix, order_sequence, is_available, is_featured, is_active, sort_order, meta_title, meta_description, meta_keywords, rating_avg, rating_count, total_order_count, created_at, updated_at FROM restaurants
+SELECT id, tenant_id, hub_id, owner_id, name, slug, type, description, short_description, banner_image_url, logo_url, gallery_urls, phone, email, address_line1, address_line2, area, city, geo_lat, geo_lng, cuisines, tags, commission_rate, vat_rate, is_vat_inclusive, min_order_amount, avg_prep_time_minutes, max_concurrent_orders, auto_accept_orders, order_prefix, order_sequence, is_available, is_featured, is_active, sort_order, meta_title, meta_description, meta_keywords, rating_avg, rating_count, total_order_count, created_at, updated_at, require_pod FROM restaurants
 WHERE tenant_id = $1
   AND deleted_at IS NULL
   AND is_active = true
@@ -1037,6 +1116,7 @@ func (q *Queries) SearchRestaurants(ctx context.Context, arg SearchRestaurantsPa
 			&i.TotalOrderCount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RequirePod,
 		); err != nil {
 			return nil, err
 		}
@@ -30,6 +30,22 @@ func (q *Queries) AddPromoCategoryRestriction(ctx context.Context, arg AddPromoC
 	return err
 }
 
+const addPromoProductRestriction = `-- name: AddPromoProductRestriction :exec
+INSERT INTO promo_product_restrictions (promo_id, product_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AddPromoProductRestrictionParams struct {
+	PromoID   uuid.UUID `json:"promo_id"`
+	ProductID uuid.UUID `json:"product_id"`
+}
+
+func (q *Queries) AddPromoProductRestriction(ctx context.Context, arg AddPromoProductRestrictionParams) error {
+	_, err := q.db.Exec(ctx, addPromoProductRestriction, arg.PromoID, arg.ProductID)
+	return err
+}
+
 const addPromoRestaurantRestriction = `-- name: AddPromoRestaurantRestriction :exec
 INSERT INTO promo_restaurant_restrictions (promo_id, restaurant_id)
 VALUES ($1, $2)
@@ -97,30 +113,41 @@ INSERT INTO promos (
     tenant_id, code, title, description, promo_type,
     discount_amount, max_discount_cap, cashback_amount, funded_by,
     applies_to, min_order_amount, max_total_uses, max_uses_per_user,
-    include_stores, is_active, starts_at, ends_at, created_by
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
-RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at
+    include_stores, is_active, starts_at, ends_at, created_by,
+    rule_type, buy_qty, get_qty, first_order_only, new_customer_days,
+    auto_apply, stacking, budget_cap
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+    $19, $20, $21, $22, $23, $24, $25, $26)
+RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at
 `
 
 type CreatePromoParams struct {
-	TenantID       uuid.UUID          `json:"tenant_id"`
-	Code           string             `json:"code"`
-	Title          string             `json:"title"`
-	Description    sql.NullString     `json:"description"`
-	PromoType      PromoType          `json:"promo_type"`
-	DiscountAmount pgtype.Numeric     `json:"discount_amount"`
-	MaxDiscountCap pgtype.Numeric     `json:"max_discount_cap"`
-	CashbackAmount pgtype.Numeric     `json:"cashback_amount"`
-	FundedBy       PromoFunder        `json:"funded_by"`
-	AppliesTo      PromoApplyOn       `json:"applies_to"`
-	MinOrderAmount pgtype.Numeric     `json:"min_order_amount"`
-	MaxTotalUses   *int32             `json:"max_total_uses"`
-	MaxUsesPerUser int32              `json:"max_uses_per_user"`
-	IncludeStores  bool               `json:"include_stores"`
-	IsActive       bool               `json:"is_active"`
-	StartsAt       time.Time          `json:"starts_at"`
-	EndsAt         pgtype.Timestamptz `json:"ends_at"`
-	CreatedBy      pgtype.UUID        `json:"created_by"`
+	TenantID        uuid.UUID          `json:"tenant_id"`
+	Code            sql.NullString     `json:"code"`
+	Title           string             `json:"title"`
+	Description     sql.NullString     `json:"description"`
+	PromoType       PromoType          `json:"promo_type"`
+	DiscountAmount  pgtype.Numeric     `json:"discount_amount"`
+	MaxDiscountCap  pgtype.Numeric     `json:"max_discount_cap"`
+	CashbackAmount  pgtype.Numeric     `json:"cashback_amount"`
+	FundedBy        PromoFunder        `json:"funded_by"`
+	AppliesTo       PromoApplyOn       `json:"applies_to"`
+	MinOrderAmount  pgtype.Numeric     `json:"min_order_amount"`
+	MaxTotalUses    *int32             `json:"max_total_uses"`
+	MaxUsesPerUser  int32              `json:"max_uses_per_user"`
+	IncludeStores   bool               `json:"include_stores"`
+	IsActive        bool               `json:"is_active"`
+	StartsAt        time.Time          `json:"starts_at"`
+	EndsAt          pgtype.Timestamptz `json:"ends_at"`
+	CreatedBy       pgtype.UUID        `json:"created_by"`
+	RuleType        PromoRule          `json:"rule_type"`
+	BuyQty          *int32             `json:"buy_qty"`
+	GetQty          *int32             `json:"get_qty"`
+	FirstOrderOnly  bool               `json:"first_order_only"`
+	NewCustomerDays *int32             `json:"new_customer_days"`
+	AutoApply       bool               `json:"auto_apply"`
+	Stacking        PromoStacking      `json:"stacking"`
+	BudgetCap       pgtype.Numeric     `json:"budget_cap"`
 }
 
 // ============================================================
@@ -146,6 +173,14 @@ func (q *Queries) CreatePromo(ctx context.Context, arg CreatePromoParams) (Promo
 		arg.StartsAt,
 		arg.EndsAt,
 		arg.CreatedBy,
+		arg.RuleType,
+		arg.BuyQty,
+		arg.GetQty,
+		arg.FirstOrderOnly,
+		arg.NewCustomerDays,
+		arg.AutoApply,
+		arg.Stacking,
+		arg.BudgetCap,
 	)
 	var i Promo
 	err := row.Scan(
@@ -172,6 +207,72 @@ func (q *Queries) CreatePromo(ctx context.Context, arg CreatePromoParams) (Promo
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
+	)
+	return i, err
+}
+
+const createPromoTier = `-- name: CreatePromoTier :one
+INSERT INTO promo_tiers (promo_id, min_subtotal, discount_amount)
+VALUES ($1, $2, $3)
+RETURNING id, promo_id, min_subtotal, discount_amount, created_at
+`
+
+type CreatePromoTierParams struct {
+	PromoID        uuid.UUID      `json:"promo_id"`
+	MinSubtotal    pgtype.Numeric `json:"min_subtotal"`
+	DiscountAmount pgtype.Numeric `json:"discount_amount"`
+}
+
+func (q *Queries) CreatePromoTier(ctx context.Context, arg CreatePromoTierParams) (PromoTier, error) {
+	row := q.db.QueryRow(ctx, createPromoTier, arg.PromoID, arg.MinSubtotal, arg.DiscountAmount)
+	var i PromoTier
+	err := row.Scan(
+		&i.ID,
+		&i.PromoID,
+		&i.MinSubtotal,
+		&i.DiscountAmount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPromoTimeWindow = `-- name: CreatePromoTimeWindow :one
+INSERT INTO promo_time_windows (promo_id, day_of_week, start_minute, end_minute)
+VALUES ($1, $2, $3, $4)
+RETURNING id, promo_id, day_of_week, start_minute, end_minute, created_at
+`
+
+type CreatePromoTimeWindowParams struct {
+	PromoID     uuid.UUID `json:"promo_id"`
+	DayOfWeek   *int32    `json:"day_of_week"`
+	StartMinute int32     `json:"start_minute"`
+	EndMinute   int32     `json:"end_minute"`
+}
+
+func (q *Queries) CreatePromoTimeWindow(ctx context.Context, arg CreatePromoTimeWindowParams) (PromoTimeWindow, error) {
+	row := q.db.QueryRow(ctx, createPromoTimeWindow,
+		arg.PromoID,
+		arg.DayOfWeek,
+		arg.StartMinute,
+		arg.EndMinute,
+	)
+	var i PromoTimeWindow
+	err := row.Scan(
+		&i.ID,
+		&i.PromoID,
+		&i.DayOfWeek,
+		&i.StartMinute,
+		&i.EndMinute,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -179,9 +280,9 @@ func (q *Queries) CreatePromo(ctx context.Context, arg CreatePromoParams) (Promo
 const createPromoUsage = `-- name: CreatePromoUsage :one
 INSERT INTO promo_usages (
     promo_id, user_id, order_id, tenant_id,
-    discount_amount, cashback_amount
-) VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, promo_id, user_id, order_id, tenant_id, discount_amount, cashback_amount, created_at
+    discount_amount, cashback_amount, funded_by
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, promo_id, user_id, order_id, tenant_id, discount_amount, cashback_amount, created_at, funded_by, reversed_at
 `
 
 type CreatePromoUsageParams struct {
@@ -191,6 +292,7 @@ type CreatePromoUsageParams struct {
 	TenantID       uuid.UUID      `json:"tenant_id"`
 	DiscountAmount pgtype.Numeric `json:"discount_amount"`
 	CashbackAmount pgtype.Numeric `json:"cashback_amount"`
+	FundedBy       PromoFunder    `json:"funded_by"`
 }
 
 func (q *Queries) CreatePromoUsage(ctx context.Context, arg CreatePromoUsageParams) (PromoUsage, error) {
@@ -201,6 +303,7 @@ func (q *Queries) CreatePromoUsage(ctx context.Context, arg CreatePromoUsagePara
 		arg.TenantID,
 		arg.DiscountAmount,
 		arg.CashbackAmount,
+		arg.FundedBy,
 	)
 	var i PromoUsage
 	err := row.Scan(
@@ -212,15 +315,18 @@ func (q *Queries) CreatePromoUsage(ctx context.Context, arg CreatePromoUsagePara
 		&i.DiscountAmount,
 		&i.CashbackAmount,
 		&i.CreatedAt,
+		&i.FundedBy,
+		&i.ReversedAt,
 	)
 	return i, err
 }
 
 const deactivatePromo = `-- name: DeactivatePromo :one
 UPDATE promos
-SET is_active = false
+SET is_active = false,
+    budget_exhausted_at = NULL
 WHERE id = $1 AND tenant_id = $2
-RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at
+RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at
 `
 
 type DeactivatePromoParams struct {
@@ -255,12 +361,21 @@ func (q *Queries) DeactivatePromo(ctx context.Context, arg DeactivatePromoParams
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
 	)
 	return i, err
 }
 
 const getActivePromoByCode = `-- name: GetActivePromoByCode :one
-SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at FROM promos
+SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at FROM promos
 WHERE code = $1 AND tenant_id = $2
   AND is_active = true
   AND starts_at <= NOW()
@@ -268,8 +383,8 @@ WHERE code = $1 AND tenant_id = $2
 `
 
 type GetActivePromoByCodeParams struct {
-	Code     string    `json:"code"`
-	TenantID uuid.UUID `json:"tenant_id"`
+	Code     sql.NullString `json:"code"`
+	TenantID uuid.UUID      `json:"tenant_id"`
 }
 
 func (q *Queries) GetActivePromoByCode(ctx context.Context, arg GetActivePromoByCodeParams) (Promo, error) {
@@ -299,18 +414,27 @@ func (q *Queries) GetActivePromoByCode(ctx context.Context, arg GetActivePromoBy
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
 	)
 	return i, err
 }
 
 const getPromoByCode = `-- name: GetPromoByCode :one
-SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at FROM promos
+SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at FROM promos
 WHERE code = $1 AND tenant_id = $2
 `
 
 type GetPromoByCodeParams struct {
-	Code     string    `json:"code"`
-	TenantID uuid.UUID `json:"tenant_id"`
+	Code     sql.NullString `json:"code"`
+	TenantID uuid.UUID      `json:"tenant_id"`
 }
 
 func (q *Queries) GetPromoByCode(ctx context.Context, arg GetPromoByCodeParams) (Promo, error) {
@@ -340,12 +464,21 @@ func (q *Queries) GetPromoByCode(ctx context.Context, arg GetPromoByCodeParams)
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
 	)
 	return i, err
 }
 
 const getPromoByID = `-- name: GetPromoByID :one
-SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at FROM promos
+SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at FROM promos
 WHERE id = $1 AND tenant_id = $2
 `
 
@@ -381,13 +514,112 @@ func (q *Queries) GetPromoByID(ctx context.Context, arg GetPromoByIDParams) (Pro
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
 	)
 	return i, err
 }
 
+const getPromoCustomerHistory = `-- name: GetPromoCustomerHistory :one
+SELECT u.created_at AS registered_at, COUNT(o.id) AS order_count
+FROM users u
+LEFT JOIN orders o ON o.customer_id = u.id
+    AND o.tenant_id = $1
+    AND o.status NOT IN ('cancelled', 'rejected')
+    AND o.deleted_at IS NULL
+WHERE u.id = $2
+GROUP BY u.id
+`
+
+type GetPromoCustomerHistoryParams struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+type GetPromoCustomerHistoryRow struct {
+	RegisteredAt time.Time `json:"registered_at"`
+	OrderCount   int64     `json:"order_count"`
+}
+
+func (q *Queries) GetPromoCustomerHistory(ctx context.Context, arg GetPromoCustomerHistoryParams) (GetPromoCustomerHistoryRow, error) {
+	row := q.db.QueryRow(ctx, getPromoCustomerHistory, arg.TenantID, arg.UserID)
+	var i GetPromoCustomerHistoryRow
+	err := row.Scan(&i.RegisteredAt, &i.OrderCount)
+	return i, err
+}
+
+const getPromoRedemptionReport = `-- name: GetPromoRedemptionReport :many
+SELECT p.id AS promo_id, p.code, p.title, pu.funded_by,
+    COUNT(*) AS redemptions,
+    COUNT(DISTINCT pu.user_id) AS customers,
+    COALESCE(SUM(pu.discount_amount), 0)::numeric AS discount_total,
+    COALESCE(SUM(pu.cashback_amount), 0)::numeric AS cashback_total
+FROM promo_usages pu
+JOIN promos p ON p.id = pu.promo_id
+WHERE pu.tenant_id = $1
+  AND pu.reversed_at IS NULL
+  AND pu.created_at >= $2
+  AND pu.created_at < $3
+GROUP BY p.id, p.code, p.title, pu.funded_by
+ORDER BY discount_total DESC
+`
+
+type GetPromoRedemptionReportParams struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+}
+
+type GetPromoRedemptionReportRow struct {
+	PromoID       uuid.UUID      `json:"promo_id"`
+	Code          sql.NullString `json:"code"`
+	Title         string         `json:"title"`
+	FundedBy      PromoFunder    `json:"funded_by"`
+	Redemptions   int64          `json:"redemptions"`
+	Customers     int64          `json:"customers"`
+	DiscountTotal pgtype.Numeric `json:"discount_total"`
+	CashbackTotal pgtype.Numeric `json:"cashback_total"`
+}
+
+func (q *Queries) GetPromoRedemptionReport(ctx context.Context, arg GetPromoRedemptionReportParams) ([]GetPromoRedemptionReportRow, error) {
+	rows, err := q.db.Query(ctx, getPromoRedemptionReport, arg.TenantID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPromoRedemptionReportRow{}
+	for rows.Next() {
+		var i GetPromoRedemptionReportRow
+		if err := rows.Scan(
+			&i.PromoID,
+			&i.Code,
+			&i.Title,
+			&i.FundedBy,
+			&i.Redemptions,
+			&i.Customers,
+			&i.DiscountTotal,
+			&i.CashbackTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUsageCountByUserAndPromo = `-- name: GetUsageCountByUserAndPromo :one
 SELECT COUNT(*) FROM promo_usages
-WHERE user_id = $1 AND promo_id = $2
+WHERE user_id = $1 AND promo_id = $2 AND reversed_at IS NULL
 `
 
 type GetUsageCountByUserAndPromoParams struct {
@@ -402,22 +634,138 @@ func (q *Queries) GetUsageCountByUserAndPromo(ctx context.Context, arg GetUsageC
 	return count, err
 }
 
-const incrementPromoUsage = `-- name: IncrementPromoUsage :exec
+const redeemPromo = `-- name: RedeemPromo :one
 UPDATE promos
 SET total_uses = total_uses + 1,
-    total_discount_given = total_discount_given + $1
+    total_discount_given = total_discount_given + $1,
+    is_active = CASE
+        WHEN budget_cap IS NOT NULL AND total_discount_given + $1 >= budget_cap THEN false
+        ELSE is_active
+    END,
+    budget_exhausted_at = CASE
+        WHEN budget_cap IS NOT NULL AND total_discount_given + $1 >= budget_cap THEN NOW()
+        ELSE budget_exhausted_at
+    END
 WHERE id = $2 AND tenant_id = $3
+  AND is_active = true
+  AND starts_at <= NOW()
+  AND (ends_at IS NULL OR ends_at > NOW())
+  AND (max_total_uses IS NULL OR total_uses < max_total_uses)
+  AND (budget_cap IS NULL OR total_discount_given + $1 <= budget_cap)
+RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at
 `
 
-type IncrementPromoUsageParams struct {
+type RedeemPromoParams struct {
 	DiscountAmount pgtype.Numeric `json:"discount_amount"`
 	ID             uuid.UUID      `json:"id"`
 	TenantID       uuid.UUID      `json:"tenant_id"`
 }
 
-func (q *Queries) IncrementPromoUsage(ctx context.Context, arg IncrementPromoUsageParams) error {
-	_, err := q.db.Exec(ctx, incrementPromoUsage, arg.DiscountAmount, arg.ID, arg.TenantID)
-	return err
+// Claims one use and the discount against the promo's limits in a single
+// statement. The row lock it takes is held until the order transaction
+// commits, which serialises concurrent checkouts of the same promo. No row
+// means a limit or the budget was reached in the meantime.
+func (q *Queries) RedeemPromo(ctx context.Context, arg RedeemPromoParams) (Promo, error) {
+	row := q.db.QueryRow(ctx, redeemPromo, arg.DiscountAmount, arg.ID, arg.TenantID)
+	var i Promo
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Code,
+		&i.Title,
+		&i.Description,
+		&i.PromoType,
+		&i.DiscountAmount,
+		&i.MaxDiscountCap,
+		&i.CashbackAmount,
+		&i.FundedBy,
+		&i.AppliesTo,
+		&i.MinOrderAmount,
+		&i.MaxTotalUses,
+		&i.MaxUsesPerUser,
+		&i.IncludeStores,
+		&i.IsActive,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.TotalUses,
+		&i.TotalDiscountGiven,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
+	)
+	return i, err
+}
+
+const listActiveAutoApplyPromos = `-- name: ListActiveAutoApplyPromos :many
+SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at FROM promos
+WHERE tenant_id = $1
+  AND auto_apply = true
+  AND is_active = true
+  AND starts_at <= NOW()
+  AND (ends_at IS NULL OR ends_at > NOW())
+ORDER BY created_at
+`
+
+func (q *Queries) ListActiveAutoApplyPromos(ctx context.Context, tenantID uuid.UUID) ([]Promo, error) {
+	rows, err := q.db.Query(ctx, listActiveAutoApplyPromos, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Promo{}
+	for rows.Next() {
+		var i Promo
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.Code,
+			&i.Title,
+			&i.Description,
+			&i.PromoType,
+			&i.DiscountAmount,
+			&i.MaxDiscountCap,
+			&i.CashbackAmount,
+			&i.FundedBy,
+			&i.AppliesTo,
+			&i.MinOrderAmount,
+			&i.MaxTotalUses,
+			&i.MaxUsesPerUser,
+			&i.IncludeStores,
+			&i.IsActive,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.TotalUses,
+			&i.TotalDiscountGiven,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RuleType,
+			&i.BuyQty,
+			&i.GetQty,
+			&i.FirstOrderOnly,
+			&i.NewCustomerDays,
+			&i.AutoApply,
+			&i.Stacking,
+			&i.BudgetCap,
+			&i.BudgetExhaustedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const listPromoCategoryRestrictions = `-- name: ListPromoCategoryRestrictions :many
@@ -445,6 +793,31 @@ func (q *Queries) ListPromoCategoryRestrictions(ctx context.Context, promoID uui
 	return items, nil
 }
 
+const listPromoProductRestrictions = `-- name: ListPromoProductRestrictions :many
+SELECT product_id FROM promo_product_restrictions
+WHERE promo_id = $1
+`
+
+func (q *Queries) ListPromoProductRestrictions(ctx context.Context, promoID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listPromoProductRestrictions, promoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var product_id uuid.UUID
+		if err := rows.Scan(&product_id); err != nil {
+			return nil, err
+		}
+		items = append(items, product_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPromoRestaurantRestrictions = `-- name: ListPromoRestaurantRestrictions :many
 SELECT restaurant_id FROM promo_restaurant_restrictions
 WHERE promo_id = $1
@@ -470,6 +843,71 @@ func (q *Queries) ListPromoRestaurantRestrictions(ctx context.Context, promoID u
 	return items, nil
 }
 
+const listPromoTiers = `-- name: ListPromoTiers :many
+SELECT id, promo_id, min_subtotal, discount_amount, created_at FROM promo_tiers
+WHERE promo_id = $1
+ORDER BY min_subtotal
+`
+
+func (q *Queries) ListPromoTiers(ctx context.Context, promoID uuid.UUID) ([]PromoTier, error) {
+	rows, err := q.db.Query(ctx, listPromoTiers, promoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PromoTier{}
+	for rows.Next() {
+		var i PromoTier
+		if err := rows.Scan(
+			&i.ID,
+			&i.PromoID,
+			&i.MinSubtotal,
+			&i.DiscountAmount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPromoTimeWindows = `-- name: ListPromoTimeWindows :many
+SELECT id, promo_id, day_of_week, start_minute, end_minute, created_at FROM promo_time_windows
+WHERE promo_id = $1
+ORDER BY day_of_week NULLS FIRST, start_minute
+`
+
+func (q *Queries) ListPromoTimeWindows(ctx context.Context, promoID uuid.UUID) ([]PromoTimeWindow, error) {
+	rows, err := q.db.Query(ctx, listPromoTimeWindows, promoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PromoTimeWindow{}
+	for rows.Next() {
+		var i PromoTimeWindow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PromoID,
+			&i.DayOfWeek,
+			&i.StartMinute,
+			&i.EndMinute,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPromoUserEligibility = `-- name: ListPromoUserEligibility :many
 SELECT user_id FROM promo_user_eligibility
 WHERE promo_id = $1
@@ -496,7 +934,7 @@ func (q *Queries) ListPromoUserEligibility(ctx context.Context, promoID uuid.UUI
 }
 
 const listPromos = `-- name: ListPromos :many
-SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at FROM promos
+SELECT id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at FROM promos
 WHERE tenant_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -541,6 +979,15 @@ func (q *Queries) ListPromos(ctx context.Context, arg ListPromosParams) ([]Promo
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RuleType,
+			&i.BuyQty,
+			&i.GetQty,
+			&i.FirstOrderOnly,
+			&i.NewCustomerDays,
+			&i.AutoApply,
+			&i.Stacking,
+			&i.BudgetCap,
+			&i.BudgetExhaustedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -552,6 +999,39 @@ func (q *Queries) ListPromos(ctx context.Context, arg ListPromosParams) ([]Promo
 	return items, nil
 }
 
+const releasePromoUsagesForOrder = `-- name: ReleasePromoUsagesForOrder :exec
+WITH reversed AS (
+    UPDATE promo_usages
+    SET reversed_at = NOW()
+    WHERE order_id = $1 AND tenant_id = $2 AND reversed_at IS NULL
+    RETURNING promo_id, discount_amount
+)
+UPDATE promos p
+SET total_uses = GREATEST(p.total_uses - r.uses, 0),
+    total_discount_given = GREATEST(p.total_discount_given - r.amount, 0),
+    is_active = p.is_active OR p.budget_exhausted_at IS NOT NULL,
+    budget_exhausted_at = NULL
+FROM (
+    SELECT promo_id, COUNT(*)::int AS uses, SUM(discount_amount) AS amount
+    FROM reversed
+    GROUP BY promo_id
+) r
+WHERE p.id = r.promo_id
+`
+
+type ReleasePromoUsagesForOrderParams struct {
+	OrderID  uuid.UUID `json:"order_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+// Reverses an order's promo usages and gives the uses and discount back to
+// each promo. Promos deactivated for spending their budget become active
+// again.
+func (q *Queries) ReleasePromoUsagesForOrder(ctx context.Context, arg ReleasePromoUsagesForOrderParams) error {
+	_, err := q.db.Exec(ctx, releasePromoUsagesForOrder, arg.OrderID, arg.TenantID)
+	return err
+}
+
 const removePromoCategoryRestrictions = `-- name: RemovePromoCategoryRestrictions :exec
 DELETE FROM promo_category_restrictions
 WHERE promo_id = $1
@@ -562,6 +1042,16 @@ func (q *Queries) RemovePromoCategoryRestrictions(ctx context.Context, promoID u
 	return err
 }
 
+const removePromoProductRestrictions = `-- name: RemovePromoProductRestrictions :exec
+DELETE FROM promo_product_restrictions
+WHERE promo_id = $1
+`
+
+func (q *Queries) RemovePromoProductRestrictions(ctx context.Context, promoID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, removePromoProductRestrictions, promoID)
+	return err
+}
+
 const removePromoRestaurantRestrictions = `-- name: RemovePromoRestaurantRestrictions :exec
 DELETE FROM promo_restaurant_restrictions
 WHERE promo_id = $1
@@ -572,6 +1062,26 @@ func (q *Queries) RemovePromoRestaurantRestrictions(ctx context.Context, promoID
 	return err
 }
 
+const removePromoTiers = `-- name: RemovePromoTiers :exec
+DELETE FROM promo_tiers
+WHERE promo_id = $1
+`
+
+func (q *Queries) RemovePromoTiers(ctx context.Context, promoID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, removePromoTiers, promoID)
+	return err
+}
+
+const removePromoTimeWindows = `-- name: RemovePromoTimeWindows :exec
+DELETE FROM promo_time_windows
+WHERE promo_id = $1
+`
+
+func (q *Queries) RemovePromoTimeWindows(ctx context.Context, promoID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, removePromoTimeWindows, promoID)
+	return err
+}
+
 const removePromoUserEligibility = `-- name: RemovePromoUserEligibility :exec
 DELETE FROM promo_user_eligibility
 WHERE promo_id = $1
@@ -593,24 +1103,38 @@ UPDATE promos SET
     max_total_uses = COALESCE($7, max_total_uses),
     max_uses_per_user = COALESCE($8, max_uses_per_user),
     starts_at = COALESCE($9, starts_at),
-    ends_at = COALESCE($10, ends_at)
-WHERE id = $11 AND tenant_id = $12
-RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at
+    ends_at = COALESCE($10, ends_at),
+    buy_qty = COALESCE($11, buy_qty),
+    get_qty = COALESCE($12, get_qty),
+    first_order_only = COALESCE($13, first_order_only),
+    new_customer_days = COALESCE($14, new_customer_days),
+    auto_apply = COALESCE($15, auto_apply),
+    stacking = COALESCE($16, stacking),
+    budget_cap = COALESCE($17, budget_cap)
+WHERE id = $18 AND tenant_id = $19
+RETURNING id, tenant_id, code, title, description, promo_type, discount_amount, max_discount_cap, cashback_amount, funded_by, applies_to, min_order_amount, max_total_uses, max_uses_per_user, include_stores, is_active, starts_at, ends_at, total_uses, total_discount_given, created_by, created_at, updated_at, rule_type, buy_qty, get_qty, first_order_only, new_customer_days, auto_apply, stacking, budget_cap, budget_exhausted_at
 `
 
 type UpdatePromoParams struct {
-	Title          sql.NullString     `json:"title"`
-	Description    sql.NullString     `json:"description"`
-	DiscountAmount pgtype.Numeric     `json:"discount_amount"`
-	MaxDiscountCap pgtype.Numeric     `json:"max_discount_cap"`
-	CashbackAmount pgtype.Numeric     `json:"cashback_amount"`
-	MinOrderAmount pgtype.Numeric     `json:"min_order_amount"`
-	MaxTotalUses   *int32             `json:"max_total_uses"`
-	MaxUsesPerUser *int32             `json:"max_uses_per_user"`
-	StartsAt       pgtype.Timestamptz `json:"starts_at"`
-	EndsAt         pgtype.Timestamptz `json:"ends_at"`
-	ID             uuid.UUID          `json:"id"`
-	TenantID       uuid.UUID          `json:"tenant_id"`
+	Title           sql.NullString     `json:"title"`
+	Description     sql.NullString     `json:"description"`
+	DiscountAmount  pgtype.Numeric     `json:"discount_amount"`
+	MaxDiscountCap  pgtype.Numeric     `json:"max_discount_cap"`
+	CashbackAmount  pgtype.Numeric     `json:"cashback_amount"`
+	MinOrderAmount  pgtype.Numeric     `json:"min_order_amount"`
+	MaxTotalUses    *int32             `json:"max_total_uses"`
+	MaxUsesPerUser  *int32             `json:"max_uses_per_user"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	EndsAt          pgtype.Timestamptz `json:"ends_at"`
+	BuyQty          *int32             `json:"buy_qty"`
+	GetQty          *int32             `json:"get_qty"`
+	FirstOrderOnly  *bool              `json:"first_order_only"`
+	NewCustomerDays *int32             `json:"new_customer_days"`
+	AutoApply       *bool              `json:"auto_apply"`
+	Stacking        NullPromoStacking  `json:"stacking"`
+	BudgetCap       pgtype.Numeric     `json:"budget_cap"`
+	ID              uuid.UUID          `json:"id"`
+	TenantID        uuid.UUID          `json:"tenant_id"`
 }
 
 func (q *Queries) UpdatePromo(ctx context.Context, arg UpdatePromoParams) (Promo, error) {
@@ -625,6 +1149,13 @@ func (q *Queries) UpdatePromo(ctx context.Context, arg UpdatePromoParams) (Promo
 		arg.MaxUsesPerUser,
 		arg.StartsAt,
 		arg.EndsAt,
+		arg.BuyQty,
+		arg.GetQty,
+		arg.FirstOrderOnly,
+		arg.NewCustomerDays,
+		arg.AutoApply,
+		arg.Stacking,
+		arg.BudgetCap,
 		arg.ID,
 		arg.TenantID,
 	)
@@ -653,6 +1184,15 @@ func (q *Queries) UpdatePromo(ctx context.Context, arg UpdatePromoParams) (Promo
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RuleType,
+		&i.BuyQty,
+		&i.GetQty,
+		&i.FirstOrderOnly,
+		&i.NewCustomerDays,
+		&i.AutoApply,
+		&i.Stacking,
+		&i.BudgetCap,
+		&i.BudgetExhaustedAt,
 	)
 	return i, err
 }
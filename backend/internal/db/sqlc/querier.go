@@ -14,58 +14,98 @@ import (
 )
 
 type Querier interface {
+	AcceptAssignmentOffer(ctx context.Context, arg AcceptAssignmentOfferParams) error
 	AddPromoCategoryRestriction(ctx context.Context, arg AddPromoCategoryRestrictionParams) error
+	AddPromoProductRestriction(ctx context.Context, arg AddPromoProductRestrictionParams) error
 	AddPromoRestaurantRestriction(ctx context.Context, arg AddPromoRestaurantRestrictionParams) error
 	AddPromoUserEligibility(ctx context.Context, arg AddPromoUserEligibilityParams) error
+	AddPromoUserEligibilityBulk(ctx context.Context, arg AddPromoUserEligibilityBulkParams) (int64, error)
+	AddRiderCashInHand(ctx context.Context, arg AddRiderCashInHandParams) error
 	AddTimelineEvent(ctx context.Context, arg AddTimelineEventParams) (OrderTimelineEvent, error)
 	AdjustStock(ctx context.Context, arg AdjustStockParams) (InventoryItem, error)
+	AdvanceReportSubscription(ctx context.Context, arg AdvanceReportSubscriptionParams) (int64, error)
 	AppealPenalty(ctx context.Context, arg AppealPenaltyParams) (RiderPenalty, error)
 	AppendLocationHistory(ctx context.Context, arg AppendLocationHistoryParams) (RiderLocationHistory, error)
 	ApproveRefund(ctx context.Context, arg ApproveRefundParams) (Refund, error)
 	AssignRiderToOrder(ctx context.Context, arg AssignRiderToOrderParams) (Order, error)
+	AttachEarningsToPayout(ctx context.Context, arg AttachEarningsToPayoutParams) ([]AttachEarningsToPayoutRow, error)
+	AttachInvoiceAdjustments(ctx context.Context, arg AttachInvoiceAdjustmentsParams) (int64, error)
+	AttachPenaltyToPayout(ctx context.Context, arg AttachPenaltyToPayoutParams) error
+	CancelRiderShift(ctx context.Context, arg CancelRiderShiftParams) (RiderShift, error)
 	CheckAllPickupsInStatus(ctx context.Context, arg CheckAllPickupsInStatusParams) (bool, error)
 	CheckPromoUserEligibility(ctx context.Context, arg CheckPromoUserEligibilityParams) (int64, error)
+	ClaimOrderForRider(ctx context.Context, arg ClaimOrderForRiderParams) (Order, error)
+	ClaimReportExport(ctx context.Context) (ReportExport, error)
 	ClearDefaultAddresses(ctx context.Context, userID uuid.UUID) error
+	ClearPayoutPenalties(ctx context.Context, arg ClearPayoutPenaltiesParams) error
+	ClearTaxInvoiceFailure(ctx context.Context, orderID uuid.UUID) error
 	ClearUserPushToken(ctx context.Context, id uuid.UUID) error
+	CompleteCheckedInRiderShifts(ctx context.Context, arg CompleteCheckedInRiderShiftsParams) error
+	CompleteDeliveryProof(ctx context.Context, arg CompleteDeliveryProofParams) (DeliveryProof, error)
+	CompleteReportExport(ctx context.Context, arg CompleteReportExportParams) (ReportExport, error)
+	CompleteRiderPayout(ctx context.Context, arg CompleteRiderPayoutParams) (RiderPayout, error)
+	CompleteVendorPayout(ctx context.Context, arg CompleteVendorPayoutParams) (VendorPayout, error)
 	ConsumeReservedStock(ctx context.Context, arg ConsumeReservedStockParams) (InventoryItem, error)
+	CountActiveShiftTemplates(ctx context.Context, arg CountActiveShiftTemplatesParams) (int64, error)
 	CountBannersByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	CountInventoryByRestaurant(ctx context.Context, arg CountInventoryByRestaurantParams) (int64, error)
+	CountInvoiceDisputes(ctx context.Context, arg CountInvoiceDisputesParams) (int64, error)
+	CountInvoiceLineItems(ctx context.Context, arg CountInvoiceLineItemsParams) (int64, error)
 	CountInvoicesByRestaurant(ctx context.Context, arg CountInvoicesByRestaurantParams) (int64, error)
 	CountInvoicesByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	CountLowStock(ctx context.Context, arg CountLowStockParams) (int64, error)
 	CountNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
-	CountOrderIssuesByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountOrderIssuesByTenant(ctx context.Context, arg CountOrderIssuesByTenantParams) (int64, error)
 	CountOrdersByCustomer(ctx context.Context, arg CountOrdersByCustomerParams) (int64, error)
 	CountOrdersByRestaurant(ctx context.Context, arg CountOrdersByRestaurantParams) (int64, error)
 	CountOrdersByRestaurantAndPeriod(ctx context.Context, arg CountOrdersByRestaurantAndPeriodParams) (CountOrdersByRestaurantAndPeriodRow, error)
 	CountOrdersByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountOutstandingPurchaseOrderItems(ctx context.Context, purchaseOrderID uuid.UUID) (int64, error)
+	CountOverlappingRiderShifts(ctx context.Context, arg CountOverlappingRiderShiftsParams) (int64, error)
 	CountProductsByRestaurant(ctx context.Context, arg CountProductsByRestaurantParams) (int64, error)
 	CountPromos(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountPurchaseOrders(ctx context.Context, arg CountPurchaseOrdersParams) (int64, error)
 	CountRecentOTPs(ctx context.Context, arg CountRecentOTPsParams) (int64, error)
-	CountRestaurantsByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountRestaurantsByTenant(ctx context.Context, arg CountRestaurantsByTenantParams) (int64, error)
 	CountReviewsByRestaurant(ctx context.Context, arg CountReviewsByRestaurantParams) (int64, error)
+	CountRiderPayoutBatches(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountRiderPayoutsByRider(ctx context.Context, arg CountRiderPayoutsByRiderParams) (int64, error)
 	CountRidersByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	CountStoriesByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountSuppliers(ctx context.Context, tenantID uuid.UUID) (int64, error)
+	CountVendorPayoutBatches(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	CountWalletTransactions(ctx context.Context, userID uuid.UUID) (int64, error)
 	CreateAddress(ctx context.Context, arg CreateAddressParams) (UserAddress, error)
+	CreateAssignmentOffer(ctx context.Context, arg CreateAssignmentOfferParams) error
 	CreateAttendance(ctx context.Context, arg CreateAttendanceParams) (RiderAttendance, error)
 	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
 	CreateBanner(ctx context.Context, arg CreateBannerParams) (Banner, error)
 	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateCodCollection(ctx context.Context, arg CreateCodCollectionParams) (CodCollection, error)
+	CreateEarningPeakWindow(ctx context.Context, arg CreateEarningPeakWindowParams) (RiderEarningPeakWindow, error)
+	CreateEarningRule(ctx context.Context, arg CreateEarningRuleParams) (RiderEarningRule, error)
+	CreateEarningSurge(ctx context.Context, arg CreateEarningSurgeParams) (RiderEarningSurge, error)
 	CreateHub(ctx context.Context, arg CreateHubParams) (Hub, error)
 	CreateHubArea(ctx context.Context, arg CreateHubAreaParams) (HubCoverageArea, error)
 	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
 	CreateInventoryAdjustment(ctx context.Context, arg CreateInventoryAdjustmentParams) (InventoryAdjustment, error)
 	CreateInventoryItem(ctx context.Context, arg CreateInventoryItemParams) (InventoryItem, error)
 	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateInvoiceDispute(ctx context.Context, arg CreateInvoiceDisputeParams) (InvoiceDispute, error)
+	CreateInvoiceDisputeComment(ctx context.Context, arg CreateInvoiceDisputeCommentParams) (InvoiceDisputeComment, error)
+	CreateInvoiceLineItem(ctx context.Context, arg CreateInvoiceLineItemParams) error
+	CreateInvoiceNote(ctx context.Context, arg CreateInvoiceNoteParams) (InvoiceNote, error)
+	CreateIssueInvoiceAdjustment(ctx context.Context, arg CreateIssueInvoiceAdjustmentParams) (InvoiceAdjustment, error)
+	CreateIssuePenalty(ctx context.Context, arg CreateIssuePenaltyParams) (RiderPenalty, error)
 	CreateLedgerAccount(ctx context.Context, arg CreateLedgerAccountParams) (LedgerAccount, error)
 	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) (LedgerEntry, error)
+	CreateManualInvoiceAdjustment(ctx context.Context, arg CreateManualInvoiceAdjustmentParams) (InvoiceAdjustment, error)
 	CreateModifierGroup(ctx context.Context, arg CreateModifierGroupParams) (ProductModifierGroup, error)
 	CreateModifierOption(ctx context.Context, arg CreateModifierOptionParams) (ProductModifierOption, error)
+	CreateNoteInvoiceAdjustment(ctx context.Context, arg CreateNoteInvoiceAdjustmentParams) (InvoiceAdjustment, error)
 	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
 	CreateOTPVerification(ctx context.Context, arg CreateOTPVerificationParams) (OtpVerification, error)
 	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
-	CreateOrderAnalytics(ctx context.Context, arg CreateOrderAnalyticsParams) (OrderAnalytic, error)
 	CreateOrderIssue(ctx context.Context, arg CreateOrderIssueParams) (OrderIssue, error)
 	CreateOrderIssueMessage(ctx context.Context, arg CreateOrderIssueMessageParams) (OrderIssueMessage, error)
 	CreateOrderItem(ctx context.Context, arg CreateOrderItemParams) (OrderItem, error)
@@ -73,39 +113,83 @@ type Querier interface {
 	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
 	CreatePromo(ctx context.Context, arg CreatePromoParams) (Promo, error)
+	CreatePromoTier(ctx context.Context, arg CreatePromoTierParams) (PromoTier, error)
+	CreatePromoTimeWindow(ctx context.Context, arg CreatePromoTimeWindowParams) (PromoTimeWindow, error)
 	CreatePromoUsage(ctx context.Context, arg CreatePromoUsageParams) (PromoUsage, error)
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error)
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateRefund(ctx context.Context, arg CreateRefundParams) (Refund, error)
+	CreateRefundInvoiceAdjustment(ctx context.Context, arg CreateRefundInvoiceAdjustmentParams) (InvoiceAdjustment, error)
+	CreateReportDelivery(ctx context.Context, arg CreateReportDeliveryParams) error
+	CreateReportExport(ctx context.Context, arg CreateReportExportParams) (ReportExport, error)
+	CreateReportSubscription(ctx context.Context, arg CreateReportSubscriptionParams) (ReportSubscription, error)
 	CreateRestaurant(ctx context.Context, arg CreateRestaurantParams) (Restaurant, error)
 	CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error)
 	CreateRider(ctx context.Context, arg CreateRiderParams) (Rider, error)
+	CreateRiderApplication(ctx context.Context, arg CreateRiderApplicationParams) (RiderApplication, error)
+	CreateRiderCashDeposit(ctx context.Context, arg CreateRiderCashDepositParams) (RiderCashDeposit, error)
+	CreateRiderDocument(ctx context.Context, arg CreateRiderDocumentParams) (RiderDocument, error)
 	CreateRiderEarning(ctx context.Context, arg CreateRiderEarningParams) (RiderEarning, error)
+	CreateRiderPayout(ctx context.Context, arg CreateRiderPayoutParams) (RiderPayout, error)
+	CreateRiderPayoutBatch(ctx context.Context, arg CreateRiderPayoutBatchParams) (RiderPayoutBatch, error)
 	CreateRiderPenalty(ctx context.Context, arg CreateRiderPenaltyParams) (RiderPenalty, error)
+	CreateRiderShift(ctx context.Context, arg CreateRiderShiftParams) (RiderShift, error)
 	CreateSearchLog(ctx context.Context, arg CreateSearchLogParams) (SearchLog, error)
+	CreateShiftSwap(ctx context.Context, arg CreateShiftSwapParams) (RiderShiftSwap, error)
+	CreateShiftTemplate(ctx context.Context, arg CreateShiftTemplateParams) (RiderShiftTemplate, error)
 	CreateStory(ctx context.Context, arg CreateStoryParams) (Story, error)
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
+	CreateTaxInvoice(ctx context.Context, arg CreateTaxInvoiceParams) (TaxInvoice, error)
 	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error)
 	CreateTimelineEvent(ctx context.Context, arg CreateTimelineEventParams) (OrderTimelineEvent, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (PaymentTransaction, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateVendorPayout(ctx context.Context, arg CreateVendorPayoutParams) (VendorPayout, error)
+	CreateVendorPayoutBatch(ctx context.Context, arg CreateVendorPayoutBatchParams) (VendorPayoutBatch, error)
 	CreateWalletTransaction(ctx context.Context, arg CreateWalletTransactionParams) (WalletTransaction, error)
 	CreditUserWallet(ctx context.Context, arg CreditUserWalletParams) error
 	DeactivateProductDiscount(ctx context.Context, productID uuid.UUID) error
 	DeactivatePromo(ctx context.Context, arg DeactivatePromoParams) (Promo, error)
 	DebitUserWallet(ctx context.Context, arg DebitUserWalletParams) error
+	DeclineAssignmentOffer(ctx context.Context, arg DeclineAssignmentOfferParams) (RiderAssignmentOffer, error)
+	DeductRiderCashInHand(ctx context.Context, arg DeductRiderCashInHandParams) error
+	DeductRiderPendingBalance(ctx context.Context, arg DeductRiderPendingBalanceParams) error
 	DeleteAddress(ctx context.Context, arg DeleteAddressParams) error
 	DeleteBanner(ctx context.Context, arg DeleteBannerParams) error
 	DeleteCategory(ctx context.Context, arg DeleteCategoryParams) error
+	DeleteEarningRule(ctx context.Context, arg DeleteEarningRuleParams) error
 	DeleteHub(ctx context.Context, arg DeleteHubParams) error
 	DeleteHubArea(ctx context.Context, id uuid.UUID) error
+	DeleteLocationHistoryBefore(ctx context.Context, arg DeleteLocationHistoryBeforeParams) (int64, error)
 	DeleteModifierGroup(ctx context.Context, id uuid.UUID) error
 	DeleteModifierOption(ctx context.Context, id uuid.UUID) error
 	DeleteModifierOptionsByGroup(ctx context.Context, modifierGroupID uuid.UUID) error
 	DeleteOperatingHours(ctx context.Context, restaurantID uuid.UUID) error
 	DeleteProduct(ctx context.Context, arg DeleteProductParams) error
+	DeleteReportSubscription(ctx context.Context, arg DeleteReportSubscriptionParams) (int64, error)
 	DeleteRestaurant(ctx context.Context, arg DeleteRestaurantParams) error
 	DeleteRider(ctx context.Context, arg DeleteRiderParams) error
+	DeleteRiderPayout(ctx context.Context, id uuid.UUID) error
+	DeleteScheduledEarningSurge(ctx context.Context, arg DeleteScheduledEarningSurgeParams) (int64, error)
+	DeleteShiftTemplate(ctx context.Context, arg DeleteShiftTemplateParams) (int64, error)
 	DeleteStory(ctx context.Context, arg DeleteStoryParams) error
+	DeleteVendorPayoutBatch(ctx context.Context, id uuid.UUID) error
+	DetachPayoutEarnings(ctx context.Context, payoutID pgtype.UUID) error
+	DetachPayoutPenalties(ctx context.Context, payoutID pgtype.UUID) error
+	EndEarningSurge(ctx context.Context, arg EndEarningSurgeParams) (RiderEarningSurge, error)
+	ExpireAssignmentOffers(ctx context.Context, before time.Time) (int64, error)
 	ExpireDiscounts(ctx context.Context) error
+	ExpireRiderDocument(ctx context.Context, id uuid.UUID) (int64, error)
+	ExpireRiderVerification(ctx context.Context, id uuid.UUID) (int64, error)
+	ExportInvoices(ctx context.Context, arg ExportInvoicesParams) ([]ExportInvoicesRow, error)
+	ExportOrders(ctx context.Context, arg ExportOrdersParams) ([]ExportOrdersRow, error)
+	ExportRefunds(ctx context.Context, arg ExportRefundsParams) ([]ExportRefundsRow, error)
+	ExportRestaurantSalesByDay(ctx context.Context, arg ExportRestaurantSalesByDayParams) ([]ExportRestaurantSalesByDayRow, error)
+	ExportRiderAnalytics(ctx context.Context, arg ExportRiderAnalyticsParams) ([]ExportRiderAnalyticsRow, error)
+	FailReportExport(ctx context.Context, arg FailReportExportParams) error
+	FailRiderPayout(ctx context.Context, arg FailRiderPayoutParams) (RiderPayout, error)
+	FailVendorPayout(ctx context.Context, arg FailVendorPayoutParams) (VendorPayout, error)
 	FinalizeInvoice(ctx context.Context, arg FinalizeInvoiceParams) (Invoice, error)
 	GenerateOrderNumber(ctx context.Context, arg GenerateOrderNumberParams) (interface{}, error)
 	GetActiveAttendance(ctx context.Context, riderID uuid.UUID) (RiderAttendance, error)
@@ -115,11 +199,19 @@ type Querier interface {
 	GetAdminOrderVolume(ctx context.Context, arg GetAdminOrderVolumeParams) ([]GetAdminOrderVolumeRow, error)
 	GetAdminOverview(ctx context.Context, arg GetAdminOverviewParams) (GetAdminOverviewRow, error)
 	GetAdminRevenueByPeriod(ctx context.Context, arg GetAdminRevenueByPeriodParams) ([]GetAdminRevenueByPeriodRow, error)
+	GetApplicableEarningRule(ctx context.Context, arg GetApplicableEarningRuleParams) (RiderEarningRule, error)
 	GetBannerByID(ctx context.Context, arg GetBannerByIDParams) (Banner, error)
 	GetCategoryByID(ctx context.Context, arg GetCategoryByIDParams) (Category, error)
-	GetDashboardToday(ctx context.Context, tenantID uuid.UUID) (GetDashboardTodayRow, error)
+	GetCategoryRestaurantID(ctx context.Context, arg GetCategoryRestaurantIDParams) (pgtype.UUID, error)
+	GetCheckInRiderShift(ctx context.Context, arg GetCheckInRiderShiftParams) (RiderShift, error)
+	GetCodCollectionByOrder(ctx context.Context, arg GetCodCollectionByOrderParams) (CodCollection, error)
+	GetCustomerActivitySummary(ctx context.Context, arg GetCustomerActivitySummaryParams) (GetCustomerActivitySummaryRow, error)
+	GetCustomerBaseSummary(ctx context.Context, arg GetCustomerBaseSummaryParams) (GetCustomerBaseSummaryRow, error)
+	GetDashboardToday(ctx context.Context, arg GetDashboardTodayParams) (GetDashboardTodayRow, error)
 	GetDashboardTrend(ctx context.Context, arg GetDashboardTrendParams) ([]GetDashboardTrendRow, error)
+	GetDeliveryProofByOrder(ctx context.Context, arg GetDeliveryProofByOrderParams) (DeliveryProof, error)
 	GetDeliveryZoneConfig(ctx context.Context, tenantID uuid.UUID) (DeliveryZoneConfig, error)
+	GetEarningRule(ctx context.Context, arg GetEarningRuleParams) (RiderEarningRule, error)
 	GetFinanceSummary(ctx context.Context, tenantID uuid.UUID) (GetFinanceSummaryRow, error)
 	GetHubAreaByID(ctx context.Context, id uuid.UUID) (HubCoverageArea, error)
 	GetHubAreaByName(ctx context.Context, arg GetHubAreaByNameParams) (HubCoverageArea, error)
@@ -128,14 +220,26 @@ type Querier interface {
 	GetInventoryByProductAndRestaurant(ctx context.Context, arg GetInventoryByProductAndRestaurantParams) (InventoryItem, error)
 	GetInventoryForUpdate(ctx context.Context, arg GetInventoryForUpdateParams) (InventoryItem, error)
 	GetInventoryItem(ctx context.Context, arg GetInventoryItemParams) (InventoryItem, error)
+	GetInventoryItemForUpdate(ctx context.Context, arg GetInventoryItemForUpdateParams) (InventoryItem, error)
+	GetInvoiceAdjustment(ctx context.Context, arg GetInvoiceAdjustmentParams) (InvoiceAdjustment, error)
 	GetInvoiceByID(ctx context.Context, arg GetInvoiceByIDParams) (Invoice, error)
 	GetInvoiceByPeriod(ctx context.Context, arg GetInvoiceByPeriodParams) (Invoice, error)
+	GetInvoiceDispute(ctx context.Context, arg GetInvoiceDisputeParams) (InvoiceDispute, error)
+	GetInvoiceDisputeForUpdate(ctx context.Context, arg GetInvoiceDisputeForUpdateParams) (InvoiceDispute, error)
+	GetInvoiceDisputeRestaurantID(ctx context.Context, arg GetInvoiceDisputeRestaurantIDParams) (uuid.UUID, error)
+	GetInvoiceLineItem(ctx context.Context, arg GetInvoiceLineItemParams) (InvoiceLineItem, error)
+	GetInvoiceNote(ctx context.Context, arg GetInvoiceNoteParams) (InvoiceNote, error)
+	GetInvoiceNoteRestaurantID(ctx context.Context, arg GetInvoiceNoteRestaurantIDParams) (uuid.UUID, error)
+	GetInvoiceRestaurantID(ctx context.Context, arg GetInvoiceRestaurantIDParams) (uuid.UUID, error)
+	GetJobWatermark(ctx context.Context, job string) (JobWatermark, error)
 	GetLastLedgerEntryBalance(ctx context.Context, accountID uuid.UUID) (pgtype.Numeric, error)
 	GetLatestOTP(ctx context.Context, arg GetLatestOTPParams) (OtpVerification, error)
+	GetLatestRiderApplication(ctx context.Context, arg GetLatestRiderApplicationParams) (RiderApplication, error)
 	GetLedgerAccountByCode(ctx context.Context, code string) (LedgerAccount, error)
 	GetModifierGroupByID(ctx context.Context, id uuid.UUID) (ProductModifierGroup, error)
 	GetNotificationByID(ctx context.Context, arg GetNotificationByIDParams) (Notification, error)
 	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (NotificationPreference, error)
+	GetOpenVendorPayoutByInvoice(ctx context.Context, arg GetOpenVendorPayoutByInvoiceParams) (VendorPayout, error)
 	GetOrderAnalyticsByOrderID(ctx context.Context, arg GetOrderAnalyticsByOrderIDParams) (OrderAnalytic, error)
 	GetOrderByID(ctx context.Context, arg GetOrderByIDParams) (Order, error)
 	GetOrderByNumber(ctx context.Context, arg GetOrderByNumberParams) (Order, error)
@@ -146,31 +250,64 @@ type Querier interface {
 	GetOrderItemsByRestaurant(ctx context.Context, arg GetOrderItemsByRestaurantParams) ([]OrderItem, error)
 	GetOrderPickup(ctx context.Context, arg GetOrderPickupParams) (OrderPickup, error)
 	GetOrderPickupsByOrder(ctx context.Context, orderID uuid.UUID) ([]OrderPickup, error)
-	GetOrderPickupsByRestaurantAndPeriod(ctx context.Context, arg GetOrderPickupsByRestaurantAndPeriodParams) ([]OrderPickup, error)
 	GetOrderStatusBreakdown(ctx context.Context, arg GetOrderStatusBreakdownParams) ([]GetOrderStatusBreakdownRow, error)
 	GetPeakHours(ctx context.Context, arg GetPeakHoursParams) ([]GetPeakHoursRow, error)
 	GetPenaltyByID(ctx context.Context, arg GetPenaltyByIDParams) (RiderPenalty, error)
-	GetPendingOrderCount(ctx context.Context, tenantID uuid.UUID) (int32, error)
+	GetPendingOrderCount(ctx context.Context, arg GetPendingOrderCountParams) (int32, error)
+	GetPendingRiderCashDepositForUpdate(ctx context.Context, arg GetPendingRiderCashDepositForUpdateParams) (RiderCashDeposit, error)
 	GetPickupByOrderAndRestaurant(ctx context.Context, arg GetPickupByOrderAndRestaurantParams) (OrderPickup, error)
 	GetPickupCountByOrder(ctx context.Context, orderID uuid.UUID) (int64, error)
+	GetPickupRefundableAmount(ctx context.Context, arg GetPickupRefundableAmountParams) (pgtype.Numeric, error)
+	GetProcessedRefundTotal(ctx context.Context, arg GetProcessedRefundTotalParams) (pgtype.Numeric, error)
 	GetProductByID(ctx context.Context, arg GetProductByIDParams) (Product, error)
 	GetProductByIDPublic(ctx context.Context, id uuid.UUID) (Product, error)
+	GetProductRestaurantID(ctx context.Context, arg GetProductRestaurantIDParams) (uuid.UUID, error)
 	GetPromoByCode(ctx context.Context, arg GetPromoByCodeParams) (Promo, error)
 	GetPromoByID(ctx context.Context, arg GetPromoByIDParams) (Promo, error)
+	GetPromoCustomerHistory(ctx context.Context, arg GetPromoCustomerHistoryParams) (GetPromoCustomerHistoryRow, error)
+	GetPromoRedemptionReport(ctx context.Context, arg GetPromoRedemptionReportParams) ([]GetPromoRedemptionReportRow, error)
+	GetPurchaseOrder(ctx context.Context, arg GetPurchaseOrderParams) (PurchaseOrder, error)
+	GetPurchaseOrderForUpdate(ctx context.Context, arg GetPurchaseOrderForUpdateParams) (PurchaseOrder, error)
+	GetPurchaseOrderRestaurantID(ctx context.Context, arg GetPurchaseOrderRestaurantIDParams) (uuid.UUID, error)
 	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
 	GetRefundByID(ctx context.Context, arg GetRefundByIDParams) (Refund, error)
+	GetReportExport(ctx context.Context, arg GetReportExportParams) (ReportExport, error)
+	GetReportSubscription(ctx context.Context, arg GetReportSubscriptionParams) (ReportSubscription, error)
 	GetRestaurantAvgRating(ctx context.Context, restaurantID uuid.UUID) (GetRestaurantAvgRatingRow, error)
 	GetRestaurantByID(ctx context.Context, arg GetRestaurantByIDParams) (Restaurant, error)
 	GetRestaurantBySlug(ctx context.Context, arg GetRestaurantBySlugParams) (Restaurant, error)
+	GetRestaurantPayoutAccount(ctx context.Context, arg GetRestaurantPayoutAccountParams) (RestaurantPayoutAccount, error)
+	GetRestaurantVatRegistration(ctx context.Context, arg GetRestaurantVatRegistrationParams) (RestaurantVatRegistration, error)
 	GetReviewByID(ctx context.Context, arg GetReviewByIDParams) (Review, error)
 	GetReviewByOrderAndUser(ctx context.Context, arg GetReviewByOrderAndUserParams) (Review, error)
+	GetReviewRestaurantID(ctx context.Context, arg GetReviewRestaurantIDParams) (uuid.UUID, error)
 	GetRiderAnalytics(ctx context.Context, arg GetRiderAnalyticsParams) ([]GetRiderAnalyticsRow, error)
+	GetRiderApplication(ctx context.Context, arg GetRiderApplicationParams) (RiderApplication, error)
+	GetRiderAvgRating(ctx context.Context, riderID pgtype.UUID) (GetRiderAvgRatingRow, error)
 	GetRiderByID(ctx context.Context, arg GetRiderByIDParams) (Rider, error)
 	GetRiderByUserID(ctx context.Context, arg GetRiderByUserIDParams) (Rider, error)
+	GetRiderCashDeposit(ctx context.Context, arg GetRiderCashDepositParams) (RiderCashDeposit, error)
+	GetRiderDocument(ctx context.Context, arg GetRiderDocumentParams) (RiderDocument, error)
+	GetRiderEarningTotal(ctx context.Context, arg GetRiderEarningTotalParams) (pgtype.Numeric, error)
+	GetRiderForUpdate(ctx context.Context, arg GetRiderForUpdateParams) (Rider, error)
 	GetRiderLocation(ctx context.Context, riderID uuid.UUID) (RiderLocation, error)
+	GetRiderPayout(ctx context.Context, arg GetRiderPayoutParams) (RiderPayout, error)
+	GetRiderPayoutBatch(ctx context.Context, arg GetRiderPayoutBatchParams) (RiderPayoutBatch, error)
+	GetRiderPayoutForUpdate(ctx context.Context, arg GetRiderPayoutForUpdateParams) (RiderPayout, error)
+	GetRiderShift(ctx context.Context, arg GetRiderShiftParams) (RiderShift, error)
+	GetRiderShiftForUpdate(ctx context.Context, arg GetRiderShiftForUpdateParams) (RiderShift, error)
 	GetSalesReport(ctx context.Context, arg GetSalesReportParams) ([]GetSalesReportRow, error)
+	GetScheduledOrderCharges(ctx context.Context, arg GetScheduledOrderChargesParams) (GetScheduledOrderChargesRow, error)
+	GetScheduledSalesSummary(ctx context.Context, arg GetScheduledSalesSummaryParams) (GetScheduledSalesSummaryRow, error)
+	GetScheduledTopProducts(ctx context.Context, arg GetScheduledTopProductsParams) ([]GetScheduledTopProductsRow, error)
 	GetSectionByID(ctx context.Context, arg GetSectionByIDParams) (HomepageSection, error)
+	GetSettlementSchedule(ctx context.Context, tenantID uuid.UUID) (SettlementSchedule, error)
+	GetShiftSwapForUpdate(ctx context.Context, arg GetShiftSwapForUpdateParams) (RiderShiftSwap, error)
+	GetShiftTemplate(ctx context.Context, arg GetShiftTemplateParams) (RiderShiftTemplate, error)
+	GetStockValuation(ctx context.Context, arg GetStockValuationParams) ([]GetStockValuationRow, error)
 	GetStoryByID(ctx context.Context, arg GetStoryByIDParams) (Story, error)
+	GetSupplier(ctx context.Context, arg GetSupplierParams) (Supplier, error)
+	GetTaxSettings(ctx context.Context, tenantID uuid.UUID) (TaxSetting, error)
 	GetTenantAnalytics(ctx context.Context, arg GetTenantAnalyticsParams) (GetTenantAnalyticsRow, error)
 	GetTenantByDomain(ctx context.Context, customDomain sql.NullString) (Tenant, error)
 	GetTenantByID(ctx context.Context, id uuid.UUID) (Tenant, error)
@@ -187,80 +324,181 @@ type Querier interface {
 	GetUserByPhone(ctx context.Context, arg GetUserByPhoneParams) (User, error)
 	GetUserDevicePushToken(ctx context.Context, id uuid.UUID) (sql.NullString, error)
 	GetUserWalletBalance(ctx context.Context, id uuid.UUID) (pgtype.Numeric, error)
+	GetVendorPayoutBatch(ctx context.Context, arg GetVendorPayoutBatchParams) (VendorPayoutBatch, error)
+	GetVendorPayoutForUpdate(ctx context.Context, arg GetVendorPayoutForUpdateParams) (VendorPayout, error)
+	GetWeeklyRiderPayoutBatch(ctx context.Context, arg GetWeeklyRiderPayoutBatchParams) (RiderPayoutBatch, error)
+	IncrementDeliveryProofOtpAttempts(ctx context.Context, arg IncrementDeliveryProofOtpAttemptsParams) (int32, error)
 	IncrementOTPAttempts(ctx context.Context, id uuid.UUID) (OtpVerification, error)
-	IncrementPromoUsage(ctx context.Context, arg IncrementPromoUsageParams) error
+	ListActiveAutoApplyPromos(ctx context.Context, tenantID uuid.UUID) ([]Promo, error)
 	ListActiveBanners(ctx context.Context, tenantID uuid.UUID) ([]Banner, error)
+	ListActiveEarningSurges(ctx context.Context, arg ListActiveEarningSurgesParams) ([]RiderEarningSurge, error)
 	ListActiveOrdersByRider(ctx context.Context, arg ListActiveOrdersByRiderParams) ([]Order, error)
 	ListActiveRestaurantsByTenant(ctx context.Context, tenantID uuid.UUID) ([]ListActiveRestaurantsByTenantRow, error)
 	ListActiveSections(ctx context.Context, tenantID uuid.UUID) ([]HomepageSection, error)
 	ListActiveStories(ctx context.Context, tenantID uuid.UUID) ([]Story, error)
+	ListActiveTenantIDs(ctx context.Context) ([]uuid.UUID, error)
 	ListAddresses(ctx context.Context, userID uuid.UUID) ([]UserAddress, error)
+	ListApplicationDocuments(ctx context.Context, applicationID pgtype.UUID) ([]RiderDocument, error)
 	ListAttendanceByRider(ctx context.Context, arg ListAttendanceByRiderParams) ([]RiderAttendance, error)
 	ListAttendanceByTenant(ctx context.Context, arg ListAttendanceByTenantParams) ([]RiderAttendance, error)
+	ListAttendanceInRange(ctx context.Context, arg ListAttendanceInRangeParams) ([]RiderAttendance, error)
 	ListAuditLogsByResource(ctx context.Context, arg ListAuditLogsByResourceParams) ([]AuditLog, error)
 	ListAvailableByHubAndArea(ctx context.Context, arg ListAvailableByHubAndAreaParams) ([]Restaurant, error)
 	ListAvailableProductsByRestaurant(ctx context.Context, restaurantID uuid.UUID) ([]Product, error)
 	ListAvailableRidersByHub(ctx context.Context, arg ListAvailableRidersByHubParams) ([]Rider, error)
 	ListBannersByTenant(ctx context.Context, arg ListBannersByTenantParams) ([]Banner, error)
+	ListCancellationCountsForDay(ctx context.Context, arg ListCancellationCountsForDayParams) ([]ListCancellationCountsForDayRow, error)
 	ListCategoriesByRestaurant(ctx context.Context, arg ListCategoriesByRestaurantParams) ([]Category, error)
+	ListCodCollectionsByRider(ctx context.Context, arg ListCodCollectionsByRiderParams) ([]CodCollection, error)
 	ListCreatedOrdersPastTimeout(ctx context.Context, limit int32) ([]Order, error)
+	ListCustomerLTVByAcquisitionPromo(ctx context.Context, arg ListCustomerLTVByAcquisitionPromoParams) ([]ListCustomerLTVByAcquisitionPromoRow, error)
+	ListCustomerRFMInputs(ctx context.Context, tenantID pgtype.UUID) ([]ListCustomerRFMInputsRow, error)
 	ListDeliveredOrdersByRider(ctx context.Context, arg ListDeliveredOrdersByRiderParams) ([]Order, error)
+	ListDeliveryTimingsForDay(ctx context.Context, arg ListDeliveryTimingsForDayParams) ([]ListDeliveryTimingsForDayRow, error)
+	ListDueReportSubscriptions(ctx context.Context, arg ListDueReportSubscriptionsParams) ([]ListDueReportSubscriptionsRow, error)
+	ListEarningPeakWindows(ctx context.Context, ruleID uuid.UUID) ([]RiderEarningPeakWindow, error)
+	ListEarningRules(ctx context.Context, tenantID uuid.UUID) ([]RiderEarningRule, error)
+	ListEarningSurges(ctx context.Context, arg ListEarningSurgesParams) ([]RiderEarningSurge, error)
 	ListEarningsByOrder(ctx context.Context, arg ListEarningsByOrderParams) ([]RiderEarning, error)
 	ListEarningsByRider(ctx context.Context, arg ListEarningsByRiderParams) ([]RiderEarning, error)
+	ListExpiredReportExports(ctx context.Context, arg ListExpiredReportExportsParams) ([]ReportExport, error)
+	ListExpiringRiderDocuments(ctx context.Context, arg ListExpiringRiderDocumentsParams) ([]RiderDocument, error)
+	ListFeeTaxInvoices(ctx context.Context, arg ListFeeTaxInvoicesParams) ([]TaxInvoice, error)
 	ListHubAreas(ctx context.Context, hubID uuid.UUID) ([]HubCoverageArea, error)
 	ListHubsByTenant(ctx context.Context, tenantID uuid.UUID) ([]Hub, error)
 	ListInventoryAdjustments(ctx context.Context, arg ListInventoryAdjustmentsParams) ([]InventoryAdjustment, error)
 	ListInventoryByRestaurant(ctx context.Context, arg ListInventoryByRestaurantParams) ([]InventoryItem, error)
+	ListInventoryConsumption(ctx context.Context, arg ListInventoryConsumptionParams) ([]ListInventoryConsumptionRow, error)
+	ListInvoiceAdjustmentsByInvoice(ctx context.Context, arg ListInvoiceAdjustmentsByInvoiceParams) ([]InvoiceAdjustment, error)
+	ListInvoiceDisputeComments(ctx context.Context, arg ListInvoiceDisputeCommentsParams) ([]InvoiceDisputeComment, error)
+	ListInvoiceDisputes(ctx context.Context, arg ListInvoiceDisputesParams) ([]InvoiceDispute, error)
+	ListInvoiceDisputesByInvoice(ctx context.Context, arg ListInvoiceDisputesByInvoiceParams) ([]InvoiceDispute, error)
+	ListInvoiceLineItems(ctx context.Context, arg ListInvoiceLineItemsParams) ([]InvoiceLineItem, error)
+	ListInvoiceNotesByInvoice(ctx context.Context, arg ListInvoiceNotesByInvoiceParams) ([]InvoiceNote, error)
 	ListInvoicesByRestaurant(ctx context.Context, arg ListInvoicesByRestaurantParams) ([]Invoice, error)
 	ListInvoicesByTenant(ctx context.Context, arg ListInvoicesByTenantParams) ([]Invoice, error)
+	ListInvoicesDueForFinalize(ctx context.Context, arg ListInvoicesDueForFinalizeParams) ([]Invoice, error)
+	ListInvoicesReadyForPayout(ctx context.Context, tenantID uuid.UUID) ([]Invoice, error)
 	ListLedgerAccounts(ctx context.Context) ([]LedgerAccount, error)
 	ListLedgerEntriesByAccount(ctx context.Context, arg ListLedgerEntriesByAccountParams) ([]LedgerEntry, error)
 	ListLedgerEntriesByReference(ctx context.Context, arg ListLedgerEntriesByReferenceParams) ([]LedgerEntry, error)
+	ListLocationHistoryBetween(ctx context.Context, arg ListLocationHistoryBetweenParams) ([]RiderLocationHistory, error)
 	ListLocationHistoryByRider(ctx context.Context, arg ListLocationHistoryByRiderParams) ([]RiderLocationHistory, error)
 	ListLowStock(ctx context.Context, arg ListLowStockParams) ([]InventoryItem, error)
+	ListMissedRiderShifts(ctx context.Context, arg ListMissedRiderShiftsParams) ([]RiderShift, error)
 	ListModifierGroupsByProduct(ctx context.Context, productID uuid.UUID) ([]ProductModifierGroup, error)
 	ListModifierOptionsByGroup(ctx context.Context, modifierGroupID uuid.UUID) ([]ProductModifierOption, error)
+	ListMonthlyCustomerTrend(ctx context.Context, arg ListMonthlyCustomerTrendParams) ([]ListMonthlyCustomerTrendRow, error)
 	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOfferCountsForDay(ctx context.Context, arg ListOfferCountsForDayParams) ([]ListOfferCountsForDayRow, error)
 	ListOperatingHours(ctx context.Context, restaurantID uuid.UUID) ([]RestaurantOperatingHour, error)
 	ListOrderIssueMessages(ctx context.Context, arg ListOrderIssueMessagesParams) ([]OrderIssueMessage, error)
+	ListOrderIssueRestaurantIDs(ctx context.Context, arg ListOrderIssueRestaurantIDsParams) ([]uuid.UUID, error)
 	ListOrderIssuesByOrder(ctx context.Context, arg ListOrderIssuesByOrderParams) ([]OrderIssue, error)
 	ListOrderIssuesByTenant(ctx context.Context, arg ListOrderIssuesByTenantParams) ([]OrderIssue, error)
 	ListOrderItemsByOrderIDs(ctx context.Context, arg ListOrderItemsByOrderIDsParams) ([]OrderItem, error)
 	ListOrderItemsByRestaurantAndPeriod(ctx context.Context, arg ListOrderItemsByRestaurantAndPeriodParams) ([]OrderItem, error)
+	ListOrderRestaurantIDs(ctx context.Context, arg ListOrderRestaurantIDsParams) ([]uuid.UUID, error)
+	ListOrdersAwaitingTaxInvoices(ctx context.Context, arg ListOrdersAwaitingTaxInvoicesParams) ([]ListOrdersAwaitingTaxInvoicesRow, error)
 	ListOrdersByCustomer(ctx context.Context, arg ListOrdersByCustomerParams) ([]Order, error)
 	ListOrdersByRestaurant(ctx context.Context, arg ListOrdersByRestaurantParams) ([]Order, error)
 	ListOrdersByStatus(ctx context.Context, arg ListOrdersByStatusParams) ([]Order, error)
 	ListOrdersByTenant(ctx context.Context, arg ListOrdersByTenantParams) ([]Order, error)
 	ListPenaltiesByRider(ctx context.Context, arg ListPenaltiesByRiderParams) ([]RiderPenalty, error)
+	ListPenaltyAppeals(ctx context.Context, arg ListPenaltyAppealsParams) ([]ListPenaltyAppealsRow, error)
+	ListPenaltyCountsForDay(ctx context.Context, arg ListPenaltyCountsForDayParams) ([]ListPenaltyCountsForDayRow, error)
 	ListPendingAutoConfirmOrders(ctx context.Context, limit int32) ([]Order, error)
+	ListPendingInvoiceAdjustments(ctx context.Context, arg ListPendingInvoiceAdjustmentsParams) ([]InvoiceAdjustment, error)
 	ListPendingOrdersPastTimeout(ctx context.Context, arg ListPendingOrdersPastTimeoutParams) ([]Order, error)
 	ListPendingOutboxEvents(ctx context.Context, limit int32) ([]OutboxEvent, error)
 	ListPendingPaymentOrders(ctx context.Context, arg ListPendingPaymentOrdersParams) ([]Order, error)
 	ListPendingTransactions(ctx context.Context, arg ListPendingTransactionsParams) ([]PaymentTransaction, error)
+	ListPickupTimingsForDay(ctx context.Context, arg ListPickupTimingsForDayParams) ([]ListPickupTimingsForDayRow, error)
 	ListPickupsByOrder(ctx context.Context, arg ListPickupsByOrderParams) ([]OrderPickup, error)
 	ListProductsByRestaurant(ctx context.Context, arg ListProductsByRestaurantParams) ([]Product, error)
 	ListPromoCategoryRestrictions(ctx context.Context, promoID uuid.UUID) ([]uuid.UUID, error)
+	ListPromoProductRestrictions(ctx context.Context, promoID uuid.UUID) ([]uuid.UUID, error)
 	ListPromoRestaurantRestrictions(ctx context.Context, promoID uuid.UUID) ([]uuid.UUID, error)
+	ListPromoTiers(ctx context.Context, promoID uuid.UUID) ([]PromoTier, error)
+	ListPromoTimeWindows(ctx context.Context, promoID uuid.UUID) ([]PromoTimeWindow, error)
 	ListPromoUserEligibility(ctx context.Context, promoID uuid.UUID) ([]uuid.UUID, error)
 	ListPromos(ctx context.Context, arg ListPromosParams) ([]Promo, error)
+	ListPurchaseOrderItems(ctx context.Context, purchaseOrderID uuid.UUID) ([]PurchaseOrderItem, error)
+	ListPurchaseOrders(ctx context.Context, arg ListPurchaseOrdersParams) ([]PurchaseOrder, error)
 	ListRefundsByOrder(ctx context.Context, arg ListRefundsByOrderParams) ([]Refund, error)
+	ListReportDeliveries(ctx context.Context, arg ListReportDeliveriesParams) ([]ReportDelivery, error)
+	ListReportExports(ctx context.Context, arg ListReportExportsParams) ([]ReportExport, error)
+	ListReportSubscriptions(ctx context.Context, arg ListReportSubscriptionsParams) ([]ReportSubscription, error)
+	ListRestaurantStaffUserIDs(ctx context.Context, arg ListRestaurantStaffUserIDsParams) ([]uuid.UUID, error)
+	ListRestaurantTaxInvoices(ctx context.Context, arg ListRestaurantTaxInvoicesParams) ([]TaxInvoice, error)
 	ListRestaurantsByTenant(ctx context.Context, arg ListRestaurantsByTenantParams) ([]Restaurant, error)
+	ListRestaurantsToInvoice(ctx context.Context, arg ListRestaurantsToInvoiceParams) ([]uuid.UUID, error)
 	ListReviewsByRestaurant(ctx context.Context, arg ListReviewsByRestaurantParams) ([]Review, error)
+	ListRiderApplications(ctx context.Context, arg ListRiderApplicationsParams) ([]ListRiderApplicationsRow, error)
+	ListRiderCashBalances(ctx context.Context, arg ListRiderCashBalancesParams) ([]ListRiderCashBalancesRow, error)
+	ListRiderCashDeposits(ctx context.Context, arg ListRiderCashDepositsParams) ([]ListRiderCashDepositsRow, error)
+	ListRiderCashDepositsByRider(ctx context.Context, arg ListRiderCashDepositsByRiderParams) ([]RiderCashDeposit, error)
+	ListRiderDocumentRenewals(ctx context.Context, arg ListRiderDocumentRenewalsParams) ([]ListRiderDocumentRenewalsRow, error)
 	ListRiderLocationsByTenant(ctx context.Context, tenantID uuid.UUID) ([]RiderLocation, error)
+	ListRiderPayoutBatches(ctx context.Context, arg ListRiderPayoutBatchesParams) ([]RiderPayoutBatch, error)
+	ListRiderPayoutsByBatch(ctx context.Context, arg ListRiderPayoutsByBatchParams) ([]ListRiderPayoutsByBatchRow, error)
+	ListRiderPayoutsByRider(ctx context.Context, arg ListRiderPayoutsByRiderParams) ([]RiderPayout, error)
+	ListRiderPendingPickups(ctx context.Context, arg ListRiderPendingPickupsParams) ([]ListRiderPendingPickupsRow, error)
+	ListRiderRatingCountsForDay(ctx context.Context, arg ListRiderRatingCountsForDayParams) ([]ListRiderRatingCountsForDayRow, error)
+	ListRiderScorecards(ctx context.Context, arg ListRiderScorecardsParams) ([]RiderScorecard, error)
+	ListRiderShifts(ctx context.Context, arg ListRiderShiftsParams) ([]ListRiderShiftsRow, error)
+	ListRiderTiers(ctx context.Context, arg ListRiderTiersParams) ([]ListRiderTiersRow, error)
 	ListRidersByHub(ctx context.Context, arg ListRidersByHubParams) ([]Rider, error)
 	ListRidersByTenant(ctx context.Context, arg ListRidersByTenantParams) ([]Rider, error)
+	ListRidersWithUnsettledEarnings(ctx context.Context, arg ListRidersWithUnsettledEarningsParams) ([]uuid.UUID, error)
 	ListSectionsByTenant(ctx context.Context, tenantID uuid.UUID) ([]HomepageSection, error)
+	ListSettlementPickups(ctx context.Context, arg ListSettlementPickupsParams) ([]ListSettlementPickupsRow, error)
+	ListShiftCountsForDay(ctx context.Context, arg ListShiftCountsForDayParams) ([]ListShiftCountsForDayRow, error)
+	ListShiftSwaps(ctx context.Context, arg ListShiftSwapsParams) ([]ListShiftSwapsRow, error)
+	ListShiftSwapsForRider(ctx context.Context, arg ListShiftSwapsForRiderParams) ([]ListShiftSwapsForRiderRow, error)
+	ListShiftTemplates(ctx context.Context, arg ListShiftTemplatesParams) ([]RiderShiftTemplate, error)
+	ListSignupCohortActivity(ctx context.Context, arg ListSignupCohortActivityParams) ([]ListSignupCohortActivityRow, error)
+	ListSignupCohortSizes(ctx context.Context, arg ListSignupCohortSizesParams) ([]ListSignupCohortSizesRow, error)
+	ListStaffRestaurantIDs(ctx context.Context, arg ListStaffRestaurantIDsParams) ([]uuid.UUID, error)
 	ListStoriesByTenant(ctx context.Context, arg ListStoriesByTenantParams) ([]Story, error)
+	ListSuppliers(ctx context.Context, arg ListSuppliersParams) ([]Supplier, error)
+	ListTaxInvoiceFailuresForPeriod(ctx context.Context, arg ListTaxInvoiceFailuresForPeriodParams) ([]ListTaxInvoiceFailuresForPeriodRow, error)
+	ListTaxInvoicesByOrder(ctx context.Context, arg ListTaxInvoicesByOrderParams) ([]TaxInvoice, error)
+	ListTenantRiderHubs(ctx context.Context, tenantID uuid.UUID) ([]ListTenantRiderHubsRow, error)
+	ListTenantScorecardsSince(ctx context.Context, arg ListTenantScorecardsSinceParams) ([]RiderScorecard, error)
 	ListTenants(ctx context.Context, arg ListTenantsParams) ([]Tenant, error)
+	ListTerminalOrdersAfterWatermark(ctx context.Context, arg ListTerminalOrdersAfterWatermarkParams) ([]Order, error)
+	ListTerminalOrdersForBackfill(ctx context.Context, arg ListTerminalOrdersForBackfillParams) ([]Order, error)
 	ListTimelineByOrder(ctx context.Context, arg ListTimelineByOrderParams) ([]OrderTimelineEvent, error)
 	ListTimelineEvents(ctx context.Context, arg ListTimelineEventsParams) ([]OrderTimelineEvent, error)
 	ListTransactionsByOrder(ctx context.Context, arg ListTransactionsByOrderParams) ([]PaymentTransaction, error)
+	ListUnsettledInvoiceAdjustments(ctx context.Context, arg ListUnsettledInvoiceAdjustmentsParams) ([]InvoiceAdjustment, error)
+	ListUnsettledPenaltiesForRider(ctx context.Context, arg ListUnsettledPenaltiesForRiderParams) ([]RiderPenalty, error)
+	ListUpcomingRiderShifts(ctx context.Context, arg ListUpcomingRiderShiftsParams) ([]RiderShift, error)
+	ListUserRiderDocuments(ctx context.Context, arg ListUserRiderDocumentsParams) ([]RiderDocument, error)
+	ListValidRiderDocumentTypes(ctx context.Context, arg ListValidRiderDocumentTypesParams) ([]string, error)
+	ListVendorPayoutBatches(ctx context.Context, arg ListVendorPayoutBatchesParams) ([]VendorPayoutBatch, error)
+	ListVendorPayoutsByBatch(ctx context.Context, arg ListVendorPayoutsByBatchParams) ([]ListVendorPayoutsByBatchRow, error)
 	ListWalletTransactions(ctx context.Context, arg ListWalletTransactionsParams) ([]WalletTransaction, error)
+	MarkBatchPayoutsProcessing(ctx context.Context, arg MarkBatchPayoutsProcessingParams) error
 	MarkInvoicePaid(ctx context.Context, arg MarkInvoicePaidParams) (Invoice, error)
 	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
 	MarkOTPVerified(ctx context.Context, id uuid.UUID) error
 	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
 	MarkOutboxEventProcessed(ctx context.Context, id uuid.UUID) error
+	MarkPayoutEarningsPaid(ctx context.Context, payoutID pgtype.UUID) error
+	MarkReportExportEmailed(ctx context.Context, id uuid.UUID) error
+	MarkReportExportExpired(ctx context.Context, id uuid.UUID) error
+	MarkRiderDocumentReminded(ctx context.Context, arg MarkRiderDocumentRemindedParams) error
+	MarkRiderShiftAbsent(ctx context.Context, id uuid.UUID) (RiderShift, error)
+	MarkRiderShiftCheckedIn(ctx context.Context, arg MarkRiderShiftCheckedInParams) (RiderShift, error)
+	MarkStaleRiderLocations(ctx context.Context, updatedAt time.Time) ([]MarkStaleRiderLocationsRow, error)
+	MarkVendorPayoutsProcessing(ctx context.Context, arg MarkVendorPayoutsProcessingParams) error
+	NextInvoiceNoteSerial(ctx context.Context, arg NextInvoiceNoteSerialParams) (int32, error)
+	NextPurchaseOrderSerial(ctx context.Context, tenantID uuid.UUID) (int32, error)
+	NextTaxInvoiceSerial(ctx context.Context, arg NextTaxInvoiceSerialParams) (int32, error)
+	OpenDeliveryProof(ctx context.Context, arg OpenDeliveryProofParams) (DeliveryProof, error)
+	OrderRestaurantsRequirePod(ctx context.Context, arg OrderRestaurantsRequirePodParams) (bool, error)
 	// placeholder query to validate SQLC pipeline
 	Ping(ctx context.Context) (int32, error)
 	PublishReview(ctx context.Context, arg PublishReviewParams) (Review, error)
@@ -268,17 +506,51 @@ type Querier interface {
 	PurgeOldNotifications(ctx context.Context, before time.Time) error
 	PurgeOldOrderTimeline(ctx context.Context, before time.Time) error
 	PurgeOldSearchLogs(ctx context.Context, before time.Time) error
+	ReassignRiderShift(ctx context.Context, arg ReassignRiderShiftParams) (RiderShift, error)
+	ReceivePurchaseOrderItem(ctx context.Context, arg ReceivePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	ReceiveStock(ctx context.Context, arg ReceiveStockParams) (InventoryItem, error)
+	RecordTaxInvoiceFailure(ctx context.Context, arg RecordTaxInvoiceFailureParams) (TaxInvoiceFailure, error)
+	// Claims one use and the discount against the promo's limits in a single
+	// statement. The row lock it takes is held until the order transaction
+	// commits, which serialises concurrent checkouts of the same promo. No row
+	// means a limit or the budget was reached in the meantime.
+	RedeemPromo(ctx context.Context, arg RedeemPromoParams) (Promo, error)
+	RefreshCustomerOrderStats(ctx context.Context, customerID uuid.UUID) error
+	// Reverses an order's promo usages and gives the uses and discount back to
+	// each promo. Promos deactivated for spending their budget become active
+	// again.
+	ReleasePromoUsagesForOrder(ctx context.Context, arg ReleasePromoUsagesForOrderParams) error
 	ReleaseStock(ctx context.Context, arg ReleaseStockParams) (InventoryItem, error)
+	RemoveEarningPeakWindows(ctx context.Context, ruleID uuid.UUID) error
 	RemovePromoCategoryRestrictions(ctx context.Context, promoID uuid.UUID) error
+	RemovePromoProductRestrictions(ctx context.Context, promoID uuid.UUID) error
 	RemovePromoRestaurantRestrictions(ctx context.Context, promoID uuid.UUID) error
+	RemovePromoTiers(ctx context.Context, promoID uuid.UUID) error
+	RemovePromoTimeWindows(ctx context.Context, promoID uuid.UUID) error
 	RemovePromoUserEligibility(ctx context.Context, promoID uuid.UUID) error
+	RemovePromoUserEligibilityExcept(ctx context.Context, arg RemovePromoUserEligibilityExceptParams) (int64, error)
+	RequeueStaleReportExports(ctx context.Context, before time.Time) (int64, error)
 	ReserveStock(ctx context.Context, arg ReserveStockParams) (InventoryItem, error)
+	ResolveOrderIssue(ctx context.Context, arg ResolveOrderIssueParams) (OrderIssue, error)
+	ReviewApplicationDocuments(ctx context.Context, arg ReviewApplicationDocumentsParams) error
+	ReviewPenaltyAppeal(ctx context.Context, arg ReviewPenaltyAppealParams) (RiderPenalty, error)
+	ReviewRiderApplication(ctx context.Context, arg ReviewRiderApplicationParams) (RiderApplication, error)
+	ReviewRiderCashDeposit(ctx context.Context, arg ReviewRiderCashDepositParams) (RiderCashDeposit, error)
+	ReviewRiderDocument(ctx context.Context, arg ReviewRiderDocumentParams) (RiderDocument, error)
 	RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
 	RevokeRefreshToken(ctx context.Context, id uuid.UUID) error
 	SearchProducts(ctx context.Context, arg SearchProductsParams) ([]Product, error)
 	SearchRestaurants(ctx context.Context, arg SearchRestaurantsParams) ([]Restaurant, error)
+	SetRiderPayoutBreakdown(ctx context.Context, arg SetRiderPayoutBreakdownParams) (RiderPayout, error)
+	SetRiderShiftPenalty(ctx context.Context, arg SetRiderShiftPenaltyParams) error
+	SetSettlementLastPeriodEnd(ctx context.Context, arg SetSettlementLastPeriodEndParams) error
 	SoftDeleteOrder(ctx context.Context, arg SoftDeleteOrderParams) error
 	SoftDeleteUser(ctx context.Context, id uuid.UUID) error
+	SumLocationDistance(ctx context.Context, arg SumLocationDistanceParams) (pgtype.Numeric, error)
+	SumPendingRiderCashDeposits(ctx context.Context, arg SumPendingRiderCashDepositsParams) (pgtype.Numeric, error)
+	SummarizeCodCollections(ctx context.Context, arg SummarizeCodCollectionsParams) ([]SummarizeCodCollectionsRow, error)
+	SummarizeRiderCashDeposits(ctx context.Context, arg SummarizeRiderCashDepositsParams) ([]SummarizeRiderCashDepositsRow, error)
+	SupersedeRiderDocuments(ctx context.Context, arg SupersedeRiderDocumentsParams) error
 	TransitionOrderStatus(ctx context.Context, arg TransitionOrderStatusParams) (Order, error)
 	TransitionPickupStatus(ctx context.Context, arg TransitionPickupStatusParams) (OrderPickup, error)
 	UpdateAddress(ctx context.Context, arg UpdateAddressParams) (UserAddress, error)
@@ -287,9 +559,11 @@ type Querier interface {
 	UpdateBanner(ctx context.Context, arg UpdateBannerParams) (Banner, error)
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
 	UpdateCategorySortOrder(ctx context.Context, arg UpdateCategorySortOrderParams) error
+	UpdateEarningRule(ctx context.Context, arg UpdateEarningRuleParams) (RiderEarningRule, error)
 	UpdateHub(ctx context.Context, arg UpdateHubParams) (Hub, error)
 	UpdateHubArea(ctx context.Context, arg UpdateHubAreaParams) (HubCoverageArea, error)
 	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) error
+	UpdateInvoiceDisputeStatus(ctx context.Context, arg UpdateInvoiceDisputeStatusParams) (InvoiceDispute, error)
 	UpdateModifierGroup(ctx context.Context, arg UpdateModifierGroupParams) (ProductModifierGroup, error)
 	UpdateModifierOption(ctx context.Context, arg UpdateModifierOptionParams) (ProductModifierOption, error)
 	UpdateOrderIssueRefund(ctx context.Context, arg UpdateOrderIssueRefundParams) (OrderIssue, error)
@@ -301,8 +575,11 @@ type Querier interface {
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
 	UpdateProductAvailability(ctx context.Context, arg UpdateProductAvailabilityParams) (Product, error)
 	UpdateProductHasModifiers(ctx context.Context, arg UpdateProductHasModifiersParams) error
+	UpdateProductStockAvailability(ctx context.Context, arg UpdateProductStockAvailabilityParams) error
 	UpdatePromo(ctx context.Context, arg UpdatePromoParams) (Promo, error)
+	UpdatePurchaseOrderStatus(ctx context.Context, arg UpdatePurchaseOrderStatusParams) (PurchaseOrder, error)
 	UpdateRefundStatus(ctx context.Context, arg UpdateRefundStatusParams) (Refund, error)
+	UpdateReportSubscription(ctx context.Context, arg UpdateReportSubscriptionParams) (ReportSubscription, error)
 	UpdateRestaurant(ctx context.Context, arg UpdateRestaurantParams) (Restaurant, error)
 	UpdateRestaurantAvailability(ctx context.Context, arg UpdateRestaurantAvailabilityParams) (Restaurant, error)
 	UpdateRestaurantRating(ctx context.Context, arg UpdateRestaurantRatingParams) error
@@ -310,17 +587,33 @@ type Querier interface {
 	UpdateRider(ctx context.Context, arg UpdateRiderParams) (Rider, error)
 	UpdateRiderAvailability(ctx context.Context, arg UpdateRiderAvailabilityParams) (Rider, error)
 	UpdateRiderDutyStatus(ctx context.Context, arg UpdateRiderDutyStatusParams) (Rider, error)
+	UpdateRiderPayoutBatchTotals(ctx context.Context, arg UpdateRiderPayoutBatchTotalsParams) (RiderPayoutBatch, error)
+	UpdateRiderRating(ctx context.Context, arg UpdateRiderRatingParams) error
 	UpdateRiderStats(ctx context.Context, arg UpdateRiderStatsParams) error
+	UpdateRiderTier(ctx context.Context, arg UpdateRiderTierParams) error
 	UpdateSection(ctx context.Context, arg UpdateSectionParams) (HomepageSection, error)
+	UpdateShiftSwapStatus(ctx context.Context, arg UpdateShiftSwapStatusParams) (RiderShiftSwap, error)
+	UpdateShiftTemplate(ctx context.Context, arg UpdateShiftTemplateParams) (RiderShiftTemplate, error)
+	UpdateSupplier(ctx context.Context, arg UpdateSupplierParams) (Supplier, error)
 	UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error)
 	UpdateTenantStatus(ctx context.Context, arg UpdateTenantStatusParams) (Tenant, error)
 	UpdateTransactionStatus(ctx context.Context, arg UpdateTransactionStatusParams) (PaymentTransaction, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateVendorPayoutBatchTotals(ctx context.Context, arg UpdateVendorPayoutBatchTotalsParams) (VendorPayoutBatch, error)
 	UpsertDeliveryZoneConfig(ctx context.Context, arg UpsertDeliveryZoneConfigParams) (DeliveryZoneConfig, error)
+	UpsertJobWatermark(ctx context.Context, arg UpsertJobWatermarkParams) error
 	UpsertOperatingHour(ctx context.Context, arg UpsertOperatingHourParams) (RestaurantOperatingHour, error)
+	UpsertOrderAnalytics(ctx context.Context, arg UpsertOrderAnalyticsParams) (OrderAnalytic, error)
 	UpsertProductDiscount(ctx context.Context, arg UpsertProductDiscountParams) (ProductDiscount, error)
+	UpsertRestaurantPayoutAccount(ctx context.Context, arg UpsertRestaurantPayoutAccountParams) (RestaurantPayoutAccount, error)
+	UpsertRestaurantVatRegistration(ctx context.Context, arg UpsertRestaurantVatRegistrationParams) (RestaurantVatRegistration, error)
 	UpsertRiderLocation(ctx context.Context, arg UpsertRiderLocationParams) (RiderLocation, error)
+	UpsertRiderScorecard(ctx context.Context, arg UpsertRiderScorecardParams) (RiderScorecard, error)
+	UpsertSettlementSchedule(ctx context.Context, arg UpsertSettlementScheduleParams) (SettlementSchedule, error)
+	UpsertTaxSettings(ctx context.Context, arg UpsertTaxSettingsParams) (TaxSetting, error)
+	VerifyRider(ctx context.Context, arg VerifyRiderParams) (Rider, error)
+	WithdrawAssignmentOffers(ctx context.Context, orderID uuid.UUID) error
 }
 
 var _ Querier = (*Queries)(nil)
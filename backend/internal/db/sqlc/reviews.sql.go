@@ -162,6 +162,27 @@ func (q *Queries) GetReviewByOrderAndUser(ctx context.Context, arg GetReviewByOr
 	return i, err
 }
 
+const getRiderAvgRating = `-- name: GetRiderAvgRating :one
+SELECT
+    COALESCE(AVG(rv.rider_rating), 5)::NUMERIC(3,2) AS avg_rating,
+    COUNT(rv.rider_rating)::INT AS rating_count
+FROM reviews rv
+JOIN orders o ON o.id = rv.order_id
+WHERE o.rider_id = $1 AND rv.rider_rating IS NOT NULL
+`
+
+type GetRiderAvgRatingRow struct {
+	AvgRating   pgtype.Numeric `json:"avg_rating"`
+	RatingCount int32          `json:"rating_count"`
+}
+
+func (q *Queries) GetRiderAvgRating(ctx context.Context, riderID pgtype.UUID) (GetRiderAvgRatingRow, error) {
+	row := q.db.QueryRow(ctx, getRiderAvgRating, riderID)
+	var i GetRiderAvgRatingRow
+	err := row.Scan(&i.AvgRating, &i.RatingCount)
+	return i, err
+}
+
 const listReviewsByRestaurant = `-- name: ListReviewsByRestaurant :many
 SELECT id, tenant_id, order_id, user_id, restaurant_id, restaurant_rating, rider_rating, comment, restaurant_reply, restaurant_reply_at, images, is_published, created_at, updated_at FROM reviews
 WHERE restaurant_id = $1 AND tenant_id = $2 AND is_published = true
@@ -298,3 +319,19 @@ func (q *Queries) UpdateRestaurantReply(ctx context.Context, arg UpdateRestauran
 	)
 	return i, err
 }
+
+const updateRiderRating = `-- name: UpdateRiderRating :exec
+UPDATE riders SET rating_avg = $2, rating_count = $3
+WHERE id = $1
+`
+
+type UpdateRiderRatingParams struct {
+	ID          uuid.UUID      `json:"id"`
+	RatingAvg   pgtype.Numeric `json:"rating_avg"`
+	RatingCount int32          `json:"rating_count"`
+}
+
+func (q *Queries) UpdateRiderRating(ctx context.Context, arg UpdateRiderRatingParams) error {
+	_, err := q.db.Exec(ctx, updateRiderRating, arg.ID, arg.RatingAvg, arg.RatingCount)
+	return err
+}
@@ -7,6 +7,7 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -179,6 +180,60 @@ func (q *Queries) ListAttendanceByTenant(ctx context.Context, arg ListAttendance
 	return items, nil
 }
 
+const listAttendanceInRange = `-- name: ListAttendanceInRange :many
+SELECT id, rider_id, tenant_id, work_date, checked_in_at, checked_out_at, total_hours, total_distance_km, completed_orders, cancelled_orders, earnings, created_at, updated_at FROM rider_attendance
+WHERE rider_id = $1 AND tenant_id = $2
+  AND checked_in_at <= $3::timestamptz
+  AND (checked_out_at IS NULL OR checked_out_at >= $4::timestamptz)
+ORDER BY checked_in_at
+`
+
+type ListAttendanceInRangeParams struct {
+	RiderID  uuid.UUID `json:"rider_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	ToTime   time.Time `json:"to_time"`
+	FromTime time.Time `json:"from_time"`
+}
+
+func (q *Queries) ListAttendanceInRange(ctx context.Context, arg ListAttendanceInRangeParams) ([]RiderAttendance, error) {
+	rows, err := q.db.Query(ctx, listAttendanceInRange,
+		arg.RiderID,
+		arg.TenantID,
+		arg.ToTime,
+		arg.FromTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RiderAttendance{}
+	for rows.Next() {
+		var i RiderAttendance
+		if err := rows.Scan(
+			&i.ID,
+			&i.RiderID,
+			&i.TenantID,
+			&i.WorkDate,
+			&i.CheckedInAt,
+			&i.CheckedOutAt,
+			&i.TotalHours,
+			&i.TotalDistanceKm,
+			&i.CompletedOrders,
+			&i.CancelledOrders,
+			&i.Earnings,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateAttendanceCheckout = `-- name: UpdateAttendanceCheckout :one
 UPDATE rider_attendance SET
   checked_out_at = $2,
@@ -7,26 +7,37 @@ package sqlc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createRiderEarning = `-- name: CreateRiderEarning :one
-INSERT INTO rider_earnings (rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning, is_paid_out, payout_id, created_at
+INSERT INTO rider_earnings (
+    rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning,
+    rule_id, vehicle_type, distance_km, pickup_count, multi_pickup_bonus, surge_multiplier, surge_bonus, breakdown
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+RETURNING id, rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning, is_paid_out, payout_id, created_at, rule_id, vehicle_type, distance_km, pickup_count, multi_pickup_bonus, surge_multiplier, surge_bonus, breakdown
 `
 
 type CreateRiderEarningParams struct {
-	RiderID       uuid.UUID      `json:"rider_id"`
-	TenantID      uuid.UUID      `json:"tenant_id"`
-	OrderID       uuid.UUID      `json:"order_id"`
-	BaseEarning   pgtype.Numeric `json:"base_earning"`
-	DistanceBonus pgtype.Numeric `json:"distance_bonus"`
-	PeakBonus     pgtype.Numeric `json:"peak_bonus"`
-	TipAmount     pgtype.Numeric `json:"tip_amount"`
-	TotalEarning  pgtype.Numeric `json:"total_earning"`
+	RiderID          uuid.UUID       `json:"rider_id"`
+	TenantID         uuid.UUID       `json:"tenant_id"`
+	OrderID          uuid.UUID       `json:"order_id"`
+	BaseEarning      pgtype.Numeric  `json:"base_earning"`
+	DistanceBonus    pgtype.Numeric  `json:"distance_bonus"`
+	PeakBonus        pgtype.Numeric  `json:"peak_bonus"`
+	TipAmount        pgtype.Numeric  `json:"tip_amount"`
+	TotalEarning     pgtype.Numeric  `json:"total_earning"`
+	RuleID           pgtype.UUID     `json:"rule_id"`
+	VehicleType      NullVehicleType `json:"vehicle_type"`
+	DistanceKm       pgtype.Numeric  `json:"distance_km"`
+	PickupCount      int32           `json:"pickup_count"`
+	MultiPickupBonus pgtype.Numeric  `json:"multi_pickup_bonus"`
+	SurgeMultiplier  pgtype.Numeric  `json:"surge_multiplier"`
+	SurgeBonus       pgtype.Numeric  `json:"surge_bonus"`
+	Breakdown        json.RawMessage `json:"breakdown"`
 }
 
 func (q *Queries) CreateRiderEarning(ctx context.Context, arg CreateRiderEarningParams) (RiderEarning, error) {
@@ -39,6 +50,14 @@ func (q *Queries) CreateRiderEarning(ctx context.Context, arg CreateRiderEarning
 		arg.PeakBonus,
 		arg.TipAmount,
 		arg.TotalEarning,
+		arg.RuleID,
+		arg.VehicleType,
+		arg.DistanceKm,
+		arg.PickupCount,
+		arg.MultiPickupBonus,
+		arg.SurgeMultiplier,
+		arg.SurgeBonus,
+		arg.Breakdown,
 	)
 	var i RiderEarning
 	err := row.Scan(
@@ -54,6 +73,14 @@ func (q *Queries) CreateRiderEarning(ctx context.Context, arg CreateRiderEarning
 		&i.IsPaidOut,
 		&i.PayoutID,
 		&i.CreatedAt,
+		&i.RuleID,
+		&i.VehicleType,
+		&i.DistanceKm,
+		&i.PickupCount,
+		&i.MultiPickupBonus,
+		&i.SurgeMultiplier,
+		&i.SurgeBonus,
+		&i.Breakdown,
 	)
 	return i, err
 }
@@ -72,7 +99,7 @@ func (q *Queries) GetTotalEarningsByRider(ctx context.Context, riderID uuid.UUID
 }
 
 const listEarningsByOrder = `-- name: ListEarningsByOrder :many
-SELECT id, rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning, is_paid_out, payout_id, created_at FROM rider_earnings WHERE order_id = $1 AND tenant_id = $2
+SELECT id, rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning, is_paid_out, payout_id, created_at, rule_id, vehicle_type, distance_km, pickup_count, multi_pickup_bonus, surge_multiplier, surge_bonus, breakdown FROM rider_earnings WHERE order_id = $1 AND tenant_id = $2
 `
 
 type ListEarningsByOrderParams struct {
@@ -102,6 +129,14 @@ func (q *Queries) ListEarningsByOrder(ctx context.Context, arg ListEarningsByOrd
 			&i.IsPaidOut,
 			&i.PayoutID,
 			&i.CreatedAt,
+			&i.RuleID,
+			&i.VehicleType,
+			&i.DistanceKm,
+			&i.PickupCount,
+			&i.MultiPickupBonus,
+			&i.SurgeMultiplier,
+			&i.SurgeBonus,
+			&i.Breakdown,
 		); err != nil {
 			return nil, err
 		}
@@ -114,7 +149,7 @@ func (q *Queries) ListEarningsByOrder(ctx context.Context, arg ListEarningsByOrd
 }
 
 const listEarningsByRider = `-- name: ListEarningsByRider :many
-SELECT id, rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning, is_paid_out, payout_id, created_at FROM rider_earnings
+SELECT id, rider_id, tenant_id, order_id, base_earning, distance_bonus, peak_bonus, tip_amount, total_earning, is_paid_out, payout_id, created_at, rule_id, vehicle_type, distance_km, pickup_count, multi_pickup_bonus, surge_multiplier, surge_bonus, breakdown FROM rider_earnings
 WHERE rider_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -148,6 +183,14 @@ func (q *Queries) ListEarningsByRider(ctx context.Context, arg ListEarningsByRid
 			&i.IsPaidOut,
 			&i.PayoutID,
 			&i.CreatedAt,
+			&i.RuleID,
+			&i.VehicleType,
+			&i.DistanceKm,
+			&i.PickupCount,
+			&i.MultiPickupBonus,
+			&i.SurgeMultiplier,
+			&i.SurgeBonus,
+			&i.Breakdown,
 		); err != nil {
 			return nil, err
 		}
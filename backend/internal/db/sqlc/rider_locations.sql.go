@@ -14,8 +14,8 @@ import (
 )
 
 const appendLocationHistory = `-- name: AppendLocationHistory :one
-INSERT INTO rider_location_history (rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO rider_location_history (rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id, rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km, created_at
 `
 
@@ -27,6 +27,7 @@ type AppendLocationHistoryParams struct {
 	GeoLng             pgtype.Numeric `json:"geo_lng"`
 	EventType          RiderSubject   `json:"event_type"`
 	DistanceFromPrevKm pgtype.Numeric `json:"distance_from_prev_km"`
+	CreatedAt          time.Time      `json:"created_at"`
 }
 
 func (q *Queries) AppendLocationHistory(ctx context.Context, arg AppendLocationHistoryParams) (RiderLocationHistory, error) {
@@ -38,6 +39,7 @@ func (q *Queries) AppendLocationHistory(ctx context.Context, arg AppendLocationH
 		arg.GeoLng,
 		arg.EventType,
 		arg.DistanceFromPrevKm,
+		arg.CreatedAt,
 	)
 	var i RiderLocationHistory
 	err := row.Scan(
@@ -54,8 +56,26 @@ func (q *Queries) AppendLocationHistory(ctx context.Context, arg AppendLocationH
 	return i, err
 }
 
+const deleteLocationHistoryBefore = `-- name: DeleteLocationHistoryBefore :execrows
+DELETE FROM rider_location_history
+WHERE id IN (SELECT id FROM rider_location_history WHERE created_at < $1 LIMIT $2)
+`
+
+type DeleteLocationHistoryBeforeParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) DeleteLocationHistoryBefore(ctx context.Context, arg DeleteLocationHistoryBeforeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteLocationHistoryBefore, arg.CreatedAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getRiderLocation = `-- name: GetRiderLocation :one
-SELECT rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters, updated_at FROM rider_locations WHERE rider_id = $1 LIMIT 1
+SELECT rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters, updated_at, is_stale, stale_since FROM rider_locations WHERE rider_id = $1 LIMIT 1
 `
 
 func (q *Queries) GetRiderLocation(ctx context.Context, riderID uuid.UUID) (RiderLocation, error) {
@@ -70,10 +90,64 @@ func (q *Queries) GetRiderLocation(ctx context.Context, riderID uuid.UUID) (Ride
 		&i.SpeedKmh,
 		&i.AccuracyMeters,
 		&i.UpdatedAt,
+		&i.IsStale,
+		&i.StaleSince,
 	)
 	return i, err
 }
 
+const listLocationHistoryBetween = `-- name: ListLocationHistoryBetween :many
+SELECT id, rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km, created_at FROM rider_location_history
+WHERE rider_id = $1 AND tenant_id = $2
+  AND created_at >= $3 AND created_at <= $4
+ORDER BY created_at
+LIMIT $5
+`
+
+type ListLocationHistoryBetweenParams struct {
+	RiderID  uuid.UUID `json:"rider_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+	Limit    int32     `json:"limit"`
+}
+
+func (q *Queries) ListLocationHistoryBetween(ctx context.Context, arg ListLocationHistoryBetweenParams) ([]RiderLocationHistory, error) {
+	rows, err := q.db.Query(ctx, listLocationHistoryBetween,
+		arg.RiderID,
+		arg.TenantID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []RiderLocationHistory{}
+	for rows.Next() {
+		var i RiderLocationHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.RiderID,
+			&i.TenantID,
+			&i.OrderID,
+			&i.GeoLat,
+			&i.GeoLng,
+			&i.EventType,
+			&i.DistanceFromPrevKm,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listLocationHistoryByRider = `-- name: ListLocationHistoryByRider :many
 SELECT id, rider_id, tenant_id, order_id, geo_lat, geo_lng, event_type, distance_from_prev_km, created_at FROM rider_location_history
 WHERE rider_id = $1 AND created_at >= $3::timestamptz
@@ -118,7 +192,7 @@ func (q *Queries) ListLocationHistoryByRider(ctx context.Context, arg ListLocati
 }
 
 const listRiderLocationsByTenant = `-- name: ListRiderLocationsByTenant :many
-SELECT rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters, updated_at FROM rider_locations WHERE tenant_id = $1 ORDER BY updated_at DESC
+SELECT rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters, updated_at, is_stale, stale_since FROM rider_locations WHERE tenant_id = $1 ORDER BY updated_at DESC
 `
 
 func (q *Queries) ListRiderLocationsByTenant(ctx context.Context, tenantID uuid.UUID) ([]RiderLocation, error) {
@@ -139,6 +213,8 @@ func (q *Queries) ListRiderLocationsByTenant(ctx context.Context, tenantID uuid.
 			&i.SpeedKmh,
 			&i.AccuracyMeters,
 			&i.UpdatedAt,
+			&i.IsStale,
+			&i.StaleSince,
 		); err != nil {
 			return nil, err
 		}
@@ -150,6 +226,113 @@ func (q *Queries) ListRiderLocationsByTenant(ctx context.Context, tenantID uuid.
 	return items, nil
 }
 
+const listRiderPendingPickups = `-- name: ListRiderPendingPickups :many
+SELECT op.order_id, op.restaurant_id, r.geo_lat, r.geo_lng
+FROM order_pickups op
+JOIN orders o ON o.id = op.order_id
+JOIN restaurants r ON r.id = op.restaurant_id
+WHERE o.rider_id = $1 AND o.tenant_id = $2
+  AND o.status IN ('confirmed', 'preparing', 'ready')
+  AND o.deleted_at IS NULL
+  AND op.status NOT IN ('picked', 'rejected')
+`
+
+type ListRiderPendingPickupsParams struct {
+	RiderID  pgtype.UUID `json:"rider_id"`
+	TenantID uuid.UUID   `json:"tenant_id"`
+}
+
+type ListRiderPendingPickupsRow struct {
+	OrderID      uuid.UUID      `json:"order_id"`
+	RestaurantID uuid.UUID      `json:"restaurant_id"`
+	GeoLat       pgtype.Numeric `json:"geo_lat"`
+	GeoLng       pgtype.Numeric `json:"geo_lng"`
+}
+
+func (q *Queries) ListRiderPendingPickups(ctx context.Context, arg ListRiderPendingPickupsParams) ([]ListRiderPendingPickupsRow, error) {
+	rows, err := q.db.Query(ctx, listRiderPendingPickups, arg.RiderID, arg.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRiderPendingPickupsRow{}
+	for rows.Next() {
+		var i ListRiderPendingPickupsRow
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.RestaurantID,
+			&i.GeoLat,
+			&i.GeoLng,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markStaleRiderLocations = `-- name: MarkStaleRiderLocations :many
+UPDATE rider_locations SET is_stale = true, stale_since = NOW()
+WHERE is_stale = false AND updated_at < $1
+  AND rider_id IN (SELECT id FROM riders WHERE is_on_duty = true)
+RETURNING rider_id, tenant_id, updated_at
+`
+
+type MarkStaleRiderLocationsRow struct {
+	RiderID   uuid.UUID `json:"rider_id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) MarkStaleRiderLocations(ctx context.Context, updatedAt time.Time) ([]MarkStaleRiderLocationsRow, error) {
+	rows, err := q.db.Query(ctx, markStaleRiderLocations, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MarkStaleRiderLocationsRow{}
+	for rows.Next() {
+		var i MarkStaleRiderLocationsRow
+		if err := rows.Scan(&i.RiderID, &i.TenantID, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumLocationDistance = `-- name: SumLocationDistance :one
+SELECT COALESCE(SUM(distance_from_prev_km), 0)::numeric AS distance_km
+FROM rider_location_history
+WHERE rider_id = $1 AND tenant_id = $2
+  AND created_at > $3 AND created_at <= $4
+`
+
+type SumLocationDistanceParams struct {
+	RiderID  uuid.UUID `json:"rider_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+func (q *Queries) SumLocationDistance(ctx context.Context, arg SumLocationDistanceParams) (pgtype.Numeric, error) {
+	row := q.db.QueryRow(ctx, sumLocationDistance,
+		arg.RiderID,
+		arg.TenantID,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	var distance_km pgtype.Numeric
+	err := row.Scan(&distance_km)
+	return distance_km, err
+}
+
 const upsertRiderLocation = `-- name: UpsertRiderLocation :one
 INSERT INTO rider_locations (rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
@@ -159,8 +342,10 @@ ON CONFLICT (rider_id) DO UPDATE SET
   heading = EXCLUDED.heading,
   speed_kmh = EXCLUDED.speed_kmh,
   accuracy_meters = EXCLUDED.accuracy_meters,
+  is_stale = false,
+  stale_since = NULL,
   updated_at = NOW()
-RETURNING rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters, updated_at
+RETURNING rider_id, tenant_id, geo_lat, geo_lng, heading, speed_kmh, accuracy_meters, updated_at, is_stale, stale_since
 `
 
 type UpsertRiderLocationParams struct {
@@ -193,6 +378,8 @@ func (q *Queries) UpsertRiderLocation(ctx context.Context, arg UpsertRiderLocati
 		&i.SpeedKmh,
 		&i.AccuracyMeters,
 		&i.UpdatedAt,
+		&i.IsStale,
+		&i.StaleSince,
 	)
 	return i, err
 }
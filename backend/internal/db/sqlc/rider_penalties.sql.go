@@ -8,6 +8,7 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -16,20 +17,28 @@ import (
 const appealPenalty = `-- name: AppealPenalty :one
 UPDATE rider_penalties SET
   status = 'appealed',
-  appeal_note = $3,
+  appeal_note = $1,
+  appeal_evidence_urls = $2,
   appealed_at = NOW()
-WHERE id = $1 AND tenant_id = $2
-RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at
+WHERE id = $3 AND rider_id = $4
+  AND status = 'pending' AND payout_id IS NULL AND appealed_at IS NULL
+RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at
 `
 
 type AppealPenaltyParams struct {
-	ID         uuid.UUID      `json:"id"`
-	TenantID   uuid.UUID      `json:"tenant_id"`
-	AppealNote sql.NullString `json:"appeal_note"`
+	AppealNote         sql.NullString `json:"appeal_note"`
+	AppealEvidenceUrls []string       `json:"appeal_evidence_urls"`
+	ID                 uuid.UUID      `json:"id"`
+	RiderID            uuid.UUID      `json:"rider_id"`
 }
 
 func (q *Queries) AppealPenalty(ctx context.Context, arg AppealPenaltyParams) (RiderPenalty, error) {
-	row := q.db.QueryRow(ctx, appealPenalty, arg.ID, arg.TenantID, arg.AppealNote)
+	row := q.db.QueryRow(ctx, appealPenalty,
+		arg.AppealNote,
+		arg.AppealEvidenceUrls,
+		arg.ID,
+		arg.RiderID,
+	)
 	var i RiderPenalty
 	err := row.Scan(
 		&i.ID,
@@ -46,6 +55,63 @@ func (q *Queries) AppealPenalty(ctx context.Context, arg AppealPenaltyParams) (R
 		&i.ClearedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayoutID,
+		&i.AppealEvidenceUrls,
+		&i.AppealOutcome,
+		&i.AppealReviewNote,
+		&i.AppealReviewedBy,
+		&i.AppealReviewedAt,
+	)
+	return i, err
+}
+
+const createIssuePenalty = `-- name: CreateIssuePenalty :one
+INSERT INTO rider_penalties (rider_id, tenant_id, order_id, issue_id, reason, amount)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (issue_id) WHERE issue_id IS NOT NULL DO NOTHING
+RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at
+`
+
+type CreateIssuePenaltyParams struct {
+	RiderID  uuid.UUID      `json:"rider_id"`
+	TenantID uuid.UUID      `json:"tenant_id"`
+	OrderID  pgtype.UUID    `json:"order_id"`
+	IssueID  pgtype.UUID    `json:"issue_id"`
+	Reason   string         `json:"reason"`
+	Amount   pgtype.Numeric `json:"amount"`
+}
+
+func (q *Queries) CreateIssuePenalty(ctx context.Context, arg CreateIssuePenaltyParams) (RiderPenalty, error) {
+	row := q.db.QueryRow(ctx, createIssuePenalty,
+		arg.RiderID,
+		arg.TenantID,
+		arg.OrderID,
+		arg.IssueID,
+		arg.Reason,
+		arg.Amount,
+	)
+	var i RiderPenalty
+	err := row.Scan(
+		&i.ID,
+		&i.RiderID,
+		&i.TenantID,
+		&i.OrderID,
+		&i.IssueID,
+		&i.Reason,
+		&i.Amount,
+		&i.Status,
+		&i.AppealNote,
+		&i.AppealedAt,
+		&i.ClearedAt,
+		&i.ClearedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PayoutID,
+		&i.AppealEvidenceUrls,
+		&i.AppealOutcome,
+		&i.AppealReviewNote,
+		&i.AppealReviewedBy,
+		&i.AppealReviewedAt,
 	)
 	return i, err
 }
@@ -53,7 +119,7 @@ func (q *Queries) AppealPenalty(ctx context.Context, arg AppealPenaltyParams) (R
 const createRiderPenalty = `-- name: CreateRiderPenalty :one
 INSERT INTO rider_penalties (rider_id, tenant_id, order_id, issue_id, reason, amount)
 VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at
+RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at
 `
 
 type CreateRiderPenaltyParams struct {
@@ -90,12 +156,18 @@ func (q *Queries) CreateRiderPenalty(ctx context.Context, arg CreateRiderPenalty
 		&i.ClearedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayoutID,
+		&i.AppealEvidenceUrls,
+		&i.AppealOutcome,
+		&i.AppealReviewNote,
+		&i.AppealReviewedBy,
+		&i.AppealReviewedAt,
 	)
 	return i, err
 }
 
 const getPenaltyByID = `-- name: GetPenaltyByID :one
-SELECT id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at FROM rider_penalties WHERE id = $1 AND tenant_id = $2 LIMIT 1
+SELECT id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at FROM rider_penalties WHERE id = $1 AND tenant_id = $2 LIMIT 1
 `
 
 type GetPenaltyByIDParams struct {
@@ -121,12 +193,18 @@ func (q *Queries) GetPenaltyByID(ctx context.Context, arg GetPenaltyByIDParams)
 		&i.ClearedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayoutID,
+		&i.AppealEvidenceUrls,
+		&i.AppealOutcome,
+		&i.AppealReviewNote,
+		&i.AppealReviewedBy,
+		&i.AppealReviewedAt,
 	)
 	return i, err
 }
 
 const listPenaltiesByRider = `-- name: ListPenaltiesByRider :many
-SELECT id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at FROM rider_penalties
+SELECT id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at FROM rider_penalties
 WHERE rider_id = $1 AND tenant_id = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -168,6 +246,108 @@ func (q *Queries) ListPenaltiesByRider(ctx context.Context, arg ListPenaltiesByR
 			&i.ClearedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PayoutID,
+			&i.AppealEvidenceUrls,
+			&i.AppealOutcome,
+			&i.AppealReviewNote,
+			&i.AppealReviewedBy,
+			&i.AppealReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPenaltyAppeals = `-- name: ListPenaltyAppeals :many
+SELECT p.id, p.rider_id, p.tenant_id, p.order_id, p.issue_id, p.reason, p.amount, p.status, p.appeal_note, p.appealed_at, p.cleared_at, p.cleared_by, p.created_at, p.updated_at, p.payout_id, p.appeal_evidence_urls, p.appeal_outcome, p.appeal_review_note, p.appeal_reviewed_by, p.appeal_reviewed_at, u.name AS rider_name, u.phone AS rider_phone, r.hub_id
+FROM rider_penalties p
+JOIN riders r ON r.id = p.rider_id
+JOIN users u ON u.id = r.user_id
+WHERE p.tenant_id = $1 AND p.appealed_at IS NOT NULL
+  AND ($2::uuid IS NULL OR r.hub_id = $2)
+  AND ($3::penalty_status IS NULL OR p.status = $3)
+ORDER BY p.appealed_at
+LIMIT $4 OFFSET $5
+`
+
+type ListPenaltyAppealsParams struct {
+	TenantID uuid.UUID         `json:"tenant_id"`
+	HubID    pgtype.UUID       `json:"hub_id"`
+	Status   NullPenaltyStatus `json:"status"`
+	Limit    int32             `json:"limit"`
+	Offset   int32             `json:"offset"`
+}
+
+type ListPenaltyAppealsRow struct {
+	ID                 uuid.UUID          `json:"id"`
+	RiderID            uuid.UUID          `json:"rider_id"`
+	TenantID           uuid.UUID          `json:"tenant_id"`
+	OrderID            pgtype.UUID        `json:"order_id"`
+	IssueID            pgtype.UUID        `json:"issue_id"`
+	Reason             string             `json:"reason"`
+	Amount             pgtype.Numeric     `json:"amount"`
+	Status             PenaltyStatus      `json:"status"`
+	AppealNote         sql.NullString     `json:"appeal_note"`
+	AppealedAt         pgtype.Timestamptz `json:"appealed_at"`
+	ClearedAt          pgtype.Timestamptz `json:"cleared_at"`
+	ClearedBy          pgtype.UUID        `json:"cleared_by"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	PayoutID           pgtype.UUID        `json:"payout_id"`
+	AppealEvidenceUrls []string           `json:"appeal_evidence_urls"`
+	AppealOutcome      sql.NullString     `json:"appeal_outcome"`
+	AppealReviewNote   sql.NullString     `json:"appeal_review_note"`
+	AppealReviewedBy   pgtype.UUID        `json:"appeal_reviewed_by"`
+	AppealReviewedAt   pgtype.Timestamptz `json:"appeal_reviewed_at"`
+	RiderName          string             `json:"rider_name"`
+	RiderPhone         sql.NullString     `json:"rider_phone"`
+	HubID              pgtype.UUID        `json:"hub_id"`
+}
+
+func (q *Queries) ListPenaltyAppeals(ctx context.Context, arg ListPenaltyAppealsParams) ([]ListPenaltyAppealsRow, error) {
+	rows, err := q.db.Query(ctx, listPenaltyAppeals,
+		arg.TenantID,
+		arg.HubID,
+		arg.Status,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPenaltyAppealsRow{}
+	for rows.Next() {
+		var i ListPenaltyAppealsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RiderID,
+			&i.TenantID,
+			&i.OrderID,
+			&i.IssueID,
+			&i.Reason,
+			&i.Amount,
+			&i.Status,
+			&i.AppealNote,
+			&i.AppealedAt,
+			&i.ClearedAt,
+			&i.ClearedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PayoutID,
+			&i.AppealEvidenceUrls,
+			&i.AppealOutcome,
+			&i.AppealReviewNote,
+			&i.AppealReviewedBy,
+			&i.AppealReviewedAt,
+			&i.RiderName,
+			&i.RiderPhone,
+			&i.HubID,
 		); err != nil {
 			return nil, err
 		}
@@ -179,13 +359,70 @@ func (q *Queries) ListPenaltiesByRider(ctx context.Context, arg ListPenaltiesByR
 	return items, nil
 }
 
+const reviewPenaltyAppeal = `-- name: ReviewPenaltyAppeal :one
+UPDATE rider_penalties SET
+  status = $1,
+  appeal_outcome = $2,
+  appeal_review_note = $3,
+  appeal_reviewed_by = $4,
+  appeal_reviewed_at = NOW(),
+  cleared_at = CASE WHEN $1 = 'cleared'::penalty_status THEN NOW() ELSE cleared_at END,
+  cleared_by = CASE WHEN $1 = 'cleared'::penalty_status THEN $4 ELSE cleared_by END
+WHERE id = $5 AND tenant_id = $6 AND status = 'appealed'
+RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at
+`
+
+type ReviewPenaltyAppealParams struct {
+	Status           PenaltyStatus  `json:"status"`
+	AppealOutcome    sql.NullString `json:"appeal_outcome"`
+	AppealReviewNote sql.NullString `json:"appeal_review_note"`
+	ReviewedBy       pgtype.UUID    `json:"reviewed_by"`
+	ID               uuid.UUID      `json:"id"`
+	TenantID         uuid.UUID      `json:"tenant_id"`
+}
+
+func (q *Queries) ReviewPenaltyAppeal(ctx context.Context, arg ReviewPenaltyAppealParams) (RiderPenalty, error) {
+	row := q.db.QueryRow(ctx, reviewPenaltyAppeal,
+		arg.Status,
+		arg.AppealOutcome,
+		arg.AppealReviewNote,
+		arg.ReviewedBy,
+		arg.ID,
+		arg.TenantID,
+	)
+	var i RiderPenalty
+	err := row.Scan(
+		&i.ID,
+		&i.RiderID,
+		&i.TenantID,
+		&i.OrderID,
+		&i.IssueID,
+		&i.Reason,
+		&i.Amount,
+		&i.Status,
+		&i.AppealNote,
+		&i.AppealedAt,
+		&i.ClearedAt,
+		&i.ClearedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PayoutID,
+		&i.AppealEvidenceUrls,
+		&i.AppealOutcome,
+		&i.AppealReviewNote,
+		&i.AppealReviewedBy,
+		&i.AppealReviewedAt,
+	)
+	return i, err
+}
+
 const updatePenaltyStatus = `-- name: UpdatePenaltyStatus :one
 UPDATE rider_penalties SET
   status = $1,
   cleared_at = COALESCE($2, cleared_at),
   cleared_by = COALESCE($3, cleared_by)
 WHERE id = $4 AND tenant_id = $5
-RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at
+RETURNING id, rider_id, tenant_id, order_id, issue_id, reason, amount, status, appeal_note, appealed_at, cleared_at, cleared_by, created_at, updated_at, payout_id, appeal_evidence_urls, appeal_outcome, appeal_review_note, appeal_reviewed_by, appeal_reviewed_at
 `
 
 type UpdatePenaltyStatusParams struct {
@@ -220,6 +457,12 @@ func (q *Queries) UpdatePenaltyStatus(ctx context.Context, arg UpdatePenaltyStat
 		&i.ClearedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PayoutID,
+		&i.AppealEvidenceUrls,
+		&i.AppealOutcome,
+		&i.AppealReviewNote,
+		&i.AppealReviewedBy,
+		&i.AppealReviewedAt,
 	)
 	return i, err
 }
@@ -13,6 +13,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addRiderCashInHand = `-- name: AddRiderCashInHand :exec
+UPDATE riders SET cash_in_hand = cash_in_hand + $1
+WHERE id = $2 AND tenant_id = $3
+`
+
+type AddRiderCashInHandParams struct {
+	Amount   pgtype.Numeric `json:"amount"`
+	ID       uuid.UUID      `json:"id"`
+	TenantID uuid.UUID      `json:"tenant_id"`
+}
+
+func (q *Queries) AddRiderCashInHand(ctx context.Context, arg AddRiderCashInHandParams) error {
+	_, err := q.db.Exec(ctx, addRiderCashInHand, arg.Amount, arg.ID, arg.TenantID)
+	return err
+}
+
 const countRidersByTenant = `-- name: CountRidersByTenant :one
 SELECT COUNT(*) FROM riders WHERE tenant_id = $1
 `
@@ -27,7 +43,7 @@ func (q *Queries) CountRidersByTenant(ctx context.Context, tenantID uuid.UUID) (
 const createRider = `-- name: CreateRider :one
 INSERT INTO riders (tenant_id, user_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at
+RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by
 `
 
 type CreateRiderParams struct {
@@ -72,10 +88,49 @@ func (q *Queries) CreateRider(ctx context.Context, arg CreateRiderParams) (Rider
 		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
 	)
 	return i, err
 }
 
+const deductRiderCashInHand = `-- name: DeductRiderCashInHand :exec
+UPDATE riders SET cash_in_hand = cash_in_hand - $1
+WHERE id = $2 AND tenant_id = $3
+`
+
+type DeductRiderCashInHandParams struct {
+	Amount   pgtype.Numeric `json:"amount"`
+	ID       uuid.UUID      `json:"id"`
+	TenantID uuid.UUID      `json:"tenant_id"`
+}
+
+func (q *Queries) DeductRiderCashInHand(ctx context.Context, arg DeductRiderCashInHandParams) error {
+	_, err := q.db.Exec(ctx, deductRiderCashInHand, arg.Amount, arg.ID, arg.TenantID)
+	return err
+}
+
+const deductRiderPendingBalance = `-- name: DeductRiderPendingBalance :exec
+UPDATE riders SET pending_balance = pending_balance - $1
+WHERE id = $2 AND tenant_id = $3
+`
+
+type DeductRiderPendingBalanceParams struct {
+	Amount   pgtype.Numeric `json:"amount"`
+	ID       uuid.UUID      `json:"id"`
+	TenantID uuid.UUID      `json:"tenant_id"`
+}
+
+func (q *Queries) DeductRiderPendingBalance(ctx context.Context, arg DeductRiderPendingBalanceParams) error {
+	_, err := q.db.Exec(ctx, deductRiderPendingBalance, arg.Amount, arg.ID, arg.TenantID)
+	return err
+}
+
 const deleteRider = `-- name: DeleteRider :exec
 DELETE FROM riders WHERE id = $1 AND tenant_id = $2
 `
@@ -91,7 +146,7 @@ func (q *Queries) DeleteRider(ctx context.Context, arg DeleteRiderParams) error
 }
 
 const getRiderByID = `-- name: GetRiderByID :one
-SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at FROM riders WHERE id = $1 AND tenant_id = $2 LIMIT 1
+SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by FROM riders WHERE id = $1 AND tenant_id = $2 LIMIT 1
 `
 
 type GetRiderByIDParams struct {
@@ -121,12 +176,19 @@ func (q *Queries) GetRiderByID(ctx context.Context, arg GetRiderByIDParams) (Rid
 		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
 	)
 	return i, err
 }
 
 const getRiderByUserID = `-- name: GetRiderByUserID :one
-SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at FROM riders WHERE user_id = $1 AND tenant_id = $2 LIMIT 1
+SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by FROM riders WHERE user_id = $1 AND tenant_id = $2 LIMIT 1
 `
 
 type GetRiderByUserIDParams struct {
@@ -156,12 +218,62 @@ func (q *Queries) GetRiderByUserID(ctx context.Context, arg GetRiderByUserIDPara
 		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
+	)
+	return i, err
+}
+
+const getRiderForUpdate = `-- name: GetRiderForUpdate :one
+SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by FROM riders WHERE id = $1 AND tenant_id = $2 LIMIT 1 FOR UPDATE
+`
+
+type GetRiderForUpdateParams struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+func (q *Queries) GetRiderForUpdate(ctx context.Context, arg GetRiderForUpdateParams) (Rider, error) {
+	row := q.db.QueryRow(ctx, getRiderForUpdate, arg.ID, arg.TenantID)
+	var i Rider
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TenantID,
+		&i.HubID,
+		&i.VehicleType,
+		&i.VehicleRegistration,
+		&i.LicenseNumber,
+		&i.NidNumber,
+		&i.NidVerified,
+		&i.IsAvailable,
+		&i.IsOnDuty,
+		&i.TotalOrderCount,
+		&i.TotalEarnings,
+		&i.PendingBalance,
+		&i.RatingAvg,
+		&i.RatingCount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
 	)
 	return i, err
 }
 
 const listAvailableRidersByHub = `-- name: ListAvailableRidersByHub :many
-SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at FROM riders WHERE hub_id = $1 AND tenant_id = $2 AND is_available = true AND is_on_duty = true
+SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by FROM riders WHERE hub_id = $1 AND tenant_id = $2 AND is_available = true AND is_on_duty = true
+  AND NOT EXISTS (SELECT 1 FROM rider_locations rl WHERE rl.rider_id = riders.id AND rl.is_stale)
 `
 
 type ListAvailableRidersByHubParams struct {
@@ -197,6 +309,13 @@ func (q *Queries) ListAvailableRidersByHub(ctx context.Context, arg ListAvailabl
 			&i.RatingCount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CashInHand,
+			&i.Tier,
+			&i.PerformanceScore,
+			&i.TierUpdatedAt,
+			&i.VerificationStatus,
+			&i.VerifiedAt,
+			&i.VerifiedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -209,7 +328,7 @@ func (q *Queries) ListAvailableRidersByHub(ctx context.Context, arg ListAvailabl
 }
 
 const listRidersByHub = `-- name: ListRidersByHub :many
-SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at FROM riders WHERE hub_id = $1 AND tenant_id = $2 ORDER BY created_at DESC
+SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by FROM riders WHERE hub_id = $1 AND tenant_id = $2 ORDER BY created_at DESC
 `
 
 type ListRidersByHubParams struct {
@@ -245,6 +364,13 @@ func (q *Queries) ListRidersByHub(ctx context.Context, arg ListRidersByHubParams
 			&i.RatingCount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CashInHand,
+			&i.Tier,
+			&i.PerformanceScore,
+			&i.TierUpdatedAt,
+			&i.VerificationStatus,
+			&i.VerifiedAt,
+			&i.VerifiedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -257,7 +383,7 @@ func (q *Queries) ListRidersByHub(ctx context.Context, arg ListRidersByHubParams
 }
 
 const listRidersByTenant = `-- name: ListRidersByTenant :many
-SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at FROM riders WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
+SELECT id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by FROM riders WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3
 `
 
 type ListRidersByTenantParams struct {
@@ -294,6 +420,13 @@ func (q *Queries) ListRidersByTenant(ctx context.Context, arg ListRidersByTenant
 			&i.RatingCount,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CashInHand,
+			&i.Tier,
+			&i.PerformanceScore,
+			&i.TierUpdatedAt,
+			&i.VerificationStatus,
+			&i.VerifiedAt,
+			&i.VerifiedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -316,7 +449,7 @@ UPDATE riders SET
   rating_avg = COALESCE($7, rating_avg),
   rating_count = COALESCE($8, rating_count)
 WHERE id = $9 AND tenant_id = $10
-RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at
+RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by
 `
 
 type UpdateRiderParams struct {
@@ -365,6 +498,13 @@ func (q *Queries) UpdateRider(ctx context.Context, arg UpdateRiderParams) (Rider
 		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
 	)
 	return i, err
 }
@@ -372,7 +512,7 @@ func (q *Queries) UpdateRider(ctx context.Context, arg UpdateRiderParams) (Rider
 const updateRiderAvailability = `-- name: UpdateRiderAvailability :one
 UPDATE riders SET is_available = $3
 WHERE id = $1 AND tenant_id = $2
-RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at
+RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by
 `
 
 type UpdateRiderAvailabilityParams struct {
@@ -403,6 +543,13 @@ func (q *Queries) UpdateRiderAvailability(ctx context.Context, arg UpdateRiderAv
 		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
 	)
 	return i, err
 }
@@ -410,7 +557,7 @@ func (q *Queries) UpdateRiderAvailability(ctx context.Context, arg UpdateRiderAv
 const updateRiderDutyStatus = `-- name: UpdateRiderDutyStatus :one
 UPDATE riders SET is_on_duty = $3
 WHERE id = $1 AND tenant_id = $2
-RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at
+RETURNING id, user_id, tenant_id, hub_id, vehicle_type, vehicle_registration, license_number, nid_number, nid_verified, is_available, is_on_duty, total_order_count, total_earnings, pending_balance, rating_avg, rating_count, created_at, updated_at, cash_in_hand, tier, performance_score, tier_updated_at, verification_status, verified_at, verified_by
 `
 
 type UpdateRiderDutyStatusParams struct {
@@ -441,6 +588,13 @@ func (q *Queries) UpdateRiderDutyStatus(ctx context.Context, arg UpdateRiderDuty
 		&i.RatingCount,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CashInHand,
+		&i.Tier,
+		&i.PerformanceScore,
+		&i.TierUpdatedAt,
+		&i.VerificationStatus,
+		&i.VerifiedAt,
+		&i.VerifiedBy,
 	)
 	return i, err
 }
@@ -186,6 +186,30 @@ func (q *Queries) GetTenantBySlug(ctx context.Context, slug string) (Tenant, err
 	return i, err
 }
 
+const listActiveTenantIDs = `-- name: ListActiveTenantIDs :many
+SELECT id FROM tenants WHERE status = 'active' ORDER BY created_at
+`
+
+func (q *Queries) ListActiveTenantIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listActiveTenantIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTenants = `-- name: ListTenants :many
 SELECT id, slug, name, status, plan, subscription_plan_id, commission_rate, settings, custom_domain, logo_url, favicon_url, primary_color, secondary_color, contact_email, contact_phone, address, timezone, currency, locale, created_at, updated_at FROM tenants ORDER BY created_at DESC LIMIT $1 OFFSET $2
 `
@@ -0,0 +1,333 @@
+package access
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/auth"
+	"github.com/munchies/platform/backend/internal/modules/tenant"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/respond"
+)
+
+// formMaxMemory matches the limit the multipart upload handlers parse with.
+const formMaxMemory = 10 << 20
+
+// Locator finds the restaurants that own the resource a request acts on.
+// A missing or malformed identifier is an error: scoped users are denied
+// rather than let through when the restaurant cannot be determined.
+type Locator func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error)
+
+// Policy resolves restaurant scopes and guards partner routes with them.
+type Policy struct {
+	q *sqlc.Queries
+}
+
+// NewPolicy creates a new restaurant access policy.
+func NewPolicy(q *sqlc.Queries) *Policy {
+	return &Policy{q: q}
+}
+
+// Resolve returns the restaurant scope of a user within a tenant.
+func (p *Policy) Resolve(ctx context.Context, user *sqlc.User, tenantID uuid.UUID) (*Scope, error) {
+	if IsTenantWide(user.Role) {
+		return &Scope{TenantWide: true}, nil
+	}
+	ids, err := p.q.ListStaffRestaurantIDs(ctx, sqlc.ListStaffRestaurantIDsParams{UserID: user.ID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list staff restaurants", err)
+	}
+	if len(ids) == 0 {
+		return nil, apperror.Forbidden("you are not assigned to any restaurant")
+	}
+	return &Scope{RestaurantIDs: ids}, nil
+}
+
+// LoadScope is HTTP middleware that resolves the user's restaurant scope and
+// attaches it to context. It must run after tenant resolution and Authenticate.
+func (p *Policy) LoadScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := auth.UserFromContext(r.Context())
+		if user == nil {
+			respond.Error(w, apperror.Unauthorized("authentication required"))
+			return
+		}
+		t := tenant.FromContext(r.Context())
+		if t == nil {
+			respond.Error(w, apperror.NotFound("tenant"))
+			return
+		}
+
+		scope, err := p.Resolve(r.Context(), user, t.ID)
+		if err != nil {
+			respond.Error(w, toAppError(err))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithScope(r.Context(), scope)))
+	})
+}
+
+// RequireRestaurant returns middleware that lets a request through only when
+// every locator finds a restaurant in the user's scope. Tenant-wide users
+// skip the lookups; handlers still validate the restaurant belongs to the tenant.
+func (p *Policy) RequireRestaurant(locators ...Locator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := FromContext(r.Context())
+			if scope == nil {
+				respond.Error(w, apperror.Forbidden("restaurant access not resolved"))
+				return
+			}
+			if scope.TenantWide {
+				next.ServeHTTP(w, r)
+				return
+			}
+			t := tenant.FromContext(r.Context())
+			if t == nil {
+				respond.Error(w, apperror.NotFound("tenant"))
+				return
+			}
+
+			for _, locate := range locators {
+				ids, err := locate(r, t.ID)
+				if err != nil {
+					respond.Error(w, toAppError(err))
+					return
+				}
+				if !scope.AllowsAny(ids) {
+					respond.Error(w, apperror.Forbidden("you are not assigned to this restaurant"))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ---------- Request locators ----------
+
+// URLParam reads the restaurant ID from a route parameter.
+func URLParam(name string) Locator {
+	return func(r *http.Request, _ uuid.UUID) ([]uuid.UUID, error) {
+		return parseID("restaurant id", chi.URLParam(r, name))
+	}
+}
+
+// Query reads the restaurant ID from a query parameter.
+func Query(name string) Locator {
+	return func(r *http.Request, _ uuid.UUID) ([]uuid.UUID, error) {
+		return parseID(name, r.URL.Query().Get(name))
+	}
+}
+
+// Form reads the restaurant ID from a multipart form field.
+func Form(name string) Locator {
+	return func(r *http.Request, _ uuid.UUID) ([]uuid.UUID, error) {
+		if err := r.ParseMultipartForm(formMaxMemory); err != nil {
+			return nil, apperror.BadRequest("invalid multipart form")
+		}
+		return parseID(name, r.FormValue(name))
+	}
+}
+
+// Body reads the restaurant ID from a top-level field of a JSON body. The
+// body is restored so the handler can decode it again.
+func Body(field string) Locator {
+	return func(r *http.Request, _ uuid.UUID) ([]uuid.UUID, error) {
+		raw, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, apperror.BadRequest("invalid request body")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, apperror.BadRequest("invalid request body")
+		}
+		var value string
+		if v, ok := fields[field]; ok {
+			if err := json.Unmarshal(v, &value); err != nil {
+				return nil, apperror.BadRequest("invalid " + field)
+			}
+		}
+		return parseID(field, value)
+	}
+}
+
+// ---------- Resource locators ----------
+
+// Order finds the restaurants an order (route parameter) is picked up from.
+func (p *Policy) Order(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("order id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		ids, err := p.q.ListOrderRestaurantIDs(r.Context(), sqlc.ListOrderRestaurantIDsParams{OrderID: id, TenantID: tenantID})
+		if err != nil {
+			return nil, apperror.Internal("list order restaurants", err)
+		}
+		if len(ids) == 0 {
+			return nil, apperror.NotFound("order")
+		}
+		return ids, nil
+	}
+}
+
+// Issue finds the restaurants of the order an issue (route parameter) was raised on.
+func (p *Policy) Issue(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("issue id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		ids, err := p.q.ListOrderIssueRestaurantIDs(r.Context(), sqlc.ListOrderIssueRestaurantIDsParams{IssueID: id, TenantID: tenantID})
+		if err != nil {
+			return nil, apperror.Internal("list issue restaurants", err)
+		}
+		if len(ids) == 0 {
+			return nil, apperror.NotFound("issue")
+		}
+		return ids, nil
+	}
+}
+
+// Product finds the restaurant a product (route parameter) belongs to.
+func (p *Policy) Product(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("product id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetProductRestaurantID(r.Context(), sqlc.GetProductRestaurantIDParams{ProductID: id, TenantID: tenantID})
+		return owner("product", restaurantID, err)
+	}
+}
+
+// Category finds the restaurant a category (route parameter) belongs to.
+// Tenant-wide categories have none, so only tenant-wide users may change them.
+func (p *Policy) Category(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("category id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetCategoryRestaurantID(r.Context(), sqlc.GetCategoryRestaurantIDParams{CategoryID: id, TenantID: tenantID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("category")
+		}
+		if err != nil {
+			return nil, apperror.Internal("get category restaurant", err)
+		}
+		if !restaurantID.Valid {
+			return nil, nil
+		}
+		return []uuid.UUID{restaurantID.Bytes}, nil
+	}
+}
+
+// Review finds the restaurant a review (route parameter) was left for.
+func (p *Policy) Review(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("review id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetReviewRestaurantID(r.Context(), sqlc.GetReviewRestaurantIDParams{ReviewID: id, TenantID: tenantID})
+		return owner("review", restaurantID, err)
+	}
+}
+
+// PurchaseOrder finds the restaurant a purchase order (route parameter) was raised for.
+func (p *Policy) PurchaseOrder(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("purchase order id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetPurchaseOrderRestaurantID(r.Context(), sqlc.GetPurchaseOrderRestaurantIDParams{PurchaseOrderID: id, TenantID: tenantID})
+		return owner("purchase order", restaurantID, err)
+	}
+}
+
+// Invoice finds the restaurant an invoice (route parameter) was issued to.
+func (p *Policy) Invoice(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("invoice id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetInvoiceRestaurantID(r.Context(), sqlc.GetInvoiceRestaurantIDParams{InvoiceID: id, TenantID: tenantID})
+		return owner("invoice", restaurantID, err)
+	}
+}
+
+// InvoiceDispute finds the restaurant that raised an invoice dispute (route parameter).
+func (p *Policy) InvoiceDispute(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("dispute id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetInvoiceDisputeRestaurantID(r.Context(), sqlc.GetInvoiceDisputeRestaurantIDParams{DisputeID: id, TenantID: tenantID})
+		return owner("dispute", restaurantID, err)
+	}
+}
+
+// InvoiceNote finds the restaurant a credit or debit note (route parameter) was issued to.
+func (p *Policy) InvoiceNote(param string) Locator {
+	return func(r *http.Request, tenantID uuid.UUID) ([]uuid.UUID, error) {
+		id, err := parseOne("note id", chi.URLParam(r, param))
+		if err != nil {
+			return nil, err
+		}
+		restaurantID, err := p.q.GetInvoiceNoteRestaurantID(r.Context(), sqlc.GetInvoiceNoteRestaurantIDParams{NoteID: id, TenantID: tenantID})
+		return owner("note", restaurantID, err)
+	}
+}
+
+func owner(resource string, restaurantID uuid.UUID, err error) ([]uuid.UUID, error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound(resource)
+	}
+	if err != nil {
+		return nil, apperror.Internal("get "+resource+" restaurant", err)
+	}
+	return []uuid.UUID{restaurantID}, nil
+}
+
+func parseID(name, value string) ([]uuid.UUID, error) {
+	id, err := parseOne(name, value)
+	if err != nil {
+		return nil, err
+	}
+	return []uuid.UUID{id}, nil
+}
+
+func parseOne(name, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, apperror.BadRequest(name + " is required")
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperror.BadRequest("invalid " + name)
+	}
+	return id, nil
+}
+
+func toAppError(err error) *apperror.AppError {
+	if e, ok := err.(*apperror.AppError); ok {
+		return e
+	}
+	return apperror.Internal("unexpected error", err)
+}
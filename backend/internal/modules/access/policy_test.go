@@ -0,0 +1,134 @@
+package access
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/tenant"
+)
+
+func TestScopeRestrict(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	scoped := &Scope{RestaurantIDs: []uuid.UUID{a, b}}
+	wide := &Scope{TenantWide: true}
+
+	if got, err := scoped.Restrict(nil); err != nil || len(got) != 2 {
+		t.Errorf("scoped default = %v, %v; want both assigned restaurants", got, err)
+	}
+	if got, err := scoped.Restrict([]uuid.UUID{b}); err != nil || len(got) != 1 || got[0] != b {
+		t.Errorf("scoped narrowed = %v, %v; want [b]", got, err)
+	}
+	if _, err := scoped.Restrict([]uuid.UUID{a, c}); err == nil {
+		t.Error("scoped user should not reach an unassigned restaurant")
+	}
+	if got, err := wide.Restrict(nil); err != nil || got != nil {
+		t.Errorf("tenant-wide default = %v, %v; want nil (whole tenant)", got, err)
+	}
+	if got, err := wide.Restrict([]uuid.UUID{c}); err != nil || len(got) != 1 {
+		t.Errorf("tenant-wide narrowed = %v, %v; want [c]", got, err)
+	}
+}
+
+func TestScopeAllowsAny(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	scoped := &Scope{RestaurantIDs: []uuid.UUID{a}}
+	if !scoped.AllowsAny([]uuid.UUID{b, a}) {
+		t.Error("an order shared with an assigned restaurant should be allowed")
+	}
+	if scoped.AllowsAny([]uuid.UUID{b}) || scoped.AllowsAny(nil) {
+		t.Error("orders of other restaurants should be denied")
+	}
+	if !(&Scope{TenantWide: true}).AllowsAny([]uuid.UUID{b}) {
+		t.Error("tenant-wide scope should allow every restaurant")
+	}
+}
+
+func TestIsTenantWide(t *testing.T) {
+	cases := map[sqlc.UserRole]bool{
+		sqlc.UserRoleTenantOwner:       true,
+		sqlc.UserRoleTenantAdmin:       true,
+		sqlc.UserRoleRestaurantManager: false,
+		sqlc.UserRoleRestaurantStaff:   false,
+	}
+	for role, want := range cases {
+		if got := IsTenantWide(role); got != want {
+			t.Errorf("IsTenantWide(%s) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestBodyLocatorRestoresBody(t *testing.T) {
+	id := uuid.New()
+	body := `{"restaurant_id":"` + id.String() + `","reason":"busy"}`
+	r := httptest.NewRequest(http.MethodPatch, "/orders/x/reject", strings.NewReader(body))
+
+	ids, err := Body("restaurant_id")(r, uuid.Nil)
+	if err != nil || len(ids) != 1 || ids[0] != id {
+		t.Fatalf("Body locator = %v, %v; want [%s]", ids, err, id)
+	}
+	rest, _ := io.ReadAll(r.Body)
+	if string(rest) != body {
+		t.Errorf("body after locator = %q, want it unchanged", rest)
+	}
+}
+
+func TestLocatorsRejectMissingIDs(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/inventory/adjust", strings.NewReader(`{"quantity":3}`))
+	if _, err := Body("restaurant_id")(r, uuid.Nil); err == nil {
+		t.Error("missing body field should be an error")
+	}
+	r = httptest.NewRequest(http.MethodGet, "/inventory?restaurant_id=nope", nil)
+	if _, err := Query("restaurant_id")(r, uuid.Nil); err == nil {
+		t.Error("malformed query param should be an error")
+	}
+}
+
+func TestRequireRestaurant(t *testing.T) {
+	assigned, other := uuid.New(), uuid.New()
+	guard := NewPolicy(nil).RequireRestaurant(Query("restaurant_id"))
+	handler := guard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(scope *Scope, restaurantID string) int {
+		r := httptest.NewRequest(http.MethodGet, "/inventory?restaurant_id="+restaurantID, nil)
+		ctx := tenant.WithContext(r.Context(), &sqlc.Tenant{ID: uuid.New()})
+		if scope != nil {
+			ctx = WithScope(ctx, scope)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r.WithContext(ctx))
+		return w.Code
+	}
+
+	scoped := &Scope{RestaurantIDs: []uuid.UUID{assigned}}
+	cases := []struct {
+		name  string
+		scope *Scope
+		id    string
+		want  int
+	}{
+		{"assigned restaurant", scoped, assigned.String(), http.StatusNoContent},
+		{"other restaurant", scoped, other.String(), http.StatusForbidden},
+		{"missing restaurant", scoped, "", http.StatusBadRequest},
+		{"tenant-wide", &Scope{TenantWide: true}, other.String(), http.StatusNoContent},
+		{"no scope loaded", nil, assigned.String(), http.StatusForbidden},
+	}
+	for _, c := range cases {
+		if got := serve(c.scope, c.id); got != c.want {
+			t.Errorf("%s: status %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestFromContextEmpty(t *testing.T) {
+	if FromContext(context.Background()) != nil {
+		t.Error("expected no scope on a bare context")
+	}
+}
@@ -0,0 +1,76 @@
+package access
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/contextkey"
+)
+
+// Scope is the set of restaurants a partner user may act on. Tenant owners
+// and admins are tenant-wide; restaurant managers and staff are limited to
+// the restaurants in restaurant_staff_assignments.
+type Scope struct {
+	TenantWide    bool        `json:"tenant_wide"`
+	RestaurantIDs []uuid.UUID `json:"restaurant_ids"`
+}
+
+// IsTenantWide reports whether the role sees every restaurant of the tenant.
+func IsTenantWide(role sqlc.UserRole) bool {
+	return role == sqlc.UserRoleTenantOwner || role == sqlc.UserRoleTenantAdmin
+}
+
+// Allows reports whether the scope covers the restaurant.
+func (s *Scope) Allows(id uuid.UUID) bool {
+	return s.TenantWide || slices.Contains(s.RestaurantIDs, id)
+}
+
+// AllowsAny reports whether the scope covers at least one of the restaurants.
+// An order picked up from several restaurants is visible to each of them.
+func (s *Scope) AllowsAny(ids []uuid.UUID) bool {
+	for _, id := range ids {
+		if s.Allows(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the value for a restaurant_ids query filter: nil for
+// tenant-wide users (no filter), the assigned restaurants otherwise.
+func (s *Scope) Filter() []uuid.UUID {
+	if s.TenantWide {
+		return nil
+	}
+	return s.RestaurantIDs
+}
+
+// Restrict narrows a requested set of restaurants to the scope. An empty
+// request means every restaurant in scope (nil for tenant-wide users). It
+// fails when any requested restaurant is outside the scope.
+func (s *Scope) Restrict(requested []uuid.UUID) ([]uuid.UUID, error) {
+	for _, id := range requested {
+		if !s.Allows(id) {
+			return nil, apperror.Forbidden("you are not assigned to this restaurant")
+		}
+	}
+	if len(requested) == 0 {
+		return s.Filter(), nil
+	}
+	return requested, nil
+}
+
+// FromContext returns the restaurant scope resolved for the request, or nil
+// when the route is not behind Policy.LoadScope.
+func FromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(contextkey.ScopeKey).(*Scope)
+	return s
+}
+
+// WithScope returns a new context with the scope attached.
+func WithScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, contextkey.ScopeKey, s)
+}
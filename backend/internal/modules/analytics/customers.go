@@ -0,0 +1,470 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultChurnDays is how long a customer may go without a delivered order
+// before counting as churned.
+const DefaultChurnDays = 60
+
+// maxCohortMonths bounds the retention matrix.
+const maxCohortMonths = 24
+
+// RFM segments, from best to worst.
+const (
+	SegmentChampions         = "champions"
+	SegmentLoyal             = "loyal"
+	SegmentNew               = "new"
+	SegmentPotentialLoyalist = "potential_loyalist"
+	SegmentCantLose          = "cant_lose"
+	SegmentAtRisk            = "at_risk"
+	SegmentHibernating       = "hibernating"
+	SegmentLost              = "lost"
+)
+
+// Segments lists every RFM segment in display order.
+var Segments = []string{
+	SegmentChampions, SegmentLoyal, SegmentNew, SegmentPotentialLoyalist,
+	SegmentCantLose, SegmentAtRisk, SegmentHibernating, SegmentLost,
+}
+
+// IsSegment reports whether s is a known RFM segment.
+func IsSegment(s string) bool {
+	for _, seg := range Segments {
+		if seg == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------- Summary ----------
+
+// CustomerSummary holds customer KPIs for a date range plus lifetime figures.
+type CustomerSummary struct {
+	ActiveCustomers    int32                              `json:"active_customers"`
+	NewCustomers       int32                              `json:"new_customers"`
+	ReturningCustomers int32                              `json:"returning_customers"`
+	OrderCount         int32                              `json:"order_count"`
+	Revenue            decimal.Decimal                    `json:"revenue"`
+	AvgOrderValue      decimal.Decimal                    `json:"avg_order_value"`
+	TotalCustomers     int32                              `json:"total_customers"`
+	OrderingCustomers  int32                              `json:"ordering_customers"`
+	RepeatCustomers    int32                              `json:"repeat_customers"`
+	RepeatRate         float64                            `json:"repeat_rate"`
+	ChurnDays          int                                `json:"churn_days"`
+	ChurnedCustomers   int32                              `json:"churned_customers"`
+	ChurnRate          float64                            `json:"churn_rate"`
+	AvgLifetimeValue   decimal.Decimal                    `json:"avg_lifetime_value"`
+	Trend              []sqlc.ListMonthlyCustomerTrendRow `json:"monthly_trend"`
+}
+
+// GetCustomerSummary returns active, new and returning customers and AOV for
+// the range, the monthly trend across it, and repeat and churn rates over the
+// whole customer base. Only delivered orders count.
+func (s *Service) GetCustomerSummary(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time, churnDays int) (*CustomerSummary, error) {
+	period, err := s.q.GetCustomerActivitySummary(ctx, sqlc.GetCustomerActivitySummaryParams{
+		TenantID:  tenantID,
+		StartDate: toPgDate(startDate),
+		EndDate:   toPgDate(endDate),
+	})
+	if err != nil {
+		return nil, apperror.Internal("get customer activity", err)
+	}
+
+	base, err := s.q.GetCustomerBaseSummary(ctx, sqlc.GetCustomerBaseSummaryParams{
+		TenantID:      pgtype.UUID{Bytes: tenantID, Valid: true},
+		ChurnedBefore: time.Now().AddDate(0, 0, -churnDays),
+	})
+	if err != nil {
+		return nil, apperror.Internal("get customer base", err)
+	}
+
+	trend, err := s.q.ListMonthlyCustomerTrend(ctx, sqlc.ListMonthlyCustomerTrendParams{
+		TenantID:  tenantID,
+		StartDate: toPgDate(startDate),
+		EndDate:   toPgDate(endDate),
+	})
+	if err != nil {
+		return nil, apperror.Internal("list monthly customer trend", err)
+	}
+
+	return &CustomerSummary{
+		ActiveCustomers:    period.ActiveCustomers,
+		NewCustomers:       period.NewCustomers,
+		ReturningCustomers: period.ActiveCustomers - period.NewCustomers,
+		OrderCount:         period.OrderCount,
+		Revenue:            numericToDecimal(period.Revenue),
+		AvgOrderValue:      numericToDecimal(period.AvgOrderValue),
+		TotalCustomers:     base.TotalCustomers,
+		OrderingCustomers:  base.OrderingCustomers,
+		RepeatCustomers:    base.RepeatCustomers,
+		RepeatRate:         ratio(base.RepeatCustomers, base.OrderingCustomers),
+		ChurnDays:          churnDays,
+		ChurnedCustomers:   base.ChurnedCustomers,
+		ChurnRate:          ratio(base.ChurnedCustomers, base.OrderingCustomers),
+		AvgLifetimeValue:   numericToDecimal(base.AvgLifetimeValue),
+		Trend:              trend,
+	}, nil
+}
+
+// ratio returns part/whole as a percentage rounded to two places.
+func ratio(part, whole int32) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(int64(part)*10000/int64(whole)) / 100
+}
+
+// ---------- Cohorts ----------
+
+// Cohort is one row of the retention matrix: customers who signed up in a
+// month and how many of them ordered in each following month. Index 0 of
+// Active and Retention is the signup month itself.
+type Cohort struct {
+	Month     string    `json:"cohort_month"`
+	Customers int32     `json:"customers"`
+	Active    []int32   `json:"active_customers"`
+	Retention []float64 `json:"retention_pct"`
+}
+
+// GetCustomerCohorts returns the monthly signup cohorts of the last months
+// months (Asia/Dhaka) with their retention.
+func (s *Service) GetCustomerCohorts(ctx context.Context, tenantID uuid.UUID, months int) ([]Cohort, error) {
+	if months <= 0 || months > maxCohortMonths {
+		months = 12
+	}
+	now := timeutil.ToBD(time.Now())
+	from := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, timeutil.BangladeshLocation)
+
+	params := sqlc.ListSignupCohortSizesParams{
+		TenantID: pgtype.UUID{Bytes: tenantID, Valid: true},
+		FromTime: from,
+	}
+	sizes, err := s.q.ListSignupCohortSizes(ctx, params)
+	if err != nil {
+		return nil, apperror.Internal("list cohort sizes", err)
+	}
+	activity, err := s.q.ListSignupCohortActivity(ctx, sqlc.ListSignupCohortActivityParams(params))
+	if err != nil {
+		return nil, apperror.Internal("list cohort activity", err)
+	}
+	return buildCohorts(sizes, activity, now), nil
+}
+
+// buildCohorts lays cohort activity out as a retention matrix. Each cohort
+// gets one column per month from signup through the month of now.
+func buildCohorts(sizes []sqlc.ListSignupCohortSizesRow, activity []sqlc.ListSignupCohortActivityRow, now time.Time) []Cohort {
+	cohorts := make([]Cohort, 0, len(sizes))
+	index := make(map[string]int, len(sizes))
+	for _, row := range sizes {
+		if !row.CohortMonth.Valid {
+			continue
+		}
+		span := monthsBetween(row.CohortMonth.Time, now) + 1
+		if span < 1 {
+			span = 1
+		}
+		key := row.CohortMonth.Time.Format("2006-01")
+		index[key] = len(cohorts)
+		cohorts = append(cohorts, Cohort{
+			Month:     key,
+			Customers: row.Customers,
+			Active:    make([]int32, span),
+			Retention: make([]float64, span),
+		})
+	}
+
+	for _, row := range activity {
+		if !row.CohortMonth.Valid || !row.ActivityMonth.Valid {
+			continue
+		}
+		i, ok := index[row.CohortMonth.Time.Format("2006-01")]
+		if !ok {
+			continue
+		}
+		offset := monthsBetween(row.CohortMonth.Time, row.ActivityMonth.Time)
+		c := &cohorts[i]
+		if offset < 0 || offset >= len(c.Active) {
+			continue
+		}
+		c.Active[offset] = row.ActiveCustomers
+		c.Retention[offset] = ratio(row.ActiveCustomers, c.Customers)
+	}
+	return cohorts
+}
+
+// monthsBetween counts calendar months from a to b.
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
+
+// ---------- RFM ----------
+
+// CustomerRFM is a customer's recency, frequency and monetary scores (1-5,
+// quintiles across the tenant's ordering customers) and resulting segment.
+type CustomerRFM struct {
+	UserID      uuid.UUID       `json:"user_id"`
+	Name        string          `json:"name"`
+	Phone       string          `json:"phone,omitempty"`
+	Email       string          `json:"email,omitempty"`
+	Orders      int32           `json:"orders"`
+	TotalSpent  decimal.Decimal `json:"total_spent"`
+	LastOrderAt time.Time       `json:"last_order_at"`
+	RecencyDays int             `json:"recency_days"`
+	Recency     int             `json:"r"`
+	Frequency   int             `json:"f"`
+	Monetary    int             `json:"m"`
+	Segment     string          `json:"segment"`
+}
+
+// SegmentSummary aggregates the customers of one RFM segment.
+type SegmentSummary struct {
+	Segment        string          `json:"segment"`
+	Customers      int             `json:"customers"`
+	Revenue        decimal.Decimal `json:"revenue"`
+	AvgOrders      float64         `json:"avg_orders"`
+	AvgRecencyDays int             `json:"avg_recency_days"`
+}
+
+// ListCustomerRFM scores every customer with a delivered order.
+func (s *Service) ListCustomerRFM(ctx context.Context, tenantID uuid.UUID) ([]CustomerRFM, error) {
+	rows, err := s.q.ListCustomerRFMInputs(ctx, pgtype.UUID{Bytes: tenantID, Valid: true})
+	if err != nil {
+		return nil, apperror.Internal("list customer rfm inputs", err)
+	}
+	return scoreRFM(rows, time.Now()), nil
+}
+
+// GetRFMSegments returns the size and value of each RFM segment.
+func (s *Service) GetRFMSegments(ctx context.Context, tenantID uuid.UUID) ([]SegmentSummary, error) {
+	customers, err := s.ListCustomerRFM(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeSegments(customers), nil
+}
+
+// ListSegmentCustomers returns the customers of one segment, most valuable
+// first.
+func (s *Service) ListSegmentCustomers(ctx context.Context, tenantID uuid.UUID, segment string) ([]CustomerRFM, error) {
+	if !IsSegment(segment) {
+		return nil, apperror.BadRequest("unknown segment")
+	}
+	customers, err := s.ListCustomerRFM(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]CustomerRFM, 0)
+	for _, c := range customers {
+		if c.Segment == segment {
+			members = append(members, c)
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].TotalSpent.GreaterThan(members[j].TotalSpent)
+	})
+	return members, nil
+}
+
+// SegmentEligibilityResult reports a segment copied into a promo's
+// eligibility list.
+type SegmentEligibilityResult struct {
+	PromoID   uuid.UUID `json:"promo_id"`
+	Segment   string    `json:"segment"`
+	Customers int       `json:"customers"`
+	Added     int64     `json:"added"`
+	Removed   int64     `json:"removed"`
+}
+
+// ExportSegmentToPromo makes the customers of a segment eligible for a promo.
+// With replace, users outside the segment lose eligibility. An empty segment
+// is refused: a promo with no eligibility rows is open to everyone.
+func (s *Service) ExportSegmentToPromo(ctx context.Context, tenantID, promoID uuid.UUID, segment string, replace bool) (*SegmentEligibilityResult, error) {
+	if _, err := s.q.GetPromoByID(ctx, sqlc.GetPromoByIDParams{ID: promoID, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("promo")
+		}
+		return nil, apperror.Internal("get promo", err)
+	}
+
+	members, err := s.ListSegmentCustomers(ctx, tenantID, segment)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, apperror.BadRequest("segment has no customers")
+	}
+	userIDs := make([]uuid.UUID, len(members))
+	for i, c := range members {
+		userIDs[i] = c.UserID
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	result := &SegmentEligibilityResult{PromoID: promoID, Segment: segment, Customers: len(userIDs)}
+	if replace {
+		result.Removed, err = qtx.RemovePromoUserEligibilityExcept(ctx, sqlc.RemovePromoUserEligibilityExceptParams{
+			PromoID: promoID,
+			UserIds: userIDs,
+		})
+		if err != nil {
+			return nil, apperror.Internal("trim promo eligibility", err)
+		}
+	}
+	result.Added, err = qtx.AddPromoUserEligibilityBulk(ctx, sqlc.AddPromoUserEligibilityBulkParams{
+		PromoID: promoID,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		return nil, apperror.Internal("add promo eligibility", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit", err)
+	}
+	return result, nil
+}
+
+// scoreRFM assigns quintile scores and segments as of now.
+func scoreRFM(rows []sqlc.ListCustomerRFMInputsRow, now time.Time) []CustomerRFM {
+	customers := make([]CustomerRFM, len(rows))
+	recency := make([]float64, len(rows))
+	frequency := make([]float64, len(rows))
+	monetary := make([]float64, len(rows))
+	for i, row := range rows {
+		days := 0
+		if row.LastOrderAt.Valid && now.After(row.LastOrderAt.Time) {
+			days = int(now.Sub(row.LastOrderAt.Time).Hours() / 24)
+		}
+		spent := numericToDecimal(row.TotalSpentAmount)
+		customers[i] = CustomerRFM{
+			UserID:      row.ID,
+			Name:        row.Name,
+			Phone:       row.Phone.String,
+			Email:       row.Email.String,
+			Orders:      row.TotalOrderCount,
+			TotalSpent:  spent,
+			LastOrderAt: row.LastOrderAt.Time,
+			RecencyDays: days,
+		}
+		// Fewer days since the last order is better, so score the negation.
+		recency[i] = -float64(days)
+		frequency[i] = float64(row.TotalOrderCount)
+		monetary[i] = spent.InexactFloat64()
+	}
+
+	r, f, m := quintiles(recency), quintiles(frequency), quintiles(monetary)
+	for i := range customers {
+		customers[i].Recency, customers[i].Frequency, customers[i].Monetary = r[i], f[i], m[i]
+		customers[i].Segment = classifySegment(r[i], f[i], m[i])
+	}
+	return customers
+}
+
+// quintiles scores values 1-5 by rank, higher values scoring higher. Equal
+// values share the score of their lowest rank so ties never split.
+func quintiles(values []float64) []int {
+	n := len(values)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	scores := make([]int, n)
+	rank := 0
+	for pos, i := range order {
+		if pos == 0 || values[i] != values[order[pos-1]] {
+			rank = pos
+		}
+		scores[i] = rank*5/n + 1
+	}
+	return scores
+}
+
+// classifySegment maps RFM scores to a named segment.
+func classifySegment(r, f, m int) string {
+	switch {
+	case r >= 4 && f >= 4:
+		return SegmentChampions
+	case r >= 4 && f <= 1:
+		return SegmentNew
+	case r >= 3 && f >= 3:
+		return SegmentLoyal
+	case r >= 3:
+		return SegmentPotentialLoyalist
+	case f >= 4 && m >= 4:
+		return SegmentCantLose
+	case f >= 3:
+		return SegmentAtRisk
+	case r == 2:
+		return SegmentHibernating
+	default:
+		return SegmentLost
+	}
+}
+
+// summarizeSegments totals customers per segment, listing every segment.
+func summarizeSegments(customers []CustomerRFM) []SegmentSummary {
+	type totals struct {
+		customers, orders, recency int
+		revenue                    decimal.Decimal
+	}
+	bySegment := make(map[string]*totals, len(Segments))
+	for _, seg := range Segments {
+		bySegment[seg] = &totals{}
+	}
+	for _, c := range customers {
+		t := bySegment[c.Segment]
+		t.customers++
+		t.orders += int(c.Orders)
+		t.recency += c.RecencyDays
+		t.revenue = t.revenue.Add(c.TotalSpent)
+	}
+
+	out := make([]SegmentSummary, 0, len(Segments))
+	for _, seg := range Segments {
+		t := bySegment[seg]
+		summary := SegmentSummary{Segment: seg, Customers: t.customers, Revenue: t.revenue}
+		if t.customers > 0 {
+			summary.AvgOrders = float64(t.orders*100/t.customers) / 100
+			summary.AvgRecencyDays = t.recency / t.customers
+		}
+		out = append(out, summary)
+	}
+	return out
+}
+
+// ---------- Lifetime value ----------
+
+// GetLTVByAcquisitionPromo returns lifetime value of customers acquired in
+// the range, grouped by the promo code on their first delivered order. An
+// empty code groups organic customers.
+func (s *Service) GetLTVByAcquisitionPromo(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) ([]sqlc.ListCustomerLTVByAcquisitionPromoRow, error) {
+	rows, err := s.q.ListCustomerLTVByAcquisitionPromo(ctx, sqlc.ListCustomerLTVByAcquisitionPromoParams{
+		TenantID:  tenantID,
+		StartDate: toPgDate(startDate),
+		EndDate:   toPgDate(endDate),
+	})
+	if err != nil {
+		return nil, apperror.Internal("list ltv by acquisition promo", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,128 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func TestQuintiles(t *testing.T) {
+	got := quintiles([]float64{10, 50, 20, 40, 30})
+	want := []int{1, 5, 2, 4, 3}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("quintiles = %v, want %v", got, want)
+		}
+	}
+
+	// Ties share the lowest rank's score.
+	got = quintiles([]float64{1, 1, 1, 1, 9})
+	for i, s := range got[:4] {
+		if s != 1 {
+			t.Errorf("tied value %d scored %d, want 1", i, s)
+		}
+	}
+	if got[4] != 5 {
+		t.Errorf("top value scored %d, want 5", got[4])
+	}
+}
+
+func TestClassifySegment(t *testing.T) {
+	cases := []struct {
+		r, f, m int
+		want    string
+	}{
+		{5, 5, 5, SegmentChampions},
+		{5, 1, 1, SegmentNew},
+		{3, 4, 2, SegmentLoyal},
+		{3, 2, 2, SegmentPotentialLoyalist},
+		{1, 5, 5, SegmentCantLose},
+		{2, 3, 2, SegmentAtRisk},
+		{2, 2, 2, SegmentHibernating},
+		{1, 1, 1, SegmentLost},
+	}
+	for _, c := range cases {
+		if got := classifySegment(c.r, c.f, c.m); got != c.want {
+			t.Errorf("classifySegment(%d, %d, %d) = %s, want %s", c.r, c.f, c.m, got, c.want)
+		}
+	}
+}
+
+func TestScoreRFM(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	customer := func(daysAgo int, orders int32, spent string) sqlc.ListCustomerRFMInputsRow {
+		return sqlc.ListCustomerRFMInputsRow{
+			ID:               uuid.New(),
+			TotalOrderCount:  orders,
+			TotalSpentAmount: toPgNumeric(decimal.RequireFromString(spent)),
+			LastOrderAt:      pgtype.Timestamptz{Time: now.AddDate(0, 0, -daysAgo), Valid: true},
+		}
+	}
+	rows := []sqlc.ListCustomerRFMInputsRow{
+		customer(1, 20, "12000"),
+		customer(200, 1, "150"),
+		customer(30, 5, "2500"),
+		customer(90, 3, "900"),
+		customer(3, 1, "300"),
+	}
+
+	got := scoreRFM(rows, now)
+	if got[0].Segment != SegmentChampions {
+		t.Errorf("frequent recent big spender = %s, want champions", got[0].Segment)
+	}
+	if got[1].Segment != SegmentLost || got[1].RecencyDays != 200 {
+		t.Errorf("one-off lapsed customer = %s (%d days), want lost", got[1].Segment, got[1].RecencyDays)
+	}
+	if got[0].Recency != 5 || got[1].Recency != 1 {
+		t.Errorf("recency scores = %d, %d; want 5, 1", got[0].Recency, got[1].Recency)
+	}
+
+	summary := summarizeSegments(got)
+	if len(summary) != len(Segments) {
+		t.Fatalf("summary has %d segments, want %d", len(summary), len(Segments))
+	}
+	total := 0
+	for _, s := range summary {
+		total += s.Customers
+	}
+	if total != len(rows) {
+		t.Errorf("segments cover %d customers, want %d", total, len(rows))
+	}
+}
+
+func TestBuildCohorts(t *testing.T) {
+	month := func(y int, m time.Month) pgtype.Date {
+		return pgtype.Date{Time: time.Date(y, m, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	sizes := []sqlc.ListSignupCohortSizesRow{
+		{CohortMonth: month(2026, 4), Customers: 10},
+		{CohortMonth: month(2026, 6), Customers: 4},
+	}
+	activity := []sqlc.ListSignupCohortActivityRow{
+		{CohortMonth: month(2026, 4), ActivityMonth: month(2026, 4), ActiveCustomers: 8},
+		{CohortMonth: month(2026, 4), ActivityMonth: month(2026, 6), ActiveCustomers: 3},
+		{CohortMonth: month(2026, 6), ActivityMonth: month(2026, 6), ActiveCustomers: 4},
+	}
+
+	got := buildCohorts(sizes, activity, time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC))
+	if len(got) != 2 {
+		t.Fatalf("got %d cohorts, want 2", len(got))
+	}
+	april := got[0]
+	if april.Month != "2026-04" || len(april.Active) != 3 {
+		t.Fatalf("april cohort = %+v, want 3 months of activity", april)
+	}
+	if april.Active[0] != 8 || april.Active[1] != 0 || april.Active[2] != 3 {
+		t.Errorf("april activity = %v, want [8 0 3]", april.Active)
+	}
+	if april.Retention[0] != 80 || april.Retention[2] != 30 {
+		t.Errorf("april retention = %v, want [80 0 30]", april.Retention)
+	}
+	if june := got[1]; len(june.Active) != 1 || june.Retention[0] != 100 {
+		t.Errorf("june cohort = %+v, want one month at 100%%", june)
+	}
+}
@@ -0,0 +1,306 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// EventOrderCompleted is written to the outbox when an order reaches a
+// terminal status; its handler records the order's analytics fact.
+const EventOrderCompleted = "order.completed"
+
+const (
+	// factBatchSize is how many terminal orders one page of the catch-up or
+	// a backfill picks up.
+	factBatchSize = 200
+
+	// catchUpJob names the catch-up's row in job_watermarks.
+	catchUpJob = "analytics:order_facts"
+
+	// catchUpLag keeps the catch-up behind recent status changes, whose
+	// outbox events are still being processed.
+	catchUpLag = 10 * time.Minute
+)
+
+// orderCompletedEvent is the outbox payload of EventOrderCompleted.
+type orderCompletedEvent struct {
+	OrderID  uuid.UUID `json:"order_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+}
+
+// EnqueueOrderFact writes the outbox event that records the analytics fact
+// for an order that just reached a terminal status. Pass the transaction's
+// queries so the event commits together with the status change.
+func EnqueueOrderFact(ctx context.Context, q *sqlc.Queries, o sqlc.Order) error {
+	payload, _ := json.Marshal(orderCompletedEvent{OrderID: o.ID, TenantID: o.TenantID})
+	if _, err := q.CreateOutboxEvent(ctx, sqlc.CreateOutboxEventParams{
+		TenantID:      pgtype.UUID{Bytes: o.TenantID, Valid: true},
+		AggregateType: "order",
+		AggregateID:   o.ID,
+		EventType:     EventOrderCompleted,
+		Payload:       payload,
+		MaxAttempts:   5,
+	}); err != nil {
+		return fmt.Errorf("create order fact outbox event: %w", err)
+	}
+	return nil
+}
+
+// buildOrderFact derives the order_analytics row for a terminal order. Time
+// dimensions are in Asia/Dhaka. Commission is only earned on delivered
+// orders, from the restaurants that did not reject their part.
+func buildOrderFact(o sqlc.Order, pickups []sqlc.OrderPickup) sqlc.UpsertOrderAnalyticsParams {
+	restaurants := make([]uuid.UUID, 0, len(pickups))
+	seen := make(map[uuid.UUID]bool, len(pickups))
+	commission := decimal.Zero
+	for _, p := range pickups {
+		if !seen[p.RestaurantID] {
+			seen[p.RestaurantID] = true
+			restaurants = append(restaurants, p.RestaurantID)
+		}
+		if o.Status == sqlc.OrderStatusDelivered && p.Status != sqlc.PickupStatusRejected {
+			commission = commission.Add(numericToDecimal(p.CommissionAmount))
+		}
+	}
+
+	created := timeutil.ToBD(o.CreatedAt)
+	_, week := created.ISOWeek()
+
+	prepStart := o.PreparingAt
+	if !prepStart.Valid {
+		prepStart = o.ConfirmedAt
+	}
+
+	reason := o.CancellationReason
+	if !reason.Valid {
+		reason = o.RejectionReason
+	}
+
+	return sqlc.UpsertOrderAnalyticsParams{
+		TenantID:              o.TenantID,
+		OrderID:               o.ID,
+		RestaurantIds:         restaurants,
+		CustomerID:            o.CustomerID,
+		RiderID:               o.RiderID,
+		HubID:                 o.HubID,
+		DeliveryArea:          nullString(o.DeliveryArea),
+		PaymentMethod:         string(o.PaymentMethod),
+		Platform:              string(o.Platform),
+		PromoCode:             o.PromoCode,
+		Subtotal:              orZero(o.Subtotal),
+		ItemDiscount:          orZero(o.ItemDiscountTotal),
+		PromoDiscount:         orZero(o.PromoDiscountTotal),
+		DeliveryCharge:        orZero(o.DeliveryCharge),
+		VatTotal:              orZero(o.VatTotal),
+		TotalAmount:           orZero(o.TotalAmount),
+		CommissionTotal:       toPgNumeric(commission),
+		ConfirmationDurationS: durationSeconds(pgtype.Timestamptz{Time: o.CreatedAt, Valid: true}, o.ConfirmedAt),
+		PreparationDurationS:  durationSeconds(prepStart, o.ReadyAt),
+		PickupToDeliveryS:     durationSeconds(o.PickedAt, o.DeliveredAt),
+		TotalFulfillmentS:     durationSeconds(pgtype.Timestamptz{Time: o.CreatedAt, Valid: true}, o.DeliveredAt),
+		FinalStatus:           string(o.Status),
+		CancellationReason:    reason,
+		OrderDate:             pgtype.Date{Time: time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		OrderHour:             int16(created.Hour()),
+		OrderDayOfWeek:        int16(created.Weekday()),
+		OrderWeek:             int32(week),
+		OrderMonth:            int16(created.Month()),
+		OrderYear:             int16(created.Year()),
+		CompletedAt:           completedAt(o),
+	}
+}
+
+// durationSeconds returns the whole seconds between two transitions, or nil
+// if either never happened or they are out of order.
+func durationSeconds(from, to pgtype.Timestamptz) *int32 {
+	if !from.Valid || !to.Valid || to.Time.Before(from.Time) {
+		return nil
+	}
+	s := int32(to.Time.Sub(from.Time) / time.Second)
+	return &s
+}
+
+// completedAt is when the order reached its terminal status. Rejections do
+// not stamp a transition time, so the last update stands in for it.
+func completedAt(o sqlc.Order) pgtype.Timestamptz {
+	switch {
+	case o.DeliveredAt.Valid:
+		return o.DeliveredAt
+	case o.CancelledAt.Valid:
+		return o.CancelledAt
+	default:
+		return pgtype.Timestamptz{Time: o.UpdatedAt, Valid: true}
+	}
+}
+
+// RecordOrderFact writes or refreshes the analytics fact for a terminal
+// order and the customer's lifetime counters on users.
+func (s *Service) RecordOrderFact(ctx context.Context, o sqlc.Order) error {
+	pickups, err := s.q.GetOrderPickupsByOrder(ctx, o.ID)
+	if err != nil {
+		return fmt.Errorf("list pickups for %s: %w", o.ID, err)
+	}
+	if _, err := s.q.UpsertOrderAnalytics(ctx, buildOrderFact(o, pickups)); err != nil {
+		return fmt.Errorf("upsert order analytics for %s: %w", o.ID, err)
+	}
+	if err := s.q.RefreshCustomerOrderStats(ctx, o.CustomerID); err != nil {
+		return fmt.Errorf("refresh customer stats for %s: %w", o.ID, err)
+	}
+	return nil
+}
+
+// HandleOrderCompleted records the analytics fact for an order.completed
+// outbox event. Registered with the background worker, which retries a
+// failed event with backoff and dead-letters it after its last attempt.
+func (s *Service) HandleOrderCompleted(ctx context.Context, event sqlc.OutboxEvent) error {
+	var ev orderCompletedEvent
+	if err := json.Unmarshal(event.Payload, &ev); err != nil {
+		return fmt.Errorf("decode order completed event: %w", err)
+	}
+	o, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: ev.OrderID, TenantID: ev.TenantID})
+	if err != nil {
+		return fmt.Errorf("get order %s: %w", ev.OrderID, err)
+	}
+	return s.RecordOrderFact(ctx, o)
+}
+
+// CatchUpOrderFacts enqueues order.completed events for terminal orders that
+// have neither a fact nor an event, e.g. orders finished before events were
+// written. It walks orders by (updated_at, id) from a stored watermark, so
+// each run only reads what changed since the last one. Registered with the
+// background worker.
+func (s *Service) CatchUpOrderFacts(ctx context.Context) error {
+	mark, err := s.q.GetJobWatermark(ctx, catchUpJob)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("get watermark: %w", err)
+	}
+	afterUpdated, afterID := mark.LastUpdatedAt, mark.LastID
+	before := time.Now().Add(-catchUpLag)
+
+	enqueued := 0
+	for {
+		orders, err := s.q.ListTerminalOrdersAfterWatermark(ctx, sqlc.ListTerminalOrdersAfterWatermarkParams{
+			AfterUpdatedAt: afterUpdated,
+			AfterID:        afterID,
+			Before:         before,
+			Limit:          factBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("list orders after watermark: %w", err)
+		}
+		for _, o := range orders {
+			if err := EnqueueOrderFact(ctx, s.q, o); err != nil {
+				return err
+			}
+			enqueued++
+		}
+
+		// A short page means every order up to before has been seen.
+		if len(orders) < factBatchSize {
+			afterUpdated, afterID = before, uuid.Nil
+		} else {
+			last := orders[len(orders)-1]
+			afterUpdated, afterID = last.UpdatedAt, last.ID
+		}
+		if err := s.q.UpsertJobWatermark(ctx, sqlc.UpsertJobWatermarkParams{
+			Job:           catchUpJob,
+			LastUpdatedAt: afterUpdated,
+			LastID:        afterID,
+		}); err != nil {
+			return fmt.Errorf("save watermark: %w", err)
+		}
+		if len(orders) < factBatchSize {
+			break
+		}
+	}
+
+	if enqueued > 0 {
+		log.Info().Int("orders", enqueued).Msg("order facts caught up")
+	}
+	return nil
+}
+
+// BackfillOptions bounds a rebuild of historical facts.
+type BackfillOptions struct {
+	TenantID  *uuid.UUID
+	From      time.Time
+	To        time.Time
+	BatchSize int32
+}
+
+// BackfillOrderFacts rebuilds facts for every terminal order created in
+// [From, To), overwriting existing rows. It returns the number of orders
+// processed and stops at the first failure.
+func (s *Service) BackfillOrderFacts(ctx context.Context, opts BackfillOptions) (int, error) {
+	tenant := pgtype.UUID{}
+	if opts.TenantID != nil {
+		tenant = pgtype.UUID{Bytes: *opts.TenantID, Valid: true}
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = factBatchSize
+	}
+
+	done := 0
+	afterCreated, afterID := opts.From.Add(-time.Microsecond), uuid.Nil
+	for {
+		orders, err := s.q.ListTerminalOrdersForBackfill(ctx, sqlc.ListTerminalOrdersForBackfillParams{
+			TenantID:       tenant,
+			FromTime:       opts.From,
+			ToTime:         opts.To,
+			AfterCreatedAt: afterCreated,
+			AfterID:        afterID,
+			Limit:          opts.BatchSize,
+		})
+		if err != nil {
+			return done, fmt.Errorf("list orders: %w", err)
+		}
+		for _, o := range orders {
+			if err := s.RecordOrderFact(ctx, o); err != nil {
+				return done, err
+			}
+			done++
+		}
+		if len(orders) < int(opts.BatchSize) {
+			return done, nil
+		}
+		last := orders[len(orders)-1]
+		afterCreated, afterID = last.CreatedAt, last.ID
+		log.Info().Int("orders", done).Time("through", afterCreated).Msg("order analytics backfill progress")
+	}
+}
+
+func orZero(n pgtype.Numeric) pgtype.Numeric {
+	if !n.Valid {
+		return toPgNumeric(decimal.Zero)
+	}
+	return n
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
+
+func toPgNumeric(d decimal.Decimal) pgtype.Numeric {
+	n := pgtype.Numeric{Valid: true}
+	_ = n.Scan(d.StringFixed(2))
+	return n
+}
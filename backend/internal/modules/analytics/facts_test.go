@@ -0,0 +1,85 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildOrderFact(t *testing.T) {
+	// 23:30 on a Sunday in Dhaka is still Sunday 17:30 UTC.
+	created, err := timeutil.ParseBD("2006-01-02 15:04", "2026-03-01 23:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: created.Add(d), Valid: true} }
+	num := func(s string) pgtype.Numeric { return toPgNumeric(decimal.RequireFromString(s)) }
+
+	restaurant := uuid.New()
+	order := sqlc.Order{
+		ID:           uuid.New(),
+		Status:       sqlc.OrderStatusDelivered,
+		DeliveryArea: "Gulshan",
+		CreatedAt:    created.UTC(),
+		ConfirmedAt:  at(2 * time.Minute),
+		ReadyAt:      at(20 * time.Minute),
+		PickedAt:     at(25 * time.Minute),
+		DeliveredAt:  at(40 * time.Minute),
+		TotalAmount:  num("500"),
+	}
+	pickups := []sqlc.OrderPickup{
+		{RestaurantID: restaurant, Status: sqlc.PickupStatusPicked, CommissionAmount: num("40")},
+		{RestaurantID: uuid.New(), Status: sqlc.PickupStatusRejected, CommissionAmount: num("25")},
+	}
+
+	f := buildOrderFact(order, pickups)
+	if f.OrderDate.Time.Day() != 1 || f.OrderHour != 23 || f.OrderDayOfWeek != 0 || f.OrderWeek != 9 {
+		t.Errorf("time dimensions = %v %d dow %d week %d, want 1 March 23h, Sunday, week 9",
+			f.OrderDate.Time, f.OrderHour, f.OrderDayOfWeek, f.OrderWeek)
+	}
+	if f.ConfirmationDurationS == nil || *f.ConfirmationDurationS != 120 {
+		t.Errorf("confirmation = %v, want 120s", f.ConfirmationDurationS)
+	}
+	if f.PreparationDurationS == nil || *f.PreparationDurationS != 18*60 {
+		t.Errorf("preparation = %v, want 18m measured from confirmation", f.PreparationDurationS)
+	}
+	if f.PickupToDeliveryS == nil || *f.PickupToDeliveryS != 15*60 || *f.TotalFulfillmentS != 40*60 {
+		t.Errorf("delivery timings = %v %v, want 15m and 40m", f.PickupToDeliveryS, f.TotalFulfillmentS)
+	}
+	if got := numericToDecimal(f.CommissionTotal); !got.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("commission = %s, want 40 without the rejected restaurant", got)
+	}
+	if len(f.RestaurantIds) != 2 || f.CompletedAt != order.DeliveredAt {
+		t.Errorf("restaurants = %v completed = %v", f.RestaurantIds, f.CompletedAt)
+	}
+}
+
+func TestBuildOrderFactCancelled(t *testing.T) {
+	created := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	order := sqlc.Order{
+		Status:             sqlc.OrderStatusCancelled,
+		CreatedAt:          created,
+		CancelledAt:        pgtype.Timestamptz{Time: created.Add(5 * time.Minute), Valid: true},
+		CancellationReason: nullString("customer changed mind"),
+	}
+	pickups := []sqlc.OrderPickup{{RestaurantID: uuid.New(), Status: sqlc.PickupStatusNew, CommissionAmount: toPgNumeric(decimal.NewFromInt(30))}}
+
+	f := buildOrderFact(order, pickups)
+	if !numericToDecimal(f.CommissionTotal).IsZero() {
+		t.Error("a cancelled order should earn no commission")
+	}
+	if f.ConfirmationDurationS != nil || f.TotalFulfillmentS != nil {
+		t.Error("transitions that never happened should be nil")
+	}
+	if f.CancellationReason.String != "customer changed mind" || f.CompletedAt != order.CancelledAt {
+		t.Errorf("reason = %q completed = %v", f.CancellationReason.String, f.CompletedAt)
+	}
+	if f.DeliveryArea.Valid {
+		t.Error("an empty delivery area should be stored as NULL")
+	}
+}
@@ -1,13 +1,17 @@
 package analytics
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/modules/access"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/pagination"
 	"github.com/munchies/platform/backend/internal/pkg/respond"
 )
 
@@ -22,13 +26,20 @@ func NewHandler(svc *Service) *Handler {
 }
 
 // GetDashboard handles GET /partner/dashboard
+// Restaurant managers see their own restaurants only; an optional
+// restaurant_id query param narrows the figures to one restaurant.
 func (h *Handler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 	t := tenant.FromContext(r.Context())
 	if t == nil {
 		respond.Error(w, apperror.NotFound("tenant"))
 		return
 	}
-	dashboard, err := h.svc.GetDashboard(r.Context(), t.ID)
+	restaurantIDs, err := restaurantFilter(r)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	dashboard, err := h.svc.GetDashboard(r.Context(), t.ID, restaurantIDs)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -44,7 +55,12 @@ func (h *Handler) GetSalesReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	startDate, endDate := parseDateRange(r)
-	report, err := h.svc.GetSalesReport(r.Context(), t.ID, startDate, endDate)
+	restaurantIDs, err := restaurantFilter(r)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	report, err := h.svc.GetSalesReport(r.Context(), t.ID, restaurantIDs, startDate, endDate)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -60,7 +76,12 @@ func (h *Handler) GetTopProducts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	startDate, endDate := parseDateRange(r)
-	products, err := h.svc.GetTopProducts(r.Context(), t.ID, startDate, endDate, 20)
+	restaurantIDs, err := restaurantFilter(r)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	products, err := h.svc.GetTopProducts(r.Context(), t.ID, restaurantIDs, startDate, endDate, 20)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -76,7 +97,12 @@ func (h *Handler) GetOrderBreakdown(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	startDate, endDate := parseDateRange(r)
-	breakdown, err := h.svc.GetOrderBreakdown(r.Context(), t.ID, startDate, endDate)
+	restaurantIDs, err := restaurantFilter(r)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	breakdown, err := h.svc.GetOrderBreakdown(r.Context(), t.ID, restaurantIDs, startDate, endDate)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -92,7 +118,12 @@ func (h *Handler) GetPeakHours(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	startDate, endDate := parseDateRange(r)
-	hours, err := h.svc.GetPeakHours(r.Context(), t.ID, startDate, endDate)
+	restaurantIDs, err := restaurantFilter(r)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	hours, err := h.svc.GetPeakHours(r.Context(), t.ID, restaurantIDs, startDate, endDate)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -116,6 +147,134 @@ func (h *Handler) GetRiderAnalytics(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, riders)
 }
 
+// --- Customer Analytics ---
+
+// GetCustomerSummary handles GET /partner/reports/customers/summary
+// churn_days overrides how long without an order counts as churned.
+func (h *Handler) GetCustomerSummary(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	churnDays := DefaultChurnDays
+	if v := r.URL.Query().Get("churn_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respond.Error(w, apperror.BadRequest("churn_days must be a positive number"))
+			return
+		}
+		churnDays = n
+	}
+	startDate, endDate := parseDateRange(r)
+	summary, err := h.svc.GetCustomerSummary(r.Context(), t.ID, startDate, endDate, churnDays)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, summary)
+}
+
+// GetCustomerCohorts handles GET /partner/reports/customers/cohorts?months=12
+func (h *Handler) GetCustomerCohorts(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	months, _ := strconv.Atoi(r.URL.Query().Get("months"))
+	cohorts, err := h.svc.GetCustomerCohorts(r.Context(), t.ID, months)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, cohorts)
+}
+
+// GetRFMSegments handles GET /partner/reports/customers/rfm
+func (h *Handler) GetRFMSegments(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	segments, err := h.svc.GetRFMSegments(r.Context(), t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, segments)
+}
+
+// ListSegmentCustomers handles GET /partner/reports/customers/segments/{segment}
+func (h *Handler) ListSegmentCustomers(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	members, err := h.svc.ListSegmentCustomers(r.Context(), t.ID, chi.URLParam(r, "segment"))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	limit, offset := pagination.FormatLimitOffset(page, perPage)
+	data := members[min(offset, len(members)):min(offset+limit, len(members))]
+	respond.JSON(w, http.StatusOK, pagination.PagedResponse{
+		Data: data,
+		Meta: pagination.NewMeta(int64(len(members)), limit, ""),
+	})
+}
+
+// ExportSegmentToPromo handles POST /partner/reports/customers/segments/{segment}/promo-eligibility
+// It restricts the promo to the segment's customers; with replace, customers
+// outside the segment lose eligibility.
+func (h *Handler) ExportSegmentToPromo(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	var req struct {
+		PromoID uuid.UUID `json:"promo_id"`
+		Replace bool      `json:"replace"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if req.PromoID == uuid.Nil {
+		respond.Error(w, apperror.BadRequest("promo_id is required"))
+		return
+	}
+	result, err := h.svc.ExportSegmentToPromo(r.Context(), t.ID, req.PromoID, chi.URLParam(r, "segment"), req.Replace)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, result)
+}
+
+// GetLTVByAcquisitionPromo handles GET /partner/reports/customers/ltv
+func (h *Handler) GetLTVByAcquisitionPromo(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	startDate, endDate := parseDateRange(r)
+	rows, err := h.svc.GetLTVByAcquisitionPromo(r.Context(), t.ID, startDate, endDate)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, rows)
+}
+
 // --- Admin Analytics ---
 
 // AdminOverview handles GET /admin/analytics/overview
@@ -167,6 +326,25 @@ func (h *Handler) AdminTenantAnalytics(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, analytics)
 }
 
+// restaurantFilter returns the restaurants a partner report covers: the
+// caller's access scope, narrowed by an optional restaurant_id query param.
+// nil means the whole tenant.
+func restaurantFilter(r *http.Request) ([]uuid.UUID, error) {
+	scope := access.FromContext(r.Context())
+	if scope == nil {
+		return nil, apperror.Forbidden("restaurant access not resolved")
+	}
+	var requested []uuid.UUID
+	if v := r.URL.Query().Get("restaurant_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid restaurant_id")
+		}
+		requested = append(requested, id)
+	}
+	return scope.Restrict(requested)
+}
+
 func parseDateRange(r *http.Request) (time.Time, time.Time) {
 	q := r.URL.Query()
 	endDate := time.Now()
@@ -6,18 +6,20 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 )
 
 // Service implements analytics business logic.
 type Service struct {
-	q *sqlc.Queries
+	q    *sqlc.Queries
+	pool *pgxpool.Pool
 }
 
 // NewService creates a new analytics service.
-func NewService(q *sqlc.Queries) *Service {
-	return &Service{q: q}
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool) *Service {
+	return &Service{q: q, pool: pool}
 }
 
 // DashboardResponse holds partner dashboard data.
@@ -28,9 +30,13 @@ type DashboardResponse struct {
 	Pending int                           `json:"pending_orders"`
 }
 
-// GetDashboard returns partner dashboard KPIs.
-func (s *Service) GetDashboard(ctx context.Context, tenantID uuid.UUID) (*DashboardResponse, error) {
-	today, err := s.q.GetDashboardToday(ctx, tenantID)
+// GetDashboard returns partner dashboard KPIs. restaurantIDs limits the
+// figures to orders from those restaurants; nil covers the whole tenant.
+func (s *Service) GetDashboard(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID) (*DashboardResponse, error) {
+	today, err := s.q.GetDashboardToday(ctx, sqlc.GetDashboardTodayParams{
+		TenantID:      tenantID,
+		RestaurantIds: restaurantIDs,
+	})
 	if err != nil {
 		return nil, apperror.Internal("get dashboard today", err)
 	}
@@ -38,25 +44,30 @@ func (s *Service) GetDashboard(ctx context.Context, tenantID uuid.UUID) (*Dashbo
 	endDate := time.Now()
 	startDate := endDate.AddDate(0, 0, -7)
 	trend, err := s.q.GetDashboardTrend(ctx, sqlc.GetDashboardTrendParams{
-		TenantID:  tenantID,
-		StartDate: toPgDate(startDate),
-		EndDate:   toPgDate(endDate),
+		TenantID:      tenantID,
+		StartDate:     toPgDate(startDate),
+		EndDate:       toPgDate(endDate),
+		RestaurantIds: restaurantIDs,
 	})
 	if err != nil {
 		return nil, apperror.Internal("get dashboard trend", err)
 	}
 
 	topProducts, err := s.q.GetTopProducts(ctx, sqlc.GetTopProductsParams{
-		TenantID:  tenantID,
-		StartDate: startDate,
-		EndDate:   endDate,
-		Limit:     3,
+		TenantID:      tenantID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		RestaurantIds: restaurantIDs,
+		Limit:         3,
 	})
 	if err != nil {
 		return nil, apperror.Internal("get top products", err)
 	}
 
-	pending, err := s.q.GetPendingOrderCount(ctx, tenantID)
+	pending, err := s.q.GetPendingOrderCount(ctx, sqlc.GetPendingOrderCountParams{
+		TenantID:      tenantID,
+		RestaurantIds: restaurantIDs,
+	})
 	if err != nil {
 		return nil, apperror.Internal("get pending count", err)
 	}
@@ -70,29 +81,32 @@ func (s *Service) GetDashboard(ctx context.Context, tenantID uuid.UUID) (*Dashbo
 }
 
 // GetSalesReport returns sales report data.
-func (s *Service) GetSalesReport(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) ([]sqlc.GetSalesReportRow, error) {
+func (s *Service) GetSalesReport(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, startDate, endDate time.Time) ([]sqlc.GetSalesReportRow, error) {
 	return s.q.GetSalesReport(ctx, sqlc.GetSalesReportParams{
-		TenantID:  tenantID,
-		StartDate: toPgDate(startDate),
-		EndDate:   toPgDate(endDate),
+		TenantID:      tenantID,
+		StartDate:     toPgDate(startDate),
+		EndDate:       toPgDate(endDate),
+		RestaurantIds: restaurantIDs,
 	})
 }
 
 // GetPeakHours returns peak hour data.
-func (s *Service) GetPeakHours(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) ([]sqlc.GetPeakHoursRow, error) {
+func (s *Service) GetPeakHours(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, startDate, endDate time.Time) ([]sqlc.GetPeakHoursRow, error) {
 	return s.q.GetPeakHours(ctx, sqlc.GetPeakHoursParams{
-		TenantID:  tenantID,
-		StartDate: toPgDate(startDate),
-		EndDate:   toPgDate(endDate),
+		TenantID:      tenantID,
+		StartDate:     toPgDate(startDate),
+		EndDate:       toPgDate(endDate),
+		RestaurantIds: restaurantIDs,
 	})
 }
 
 // GetOrderBreakdown returns order status breakdown.
-func (s *Service) GetOrderBreakdown(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time) ([]sqlc.GetOrderStatusBreakdownRow, error) {
+func (s *Service) GetOrderBreakdown(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, startDate, endDate time.Time) ([]sqlc.GetOrderStatusBreakdownRow, error) {
 	return s.q.GetOrderStatusBreakdown(ctx, sqlc.GetOrderStatusBreakdownParams{
-		TenantID:  tenantID,
-		StartDate: toPgDate(startDate),
-		EndDate:   toPgDate(endDate),
+		TenantID:      tenantID,
+		StartDate:     toPgDate(startDate),
+		EndDate:       toPgDate(endDate),
+		RestaurantIds: restaurantIDs,
 	})
 }
 
@@ -106,12 +120,13 @@ func (s *Service) GetRiderAnalytics(ctx context.Context, tenantID uuid.UUID, sta
 }
 
 // GetTopProducts returns top selling products.
-func (s *Service) GetTopProducts(ctx context.Context, tenantID uuid.UUID, startDate, endDate time.Time, limit int) ([]sqlc.GetTopProductsRow, error) {
+func (s *Service) GetTopProducts(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, startDate, endDate time.Time, limit int) ([]sqlc.GetTopProductsRow, error) {
 	return s.q.GetTopProducts(ctx, sqlc.GetTopProductsParams{
-		TenantID:  tenantID,
-		StartDate: startDate,
-		EndDate:   endDate,
-		Limit:     int32(limit),
+		TenantID:      tenantID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		RestaurantIds: restaurantIDs,
+		Limit:         int32(limit),
 	})
 }
 
@@ -163,12 +163,17 @@ func (h *Handler) ReorderCategories(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, apperror.NotFound("tenant"))
 		return
 	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
 	var req []ReorderCategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
 		return
 	}
-	if err := h.svc.ReorderCategories(r.Context(), t.ID, req); err != nil {
+	if err := h.svc.ReorderCategories(r.Context(), t.ID, restaurantID, req); err != nil {
 		respond.Error(w, toAppError(err))
 		return
 	}
@@ -109,8 +109,21 @@ type ReorderCategoryRequest struct {
 	SortOrder int32     `json:"sort_order"`
 }
 
-// ReorderCategories updates sort orders for multiple categories.
-func (s *Service) ReorderCategories(ctx context.Context, tenantID uuid.UUID, items []ReorderCategoryRequest) error {
+// ReorderCategories updates sort orders for multiple categories of a
+// restaurant. Every category must belong to that restaurant.
+func (s *Service) ReorderCategories(ctx context.Context, tenantID, restaurantID uuid.UUID, items []ReorderCategoryRequest) error {
+	for _, item := range items {
+		cat, err := s.repo.GetCategoryByID(ctx, item.ID, tenantID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.NotFound("category")
+		}
+		if err != nil {
+			return apperror.Internal("get category", err)
+		}
+		if !cat.RestaurantID.Valid || uuid.UUID(cat.RestaurantID.Bytes) != restaurantID {
+			return apperror.BadRequest("category does not belong to this restaurant")
+		}
+	}
 	for _, item := range items {
 		if err := s.repo.UpdateCategorySortOrder(ctx, item.ID, item.SortOrder, tenantID); err != nil {
 			return err
@@ -0,0 +1,333 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/modules/auth"
+	"github.com/munchies/platform/backend/internal/modules/tenant"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+)
+
+// Handler handles report export HTTP requests.
+type Handler struct {
+	svc *Service
+}
+
+// NewHandler creates a new export handler.
+func NewHandler(svc *Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+type createExportRequest struct {
+	ReportType   string     `json:"report_type"`
+	Format       string     `json:"format"`
+	StartDate    string     `json:"start_date"`
+	EndDate      string     `json:"end_date"`
+	RestaurantID *uuid.UUID `json:"restaurant_id"`
+	Email        bool       `json:"email"`
+}
+
+// CreateExport handles POST /partner/exports
+// The export is generated in the background; poll GET /partner/exports/{id}
+// until it is completed, then download it.
+func (h *Handler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	var req createExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	start, err := timeutil.ParseBD("2006-01-02", req.StartDate)
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("start_date must be YYYY-MM-DD"))
+		return
+	}
+	end, err := timeutil.ParseBD("2006-01-02", req.EndDate)
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("end_date must be YYYY-MM-DD"))
+		return
+	}
+	if req.Format == "" {
+		req.Format = FormatCSV
+	}
+
+	exp, err := h.svc.Request(r.Context(), user, t.ID, RequestInput{
+		ReportType:   req.ReportType,
+		Format:       req.Format,
+		StartDate:    start,
+		EndDate:      end,
+		RestaurantID: req.RestaurantID,
+		Email:        req.Email,
+	})
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusAccepted, exp)
+}
+
+// ListExports handles GET /partner/exports
+func (h *Handler) ListExports(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	exports, err := h.svc.List(r.Context(), user, t.ID, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"exports": exports,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetExport handles GET /partner/exports/{id}
+func (h *Handler) GetExport(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid export ID"))
+		return
+	}
+
+	exp, err := h.svc.Get(r.Context(), user, t.ID, id)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, exp)
+}
+
+// DownloadExport handles GET /partner/exports/{id}/download
+func (h *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid export ID"))
+		return
+	}
+
+	exp, f, err := h.svc.Open(r.Context(), user, t.ID, id)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", exp.ContentType.String)
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+exp.FileName.String+"\"")
+	if exp.SizeBytes != nil {
+		w.Header().Set("Content-Length", strconv.FormatInt(*exp.SizeBytes, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		log.Warn().Err(err).Str("export_id", exp.ID.String()).Msg("export download interrupted")
+	}
+}
+
+// ---------- Scheduled reports ----------
+
+type subscriptionRequest struct {
+	ReportType    string      `json:"report_type"`
+	Frequency     string      `json:"frequency"`
+	SendHour      *int        `json:"send_hour"`
+	SendWeekday   *int        `json:"send_weekday"`
+	Recipients    []string    `json:"recipients"`
+	RestaurantIDs []uuid.UUID `json:"restaurant_ids"`
+	IsActive      *bool       `json:"is_active"`
+}
+
+func (req subscriptionRequest) input() SubscriptionInput {
+	return SubscriptionInput{
+		ReportType:    req.ReportType,
+		Frequency:     req.Frequency,
+		SendHour:      req.SendHour,
+		SendWeekday:   req.SendWeekday,
+		Recipients:    req.Recipients,
+		RestaurantIDs: req.RestaurantIDs,
+		IsActive:      req.IsActive,
+	}
+}
+
+// CreateSubscription handles POST /partner/report-schedules
+func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	var req subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	sub, err := h.svc.CreateSubscription(r.Context(), user, t, req.input())
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, sub)
+}
+
+// ListSubscriptions handles GET /partner/report-schedules
+func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	subs, err := h.svc.ListSubscriptions(r.Context(), user, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, subs)
+}
+
+// UpdateSubscription handles PUT /partner/report-schedules/{id}
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid schedule ID"))
+		return
+	}
+
+	var req subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	sub, err := h.svc.UpdateSubscription(r.Context(), user, t, id, req.input())
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, sub)
+}
+
+// DeleteSubscription handles DELETE /partner/report-schedules/{id}
+func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid schedule ID"))
+		return
+	}
+
+	if err := h.svc.DeleteSubscription(r.Context(), user, t.ID, id); err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /partner/report-schedules/{id}/deliveries
+func (h *Handler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	user := auth.UserFromContext(r.Context())
+	t := tenant.FromContext(r.Context())
+	if user == nil || t == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid schedule ID"))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	deliveries, err := h.svc.ListDeliveries(r.Context(), user, t.ID, id, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"deliveries": deliveries,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+func parsePagination(r *http.Request) (limit, offset int32) {
+	limit = 20
+	offset = 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = int32(n)
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = int32(n)
+		}
+	}
+	return
+}
+
+func toAppError(err error) *apperror.AppError {
+	if e, ok := err.(*apperror.AppError); ok {
+		return e
+	}
+	return apperror.Internal("unexpected error", err)
+}
@@ -0,0 +1,307 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/munchies/platform/backend/internal/pkg/xlsx"
+	"github.com/shopspring/decimal"
+)
+
+// orderBatch is how many orders are read per query when exporting the order
+// list, so long ranges never load the whole table.
+const orderBatch = 1000
+
+// table writes rows in one of the export formats.
+type table interface {
+	Header(titles ...string) error
+	Row(cells ...xlsx.Cell) error
+	Close() error
+}
+
+func newTable(format string, w io.Writer, sheet string) (table, error) {
+	if format == FormatXLSX {
+		xw, err := xlsx.NewWriter(w, sheet)
+		if err != nil {
+			return nil, err
+		}
+		return xlsxTable{xw}, nil
+	}
+	return &csvTable{w: csv.NewWriter(w)}, nil
+}
+
+type xlsxTable struct{ w *xlsx.Writer }
+
+func (t xlsxTable) Header(titles ...string) error { return t.w.WriteHeader(titles) }
+func (t xlsxTable) Row(cells ...xlsx.Cell) error  { return t.w.WriteRow(cells) }
+func (t xlsxTable) Close() error                  { return t.w.Close() }
+
+type csvTable struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (t *csvTable) Header(titles ...string) error { return t.w.Write(titles) }
+
+func (t *csvTable) Row(cells ...xlsx.Cell) error {
+	t.record = t.record[:0]
+	for _, c := range cells {
+		t.record = append(t.record, c.Value)
+	}
+	return t.w.Write(t.record)
+}
+
+func (t *csvTable) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// write generates the export into w and returns the number of data rows.
+func (s *Service) write(ctx context.Context, exp sqlc.ReportExport, w io.Writer) (int, error) {
+	t, err := newTable(exp.Format, w, exp.ReportType)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	switch exp.ReportType {
+	case ReportSales:
+		n, err = s.writeSales(ctx, exp, t)
+	case ReportOrders:
+		n, err = s.writeOrders(ctx, exp, t)
+	case ReportRiderAnalytics:
+		n, err = s.writeRiderAnalytics(ctx, exp, t)
+	case ReportInvoices:
+		n, err = s.writeInvoices(ctx, exp, t)
+	case ReportRefunds:
+		n, err = s.writeRefunds(ctx, exp, t)
+	default:
+		err = fmt.Errorf("unknown report type %q", exp.ReportType)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, t.Close()
+}
+
+func (s *Service) writeSales(ctx context.Context, exp sqlc.ReportExport, t table) (int, error) {
+	// Restaurant-scoped exports read the pickups, since an order's totals can
+	// span several restaurants.
+	if exp.RestaurantIds != nil {
+		rows, err := s.q.ExportRestaurantSalesByDay(ctx, sqlc.ExportRestaurantSalesByDayParams{
+			TenantID:      exp.TenantID,
+			RestaurantIds: exp.RestaurantIds,
+			StartDate:     exp.StartDate,
+			EndDate:       exp.EndDate,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("restaurant sales: %w", err)
+		}
+		if err := t.Header("Date", "Orders", "Gross Sales", "Item Discounts", "VAT", "Total Sales", "Commission"); err != nil {
+			return 0, err
+		}
+		for _, r := range rows {
+			if err := t.Row(date(r.OrderDate), integer(r.OrderCount), money(r.GrossSales), money(r.ItemDiscounts),
+				money(r.VatTotal), money(r.TotalSales), money(r.Commission)); err != nil {
+				return 0, err
+			}
+		}
+		return len(rows), nil
+	}
+
+	rows, err := s.q.GetSalesReport(ctx, sqlc.GetSalesReportParams{
+		TenantID:  exp.TenantID,
+		StartDate: exp.StartDate,
+		EndDate:   exp.EndDate,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("sales report: %w", err)
+	}
+	if err := t.Header("Date", "Orders", "Gross Sales", "Item Discounts", "Promo Discounts", "Net Sales", "VAT",
+		"Commission", "Total Revenue", "Avg Order Value", "Avg Delivery Time (min)"); err != nil {
+		return 0, err
+	}
+	for _, r := range rows {
+		if err := t.Row(date(r.OrderDate), integer(r.OrderCount), money(r.GrossSales), money(r.ItemDiscounts),
+			money(r.PromoDiscounts), money(r.NetSales), money(r.VatTotal), money(r.Commission), money(r.TotalRevenue),
+			money(r.AvgOrderValue), minutes(r.AvgDeliveryTimeS)); err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
+
+func (s *Service) writeOrders(ctx context.Context, exp sqlc.ReportExport, t table) (int, error) {
+	if err := t.Header("Order Number", "Placed At", "Status", "Customer", "Restaurants", "Area", "Platform",
+		"Payment Method", "Payment Status", "Subtotal", "Item Discounts", "Promo Discounts", "Promo Code", "VAT",
+		"Delivery Charge", "Service Fee", "Total", "Delivered At", "Cancelled At"); err != nil {
+		return 0, err
+	}
+
+	from, to := dateRange(exp)
+	params := sqlc.ExportOrdersParams{
+		TenantID:       exp.TenantID,
+		FromTime:       from,
+		ToTime:         to,
+		RestaurantIds:  exp.RestaurantIds,
+		AfterCreatedAt: from.Add(-time.Microsecond),
+		AfterID:        uuid.Nil,
+		Limit:          orderBatch,
+	}
+	total := 0
+	for {
+		rows, err := s.q.ExportOrders(ctx, params)
+		if err != nil {
+			return 0, fmt.Errorf("orders batch: %w", err)
+		}
+		for _, r := range rows {
+			if err := t.Row(text(r.OrderNumber), timestamp(r.CreatedAt), text(string(r.Status)), text(r.CustomerName),
+				text(r.RestaurantNames), text(r.DeliveryArea), text(string(r.Platform)), text(string(r.PaymentMethod)),
+				text(string(r.PaymentStatus)), money(r.Subtotal), money(r.ItemDiscountTotal), money(r.PromoDiscountTotal),
+				nullText(r.PromoCode), money(r.VatTotal), money(r.DeliveryCharge), money(r.ServiceFee), money(r.TotalAmount),
+				optionalTimestamp(r.DeliveredAt), optionalTimestamp(r.CancelledAt)); err != nil {
+				return 0, err
+			}
+		}
+		total += len(rows)
+		if len(rows) < orderBatch {
+			return total, nil
+		}
+		last := rows[len(rows)-1]
+		params.AfterCreatedAt, params.AfterID = last.CreatedAt, last.ID
+	}
+}
+
+func (s *Service) writeRiderAnalytics(ctx context.Context, exp sqlc.ReportExport, t table) (int, error) {
+	rows, err := s.q.ExportRiderAnalytics(ctx, sqlc.ExportRiderAnalyticsParams{
+		TenantID:  exp.TenantID,
+		StartDate: exp.StartDate,
+		EndDate:   exp.EndDate,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rider analytics: %w", err)
+	}
+	if err := t.Header("Rider", "Phone", "Orders", "Delivered", "Avg Delivery Time (min)",
+		"Avg Pickup to Delivery (min)", "Delivery Revenue"); err != nil {
+		return 0, err
+	}
+	for _, r := range rows {
+		if err := t.Row(text(r.RiderName), nullText(r.RiderPhone), integer(r.TotalOrders), integer(r.DeliveredOrders),
+			minutes(r.AvgDeliveryTimeS), minutes(r.AvgPickupToDeliveryS), money(r.TotalDeliveryRevenue)); err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
+
+func (s *Service) writeInvoices(ctx context.Context, exp sqlc.ReportExport, t table) (int, error) {
+	rows, err := s.q.ExportInvoices(ctx, sqlc.ExportInvoicesParams{
+		TenantID:  exp.TenantID,
+		StartDate: exp.StartDate,
+		EndDate:   exp.EndDate,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("invoices: %w", err)
+	}
+	if err := t.Header("Invoice Number", "Restaurant", "Period Start", "Period End", "Status", "Orders", "Delivered",
+		"Gross Sales", "Item Discounts", "Vendor Promo Discounts", "Net Sales", "VAT Collected", "Commission Rate",
+		"Commission", "Penalties", "Adjustments", "Net Payable", "Finalized At", "Paid At"); err != nil {
+		return 0, err
+	}
+	for _, r := range rows {
+		if err := t.Row(text(r.InvoiceNumber), text(r.RestaurantName), date(r.PeriodStart), date(r.PeriodEnd),
+			text(string(r.Status)), integer(r.TotalOrders), integer(r.DeliveredOrders), money(r.GrossSales),
+			money(r.ItemDiscounts), money(r.VendorPromoDiscounts), money(r.NetSales), money(r.VatCollected),
+			money(r.CommissionRate), money(r.CommissionAmount), money(r.PenaltyAmount), money(r.AdjustmentAmount),
+			money(r.NetPayable), optionalTimestamp(r.FinalizedAt), optionalTimestamp(r.PaidAt)); err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
+
+func (s *Service) writeRefunds(ctx context.Context, exp sqlc.ReportExport, t table) (int, error) {
+	from, to := dateRange(exp)
+	rows, err := s.q.ExportRefunds(ctx, sqlc.ExportRefundsParams{TenantID: exp.TenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return 0, fmt.Errorf("refunds: %w", err)
+	}
+	if err := t.Header("Order Number", "Amount", "Reason", "Status", "Payment Method", "Gateway Refund ID",
+		"Requested At", "Approved At", "Processed At"); err != nil {
+		return 0, err
+	}
+	for _, r := range rows {
+		if err := t.Row(text(r.OrderNumber), money(r.Amount), text(r.Reason), text(string(r.Status)),
+			text(string(r.PaymentMethod)), nullText(r.GatewayRefundID), timestamp(r.CreatedAt),
+			optionalTimestamp(r.ApprovedAt), optionalTimestamp(r.ProcessedAt)); err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
+
+// dateRange converts the export's inclusive dates into a half-open range
+// of Dhaka days.
+func dateRange(exp sqlc.ReportExport) (time.Time, time.Time) {
+	from := time.Date(exp.StartDate.Time.Year(), exp.StartDate.Time.Month(), exp.StartDate.Time.Day(), 0, 0, 0, 0, timeutil.BangladeshLocation)
+	to := time.Date(exp.EndDate.Time.Year(), exp.EndDate.Time.Month(), exp.EndDate.Time.Day(), 0, 0, 0, 0, timeutil.BangladeshLocation)
+	return from, to.AddDate(0, 0, 1)
+}
+
+func text(s string) xlsx.Cell { return xlsx.String(s) }
+
+func nullText(s sql.NullString) xlsx.Cell { return xlsx.String(s.String) }
+
+func integer(n int32) xlsx.Cell { return xlsx.Number(strconv.Itoa(int(n))) }
+
+func minutes(seconds int32) xlsx.Cell {
+	return xlsx.Number(decimal.NewFromInt32(seconds).Div(decimal.NewFromInt(60)).StringFixed(1))
+}
+
+func money(n pgtype.Numeric) xlsx.Cell {
+	if !n.Valid {
+		return xlsx.Number("")
+	}
+	return xlsx.Number(numericToDecimal(n).StringFixed(2))
+}
+
+func date(d pgtype.Date) xlsx.Cell {
+	if !d.Valid {
+		return xlsx.String("")
+	}
+	return xlsx.String(d.Time.Format("2006-01-02"))
+}
+
+func timestamp(t time.Time) xlsx.Cell {
+	return xlsx.String(timeutil.ToBD(t).Format("2006-01-02 15:04"))
+}
+
+func optionalTimestamp(t pgtype.Timestamptz) xlsx.Cell {
+	if !t.Valid {
+		return xlsx.String("")
+	}
+	return timestamp(t.Time)
+}
+
+func pgDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: true}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
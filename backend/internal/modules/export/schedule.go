@@ -0,0 +1,571 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/munchies/platform/backend/internal/platform/email"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// Scheduled report types.
+const (
+	ScheduledSalesSummary = "sales_summary"
+	ScheduledPnL          = "pnl"
+)
+
+// Schedule frequencies.
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// Delivery log states.
+const (
+	DeliverySent   = "sent"
+	DeliveryFailed = "failed"
+)
+
+const (
+	maxRecipients       = 10
+	subscriptionsPerRun = 50
+	topProductsInReport = 5
+)
+
+// SubscriptionInput describes a scheduled report. SendHour defaults to 23
+// and SendWeekday, used by weekly reports, to Sunday (0).
+type SubscriptionInput struct {
+	ReportType    string
+	Frequency     string
+	SendHour      *int
+	SendWeekday   *int
+	Recipients    []string
+	RestaurantIDs []uuid.UUID
+	IsActive      *bool
+}
+
+func canSubscribe(role sqlc.UserRole) bool {
+	return access.IsTenantWide(role) || role == sqlc.UserRoleRestaurantManager
+}
+
+// normalizeSubscription validates the input, applies defaults and returns
+// the recipients trimmed, lower-cased and de-duplicated.
+func normalizeSubscription(in *SubscriptionInput) error {
+	if in.ReportType != ScheduledSalesSummary && in.ReportType != ScheduledPnL {
+		return apperror.BadRequest("report_type must be sales_summary or pnl")
+	}
+	if in.Frequency != FrequencyDaily && in.Frequency != FrequencyWeekly {
+		return apperror.BadRequest("frequency must be daily or weekly")
+	}
+	if in.SendHour == nil {
+		h := 23
+		in.SendHour = &h
+	}
+	if *in.SendHour < 0 || *in.SendHour > 23 {
+		return apperror.BadRequest("send_hour must be between 0 and 23")
+	}
+	if in.SendWeekday == nil {
+		d := int(time.Sunday)
+		in.SendWeekday = &d
+	}
+	if *in.SendWeekday < 0 || *in.SendWeekday > 6 {
+		return apperror.BadRequest("send_weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if in.IsActive == nil {
+		active := true
+		in.IsActive = &active
+	}
+
+	var recipients []string
+	for _, r := range in.Recipients {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if r == "" || slices.Contains(recipients, r) {
+			continue
+		}
+		if addr, err := mail.ParseAddress(r); err != nil || addr.Address != r {
+			return apperror.BadRequest("invalid recipient email: " + r)
+		}
+		recipients = append(recipients, r)
+	}
+	if len(recipients) == 0 {
+		return apperror.BadRequest("at least one recipient is required")
+	}
+	if len(recipients) > maxRecipients {
+		return apperror.BadRequest(fmt.Sprintf("a report can go to at most %d recipients", maxRecipients))
+	}
+	in.Recipients = recipients
+	return nil
+}
+
+// nextRun returns the first scheduled send strictly after after, in loc.
+func nextRun(frequency string, hour, weekday int, loc *time.Location, after time.Time) time.Time {
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, loc)
+	step := 1
+	if frequency == FrequencyWeekly {
+		step = 7
+		next = next.AddDate(0, 0, (weekday-int(next.Weekday())+7)%7)
+	}
+	if !next.After(after) {
+		next = next.AddDate(0, 0, step)
+	}
+	return next
+}
+
+// reportPeriod returns the window a send at runAt covers: the day or week
+// leading up to it, so consecutive reports neither overlap nor leave gaps.
+func reportPeriod(frequency string, runAt time.Time, loc *time.Location) (time.Time, time.Time) {
+	days := 1
+	if frequency == FrequencyWeekly {
+		days = 7
+	}
+	return runAt.In(loc).AddDate(0, 0, -days), runAt
+}
+
+// tenantLocation loads a tenant's timezone, falling back to Dhaka.
+func tenantLocation(name string) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc
+	}
+	return timeutil.BangladeshLocation
+}
+
+// CreateSubscription schedules a report for the tenant. Restaurant managers
+// can only cover the restaurants they are assigned to.
+func (s *Service) CreateSubscription(ctx context.Context, user *sqlc.User, t *sqlc.Tenant, in SubscriptionInput) (sqlc.ReportSubscription, error) {
+	if !canSubscribe(user.Role) {
+		return sqlc.ReportSubscription{}, apperror.Forbidden("your role cannot schedule reports")
+	}
+	if err := normalizeSubscription(&in); err != nil {
+		return sqlc.ReportSubscription{}, err
+	}
+	restaurants, err := s.restaurantScope(ctx, t.ID, in.RestaurantIDs)
+	if err != nil {
+		return sqlc.ReportSubscription{}, err
+	}
+
+	sub, err := s.q.CreateReportSubscription(ctx, sqlc.CreateReportSubscriptionParams{
+		TenantID:      t.ID,
+		CreatedBy:     user.ID,
+		ReportType:    in.ReportType,
+		Frequency:     in.Frequency,
+		SendHour:      int16(*in.SendHour),
+		SendWeekday:   int16(*in.SendWeekday),
+		Recipients:    in.Recipients,
+		RestaurantIds: restaurants,
+		NextRunAt:     nextRun(in.Frequency, *in.SendHour, *in.SendWeekday, tenantLocation(t.Timezone), time.Now()),
+	})
+	if err != nil {
+		return sqlc.ReportSubscription{}, apperror.Internal("create report subscription", err)
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns the tenant's scheduled reports. Restaurant
+// managers see the ones they created.
+func (s *Service) ListSubscriptions(ctx context.Context, user *sqlc.User, tenantID uuid.UUID) ([]sqlc.ReportSubscription, error) {
+	if !canSubscribe(user.Role) {
+		return nil, apperror.Forbidden("your role cannot schedule reports")
+	}
+	creator := pgtype.UUID{}
+	if !access.IsTenantWide(user.Role) {
+		creator = pgtype.UUID{Bytes: user.ID, Valid: true}
+	}
+	subs, err := s.q.ListReportSubscriptions(ctx, sqlc.ListReportSubscriptionsParams{TenantID: tenantID, CreatedBy: creator})
+	if err != nil {
+		return nil, apperror.Internal("list report subscriptions", err)
+	}
+	return subs, nil
+}
+
+func (s *Service) getSubscription(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) (sqlc.ReportSubscription, error) {
+	if !canSubscribe(user.Role) {
+		return sqlc.ReportSubscription{}, apperror.Forbidden("your role cannot schedule reports")
+	}
+	sub, err := s.q.GetReportSubscription(ctx, sqlc.GetReportSubscriptionParams{ID: id, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !access.IsTenantWide(user.Role) && sub.CreatedBy != user.ID) {
+		return sqlc.ReportSubscription{}, apperror.NotFound("report subscription")
+	}
+	if err != nil {
+		return sqlc.ReportSubscription{}, apperror.Internal("get report subscription", err)
+	}
+	return sub, nil
+}
+
+// UpdateSubscription replaces a scheduled report's settings and reschedules
+// its next send.
+func (s *Service) UpdateSubscription(ctx context.Context, user *sqlc.User, t *sqlc.Tenant, id uuid.UUID, in SubscriptionInput) (sqlc.ReportSubscription, error) {
+	if _, err := s.getSubscription(ctx, user, t.ID, id); err != nil {
+		return sqlc.ReportSubscription{}, err
+	}
+	if err := normalizeSubscription(&in); err != nil {
+		return sqlc.ReportSubscription{}, err
+	}
+	restaurants, err := s.restaurantScope(ctx, t.ID, in.RestaurantIDs)
+	if err != nil {
+		return sqlc.ReportSubscription{}, err
+	}
+
+	sub, err := s.q.UpdateReportSubscription(ctx, sqlc.UpdateReportSubscriptionParams{
+		ReportType:    in.ReportType,
+		Frequency:     in.Frequency,
+		SendHour:      int16(*in.SendHour),
+		SendWeekday:   int16(*in.SendWeekday),
+		Recipients:    in.Recipients,
+		RestaurantIds: restaurants,
+		IsActive:      *in.IsActive,
+		NextRunAt:     nextRun(in.Frequency, *in.SendHour, *in.SendWeekday, tenantLocation(t.Timezone), time.Now()),
+		ID:            id,
+		TenantID:      t.ID,
+	})
+	if err != nil {
+		return sqlc.ReportSubscription{}, apperror.Internal("update report subscription", err)
+	}
+	return sub, nil
+}
+
+// DeleteSubscription removes a scheduled report and its delivery log.
+func (s *Service) DeleteSubscription(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) error {
+	if _, err := s.getSubscription(ctx, user, tenantID, id); err != nil {
+		return err
+	}
+	if _, err := s.q.DeleteReportSubscription(ctx, sqlc.DeleteReportSubscriptionParams{ID: id, TenantID: tenantID}); err != nil {
+		return apperror.Internal("delete report subscription", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log of a scheduled report.
+func (s *Service) ListDeliveries(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID, limit, offset int32) ([]sqlc.ReportDelivery, error) {
+	if _, err := s.getSubscription(ctx, user, tenantID, id); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.q.ListReportDeliveries(ctx, sqlc.ListReportDeliveriesParams{
+		TenantID:       tenantID,
+		SubscriptionID: id,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list report deliveries", err)
+	}
+	return deliveries, nil
+}
+
+// SendScheduledReports emails every report whose send time has passed.
+// Registered with the background worker. A subscription is moved to its
+// next run before sending, so a crash loses at most one report rather than
+// mailing it twice.
+func (s *Service) SendScheduledReports(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.q.ListDueReportSubscriptions(ctx, sqlc.ListDueReportSubscriptionsParams{Now: now, Limit: subscriptionsPerRun})
+	if err != nil {
+		return fmt.Errorf("list due report subscriptions: %w", err)
+	}
+
+	for _, sub := range due {
+		loc := tenantLocation(sub.TenantTimezone)
+		claimed, err := s.q.AdvanceReportSubscription(ctx, sqlc.AdvanceReportSubscriptionParams{
+			NextRunAt: nextRun(sub.Frequency, int(sub.SendHour), int(sub.SendWeekday), loc, now),
+			ID:        sub.ID,
+			DueAt:     sub.NextRunAt,
+		})
+		if err != nil {
+			log.Error().Err(err).Str("subscription_id", sub.ID.String()).Msg("advance report subscription failed")
+			continue
+		}
+		if claimed == 0 {
+			continue
+		}
+		if err := s.sendScheduledReport(ctx, sub, loc); err != nil {
+			log.Error().Err(err).Str("subscription_id", sub.ID.String()).Msg("scheduled report failed")
+		}
+	}
+	return nil
+}
+
+func (s *Service) sendScheduledReport(ctx context.Context, sub sqlc.ListDueReportSubscriptionsRow, loc *time.Location) error {
+	from, to := reportPeriod(sub.Frequency, sub.NextRunAt, loc)
+	report, err := s.buildScheduledReport(ctx, sub, from, to)
+	if err != nil {
+		// Still log the run so the failure shows up for every recipient.
+		for _, r := range sub.Recipients {
+			s.logDelivery(ctx, sub, from, to, r, err)
+		}
+		return err
+	}
+	report.Period = formatPeriod(sub.Frequency, from, to, loc)
+	report.TenantName = sub.TenantName
+
+	body, err := email.RenderTemplate("scheduled_report", map[string]interface{}{
+		"Title":      report.Title,
+		"TenantName": report.TenantName,
+		"Period":     report.Period,
+		"Scope":      report.Scope,
+		"Sections":   report.Sections,
+	})
+	if err != nil {
+		return fmt.Errorf("render report: %w", err)
+	}
+	subject := report.Title + " — " + report.Period
+	for _, r := range sub.Recipients {
+		var sendErr error
+		if s.mail != nil {
+			sendErr = s.mail.SendEmail(ctx, r, subject, body)
+		}
+		s.logDelivery(ctx, sub, from, to, r, sendErr)
+	}
+	return nil
+}
+
+func (s *Service) logDelivery(ctx context.Context, sub sqlc.ListDueReportSubscriptionsRow, from, to time.Time, recipient string, sendErr error) {
+	status, msg := DeliverySent, ""
+	if sendErr != nil {
+		status, msg = DeliveryFailed, sendErr.Error()
+	}
+	if err := s.q.CreateReportDelivery(ctx, sqlc.CreateReportDeliveryParams{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.TenantID,
+		ReportType:     sub.ReportType,
+		PeriodStart:    from,
+		PeriodEnd:      to,
+		Recipient:      recipient,
+		Status:         status,
+		Error:          nullString(msg),
+	}); err != nil {
+		log.Error().Err(err).Str("subscription_id", sub.ID.String()).Msg("log report delivery failed")
+	}
+}
+
+// scheduledReport is the content of one report email.
+type scheduledReport struct {
+	Title      string
+	TenantName string
+	Period     string
+	Scope      string
+	Sections   []reportSection
+}
+
+type reportSection struct {
+	Heading string
+	Lines   []reportLine
+}
+
+type reportLine struct {
+	Label string
+	Value string
+	Total bool
+}
+
+func (s *Service) buildScheduledReport(ctx context.Context, sub sqlc.ListDueReportSubscriptionsRow, from, to time.Time) (scheduledReport, error) {
+	summary, err := s.q.GetScheduledSalesSummary(ctx, sqlc.GetScheduledSalesSummaryParams{
+		TenantID:      sub.TenantID,
+		FromTime:      from,
+		ToTime:        to,
+		RestaurantIds: sub.RestaurantIds,
+	})
+	if err != nil {
+		return scheduledReport{}, fmt.Errorf("sales summary: %w", err)
+	}
+
+	report := scheduledReport{}
+	if sub.RestaurantIds != nil {
+		if report.Scope, err = s.restaurantNames(ctx, sub.TenantID, sub.RestaurantIds); err != nil {
+			return scheduledReport{}, err
+		}
+	}
+	money := func(n pgtype.Numeric) string { return formatMoney(numericToDecimal(n), sub.TenantCurrency) }
+
+	if sub.ReportType == ScheduledSalesSummary {
+		report.Title = "Daily summary"
+		if sub.Frequency == FrequencyWeekly {
+			report.Title = "Weekly summary"
+		}
+		aov := decimal.Zero
+		if summary.DeliveredOrders > 0 {
+			aov = numericToDecimal(summary.TotalSales).Div(decimal.NewFromInt32(summary.DeliveredOrders))
+		}
+		report.Sections = append(report.Sections,
+			reportSection{Heading: "Orders", Lines: []reportLine{
+				{Label: "Orders placed", Value: fmt.Sprint(summary.OrderCount)},
+				{Label: "Delivered", Value: fmt.Sprint(summary.DeliveredOrders)},
+				{Label: "Cancelled or rejected", Value: fmt.Sprint(summary.CancelledOrders)},
+			}},
+			reportSection{Heading: "Sales", Lines: []reportLine{
+				{Label: "Gross sales", Value: money(summary.GrossSales)},
+				{Label: "Item discounts", Value: money(summary.ItemDiscounts)},
+				{Label: "VAT", Value: money(summary.VatTotal)},
+				{Label: "Total sales", Value: money(summary.TotalSales), Total: true},
+				{Label: "Average order value", Value: formatMoney(aov, sub.TenantCurrency)},
+				{Label: "Commission", Value: money(summary.Commission)},
+			}},
+		)
+
+		top, err := s.q.GetScheduledTopProducts(ctx, sqlc.GetScheduledTopProductsParams{
+			TenantID:      sub.TenantID,
+			FromTime:      from,
+			ToTime:        to,
+			RestaurantIds: sub.RestaurantIds,
+			Limit:         topProductsInReport,
+		})
+		if err != nil {
+			return scheduledReport{}, fmt.Errorf("top products: %w", err)
+		}
+		if len(top) > 0 {
+			section := reportSection{Heading: "Top sellers"}
+			for _, p := range top {
+				section.Lines = append(section.Lines, reportLine{
+					Label: fmt.Sprintf("%s × %d", p.ProductName, p.TotalQuantity),
+					Value: money(p.TotalRevenue),
+				})
+			}
+			report.Sections = append(report.Sections, section)
+		}
+		return report, nil
+	}
+
+	report.Title = "Weekly P&L"
+	if sub.Frequency == FrequencyDaily {
+		report.Title = "Daily P&L"
+	}
+	// A restaurant's P&L is its share of sales less the commission it owes;
+	// order-level fees, refunds and rider pay belong to the tenant.
+	if sub.RestaurantIds != nil {
+		sales := numericToDecimal(summary.TotalSales)
+		commission := numericToDecimal(summary.Commission)
+		fm := func(d decimal.Decimal) string { return formatMoney(d, sub.TenantCurrency) }
+		report.Sections = append(report.Sections, reportSection{Heading: "Profit & loss", Lines: []reportLine{
+			{Label: "Gross sales", Value: money(summary.GrossSales)},
+			{Label: "Item discounts", Value: fm(numericToDecimal(summary.ItemDiscounts).Neg())},
+			{Label: "VAT collected", Value: money(summary.VatTotal)},
+			{Label: "Total sales", Value: money(summary.TotalSales), Total: true},
+			{Label: "Commission", Value: fm(commission.Neg())},
+			{Label: "Net earnings", Value: fm(sales.Sub(commission)), Total: true},
+		}})
+		return report, nil
+	}
+
+	charges, err := s.q.GetScheduledOrderCharges(ctx, sqlc.GetScheduledOrderChargesParams{TenantID: sub.TenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return scheduledReport{}, fmt.Errorf("order charges: %w", err)
+	}
+	refunds, err := s.q.GetProcessedRefundTotal(ctx, sqlc.GetProcessedRefundTotalParams{TenantID: sub.TenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return scheduledReport{}, fmt.Errorf("refund total: %w", err)
+	}
+	riderPay, err := s.q.GetRiderEarningTotal(ctx, sqlc.GetRiderEarningTotalParams{TenantID: sub.TenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return scheduledReport{}, fmt.Errorf("rider earnings: %w", err)
+	}
+
+	pnl := computePnL(summary, charges, numericToDecimal(refunds), numericToDecimal(riderPay))
+	fm := func(d decimal.Decimal) string { return formatMoney(d, sub.TenantCurrency) }
+	report.Sections = append(report.Sections,
+		reportSection{Heading: "Sales", Lines: []reportLine{
+			{Label: "Delivered orders", Value: fmt.Sprint(summary.DeliveredOrders)},
+			{Label: "Restaurant sales", Value: money(summary.TotalSales)},
+			{Label: "Collected from customers", Value: money(charges.TotalCollected)},
+		}},
+		reportSection{Heading: "Income", Lines: []reportLine{
+			{Label: "Commission", Value: money(summary.Commission)},
+			{Label: "Delivery charges", Value: money(charges.DeliveryCharges)},
+			{Label: "Service fees", Value: money(charges.ServiceFees)},
+			{Label: "Total income", Value: fm(pnl.Income), Total: true},
+		}},
+		reportSection{Heading: "Costs", Lines: []reportLine{
+			{Label: "Promo discounts", Value: money(charges.PromoDiscounts)},
+			{Label: "Refunds processed", Value: fm(pnl.Refunds)},
+			{Label: "Rider earnings", Value: fm(pnl.RiderPay)},
+			{Label: "Total costs", Value: fm(pnl.Costs), Total: true},
+		}},
+		reportSection{Heading: "Result", Lines: []reportLine{
+			{Label: "Net profit", Value: fm(pnl.Net), Total: true},
+		}},
+	)
+	return report, nil
+}
+
+// pnl is a tenant's profit and loss for a period.
+type pnl struct {
+	Income   decimal.Decimal
+	Refunds  decimal.Decimal
+	RiderPay decimal.Decimal
+	Costs    decimal.Decimal
+	Net      decimal.Decimal
+}
+
+// computePnL nets the tenant's income (commission, delivery charges and
+// service fees) against promo discounts it funds, refunds and rider pay.
+func computePnL(summary sqlc.GetScheduledSalesSummaryRow, charges sqlc.GetScheduledOrderChargesRow, refunds, riderPay decimal.Decimal) pnl {
+	income := numericToDecimal(summary.Commission).
+		Add(numericToDecimal(charges.DeliveryCharges)).
+		Add(numericToDecimal(charges.ServiceFees))
+	costs := numericToDecimal(charges.PromoDiscounts).Add(refunds).Add(riderPay)
+	return pnl{Income: income, Refunds: refunds, RiderPay: riderPay, Costs: costs, Net: income.Sub(costs)}
+}
+
+func (s *Service) restaurantNames(ctx context.Context, tenantID uuid.UUID, ids []uuid.UUID) (string, error) {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		r, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: id, TenantID: tenantID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("get restaurant: %w", err)
+		}
+		names = append(names, r.Name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", "), nil
+}
+
+// formatPeriod describes a report window for the email, e.g.
+// "17 Oct 2026 23:00 – 18 Oct 2026 23:00".
+func formatPeriod(frequency string, from, to time.Time, loc *time.Location) string {
+	layout := "2 Jan 2006 15:04"
+	if from.In(loc).Hour() == 0 && to.In(loc).Hour() == 0 {
+		// Midnight to midnight reads better as whole days.
+		last := to.In(loc).AddDate(0, 0, -1)
+		if frequency == FrequencyDaily {
+			return last.Format("Mon 2 Jan 2006")
+		}
+		return from.In(loc).Format("2 Jan") + " – " + last.Format("2 Jan 2006")
+	}
+	return from.In(loc).Format(layout) + " – " + to.In(loc).Format(layout)
+}
+
+// formatMoney renders an amount with thousands separators, e.g.
+// "BDT 12,345.50".
+func formatMoney(d decimal.Decimal, currency string) string {
+	s := d.Abs().StringFixed(2)
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	out := b.String() + "." + frac
+	if d.IsNegative() {
+		out = "-" + out
+	}
+	if currency != "" {
+		out = currency + " " + out
+	}
+	return out
+}
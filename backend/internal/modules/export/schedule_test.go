@@ -0,0 +1,125 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func TestNextRun(t *testing.T) {
+	dhaka, _ := time.LoadLocation("Asia/Dhaka")
+	london, _ := time.LoadLocation("Europe/London")
+	// Wednesday 14 October 2026, 22:00 in Dhaka.
+	now := time.Date(2026, 10, 14, 22, 0, 0, 0, dhaka)
+
+	cases := []struct {
+		name      string
+		frequency string
+		hour      int
+		weekday   int
+		loc       *time.Location
+		after     time.Time
+		want      time.Time
+	}{
+		{"daily later today", FrequencyDaily, 23, 0, dhaka, now, time.Date(2026, 10, 14, 23, 0, 0, 0, dhaka)},
+		{"daily already passed", FrequencyDaily, 8, 0, dhaka, now, time.Date(2026, 10, 15, 8, 0, 0, 0, dhaka)},
+		{"daily exactly due", FrequencyDaily, 22, 0, dhaka, now, time.Date(2026, 10, 15, 22, 0, 0, 0, dhaka)},
+		{"weekly sunday", FrequencyWeekly, 23, 0, dhaka, now, time.Date(2026, 10, 18, 23, 0, 0, 0, dhaka)},
+		{"weekly today passed", FrequencyWeekly, 9, 3, dhaka, now, time.Date(2026, 10, 21, 9, 0, 0, 0, dhaka)},
+		// 22:00 in Dhaka is 17:00 in London, so 23:00 London is still today.
+		{"tenant timezone", FrequencyDaily, 23, 0, london, now, time.Date(2026, 10, 14, 23, 0, 0, 0, london)},
+		// Clocks go back on 25 October; the send stays at 23:00 local.
+		{"across DST", FrequencyWeekly, 23, 0, london, time.Date(2026, 10, 19, 0, 0, 0, 0, london), time.Date(2026, 10, 25, 23, 0, 0, 0, london)},
+	}
+	for _, c := range cases {
+		got := nextRun(c.frequency, c.hour, c.weekday, c.loc, c.after)
+		if !got.Equal(c.want) {
+			t.Errorf("%s: nextRun = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestReportPeriod(t *testing.T) {
+	dhaka, _ := time.LoadLocation("Asia/Dhaka")
+	run := time.Date(2026, 10, 18, 23, 0, 0, 0, dhaka)
+
+	from, to := reportPeriod(FrequencyWeekly, run, dhaka)
+	if !to.Equal(run) || !from.Equal(time.Date(2026, 10, 11, 23, 0, 0, 0, dhaka)) {
+		t.Errorf("weekly period = %v – %v", from, to)
+	}
+	from, _ = reportPeriod(FrequencyDaily, run, dhaka)
+	if !from.Equal(time.Date(2026, 10, 17, 23, 0, 0, 0, dhaka)) {
+		t.Errorf("daily period starts %v", from)
+	}
+	// The next daily window starts where this one ended.
+	next := nextRun(FrequencyDaily, 23, 0, dhaka, run)
+	if nextFrom, _ := reportPeriod(FrequencyDaily, next, dhaka); !nextFrom.Equal(run) {
+		t.Errorf("consecutive windows leave a gap: %v vs %v", nextFrom, run)
+	}
+}
+
+func TestNormalizeSubscription(t *testing.T) {
+	in := SubscriptionInput{
+		ReportType: ScheduledPnL,
+		Frequency:  FrequencyWeekly,
+		Recipients: []string{" Owner@Example.com", "owner@example.com", "", "finance@example.com"},
+	}
+	if err := normalizeSubscription(&in); err != nil {
+		t.Fatal(err)
+	}
+	if *in.SendHour != 23 || *in.SendWeekday != 0 || !*in.IsActive {
+		t.Errorf("defaults = %d %d %v, want 23 Sunday active", *in.SendHour, *in.SendWeekday, *in.IsActive)
+	}
+	if len(in.Recipients) != 2 || in.Recipients[0] != "owner@example.com" {
+		t.Errorf("recipients = %v", in.Recipients)
+	}
+
+	hour := 24
+	bad := []SubscriptionInput{
+		{ReportType: "inventory", Frequency: FrequencyDaily, Recipients: []string{"a@example.com"}},
+		{ReportType: ScheduledSalesSummary, Frequency: "monthly", Recipients: []string{"a@example.com"}},
+		{ReportType: ScheduledSalesSummary, Frequency: FrequencyDaily, Recipients: []string{"a@example.com"}, SendHour: &hour},
+		{ReportType: ScheduledSalesSummary, Frequency: FrequencyDaily, Recipients: []string{"not an email"}},
+		{ReportType: ScheduledSalesSummary, Frequency: FrequencyDaily},
+	}
+	for i, b := range bad {
+		if err := normalizeSubscription(&b); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
+
+func TestComputePnL(t *testing.T) {
+	num := func(s string) pgtype.Numeric {
+		var n pgtype.Numeric
+		_ = n.Scan(s)
+		return n
+	}
+	summary := sqlc.GetScheduledSalesSummaryRow{Commission: num("1500.00")}
+	charges := sqlc.GetScheduledOrderChargesRow{
+		DeliveryCharges: num("2000.00"),
+		ServiceFees:     num("300.00"),
+		PromoDiscounts:  num("450.00"),
+	}
+	p := computePnL(summary, charges, decimal.NewFromInt(200), decimal.NewFromInt(1800))
+	if !p.Income.Equal(decimal.NewFromInt(3800)) || !p.Costs.Equal(decimal.NewFromInt(2450)) || !p.Net.Equal(decimal.NewFromInt(1350)) {
+		t.Errorf("pnl = income %s costs %s net %s", p.Income, p.Costs, p.Net)
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	cases := map[string]string{
+		"0":           "BDT 0.00",
+		"999.5":       "BDT 999.50",
+		"1234567.891": "BDT 1,234,567.89",
+		"-1250":       "BDT -1,250.00",
+	}
+	for in, want := range cases {
+		if got := formatMoney(decimal.RequireFromString(in), "BDT"); got != want {
+			t.Errorf("formatMoney(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
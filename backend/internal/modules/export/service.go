@@ -0,0 +1,375 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/munchies/platform/backend/internal/platform/storage"
+	"github.com/rs/zerolog/log"
+)
+
+// Report types that can be exported.
+const (
+	ReportSales          = "sales"
+	ReportOrders         = "orders"
+	ReportRiderAnalytics = "rider_analytics"
+	ReportInvoices       = "invoices"
+	ReportRefunds        = "refunds"
+)
+
+// File formats.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Export states.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusExpired   = "expired"
+)
+
+const (
+	maxAttempts   = 3
+	maxRangeDays  = 731
+	fileRetention = 7 * 24 * time.Hour
+	// staleAfter is how long an export can run before it is assumed lost
+	// with its worker and queued again.
+	staleAfter = 30 * time.Minute
+	// exportsPerRun caps how many exports one worker tick generates.
+	exportsPerRun = 5
+)
+
+// reportRoles lists who may request each report. Restaurant roles only get
+// their own restaurants' sales and orders; finance and rider reports are for
+// tenant owners and admins.
+var reportRoles = map[string][]sqlc.UserRole{
+	ReportSales:          {sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin, sqlc.UserRoleRestaurantManager},
+	ReportOrders:         {sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin, sqlc.UserRoleRestaurantManager, sqlc.UserRoleRestaurantStaff},
+	ReportRiderAnalytics: {sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin},
+	ReportInvoices:       {sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin},
+	ReportRefunds:        {sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin},
+}
+
+// EmailSender sends emails.
+type EmailSender interface {
+	SendEmail(ctx context.Context, to, subject, htmlBody string) error
+}
+
+// Service implements report export business logic.
+type Service struct {
+	q       *sqlc.Queries
+	store   storage.Store
+	mail    EmailSender
+	baseURL string
+}
+
+// NewService creates a new export service. baseURL is the public API origin
+// used for download links in completion emails.
+func NewService(q *sqlc.Queries, store storage.Store, mail EmailSender, baseURL string) *Service {
+	return &Service{q: q, store: store, mail: mail, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// RequestInput describes an export to generate.
+type RequestInput struct {
+	ReportType   string
+	Format       string
+	StartDate    time.Time
+	EndDate      time.Time
+	RestaurantID *uuid.UUID
+	Email        bool
+}
+
+func canRequest(role sqlc.UserRole, reportType string) bool {
+	return slices.Contains(reportRoles[reportType], role)
+}
+
+func validateRequest(in RequestInput) error {
+	if _, ok := reportRoles[in.ReportType]; !ok {
+		return apperror.BadRequest("report_type must be sales, orders, rider_analytics, invoices or refunds")
+	}
+	if in.Format != FormatCSV && in.Format != FormatXLSX {
+		return apperror.BadRequest("format must be csv or xlsx")
+	}
+	if in.EndDate.Before(in.StartDate) {
+		return apperror.BadRequest("end_date must not be before start_date")
+	}
+	if in.EndDate.Sub(in.StartDate) > maxRangeDays*24*time.Hour {
+		return apperror.BadRequest("date range cannot exceed two years")
+	}
+	if in.RestaurantID != nil && in.ReportType != ReportSales && in.ReportType != ReportOrders {
+		return apperror.BadRequest("restaurant_id only applies to sales and order exports")
+	}
+	return nil
+}
+
+// Request queues an export for the worker. Restaurant managers and staff are
+// limited to the restaurants they are assigned to.
+func (s *Service) Request(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, in RequestInput) (sqlc.ReportExport, error) {
+	if err := validateRequest(in); err != nil {
+		return sqlc.ReportExport{}, err
+	}
+	if !canRequest(user.Role, in.ReportType) {
+		return sqlc.ReportExport{}, apperror.Forbidden("your role cannot export " + in.ReportType)
+	}
+	if in.Email && (!user.Email.Valid || user.Email.String == "") {
+		return sqlc.ReportExport{}, apperror.BadRequest("your account has no email address")
+	}
+
+	var requested []uuid.UUID
+	if in.RestaurantID != nil {
+		requested = []uuid.UUID{*in.RestaurantID}
+	}
+	restaurants, err := s.restaurantScope(ctx, tenantID, requested)
+	if err != nil {
+		return sqlc.ReportExport{}, err
+	}
+
+	exp, err := s.q.CreateReportExport(ctx, sqlc.CreateReportExportParams{
+		TenantID:          tenantID,
+		RequestedBy:       user.ID,
+		RequesterRole:     user.Role,
+		ReportType:        in.ReportType,
+		Format:            in.Format,
+		StartDate:         pgDate(in.StartDate),
+		EndDate:           pgDate(in.EndDate),
+		RestaurantIds:     restaurants,
+		EmailOnCompletion: in.Email,
+	})
+	if err != nil {
+		return sqlc.ReportExport{}, apperror.Internal("create export", err)
+	}
+	return exp, nil
+}
+
+// restaurantScope resolves which restaurants a report covers from the
+// request's access scope. Tenant owners and admins get what they asked for,
+// nil meaning the whole tenant. Restaurant users are held to their
+// assignments and default to all of them.
+func (s *Service) restaurantScope(ctx context.Context, tenantID uuid.UUID, requested []uuid.UUID) ([]uuid.UUID, error) {
+	scope := access.FromContext(ctx)
+	if scope == nil {
+		return nil, apperror.Forbidden("restaurant access not resolved")
+	}
+	if scope.TenantWide {
+		for _, id := range requested {
+			if _, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: id, TenantID: tenantID}); errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.NotFound("restaurant")
+			} else if err != nil {
+				return nil, apperror.Internal("get restaurant", err)
+			}
+		}
+	}
+	return scope.Restrict(requested)
+}
+
+// List returns exports newest first. Tenant owners and admins see every
+// export of the tenant; other users see their own.
+func (s *Service) List(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, limit, offset int32) ([]sqlc.ReportExport, error) {
+	requester := pgtype.UUID{}
+	if !access.IsTenantWide(user.Role) {
+		requester = pgtype.UUID{Bytes: user.ID, Valid: true}
+	}
+	exports, err := s.q.ListReportExports(ctx, sqlc.ListReportExportsParams{
+		TenantID:    tenantID,
+		RequestedBy: requester,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list exports", err)
+	}
+	return exports, nil
+}
+
+// Get returns an export the user may see.
+func (s *Service) Get(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) (sqlc.ReportExport, error) {
+	exp, err := s.q.GetReportExport(ctx, sqlc.GetReportExportParams{ID: id, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !access.IsTenantWide(user.Role) && exp.RequestedBy != user.ID) {
+		return sqlc.ReportExport{}, apperror.NotFound("export")
+	}
+	if err != nil {
+		return sqlc.ReportExport{}, apperror.Internal("get export", err)
+	}
+	return exp, nil
+}
+
+// Open returns a completed export's file. The caller closes the reader.
+func (s *Service) Open(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) (sqlc.ReportExport, io.ReadCloser, error) {
+	exp, err := s.Get(ctx, user, tenantID, id)
+	if err != nil {
+		return sqlc.ReportExport{}, nil, err
+	}
+	if exp.Status != StatusCompleted || !exp.FileKey.Valid {
+		return sqlc.ReportExport{}, nil, apperror.Conflict("export is " + exp.Status)
+	}
+	f, err := s.store.Open(ctx, exp.FileKey.String)
+	if errors.Is(err, storage.ErrNotFound) {
+		return sqlc.ReportExport{}, nil, apperror.NotFound("export file")
+	}
+	if err != nil {
+		return sqlc.ReportExport{}, nil, apperror.Internal("open export file", err)
+	}
+	return exp, f, nil
+}
+
+// ProcessExports generates queued exports. Registered with the background
+// worker; exports left running by a lost worker are queued again first.
+func (s *Service) ProcessExports(ctx context.Context) error {
+	if n, err := s.q.RequeueStaleReportExports(ctx, time.Now().Add(-staleAfter)); err != nil {
+		return fmt.Errorf("requeue stale exports: %w", err)
+	} else if n > 0 {
+		log.Warn().Int64("exports", n).Msg("requeued stale report exports")
+	}
+
+	for i := 0; i < exportsPerRun; i++ {
+		exp, err := s.q.ClaimReportExport(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("claim export: %w", err)
+		}
+		if err := s.run(ctx, exp); err != nil {
+			log.Error().Err(err).Str("export_id", exp.ID.String()).Int32("attempt", exp.Attempts).Msg("report export failed")
+			if ferr := s.q.FailReportExport(ctx, sqlc.FailReportExportParams{
+				MaxAttempts: maxAttempts,
+				Error:       nullString(err.Error()),
+				ID:          exp.ID,
+			}); ferr != nil {
+				log.Error().Err(ferr).Str("export_id", exp.ID.String()).Msg("mark export failed")
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) run(ctx context.Context, exp sqlc.ReportExport) error {
+	tmp, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	rows, err := s.write(ctx, exp, tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind temp file: %w", err)
+	}
+
+	key := fileKey(exp)
+	size, err := s.store.Put(ctx, key, tmp)
+	if err != nil {
+		return err
+	}
+	rowCount := int32(rows)
+	done, err := s.q.CompleteReportExport(ctx, sqlc.CompleteReportExportParams{
+		FileKey:     nullString(key),
+		FileName:    nullString(fileName(exp)),
+		ContentType: nullString(contentType(exp.Format)),
+		RowCount:    &rowCount,
+		SizeBytes:   &size,
+		ExpiresAt:   time.Now().Add(fileRetention),
+		ID:          exp.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("complete export: %w", err)
+	}
+
+	if done.EmailOnCompletion {
+		if err := s.sendCompletionEmail(ctx, done); err != nil {
+			// The file is ready; a lost email is not worth regenerating it.
+			log.Warn().Err(err).Str("export_id", done.ID.String()).Msg("export completion email failed")
+		}
+	}
+	return nil
+}
+
+var completionEmail = template.Must(template.New("export_ready").Parse(
+	`<h1>Your export is ready</h1><p>{{.Name}} ({{.Rows}} rows) can be downloaded until {{.Expires}}.</p>` +
+		`<p><a href="{{.Link}}">Download</a> (sign in required)</p>`))
+
+func (s *Service) sendCompletionEmail(ctx context.Context, exp sqlc.ReportExport) error {
+	if s.mail == nil {
+		return nil
+	}
+	user, err := s.q.GetUserByID(ctx, exp.RequestedBy)
+	if err != nil {
+		return fmt.Errorf("get requester: %w", err)
+	}
+	if !user.Email.Valid || user.Email.String == "" {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := completionEmail.Execute(&body, map[string]interface{}{
+		"Name":    exp.FileName.String,
+		"Rows":    *exp.RowCount,
+		"Expires": timeutil.ToBD(exp.ExpiresAt.Time).Format("2 Jan 2006 15:04"),
+		"Link":    s.baseURL + "/api/v1/partner/exports/" + exp.ID.String() + "/download",
+	}); err != nil {
+		return fmt.Errorf("render email: %w", err)
+	}
+	if err := s.mail.SendEmail(ctx, user.Email.String, "Export ready: "+exp.FileName.String, body.String()); err != nil {
+		return err
+	}
+	return s.q.MarkReportExportEmailed(ctx, exp.ID)
+}
+
+// CleanupExports deletes files past their retention and marks the exports
+// expired. Registered with the background worker.
+func (s *Service) CleanupExports(ctx context.Context) error {
+	expired, err := s.q.ListExpiredReportExports(ctx, sqlc.ListExpiredReportExportsParams{Now: time.Now(), Limit: 100})
+	if err != nil {
+		return fmt.Errorf("list expired exports: %w", err)
+	}
+	for _, exp := range expired {
+		if exp.FileKey.Valid {
+			if err := s.store.Delete(ctx, exp.FileKey.String); err != nil {
+				log.Error().Err(err).Str("export_id", exp.ID.String()).Msg("delete export file failed")
+				continue
+			}
+		}
+		if err := s.q.MarkReportExportExpired(ctx, exp.ID); err != nil {
+			log.Error().Err(err).Str("export_id", exp.ID.String()).Msg("mark export expired failed")
+		}
+	}
+	return nil
+}
+
+func fileKey(exp sqlc.ReportExport) string {
+	return "exports/" + exp.TenantID.String() + "/" + exp.ID.String() + "." + exp.Format
+}
+
+func fileName(exp sqlc.ReportExport) string {
+	return fmt.Sprintf("%s_%s_%s.%s", exp.ReportType,
+		exp.StartDate.Time.Format("2006-01-02"), exp.EndDate.Time.Format("2006-01-02"), exp.Format)
+}
+
+func contentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
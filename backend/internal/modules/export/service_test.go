@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/xlsx"
+)
+
+func TestValidateRequest(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	restaurant := uuid.New()
+	valid := RequestInput{ReportType: ReportOrders, Format: FormatXLSX, StartDate: day, EndDate: day, RestaurantID: &restaurant}
+	if err := validateRequest(valid); err != nil {
+		t.Fatalf("single-day order export rejected: %v", err)
+	}
+
+	cases := map[string]func(in *RequestInput){
+		"unknown report":      func(in *RequestInput) { in.ReportType = "payroll" },
+		"unknown format":      func(in *RequestInput) { in.Format = "pdf" },
+		"reversed range":      func(in *RequestInput) { in.EndDate = day.AddDate(0, 0, -1) },
+		"range too long":      func(in *RequestInput) { in.EndDate = day.AddDate(3, 0, 0) },
+		"restaurant on rider": func(in *RequestInput) { in.ReportType = ReportRiderAnalytics },
+	}
+	for name, mutate := range cases {
+		in := valid
+		mutate(&in)
+		if err := validateRequest(in); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCanRequest(t *testing.T) {
+	cases := []struct {
+		role   sqlc.UserRole
+		report string
+		want   bool
+	}{
+		{sqlc.UserRoleTenantAdmin, ReportInvoices, true},
+		{sqlc.UserRoleRestaurantManager, ReportSales, true},
+		{sqlc.UserRoleRestaurantManager, ReportRefunds, false},
+		{sqlc.UserRoleRestaurantStaff, ReportOrders, true},
+		{sqlc.UserRoleRestaurantStaff, ReportSales, false},
+		{sqlc.UserRoleCustomer, ReportOrders, false},
+	}
+	for _, c := range cases {
+		if got := canRequest(c.role, c.report); got != c.want {
+			t.Errorf("canRequest(%s, %s) = %v, want %v", c.role, c.report, got, c.want)
+		}
+	}
+}
+
+func TestCSVTable(t *testing.T) {
+	var buf bytes.Buffer
+	tbl, err := newTable(FormatCSV, &buf, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = tbl.Header("Order", "Total")
+	_ = tbl.Row(xlsx.String("ORD-1, Gulshan"), xlsx.Number("120.00"))
+	if err := tbl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "Order,Total\n\"ORD-1, Gulshan\",120.00\n"
+	if buf.String() != want {
+		t.Errorf("csv = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDateRangeAndFileName(t *testing.T) {
+	exp := sqlc.ReportExport{
+		ReportType: ReportSales,
+		Format:     FormatCSV,
+		StartDate:  pgDate(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:    pgDate(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)),
+	}
+	from, to := dateRange(exp)
+	if got := from.UTC(); !got.Equal(time.Date(2026, 2, 28, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("from = %v, want Dhaka midnight of 1 March", got)
+	}
+	if got := to.Sub(from); got != 31*24*time.Hour {
+		t.Errorf("range = %v, want 31 days", got)
+	}
+	if got := fileName(exp); got != "sales_2026-03-01_2026-03-31.csv" {
+		t.Errorf("fileName = %q", got)
+	}
+	if money(pgtype.Numeric{}).Value != "" {
+		t.Error("NULL amounts should be blank")
+	}
+}
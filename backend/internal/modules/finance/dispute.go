@@ -0,0 +1,541 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/pagination"
+	"github.com/shopspring/decimal"
+)
+
+// maxDisputeAttachments caps the files attached to one dispute comment.
+const maxDisputeAttachments = 5
+
+// RaiseDisputeRequest is a restaurant's dispute against a finalized
+// invoice, optionally pointing at one of its line items.
+type RaiseDisputeRequest struct {
+	LineItemID     *uuid.UUID       `json:"line_item_id"`
+	Reason         string           `json:"reason"`
+	DisputedAmount *decimal.Decimal `json:"disputed_amount"`
+	Attachments    []string         `json:"attachments"`
+}
+
+func (r *RaiseDisputeRequest) validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" {
+		return apperror.BadRequest("reason is required")
+	}
+	if r.DisputedAmount != nil && !r.DisputedAmount.IsPositive() {
+		return apperror.BadRequest("disputed_amount must be positive")
+	}
+	return validateAttachments(r.Attachments)
+}
+
+// DisputeDetail is a dispute with its comment thread and the credit or
+// debit notes issued against it.
+type DisputeDetail struct {
+	Dispute  sqlc.InvoiceDispute          `json:"dispute"`
+	Invoice  *sqlc.Invoice                `json:"invoice"`
+	Comments []sqlc.InvoiceDisputeComment `json:"comments"`
+	Notes    []sqlc.InvoiceNote           `json:"notes"`
+}
+
+// RaiseDispute opens a dispute against a finalized or paid invoice. The
+// reason and any attachments become the first comment of the thread. A line
+// item can only carry one dispute that is still open.
+func (s *Service) RaiseDispute(ctx context.Context, tenantID, invoiceID, raisedBy uuid.UUID, req RaiseDisputeRequest) (*DisputeDetail, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	inv, err := s.GetByID(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status == sqlc.InvoiceStatusDraft {
+		return nil, apperror.BadRequest("only finalized invoices can be disputed")
+	}
+	if req.LineItemID != nil {
+		if _, err := s.q.GetInvoiceLineItem(ctx, sqlc.GetInvoiceLineItemParams{ID: *req.LineItemID, InvoiceID: invoiceID, TenantID: tenantID}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.BadRequest("line item is not on this invoice")
+			}
+			return nil, apperror.Internal("get invoice line item", err)
+		}
+	}
+	var disputed pgtype.Numeric
+	if req.DisputedAmount != nil {
+		disputed = toPgNumeric(*req.DisputedAmount)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	dispute, err := qtx.CreateInvoiceDispute(ctx, sqlc.CreateInvoiceDisputeParams{
+		TenantID:       tenantID,
+		InvoiceID:      invoiceID,
+		RestaurantID:   inv.RestaurantID,
+		LineItemID:     toPgUUIDPtr(req.LineItemID),
+		Reason:         req.Reason,
+		DisputedAmount: disputed,
+		RaisedBy:       raisedBy,
+	})
+	if isUniqueViolation(err) {
+		return nil, apperror.Conflict("this line item already has an open dispute")
+	}
+	if err != nil {
+		return nil, apperror.Internal("create invoice dispute", err)
+	}
+	comment, err := qtx.CreateInvoiceDisputeComment(ctx, sqlc.CreateInvoiceDisputeCommentParams{
+		DisputeID:   dispute.ID,
+		TenantID:    tenantID,
+		AuthorID:    raisedBy,
+		Message:     req.Reason,
+		Attachments: orEmpty(req.Attachments),
+	})
+	if err != nil {
+		return nil, apperror.Internal("create dispute comment", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return &DisputeDetail{
+		Dispute:  dispute,
+		Invoice:  inv,
+		Comments: []sqlc.InvoiceDisputeComment{comment},
+		Notes:    []sqlc.InvoiceNote{},
+	}, nil
+}
+
+// ListDisputes returns a tenant's invoice disputes, newest first, optionally
+// filtered by status and restricted to restaurantIDs (nil for all).
+func (s *Service) ListDisputes(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, status string, page, perPage int) ([]sqlc.InvoiceDispute, pagination.Meta, error) {
+	if status != "" && !isDisputeStatus(status) {
+		return nil, pagination.Meta{}, apperror.BadRequest("status must be one of open, under_review, resolved, rejected")
+	}
+	limit, offset := pagination.FormatLimitOffset(page, perPage)
+	total, err := s.q.CountInvoiceDisputes(ctx, sqlc.CountInvoiceDisputesParams{
+		TenantID:      tenantID,
+		Status:        toNullStringVal(status),
+		RestaurantIds: restaurantIDs,
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("count invoice disputes", err)
+	}
+	items, err := s.q.ListInvoiceDisputes(ctx, sqlc.ListInvoiceDisputesParams{
+		TenantID:      tenantID,
+		Status:        toNullStringVal(status),
+		RestaurantIds: restaurantIDs,
+		Lim:           int32(limit),
+		Off:           int32(offset),
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("list invoice disputes", err)
+	}
+	return items, pagination.NewMeta(total, limit, ""), nil
+}
+
+// ListInvoiceDisputes returns every dispute raised against an invoice.
+func (s *Service) ListInvoiceDisputes(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]sqlc.InvoiceDispute, error) {
+	if _, err := s.GetByID(ctx, tenantID, invoiceID); err != nil {
+		return nil, err
+	}
+	disputes, err := s.q.ListInvoiceDisputesByInvoice(ctx, sqlc.ListInvoiceDisputesByInvoiceParams{InvoiceID: invoiceID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list invoice disputes", err)
+	}
+	return disputes, nil
+}
+
+// GetDispute returns a dispute with its invoice, comments and notes.
+func (s *Service) GetDispute(ctx context.Context, tenantID, disputeID uuid.UUID) (*DisputeDetail, error) {
+	dispute, err := s.q.GetInvoiceDispute(ctx, sqlc.GetInvoiceDisputeParams{ID: disputeID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("dispute")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get invoice dispute", err)
+	}
+	inv, err := s.GetByID(ctx, tenantID, dispute.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.q.ListInvoiceDisputeComments(ctx, sqlc.ListInvoiceDisputeCommentsParams{DisputeID: disputeID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list dispute comments", err)
+	}
+	invoiceNotes, err := s.q.ListInvoiceNotesByInvoice(ctx, sqlc.ListInvoiceNotesByInvoiceParams{InvoiceID: dispute.InvoiceID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list invoice notes", err)
+	}
+	notes := []sqlc.InvoiceNote{}
+	for _, n := range invoiceNotes {
+		if n.DisputeID.Valid && uuid.UUID(n.DisputeID.Bytes) == disputeID {
+			notes = append(notes, n)
+		}
+	}
+	return &DisputeDetail{Dispute: dispute, Invoice: inv, Comments: comments, Notes: notes}, nil
+}
+
+// AddDisputeComment appends a comment, with optional attachment URLs, to a
+// dispute that is still open or under review.
+func (s *Service) AddDisputeComment(ctx context.Context, tenantID, disputeID, authorID uuid.UUID, message string, attachments []string) (*sqlc.InvoiceDisputeComment, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, apperror.BadRequest("message is required")
+	}
+	if err := validateAttachments(attachments); err != nil {
+		return nil, err
+	}
+	dispute, err := s.q.GetInvoiceDispute(ctx, sqlc.GetInvoiceDisputeParams{ID: disputeID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("dispute")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get invoice dispute", err)
+	}
+	if disputeClosed(dispute.Status) {
+		return nil, apperror.BadRequest("dispute is " + string(dispute.Status))
+	}
+	comment, err := s.q.CreateInvoiceDisputeComment(ctx, sqlc.CreateInvoiceDisputeCommentParams{
+		DisputeID:   disputeID,
+		TenantID:    tenantID,
+		AuthorID:    authorID,
+		Message:     message,
+		Attachments: orEmpty(attachments),
+	})
+	if err != nil {
+		return nil, apperror.Internal("create dispute comment", err)
+	}
+	return &comment, nil
+}
+
+// UpdateDisputeStatus moves a dispute under review, or closes it as
+// resolved or rejected. Closing requires a note for the restaurant.
+func (s *Service) UpdateDisputeStatus(ctx context.Context, tenantID, disputeID, actorID uuid.UUID, status sqlc.InvoiceDisputeStatus, note string) (*sqlc.InvoiceDispute, error) {
+	note = strings.TrimSpace(note)
+	if disputeClosed(status) && note == "" {
+		return nil, apperror.BadRequest("note is required to close a dispute")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	dispute, err := qtx.GetInvoiceDisputeForUpdate(ctx, sqlc.GetInvoiceDisputeForUpdateParams{ID: disputeID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("dispute")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get invoice dispute", err)
+	}
+	if !canMoveDispute(dispute.Status, status) {
+		return nil, apperror.BadRequest(fmt.Sprintf("cannot move a dispute from %s to %s", dispute.Status, status))
+	}
+
+	params := sqlc.UpdateInvoiceDisputeStatusParams{Status: status, ID: disputeID, TenantID: tenantID}
+	if disputeClosed(status) {
+		params.ResolutionNote = toNullStringVal(note)
+		params.ResolvedBy = toPgUUID(actorID)
+	}
+	updated, err := qtx.UpdateInvoiceDisputeStatus(ctx, params)
+	if err != nil {
+		return nil, apperror.Internal("update dispute status", err)
+	}
+	if note != "" {
+		if _, err := qtx.CreateInvoiceDisputeComment(ctx, sqlc.CreateInvoiceDisputeCommentParams{
+			DisputeID:   disputeID,
+			TenantID:    tenantID,
+			AuthorID:    actorID,
+			Message:     note,
+			Attachments: []string{},
+		}); err != nil {
+			return nil, apperror.Internal("create dispute comment", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return &updated, nil
+}
+
+// IssueNoteRequest is a credit or debit note against an invoice.
+type IssueNoteRequest struct {
+	Type      string          `json:"type"`
+	Amount    decimal.Decimal `json:"amount"`
+	Reason    string          `json:"reason"`
+	DisputeID *uuid.UUID      `json:"dispute_id"`
+}
+
+func (r *IssueNoteRequest) validate() error {
+	if r.Type != string(sqlc.InvoiceNoteTypeCredit) && r.Type != string(sqlc.InvoiceNoteTypeDebit) {
+		return apperror.BadRequest("type must be credit or debit")
+	}
+	if !r.Amount.IsPositive() {
+		return apperror.BadRequest("amount must be positive")
+	}
+	if r.Amount.Exponent() < -2 {
+		return apperror.BadRequest("amount cannot have more than 2 decimal places")
+	}
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" {
+		return apperror.BadRequest("reason is required")
+	}
+	return nil
+}
+
+// IssueNote issues a numbered credit or debit note against a finalized
+// invoice. The note is carried into the restaurant's next invoice as an
+// adjustment (a credit note adds to the payout, a debit note deducts from
+// it) and posted to the ledger between commission and vendor payable. A note
+// raised for a dispute that is still open resolves it.
+func (s *Service) IssueNote(ctx context.Context, tenantID, invoiceID, issuedBy uuid.UUID, req IssueNoteRequest) (*sqlc.InvoiceNote, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	inv, err := s.GetByID(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status == sqlc.InvoiceStatusDraft {
+		return nil, apperror.BadRequest("notes can only be issued against finalized invoices; adjust the draft instead")
+	}
+	noteType := sqlc.InvoiceNoteType(req.Type)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	var dispute *sqlc.InvoiceDispute
+	if req.DisputeID != nil {
+		d, err := qtx.GetInvoiceDisputeForUpdate(ctx, sqlc.GetInvoiceDisputeForUpdateParams{ID: *req.DisputeID, TenantID: tenantID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("dispute")
+		}
+		if err != nil {
+			return nil, apperror.Internal("get invoice dispute", err)
+		}
+		if d.InvoiceID != invoiceID {
+			return nil, apperror.BadRequest("dispute is not against this invoice")
+		}
+		dispute = &d
+	}
+
+	serial, err := qtx.NextInvoiceNoteSerial(ctx, sqlc.NextInvoiceNoteSerialParams{TenantID: tenantID, NoteType: noteType})
+	if err != nil {
+		return nil, apperror.Internal("next note serial", err)
+	}
+	number := noteNumber(noteType, serial)
+	kind, amount := noteAdjustment(noteType, req.Amount)
+
+	adj, err := qtx.CreateNoteInvoiceAdjustment(ctx, sqlc.CreateNoteInvoiceAdjustmentParams{
+		TenantID:     tenantID,
+		RestaurantID: inv.RestaurantID,
+		Amount:       toPgNumeric(amount),
+		Reason:       fmt.Sprintf("%s %s for %s: %s", noteLabel(noteType), number, inv.InvoiceNumber, req.Reason),
+		CreatedBy:    toPgUUID(issuedBy),
+		Kind:         kind,
+	})
+	if err != nil {
+		return nil, apperror.Internal("create note adjustment", err)
+	}
+	note, err := qtx.CreateInvoiceNote(ctx, sqlc.CreateInvoiceNoteParams{
+		TenantID:     tenantID,
+		InvoiceID:    invoiceID,
+		RestaurantID: inv.RestaurantID,
+		DisputeID:    toPgUUIDPtr(req.DisputeID),
+		NoteType:     noteType,
+		NoteNumber:   number,
+		Amount:       toPgNumeric(req.Amount),
+		Reason:       req.Reason,
+		AdjustmentID: adj.ID,
+		IssuedBy:     issuedBy,
+	})
+	if err != nil {
+		return nil, apperror.Internal("create invoice note", err)
+	}
+
+	// A credit note gives commission back to the restaurant; a debit note
+	// takes more of its payable.
+	debitAccount, creditAccount := AccountPlatformCommission, AccountVendorPayable
+	if noteType == sqlc.InvoiceNoteTypeDebit {
+		debitAccount, creditAccount = AccountVendorPayable, AccountPlatformCommission
+	}
+	ledger := NewLedgerService(qtx)
+	description := fmt.Sprintf("%s %s for %s", noteLabel(noteType), number, inv.InvoiceNumber)
+	metadata := map[string]interface{}{
+		"invoice_id":    invoiceID.String(),
+		"restaurant_id": inv.RestaurantID.String(),
+		"adjustment_id": adj.ID.String(),
+	}
+	if _, err := ledger.Record(ctx, &tenantID, debitAccount, sqlc.LedgerEntryTypeAdjustment, "invoice_note", note.ID, req.Amount, decimal.Zero, description, metadata); err != nil {
+		return nil, apperror.Internal("record note ledger entry", err)
+	}
+	if _, err := ledger.Record(ctx, &tenantID, creditAccount, sqlc.LedgerEntryTypeAdjustment, "invoice_note", note.ID, decimal.Zero, req.Amount, description, metadata); err != nil {
+		return nil, apperror.Internal("record note ledger entry", err)
+	}
+
+	if dispute != nil && !disputeClosed(dispute.Status) {
+		if _, err := qtx.UpdateInvoiceDisputeStatus(ctx, sqlc.UpdateInvoiceDisputeStatusParams{
+			Status:         sqlc.InvoiceDisputeStatusResolved,
+			ResolutionNote: toNullStringVal("Resolved by " + number),
+			ResolvedBy:     toPgUUID(issuedBy),
+			ID:             dispute.ID,
+			TenantID:       tenantID,
+		}); err != nil {
+			return nil, apperror.Internal("resolve dispute", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return &note, nil
+}
+
+// ListNotes returns the credit and debit notes issued against an invoice.
+func (s *Service) ListNotes(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]sqlc.InvoiceNote, error) {
+	if _, err := s.GetByID(ctx, tenantID, invoiceID); err != nil {
+		return nil, err
+	}
+	notes, err := s.q.ListInvoiceNotesByInvoice(ctx, sqlc.ListInvoiceNotesByInvoiceParams{InvoiceID: invoiceID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list invoice notes", err)
+	}
+	return notes, nil
+}
+
+// NoteDocument is the content of a credit or debit note PDF. SettledOn is
+// the invoice that carried the note into a payout, nil until one has.
+type NoteDocument struct {
+	Note       sqlc.InvoiceNote
+	Invoice    *sqlc.Invoice
+	Restaurant sqlc.Restaurant
+	SettledOn  *sqlc.Invoice
+}
+
+// GetNoteDocument loads a note with its invoice, restaurant and settlement
+// for rendering.
+func (s *Service) GetNoteDocument(ctx context.Context, tenantID, noteID uuid.UUID) (*NoteDocument, error) {
+	note, err := s.q.GetInvoiceNote(ctx, sqlc.GetInvoiceNoteParams{ID: noteID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("note")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get invoice note", err)
+	}
+	inv, err := s.GetByID(ctx, tenantID, note.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: note.RestaurantID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("get note restaurant", err)
+	}
+	doc := &NoteDocument{Note: note, Invoice: inv, Restaurant: rest}
+
+	adj, err := s.q.GetInvoiceAdjustment(ctx, sqlc.GetInvoiceAdjustmentParams{ID: note.AdjustmentID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("get note adjustment", err)
+	}
+	if adj.InvoiceID.Valid {
+		if doc.SettledOn, err = s.GetByID(ctx, tenantID, adj.InvoiceID.Bytes); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// noteNumber formats a note's tenant-wide serial: CN-000001, DN-000001.
+func noteNumber(t sqlc.InvoiceNoteType, serial int32) string {
+	prefix := "CN"
+	if t == sqlc.InvoiceNoteTypeDebit {
+		prefix = "DN"
+	}
+	return fmt.Sprintf("%s-%06d", prefix, serial)
+}
+
+// noteAdjustment returns the adjustment a note settles through. Adjustments
+// are deducted from the payout, so a credit note is negative.
+func noteAdjustment(t sqlc.InvoiceNoteType, amount decimal.Decimal) (sqlc.InvoiceAdjustmentKind, decimal.Decimal) {
+	if t == sqlc.InvoiceNoteTypeDebit {
+		return sqlc.InvoiceAdjustmentKindDebitNote, amount
+	}
+	return sqlc.InvoiceAdjustmentKindCreditNote, amount.Neg()
+}
+
+func noteLabel(t sqlc.InvoiceNoteType) string {
+	if t == sqlc.InvoiceNoteTypeDebit {
+		return "Debit note"
+	}
+	return "Credit note"
+}
+
+func isDisputeStatus(s string) bool {
+	switch sqlc.InvoiceDisputeStatus(s) {
+	case sqlc.InvoiceDisputeStatusOpen, sqlc.InvoiceDisputeStatusUnderReview, sqlc.InvoiceDisputeStatusResolved, sqlc.InvoiceDisputeStatusRejected:
+		return true
+	}
+	return false
+}
+
+func disputeClosed(s sqlc.InvoiceDisputeStatus) bool {
+	return s == sqlc.InvoiceDisputeStatusResolved || s == sqlc.InvoiceDisputeStatusRejected
+}
+
+// canMoveDispute reports whether a dispute may go from one status to
+// another. Disputes only move forward and closed disputes stay closed.
+func canMoveDispute(from, to sqlc.InvoiceDisputeStatus) bool {
+	switch to {
+	case sqlc.InvoiceDisputeStatusUnderReview:
+		return from == sqlc.InvoiceDisputeStatusOpen
+	case sqlc.InvoiceDisputeStatusResolved, sqlc.InvoiceDisputeStatusRejected:
+		return !disputeClosed(from)
+	}
+	return false
+}
+
+func validateAttachments(attachments []string) error {
+	if len(attachments) > maxDisputeAttachments {
+		return apperror.BadRequest(fmt.Sprintf("at most %d attachments are allowed", maxDisputeAttachments))
+	}
+	for _, a := range attachments {
+		u, err := url.Parse(a)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return apperror.BadRequest("attachments must be uploaded file URLs")
+		}
+	}
+	return nil
+}
+
+func orEmpty(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,78 @@
+package finance
+
+import (
+	"testing"
+
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestNoteNumber(t *testing.T) {
+	if got := noteNumber(sqlc.InvoiceNoteTypeCredit, 42); got != "CN-000042" {
+		t.Errorf("credit note number = %q", got)
+	}
+	if got := noteNumber(sqlc.InvoiceNoteTypeDebit, 1234567); got != "DN-1234567" {
+		t.Errorf("debit note number = %q", got)
+	}
+}
+
+func TestCanMoveDispute(t *testing.T) {
+	open, review := sqlc.InvoiceDisputeStatusOpen, sqlc.InvoiceDisputeStatusUnderReview
+	resolved, rejected := sqlc.InvoiceDisputeStatusResolved, sqlc.InvoiceDisputeStatusRejected
+	cases := []struct {
+		from, to sqlc.InvoiceDisputeStatus
+		want     bool
+	}{
+		{open, review, true},
+		{open, resolved, true},
+		{open, rejected, true},
+		{review, resolved, true},
+		{review, rejected, true},
+		{review, review, false},
+		{review, open, false},
+		{resolved, rejected, false},
+		{rejected, review, false},
+		{open, open, false},
+	}
+	for _, c := range cases {
+		if got := canMoveDispute(c.from, c.to); got != c.want {
+			t.Errorf("%s -> %s = %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestIssueNoteRequestValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		req  IssueNoteRequest
+		ok   bool
+	}{
+		{"credit", IssueNoteRequest{Type: "credit", Amount: dec("150.50"), Reason: "Commission overcharged"}, true},
+		{"debit", IssueNoteRequest{Type: "debit", Amount: dec("20"), Reason: "Packaging"}, true},
+		{"unknown type", IssueNoteRequest{Type: "refund", Amount: dec("20"), Reason: "x"}, false},
+		{"zero amount", IssueNoteRequest{Type: "credit", Amount: dec("0"), Reason: "x"}, false},
+		{"negative amount", IssueNoteRequest{Type: "debit", Amount: dec("-5"), Reason: "x"}, false},
+		{"sub-poisha amount", IssueNoteRequest{Type: "credit", Amount: dec("1.005"), Reason: "x"}, false},
+		{"blank reason", IssueNoteRequest{Type: "credit", Amount: dec("5"), Reason: "  "}, false},
+	}
+	for _, c := range cases {
+		if err := c.req.validate(); (err == nil) != c.ok {
+			t.Errorf("%s: validate() = %v", c.name, err)
+		}
+	}
+}
+
+func TestValidateAttachments(t *testing.T) {
+	if err := validateAttachments([]string{"https://cdn.example.com/media/statement.pdf"}); err != nil {
+		t.Errorf("uploaded URL rejected: %v", err)
+	}
+	for _, bad := range [][]string{
+		{"file:///etc/passwd"},
+		{"javascript:alert(1)"},
+		{"statement.pdf"},
+		{"https://a/1", "https://a/2", "https://a/3", "https://a/4", "https://a/5", "https://a/6"},
+	} {
+		if err := validateAttachments(bad); err == nil {
+			t.Errorf("attachments %v accepted", bad)
+		}
+	}
+}
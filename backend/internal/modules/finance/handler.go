@@ -8,21 +8,26 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
 	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/shopspring/decimal"
 )
 
 // Handler handles finance HTTP requests.
 type Handler struct {
-	svc *Service
+	svc  *Service
+	docs *documents.Kit
 }
 
 // NewHandler creates a new finance handler.
-func NewHandler(svc *Service) *Handler {
-	return &Handler{svc: svc}
+func NewHandler(svc *Service, docs *documents.Kit) *Handler {
+	return &Handler{svc: svc, docs: docs}
 }
 
 // GetSummary handles GET /partner/finance/summary
@@ -76,6 +81,117 @@ func (h *Handler) GetInvoice(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, inv)
 }
 
+// ListInvoiceAdjustments handles GET /partner/finance/invoices/:id/adjustments
+func (h *Handler) ListInvoiceAdjustments(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid invoice id"))
+		return
+	}
+	adjustments, err := h.svc.ListAdjustments(r.Context(), t.ID, invoiceID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"adjustments": adjustments})
+}
+
+// ListInvoiceLineItems handles GET /partner/finance/invoices/:id/line-items
+func (h *Handler) ListInvoiceLineItems(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid invoice id"))
+		return
+	}
+	page, perPage := parsePagination(r)
+	items, meta, err := h.svc.ListLineItems(r.Context(), t.ID, invoiceID, r.URL.Query().Get("type"), page, perPage)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, pagination.PagedResponse{Data: items, Meta: meta})
+}
+
+// ListPendingAdjustments handles GET /partner/finance/adjustments?restaurant_id=
+func (h *Handler) ListPendingAdjustments(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(r.URL.Query().Get("restaurant_id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant_id"))
+		return
+	}
+	adjustments, err := h.svc.ListPendingAdjustments(r.Context(), t.ID, restaurantID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"adjustments": adjustments})
+}
+
+// CreateAdjustment handles POST /admin/finance/adjustments
+func (h *Handler) CreateAdjustment(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	var req struct {
+		RestaurantID string          `json:"restaurant_id"`
+		OrderID      *string         `json:"order_id"`
+		Amount       decimal.Decimal `json:"amount"`
+		Reason       string          `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	restaurantID, err := uuid.Parse(req.RestaurantID)
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant_id"))
+		return
+	}
+	var orderID *uuid.UUID
+	if req.OrderID != nil && *req.OrderID != "" {
+		id, err := uuid.Parse(*req.OrderID)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid order_id"))
+			return
+		}
+		orderID = &id
+	}
+
+	adj, err := h.svc.CreateAdjustment(r.Context(), t.ID, restaurantID, orderID, req.Amount, req.Reason, u.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "invoice_adjustment.created", "invoice_adjustment", adj.ID, req.Reason)
+
+	respond.JSON(w, http.StatusCreated, adj)
+}
+
 // GenerateInvoice handles POST /admin/finance/invoices/generate
 func (h *Handler) GenerateInvoice(w http.ResponseWriter, r *http.Request) {
 	u := auth.UserFromContext(r.Context())
@@ -217,20 +333,20 @@ func (h *Handler) GetInvoicePDF(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	inv, err := h.svc.GetByID(r.Context(), t.ID, invoiceID)
+	doc, err := h.svc.GetInvoiceDocument(r.Context(), t.ID, invoiceID)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
 	}
 
-	pdfBytes, err := GenerateInvoicePDF(inv)
+	pdfBytes, err := GenerateInvoicePDF(r.Context(), h.docs, t, doc)
 	if err != nil {
 		respond.Error(w, apperror.Internal("failed to generate PDF", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Disposition", "attachment; filename=\""+inv.InvoiceNumber+".pdf\"")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+doc.Invoice.InvoiceNumber+".pdf\"")
 	w.WriteHeader(http.StatusOK)
 	w.Write(pdfBytes)
 }
@@ -254,3 +370,614 @@ func toAppError(err error) *apperror.AppError {
 	}
 	return apperror.Internal("unexpected error", err)
 }
+
+// GetSettlementSchedule handles GET /admin/finance/settlement-schedule
+func (h *Handler) GetSettlementSchedule(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	sched, err := h.svc.GetSettlementSchedule(r.Context(), t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, sched)
+}
+
+// UpdateSettlementSchedule handles PUT /admin/finance/settlement-schedule
+func (h *Handler) UpdateSettlementSchedule(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	var req struct {
+		Cycle        string `json:"cycle"`
+		AnchorDate   string `json:"anchor_date"`
+		ReviewDays   int    `json:"review_days"`
+		AutoFinalize bool   `json:"auto_finalize"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	update := UpdateSettlementScheduleRequest{
+		Cycle:        req.Cycle,
+		ReviewDays:   req.ReviewDays,
+		AutoFinalize: req.AutoFinalize,
+	}
+	if req.AnchorDate != "" {
+		anchor, err := time.Parse("2006-01-02", req.AnchorDate)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid anchor_date format (YYYY-MM-DD)"))
+			return
+		}
+		update.AnchorDate = &anchor
+	}
+
+	sched, err := h.svc.UpdateSettlementSchedule(r.Context(), t.ID, u.ID, update)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "settlement_schedule.updated", "tenant", t.ID, "")
+
+	respond.JSON(w, http.StatusOK, sched)
+}
+
+// RunInvoiceCycle handles POST /admin/finance/invoice-runs
+func (h *Handler) RunInvoiceCycle(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	res, err := h.svc.RunInvoiceCycle(r.Context(), t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "invoice_run.triggered", "tenant", t.ID, "")
+
+	respond.JSON(w, http.StatusOK, res)
+}
+
+// GetPayoutAccount handles GET /partner/finance/restaurants/:id/payout-account
+func (h *Handler) GetPayoutAccount(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
+	acct, err := h.svc.GetPayoutAccount(r.Context(), t.ID, restaurantID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, acct)
+}
+
+// SetPayoutAccount handles PUT /partner/finance/restaurants/:id/payout-account
+func (h *Handler) SetPayoutAccount(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
+
+	var req PayoutAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	acct, err := h.svc.SetPayoutAccount(r.Context(), t.ID, restaurantID, u.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "payout_account.updated", "restaurant", restaurantID, "")
+
+	respond.JSON(w, http.StatusOK, acct)
+}
+
+// CreatePayoutBatch handles POST /admin/finance/payout-batches
+func (h *Handler) CreatePayoutBatch(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	detail, err := h.svc.CreatePayoutBatch(r.Context(), t.ID, u.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "payout_batch.created", "vendor_payout_batch", detail.Batch.ID, "")
+
+	respond.JSON(w, http.StatusCreated, detail)
+}
+
+// ListPayoutBatches handles GET /admin/finance/payout-batches
+func (h *Handler) ListPayoutBatches(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	page, perPage := parsePagination(r)
+	batches, meta, err := h.svc.ListPayoutBatches(r.Context(), t.ID, page, perPage)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, pagination.PagedResponse{Data: batches, Meta: meta})
+}
+
+// GetPayoutBatch handles GET /admin/finance/payout-batches/:id
+func (h *Handler) GetPayoutBatch(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid batch id"))
+		return
+	}
+	detail, err := h.svc.GetPayoutBatch(r.Context(), t.ID, batchID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, detail)
+}
+
+// ExportDisbursement handles POST /admin/finance/payout-batches/:id/disbursement.csv?method=bank|bkash
+// Exporting moves the listed payouts to processing.
+func (h *Handler) ExportDisbursement(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid batch id"))
+		return
+	}
+	method := r.URL.Query().Get("method")
+
+	detail, err := h.svc.ExportDisbursement(r.Context(), t.ID, batchID, method)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "payout_batch.exported", "vendor_payout_batch", batchID, method)
+
+	filename := "vendor-payouts-" + method + "-" + detail.Batch.CreatedAt.Format("2006-01-02") + "-" + batchID.String()[:8] + ".csv"
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	w.WriteHeader(http.StatusOK)
+	WriteDisbursementFile(w, method, detail.Payouts)
+}
+
+// ReconcileStatement handles POST /admin/finance/payout-batches/:id/reconcile
+// The statement is uploaded as a CSV in the multipart field "file".
+func (h *Handler) ReconcileStatement(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid batch id"))
+		return
+	}
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid multipart form"))
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("file is required"))
+		return
+	}
+	defer file.Close()
+
+	res, err := h.svc.ReconcileStatement(r.Context(), t.ID, batchID, u.ID, file)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "payout_batch.reconciled", "vendor_payout_batch", batchID, "")
+
+	respond.JSON(w, http.StatusOK, res)
+}
+
+// CompletePayout handles PATCH /admin/finance/payouts/:id/complete
+func (h *Handler) CompletePayout(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	payoutID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid payout id"))
+		return
+	}
+
+	var req struct {
+		PaymentReference string `json:"payment_reference"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	payout, err := h.svc.CompletePayout(r.Context(), t.ID, payoutID, u.ID, req.PaymentReference)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "vendor_payout.completed", "vendor_payout", payout.ID, req.PaymentReference)
+
+	respond.JSON(w, http.StatusOK, payout)
+}
+
+// FailPayout handles PATCH /admin/finance/payouts/:id/fail
+func (h *Handler) FailPayout(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	payoutID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid payout id"))
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if req.Note == "" {
+		respond.Error(w, apperror.BadRequest("note is required"))
+		return
+	}
+
+	payout, err := h.svc.FailPayout(r.Context(), t.ID, payoutID, u.ID, req.Note)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "vendor_payout.failed", "vendor_payout", payout.ID, req.Note)
+
+	respond.JSON(w, http.StatusOK, payout)
+}
+
+// RaiseDispute handles POST /partner/finance/invoices/:id/disputes
+func (h *Handler) RaiseDispute(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid invoice id"))
+		return
+	}
+
+	var req RaiseDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	detail, err := h.svc.RaiseDispute(r.Context(), t.ID, invoiceID, u.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "invoice_dispute.raised", "invoice_dispute", detail.Dispute.ID, detail.Dispute.Reason)
+
+	respond.JSON(w, http.StatusCreated, detail)
+}
+
+// ListDisputes handles GET /partner/finance/disputes and GET /admin/finance/disputes?status=
+// Partner staff only see disputes of the restaurants they are assigned to.
+func (h *Handler) ListDisputes(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	var restaurantIDs []uuid.UUID
+	if scope := access.FromContext(r.Context()); scope != nil {
+		restaurantIDs = scope.Filter()
+	}
+	page, perPage := parsePagination(r)
+	items, meta, err := h.svc.ListDisputes(r.Context(), t.ID, restaurantIDs, r.URL.Query().Get("status"), page, perPage)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, pagination.PagedResponse{Data: items, Meta: meta})
+}
+
+// ListInvoiceDisputes handles GET /partner/finance/invoices/:id/disputes
+func (h *Handler) ListInvoiceDisputes(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid invoice id"))
+		return
+	}
+	disputes, err := h.svc.ListInvoiceDisputes(r.Context(), t.ID, invoiceID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"disputes": disputes})
+}
+
+// GetDispute handles GET /partner/finance/disputes/:id
+func (h *Handler) GetDispute(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	disputeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid dispute id"))
+		return
+	}
+	detail, err := h.svc.GetDispute(r.Context(), t.ID, disputeID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, detail)
+}
+
+// AddDisputeComment handles POST /partner/finance/disputes/:id/comments
+func (h *Handler) AddDisputeComment(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	disputeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid dispute id"))
+		return
+	}
+
+	var req struct {
+		Message     string   `json:"message"`
+		Attachments []string `json:"attachments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	comment, err := h.svc.AddDisputeComment(r.Context(), t.ID, disputeID, u.ID, req.Message, req.Attachments)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "invoice_dispute.commented", "invoice_dispute", disputeID, "")
+
+	respond.JSON(w, http.StatusCreated, comment)
+}
+
+// UpdateDisputeStatus handles PATCH /admin/finance/disputes/:id/status
+func (h *Handler) UpdateDisputeStatus(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	disputeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid dispute id"))
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	dispute, err := h.svc.UpdateDisputeStatus(r.Context(), t.ID, disputeID, u.ID, sqlc.InvoiceDisputeStatus(req.Status), req.Note)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "invoice_dispute.status_changed", "invoice_dispute", dispute.ID, req.Status+": "+req.Note)
+
+	respond.JSON(w, http.StatusOK, dispute)
+}
+
+// IssueNote handles POST /admin/finance/invoices/:id/notes
+func (h *Handler) IssueNote(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid invoice id"))
+		return
+	}
+
+	var req IssueNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	note, err := h.svc.IssueNote(r.Context(), t.ID, invoiceID, u.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "invoice_note.issued", "invoice_note", note.ID, note.NoteNumber+": "+note.Reason)
+
+	respond.JSON(w, http.StatusCreated, note)
+}
+
+// ListInvoiceNotes handles GET /partner/finance/invoices/:id/notes
+func (h *Handler) ListInvoiceNotes(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	invoiceID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid invoice id"))
+		return
+	}
+	notes, err := h.svc.ListNotes(r.Context(), t.ID, invoiceID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"notes": notes})
+}
+
+// GetNotePDF handles GET /partner/finance/notes/:id/pdf
+func (h *Handler) GetNotePDF(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	noteID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid note id"))
+		return
+	}
+
+	doc, err := h.svc.GetNoteDocument(r.Context(), t.ID, noteID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	pdfBytes, err := GenerateNotePDF(r.Context(), h.docs, t, doc)
+	if err != nil {
+		respond.Error(w, apperror.Internal("failed to generate PDF", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+doc.Note.NoteNumber+".pdf\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdfBytes)
+}
@@ -19,6 +19,10 @@ const (
 	AccountVendorPayable      = "VENDOR_PAYABLE"
 	AccountRefundLiability    = "REFUND_LIABILITY"
 	AccountDeliveryFee        = "DELIVERY_FEE"
+	AccountCodReceivable      = "COD_RECEIVABLE"
+	AccountRiderCash          = "RIDER_CASH"
+	AccountHubCash            = "HUB_CASH"
+	AccountRiderPayable       = "RIDER_PAYABLE"
 )
 
 // LedgerService provides append-only ledger operations.
@@ -85,6 +89,10 @@ func (s *LedgerService) SeedPlatformAccounts(ctx context.Context) error {
 		{AccountVendorPayable, "Vendor Payable", sqlc.LedgerAccountTypeLiability, "Amounts owed to restaurant vendors"},
 		{AccountRefundLiability, "Refund Liability", sqlc.LedgerAccountTypeLiability, "Pending refund obligations"},
 		{AccountDeliveryFee, "Delivery Fee", sqlc.LedgerAccountTypeRevenue, "Delivery fee revenue"},
+		{AccountCodReceivable, "COD Receivable", sqlc.LedgerAccountTypeAsset, "Cash due from customers on COD orders"},
+		{AccountRiderCash, "Rider Cash in Hand", sqlc.LedgerAccountTypeAsset, "COD cash collected and held by riders"},
+		{AccountHubCash, "Hub Cash", sqlc.LedgerAccountTypeAsset, "Cash deposited by riders at hubs"},
+		{AccountRiderPayable, "Rider Payable", sqlc.LedgerAccountTypeLiability, "Earnings owed to riders"},
 	}
 
 	for _, a := range accounts {
@@ -0,0 +1,695 @@
+package finance
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/pagination"
+	"github.com/shopspring/decimal"
+)
+
+// Payout batch kinds.
+const (
+	BatchScheduled = "scheduled"
+	BatchOnDemand  = "on_demand"
+)
+
+// Payout methods.
+const (
+	PayoutMethodBank  = "bank"
+	PayoutMethodBkash = "bkash"
+)
+
+var (
+	bkashWalletRegex   = regexp.MustCompile(`^01[3-9]\d{8}$`)
+	routingNumberRegex = regexp.MustCompile(`^\d{9}$`)
+)
+
+// PayoutAccountRequest is a restaurant's bank or bKash payout account.
+type PayoutAccountRequest struct {
+	Method        string `json:"method"`
+	AccountName   string `json:"account_name"`
+	AccountNumber string `json:"account_number"`
+	BankName      string `json:"bank_name"`
+	BranchName    string `json:"branch_name"`
+	RoutingNumber string `json:"routing_number"`
+}
+
+func (r *PayoutAccountRequest) validate() error {
+	r.AccountName = strings.TrimSpace(r.AccountName)
+	r.AccountNumber = strings.TrimSpace(r.AccountNumber)
+	if r.AccountName == "" || r.AccountNumber == "" {
+		return apperror.BadRequest("account_name and account_number are required")
+	}
+	switch r.Method {
+	case PayoutMethodBkash:
+		if !bkashWalletRegex.MatchString(r.AccountNumber) {
+			return apperror.BadRequest("account_number must be an 11-digit bKash wallet number")
+		}
+		r.BankName, r.BranchName, r.RoutingNumber = "", "", ""
+	case PayoutMethodBank:
+		if strings.TrimSpace(r.BankName) == "" {
+			return apperror.BadRequest("bank_name is required for bank payouts")
+		}
+		if !routingNumberRegex.MatchString(r.RoutingNumber) {
+			return apperror.BadRequest("routing_number must be a 9-digit BEFTN routing number")
+		}
+	default:
+		return apperror.BadRequest("method must be bank or bkash")
+	}
+	return nil
+}
+
+// GetPayoutAccount returns a restaurant's payout account.
+func (s *Service) GetPayoutAccount(ctx context.Context, tenantID, restaurantID uuid.UUID) (*sqlc.RestaurantPayoutAccount, error) {
+	acct, err := s.q.GetRestaurantPayoutAccount(ctx, sqlc.GetRestaurantPayoutAccountParams{
+		RestaurantID: restaurantID,
+		TenantID:     tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("payout account")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get payout account", err)
+	}
+	return &acct, nil
+}
+
+// SetPayoutAccount creates or replaces a restaurant's payout account. Payouts
+// already in a batch keep the account they were created with.
+func (s *Service) SetPayoutAccount(ctx context.Context, tenantID, restaurantID, actorID uuid.UUID, req PayoutAccountRequest) (*sqlc.RestaurantPayoutAccount, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: restaurantID, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("restaurant")
+		}
+		return nil, apperror.Internal("get restaurant", err)
+	}
+
+	acct, err := s.q.UpsertRestaurantPayoutAccount(ctx, sqlc.UpsertRestaurantPayoutAccountParams{
+		RestaurantID:  restaurantID,
+		TenantID:      tenantID,
+		Method:        req.Method,
+		AccountName:   req.AccountName,
+		AccountNumber: req.AccountNumber,
+		BankName:      toNullStringVal(strings.TrimSpace(req.BankName)),
+		BranchName:    toNullStringVal(strings.TrimSpace(req.BranchName)),
+		RoutingNumber: toNullStringVal(req.RoutingNumber),
+		UpdatedBy:     toPgUUID(actorID),
+	})
+	if err != nil {
+		return nil, apperror.Internal("set payout account", err)
+	}
+	return &acct, nil
+}
+
+// SkippedInvoice is a finalized invoice a payout batch could not include.
+type SkippedInvoice struct {
+	InvoiceID     uuid.UUID `json:"invoice_id"`
+	InvoiceNumber string    `json:"invoice_number"`
+	RestaurantID  uuid.UUID `json:"restaurant_id"`
+	Reason        string    `json:"reason"`
+}
+
+// PayoutBatchDetail is a batch with its payouts.
+type PayoutBatchDetail struct {
+	Batch   sqlc.VendorPayoutBatch             `json:"batch"`
+	Payouts []sqlc.ListVendorPayoutsByBatchRow `json:"payouts"`
+	Skipped []SkippedInvoice                   `json:"skipped,omitempty"`
+}
+
+// carryForward closes an invoice on which the restaurant owes the platform
+// and charges what it owes to the restaurant's next invoice.
+func carryForward(ctx context.Context, qtx *sqlc.Queries, inv sqlc.Invoice, owed decimal.Decimal, actorID *uuid.UUID) error {
+	if _, err := qtx.CreateNoteInvoiceAdjustment(ctx, sqlc.CreateNoteInvoiceAdjustmentParams{
+		TenantID:     inv.TenantID,
+		RestaurantID: inv.RestaurantID,
+		Amount:       toPgNumeric(owed),
+		Reason:       fmt.Sprintf("Balance carried forward from %s", inv.InvoiceNumber),
+		CreatedBy:    toPgUUIDPtr(actorID),
+		Kind:         sqlc.InvoiceAdjustmentKindCarryForward,
+	}); err != nil {
+		return apperror.Internal("carry forward invoice balance", err)
+	}
+	if _, err := qtx.MarkInvoicePaid(ctx, sqlc.MarkInvoicePaidParams{
+		ID:       inv.ID,
+		TenantID: inv.TenantID,
+		PaidBy:   toPgUUIDPtr(actorID),
+		Notes:    toNullStringVal(fmt.Sprintf("Balance of %s carried forward to the next invoice", owed.StringFixed(2))),
+	}); err != nil {
+		return apperror.Internal("close carried forward invoice", err)
+	}
+	return nil
+}
+
+// CreatePayoutBatch pays out every finalized invoice that is not already in
+// a batch.
+func (s *Service) CreatePayoutBatch(ctx context.Context, tenantID, actorID uuid.UUID) (*PayoutBatchDetail, error) {
+	detail, err := s.createPayoutBatch(ctx, tenantID, BatchOnDemand, &actorID)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Batch.ID == uuid.Nil {
+		if len(detail.Skipped) > 0 {
+			return nil, apperror.BadRequest(fmt.Sprintf("no invoices ready for payout (%d skipped: %s)", len(detail.Skipped), detail.Skipped[0].Reason))
+		}
+		return nil, apperror.BadRequest("no finalized invoices ready for payout")
+	}
+	return detail, nil
+}
+
+// createPayoutBatch builds a batch with one payout per finalized invoice
+// owing the restaurant money. Invoices that settle at zero are marked paid
+// straight away. When the restaurant owes the platform the balance is carried
+// into its next invoice and the invoice is closed. Invoices whose restaurant
+// has no payout account are skipped and stay finalized. When nothing is paid
+// out no batch is kept and the returned batch has a nil ID.
+func (s *Service) createPayoutBatch(ctx context.Context, tenantID uuid.UUID, kind string, actorID *uuid.UUID) (*PayoutBatchDetail, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	invoices, err := qtx.ListInvoicesReadyForPayout(ctx, tenantID)
+	if err != nil {
+		return nil, apperror.Internal("list invoices ready for payout", err)
+	}
+	detail := &PayoutBatchDetail{}
+	if len(invoices) == 0 {
+		return detail, nil
+	}
+
+	batch, err := qtx.CreateVendorPayoutBatch(ctx, sqlc.CreateVendorPayoutBatchParams{
+		TenantID:  tenantID,
+		Kind:      kind,
+		CreatedBy: toPgUUIDPtr(actorID),
+	})
+	if err != nil {
+		return nil, apperror.Internal("create payout batch", err)
+	}
+
+	var count int32
+	total := decimal.Zero
+	accounts := make(map[uuid.UUID]*sqlc.RestaurantPayoutAccount)
+	for _, inv := range invoices {
+		skip := func(reason string) {
+			detail.Skipped = append(detail.Skipped, SkippedInvoice{
+				InvoiceID:     inv.ID,
+				InvoiceNumber: inv.InvoiceNumber,
+				RestaurantID:  inv.RestaurantID,
+				Reason:        reason,
+			})
+		}
+
+		amount := pgNumericToDecimal(inv.NetPayable)
+		if amount.IsZero() {
+			if _, err := qtx.MarkInvoicePaid(ctx, sqlc.MarkInvoicePaidParams{
+				ID:       inv.ID,
+				TenantID: tenantID,
+				PaidBy:   toPgUUIDPtr(actorID),
+				Notes:    toNullStringVal("Nothing to transfer"),
+			}); err != nil {
+				return nil, apperror.Internal("settle zero invoice", err)
+			}
+			continue
+		}
+		if amount.IsNegative() {
+			if err := carryForward(ctx, qtx, inv, amount.Neg(), actorID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		acct, ok := accounts[inv.RestaurantID]
+		if !ok {
+			a, err := qtx.GetRestaurantPayoutAccount(ctx, sqlc.GetRestaurantPayoutAccountParams{
+				RestaurantID: inv.RestaurantID,
+				TenantID:     tenantID,
+			})
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.Internal("get payout account", err)
+			}
+			if err == nil {
+				acct = &a
+			}
+			accounts[inv.RestaurantID] = acct
+		}
+		if acct == nil {
+			skip("restaurant has no payout account")
+			continue
+		}
+
+		if _, err := qtx.CreateVendorPayout(ctx, sqlc.CreateVendorPayoutParams{
+			TenantID:      tenantID,
+			BatchID:       batch.ID,
+			RestaurantID:  inv.RestaurantID,
+			InvoiceID:     inv.ID,
+			Amount:        inv.NetPayable,
+			Method:        acct.Method,
+			AccountName:   acct.AccountName,
+			AccountNumber: acct.AccountNumber,
+			BankName:      acct.BankName,
+			BranchName:    acct.BranchName,
+			RoutingNumber: acct.RoutingNumber,
+		}); err != nil {
+			return nil, apperror.Internal("create payout", err)
+		}
+		count++
+		total = total.Add(amount)
+	}
+
+	if count == 0 {
+		if err := qtx.DeleteVendorPayoutBatch(ctx, batch.ID); err != nil {
+			return nil, apperror.Internal("delete empty payout batch", err)
+		}
+	} else {
+		batch, err = qtx.UpdateVendorPayoutBatchTotals(ctx, sqlc.UpdateVendorPayoutBatchTotalsParams{
+			PayoutCount: count,
+			TotalAmount: toPgNumeric(total),
+			ID:          batch.ID,
+		})
+		if err != nil {
+			return nil, apperror.Internal("update payout batch", err)
+		}
+		detail.Batch = batch
+		detail.Payouts, err = qtx.ListVendorPayoutsByBatch(ctx, sqlc.ListVendorPayoutsByBatchParams{
+			BatchID:  batch.ID,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return nil, apperror.Internal("list batch payouts", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return detail, nil
+}
+
+// GetPayoutBatch returns a batch with its payouts.
+func (s *Service) GetPayoutBatch(ctx context.Context, tenantID, batchID uuid.UUID) (*PayoutBatchDetail, error) {
+	return loadPayoutBatch(ctx, s.q, tenantID, batchID)
+}
+
+func loadPayoutBatch(ctx context.Context, q *sqlc.Queries, tenantID, batchID uuid.UUID) (*PayoutBatchDetail, error) {
+	batch, err := q.GetVendorPayoutBatch(ctx, sqlc.GetVendorPayoutBatchParams{ID: batchID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("payout batch")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get payout batch", err)
+	}
+	payouts, err := q.ListVendorPayoutsByBatch(ctx, sqlc.ListVendorPayoutsByBatchParams{
+		BatchID:  batchID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list batch payouts", err)
+	}
+	return &PayoutBatchDetail{Batch: batch, Payouts: payouts}, nil
+}
+
+// ListPayoutBatches returns paginated payout batches for a tenant.
+func (s *Service) ListPayoutBatches(ctx context.Context, tenantID uuid.UUID, page, perPage int) ([]sqlc.VendorPayoutBatch, pagination.Meta, error) {
+	limit, offset := pagination.FormatLimitOffset(page, perPage)
+	total, err := s.q.CountVendorPayoutBatches(ctx, tenantID)
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("count payout batches", err)
+	}
+	batches, err := s.q.ListVendorPayoutBatches(ctx, sqlc.ListVendorPayoutBatchesParams{
+		TenantID: tenantID,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("list payout batches", err)
+	}
+	return batches, pagination.NewMeta(total, limit, ""), nil
+}
+
+// ExportDisbursement moves the payouts of a batch to be sent by one method
+// to processing and returns them, in one transaction.
+func (s *Service) ExportDisbursement(ctx context.Context, tenantID, batchID uuid.UUID, method string) (*PayoutBatchDetail, error) {
+	if method != PayoutMethodBank && method != PayoutMethodBkash {
+		return nil, apperror.BadRequest("method must be bank or bkash")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	if err := qtx.MarkVendorPayoutsProcessing(ctx, sqlc.MarkVendorPayoutsProcessingParams{
+		BatchID:  batchID,
+		TenantID: tenantID,
+		Method:   method,
+	}); err != nil {
+		return nil, apperror.Internal("mark payouts processing", err)
+	}
+	detail, err := loadPayoutBatch(ctx, qtx, tenantID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := detail.Payouts[:0]
+	for _, p := range detail.Payouts {
+		if p.Method != method {
+			continue
+		}
+		if p.Status != sqlc.PayoutStatusPending && p.Status != sqlc.PayoutStatusProcessing {
+			continue
+		}
+		rows = append(rows, p)
+	}
+	detail.Payouts = rows
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return detail, nil
+}
+
+// WriteDisbursementFile writes a disbursement file for the payouts. Bank
+// payouts use the BEFTN bulk transfer columns; bKash payouts the bulk
+// disbursement columns. The reference is the payout ID, which the
+// statement must carry back for reconciliation.
+func WriteDisbursementFile(w io.Writer, method string, payouts []sqlc.ListVendorPayoutsByBatchRow) error {
+	cw := csv.NewWriter(w)
+	if method == PayoutMethodBkash {
+		cw.Write([]string{"wallet_number", "amount", "reference", "name"})
+	} else {
+		cw.Write([]string{"beneficiary_name", "account_number", "bank_name", "branch_name", "routing_number", "amount", "reference"})
+	}
+	for _, p := range payouts {
+		amount := pgNumericToDecimal(p.Amount).StringFixed(2)
+		if method == PayoutMethodBkash {
+			cw.Write([]string{p.AccountNumber, amount, p.ID.String(), p.AccountName})
+			continue
+		}
+		cw.Write([]string{
+			p.AccountName,
+			p.AccountNumber,
+			p.BankName.String,
+			p.BranchName.String,
+			p.RoutingNumber.String,
+			amount,
+			p.ID.String(),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// StatementLine is one transfer on a bank or bKash statement.
+type StatementLine struct {
+	Row           int
+	Reference     string
+	Amount        decimal.Decimal
+	TransactionID string
+	Failed        bool
+}
+
+// ReconcileIssue is a statement line that could not be applied.
+type ReconcileIssue struct {
+	Row       int    `json:"row"`
+	Reference string `json:"reference"`
+	Message   string `json:"message"`
+}
+
+// ReconcileResult summarises a statement applied to a batch. Outstanding
+// counts payouts still awaiting confirmation.
+type ReconcileResult struct {
+	Completed   int              `json:"completed"`
+	Failed      int              `json:"failed"`
+	Outstanding int              `json:"outstanding"`
+	Issues      []ReconcileIssue `json:"issues"`
+}
+
+var failedStatementStatuses = map[string]bool{
+	"failed": true, "rejected": true, "returned": true, "reversed": true,
+}
+
+// ParseStatement reads a CSV statement. The header must name reference and
+// amount columns; transaction_id (or txn_id, trx_id, trxid) and status are
+// optional. Rows that cannot be read are returned as issues.
+func ParseStatement(r io.Reader) ([]StatementLine, []ReconcileIssue, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, nil, apperror.BadRequest("invalid CSV format")
+	}
+	if len(records) < 2 {
+		return nil, nil, apperror.BadRequest("statement must have a header row and at least one data row")
+	}
+
+	col := map[string]int{}
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "reference", "ref":
+			col["reference"] = i
+		case "amount":
+			col["amount"] = i
+		case "transaction_id", "txn_id", "trx_id", "trxid":
+			col["transaction_id"] = i
+		case "status":
+			col["status"] = i
+		}
+	}
+	if _, ok := col["reference"]; !ok {
+		return nil, nil, apperror.BadRequest("statement has no reference column")
+	}
+	if _, ok := col["amount"]; !ok {
+		return nil, nil, apperror.BadRequest("statement has no amount column")
+	}
+	field := func(row []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var lines []StatementLine
+	var issues []ReconcileIssue
+	for i, row := range records[1:] {
+		line := StatementLine{
+			Row:           i + 2,
+			Reference:     field(row, "reference"),
+			TransactionID: field(row, "transaction_id"),
+			Failed:        failedStatementStatuses[strings.ToLower(field(row, "status"))],
+		}
+		amount, err := decimal.NewFromString(strings.ReplaceAll(field(row, "amount"), ",", ""))
+		if err != nil {
+			issues = append(issues, ReconcileIssue{Row: line.Row, Reference: line.Reference, Message: "invalid amount"})
+			continue
+		}
+		line.Amount = amount
+		lines = append(lines, line)
+	}
+	return lines, issues, nil
+}
+
+// statementMatch pairs a statement line with the open payout it settles.
+type statementMatch struct {
+	PayoutID uuid.UUID
+	Line     StatementLine
+}
+
+// matchStatement checks statement lines against a batch's payouts. A line
+// settles an open payout when its reference is the payout ID and, unless the
+// transfer failed, its amount is the payout amount.
+func matchStatement(payouts []sqlc.ListVendorPayoutsByBatchRow, lines []StatementLine) (completed, failed []statementMatch, issues []ReconcileIssue) {
+	byID := make(map[uuid.UUID]sqlc.ListVendorPayoutsByBatchRow, len(payouts))
+	for _, p := range payouts {
+		byID[p.ID] = p
+	}
+	seen := make(map[uuid.UUID]bool)
+
+	for _, l := range lines {
+		issue := func(msg string) {
+			issues = append(issues, ReconcileIssue{Row: l.Row, Reference: l.Reference, Message: msg})
+		}
+		id, err := uuid.Parse(l.Reference)
+		if err != nil {
+			issue("reference is not a payout ID")
+			continue
+		}
+		p, ok := byID[id]
+		if !ok {
+			issue("payout is not in this batch")
+			continue
+		}
+		if seen[id] {
+			issue("payout appears more than once")
+			continue
+		}
+		seen[id] = true
+		if p.Status != sqlc.PayoutStatusPending && p.Status != sqlc.PayoutStatusProcessing {
+			issue("payout is already " + string(p.Status))
+			continue
+		}
+		if l.Failed {
+			failed = append(failed, statementMatch{PayoutID: id, Line: l})
+			continue
+		}
+		if amount := pgNumericToDecimal(p.Amount); !l.Amount.Equal(amount) {
+			issue(fmt.Sprintf("amount %s does not match payout amount %s", l.Amount.StringFixed(2), amount.StringFixed(2)))
+			continue
+		}
+		completed = append(completed, statementMatch{PayoutID: id, Line: l})
+	}
+	return completed, failed, issues
+}
+
+// ReconcileStatement applies a bank or bKash statement to a batch: matched
+// transfers complete their payout and mark the invoice paid with the
+// statement's transaction ID; failed transfers release the invoice for the
+// next batch.
+func (s *Service) ReconcileStatement(ctx context.Context, tenantID, batchID, actorID uuid.UUID, r io.Reader) (*ReconcileResult, error) {
+	lines, issues, err := ParseStatement(r)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := s.GetPayoutBatch(ctx, tenantID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	completed, failed, matchIssues := matchStatement(detail.Payouts, lines)
+	res := &ReconcileResult{Issues: append(issues, matchIssues...)}
+	for _, m := range completed {
+		reference := m.Line.TransactionID
+		if reference == "" {
+			reference = m.Line.Reference
+		}
+		if _, err := s.CompletePayout(ctx, tenantID, m.PayoutID, actorID, reference); err != nil {
+			res.Issues = append(res.Issues, ReconcileIssue{Row: m.Line.Row, Reference: m.Line.Reference, Message: err.Error()})
+			continue
+		}
+		res.Completed++
+	}
+	for _, m := range failed {
+		note := "Transfer failed on statement"
+		if m.Line.TransactionID != "" {
+			note += " (" + m.Line.TransactionID + ")"
+		}
+		if _, err := s.FailPayout(ctx, tenantID, m.PayoutID, actorID, note); err != nil {
+			res.Issues = append(res.Issues, ReconcileIssue{Row: m.Line.Row, Reference: m.Line.Reference, Message: err.Error()})
+			continue
+		}
+		res.Failed++
+	}
+
+	after, err := s.GetPayoutBatch(ctx, tenantID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range after.Payouts {
+		if p.Status == sqlc.PayoutStatusPending || p.Status == sqlc.PayoutStatusProcessing {
+			res.Outstanding++
+		}
+	}
+	return res, nil
+}
+
+// CompletePayout records a transfer: the payout is completed and its
+// invoice marked paid with the same reference, in one transaction.
+func (s *Service) CompletePayout(ctx context.Context, tenantID, payoutID, actorID uuid.UUID, reference string) (*sqlc.VendorPayout, error) {
+	if reference == "" {
+		return nil, apperror.BadRequest("payment_reference is required")
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	payout, err := lockOpenVendorPayout(ctx, qtx, tenantID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := qtx.CompleteVendorPayout(ctx, sqlc.CompleteVendorPayoutParams{
+		PaymentReference: toNullStringVal(reference),
+		ProcessedBy:      toPgUUID(actorID),
+		ID:               payout.ID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("complete payout", err)
+	}
+	_, err = qtx.MarkInvoicePaid(ctx, sqlc.MarkInvoicePaidParams{
+		ID:               payout.InvoiceID,
+		TenantID:         tenantID,
+		PaidBy:           toPgUUID(actorID),
+		PaymentReference: toNullStringVal(reference),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.Conflict("invoice is no longer finalized")
+	}
+	if err != nil {
+		return nil, apperror.Internal("mark invoice paid", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return &completed, nil
+}
+
+// FailPayout marks a transfer as failed. Its invoice stays finalized and is
+// picked up by the next batch.
+func (s *Service) FailPayout(ctx context.Context, tenantID, payoutID, actorID uuid.UUID, note string) (*sqlc.VendorPayout, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	payout, err := lockOpenVendorPayout(ctx, qtx, tenantID, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	failed, err := qtx.FailVendorPayout(ctx, sqlc.FailVendorPayoutParams{
+		ProcessedBy: toPgUUID(actorID),
+		Note:        toNullStringVal(note),
+		ID:          payout.ID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("fail payout", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return &failed, nil
+}
+
+func lockOpenVendorPayout(ctx context.Context, q *sqlc.Queries, tenantID, payoutID uuid.UUID) (sqlc.VendorPayout, error) {
+	payout, err := q.GetVendorPayoutForUpdate(ctx, sqlc.GetVendorPayoutForUpdateParams{ID: payoutID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.VendorPayout{}, apperror.NotFound("payout")
+	}
+	if err != nil {
+		return sqlc.VendorPayout{}, apperror.Internal("get payout", err)
+	}
+	if payout.Status != sqlc.PayoutStatusPending && payout.Status != sqlc.PayoutStatusProcessing {
+		return sqlc.VendorPayout{}, apperror.Conflict("payout is already " + string(payout.Status))
+	}
+	return payout, nil
+}
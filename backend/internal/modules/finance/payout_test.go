@@ -0,0 +1,110 @@
+package finance
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestPayoutAccountValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		req  PayoutAccountRequest
+		ok   bool
+	}{
+		{"bkash", PayoutAccountRequest{Method: PayoutMethodBkash, AccountName: "Kacchi Bhai", AccountNumber: "01712345678"}, true},
+		{"bkash bad wallet", PayoutAccountRequest{Method: PayoutMethodBkash, AccountName: "Kacchi Bhai", AccountNumber: "12345"}, false},
+		{"bank", PayoutAccountRequest{Method: PayoutMethodBank, AccountName: "Kacchi Bhai Ltd", AccountNumber: "1234567890", BankName: "City Bank", RoutingNumber: "225261725"}, true},
+		{"bank without routing", PayoutAccountRequest{Method: PayoutMethodBank, AccountName: "Kacchi Bhai Ltd", AccountNumber: "1234567890", BankName: "City Bank"}, false},
+		{"missing name", PayoutAccountRequest{Method: PayoutMethodBkash, AccountNumber: "01712345678"}, false},
+		{"unknown method", PayoutAccountRequest{Method: "nagad", AccountName: "x", AccountNumber: "01712345678"}, false},
+	}
+	for _, c := range cases {
+		err := c.req.validate()
+		if (err == nil) != c.ok {
+			t.Errorf("%s: validate() = %v, want ok=%v", c.name, err, c.ok)
+		}
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	csv := "Ref,Amount,TrxID,Status\n" +
+		"a,\"1,250.50\",TX1,Success\n" +
+		"b,oops,TX2,success\n" +
+		"c,300,TX3,Returned\n"
+	lines, issues, err := ParseStatement(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseStatement: %v", err)
+	}
+	if len(lines) != 2 || len(issues) != 1 {
+		t.Fatalf("got %d lines, %d issues; want 2, 1", len(lines), len(issues))
+	}
+	if lines[0].Reference != "a" || !lines[0].Amount.Equal(dec("1250.50")) || lines[0].TransactionID != "TX1" || lines[0].Failed {
+		t.Errorf("line 0 = %+v", lines[0])
+	}
+	if !lines[1].Failed || lines[1].Row != 4 {
+		t.Errorf("line 1 = %+v, want failed transfer on row 4", lines[1])
+	}
+	if issues[0].Row != 3 {
+		t.Errorf("issue on row %d, want 3", issues[0].Row)
+	}
+
+	if _, _, err := ParseStatement(strings.NewReader("amount\n100\n")); err == nil {
+		t.Error("statement without a reference column should be rejected")
+	}
+}
+
+func TestMatchStatement(t *testing.T) {
+	open, done, short, failing := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	payouts := []sqlc.ListVendorPayoutsByBatchRow{
+		{ID: open, Amount: num("1000"), Status: sqlc.PayoutStatusProcessing},
+		{ID: done, Amount: num("500"), Status: sqlc.PayoutStatusCompleted},
+		{ID: short, Amount: num("750"), Status: sqlc.PayoutStatusProcessing},
+		{ID: failing, Amount: num("200"), Status: sqlc.PayoutStatusPending},
+	}
+	lines := []StatementLine{
+		{Row: 2, Reference: open.String(), Amount: dec("1000.00"), TransactionID: "TX1"},
+		{Row: 3, Reference: done.String(), Amount: dec("500")},
+		{Row: 4, Reference: short.String(), Amount: dec("700")},
+		{Row: 5, Reference: failing.String(), Amount: dec("200"), Failed: true},
+		{Row: 6, Reference: uuid.NewString(), Amount: dec("10")},
+		{Row: 7, Reference: "not-a-uuid", Amount: dec("10")},
+		{Row: 8, Reference: open.String(), Amount: dec("1000")},
+	}
+
+	completed, failed, issues := matchStatement(payouts, lines)
+	if len(completed) != 1 || completed[0].PayoutID != open || completed[0].Line.TransactionID != "TX1" {
+		t.Errorf("completed = %+v, want only the matching payout", completed)
+	}
+	if len(failed) != 1 || failed[0].PayoutID != failing {
+		t.Errorf("failed = %+v, want the returned transfer", failed)
+	}
+	rows := map[int]bool{}
+	for _, i := range issues {
+		rows[i.Row] = true
+	}
+	for _, row := range []int{3, 4, 6, 7, 8} {
+		if !rows[row] {
+			t.Errorf("expected an issue for row %d; issues = %+v", row, issues)
+		}
+	}
+}
+
+func TestWriteDisbursementFile(t *testing.T) {
+	id := uuid.New()
+	payouts := []sqlc.ListVendorPayoutsByBatchRow{{
+		ID: id, Amount: num("1250.5"), Method: PayoutMethodBkash,
+		AccountName: "Kacchi Bhai", AccountNumber: "01712345678",
+	}}
+	var buf bytes.Buffer
+	if err := WriteDisbursementFile(&buf, PayoutMethodBkash, payouts); err != nil {
+		t.Fatalf("WriteDisbursementFile: %v", err)
+	}
+	want := "wallet_number,amount,reference,name\n01712345678,1250.50," + id.String() + ",Kacchi Bhai\n"
+	if buf.String() != want {
+		t.Errorf("file =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -1,81 +1,188 @@
 package finance
 
 import (
-	"bytes"
+	"context"
+	"database/sql"
 	"fmt"
-	"html/template"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/pdf"
+	"github.com/munchies/platform/backend/internal/platform/documents"
 )
 
-const invoiceHTMLTemplate = `<!DOCTYPE html>
-<html>
-<head><style>
-body { font-family: Arial, sans-serif; margin: 40px; }
-h1 { color: #333; }
-table { width: 100%%; border-collapse: collapse; margin: 20px 0; }
-th, td { border: 1px solid #ddd; padding: 8px; text-align: left; }
-th { background-color: #f2f2f2; }
-.total { font-weight: bold; font-size: 1.2em; }
-.header { display: flex; justify-content: space-between; }
-</style></head>
-<body>
-<h1>Invoice {{.InvoiceNumber}}</h1>
-<p><strong>Period:</strong> {{.PeriodStart}} to {{.PeriodEnd}}</p>
-<p><strong>Status:</strong> {{.Status}}</p>
-<table>
-<tr><th>Description</th><th>Amount</th></tr>
-<tr><td>Gross Sales</td><td>{{.GrossSales}}</td></tr>
-<tr><td>Item Discounts</td><td>-{{.ItemDiscounts}}</td></tr>
-<tr><td>Vendor Promo Discounts</td><td>-{{.VendorPromoDiscounts}}</td></tr>
-<tr><td>Net Sales</td><td>{{.NetSales}}</td></tr>
-<tr><td>VAT Collected</td><td>{{.VatCollected}}</td></tr>
-<tr><td>Commission ({{.CommissionRate}}%%)</td><td>-{{.CommissionAmount}}</td></tr>
-<tr><td>Penalties</td><td>-{{.PenaltyAmount}}</td></tr>
-<tr><td>Adjustments</td><td>{{.AdjustmentAmount}}</td></tr>
-<tr class="total"><td>Net Payable</td><td>{{.NetPayable}}</td></tr>
-</table>
-<p><strong>Total Orders:</strong> {{.TotalOrders}} | <strong>Delivered:</strong> {{.DeliveredOrders}} | <strong>Cancelled:</strong> {{.CancelledOrders}} | <strong>Rejected:</strong> {{.RejectedOrders}}</p>
-</body>
-</html>`
-
-// GenerateInvoicePDF generates a PDF representation of an invoice.
-// For now, it returns the HTML as a simple PDF-like document.
-// In production, this would use wkhtmltopdf or a headless browser.
-func GenerateInvoicePDF(inv *sqlc.Invoice) ([]byte, error) {
-	tmpl, err := template.New("invoice").Parse(invoiceHTMLTemplate)
-	if err != nil {
-		return nil, fmt.Errorf("parse template: %w", err)
+// lineTypeLabels names invoice line types on the PDF.
+var lineTypeLabels = map[string]string{
+	LineTypeOrder:   "Order",
+	LineTypePenalty: "Penalty",
+	LineTypeManual:  "Adjustment",
+	LineTypeRefund:  "Refund",
+
+	LineTypeCreditNote:   "Credit note",
+	LineTypeDebitNote:    "Debit note",
+	LineTypeCarryForward: "Balance carried forward",
+}
+
+// GenerateInvoicePDF renders an invoice under the tenant's letterhead: the
+// settlement summary, every line item and the period's order counts.
+func GenerateInvoicePDF(ctx context.Context, kit *documents.Kit, t *sqlc.Tenant, doc *InvoiceDocument) ([]byte, error) {
+	inv := doc.Invoice
+	brand := kit.Brand(ctx, t)
+	loc := documents.Location(t)
+
+	d := kit.Document("Invoice "+inv.InvoiceNumber, t.Name)
+	fields := []pdf.Field{
+		{Label: "Invoice no", Value: inv.InvoiceNumber},
+		{Label: "Period", Value: formatPgDate(inv.PeriodStart) + " to " + formatPgDate(inv.PeriodEnd)},
+		{Label: "Status", Value: strings.ToUpper(string(inv.Status))},
+	}
+	if inv.FinalizedAt.Valid {
+		fields = append(fields, pdf.Field{Label: "Issued", Value: inv.FinalizedAt.Time.In(loc).Format("2 Jan 2006")})
+	}
+	d.Letterhead(brand, "INVOICE", fields)
+
+	d.Heading("Billed to", brand.Color)
+	billed := []pdf.Field{{Label: "Restaurant", Value: doc.Restaurant.Name}}
+	if addr := restaurantAddress(doc.Restaurant); addr != "" {
+		billed = append(billed, pdf.Field{Label: "Address", Value: addr})
 	}
+	if doc.Restaurant.Phone.Valid {
+		billed = append(billed, pdf.Field{Label: "Phone", Value: doc.Restaurant.Phone.String})
+	}
+	if inv.Status == sqlc.InvoiceStatusPaid && inv.PaymentReference.Valid {
+		billed = append(billed, pdf.Field{Label: "Payment ref", Value: inv.PaymentReference.String})
+	}
+	d.Fields(billed)
+	d.Space(6)
+
+	currency := t.Currency
+	d.Heading("Summary", brand.Color)
+	adjustments := "Adjustments"
+	if inv.AdjustmentNote.Valid && inv.AdjustmentNote.String != "" {
+		adjustments += " (" + inv.AdjustmentNote.String + ")"
+	}
+	d.Table(pdf.Table{
+		Columns: []pdf.Column{{Title: "Description", Width: 3}, {Title: "Amount (" + currency + ")", Width: 1, Align: pdf.AlignRight}},
+		Rows: []pdf.Row{
+			{Cells: []string{"Gross sales", formatPgNumeric(inv.GrossSales)}},
+			{Cells: []string{"Item discounts", deduction(inv.ItemDiscounts)}},
+			{Cells: []string{"Vendor promo discounts", deduction(inv.VendorPromoDiscounts)}},
+			{Cells: []string{"Net sales", formatPgNumeric(inv.NetSales)}, Bold: true},
+			{Cells: []string{"VAT collected", formatPgNumeric(inv.VatCollected)}},
+			{Cells: []string{"Commission (" + formatPgNumeric(inv.CommissionRate) + "%)", deduction(inv.CommissionAmount)}},
+			{Cells: []string{"Penalties", deduction(inv.PenaltyAmount)}},
+			{Cells: []string{"Refunds", deduction(inv.RefundAmount)}},
+			{Cells: []string{adjustments, deduction(inv.AdjustmentAmount)}},
+			{Cells: []string{"Net payable", formatPgNumeric(inv.NetPayable)}, Bold: true},
+		},
+		Accent: brand.Color,
+	})
+	d.Space(4)
+	d.Paragraph(fmt.Sprintf("Orders: %d total, %d delivered, %d cancelled, %d rejected",
+		inv.TotalOrders, inv.DeliveredOrders, inv.CancelledOrders, inv.RejectedOrders), pdf.Style{Size: 9, Color: pdf.Gray}, pdf.AlignLeft)
 
-	data := map[string]interface{}{
-		"InvoiceNumber":        inv.InvoiceNumber,
-		"PeriodStart":          formatPgDate(inv.PeriodStart),
-		"PeriodEnd":            formatPgDate(inv.PeriodEnd),
-		"Status":               inv.Status,
-		"GrossSales":           formatPgNumeric(inv.GrossSales),
-		"ItemDiscounts":        formatPgNumeric(inv.ItemDiscounts),
-		"VendorPromoDiscounts": formatPgNumeric(inv.VendorPromoDiscounts),
-		"NetSales":             formatPgNumeric(inv.NetSales),
-		"VatCollected":         formatPgNumeric(inv.VatCollected),
-		"CommissionRate":       formatPgNumeric(inv.CommissionRate),
-		"CommissionAmount":     formatPgNumeric(inv.CommissionAmount),
-		"PenaltyAmount":        formatPgNumeric(inv.PenaltyAmount),
-		"AdjustmentAmount":     formatPgNumeric(inv.AdjustmentAmount),
-		"NetPayable":           formatPgNumeric(inv.NetPayable),
-		"TotalOrders":          inv.TotalOrders,
-		"DeliveredOrders":      inv.DeliveredOrders,
-		"CancelledOrders":      inv.CancelledOrders,
-		"RejectedOrders":       inv.RejectedOrders,
+	if len(doc.LineItems) > 0 {
+		d.Space(8)
+		d.Heading("Line items", brand.Color)
+		rows := make([]pdf.Row, 0, len(doc.LineItems)+1)
+		for _, li := range doc.LineItems {
+			rows = append(rows, pdf.Row{Cells: []string{
+				li.OccurredAt.In(loc).Format("02 Jan"),
+				lineTypeLabels[li.LineType],
+				li.Description,
+				formatPgNumeric(li.NetSales),
+				formatPgNumeric(li.Vat),
+				formatPgNumeric(li.CommissionAmount),
+				formatPgNumeric(li.Amount),
+			}})
+		}
+		rows = append(rows, pdf.Row{Cells: []string{"", "", "Net payable", "", "", "", formatPgNumeric(inv.NetPayable)}, Bold: true})
+		d.Table(pdf.Table{
+			Columns: []pdf.Column{
+				{Title: "Date", Width: 1},
+				{Title: "Type", Width: 1.3},
+				{Title: "Description", Width: 3},
+				{Title: "Net sales", Width: 1.4, Align: pdf.AlignRight},
+				{Title: "VAT", Width: 1.2, Align: pdf.AlignRight},
+				{Title: "Commission", Width: 1.4, Align: pdf.AlignRight},
+				{Title: "Amount", Width: 1.4, Align: pdf.AlignRight},
+			},
+			Rows:     rows,
+			Accent:   brand.Color,
+			FontSize: 8,
+		})
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("execute template: %w", err)
+	if inv.Notes.Valid && inv.Notes.String != "" {
+		d.Space(8)
+		d.Heading("Notes", brand.Color)
+		d.Paragraph(inv.Notes.String, pdf.Style{Size: 9}, pdf.AlignLeft)
 	}
 
-	return buf.Bytes(), nil
+	return d.Bytes()
+}
+
+// GenerateNotePDF renders a credit or debit note under the tenant's
+// letterhead, referencing the invoice it corrects and the invoice that
+// settled it, if any.
+func GenerateNotePDF(ctx context.Context, kit *documents.Kit, t *sqlc.Tenant, doc *NoteDocument) ([]byte, error) {
+	note := doc.Note
+	brand := kit.Brand(ctx, t)
+	loc := documents.Location(t)
+	label := noteLabel(note.NoteType)
+
+	d := kit.Document(label+" "+note.NoteNumber, t.Name)
+	d.Letterhead(brand, strings.ToUpper(label), []pdf.Field{
+		{Label: "Note no", Value: note.NoteNumber},
+		{Label: "Date", Value: note.CreatedAt.In(loc).Format("2 Jan 2006")},
+		{Label: "Invoice no", Value: doc.Invoice.InvoiceNumber},
+		{Label: "Invoice period", Value: formatPgDate(doc.Invoice.PeriodStart) + " to " + formatPgDate(doc.Invoice.PeriodEnd)},
+	})
+
+	d.Heading("Billed to", brand.Color)
+	billed := []pdf.Field{{Label: "Restaurant", Value: doc.Restaurant.Name}}
+	if addr := restaurantAddress(doc.Restaurant); addr != "" {
+		billed = append(billed, pdf.Field{Label: "Address", Value: addr})
+	}
+	d.Fields(billed)
+	d.Space(6)
+
+	effect := "Added to payout"
+	if note.NoteType == sqlc.InvoiceNoteTypeDebit {
+		effect = "Deducted from payout"
+	}
+	d.Heading("Amount", brand.Color)
+	d.Table(pdf.Table{
+		Columns: []pdf.Column{{Title: "Description", Width: 3}, {Title: "Amount (" + t.Currency + ")", Width: 1, Align: pdf.AlignRight}},
+		Rows: []pdf.Row{
+			{Cells: []string{note.Reason, formatPgNumeric(note.Amount)}},
+			{Cells: []string{effect, formatPgNumeric(note.Amount)}, Bold: true},
+		},
+		Accent: brand.Color,
+	})
+	d.Space(8)
+
+	settlement := "Settles on the restaurant's next invoice."
+	if doc.SettledOn != nil {
+		settlement = "Settled on invoice " + doc.SettledOn.InvoiceNumber + " (" + formatPgDate(doc.SettledOn.PeriodStart) + " to " + formatPgDate(doc.SettledOn.PeriodEnd) + ")."
+	}
+	d.Paragraph(settlement, pdf.Style{Size: 9, Color: pdf.Gray}, pdf.AlignLeft)
+	return d.Bytes()
+}
+
+// restaurantAddress joins the non-empty parts of a restaurant's address.
+func restaurantAddress(r sqlc.Restaurant) string {
+	var parts []string
+	for _, p := range []sql.NullString{r.AddressLine1, r.AddressLine2, r.Area} {
+		if p.Valid && strings.TrimSpace(p.String) != "" {
+			parts = append(parts, strings.TrimSpace(p.String))
+		}
+	}
+	if r.City != "" {
+		parts = append(parts, r.City)
+	}
+	return strings.Join(parts, ", ")
 }
 
 func formatPgDate(d pgtype.Date) string {
@@ -86,12 +193,14 @@ func formatPgDate(d pgtype.Date) string {
 }
 
 func formatPgNumeric(n pgtype.Numeric) string {
-	if !n.Valid {
-		return "0.00"
-	}
-	f, err := n.Float64Value()
-	if err != nil {
-		return "0.00"
+	return documents.Money(pgNumericToDecimal(n))
+}
+
+// deduction formats an amount taken off the payout.
+func deduction(n pgtype.Numeric) string {
+	d := pgNumericToDecimal(n)
+	if d.IsZero() {
+		return documents.Money(d)
 	}
-	return fmt.Sprintf("%.2f", f.Float64)
+	return documents.Money(d.Neg())
 }
@@ -0,0 +1,79 @@
+package finance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+)
+
+func TestGenerateInvoicePDF(t *testing.T) {
+	inv := &sqlc.Invoice{
+		InvoiceNumber:    "INV-20261001-1a2b3c4d",
+		PeriodStart:      pgtype.Date{Time: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		PeriodEnd:        pgtype.Date{Time: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), Valid: true},
+		Status:           sqlc.InvoiceStatusFinalized,
+		GrossSales:       num("152000"),
+		NetSales:         num("150000"),
+		VatCollected:     num("11250"),
+		CommissionRate:   num("15"),
+		CommissionAmount: num("22500"),
+		NetPayable:       num("138750"),
+	}
+	doc := &InvoiceDocument{Invoice: inv, Restaurant: sqlc.Restaurant{Name: "Kacchi Bhai", City: "Dhaka"}}
+	for i := range 150 {
+		doc.LineItems = append(doc.LineItems, sqlc.InvoiceLineItem{
+			LineType:    LineTypeOrder,
+			Description: fmt.Sprintf("Order ORD-%04d", i),
+			NetSales:    num("1000"),
+			Vat:         num("75"),
+			Amount:      num("925"),
+			OccurredAt:  time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	kit := documents.New(documents.Config{})
+	out, err := GenerateInvoicePDF(context.Background(), kit, &sqlc.Tenant{Name: "Munchies", PrimaryColor: "#E23744", Currency: "BDT"}, doc)
+	if err != nil {
+		t.Fatalf("GenerateInvoicePDF: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("output is not a PDF")
+	}
+	// 150 line items cannot fit on one page.
+	if bytes.Contains(out, []byte("/Count 1 ")) {
+		t.Error("line items should run onto further pages")
+	}
+}
+
+func TestGenerateNotePDF(t *testing.T) {
+	period := func(day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	doc := &NoteDocument{
+		Note: sqlc.InvoiceNote{
+			NoteType:   sqlc.InvoiceNoteTypeCredit,
+			NoteNumber: "CN-000001",
+			Amount:     num("450"),
+			Reason:     "Commission charged on a cancelled order",
+			CreatedAt:  time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+		},
+		Invoice:    &sqlc.Invoice{InvoiceNumber: "INV-20261001-1a2b3c4d", PeriodStart: period(1), PeriodEnd: period(15)},
+		Restaurant: sqlc.Restaurant{Name: "Kacchi Bhai", City: "Dhaka"},
+		SettledOn:  &sqlc.Invoice{InvoiceNumber: "INV-20261016-1a2b3c4d", PeriodStart: period(16), PeriodEnd: period(31)},
+	}
+
+	kit := documents.New(documents.Config{})
+	out, err := GenerateNotePDF(context.Background(), kit, &sqlc.Tenant{Name: "Munchies", PrimaryColor: "#E23744", Currency: "BDT"}, doc)
+	if err != nil {
+		t.Fatalf("GenerateNotePDF: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("output is not a PDF")
+	}
+}
@@ -0,0 +1,267 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+)
+
+// Settlement cycles.
+const (
+	CycleWeekly      = "weekly"
+	CycleFortnightly = "fortnightly"
+)
+
+const (
+	defaultReviewDays = 3
+	maxReviewDays     = 30
+	autoFinalizeNote  = "Finalized automatically after the review window"
+)
+
+// defaultCycleAnchor is the Monday settlement periods are counted from when a
+// tenant has not chosen its own.
+var defaultCycleAnchor = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func cycleDays(cycle string) int {
+	if cycle == CycleFortnightly {
+		return 14
+	}
+	return 7
+}
+
+// settlementWindow returns the instants bounding the inclusive Asia/Dhaka
+// dates start through end.
+func settlementWindow(start, end time.Time) (from, to time.Time) {
+	from = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, timeutil.BangladeshLocation)
+	to = time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, timeutil.BangladeshLocation)
+	return from, to
+}
+
+// dueInvoicePeriod returns the period a schedule should invoice on today: it
+// ends with the last full cycle before today and starts the day after the
+// last invoiced period, so periods never overlap and a missed run is caught
+// up in one invoice. Without a previous run it is the last full cycle. It
+// reports false when that period has already been invoiced. All dates are
+// midnight UTC.
+func dueInvoicePeriod(cycle string, anchor time.Time, lastEnd pgtype.Date, today time.Time) (start, end time.Time, ok bool) {
+	days := cycleDays(cycle)
+	elapsed := int(today.Sub(anchor).Hours() / 24)
+	n := elapsed / days
+	if elapsed < 0 && elapsed%days != 0 {
+		n--
+	}
+	current := anchor.AddDate(0, 0, n*days)
+	start = current.AddDate(0, 0, -days)
+	end = current.AddDate(0, 0, -1)
+	if lastEnd.Valid {
+		start = lastEnd.Time.AddDate(0, 0, 1)
+	}
+	return start, end, !start.After(end)
+}
+
+// GetSettlementSchedule returns a tenant's settlement schedule, or the
+// defaults (weekly from Monday, three review days, auto-finalize) when it has
+// not set one.
+func (s *Service) GetSettlementSchedule(ctx context.Context, tenantID uuid.UUID) (*sqlc.SettlementSchedule, error) {
+	sched, err := s.q.GetSettlementSchedule(ctx, tenantID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &sqlc.SettlementSchedule{
+			TenantID:     tenantID,
+			Cycle:        CycleWeekly,
+			AnchorDate:   pgtype.Date{Time: defaultCycleAnchor, Valid: true},
+			ReviewDays:   defaultReviewDays,
+			AutoFinalize: true,
+		}, nil
+	}
+	if err != nil {
+		return nil, apperror.Internal("get settlement schedule", err)
+	}
+	return &sched, nil
+}
+
+// UpdateSettlementScheduleRequest changes how a tenant is invoiced. A nil
+// anchor keeps the current one.
+type UpdateSettlementScheduleRequest struct {
+	Cycle        string
+	AnchorDate   *time.Time
+	ReviewDays   int
+	AutoFinalize bool
+}
+
+// UpdateSettlementSchedule sets a tenant's settlement cycle and review window.
+func (s *Service) UpdateSettlementSchedule(ctx context.Context, tenantID, actorID uuid.UUID, req UpdateSettlementScheduleRequest) (*sqlc.SettlementSchedule, error) {
+	if req.Cycle != CycleWeekly && req.Cycle != CycleFortnightly {
+		return nil, apperror.BadRequest("cycle must be weekly or fortnightly")
+	}
+	if req.ReviewDays < 0 || req.ReviewDays > maxReviewDays {
+		return nil, apperror.BadRequest(fmt.Sprintf("review_days must be between 0 and %d", maxReviewDays))
+	}
+
+	current, err := s.GetSettlementSchedule(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	anchor := current.AnchorDate
+	if req.AnchorDate != nil {
+		anchor = pgDateFromTime(*req.AnchorDate)
+	}
+
+	sched, err := s.q.UpsertSettlementSchedule(ctx, sqlc.UpsertSettlementScheduleParams{
+		TenantID:     tenantID,
+		Cycle:        req.Cycle,
+		AnchorDate:   anchor,
+		ReviewDays:   int16(req.ReviewDays),
+		AutoFinalize: req.AutoFinalize,
+		UpdatedBy:    toPgUUID(actorID),
+	})
+	if err != nil {
+		return nil, apperror.Internal("update settlement schedule", err)
+	}
+	return &sched, nil
+}
+
+// InvoiceRunResult is what one settlement run did for a tenant.
+type InvoiceRunResult struct {
+	PeriodStart       *string                 `json:"period_start"`
+	PeriodEnd         *string                 `json:"period_end"`
+	InvoicesGenerated int                     `json:"invoices_generated"`
+	InvoicesFinalized int                     `json:"invoices_finalized"`
+	PayoutBatch       *sqlc.VendorPayoutBatch `json:"payout_batch"`
+	SkippedInvoices   []SkippedInvoice        `json:"skipped_invoices"`
+}
+
+// RunInvoiceCycles runs the settlement schedule of every active tenant:
+// invoices for periods that have closed, auto-finalization of drafts past
+// their review window and a payout batch for finalized invoices. It is safe
+// to run repeatedly.
+func (s *Service) RunInvoiceCycles(ctx context.Context) error {
+	tenantIDs, err := s.q.ListActiveTenantIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, tenantID := range tenantIDs {
+		res, err := s.RunInvoiceCycle(ctx, tenantID)
+		if err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("settlement run failed")
+			continue
+		}
+		if res.InvoicesGenerated > 0 || res.InvoicesFinalized > 0 || res.PayoutBatch != nil {
+			ev := log.Info().Str("tenant_id", tenantID.String()).
+				Int("generated", res.InvoicesGenerated).
+				Int("finalized", res.InvoicesFinalized)
+			if res.PayoutBatch != nil {
+				ev = ev.Str("payout_batch_id", res.PayoutBatch.ID.String())
+			}
+			ev.Msg("settlement run completed")
+		}
+	}
+	return nil
+}
+
+// RunInvoiceCycle runs one tenant's settlement schedule now.
+func (s *Service) RunInvoiceCycle(ctx context.Context, tenantID uuid.UUID) (*InvoiceRunResult, error) {
+	sched, err := s.GetSettlementSchedule(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	res := &InvoiceRunResult{}
+
+	now := timeutil.NowBD()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if start, end, ok := dueInvoicePeriod(sched.Cycle, sched.AnchorDate.Time, sched.LastPeriodEnd, today); ok {
+		generated, err := s.invoicePeriod(ctx, tenantID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		ps, pe := start.Format("2006-01-02"), end.Format("2006-01-02")
+		res.PeriodStart, res.PeriodEnd = &ps, &pe
+		res.InvoicesGenerated = generated
+	}
+
+	if sched.AutoFinalize {
+		finalized, err := s.finalizeReviewedInvoices(ctx, tenantID, time.Now().AddDate(0, 0, -int(sched.ReviewDays)))
+		if err != nil {
+			return nil, err
+		}
+		res.InvoicesFinalized = finalized
+	}
+
+	detail, err := s.createPayoutBatch(ctx, tenantID, BatchScheduled, nil)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Batch.ID != uuid.Nil {
+		res.PayoutBatch = &detail.Batch
+	}
+	res.SkippedInvoices = detail.Skipped
+	return res, nil
+}
+
+// invoicePeriod generates the period's invoice for every restaurant with
+// delivered orders or pending adjustments and records the period as
+// invoiced. A failure leaves the period open so the next run retries it;
+// invoices already generated are returned as they are.
+func (s *Service) invoicePeriod(ctx context.Context, tenantID uuid.UUID, start, end time.Time) (int, error) {
+	from, to := settlementWindow(start, end)
+	restaurantIDs, err := s.q.ListRestaurantsToInvoice(ctx, sqlc.ListRestaurantsToInvoiceParams{
+		TenantID:    tenantID,
+		PeriodStart: from,
+		PeriodEnd:   to,
+	})
+	if err != nil {
+		return 0, apperror.Internal("list restaurants to invoice", err)
+	}
+
+	for _, restaurantID := range restaurantIDs {
+		if _, err := s.GenerateForRestaurant(ctx, tenantID, restaurantID, start, end, nil); err != nil {
+			return 0, fmt.Errorf("invoice restaurant %s: %w", restaurantID, err)
+		}
+	}
+
+	if err := s.q.SetSettlementLastPeriodEnd(ctx, sqlc.SetSettlementLastPeriodEndParams{
+		TenantID:      tenantID,
+		LastPeriodEnd: pgDateFromTime(end),
+	}); err != nil {
+		return 0, apperror.Internal("record invoiced period", err)
+	}
+	return len(restaurantIDs), nil
+}
+
+// finalizeReviewedInvoices finalizes draft invoices generated before the
+// given instant.
+func (s *Service) finalizeReviewedInvoices(ctx context.Context, tenantID uuid.UUID, before time.Time) (int, error) {
+	drafts, err := s.q.ListInvoicesDueForFinalize(ctx, sqlc.ListInvoicesDueForFinalizeParams{
+		TenantID: tenantID,
+		Before:   before,
+	})
+	if err != nil {
+		return 0, apperror.Internal("list invoices due for finalization", err)
+	}
+
+	finalized := 0
+	for _, inv := range drafts {
+		_, err := s.q.FinalizeInvoice(ctx, sqlc.FinalizeInvoiceParams{
+			ID:       inv.ID,
+			TenantID: tenantID,
+			Notes:    toNullStringVal(autoFinalizeNote),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return finalized, apperror.Internal("finalize invoice", err)
+		}
+		finalized++
+	}
+	return finalized, nil
+}
@@ -0,0 +1,74 @@
+package finance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestDueInvoicePeriod(t *testing.T) {
+	none := pgtype.Date{}
+	cases := []struct {
+		name       string
+		cycle      string
+		lastEnd    pgtype.Date
+		today      string
+		start, end string
+		ok         bool
+	}{
+		{"weekly mid-week", CycleWeekly, none, "2026-10-21", "2026-10-12", "2026-10-18", true},
+		{"weekly on the boundary", CycleWeekly, none, "2026-10-19", "2026-10-12", "2026-10-18", true},
+		{"fortnightly", CycleFortnightly, none, "2026-10-21", "2026-10-05", "2026-10-18", true},
+		{"fortnightly day before boundary", CycleFortnightly, none, "2026-10-18", "2026-09-21", "2026-10-04", true},
+		{"already invoiced", CycleWeekly, pgDateFromTime(day("2026-10-18")), "2026-10-21", "2026-10-19", "2026-10-18", false},
+		{"catches up missed runs", CycleWeekly, pgDateFromTime(day("2026-09-27")), "2026-10-21", "2026-09-28", "2026-10-18", true},
+		{"after a cycle change", CycleFortnightly, pgDateFromTime(day("2026-10-11")), "2026-10-21", "2026-10-12", "2026-10-18", true},
+	}
+	for _, c := range cases {
+		start, end, ok := dueInvoicePeriod(c.cycle, defaultCycleAnchor, c.lastEnd, day(c.today))
+		if ok != c.ok {
+			t.Errorf("%s: ok = %v, want %v", c.name, ok, c.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got := start.Format("2006-01-02"); got != c.start {
+			t.Errorf("%s: start = %s, want %s", c.name, got, c.start)
+		}
+		if got := end.Format("2006-01-02"); got != c.end {
+			t.Errorf("%s: end = %s, want %s", c.name, got, c.end)
+		}
+	}
+}
+
+func TestDueInvoicePeriodBeforeAnchor(t *testing.T) {
+	anchor := day("2026-11-02")
+	start, end, ok := dueInvoicePeriod(CycleWeekly, anchor, pgtype.Date{}, day("2026-10-21"))
+	if !ok || start.Format("2006-01-02") != "2026-10-12" || end.Format("2006-01-02") != "2026-10-18" {
+		t.Errorf("period = %s..%s (%v), want 2026-10-12..2026-10-18", start.Format("2006-01-02"), end.Format("2006-01-02"), ok)
+	}
+}
+
+func TestSettlementWindow(t *testing.T) {
+	from, to := settlementWindow(day("2026-10-12"), day("2026-10-18"))
+	wantFrom := time.Date(2026, 10, 12, 0, 0, 0, 0, timeutil.BangladeshLocation)
+	wantTo := time.Date(2026, 10, 19, 0, 0, 0, 0, timeutil.BangladeshLocation)
+	if !from.Equal(wantFrom) || !to.Equal(wantTo) {
+		t.Errorf("window = %s..%s, want %s..%s", from, to, wantFrom, wantTo)
+	}
+	// Midnight in Dhaka is 18:00 UTC the day before.
+	if from.UTC().Hour() != 18 || from.UTC().Day() != 11 {
+		t.Errorf("from in UTC = %s, want 2026-10-11 18:00", from.UTC())
+	}
+}
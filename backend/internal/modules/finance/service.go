@@ -5,11 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
@@ -18,17 +21,25 @@ import (
 
 // Service implements invoice and finance business logic.
 type Service struct {
-	q *sqlc.Queries
+	q    *sqlc.Queries
+	pool *pgxpool.Pool
 }
 
 // NewService creates a new finance service.
-func NewService(q *sqlc.Queries) *Service {
-	return &Service{q: q}
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool) *Service {
+	return &Service{q: q, pool: pool}
 }
 
 // GenerateForRestaurant generates an invoice for a restaurant for a given period.
 // It is idempotent: if an invoice already exists for the same restaurant+period, it returns the existing one.
+// The invoice, its line items and the settled adjustments are written in one transaction.
+// periodStart and periodEnd are inclusive Asia/Dhaka dates.
 func (s *Service) GenerateForRestaurant(ctx context.Context, tenantID, restaurantID uuid.UUID, periodStart, periodEnd time.Time, generatedBy *uuid.UUID) (*sqlc.Invoice, error) {
+	if periodEnd.Before(periodStart) {
+		return nil, apperror.BadRequest("period_end must not be before period_start")
+	}
+	from, to := settlementWindow(periodStart, periodEnd)
+
 	// Check for existing invoice (idempotent)
 	existing, err := s.q.GetInvoiceByPeriod(ctx, sqlc.GetInvoiceByPeriodParams{
 		TenantID:     tenantID,
@@ -43,45 +54,43 @@ func (s *Service) GenerateForRestaurant(ctx context.Context, tenantID, restauran
 		return nil, fmt.Errorf("check existing invoice: %w", err)
 	}
 
-	// Get order pickups for this restaurant in the period
-	pickups, err := s.q.GetOrderPickupsByRestaurantAndPeriod(ctx, sqlc.GetOrderPickupsByRestaurantAndPeriodParams{
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	// The restaurant's share of orders delivered in the period
+	pickups, err := qtx.ListSettlementPickups(ctx, sqlc.ListSettlementPickupsParams{
 		TenantID:     tenantID,
 		RestaurantID: restaurantID,
-		PeriodStart:  periodStart,
-		PeriodEnd:    periodEnd,
+		PeriodStart:  from,
+		PeriodEnd:    to,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("get order pickups: %w", err)
+		return nil, fmt.Errorf("list settlement pickups: %w", err)
 	}
 
-	// Calculate invoice amounts from pickups
-	grossSales := decimal.Zero
-	itemDiscounts := decimal.Zero
-	vatCollected := decimal.Zero
-	commissionTotal := decimal.Zero
-	var commissionRate decimal.Decimal
-
-	for _, p := range pickups {
-		grossSales = grossSales.Add(pgNumericToDecimal(p.ItemsSubtotal))
-		itemDiscounts = itemDiscounts.Add(pgNumericToDecimal(p.ItemsDiscount))
-		vatCollected = vatCollected.Add(pgNumericToDecimal(p.ItemsVat))
-		commissionTotal = commissionTotal.Add(pgNumericToDecimal(p.CommissionAmount))
-		commissionRate = pgNumericToDecimal(p.CommissionRate)
+	// Penalties, manual adjustments and refunds charged to the restaurant
+	// that no earlier invoice has settled.
+	adjustments, err := qtx.ListUnsettledInvoiceAdjustments(ctx, sqlc.ListUnsettledInvoiceAdjustmentsParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+		Before:       to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list invoice adjustments: %w", err)
 	}
 
-	vendorPromoDiscounts := decimal.Zero
-	netSales := grossSales.Sub(itemDiscounts).Sub(vendorPromoDiscounts)
-	penaltyAmount := decimal.Zero
-	adjustmentAmount := decimal.Zero
-
-	// net_payable = net_sales + vat_collected - commission_amount - penalty - adjustment
-	netPayable := netSales.Add(vatCollected).Sub(commissionTotal).Sub(penaltyAmount).Sub(adjustmentAmount)
+	st := settle(restaurantID, pickups, adjustments)
 
 	// Get order counts for the period
-	counts, err := s.q.CountOrdersByRestaurantAndPeriod(ctx, sqlc.CountOrdersByRestaurantAndPeriodParams{
-		TenantID:    tenantID,
-		PeriodStart: periodStart,
-		PeriodEnd:   periodEnd,
+	counts, err := qtx.CountOrdersByRestaurantAndPeriod(ctx, sqlc.CountOrdersByRestaurantAndPeriodParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+		PeriodStart:  from,
+		PeriodEnd:    to,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("count orders: %w", err)
@@ -90,22 +99,24 @@ func (s *Service) GenerateForRestaurant(ctx context.Context, tenantID, restauran
 	// Generate invoice number
 	invoiceNumber := fmt.Sprintf("INV-%s-%s", periodStart.Format("20060102"), restaurantID.String()[:8])
 
-	inv, err := s.q.CreateInvoice(ctx, sqlc.CreateInvoiceParams{
+	inv, err := qtx.CreateInvoice(ctx, sqlc.CreateInvoiceParams{
 		TenantID:             tenantID,
 		RestaurantID:         restaurantID,
 		InvoiceNumber:        invoiceNumber,
 		PeriodStart:          pgDateFromTime(periodStart),
 		PeriodEnd:            pgDateFromTime(periodEnd),
-		GrossSales:           toPgNumeric(grossSales),
-		ItemDiscounts:        toPgNumeric(itemDiscounts),
-		VendorPromoDiscounts: toPgNumeric(vendorPromoDiscounts),
-		NetSales:             toPgNumeric(netSales),
-		VatCollected:         toPgNumeric(vatCollected),
-		CommissionRate:       toPgNumeric(commissionRate),
-		CommissionAmount:     toPgNumeric(commissionTotal),
-		PenaltyAmount:        toPgNumeric(penaltyAmount),
-		AdjustmentAmount:     toPgNumeric(adjustmentAmount),
-		NetPayable:           toPgNumeric(netPayable),
+		GrossSales:           toPgNumeric(st.GrossSales),
+		ItemDiscounts:        toPgNumeric(st.ItemDiscounts),
+		VendorPromoDiscounts: toPgNumeric(st.VendorPromoDiscounts),
+		NetSales:             toPgNumeric(st.NetSales),
+		VatCollected:         toPgNumeric(st.VatCollected),
+		CommissionRate:       toPgNumeric(st.CommissionRate),
+		CommissionAmount:     toPgNumeric(st.CommissionAmount),
+		PenaltyAmount:        toPgNumeric(st.PenaltyAmount),
+		AdjustmentAmount:     toPgNumeric(st.AdjustmentAmount),
+		AdjustmentNote:       toNullStringVal(st.AdjustmentNote),
+		RefundAmount:         toPgNumeric(st.RefundAmount),
+		NetPayable:           toPgNumeric(st.NetPayable),
 		TotalOrders:          counts.TotalOrders,
 		DeliveredOrders:      counts.DeliveredOrders,
 		CancelledOrders:      counts.CancelledOrders,
@@ -116,9 +127,171 @@ func (s *Service) GenerateForRestaurant(ctx context.Context, tenantID, restauran
 	if err != nil {
 		return nil, fmt.Errorf("create invoice: %w", err)
 	}
+
+	for _, line := range st.Lines {
+		line.TenantID = tenantID
+		line.InvoiceID = inv.ID
+		if err := qtx.CreateInvoiceLineItem(ctx, line); err != nil {
+			return nil, fmt.Errorf("create invoice line item: %w", err)
+		}
+	}
+	if len(st.AdjustmentIDs) > 0 {
+		attached, err := qtx.AttachInvoiceAdjustments(ctx, sqlc.AttachInvoiceAdjustmentsParams{
+			InvoiceID: toPgUUID(inv.ID),
+			Ids:       st.AdjustmentIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("attach invoice adjustments: %w", err)
+		}
+		if attached != int64(len(st.AdjustmentIDs)) {
+			return nil, apperror.Conflict("invoice adjustments were settled by another invoice run")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit invoice: %w", err)
+	}
 	return &inv, nil
 }
 
+// ListAdjustments returns the penalties, refunds, manual adjustments and
+// credit or debit notes settled on an invoice.
+func (s *Service) ListAdjustments(ctx context.Context, tenantID, invoiceID uuid.UUID) ([]sqlc.InvoiceAdjustment, error) {
+	if _, err := s.GetByID(ctx, tenantID, invoiceID); err != nil {
+		return nil, err
+	}
+	adjustments, err := s.q.ListInvoiceAdjustmentsByInvoice(ctx, sqlc.ListInvoiceAdjustmentsByInvoiceParams{
+		InvoiceID: toPgUUID(invoiceID),
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list invoice adjustments", err)
+	}
+	return adjustments, nil
+}
+
+// CreateAdjustment records a manual adjustment against a restaurant, settled
+// on its next invoice. A positive amount is deducted from the payout and a
+// negative amount is credited to it.
+func (s *Service) CreateAdjustment(ctx context.Context, tenantID, restaurantID uuid.UUID, orderID *uuid.UUID, amount decimal.Decimal, reason string, createdBy uuid.UUID) (*sqlc.InvoiceAdjustment, error) {
+	if amount.IsZero() {
+		return nil, apperror.BadRequest("amount must not be zero")
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, apperror.BadRequest("reason is required")
+	}
+	if _, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: restaurantID, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("restaurant")
+		}
+		return nil, apperror.Internal("get restaurant", err)
+	}
+	if orderID != nil {
+		restaurantIDs, err := s.q.ListOrderRestaurantIDs(ctx, sqlc.ListOrderRestaurantIDsParams{OrderID: *orderID, TenantID: tenantID})
+		if err != nil {
+			return nil, apperror.Internal("list order restaurants", err)
+		}
+		if !slices.Contains(restaurantIDs, restaurantID) {
+			return nil, apperror.BadRequest("order does not include this restaurant")
+		}
+	}
+
+	adj, err := s.q.CreateManualInvoiceAdjustment(ctx, sqlc.CreateManualInvoiceAdjustmentParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+		OrderID:      toPgUUIDPtr(orderID),
+		Amount:       toPgNumeric(amount),
+		Reason:       strings.TrimSpace(reason),
+		CreatedBy:    toPgUUID(createdBy),
+	})
+	if err != nil {
+		return nil, apperror.Internal("create invoice adjustment", err)
+	}
+	return &adj, nil
+}
+
+// ListPendingAdjustments returns a restaurant's adjustments that no invoice
+// has settled yet.
+func (s *Service) ListPendingAdjustments(ctx context.Context, tenantID, restaurantID uuid.UUID) ([]sqlc.InvoiceAdjustment, error) {
+	adjustments, err := s.q.ListPendingInvoiceAdjustments(ctx, sqlc.ListPendingInvoiceAdjustmentsParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list pending adjustments", err)
+	}
+	return adjustments, nil
+}
+
+// ListLineItems returns an invoice's line items, optionally filtered by type.
+func (s *Service) ListLineItems(ctx context.Context, tenantID, invoiceID uuid.UUID, lineType string, page, perPage int) ([]sqlc.InvoiceLineItem, pagination.Meta, error) {
+	if lineType != "" && !IsLineType(lineType) {
+		return nil, pagination.Meta{}, apperror.BadRequest("type must be one of order, penalty, manual, refund, credit_note, debit_note")
+	}
+	if _, err := s.GetByID(ctx, tenantID, invoiceID); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+
+	limit, offset := pagination.FormatLimitOffset(page, perPage)
+	total, err := s.q.CountInvoiceLineItems(ctx, sqlc.CountInvoiceLineItemsParams{
+		InvoiceID: invoiceID,
+		TenantID:  tenantID,
+		LineType:  toNullStringVal(lineType),
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("count invoice line items", err)
+	}
+	items, err := s.q.ListInvoiceLineItems(ctx, sqlc.ListInvoiceLineItemsParams{
+		InvoiceID: invoiceID,
+		TenantID:  tenantID,
+		LineType:  toNullStringVal(lineType),
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("list invoice line items", err)
+	}
+	return items, pagination.NewMeta(total, limit, ""), nil
+}
+
+// InvoiceDocument is the content of an invoice PDF.
+type InvoiceDocument struct {
+	Invoice    *sqlc.Invoice
+	Restaurant sqlc.Restaurant
+	LineItems  []sqlc.InvoiceLineItem
+}
+
+// GetInvoiceDocument loads an invoice with its restaurant and all its line
+// items for rendering.
+func (s *Service) GetInvoiceDocument(ctx context.Context, tenantID, invoiceID uuid.UUID) (*InvoiceDocument, error) {
+	inv, err := s.GetByID(ctx, tenantID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: inv.RestaurantID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("get invoice restaurant", err)
+	}
+
+	doc := &InvoiceDocument{Invoice: inv, Restaurant: rest}
+	const batch = 500
+	for offset := 0; ; offset += batch {
+		items, err := s.q.ListInvoiceLineItems(ctx, sqlc.ListInvoiceLineItemsParams{
+			InvoiceID: invoiceID,
+			TenantID:  tenantID,
+			Limit:     batch,
+			Offset:    int32(offset),
+		})
+		if err != nil {
+			return nil, apperror.Internal("list invoice line items", err)
+		}
+		doc.LineItems = append(doc.LineItems, items...)
+		if len(items) < batch {
+			return doc, nil
+		}
+	}
+}
+
 // GetByID returns an invoice by ID.
 func (s *Service) GetByID(ctx context.Context, tenantID, invoiceID uuid.UUID) (*sqlc.Invoice, error) {
 	inv, err := s.q.GetInvoiceByID(ctx, sqlc.GetInvoiceByIDParams{
@@ -182,9 +355,17 @@ func (s *Service) FinalizeInvoice(ctx context.Context, tenantID, invoiceID, acto
 	return &inv, nil
 }
 
-// MarkPaid marks an invoice as paid.
+// MarkPaid marks an invoice as paid. A payout in flight for the invoice is
+// completed with the same reference so the batch reconciles.
 func (s *Service) MarkPaid(ctx context.Context, tenantID, invoiceID, actorID uuid.UUID, paymentReference string, reason *string) (*sqlc.Invoice, error) {
-	inv, err := s.q.MarkInvoicePaid(ctx, sqlc.MarkInvoicePaidParams{
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	inv, err := qtx.MarkInvoicePaid(ctx, sqlc.MarkInvoicePaidParams{
 		ID:               invoiceID,
 		TenantID:         tenantID,
 		PaidBy:           toPgUUID(actorID),
@@ -197,6 +378,27 @@ func (s *Service) MarkPaid(ctx context.Context, tenantID, invoiceID, actorID uui
 	if err != nil {
 		return nil, err
 	}
+
+	payout, err := qtx.GetOpenVendorPayoutByInvoice(ctx, sqlc.GetOpenVendorPayoutByInvoiceParams{
+		InvoiceID: invoiceID,
+		TenantID:  tenantID,
+	})
+	if err == nil {
+		if _, err := qtx.CompleteVendorPayout(ctx, sqlc.CompleteVendorPayoutParams{
+			PaymentReference: toNullStringVal(paymentReference),
+			ProcessedBy:      toPgUUID(actorID),
+			Note:             toNullStringVal("Invoice marked paid"),
+			ID:               payout.ID,
+		}); err != nil {
+			return nil, apperror.Internal("complete payout", err)
+		}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.Internal("get invoice payout", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
 	return &inv, nil
 }
 
@@ -0,0 +1,189 @@
+package finance
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+// Invoice line types. Adjustment lines use the adjustment's kind.
+const (
+	LineTypeOrder   = "order"
+	LineTypePenalty = string(sqlc.InvoiceAdjustmentKindPenalty)
+	LineTypeManual  = string(sqlc.InvoiceAdjustmentKindManual)
+	LineTypeRefund  = string(sqlc.InvoiceAdjustmentKindRefund)
+
+	LineTypeCreditNote   = string(sqlc.InvoiceAdjustmentKindCreditNote)
+	LineTypeDebitNote    = string(sqlc.InvoiceAdjustmentKindDebitNote)
+	LineTypeCarryForward = string(sqlc.InvoiceAdjustmentKindCarryForward)
+)
+
+// IsLineType reports whether s is a known invoice line type.
+func IsLineType(s string) bool {
+	switch s {
+	case LineTypeOrder, LineTypePenalty, LineTypeManual, LineTypeRefund, LineTypeCreditNote, LineTypeDebitNote, LineTypeCarryForward:
+		return true
+	}
+	return false
+}
+
+// settlement is the computed content of an invoice for one restaurant and
+// period. Line items have no invoice ID until the invoice is created.
+type settlement struct {
+	GrossSales           decimal.Decimal
+	ItemDiscounts        decimal.Decimal
+	VendorPromoDiscounts decimal.Decimal
+	NetSales             decimal.Decimal
+	VatCollected         decimal.Decimal
+	CommissionRate       decimal.Decimal
+	CommissionAmount     decimal.Decimal
+	PenaltyAmount        decimal.Decimal
+	RefundAmount         decimal.Decimal
+	AdjustmentAmount     decimal.Decimal
+	AdjustmentNote       string
+	NetPayable           decimal.Decimal
+	AdjustmentIDs        []uuid.UUID
+	Lines                []sqlc.CreateInvoiceLineItemParams
+}
+
+// settle computes a restaurant's invoice from its delivered pickups and the
+// adjustments waiting to be settled:
+//
+//	net_sales   = gross_sales - item_discounts - vendor_promo_discounts
+//	net_payable = net_sales + vat_collected - commission - penalties - refunds - adjustments
+//
+// Only promos funded by the vendor or restaurant reduce its sales; the
+// platform bears the rest. The commission rate is weighted by each order's
+// commission base so it reflects what was actually charged.
+func settle(restaurantID uuid.UUID, pickups []sqlc.ListSettlementPickupsRow, adjustments []sqlc.InvoiceAdjustment) settlement {
+	var s settlement
+	weightedRate, commissionBase := decimal.Zero, decimal.Zero
+
+	for _, p := range pickups {
+		gross := pgNumericToDecimal(p.ItemsSubtotal)
+		itemDiscount := pgNumericToDecimal(p.ItemsDiscount)
+		vendorPromo := vendorPromoDiscount(p.PromoSnapshot, restaurantID)
+		net := gross.Sub(itemDiscount).Sub(vendorPromo)
+		vat := pgNumericToDecimal(p.ItemsVat)
+		rate := pgNumericToDecimal(p.CommissionRate)
+		commission := pgNumericToDecimal(p.CommissionAmount)
+
+		s.GrossSales = s.GrossSales.Add(gross)
+		s.ItemDiscounts = s.ItemDiscounts.Add(itemDiscount)
+		s.VendorPromoDiscounts = s.VendorPromoDiscounts.Add(vendorPromo)
+		s.VatCollected = s.VatCollected.Add(vat)
+		s.CommissionAmount = s.CommissionAmount.Add(commission)
+
+		base := gross.Sub(itemDiscount)
+		weightedRate = weightedRate.Add(rate.Mul(base))
+		commissionBase = commissionBase.Add(base)
+
+		s.Lines = append(s.Lines, sqlc.CreateInvoiceLineItemParams{
+			LineType:            LineTypeOrder,
+			OrderID:             toPgUUID(p.OrderID),
+			Description:         "Order " + p.OrderNumber,
+			GrossSales:          toPgNumeric(gross),
+			ItemDiscount:        toPgNumeric(itemDiscount),
+			VendorPromoDiscount: toPgNumeric(vendorPromo),
+			NetSales:            toPgNumeric(net),
+			Vat:                 toPgNumeric(vat),
+			CommissionRate:      toPgNumeric(rate),
+			CommissionAmount:    toPgNumeric(commission),
+			Amount:              toPgNumeric(net.Add(vat).Sub(commission)),
+			OccurredAt:          p.DeliveredAt.Time,
+		})
+	}
+	s.NetSales = s.GrossSales.Sub(s.ItemDiscounts).Sub(s.VendorPromoDiscounts)
+	if commissionBase.IsPositive() {
+		s.CommissionRate = weightedRate.Div(commissionBase).Round(2)
+	}
+
+	var notes []string
+	for _, a := range adjustments {
+		amount := pgNumericToDecimal(a.Amount)
+		switch a.Kind {
+		case sqlc.InvoiceAdjustmentKindRefund:
+			s.RefundAmount = s.RefundAmount.Add(amount)
+		case sqlc.InvoiceAdjustmentKindManual, sqlc.InvoiceAdjustmentKindCreditNote, sqlc.InvoiceAdjustmentKindDebitNote,
+			sqlc.InvoiceAdjustmentKindCarryForward:
+			s.AdjustmentAmount = s.AdjustmentAmount.Add(amount)
+			notes = append(notes, a.Reason)
+		default:
+			s.PenaltyAmount = s.PenaltyAmount.Add(amount)
+		}
+		s.AdjustmentIDs = append(s.AdjustmentIDs, a.ID)
+		s.Lines = append(s.Lines, sqlc.CreateInvoiceLineItemParams{
+			LineType:            string(a.Kind),
+			OrderID:             a.OrderID,
+			AdjustmentID:        toPgUUID(a.ID),
+			Description:         a.Reason,
+			GrossSales:          toPgNumeric(decimal.Zero),
+			ItemDiscount:        toPgNumeric(decimal.Zero),
+			VendorPromoDiscount: toPgNumeric(decimal.Zero),
+			NetSales:            toPgNumeric(decimal.Zero),
+			Vat:                 toPgNumeric(decimal.Zero),
+			CommissionRate:      toPgNumeric(decimal.Zero),
+			CommissionAmount:    toPgNumeric(decimal.Zero),
+			Amount:              toPgNumeric(amount.Neg()),
+			OccurredAt:          a.CreatedAt,
+		})
+	}
+	s.AdjustmentNote = strings.Join(notes, "; ")
+
+	s.NetPayable = s.NetSales.Add(s.VatCollected).Sub(s.CommissionAmount).
+		Sub(s.PenaltyAmount).Sub(s.RefundAmount).Sub(s.AdjustmentAmount)
+	return s
+}
+
+// promoSnapshot is the part of orders.promo_snapshot settlement needs: the
+// funder of each applied promo and its discount per cart line.
+type promoSnapshot struct {
+	Promos []snapshotPromo `json:"promos"`
+}
+
+type snapshotPromo struct {
+	FundedBy    sqlc.PromoFunder `json:"funded_by"`
+	Allocations []struct {
+		ProductID    uuid.UUID       `json:"product_id"`
+		RestaurantID uuid.UUID       `json:"restaurant_id"`
+		Discount     decimal.Decimal `json:"discount"`
+	} `json:"allocations"`
+}
+
+// vendorPromoDiscount returns the promo discount on an order that the
+// restaurant funds: allocations to its items from vendor- or
+// restaurant-funded promos. Delivery discounts are never charged to it.
+func vendorPromoDiscount(snapshot []byte, restaurantID uuid.UUID) decimal.Decimal {
+	total := decimal.Zero
+	for _, d := range VendorPromoDiscountByProduct(snapshot, restaurantID) {
+		total = total.Add(d)
+	}
+	return total
+}
+
+// VendorPromoDiscountByProduct splits the promo discount a restaurant funds
+// on an order by product. Tax invoices take it off the restaurant's supply.
+func VendorPromoDiscountByProduct(snapshot []byte, restaurantID uuid.UUID) map[uuid.UUID]decimal.Decimal {
+	out := map[uuid.UUID]decimal.Decimal{}
+	if len(snapshot) == 0 {
+		return out
+	}
+	var snap promoSnapshot
+	if err := json.Unmarshal(snapshot, &snap); err != nil {
+		return out
+	}
+	for _, p := range snap.Promos {
+		if p.FundedBy != sqlc.PromoFunderVendor && p.FundedBy != sqlc.PromoFunderRestaurant {
+			continue
+		}
+		for _, a := range p.Allocations {
+			if a.RestaurantID == restaurantID {
+				out[a.ProductID] = out[a.ProductID].Add(a.Discount)
+			}
+		}
+	}
+	return out
+}
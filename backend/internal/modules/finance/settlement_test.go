@@ -0,0 +1,152 @@
+package finance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func num(s string) pgtype.Numeric { return toPgNumeric(dec(s)) }
+
+func TestVendorPromoDiscount(t *testing.T) {
+	restaurant, other := uuid.New(), uuid.New()
+	snapshot := []byte(fmt.Sprintf(`{"promos":[
+		{"funded_by":"vendor","allocations":[{"restaurant_id":%q,"discount":"30"},{"restaurant_id":%q,"discount":"20"}]},
+		{"funded_by":"platform","allocations":[{"restaurant_id":%q,"discount":"50"}]},
+		{"funded_by":"restaurant","allocations":[{"restaurant_id":%q,"discount":"12.50"}]}
+	]}`, restaurant, other, restaurant, restaurant))
+
+	if got := vendorPromoDiscount(snapshot, restaurant); !got.Equal(dec("42.50")) {
+		t.Errorf("vendorPromoDiscount = %s, want 42.50", got)
+	}
+	if got := vendorPromoDiscount(snapshot, other); !got.Equal(dec("20")) {
+		t.Errorf("other restaurant = %s, want 20", got)
+	}
+	if got := vendorPromoDiscount(nil, restaurant); !got.IsZero() {
+		t.Errorf("no snapshot = %s, want 0", got)
+	}
+}
+
+func TestSettle(t *testing.T) {
+	restaurant := uuid.New()
+	delivered := pgtype.Timestamptz{Time: time.Date(2026, 6, 3, 12, 0, 0, 0, time.UTC), Valid: true}
+	snapshot := []byte(fmt.Sprintf(`{"promos":[{"funded_by":"vendor","allocations":[{"restaurant_id":%q,"discount":"50"}]}]}`, restaurant))
+	pickups := []sqlc.ListSettlementPickupsRow{
+		{
+			OrderID: uuid.New(), OrderNumber: "A-1", DeliveredAt: delivered,
+			ItemsSubtotal: num("1000"), ItemsDiscount: num("100"), ItemsVat: num("45"),
+			CommissionRate: num("10"), CommissionAmount: num("90"), PromoSnapshot: snapshot,
+		},
+		{
+			OrderID: uuid.New(), OrderNumber: "A-2", DeliveredAt: delivered,
+			ItemsSubtotal: num("300"), ItemsDiscount: num("0"), ItemsVat: num("15"),
+			CommissionRate: num("20"), CommissionAmount: num("60"),
+		},
+	}
+	adjustments := []sqlc.InvoiceAdjustment{
+		{ID: uuid.New(), Kind: sqlc.InvoiceAdjustmentKindPenalty, Amount: num("25"), Reason: "Missing item"},
+		{ID: uuid.New(), Kind: sqlc.InvoiceAdjustmentKindRefund, Amount: num("40"), Reason: "Refund: cold food"},
+		{ID: uuid.New(), Kind: sqlc.InvoiceAdjustmentKindManual, Amount: num("-15"), Reason: "Packaging credit"},
+		{ID: uuid.New(), Kind: sqlc.InvoiceAdjustmentKindManual, Amount: num("5"), Reason: "Tablet rental"},
+	}
+
+	s := settle(restaurant, pickups, adjustments)
+
+	checks := []struct {
+		name string
+		got  decimal.Decimal
+		want string
+	}{
+		{"gross sales", s.GrossSales, "1300"},
+		{"item discounts", s.ItemDiscounts, "100"},
+		{"vendor promo discounts", s.VendorPromoDiscounts, "50"},
+		{"net sales", s.NetSales, "1150"},
+		{"vat", s.VatCollected, "60"},
+		{"commission", s.CommissionAmount, "150"},
+		// (10×900 + 20×300) / 1200
+		{"commission rate", s.CommissionRate, "12.5"},
+		{"penalties", s.PenaltyAmount, "25"},
+		{"refunds", s.RefundAmount, "40"},
+		{"adjustments", s.AdjustmentAmount, "-10"},
+		// 1150 + 60 − 150 − 25 − 40 + 10
+		{"net payable", s.NetPayable, "1005"},
+	}
+	for _, c := range checks {
+		if !c.got.Equal(dec(c.want)) {
+			t.Errorf("%s = %s, want %s", c.name, c.got, c.want)
+		}
+	}
+
+	if s.AdjustmentNote != "Packaging credit; Tablet rental" {
+		t.Errorf("adjustment note = %q", s.AdjustmentNote)
+	}
+	if len(s.AdjustmentIDs) != len(adjustments) {
+		t.Errorf("settled %d adjustments, want %d", len(s.AdjustmentIDs), len(adjustments))
+	}
+	if len(s.Lines) != len(pickups)+len(adjustments) {
+		t.Fatalf("got %d lines, want %d", len(s.Lines), len(pickups)+len(adjustments))
+	}
+
+	// Line items reconcile to the invoice total.
+	sum := decimal.Zero
+	for _, l := range s.Lines {
+		sum = sum.Add(pgNumericToDecimal(l.Amount))
+	}
+	if !sum.Equal(s.NetPayable) {
+		t.Errorf("line items sum to %s, want net payable %s", sum, s.NetPayable)
+	}
+	if first := s.Lines[0]; first.LineType != LineTypeOrder || !pgNumericToDecimal(first.NetSales).Equal(dec("850")) {
+		t.Errorf("first line = %s net %s, want order net 850", first.LineType, pgNumericToDecimal(first.NetSales))
+	}
+}
+
+func TestSettleNotes(t *testing.T) {
+	creditKind, creditAmount := noteAdjustment(sqlc.InvoiceNoteTypeCredit, dec("120"))
+	debitKind, debitAmount := noteAdjustment(sqlc.InvoiceNoteTypeDebit, dec("30"))
+	adjustments := []sqlc.InvoiceAdjustment{
+		{ID: uuid.New(), Kind: creditKind, Amount: toPgNumeric(creditAmount), Reason: "Credit note CN-000001 for INV-20261001-1a2b3c4d: commission overcharged"},
+		{ID: uuid.New(), Kind: debitKind, Amount: toPgNumeric(debitAmount), Reason: "Debit note DN-000001 for INV-20261001-1a2b3c4d: packaging not billed"},
+	}
+
+	s := settle(uuid.New(), nil, adjustments)
+
+	if !s.AdjustmentAmount.Equal(dec("-90")) || !s.NetPayable.Equal(dec("90")) {
+		t.Errorf("adjustments %s net payable %s, want -90 and 90", s.AdjustmentAmount, s.NetPayable)
+	}
+	if !s.PenaltyAmount.IsZero() || !s.RefundAmount.IsZero() {
+		t.Errorf("notes settled as penalties %s or refunds %s", s.PenaltyAmount, s.RefundAmount)
+	}
+	if s.Lines[0].LineType != LineTypeCreditNote || !pgNumericToDecimal(s.Lines[0].Amount).Equal(dec("120")) {
+		t.Errorf("credit note line = %s %s, want credit_note 120", s.Lines[0].LineType, pgNumericToDecimal(s.Lines[0].Amount))
+	}
+	if s.Lines[1].LineType != LineTypeDebitNote || !pgNumericToDecimal(s.Lines[1].Amount).Equal(dec("-30")) {
+		t.Errorf("debit note line = %s %s, want debit_note -30", s.Lines[1].LineType, pgNumericToDecimal(s.Lines[1].Amount))
+	}
+	for _, l := range s.Lines {
+		if !IsLineType(l.LineType) || lineTypeLabels[l.LineType] == "" {
+			t.Errorf("line type %q is not known", l.LineType)
+		}
+	}
+}
+
+func TestSettleCarryForward(t *testing.T) {
+	adjustments := []sqlc.InvoiceAdjustment{
+		{ID: uuid.New(), Kind: sqlc.InvoiceAdjustmentKindCarryForward, Amount: toPgNumeric(dec("75")), Reason: "Balance carried forward from INV-20261001-1a2b3c4d"},
+	}
+
+	s := settle(uuid.New(), nil, adjustments)
+
+	if !s.AdjustmentAmount.Equal(dec("75")) || !s.NetPayable.Equal(dec("-75")) {
+		t.Errorf("adjustments %s net payable %s, want 75 and -75", s.AdjustmentAmount, s.NetPayable)
+	}
+	if l := s.Lines[0]; l.LineType != LineTypeCarryForward || !IsLineType(l.LineType) || lineTypeLabels[l.LineType] == "" {
+		t.Errorf("carry forward line type %q is not known", l.LineType)
+	}
+}
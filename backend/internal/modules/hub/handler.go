@@ -47,15 +47,18 @@ func (h *Handler) CreateHub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name         string  `json:"name"`
-		Code         *string `json:"code"`
-		AddressLine1 *string `json:"address_line1"`
-		AddressLine2 *string `json:"address_line2"`
-		City         string  `json:"city"`
-		ContactPhone *string `json:"contact_phone"`
-		ContactEmail *string `json:"contact_email"`
-		IsActive     bool    `json:"is_active"`
-		SortOrder    int32   `json:"sort_order"`
+		Name           string         `json:"name"`
+		Code           *string        `json:"code"`
+		AddressLine1   *string        `json:"address_line1"`
+		AddressLine2   *string        `json:"address_line2"`
+		City           string         `json:"city"`
+		ContactPhone   *string        `json:"contact_phone"`
+		ContactEmail   *string        `json:"contact_email"`
+		IsActive       bool           `json:"is_active"`
+		SortOrder      int32          `json:"sort_order"`
+		ManagerID      *uuid.UUID     `json:"manager_id"`
+		RiderCashLimit pgtype.Numeric `json:"rider_cash_limit"`
+		CheckinRadiusM *int32         `json:"checkin_radius_m"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -70,15 +73,18 @@ func (h *Handler) CreateHub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	hub, err := h.svc.CreateHub(r.Context(), t.ID, CreateHubRequest{
-		Name:         req.Name,
-		Code:         req.Code,
-		AddressLine1: req.AddressLine1,
-		AddressLine2: req.AddressLine2,
-		City:         req.City,
-		ContactPhone: req.ContactPhone,
-		ContactEmail: req.ContactEmail,
-		IsActive:     req.IsActive,
-		SortOrder:    req.SortOrder,
+		Name:           req.Name,
+		Code:           req.Code,
+		AddressLine1:   req.AddressLine1,
+		AddressLine2:   req.AddressLine2,
+		City:           req.City,
+		ContactPhone:   req.ContactPhone,
+		ContactEmail:   req.ContactEmail,
+		IsActive:       req.IsActive,
+		SortOrder:      req.SortOrder,
+		ManagerID:      req.ManagerID,
+		RiderCashLimit: req.RiderCashLimit,
+		CheckinRadiusM: req.CheckinRadiusM,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -121,15 +127,18 @@ func (h *Handler) UpdateHub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name         *string `json:"name"`
-		Code         *string `json:"code"`
-		AddressLine1 *string `json:"address_line1"`
-		AddressLine2 *string `json:"address_line2"`
-		City         *string `json:"city"`
-		ContactPhone *string `json:"contact_phone"`
-		ContactEmail *string `json:"contact_email"`
-		IsActive     *bool   `json:"is_active"`
-		SortOrder    *int32  `json:"sort_order"`
+		Name           *string        `json:"name"`
+		Code           *string        `json:"code"`
+		AddressLine1   *string        `json:"address_line1"`
+		AddressLine2   *string        `json:"address_line2"`
+		City           *string        `json:"city"`
+		ContactPhone   *string        `json:"contact_phone"`
+		ContactEmail   *string        `json:"contact_email"`
+		IsActive       *bool          `json:"is_active"`
+		SortOrder      *int32         `json:"sort_order"`
+		ManagerID      *uuid.UUID     `json:"manager_id"`
+		RiderCashLimit pgtype.Numeric `json:"rider_cash_limit"`
+		CheckinRadiusM *int32         `json:"checkin_radius_m"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -137,15 +146,18 @@ func (h *Handler) UpdateHub(w http.ResponseWriter, r *http.Request) {
 	}
 
 	hub, err := h.svc.UpdateHub(r.Context(), id, t.ID, UpdateHubRequest{
-		Name:         req.Name,
-		Code:         req.Code,
-		AddressLine1: req.AddressLine1,
-		AddressLine2: req.AddressLine2,
-		City:         req.City,
-		ContactPhone: req.ContactPhone,
-		ContactEmail: req.ContactEmail,
-		IsActive:     req.IsActive,
-		SortOrder:    req.SortOrder,
+		Name:           req.Name,
+		Code:           req.Code,
+		AddressLine1:   req.AddressLine1,
+		AddressLine2:   req.AddressLine2,
+		City:           req.City,
+		ContactPhone:   req.ContactPhone,
+		ContactEmail:   req.ContactEmail,
+		IsActive:       req.IsActive,
+		SortOrder:      req.SortOrder,
+		ManagerID:      req.ManagerID,
+		RiderCashLimit: req.RiderCashLimit,
+		CheckinRadiusM: req.CheckinRadiusM,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -312,6 +324,10 @@ func (h *Handler) UpsertDeliveryZoneConfig(w http.ResponseWriter, r *http.Reques
 		Model                 sqlc.DeliveryModel `json:"model"`
 		DistanceTiers         json.RawMessage    `json:"distance_tiers"`
 		FreeDeliveryThreshold pgtype.Numeric     `json:"free_delivery_threshold"`
+		PodOtpEnabled         bool               `json:"pod_otp_enabled"`
+		PodPhotoRequired      bool               `json:"pod_photo_required"`
+		PodGeofenceRadiusM    *int32             `json:"pod_geofence_radius_m"`
+		PodMinOrderValue      pgtype.Numeric     `json:"pod_min_order_value"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -322,6 +338,10 @@ func (h *Handler) UpsertDeliveryZoneConfig(w http.ResponseWriter, r *http.Reques
 		Model:                 req.Model,
 		DistanceTiers:         req.DistanceTiers,
 		FreeDeliveryThreshold: req.FreeDeliveryThreshold,
+		PodOtpEnabled:         req.PodOtpEnabled,
+		PodPhotoRequired:      req.PodPhotoRequired,
+		PodGeofenceRadiusM:    req.PodGeofenceRadiusM,
+		PodMinOrderValue:      req.PodMinOrderValue,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
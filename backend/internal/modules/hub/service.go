@@ -26,30 +26,42 @@ func NewService(repo *Repository) *Service {
 
 // CreateHubRequest holds fields for creating a hub.
 type CreateHubRequest struct {
-	Name         string
-	Code         *string
-	AddressLine1 *string
-	AddressLine2 *string
-	City         string
-	ContactPhone *string
-	ContactEmail *string
-	IsActive     bool
-	SortOrder    int32
+	Name           string
+	Code           *string
+	AddressLine1   *string
+	AddressLine2   *string
+	City           string
+	ContactPhone   *string
+	ContactEmail   *string
+	IsActive       bool
+	SortOrder      int32
+	ManagerID      *uuid.UUID
+	RiderCashLimit pgtype.Numeric
+	CheckinRadiusM *int32
 }
 
 // CreateHub creates a new hub for the tenant.
 func (s *Service) CreateHub(ctx context.Context, tenantID uuid.UUID, req CreateHubRequest) (*sqlc.Hub, error) {
+	if negative(req.RiderCashLimit) {
+		return nil, apperror.BadRequest("rider_cash_limit cannot be negative")
+	}
+	if req.CheckinRadiusM != nil && *req.CheckinRadiusM <= 0 {
+		return nil, apperror.BadRequest("checkin_radius_m must be positive")
+	}
 	return s.repo.CreateHub(ctx, sqlc.CreateHubParams{
-		TenantID:     tenantID,
-		Name:         req.Name,
-		Code:         nullString(req.Code),
-		AddressLine1: nullString(req.AddressLine1),
-		AddressLine2: nullString(req.AddressLine2),
-		City:         req.City,
-		ContactPhone: nullString(req.ContactPhone),
-		ContactEmail: nullString(req.ContactEmail),
-		IsActive:     req.IsActive,
-		SortOrder:    req.SortOrder,
+		TenantID:       tenantID,
+		Name:           req.Name,
+		Code:           nullString(req.Code),
+		AddressLine1:   nullString(req.AddressLine1),
+		AddressLine2:   nullString(req.AddressLine2),
+		City:           req.City,
+		ContactPhone:   nullString(req.ContactPhone),
+		ContactEmail:   nullString(req.ContactEmail),
+		IsActive:       req.IsActive,
+		SortOrder:      req.SortOrder,
+		ManagerID:      nullUUID(req.ManagerID),
+		RiderCashLimit: req.RiderCashLimit,
+		CheckinRadiusM: req.CheckinRadiusM,
 	})
 }
 
@@ -69,31 +81,43 @@ func (s *Service) ListHubs(ctx context.Context, tenantID uuid.UUID) ([]sqlc.Hub,
 
 // UpdateHubRequest holds updateable hub fields.
 type UpdateHubRequest struct {
-	Name         *string
-	Code         *string
-	AddressLine1 *string
-	AddressLine2 *string
-	City         *string
-	ContactPhone *string
-	ContactEmail *string
-	IsActive     *bool
-	SortOrder    *int32
+	Name           *string
+	Code           *string
+	AddressLine1   *string
+	AddressLine2   *string
+	City           *string
+	ContactPhone   *string
+	ContactEmail   *string
+	IsActive       *bool
+	SortOrder      *int32
+	ManagerID      *uuid.UUID
+	RiderCashLimit pgtype.Numeric
+	CheckinRadiusM *int32
 }
 
 // UpdateHub updates a hub.
 func (s *Service) UpdateHub(ctx context.Context, id, tenantID uuid.UUID, req UpdateHubRequest) (*sqlc.Hub, error) {
+	if negative(req.RiderCashLimit) {
+		return nil, apperror.BadRequest("rider_cash_limit cannot be negative")
+	}
+	if req.CheckinRadiusM != nil && *req.CheckinRadiusM <= 0 {
+		return nil, apperror.BadRequest("checkin_radius_m must be positive")
+	}
 	h, err := s.repo.UpdateHub(ctx, sqlc.UpdateHubParams{
-		ID:           id,
-		TenantID:     tenantID,
-		Name:         nullString(req.Name),
-		Code:         nullString(req.Code),
-		AddressLine1: nullString(req.AddressLine1),
-		AddressLine2: nullString(req.AddressLine2),
-		City:         nullString(req.City),
-		ContactPhone: nullString(req.ContactPhone),
-		ContactEmail: nullString(req.ContactEmail),
-		IsActive:     req.IsActive,
-		SortOrder:    req.SortOrder,
+		ID:             id,
+		TenantID:       tenantID,
+		Name:           nullString(req.Name),
+		Code:           nullString(req.Code),
+		AddressLine1:   nullString(req.AddressLine1),
+		AddressLine2:   nullString(req.AddressLine2),
+		City:           nullString(req.City),
+		ContactPhone:   nullString(req.ContactPhone),
+		ContactEmail:   nullString(req.ContactEmail),
+		IsActive:       req.IsActive,
+		SortOrder:      req.SortOrder,
+		ManagerID:      nullUUID(req.ManagerID),
+		RiderCashLimit: req.RiderCashLimit,
+		CheckinRadiusM: req.CheckinRadiusM,
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, apperror.NotFound("hub")
@@ -185,15 +209,29 @@ type UpsertDeliveryZoneConfigRequest struct {
 	Model                 sqlc.DeliveryModel
 	DistanceTiers         json.RawMessage
 	FreeDeliveryThreshold pgtype.Numeric
+	PodOtpEnabled         bool
+	PodPhotoRequired      bool
+	PodGeofenceRadiusM    *int32
+	PodMinOrderValue      pgtype.Numeric
 }
 
 // UpsertDeliveryZoneConfig upserts the delivery zone config for a tenant.
 func (s *Service) UpsertDeliveryZoneConfig(ctx context.Context, tenantID uuid.UUID, req UpsertDeliveryZoneConfigRequest) (*sqlc.DeliveryZoneConfig, error) {
+	if req.PodGeofenceRadiusM != nil && *req.PodGeofenceRadiusM <= 0 {
+		return nil, apperror.BadRequest("pod_geofence_radius_m must be positive")
+	}
+	if negative(req.PodMinOrderValue) {
+		return nil, apperror.BadRequest("pod_min_order_value cannot be negative")
+	}
 	return s.repo.UpsertDeliveryZoneConfig(ctx, sqlc.UpsertDeliveryZoneConfigParams{
 		TenantID:              tenantID,
 		Model:                 req.Model,
 		DistanceTiers:         req.DistanceTiers,
 		FreeDeliveryThreshold: req.FreeDeliveryThreshold,
+		PodOtpEnabled:         req.PodOtpEnabled,
+		PodPhotoRequired:      req.PodPhotoRequired,
+		PodGeofenceRadiusM:    req.PodGeofenceRadiusM,
+		PodMinOrderValue:      req.PodMinOrderValue,
 	})
 }
 
@@ -212,3 +250,14 @@ func nullString(s *string) sql.NullString {
 	}
 	return sql.NullString{String: *s, Valid: true}
 }
+
+func nullUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
+
+func negative(n pgtype.Numeric) bool {
+	return n.Valid && n.Int != nil && n.Int.Sign() < 0
+}
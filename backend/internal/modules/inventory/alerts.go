@@ -0,0 +1,172 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+// Stock level event types written to the outbox.
+const (
+	EventLowStock   = "inventory.low_stock"
+	EventOutOfStock = "inventory.out_of_stock"
+	EventRestocked  = "inventory.restocked"
+)
+
+// Notifier delivers staff alerts. Satisfied by notification.Service.
+type Notifier interface {
+	SendPush(ctx context.Context, tenantID *uuid.UUID, userID uuid.UUID, title, body string, data map[string]string) error
+}
+
+// StockEvent is the outbox payload for stock level changes.
+type StockEvent struct {
+	InventoryItemID  uuid.UUID `json:"inventory_item_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	RestaurantID     uuid.UUID `json:"restaurant_id"`
+	TenantID         uuid.UUID `json:"tenant_id"`
+	AvailableQty     int32     `json:"available_qty"`
+	ReorderThreshold int32     `json:"reorder_threshold"`
+}
+
+// syncStockLevel reacts to a change in sellable stock (stock_qty - reserved_qty).
+// It flips product availability when stock runs out or comes back and writes
+// outbox events when the reorder threshold is crossed. Pass the transaction's
+// queries so product updates and events commit together with the stock change.
+func (s *Service) syncStockLevel(ctx context.Context, q *sqlc.Queries, item sqlc.InventoryItem, prevAvailable int32) error {
+	available := item.StockQty - item.ReservedQty
+
+	events := stockEvents(prevAvailable, available, item.ReorderThreshold)
+	for _, eventType := range events {
+		switch eventType {
+		case EventOutOfStock:
+			if err := q.UpdateProductStockAvailability(ctx, sqlc.UpdateProductStockAvailabilityParams{
+				Availability:        sqlc.ProductAvailOutOfStock,
+				ID:                  item.ProductID,
+				TenantID:            item.TenantID,
+				CurrentAvailability: sqlc.ProductAvailAvailable,
+			}); err != nil {
+				return fmt.Errorf("mark product out of stock: %w", err)
+			}
+		case EventRestocked:
+			// Only products we flipped to out_of_stock come back; manually
+			// unavailable products stay hidden.
+			if err := q.UpdateProductStockAvailability(ctx, sqlc.UpdateProductStockAvailabilityParams{
+				Availability:        sqlc.ProductAvailAvailable,
+				ID:                  item.ProductID,
+				TenantID:            item.TenantID,
+				CurrentAvailability: sqlc.ProductAvailOutOfStock,
+			}); err != nil {
+				return fmt.Errorf("mark product available: %w", err)
+			}
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	payload, _ := json.Marshal(StockEvent{
+		InventoryItemID:  item.ID,
+		ProductID:        item.ProductID,
+		RestaurantID:     item.RestaurantID,
+		TenantID:         item.TenantID,
+		AvailableQty:     available,
+		ReorderThreshold: item.ReorderThreshold,
+	})
+	for _, eventType := range events {
+		if _, err := q.CreateOutboxEvent(ctx, sqlc.CreateOutboxEventParams{
+			TenantID:      pgtype.UUID{Bytes: item.TenantID, Valid: true},
+			AggregateType: "tenant",
+			AggregateID:   item.TenantID,
+			EventType:     eventType,
+			Payload:       payload,
+			MaxAttempts:   5,
+		}); err != nil {
+			return fmt.Errorf("create stock outbox event: %w", err)
+		}
+	}
+	return nil
+}
+
+// stockEvents returns the stock level events a change in sellable stock
+// from prevAvailable to available crosses: running out, coming back, and
+// dropping to the reorder threshold. Running out also switches the product
+// to out_of_stock and coming back switches it to available again.
+func stockEvents(prevAvailable, available, reorderThreshold int32) []string {
+	var events []string
+	switch {
+	case prevAvailable > 0 && available <= 0:
+		events = append(events, EventOutOfStock)
+	case prevAvailable <= 0 && available > 0:
+		events = append(events, EventRestocked)
+	}
+	if available > 0 && available <= reorderThreshold && prevAvailable > reorderThreshold {
+		events = append(events, EventLowStock)
+	}
+	return events
+}
+
+// HandleStockEvent sends push alerts to the restaurant's staff for a stock
+// level outbox event. Registered with the background worker.
+func (s *Service) HandleStockEvent(ctx context.Context, event sqlc.OutboxEvent) error {
+	if s.notifier == nil {
+		return nil
+	}
+
+	var ev StockEvent
+	if err := json.Unmarshal(event.Payload, &ev); err != nil {
+		// Malformed payloads can never succeed; drop them.
+		log.Error().Err(err).Str("event_id", event.ID.String()).Msg("invalid stock event payload")
+		return nil
+	}
+
+	product, err := s.q.GetProductByID(ctx, sqlc.GetProductByIDParams{
+		ID:       ev.ProductID,
+		TenantID: ev.TenantID,
+	})
+	if err != nil {
+		return fmt.Errorf("get product: %w", err)
+	}
+
+	var title, body string
+	switch event.EventType {
+	case EventLowStock:
+		title = "Low stock"
+		body = fmt.Sprintf("%s is running low: %d left (reorder at %d).", product.Name, ev.AvailableQty, ev.ReorderThreshold)
+	case EventOutOfStock:
+		title = "Out of stock"
+		body = fmt.Sprintf("%s is out of stock and hidden from customers.", product.Name)
+	case EventRestocked:
+		title = "Back in stock"
+		body = fmt.Sprintf("%s is back in stock (%d available).", product.Name, ev.AvailableQty)
+	default:
+		return nil
+	}
+
+	staff, err := s.q.ListRestaurantStaffUserIDs(ctx, sqlc.ListRestaurantStaffUserIDsParams{
+		RestaurantID: ev.RestaurantID,
+		TenantID:     ev.TenantID,
+	})
+	if err != nil {
+		return fmt.Errorf("list restaurant staff: %w", err)
+	}
+
+	data := map[string]string{
+		"action_type":       event.EventType,
+		"inventory_item_id": ev.InventoryItemID.String(),
+		"product_id":        ev.ProductID.String(),
+		"restaurant_id":     ev.RestaurantID.String(),
+	}
+	tenantID := ev.TenantID
+	for _, userID := range staff {
+		if err := s.notifier.SendPush(ctx, &tenantID, userID, title, body, data); err != nil {
+			log.Warn().Err(err).Str("user_id", userID.String()).Msg("stock alert push failed")
+		}
+	}
+	return nil
+}
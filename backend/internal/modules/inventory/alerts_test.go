@@ -0,0 +1,44 @@
+package inventory
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStockEvents(t *testing.T) {
+	cases := []struct {
+		name            string
+		prev, available int32
+		threshold       int32
+		want            []string
+	}{
+		{"sells out", 3, 0, 5, []string{EventOutOfStock}},
+		{"oversold", 1, -2, 5, []string{EventOutOfStock}},
+		{"stays out", 0, 0, 5, nil},
+		{"restocked above threshold", 0, 20, 5, []string{EventRestocked}},
+		{"restocked below threshold", 0, 3, 5, []string{EventRestocked}},
+		{"drops to threshold", 6, 5, 5, []string{EventLowStock}},
+		{"drops below threshold", 10, 2, 5, []string{EventLowStock}},
+		{"already low", 4, 3, 5, nil},
+		{"stays above threshold", 20, 10, 5, nil},
+		{"back above threshold", 3, 12, 5, nil},
+		{"no threshold", 2, 1, 0, nil},
+	}
+	for _, c := range cases {
+		if got := stockEvents(c.prev, c.available, c.threshold); !slices.Equal(got, c.want) {
+			t.Errorf("%s: stockEvents(%d, %d, %d) = %v, want %v", c.name, c.prev, c.available, c.threshold, got, c.want)
+		}
+	}
+}
+
+func TestDaysOfCover(t *testing.T) {
+	if got := daysOfCover(10, 0); got != nil {
+		t.Errorf("no usage = %v, want nil", *got)
+	}
+	if got := daysOfCover(10, 3); got == nil || *got != 3.3 {
+		t.Errorf("10 at 3/day = %v, want 3.3", got)
+	}
+	if got := daysOfCover(-4, 2); got == nil || *got != 0 {
+		t.Errorf("oversold = %v, want 0", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
@@ -154,11 +155,30 @@ func (h *Handler) ListLowStock(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, pagination.PagedResponse{Data: items, Meta: meta})
 }
 
-// RegisterRoutes registers inventory routes on the given router.
-func (h *Handler) RegisterRoutes(r chi.Router) {
-	r.Get("/", h.ListInventory)
-	r.Post("/adjust", h.AdjustStock)
-	r.Get("/low-stock", h.ListLowStock)
+// RegisterRoutes registers inventory routes on the given router. Stock and
+// purchase orders are limited to the caller's restaurants; suppliers are
+// shared across the tenant and only owners and admins may change them.
+func (h *Handler) RegisterRoutes(r chi.Router, policy *access.Policy) {
+	byQuery := policy.RequireRestaurant(access.Query("restaurant_id"))
+	byBody := policy.RequireRestaurant(access.Body("restaurant_id"))
+	byPurchaseOrder := policy.RequireRestaurant(policy.PurchaseOrder("id"))
+	tenantAdmins := auth.RequireRoles(sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin)
+
+	r.With(byQuery).Get("/", h.ListInventory)
+	r.With(byBody).Post("/adjust", h.AdjustStock)
+	r.With(byQuery).Get("/low-stock", h.ListLowStock)
+	r.With(byQuery).Get("/valuation", h.GetStockValuation)
+
+	r.Get("/suppliers", h.ListSuppliers)
+	r.With(tenantAdmins).Post("/suppliers", h.CreateSupplier)
+	r.With(tenantAdmins).Patch("/suppliers/{id}", h.UpdateSupplier)
+
+	r.With(byQuery).Get("/purchase-orders", h.ListPurchaseOrders)
+	r.With(byBody).Post("/purchase-orders", h.CreatePurchaseOrder)
+	r.With(byPurchaseOrder).Get("/purchase-orders/{id}", h.GetPurchaseOrder)
+	r.With(byPurchaseOrder).Post("/purchase-orders/{id}/send", h.SendPurchaseOrder)
+	r.With(byPurchaseOrder).Post("/purchase-orders/{id}/receive", h.ReceivePurchaseOrder)
+	r.With(byPurchaseOrder).Post("/purchase-orders/{id}/cancel", h.CancelPurchaseOrder)
 }
 
 func parsePagination(r *http.Request) (page, perPage int) {
@@ -0,0 +1,617 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/pagination"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
+)
+
+// ---- Suppliers ----
+
+// SupplierRequest holds fields for creating or updating a supplier.
+// On update, nil fields are left unchanged.
+type SupplierRequest struct {
+	Name         *string
+	ContactName  *string
+	Phone        *string
+	Email        *string
+	Address      *string
+	LeadTimeDays *int32
+	Notes        *string
+	IsActive     *bool
+}
+
+// CreateSupplier registers a new supplier for the tenant.
+func (s *Service) CreateSupplier(ctx context.Context, tenantID uuid.UUID, req SupplierRequest) (*sqlc.Supplier, error) {
+	if req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		return nil, apperror.BadRequest("supplier name is required")
+	}
+	leadTime := int32(1)
+	if req.LeadTimeDays != nil {
+		if *req.LeadTimeDays < 0 {
+			return nil, apperror.BadRequest("lead_time_days cannot be negative")
+		}
+		leadTime = *req.LeadTimeDays
+	}
+	supplier, err := s.q.CreateSupplier(ctx, sqlc.CreateSupplierParams{
+		TenantID:     tenantID,
+		Name:         strings.TrimSpace(*req.Name),
+		ContactName:  toNullString(req.ContactName),
+		Phone:        toNullString(req.Phone),
+		Email:        toNullString(req.Email),
+		Address:      toNullString(req.Address),
+		LeadTimeDays: leadTime,
+		Notes:        toNullString(req.Notes),
+	})
+	if err != nil {
+		return nil, apperror.Internal("create supplier", err)
+	}
+	return &supplier, nil
+}
+
+// ListSuppliers returns paginated suppliers for the tenant.
+func (s *Service) ListSuppliers(ctx context.Context, tenantID uuid.UUID, page, perPage int) ([]sqlc.Supplier, pagination.Meta, error) {
+	limit, offset := pagination.FormatLimitOffset(page, perPage)
+
+	total, err := s.q.CountSuppliers(ctx, tenantID)
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("count suppliers", err)
+	}
+
+	suppliers, err := s.q.ListSuppliers(ctx, sqlc.ListSuppliersParams{
+		TenantID: tenantID,
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("list suppliers", err)
+	}
+
+	meta := pagination.NewMeta(total, limit, "")
+	return suppliers, meta, nil
+}
+
+// UpdateSupplier applies a partial update to a supplier.
+func (s *Service) UpdateSupplier(ctx context.Context, tenantID, supplierID uuid.UUID, req SupplierRequest) (*sqlc.Supplier, error) {
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return nil, apperror.BadRequest("supplier name cannot be empty")
+	}
+	if req.LeadTimeDays != nil && *req.LeadTimeDays < 0 {
+		return nil, apperror.BadRequest("lead_time_days cannot be negative")
+	}
+	supplier, err := s.q.UpdateSupplier(ctx, sqlc.UpdateSupplierParams{
+		Name:         toNullString(req.Name),
+		ContactName:  toNullString(req.ContactName),
+		Phone:        toNullString(req.Phone),
+		Email:        toNullString(req.Email),
+		Address:      toNullString(req.Address),
+		LeadTimeDays: req.LeadTimeDays,
+		Notes:        toNullString(req.Notes),
+		IsActive:     req.IsActive,
+		ID:           supplierID,
+		TenantID:     tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("supplier")
+	}
+	if err != nil {
+		return nil, apperror.Internal("update supplier", err)
+	}
+	return &supplier, nil
+}
+
+// ---- Purchase Orders ----
+
+// PurchaseOrderLine is a single product line on a new purchase order.
+type PurchaseOrderLine struct {
+	InventoryItemID uuid.UUID
+	Qty             int32
+	UnitCost        decimal.Decimal
+}
+
+// CreatePurchaseOrderRequest holds fields for creating a draft purchase order.
+type CreatePurchaseOrderRequest struct {
+	TenantID     uuid.UUID
+	RestaurantID uuid.UUID
+	SupplierID   uuid.UUID
+	ExpectedAt   *time.Time
+	Notes        *string
+	CreatedBy    uuid.UUID
+	Lines        []PurchaseOrderLine
+}
+
+// PurchaseOrderDetail is a purchase order with its lines.
+type PurchaseOrderDetail struct {
+	sqlc.PurchaseOrder
+	Items []sqlc.PurchaseOrderItem `json:"items"`
+}
+
+// CreatePurchaseOrder creates a draft purchase order for a restaurant.
+func (s *Service) CreatePurchaseOrder(ctx context.Context, req CreatePurchaseOrderRequest) (*PurchaseOrderDetail, error) {
+	if len(req.Lines) == 0 {
+		return nil, apperror.BadRequest("purchase order must have at least one line")
+	}
+
+	supplier, err := s.q.GetSupplier(ctx, sqlc.GetSupplierParams{ID: req.SupplierID, TenantID: req.TenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("supplier")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get supplier", err)
+	}
+	if !supplier.IsActive {
+		return nil, apperror.BadRequest("supplier is inactive")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	total := decimal.Zero
+	seen := make(map[uuid.UUID]bool, len(req.Lines))
+	items := make([]sqlc.InventoryItem, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		if line.Qty <= 0 {
+			return nil, apperror.BadRequest("line quantity must be positive")
+		}
+		if line.UnitCost.IsNegative() {
+			return nil, apperror.BadRequest("unit cost cannot be negative")
+		}
+		if seen[line.InventoryItemID] {
+			return nil, apperror.BadRequest("duplicate inventory item " + line.InventoryItemID.String())
+		}
+		seen[line.InventoryItemID] = true
+
+		item, err := qtx.GetInventoryItem(ctx, sqlc.GetInventoryItemParams{
+			ID:       line.InventoryItemID,
+			TenantID: req.TenantID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("inventory item " + line.InventoryItemID.String())
+		}
+		if err != nil {
+			return nil, apperror.Internal("get inventory item", err)
+		}
+		if item.RestaurantID != req.RestaurantID {
+			return nil, apperror.BadRequest("inventory item does not belong to this restaurant")
+		}
+		items = append(items, item)
+		total = total.Add(line.UnitCost.Mul(decimal.NewFromInt32(line.Qty)))
+	}
+
+	serial, err := qtx.NextPurchaseOrderSerial(ctx, req.TenantID)
+	if err != nil {
+		return nil, apperror.Internal("next purchase order serial", err)
+	}
+
+	var expectedAt pgtype.Timestamptz
+	if req.ExpectedAt != nil {
+		expectedAt = pgtype.Timestamptz{Time: *req.ExpectedAt, Valid: true}
+	}
+	po, err := qtx.CreatePurchaseOrder(ctx, sqlc.CreatePurchaseOrderParams{
+		TenantID:     req.TenantID,
+		RestaurantID: req.RestaurantID,
+		SupplierID:   req.SupplierID,
+		PoNumber:     purchaseOrderNumber(serial),
+		ExpectedAt:   expectedAt,
+		TotalAmount:  toPgNumeric(total),
+		Notes:        toNullString(req.Notes),
+		CreatedBy:    pgtype.UUID{Bytes: req.CreatedBy, Valid: true},
+	})
+	if err != nil {
+		return nil, apperror.Internal("create purchase order", err)
+	}
+
+	detail := &PurchaseOrderDetail{PurchaseOrder: po}
+	for i, line := range req.Lines {
+		poItem, err := qtx.CreatePurchaseOrderItem(ctx, sqlc.CreatePurchaseOrderItemParams{
+			PurchaseOrderID: po.ID,
+			TenantID:        req.TenantID,
+			InventoryItemID: line.InventoryItemID,
+			ProductID:       items[i].ProductID,
+			QtyOrdered:      line.Qty,
+			UnitCost:        toPgNumeric(line.UnitCost),
+		})
+		if err != nil {
+			return nil, apperror.Internal("create purchase order item", err)
+		}
+		detail.Items = append(detail.Items, poItem)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit transaction", err)
+	}
+	return detail, nil
+}
+
+// GetPurchaseOrder returns a purchase order with its lines.
+func (s *Service) GetPurchaseOrder(ctx context.Context, tenantID, poID uuid.UUID) (*PurchaseOrderDetail, error) {
+	po, err := s.q.GetPurchaseOrder(ctx, sqlc.GetPurchaseOrderParams{ID: poID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("purchase order")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get purchase order", err)
+	}
+	items, err := s.q.ListPurchaseOrderItems(ctx, po.ID)
+	if err != nil {
+		return nil, apperror.Internal("list purchase order items", err)
+	}
+	return &PurchaseOrderDetail{PurchaseOrder: po, Items: items}, nil
+}
+
+// ListPurchaseOrders returns paginated purchase orders for a restaurant,
+// optionally filtered by status.
+func (s *Service) ListPurchaseOrders(ctx context.Context, tenantID, restaurantID uuid.UUID, status *sqlc.PurchaseOrderStatus, page, perPage int) ([]sqlc.PurchaseOrder, pagination.Meta, error) {
+	limit, offset := pagination.FormatLimitOffset(page, perPage)
+
+	var statusFilter sqlc.NullPurchaseOrderStatus
+	if status != nil {
+		statusFilter = sqlc.NullPurchaseOrderStatus{PurchaseOrderStatus: *status, Valid: true}
+	}
+
+	total, err := s.q.CountPurchaseOrders(ctx, sqlc.CountPurchaseOrdersParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+		Status:       statusFilter,
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("count purchase orders", err)
+	}
+
+	pos, err := s.q.ListPurchaseOrders(ctx, sqlc.ListPurchaseOrdersParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+		Limit:        int32(limit),
+		Offset:       int32(offset),
+		Status:       statusFilter,
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("list purchase orders", err)
+	}
+
+	meta := pagination.NewMeta(total, limit, "")
+	return pos, meta, nil
+}
+
+// SendPurchaseOrder marks a draft purchase order as sent to the supplier.
+func (s *Service) SendPurchaseOrder(ctx context.Context, tenantID, poID uuid.UUID) (*sqlc.PurchaseOrder, error) {
+	return s.transitionPurchaseOrder(ctx, tenantID, poID, sqlc.PurchaseOrderStatusSent)
+}
+
+// CancelPurchaseOrder cancels a purchase order that has not been received against.
+func (s *Service) CancelPurchaseOrder(ctx context.Context, tenantID, poID uuid.UUID) (*sqlc.PurchaseOrder, error) {
+	return s.transitionPurchaseOrder(ctx, tenantID, poID, sqlc.PurchaseOrderStatusCancelled)
+}
+
+// purchaseOrderTransitions lists the statuses a purchase order can move to
+// from each status. Receiving moves a sent order to partially_received or
+// received; once goods have arrived it can no longer be cancelled.
+var purchaseOrderTransitions = map[sqlc.PurchaseOrderStatus][]sqlc.PurchaseOrderStatus{
+	sqlc.PurchaseOrderStatusDraft: {sqlc.PurchaseOrderStatusSent, sqlc.PurchaseOrderStatusCancelled},
+	sqlc.PurchaseOrderStatusSent: {
+		sqlc.PurchaseOrderStatusPartiallyReceived,
+		sqlc.PurchaseOrderStatusReceived,
+		sqlc.PurchaseOrderStatusCancelled,
+	},
+	sqlc.PurchaseOrderStatusPartiallyReceived: {sqlc.PurchaseOrderStatusPartiallyReceived, sqlc.PurchaseOrderStatusReceived},
+}
+
+// canMovePurchaseOrder reports whether a purchase order may move from one
+// status to another.
+func canMovePurchaseOrder(from, to sqlc.PurchaseOrderStatus) bool {
+	return slices.Contains(purchaseOrderTransitions[from], to)
+}
+
+func (s *Service) transitionPurchaseOrder(ctx context.Context, tenantID, poID uuid.UUID, to sqlc.PurchaseOrderStatus) (*sqlc.PurchaseOrder, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	po, err := qtx.GetPurchaseOrderForUpdate(ctx, sqlc.GetPurchaseOrderForUpdateParams{ID: poID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("purchase order")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get purchase order", err)
+	}
+
+	if !canMovePurchaseOrder(po.Status, to) {
+		return nil, apperror.BadRequest(fmt.Sprintf("cannot move purchase order from %s to %s", po.Status, to))
+	}
+
+	updated, err := qtx.UpdatePurchaseOrderStatus(ctx, sqlc.UpdatePurchaseOrderStatusParams{
+		Status:   to,
+		ID:       po.ID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("update purchase order status", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit transaction", err)
+	}
+	return &updated, nil
+}
+
+// ReceiveLine is the quantity received against one purchase order line.
+type ReceiveLine struct {
+	PurchaseOrderItemID uuid.UUID
+	Qty                 int32
+}
+
+// ReceivePurchaseOrderRequest holds fields for receiving goods.
+type ReceivePurchaseOrderRequest struct {
+	TenantID        uuid.UUID
+	PurchaseOrderID uuid.UUID
+	ReceivedBy      uuid.UUID
+	Lines           []ReceiveLine
+}
+
+// ReceivePurchaseOrder books received goods into stock. Each line posts a
+// purchase adjustment at the line's unit cost and rolls the item's cost price
+// forward as a weighted average. The order moves to partially_received or
+// received depending on what is still outstanding.
+func (s *Service) ReceivePurchaseOrder(ctx context.Context, req ReceivePurchaseOrderRequest) (*PurchaseOrderDetail, error) {
+	if len(req.Lines) == 0 {
+		return nil, apperror.BadRequest("nothing to receive")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	po, err := qtx.GetPurchaseOrderForUpdate(ctx, sqlc.GetPurchaseOrderForUpdateParams{
+		ID:       req.PurchaseOrderID,
+		TenantID: req.TenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("purchase order")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get purchase order", err)
+	}
+
+	poItems, err := qtx.ListPurchaseOrderItems(ctx, po.ID)
+	if err != nil {
+		return nil, apperror.Internal("list purchase order items", err)
+	}
+	status, err := planReceipt(po.Status, poItems, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]sqlc.PurchaseOrderItem, len(poItems))
+	for _, it := range poItems {
+		byID[it.ID] = it
+	}
+
+	for _, line := range req.Lines {
+		poItem := byID[line.PurchaseOrderItemID]
+		received, err := qtx.ReceivePurchaseOrderItem(ctx, sqlc.ReceivePurchaseOrderItemParams{
+			Qty:             line.Qty,
+			ID:              poItem.ID,
+			PurchaseOrderID: po.ID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.BadRequest("received quantity exceeds ordered quantity for item " + poItem.ID.String())
+		} else if err != nil {
+			return nil, apperror.Internal("receive purchase order item", err)
+		}
+		byID[received.ID] = received
+
+		current, err := qtx.GetInventoryItemForUpdate(ctx, sqlc.GetInventoryItemForUpdateParams{
+			ID:       poItem.InventoryItemID,
+			TenantID: req.TenantID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NotFound("inventory item")
+		}
+		if err != nil {
+			return nil, apperror.Internal("get inventory item", err)
+		}
+		item, err := qtx.ReceiveStock(ctx, sqlc.ReceiveStockParams{
+			Qty:       line.Qty,
+			CostPrice: toPgNumeric(weightedAverageCost(current.StockQty, current.CostPrice, line.Qty, pgNumericToDecimal(poItem.UnitCost))),
+			ID:        current.ID,
+			TenantID:  req.TenantID,
+		})
+		if err != nil {
+			return nil, apperror.Internal("receive stock", err)
+		}
+
+		qtyBefore := item.StockQty - line.Qty
+		if _, err := qtx.CreateInventoryAdjustment(ctx, sqlc.CreateInventoryAdjustmentParams{
+			InventoryItemID: item.ID,
+			TenantID:        req.TenantID,
+			RestaurantID:    item.RestaurantID,
+			AdjustmentType:  sqlc.InventoryAdjustmentReasonPurchase,
+			QtyBefore:       qtyBefore,
+			QtyChange:       line.Qty,
+			QtyAfter:        item.StockQty,
+			CostPrice:       poItem.UnitCost,
+			Note:            sql.NullString{String: "Received on " + po.PoNumber, Valid: true},
+			AdjustedBy:      pgtype.UUID{Bytes: req.ReceivedBy, Valid: true},
+		}); err != nil {
+			return nil, apperror.Internal("create inventory adjustment", err)
+		}
+
+		if err := s.syncStockLevel(ctx, qtx, item, qtyBefore-item.ReservedQty); err != nil {
+			return nil, apperror.Internal("sync stock level", err)
+		}
+	}
+
+	if status != po.Status {
+		po, err = qtx.UpdatePurchaseOrderStatus(ctx, sqlc.UpdatePurchaseOrderStatusParams{
+			Status:   status,
+			ID:       po.ID,
+			TenantID: req.TenantID,
+		})
+		if err != nil {
+			return nil, apperror.Internal("update purchase order status", err)
+		}
+	}
+
+	items, err := qtx.ListPurchaseOrderItems(ctx, po.ID)
+	if err != nil {
+		return nil, apperror.Internal("list purchase order items", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit transaction", err)
+	}
+	return &PurchaseOrderDetail{PurchaseOrder: po, Items: items}, nil
+}
+
+// planReceipt checks a receipt against a purchase order's current lines and
+// returns the status the order moves to once it is booked. Lines may repeat an
+// item; their quantities count together against what is outstanding.
+func planReceipt(status sqlc.PurchaseOrderStatus, items []sqlc.PurchaseOrderItem, lines []ReceiveLine) (sqlc.PurchaseOrderStatus, error) {
+	if !canMovePurchaseOrder(status, sqlc.PurchaseOrderStatusReceived) {
+		return status, apperror.BadRequest("only sent purchase orders can be received")
+	}
+
+	byID := make(map[uuid.UUID]sqlc.PurchaseOrderItem, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	for _, line := range lines {
+		if line.Qty <= 0 {
+			return status, apperror.BadRequest("received quantity must be positive")
+		}
+		item, ok := byID[line.PurchaseOrderItemID]
+		if !ok {
+			return status, apperror.NotFound("purchase order item " + line.PurchaseOrderItemID.String())
+		}
+		if line.Qty > outstandingQty(item) {
+			return status, apperror.BadRequest("received quantity exceeds ordered quantity for item " + item.ID.String())
+		}
+		item.QtyReceived += line.Qty
+		byID[item.ID] = item
+	}
+
+	var outstanding int64
+	for _, item := range byID {
+		if outstandingQty(item) > 0 {
+			outstanding++
+		}
+	}
+	return receivedStatus(outstanding), nil
+}
+
+// outstandingQty is how much of a purchase order line is still to arrive.
+func outstandingQty(item sqlc.PurchaseOrderItem) int32 {
+	return item.QtyOrdered - item.QtyReceived
+}
+
+// receivedStatus is a purchase order's status after a receipt, given how
+// many of its lines are still outstanding.
+func receivedStatus(outstandingLines int64) sqlc.PurchaseOrderStatus {
+	if outstandingLines == 0 {
+		return sqlc.PurchaseOrderStatusReceived
+	}
+	return sqlc.PurchaseOrderStatusPartiallyReceived
+}
+
+// weightedAverageCost rolls an item's cost price forward when qty units
+// arrive at unitCost:
+//
+//	(on_hand × cost + qty × unit_cost) / (on_hand + qty), rounded to the poisha
+//
+// Items with no cost yet, or no stock on hand (including oversold stock),
+// take the unit cost of the receipt.
+func weightedAverageCost(onHand int32, cost pgtype.Numeric, qty int32, unitCost decimal.Decimal) decimal.Decimal {
+	if !cost.Valid || onHand <= 0 {
+		return unitCost
+	}
+	held := decimal.NewFromInt32(onHand)
+	arriving := decimal.NewFromInt32(qty)
+	return held.Mul(pgNumericToDecimal(cost)).Add(arriving.Mul(unitCost)).Div(held.Add(arriving)).Round(2)
+}
+
+// ---- Stock Valuation ----
+
+// StockValuationReport values on-hand stock at weighted average cost.
+type StockValuationReport struct {
+	RestaurantID uuid.UUID                   `json:"restaurant_id"`
+	TotalUnits   int64                       `json:"total_units"`
+	TotalValue   decimal.Decimal             `json:"total_value"`
+	Unvalued     int                         `json:"unvalued_items"`
+	Items        []sqlc.GetStockValuationRow `json:"items"`
+	GeneratedAt  time.Time                   `json:"generated_at"`
+}
+
+// GetStockValuation returns the stock valuation report for a restaurant.
+// Items without a cost price are counted in Unvalued and contribute zero.
+func (s *Service) GetStockValuation(ctx context.Context, tenantID, restaurantID uuid.UUID) (*StockValuationReport, error) {
+	rows, err := s.q.GetStockValuation(ctx, sqlc.GetStockValuationParams{
+		TenantID:     tenantID,
+		RestaurantID: restaurantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("get stock valuation", err)
+	}
+
+	report := &StockValuationReport{
+		RestaurantID: restaurantID,
+		TotalValue:   decimal.Zero,
+		Items:        rows,
+		GeneratedAt:  timeutil.NowBD(),
+	}
+	for _, row := range rows {
+		report.TotalUnits += int64(row.StockQty)
+		report.TotalValue = report.TotalValue.Add(pgNumericToDecimal(row.StockValue))
+		if !row.CostPrice.Valid && row.StockQty > 0 {
+			report.Unvalued++
+		}
+	}
+	return report, nil
+}
+
+// purchaseOrderNumber formats a tenant's purchase order serial, dated in
+// Bangladesh time, e.g. PO-20240315-000042.
+func purchaseOrderNumber(serial int32) string {
+	return fmt.Sprintf("PO-%s-%06d", timeutil.NowBD().Format("20060102"), serial)
+}
+
+func toPgNumeric(d decimal.Decimal) pgtype.Numeric {
+	n := pgtype.Numeric{}
+	_ = n.Scan(d.String())
+	return n
+}
+
+func pgNumericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
+
+func toNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
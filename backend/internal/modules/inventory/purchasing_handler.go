@@ -0,0 +1,370 @@
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/auth"
+	"github.com/munchies/platform/backend/internal/modules/tenant"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/pagination"
+	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/shopspring/decimal"
+)
+
+type supplierBody struct {
+	Name         *string `json:"name"`
+	ContactName  *string `json:"contact_name"`
+	Phone        *string `json:"phone"`
+	Email        *string `json:"email"`
+	Address      *string `json:"address"`
+	LeadTimeDays *int32  `json:"lead_time_days"`
+	Notes        *string `json:"notes"`
+	IsActive     *bool   `json:"is_active"`
+}
+
+func (b supplierBody) toRequest() SupplierRequest {
+	return SupplierRequest{
+		Name:         b.Name,
+		ContactName:  b.ContactName,
+		Phone:        b.Phone,
+		Email:        b.Email,
+		Address:      b.Address,
+		LeadTimeDays: b.LeadTimeDays,
+		Notes:        b.Notes,
+		IsActive:     b.IsActive,
+	}
+}
+
+// ListSuppliers handles GET /partner/inventory/suppliers
+func (h *Handler) ListSuppliers(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	page, perPage := parsePagination(r)
+	suppliers, meta, err := h.svc.ListSuppliers(r.Context(), t.ID, page, perPage)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, pagination.PagedResponse{Data: suppliers, Meta: meta})
+}
+
+// CreateSupplier handles POST /partner/inventory/suppliers
+func (h *Handler) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	var req supplierBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	supplier, err := h.svc.CreateSupplier(r.Context(), t.ID, req.toRequest())
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusCreated, supplier)
+}
+
+// UpdateSupplier handles PATCH /partner/inventory/suppliers/{id}
+func (h *Handler) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	supplierID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid supplier id"))
+		return
+	}
+
+	var req supplierBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	supplier, err := h.svc.UpdateSupplier(r.Context(), t.ID, supplierID, req.toRequest())
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, supplier)
+}
+
+// ListPurchaseOrders handles GET /partner/inventory/purchase-orders
+func (h *Handler) ListPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	restaurantID, err := uuid.Parse(r.URL.Query().Get("restaurant_id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("restaurant_id is required"))
+		return
+	}
+
+	var status *sqlc.PurchaseOrderStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		st := sqlc.PurchaseOrderStatus(s)
+		switch st {
+		case sqlc.PurchaseOrderStatusDraft, sqlc.PurchaseOrderStatusSent, sqlc.PurchaseOrderStatusPartiallyReceived,
+			sqlc.PurchaseOrderStatusReceived, sqlc.PurchaseOrderStatusCancelled:
+		default:
+			respond.Error(w, apperror.BadRequest("status must be draft, sent, partially_received, received or cancelled"))
+			return
+		}
+		status = &st
+	}
+
+	page, perPage := parsePagination(r)
+	pos, meta, err := h.svc.ListPurchaseOrders(r.Context(), t.ID, restaurantID, status, page, perPage)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, pagination.PagedResponse{Data: pos, Meta: meta})
+}
+
+// CreatePurchaseOrder handles POST /partner/inventory/purchase-orders
+func (h *Handler) CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	var req struct {
+		RestaurantID string  `json:"restaurant_id"`
+		SupplierID   string  `json:"supplier_id"`
+		ExpectedAt   *string `json:"expected_at"`
+		Notes        *string `json:"notes"`
+		Items        []struct {
+			InventoryItemID string `json:"inventory_item_id"`
+			Qty             int32  `json:"qty"`
+			UnitCost        string `json:"unit_cost"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	restaurantID, err := uuid.Parse(req.RestaurantID)
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant_id"))
+		return
+	}
+	supplierID, err := uuid.Parse(req.SupplierID)
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid supplier_id"))
+		return
+	}
+
+	var expectedAt *time.Time
+	if req.ExpectedAt != nil {
+		ts, err := time.Parse(time.RFC3339, *req.ExpectedAt)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid expected_at format, use RFC3339"))
+			return
+		}
+		expectedAt = &ts
+	}
+
+	lines := make([]PurchaseOrderLine, 0, len(req.Items))
+	for _, item := range req.Items {
+		itemID, err := uuid.Parse(item.InventoryItemID)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid inventory_item_id"))
+			return
+		}
+		unitCost, err := decimal.NewFromString(item.UnitCost)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid unit_cost"))
+			return
+		}
+		lines = append(lines, PurchaseOrderLine{
+			InventoryItemID: itemID,
+			Qty:             item.Qty,
+			UnitCost:        unitCost,
+		})
+	}
+
+	po, err := h.svc.CreatePurchaseOrder(r.Context(), CreatePurchaseOrderRequest{
+		TenantID:     t.ID,
+		RestaurantID: restaurantID,
+		SupplierID:   supplierID,
+		ExpectedAt:   expectedAt,
+		Notes:        req.Notes,
+		CreatedBy:    u.ID,
+		Lines:        lines,
+	})
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusCreated, po)
+}
+
+// GetPurchaseOrder handles GET /partner/inventory/purchase-orders/{id}
+func (h *Handler) GetPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	poID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid purchase order id"))
+		return
+	}
+
+	po, err := h.svc.GetPurchaseOrder(r.Context(), t.ID, poID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, po)
+}
+
+// SendPurchaseOrder handles POST /partner/inventory/purchase-orders/{id}/send
+func (h *Handler) SendPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	poID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid purchase order id"))
+		return
+	}
+
+	po, err := h.svc.SendPurchaseOrder(r.Context(), t.ID, poID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, po)
+}
+
+// CancelPurchaseOrder handles POST /partner/inventory/purchase-orders/{id}/cancel
+func (h *Handler) CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	poID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid purchase order id"))
+		return
+	}
+
+	po, err := h.svc.CancelPurchaseOrder(r.Context(), t.ID, poID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, po)
+}
+
+// ReceivePurchaseOrder handles POST /partner/inventory/purchase-orders/{id}/receive
+func (h *Handler) ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	poID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid purchase order id"))
+		return
+	}
+
+	var req struct {
+		Items []struct {
+			PurchaseOrderItemID string `json:"purchase_order_item_id"`
+			Qty                 int32  `json:"qty"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	lines := make([]ReceiveLine, 0, len(req.Items))
+	for _, item := range req.Items {
+		itemID, err := uuid.Parse(item.PurchaseOrderItemID)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid purchase_order_item_id"))
+			return
+		}
+		lines = append(lines, ReceiveLine{PurchaseOrderItemID: itemID, Qty: item.Qty})
+	}
+
+	po, err := h.svc.ReceivePurchaseOrder(r.Context(), ReceivePurchaseOrderRequest{
+		TenantID:        t.ID,
+		PurchaseOrderID: poID,
+		ReceivedBy:      u.ID,
+		Lines:           lines,
+	})
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, po)
+}
+
+// GetStockValuation handles GET /partner/inventory/valuation
+func (h *Handler) GetStockValuation(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	restaurantID, err := uuid.Parse(r.URL.Query().Get("restaurant_id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("restaurant_id is required"))
+		return
+	}
+
+	report, err := h.svc.GetStockValuation(r.Context(), t.ID, restaurantID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, report)
+}
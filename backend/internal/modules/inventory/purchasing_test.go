@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func TestWeightedAverageCost(t *testing.T) {
+	cases := []struct {
+		name     string
+		onHand   int32
+		cost     pgtype.Numeric
+		qty      int32
+		unitCost string
+		want     string
+	}{
+		{"first receipt", 0, pgtype.Numeric{}, 10, "25", "25"},
+		{"no cost yet", 8, pgtype.Numeric{}, 10, "25", "25"},
+		{"empty stock", 0, toPgNumeric(dec("40")), 5, "30", "30"},
+		{"oversold stock", -3, toPgNumeric(dec("40")), 5, "30", "30"},
+		{"same cost", 10, toPgNumeric(dec("20")), 10, "20", "20"},
+		// (10×20 + 30×24) / 40
+		{"dearer receipt", 10, toPgNumeric(dec("20")), 30, "24", "23"},
+		// (3×10 + 4×11.50) / 7 = 10.857…
+		{"rounds to poisha", 3, toPgNumeric(dec("10")), 4, "11.50", "10.86"},
+	}
+	for _, c := range cases {
+		if got := weightedAverageCost(c.onHand, c.cost, c.qty, dec(c.unitCost)); !got.Equal(dec(c.want)) {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestPartialReceipts(t *testing.T) {
+	eggs := sqlc.PurchaseOrderItem{ID: uuid.New(), QtyOrdered: 12}
+	flour := sqlc.PurchaseOrderItem{ID: uuid.New(), QtyOrdered: 4}
+	status := sqlc.PurchaseOrderStatusSent
+
+	for _, receipt := range []struct {
+		name   string
+		lines  []ReceiveLine
+		status sqlc.PurchaseOrderStatus
+	}{
+		{"part of one line", []ReceiveLine{{eggs.ID, 5}}, sqlc.PurchaseOrderStatusPartiallyReceived},
+		{"other line in full", []ReceiveLine{{flour.ID, 4}}, sqlc.PurchaseOrderStatusPartiallyReceived},
+		{"rest split across lines", []ReceiveLine{{eggs.ID, 4}, {eggs.ID, 3}}, sqlc.PurchaseOrderStatusReceived},
+	} {
+		got, err := planReceipt(status, []sqlc.PurchaseOrderItem{eggs, flour}, receipt.lines)
+		if err != nil {
+			t.Fatalf("%s: %v", receipt.name, err)
+		}
+		if got != receipt.status {
+			t.Errorf("%s: status %s, want %s", receipt.name, got, receipt.status)
+		}
+		for _, line := range receipt.lines {
+			if line.PurchaseOrderItemID == eggs.ID {
+				eggs.QtyReceived += line.Qty
+			} else {
+				flour.QtyReceived += line.Qty
+			}
+		}
+		status = got
+	}
+
+	if _, err := planReceipt(status, []sqlc.PurchaseOrderItem{eggs, flour}, []ReceiveLine{{eggs.ID, 1}}); err == nil {
+		t.Error("a fully received order accepted another receipt")
+	}
+}
+
+func TestPlanReceiptRejects(t *testing.T) {
+	item := sqlc.PurchaseOrderItem{ID: uuid.New(), QtyOrdered: 10, QtyReceived: 6}
+	items := []sqlc.PurchaseOrderItem{item}
+	cases := []struct {
+		name   string
+		status sqlc.PurchaseOrderStatus
+		lines  []ReceiveLine
+		code   int
+	}{
+		{"draft order", sqlc.PurchaseOrderStatusDraft, []ReceiveLine{{item.ID, 1}}, http.StatusBadRequest},
+		{"cancelled order", sqlc.PurchaseOrderStatusCancelled, []ReceiveLine{{item.ID, 1}}, http.StatusBadRequest},
+		{"zero quantity", sqlc.PurchaseOrderStatusSent, []ReceiveLine{{item.ID, 0}}, http.StatusBadRequest},
+		{"unknown line", sqlc.PurchaseOrderStatusSent, []ReceiveLine{{uuid.New(), 1}}, http.StatusNotFound},
+		{"over outstanding", sqlc.PurchaseOrderStatusSent, []ReceiveLine{{item.ID, 5}}, http.StatusBadRequest},
+		{"repeated lines over outstanding", sqlc.PurchaseOrderStatusPartiallyReceived, []ReceiveLine{{item.ID, 2}, {item.ID, 3}}, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		_, err := planReceipt(c.status, items, c.lines)
+		var appErr *apperror.AppError
+		if !errors.As(err, &appErr) {
+			t.Errorf("%s: got %v, want an app error", c.name, err)
+			continue
+		}
+		if appErr.HTTPStatus() != c.code {
+			t.Errorf("%s: status %d, want %d", c.name, appErr.HTTPStatus(), c.code)
+		}
+	}
+}
+
+func TestCanMovePurchaseOrder(t *testing.T) {
+	draft, sent := sqlc.PurchaseOrderStatusDraft, sqlc.PurchaseOrderStatusSent
+	partial, received := sqlc.PurchaseOrderStatusPartiallyReceived, sqlc.PurchaseOrderStatusReceived
+	cancelled := sqlc.PurchaseOrderStatusCancelled
+	cases := []struct {
+		from, to sqlc.PurchaseOrderStatus
+		want     bool
+	}{
+		{draft, sent, true},
+		{draft, cancelled, true},
+		{draft, received, false},
+		{draft, partial, false},
+		{sent, partial, true},
+		{sent, received, true},
+		{sent, cancelled, true},
+		{sent, draft, false},
+		{partial, partial, true},
+		{partial, received, true},
+		{partial, cancelled, false},
+		{received, cancelled, false},
+		{received, partial, false},
+		{cancelled, sent, false},
+		{cancelled, received, false},
+	}
+	for _, c := range cases {
+		if got := canMovePurchaseOrder(c.from, c.to); got != c.want {
+			t.Errorf("%s -> %s = %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+}
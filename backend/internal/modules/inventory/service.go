@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
@@ -16,12 +19,15 @@ import (
 
 // Service handles inventory business logic.
 type Service struct {
-	q *sqlc.Queries
+	q        *sqlc.Queries
+	pool     *pgxpool.Pool
+	notifier Notifier
 }
 
-// NewService creates a new inventory service.
-func NewService(q *sqlc.Queries) *Service {
-	return &Service{q: q}
+// NewService creates a new inventory service. notifier may be nil, in which
+// case stock alerts are recorded as events but no staff pushes are sent.
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool, notifier Notifier) *Service {
+	return &Service{q: q, pool: pool, notifier: notifier}
 }
 
 // AdjustStockRequest holds fields for a stock adjustment.
@@ -35,10 +41,18 @@ type AdjustStockRequest struct {
 	AdjustedBy      uuid.UUID
 }
 
-// AdjustStock modifies stock quantity and creates an audit log entry.
+// AdjustStock modifies stock quantity and creates an audit log entry. The
+// item is locked so the availability switch and stock alerts see the level
+// the adjustment started from.
 func (s *Service) AdjustStock(ctx context.Context, req AdjustStockRequest) (*sqlc.InventoryItem, *sqlc.InventoryAdjustment, error) {
-	// Get current item
-	item, err := s.q.GetInventoryItem(ctx, sqlc.GetInventoryItemParams{
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	item, err := qtx.GetInventoryItemForUpdate(ctx, sqlc.GetInventoryItemForUpdateParams{
 		ID:       req.InventoryItemID,
 		TenantID: req.TenantID,
 	})
@@ -61,7 +75,7 @@ func (s *Service) AdjustStock(ctx context.Context, req AdjustStockRequest) (*sql
 	}
 
 	// Adjust stock
-	updated, err := s.q.AdjustStock(ctx, sqlc.AdjustStockParams{
+	updated, err := qtx.AdjustStock(ctx, sqlc.AdjustStockParams{
 		QtyChange: req.QtyChange,
 		ID:        req.InventoryItemID,
 		TenantID:  req.TenantID,
@@ -73,12 +87,16 @@ func (s *Service) AdjustStock(ctx context.Context, req AdjustStockRequest) (*sql
 		return nil, nil, apperror.Internal("adjust stock", err)
 	}
 
+	if err := s.syncStockLevel(ctx, qtx, updated, item.StockQty-item.ReservedQty); err != nil {
+		return nil, nil, apperror.Internal("sync stock level", err)
+	}
+
 	// Create audit log
 	var costPrice pgtype.Numeric
 	if item.CostPrice.Valid {
 		costPrice = item.CostPrice
 	}
-	adj, err := s.q.CreateInventoryAdjustment(ctx, sqlc.CreateInventoryAdjustmentParams{
+	adj, err := qtx.CreateInventoryAdjustment(ctx, sqlc.CreateInventoryAdjustmentParams{
 		InventoryItemID: req.InventoryItemID,
 		TenantID:        req.TenantID,
 		RestaurantID:    req.RestaurantID,
@@ -95,6 +113,9 @@ func (s *Service) AdjustStock(ctx context.Context, req AdjustStockRequest) (*sql
 		return nil, nil, apperror.Internal("create inventory adjustment", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, apperror.Internal("commit", err)
+	}
 	return &updated, &adj, nil
 }
 
@@ -124,8 +145,20 @@ func (s *Service) ListInventory(ctx context.Context, tenantID, restaurantID uuid
 	return items, meta, nil
 }
 
+// consumptionWindow is how far back ListLowStock looks to estimate daily usage.
+const consumptionWindow = 14 * 24 * time.Hour
+
+// LowStockItem is an inventory item below its reorder threshold with a
+// days-of-cover forecast based on recent order consumption.
+type LowStockItem struct {
+	sqlc.InventoryItem
+	AvailableQty  int32    `json:"available_qty"`
+	AvgDailyUsage float64  `json:"avg_daily_usage"`
+	DaysOfCover   *float64 `json:"days_of_cover"`
+}
+
 // ListLowStock returns inventory items below the reorder threshold.
-func (s *Service) ListLowStock(ctx context.Context, tenantID, restaurantID uuid.UUID, page, perPage int) ([]sqlc.InventoryItem, pagination.Meta, error) {
+func (s *Service) ListLowStock(ctx context.Context, tenantID, restaurantID uuid.UUID, page, perPage int) ([]LowStockItem, pagination.Meta, error) {
 	limit, offset := pagination.FormatLimitOffset(page, perPage)
 
 	total, err := s.q.CountLowStock(ctx, sqlc.CountLowStockParams{
@@ -146,15 +179,59 @@ func (s *Service) ListLowStock(ctx context.Context, tenantID, restaurantID uuid.
 		return nil, pagination.Meta{}, apperror.Internal("list low stock", err)
 	}
 
+	productIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	consumption, err := s.q.ListInventoryConsumption(ctx, sqlc.ListInventoryConsumptionParams{
+		RestaurantID: restaurantID,
+		TenantID:     tenantID,
+		ProductIds:   productIDs,
+		Since:        time.Now().Add(-consumptionWindow),
+	})
+	if err != nil {
+		return nil, pagination.Meta{}, apperror.Internal("list inventory consumption", err)
+	}
+	unitsSold := make(map[uuid.UUID]int64, len(consumption))
+	for _, c := range consumption {
+		unitsSold[c.ProductID] = c.UnitsSold
+	}
+
+	windowDays := consumptionWindow.Hours() / 24
+	result := make([]LowStockItem, 0, len(items))
+	for _, item := range items {
+		available := item.StockQty - item.ReservedQty
+		avgDaily := float64(unitsSold[item.ProductID]) / windowDays
+		result = append(result, LowStockItem{
+			InventoryItem: item,
+			AvailableQty:  available,
+			AvgDailyUsage: avgDaily,
+			DaysOfCover:   daysOfCover(available, avgDaily),
+		})
+	}
+
 	meta := pagination.NewMeta(total, limit, "")
-	return items, meta, nil
+	return result, meta, nil
+}
+
+// daysOfCover estimates how many days the available stock lasts at the given
+// daily usage. Returns nil when there is no recent usage to forecast from.
+func daysOfCover(available int32, avgDailyUsage float64) *float64 {
+	if avgDailyUsage <= 0 {
+		return nil
+	}
+	days := math.Round(float64(available)/avgDailyUsage*10) / 10
+	if days < 0 {
+		days = 0
+	}
+	return &days
 }
 
 // CheckAndReserveStock validates stock availability and reserves it for an order.
 // Used during order creation within a transaction.
 func (s *Service) CheckAndReserveStock(ctx context.Context, q *sqlc.Queries, tenantID uuid.UUID, items []StockReservation) error {
 	for _, item := range items {
-		_, err := q.ReserveStock(ctx, sqlc.ReserveStockParams{
+		reserved, err := q.ReserveStock(ctx, sqlc.ReserveStockParams{
 			Qty:          item.Quantity,
 			ProductID:    item.ProductID,
 			RestaurantID: item.RestaurantID,
@@ -166,6 +243,10 @@ func (s *Service) CheckAndReserveStock(ctx context.Context, q *sqlc.Queries, ten
 		if err != nil {
 			return apperror.Internal("reserve stock", err)
 		}
+		prevAvailable := reserved.StockQty - reserved.ReservedQty + item.Quantity
+		if err := s.syncStockLevel(ctx, q, reserved, prevAvailable); err != nil {
+			return apperror.Internal("sync stock level", err)
+		}
 	}
 	return nil
 }
@@ -173,15 +254,22 @@ func (s *Service) CheckAndReserveStock(ctx context.Context, q *sqlc.Queries, ten
 // ReleaseStockForOrder releases reserved stock when an order is cancelled.
 func (s *Service) ReleaseStockForOrder(ctx context.Context, q *sqlc.Queries, tenantID uuid.UUID, items []StockReservation) error {
 	for _, item := range items {
-		_, err := q.ReleaseStock(ctx, sqlc.ReleaseStockParams{
+		released, err := q.ReleaseStock(ctx, sqlc.ReleaseStockParams{
 			Qty:          item.Quantity,
 			ProductID:    item.ProductID,
 			RestaurantID: item.RestaurantID,
 			TenantID:     tenantID,
 		})
-		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
 			return apperror.Internal("release stock", err)
 		}
+		prevAvailable := released.StockQty - released.ReservedQty - item.Quantity
+		if err := s.syncStockLevel(ctx, q, released, prevAvailable); err != nil {
+			return apperror.Internal("sync stock level", err)
+		}
 	}
 	return nil
 }
@@ -8,11 +8,13 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
 	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/shopspring/decimal"
 )
 
 // Handler handles order issue HTTP requests.
@@ -71,6 +73,7 @@ func (h *Handler) CreateIssue(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetIssue handles GET /partner/issues/:id
+// The response carries the order's delivery proof for disputed deliveries.
 func (h *Handler) GetIssue(w http.ResponseWriter, r *http.Request) {
 	t := tenant.FromContext(r.Context())
 	if t == nil {
@@ -82,7 +85,7 @@ func (h *Handler) GetIssue(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, apperror.BadRequest("invalid issue id"))
 		return
 	}
-	issue, err := h.svc.GetByID(r.Context(), t.ID, issueID)
+	issue, err := h.svc.GetDetail(r.Context(), t.ID, issueID)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -97,8 +100,13 @@ func (h *Handler) ListIssues(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, apperror.NotFound("tenant"))
 		return
 	}
+	scope := access.FromContext(r.Context())
+	if scope == nil {
+		respond.Error(w, apperror.Forbidden("restaurant access not resolved"))
+		return
+	}
 	page, perPage := parsePagination(r)
-	items, meta, err := h.svc.ListByTenant(r.Context(), t.ID, page, perPage)
+	items, meta, err := h.svc.ListByTenant(r.Context(), t.ID, scope.Filter(), page, perPage)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -189,15 +197,38 @@ func (h *Handler) ResolveIssue(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Note   string `json:"note"`
-		Reason string `json:"reason"`
+		Note                    string     `json:"note"`
+		Reason                  string     `json:"reason"`
+		AccountableParty        *string    `json:"accountable_party"`
+		RiderPenaltyAmount      *string    `json:"rider_penalty_amount"`
+		RestaurantPenaltyAmount *string    `json:"restaurant_penalty_amount"`
+		RestaurantID            *uuid.UUID `json:"restaurant_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
 		return
 	}
 
-	issue, err := h.svc.Resolve(r.Context(), t.ID, issueID, u.ID, req.Note)
+	in := ResolveIssueInput{Note: req.Note, RestaurantID: req.RestaurantID}
+	if req.AccountableParty != nil {
+		party := sqlc.Accountable(*req.AccountableParty)
+		if party != sqlc.AccountableRestaurant && party != sqlc.AccountableRider && party != sqlc.AccountablePlatform {
+			respond.Error(w, apperror.BadRequest("accountable_party must be restaurant, rider or platform"))
+			return
+		}
+		in.AccountableParty = &party
+	}
+	var appErr *apperror.AppError
+	if in.RiderPenaltyAmount, appErr = parseAmount("rider_penalty_amount", req.RiderPenaltyAmount); appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	if in.RestaurantPenaltyAmount, appErr = parseAmount("restaurant_penalty_amount", req.RestaurantPenaltyAmount); appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	issue, err := h.svc.Resolve(r.Context(), t.ID, issueID, u.ID, in)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -295,6 +326,17 @@ func parsePagination(r *http.Request) (page, perPage int) {
 	return page, perPage
 }
 
+func parseAmount(field string, v *string) (*decimal.Decimal, *apperror.AppError) {
+	if v == nil {
+		return nil, nil
+	}
+	d, err := decimal.NewFromString(*v)
+	if err != nil || d.IsNegative() {
+		return nil, apperror.BadRequest(field + " must be a non-negative amount")
+	}
+	return &d, nil
+}
+
 func toAppError(err error) *apperror.AppError {
 	if e, ok := err.(*apperror.AppError); ok {
 		return e
@@ -9,19 +9,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
+	"github.com/shopspring/decimal"
 )
 
 // Service implements order issue business logic.
 type Service struct {
-	q *sqlc.Queries
+	q    *sqlc.Queries
+	pool *pgxpool.Pool
 }
 
 // NewService creates a new issue service.
-func NewService(q *sqlc.Queries) *Service {
-	return &Service{q: q}
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool) *Service {
+	return &Service{q: q, pool: pool}
 }
 
 // CreateIssueRequest holds fields for creating an issue.
@@ -35,7 +38,7 @@ type CreateIssueRequest struct {
 
 // CreateIssue creates a new order issue.
 func (s *Service) CreateIssue(ctx context.Context, tenantID uuid.UUID, req CreateIssueRequest) (*sqlc.OrderIssue, error) {
-	_, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{
+	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{
 		ID:       req.OrderID,
 		TenantID: tenantID,
 	})
@@ -45,6 +48,9 @@ func (s *Service) CreateIssue(ctx context.Context, tenantID uuid.UUID, req Creat
 	if err != nil {
 		return nil, err
 	}
+	if req.IssueType == sqlc.IssueTypeNotDelivered && order.Status != sqlc.OrderStatusDelivered {
+		return nil, apperror.BadRequest("only delivered orders can be disputed as not delivered")
+	}
 
 	issue, err := s.q.CreateOrderIssue(ctx, sqlc.CreateOrderIssueParams{
 		OrderID:          req.OrderID,
@@ -75,17 +81,52 @@ func (s *Service) GetByID(ctx context.Context, tenantID, issueID uuid.UUID) (*sq
 	return &issue, nil
 }
 
-// ListByTenant returns paginated issues for a tenant.
-func (s *Service) ListByTenant(ctx context.Context, tenantID uuid.UUID, page, perPage int) ([]sqlc.OrderIssue, pagination.Meta, error) {
+// IssueDetail is an issue together with the proof of delivery recorded for
+// its order, so disputed deliveries can be checked against the evidence.
+type IssueDetail struct {
+	sqlc.OrderIssue
+	DeliveryProof *sqlc.DeliveryProof `json:"delivery_proof"`
+}
+
+// GetDetail returns an order issue with its order's delivery proof, if any.
+// The delivery code itself is withheld.
+func (s *Service) GetDetail(ctx context.Context, tenantID, issueID uuid.UUID) (*IssueDetail, error) {
+	issue, err := s.GetByID(ctx, tenantID, issueID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &IssueDetail{OrderIssue: *issue}
+
+	proof, err := s.q.GetDeliveryProofByOrder(ctx, sqlc.GetDeliveryProofByOrderParams{
+		OrderID:  issue.OrderID,
+		TenantID: tenantID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.Internal("get delivery proof", err)
+	}
+	if err == nil {
+		proof.OtpCode = sql.NullString{}
+		detail.DeliveryProof = &proof
+	}
+	return detail, nil
+}
+
+// ListByTenant returns paginated issues for a tenant. restaurantIDs limits
+// the list to orders from those restaurants; nil lists every issue.
+func (s *Service) ListByTenant(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, page, perPage int) ([]sqlc.OrderIssue, pagination.Meta, error) {
 	limit, offset := pagination.FormatLimitOffset(page, perPage)
-	total, err := s.q.CountOrderIssuesByTenant(ctx, tenantID)
+	total, err := s.q.CountOrderIssuesByTenant(ctx, sqlc.CountOrderIssuesByTenantParams{
+		TenantID:      tenantID,
+		RestaurantIds: restaurantIDs,
+	})
 	if err != nil {
 		return nil, pagination.Meta{}, apperror.Internal("count issues", err)
 	}
 	items, err := s.q.ListOrderIssuesByTenant(ctx, sqlc.ListOrderIssuesByTenantParams{
-		TenantID: tenantID,
-		Limit:    int32(limit),
-		Offset:   int32(offset),
+		TenantID:      tenantID,
+		RestaurantIds: restaurantIDs,
+		Limit:         int32(limit),
+		Offset:        int32(offset),
 	})
 	if err != nil {
 		return nil, pagination.Meta{}, apperror.Internal("list issues", err)
@@ -93,14 +134,31 @@ func (s *Service) ListByTenant(ctx context.Context, tenantID uuid.UUID, page, pe
 	return items, pagination.NewMeta(total, limit, ""), nil
 }
 
-// Resolve resolves an order issue.
-func (s *Service) Resolve(ctx context.Context, tenantID, issueID, resolvedByID uuid.UUID, note string) (*sqlc.OrderIssue, error) {
-	issue, err := s.q.UpdateOrderIssueStatus(ctx, sqlc.UpdateOrderIssueStatusParams{
-		ID:             issueID,
-		TenantID:       tenantID,
-		Status:         sqlc.IssueStatusResolved,
-		ResolutionNote: toNullStringVal(note),
-		ResolvedByID:   toPgUUID(resolvedByID),
+// ResolveIssueInput is an admin's ruling on an issue. Nil fields keep the
+// values already on the issue.
+type ResolveIssueInput struct {
+	Note                    string
+	AccountableParty        *sqlc.Accountable
+	RiderPenaltyAmount      *decimal.Decimal
+	RestaurantPenaltyAmount *decimal.Decimal
+	// RestaurantID names the restaurant at fault on a multi-restaurant order.
+	RestaurantID *uuid.UUID
+}
+
+// Resolve resolves an open order issue. When the rider is held accountable
+// their penalty is raised against them, and when a restaurant is, the penalty
+// is charged on its next invoice.
+func (s *Service) Resolve(ctx context.Context, tenantID, issueID, resolvedByID uuid.UUID, in ResolveIssueInput) (*sqlc.OrderIssue, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	existing, err := qtx.GetOrderIssueByID(ctx, sqlc.GetOrderIssueByIDParams{
+		ID:       issueID,
+		TenantID: tenantID,
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, apperror.NotFound("issue")
@@ -108,9 +166,130 @@ func (s *Service) Resolve(ctx context.Context, tenantID, issueID, resolvedByID u
 	if err != nil {
 		return nil, err
 	}
+	if existing.Status != sqlc.IssueStatusOpen {
+		return nil, apperror.Conflict("issue is already " + string(existing.Status))
+	}
+
+	party := existing.AccountableParty
+	if in.AccountableParty != nil {
+		party = *in.AccountableParty
+	}
+	riderPenalty := pgNumericToDecimal(existing.RiderPenaltyAmount)
+	if in.RiderPenaltyAmount != nil {
+		riderPenalty = *in.RiderPenaltyAmount
+	}
+	restaurantPenalty := pgNumericToDecimal(existing.RestaurantPenaltyAmount)
+	if in.RestaurantPenaltyAmount != nil {
+		restaurantPenalty = *in.RestaurantPenaltyAmount
+	}
+	if riderPenalty.IsNegative() || restaurantPenalty.IsNegative() {
+		return nil, apperror.BadRequest("penalty amounts cannot be negative")
+	}
+
+	issue, err := qtx.ResolveOrderIssue(ctx, sqlc.ResolveOrderIssueParams{
+		AccountableParty:        party,
+		RiderPenaltyAmount:      toPgNumeric(riderPenalty),
+		RestaurantPenaltyAmount: toPgNumeric(restaurantPenalty),
+		ResolutionNote:          toNullStringVal(in.Note),
+		ResolvedByID:            toPgUUID(resolvedByID),
+		ID:                      issueID,
+		TenantID:                tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.Conflict("issue is already resolved")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case party == sqlc.AccountableRider && riderPenalty.IsPositive():
+		if err := penalizeRider(ctx, qtx, issue, riderPenalty); err != nil {
+			return nil, err
+		}
+	case party == sqlc.AccountableRestaurant && restaurantPenalty.IsPositive():
+		if err := chargeRestaurant(ctx, qtx, issue, restaurantPenalty, in.RestaurantID, resolvedByID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit", err)
+	}
 	return &issue, nil
 }
 
+// penalizeRider raises the issue's penalty against the order's rider. It is
+// deducted from their next payout unless they appeal it.
+func penalizeRider(ctx context.Context, q *sqlc.Queries, issue sqlc.OrderIssue, amount decimal.Decimal) error {
+	order, err := q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: issue.OrderID, TenantID: issue.TenantID})
+	if err != nil {
+		return apperror.Internal("get order", err)
+	}
+	if !order.RiderID.Valid {
+		return apperror.BadRequest("the order has no rider to hold accountable")
+	}
+	_, err = q.CreateIssuePenalty(ctx, sqlc.CreateIssuePenaltyParams{
+		RiderID:  order.RiderID.Bytes,
+		TenantID: issue.TenantID,
+		OrderID:  toPgUUID(issue.OrderID),
+		IssueID:  toPgUUID(issue.ID),
+		Reason:   "Order issue: " + string(issue.IssueType),
+		Amount:   toPgNumeric(amount),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return apperror.Internal("create rider penalty", err)
+	}
+	return nil
+}
+
+// chargeRestaurant records the issue's penalty as an adjustment on the
+// restaurant's next invoice.
+func chargeRestaurant(ctx context.Context, q *sqlc.Queries, issue sqlc.OrderIssue, amount decimal.Decimal, restaurantID *uuid.UUID, createdBy uuid.UUID) error {
+	pickups, err := q.ListPickupsByOrder(ctx, sqlc.ListPickupsByOrderParams{OrderID: issue.OrderID, TenantID: issue.TenantID})
+	if err != nil {
+		return apperror.Internal("list pickups", err)
+	}
+	restaurant, err := accountableRestaurant(pickups, restaurantID)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateIssueInvoiceAdjustment(ctx, sqlc.CreateIssueInvoiceAdjustmentParams{
+		TenantID:     issue.TenantID,
+		RestaurantID: restaurant,
+		OrderID:      toPgUUID(issue.OrderID),
+		IssueID:      toPgUUID(issue.ID),
+		Amount:       toPgNumeric(amount),
+		Reason:       "Order issue: " + string(issue.IssueType),
+		CreatedBy:    toPgUUID(createdBy),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return apperror.Internal("create invoice adjustment", err)
+	}
+	return nil
+}
+
+// accountableRestaurant picks the restaurant an issue is charged to. A single
+// restaurant order needs no choice; otherwise the requested restaurant must
+// be one the order was picked up from.
+func accountableRestaurant(pickups []sqlc.OrderPickup, requested *uuid.UUID) (uuid.UUID, error) {
+	if requested != nil {
+		for _, p := range pickups {
+			if p.RestaurantID == *requested {
+				return *requested, nil
+			}
+		}
+		return uuid.Nil, apperror.BadRequest("restaurant_id is not part of this order")
+	}
+	if len(pickups) == 1 {
+		return pickups[0].RestaurantID, nil
+	}
+	if len(pickups) == 0 {
+		return uuid.Nil, apperror.BadRequest("the order has no restaurant to hold accountable")
+	}
+	return uuid.Nil, apperror.BadRequest("restaurant_id is required for multi-restaurant orders")
+}
+
 // ApproveRefund approves a refund for an issue.
 func (s *Service) ApproveRefund(ctx context.Context, tenantID, issueID uuid.UUID) (*sqlc.OrderIssue, error) {
 	existing, err := s.q.GetOrderIssueByID(ctx, sqlc.GetOrderIssueByIDParams{
@@ -209,3 +388,16 @@ func toNullStringVal(s string) sql.NullString {
 	}
 	return sql.NullString{String: s, Valid: true}
 }
+
+func pgNumericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
+
+func toPgNumeric(d decimal.Decimal) pgtype.Numeric {
+	n := pgtype.Numeric{}
+	_ = n.Scan(d.Round(2).String())
+	return n
+}
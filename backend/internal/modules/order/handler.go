@@ -8,22 +8,25 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
 	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/munchies/platform/backend/internal/platform/documents"
 	"github.com/shopspring/decimal"
 )
 
 // Handler handles order HTTP requests.
 type Handler struct {
-	svc *Service
+	svc  *Service
+	docs *documents.Kit
 }
 
 // NewHandler creates a new order handler.
-func NewHandler(svc *Service) *Handler {
-	return &Handler{svc: svc}
+func NewHandler(svc *Service, docs *documents.Kit) *Handler {
+	return &Handler{svc: svc, docs: docs}
 }
 
 // CalculateCharges handles POST /api/v1/orders/charges/calculate
@@ -53,6 +56,7 @@ func (h *Handler) CalculateCharges(w http.ResponseWriter, r *http.Request) {
 		} `json:"items"`
 		PromoCode    string `json:"promo_code"`
 		DeliveryArea string `json:"delivery_area"`
+		RiderTip     string `json:"rider_tip"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -65,12 +69,19 @@ func (h *Handler) CalculateCharges(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	riderTip, err := parseRiderTip(req.RiderTip)
+	if err != nil {
+		respond.Error(w, err.(*apperror.AppError))
+		return
+	}
+
 	result, err := h.svc.CalculateCharges(r.Context(), CalculateChargesRequest{
 		TenantID:     t.ID,
 		UserID:       u.ID,
 		Items:        items,
 		PromoCode:    req.PromoCode,
 		DeliveryArea: req.DeliveryArea,
+		RiderTip:     riderTip,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -122,6 +133,7 @@ func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		IsReorder              bool            `json:"is_reorder"`
 		AutoConfirmMinutes     *int            `json:"auto_confirm_minutes"`
 		EstimatedDeliveryMins  *int32          `json:"estimated_delivery_minutes"`
+		RiderTip               string          `json:"rider_tip"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -249,6 +261,12 @@ func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		geoLng = &v
 	}
 
+	riderTip, err := parseRiderTip(req.RiderTip)
+	if err != nil {
+		respond.Error(w, err.(*apperror.AppError))
+		return
+	}
+
 	result, err := h.svc.CreateOrder(r.Context(), CreateOrderRequest{
 		TenantID:               t.ID,
 		CustomerID:             u.ID,
@@ -268,6 +286,7 @@ func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		IsReorder:              req.IsReorder,
 		AutoConfirmMinutes:     req.AutoConfirmMinutes,
 		EstimatedDeliveryMins:  req.EstimatedDeliveryMins,
+		RiderTip:               riderTip,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -321,6 +340,97 @@ func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, result)
 }
 
+// GetReceipt handles GET /api/v1/orders/{id}/receipt?type=receipt|vat_challan
+func (h *Handler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order id"))
+		return
+	}
+
+	data, err := h.svc.GetReceipt(r.Context(), t.ID, orderID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	// Ensure customer can only download their own receipts
+	if data.Order.CustomerID != u.ID && u.Role == sqlc.UserRoleCustomer {
+		respond.Error(w, apperror.Forbidden("access denied"))
+		return
+	}
+
+	h.writeReceipt(w, r, t, data)
+}
+
+// GetReceiptPartner handles GET /api/v1/partner/orders/{id}/receipt. Staff
+// scoped to some restaurants only see those restaurants' part of the order.
+func (h *Handler) GetReceiptPartner(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order id"))
+		return
+	}
+
+	data, err := h.svc.GetReceipt(r.Context(), t.ID, orderID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	if scope := access.FromContext(r.Context()); scope != nil && !scope.TenantWide {
+		data.Restrict(scope.Allows)
+	}
+
+	h.writeReceipt(w, r, t, data)
+}
+
+// writeReceipt renders the receipt type asked for in ?type= as a PDF download.
+func (h *Handler) writeReceipt(w http.ResponseWriter, r *http.Request, t *sqlc.Tenant, data *ReceiptData) {
+	kind := r.URL.Query().Get("type")
+	var (
+		pdfBytes []byte
+		err      error
+		name     = data.Order.OrderNumber
+	)
+	switch kind {
+	case "", ReceiptTypeReceipt:
+		pdfBytes, err = GenerateReceiptPDF(r.Context(), h.docs, t, data)
+		name += "-receipt"
+	case ReceiptTypeVATChallan:
+		pdfBytes, err = GenerateVATChallanPDF(r.Context(), h.docs, t, data)
+		name += "-vat-challan"
+	default:
+		respond.Error(w, apperror.BadRequest("type must be receipt or vat_challan"))
+		return
+	}
+	if err != nil {
+		respond.Error(w, apperror.Internal("failed to generate PDF", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".pdf\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdfBytes)
+}
+
 // TrackOrder handles GET /api/v1/orders/{id}/tracking (SSE stream)
 func (h *Handler) TrackOrder(w http.ResponseWriter, r *http.Request) {
 	u := auth.UserFromContext(r.Context())
@@ -756,6 +866,17 @@ func parseCartItems(items []struct {
 	return cartItems, nil
 }
 
+func parseRiderTip(v string) (decimal.Decimal, error) {
+	if v == "" {
+		return decimal.Zero, nil
+	}
+	tip, err := decimal.NewFromString(v)
+	if err != nil || tip.IsNegative() {
+		return decimal.Zero, apperror.BadRequest("invalid rider_tip")
+	}
+	return tip.Round(2), nil
+}
+
 func parsePagination(r *http.Request) (page, perPage int) {
 	q := r.URL.Query()
 	page, _ = strconv.Atoi(q.Get("page"))
@@ -0,0 +1,400 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/pdf"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/shopspring/decimal"
+)
+
+// Receipt document types served by /orders/{id}/receipt.
+const (
+	ReceiptTypeReceipt    = "receipt"
+	ReceiptTypeVATChallan = "vat_challan"
+)
+
+// ReceiptData is the content of an order receipt or VAT challan.
+type ReceiptData struct {
+	Order       sqlc.Order
+	Items       []sqlc.OrderItem
+	Pickups     []sqlc.OrderPickup
+	Restaurants map[uuid.UUID]sqlc.Restaurant
+	// Partial is set when the document covers only some of the order's
+	// restaurants, so order-level charges and totals are left out.
+	Partial bool
+}
+
+// GetReceipt loads what an order's receipt shows. Receipts are issued once
+// the order is paid or delivered.
+func (s *Service) GetReceipt(ctx context.Context, tenantID, orderID uuid.UUID) (*ReceiptData, error) {
+	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("order")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get order", err)
+	}
+	if order.Status != sqlc.OrderStatusDelivered && order.PaymentStatus != sqlc.PaymentStatusPaid {
+		return nil, apperror.BadRequest("a receipt is available once the order is paid or delivered")
+	}
+
+	items, err := s.q.GetOrderItemsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, apperror.Internal("get order items", err)
+	}
+	pickups, err := s.q.GetOrderPickupsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, apperror.Internal("get order pickups", err)
+	}
+
+	data := &ReceiptData{Order: order, Items: items, Pickups: pickups, Restaurants: map[uuid.UUID]sqlc.Restaurant{}}
+	for _, p := range pickups {
+		rest, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: p.RestaurantID, TenantID: tenantID})
+		if err != nil {
+			return nil, apperror.Internal("get restaurant", err)
+		}
+		data.Restaurants[p.RestaurantID] = rest
+	}
+	return data, nil
+}
+
+// Restrict drops the items and pickups of restaurants not allowed, for staff
+// who only see their own restaurants' part of an order.
+func (d *ReceiptData) Restrict(allowed func(uuid.UUID) bool) {
+	var pickups []sqlc.OrderPickup
+	for _, p := range d.Pickups {
+		if allowed(p.RestaurantID) {
+			pickups = append(pickups, p)
+		}
+	}
+	var items []sqlc.OrderItem
+	for _, it := range d.Items {
+		if allowed(it.RestaurantID) {
+			items = append(items, it)
+		}
+	}
+	if len(pickups) < len(d.Pickups) {
+		d.Partial = true
+	}
+	d.Pickups, d.Items = pickups, items
+}
+
+// GenerateReceiptPDF renders the customer receipt: the items of each
+// restaurant, the charges and the payment.
+func GenerateReceiptPDF(ctx context.Context, kit *documents.Kit, t *sqlc.Tenant, data *ReceiptData) ([]byte, error) {
+	o := data.Order
+	brand := kit.Brand(ctx, t)
+	loc := documents.Location(t)
+
+	d := kit.Document("Receipt "+o.OrderNumber, t.Name)
+	fields := []pdf.Field{
+		{Label: "Order no", Value: o.OrderNumber},
+		{Label: "Ordered", Value: o.CreatedAt.In(loc).Format("2 Jan 2006, 3:04 PM")},
+	}
+	if o.DeliveredAt.Valid {
+		fields = append(fields, pdf.Field{Label: "Delivered", Value: o.DeliveredAt.Time.In(loc).Format("2 Jan 2006, 3:04 PM")})
+	}
+	fields = append(fields, pdf.Field{Label: "Payment", Value: paymentLabel(o)})
+	d.Letterhead(brand, "RECEIPT", fields)
+
+	d.Heading("Delivered to", brand.Color)
+	d.Fields(recipientFields(o))
+	d.Space(6)
+
+	currency := t.Currency
+	for _, p := range data.Pickups {
+		rest := data.Restaurants[p.RestaurantID]
+		d.Heading(rest.Name+"  ·  "+p.PickupNumber, brand.Color)
+		var rows []pdf.Row
+		for _, it := range data.Items {
+			if it.RestaurantID != p.RestaurantID {
+				continue
+			}
+			unit := numericToDecimal(it.UnitPrice).Add(numericToDecimal(it.ModifierPrice))
+			discount := numericToDecimal(it.ItemDiscount).Add(numericToDecimal(it.PromoDiscount))
+			name := it.ProductName
+			if it.SpecialInstructions.Valid && it.SpecialInstructions.String != "" {
+				name += "\nNote: " + it.SpecialInstructions.String
+			}
+			rows = append(rows, pdf.Row{Cells: []string{
+				name,
+				decimal.NewFromInt32(it.Quantity).String(),
+				documents.Money(unit),
+				negative(discount),
+				money(it.ItemVat),
+				money(it.ItemTotal),
+			}})
+		}
+		d.Table(pdf.Table{
+			Columns: []pdf.Column{
+				{Title: "Item", Width: 4},
+				{Title: "Qty", Width: 0.8, Align: pdf.AlignRight},
+				{Title: "Unit price", Width: 1.5, Align: pdf.AlignRight},
+				{Title: "Discount", Width: 1.4, Align: pdf.AlignRight},
+				{Title: "VAT", Width: 1.2, Align: pdf.AlignRight},
+				{Title: "Total", Width: 1.5, Align: pdf.AlignRight},
+			},
+			Rows:   rows,
+			Accent: brand.Color,
+		})
+		d.Space(8)
+	}
+
+	d.Heading("Payment summary", brand.Color)
+	d.Table(pdf.Table{
+		Columns: []pdf.Column{{Title: "Description", Width: 3}, {Title: "Amount (" + currency + ")", Width: 1, Align: pdf.AlignRight}},
+		Rows:    summaryRows(data),
+		Accent:  brand.Color,
+	})
+	if o.CustomerNote.Valid && o.CustomerNote.String != "" {
+		d.Space(6)
+		d.Paragraph("Note: "+o.CustomerNote.String, pdf.Style{Size: 9, Color: pdf.Gray}, pdf.AlignLeft)
+	}
+	d.Space(12)
+	d.Paragraph("Thank you for ordering with "+t.Name+".", pdf.Style{Size: 10, Bold: true, Color: brand.Color}, pdf.AlignCenter)
+	return d.Bytes()
+}
+
+// summaryRows lists the order's charges. Partial receipts only total the
+// items of the restaurants they cover.
+func summaryRows(data *ReceiptData) []pdf.Row {
+	o := data.Order
+	if data.Partial {
+		var subtotal, discount, vat, total decimal.Decimal
+		for _, p := range data.Pickups {
+			subtotal = subtotal.Add(numericToDecimal(p.ItemsSubtotal))
+			discount = discount.Add(numericToDecimal(p.ItemsDiscount))
+			vat = vat.Add(numericToDecimal(p.ItemsVat))
+			total = total.Add(numericToDecimal(p.ItemsTotal))
+		}
+		return []pdf.Row{
+			{Cells: []string{"Subtotal", documents.Money(subtotal)}},
+			{Cells: []string{"Item discounts", negative(discount)}},
+			{Cells: []string{"VAT", documents.Money(vat)}},
+			{Cells: []string{"Items total", documents.Money(total)}, Bold: true},
+		}
+	}
+
+	rows := []pdf.Row{
+		{Cells: []string{"Subtotal", money(o.Subtotal)}},
+		{Cells: []string{"Item discounts", negative(numericToDecimal(o.ItemDiscountTotal))}},
+	}
+	if promo := numericToDecimal(o.PromoDiscountTotal); !promo.IsZero() {
+		label := "Promo discount"
+		if o.PromoCode.Valid {
+			label += " (" + o.PromoCode.String + ")"
+		}
+		rows = append(rows, pdf.Row{Cells: []string{label, negative(promo)}})
+	}
+	rows = append(rows,
+		pdf.Row{Cells: []string{"VAT", money(o.VatTotal)}},
+		pdf.Row{Cells: []string{"Delivery charge", money(o.DeliveryCharge)}},
+	)
+	if fee := numericToDecimal(o.ServiceFee); !fee.IsZero() {
+		rows = append(rows, pdf.Row{Cells: []string{"Service fee", documents.Money(fee)}})
+	}
+	if tip := numericToDecimal(o.RiderTip); !tip.IsZero() {
+		rows = append(rows, pdf.Row{Cells: []string{"Rider tip", documents.Money(tip)}})
+	}
+	rows = append(rows, pdf.Row{Cells: []string{"Total", money(o.TotalAmount)}, Bold: true})
+	if o.PaymentStatus == sqlc.PaymentStatusPaid {
+		rows = append(rows, pdf.Row{Cells: []string{"Paid (" + paymentMethodLabel(o.PaymentMethod) + ")", money(o.TotalAmount)}})
+	}
+	return rows
+}
+
+// challanLine is one restaurant's taxable supply on an order.
+type challanLine struct {
+	Restaurant sqlc.Restaurant
+	Taxable    decimal.Decimal
+	Rate       decimal.Decimal
+	VAT        decimal.Decimal
+}
+
+// vatChallanLines totals each restaurant's taxable value (after item
+// discounts) and VAT, in pickup order.
+func vatChallanLines(data *ReceiptData) []challanLine {
+	lines := make([]challanLine, 0, len(data.Pickups))
+	for _, p := range data.Pickups {
+		rest := data.Restaurants[p.RestaurantID]
+		line := challanLine{Restaurant: rest, Rate: numericToDecimal(rest.VatRate)}
+		for _, it := range data.Items {
+			if it.RestaurantID != p.RestaurantID {
+				continue
+			}
+			line.Taxable = line.Taxable.Add(numericToDecimal(it.ItemSubtotal).Sub(numericToDecimal(it.ItemDiscount)))
+			line.VAT = line.VAT.Add(numericToDecimal(it.ItemVat))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// GenerateVATChallanPDF renders the VAT challan of an order: each
+// restaurant's taxable supply, VAT rate and VAT charged.
+func GenerateVATChallanPDF(ctx context.Context, kit *documents.Kit, t *sqlc.Tenant, data *ReceiptData) ([]byte, error) {
+	o := data.Order
+	brand := kit.Brand(ctx, t)
+	loc := documents.Location(t)
+
+	d := kit.Document("VAT challan "+o.OrderNumber, t.Name)
+	supplied := o.CreatedAt
+	if o.DeliveredAt.Valid {
+		supplied = o.DeliveredAt.Time
+	}
+	d.Letterhead(brand, "VAT CHALLAN", []pdf.Field{
+		{Label: "Challan no", Value: o.OrderNumber},
+		{Label: "Date of supply", Value: supplied.In(loc).Format("2 Jan 2006")},
+		{Label: "Time of supply", Value: supplied.In(loc).Format("3:04 PM")},
+	})
+
+	d.Heading("Buyer", brand.Color)
+	d.Fields(recipientFields(o))
+	d.Space(6)
+
+	lines := vatChallanLines(data)
+	d.Heading("Sellers", brand.Color)
+	var sellers []pdf.Field
+	for i, l := range lines {
+		value := l.Restaurant.Name
+		if addr := restaurantAddress(l.Restaurant); addr != "" {
+			value += "\n" + addr
+		}
+		sellers = append(sellers, pdf.Field{Label: fmt.Sprintf("Seller %d", i+1), Value: value})
+	}
+	d.Fields(sellers)
+	d.Space(6)
+
+	currency := t.Currency
+	d.Heading("Taxable supplies", brand.Color)
+	var rows []pdf.Row
+	var taxable, vat decimal.Decimal
+	for _, l := range lines {
+		rows = append(rows, pdf.Row{Cells: []string{
+			l.Restaurant.Name,
+			documents.Money(l.Taxable),
+			l.Rate.StringFixed(2) + "%",
+			documents.Money(l.VAT),
+			documents.Money(l.Taxable.Add(l.VAT)),
+		}})
+		taxable = taxable.Add(l.Taxable)
+		vat = vat.Add(l.VAT)
+	}
+	rows = append(rows, pdf.Row{Cells: []string{"Total", documents.Money(taxable), "", documents.Money(vat), documents.Money(taxable.Add(vat))}, Bold: true})
+	d.Table(pdf.Table{
+		Columns: []pdf.Column{
+			{Title: "Seller", Width: 3},
+			{Title: "Taxable value (" + currency + ")", Width: 2, Align: pdf.AlignRight},
+			{Title: "VAT rate", Width: 1.2, Align: pdf.AlignRight},
+			{Title: "VAT (" + currency + ")", Width: 1.6, Align: pdf.AlignRight},
+			{Title: "Total (" + currency + ")", Width: 1.8, Align: pdf.AlignRight},
+		},
+		Rows:   rows,
+		Accent: brand.Color,
+	})
+	d.Space(10)
+	d.Paragraph("Delivery charges, service fees and rider tips are not included in the taxable value above.", pdf.Style{Size: 8.5, Color: pdf.Gray}, pdf.AlignLeft)
+	return d.Bytes()
+}
+
+// recipientFields describes who an order was delivered to.
+func recipientFields(o sqlc.Order) []pdf.Field {
+	fields := []pdf.Field{
+		{Label: "Name", Value: o.DeliveryRecipientName},
+		{Label: "Phone", Value: o.DeliveryRecipientPhone},
+	}
+	if addr := deliveryAddress(o.DeliveryAddress, o.DeliveryArea); addr != "" {
+		fields = append(fields, pdf.Field{Label: "Address", Value: addr})
+	}
+	return fields
+}
+
+// deliveryAddress formats the address snapshot stored on an order.
+func deliveryAddress(raw json.RawMessage, area string) string {
+	var a struct {
+		AddressLine1 string `json:"address_line1"`
+		AddressLine2 string `json:"address_line2"`
+		Area         string `json:"area"`
+		City         string `json:"city"`
+	}
+	_ = json.Unmarshal(raw, &a)
+	if a.Area == "" {
+		a.Area = area
+	}
+	var parts []string
+	for _, p := range []string{a.AddressLine1, a.AddressLine2, a.Area, a.City} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// restaurantAddress joins the non-empty parts of a restaurant's address.
+func restaurantAddress(r sqlc.Restaurant) string {
+	var parts []string
+	for _, p := range []string{r.AddressLine1.String, r.AddressLine2.String, r.Area.String, r.City} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// paymentMethodLabels names payment methods on receipts.
+var paymentMethodLabels = map[sqlc.PaymentMethod]string{
+	sqlc.PaymentMethodCod:        "Cash on delivery",
+	sqlc.PaymentMethodBkash:      "bKash",
+	sqlc.PaymentMethodAamarpay:   "aamarPay",
+	sqlc.PaymentMethodSslcommerz: "SSLCommerz",
+	sqlc.PaymentMethodWallet:     "Wallet",
+	sqlc.PaymentMethodCard:       "Card",
+}
+
+// paymentStatusLabels names payment statuses on receipts.
+var paymentStatusLabels = map[sqlc.PaymentStatus]string{
+	sqlc.PaymentStatusUnpaid:            "Unpaid",
+	sqlc.PaymentStatusPaid:              "Paid",
+	sqlc.PaymentStatusRefunded:          "Refunded",
+	sqlc.PaymentStatusPartiallyRefunded: "Partially refunded",
+}
+
+func paymentLabel(o sqlc.Order) string {
+	return paymentMethodLabel(o.PaymentMethod) + ", " + paymentStatusLabels[o.PaymentStatus]
+}
+
+func paymentMethodLabel(m sqlc.PaymentMethod) string {
+	if label, ok := paymentMethodLabels[m]; ok {
+		return label
+	}
+	return string(m)
+}
+
+func money(n pgtype.Numeric) string {
+	return documents.Money(numericToDecimal(n))
+}
+
+// negative formats an amount taken off the total.
+func negative(d decimal.Decimal) string {
+	if d.IsZero() {
+		return documents.Money(d)
+	}
+	return documents.Money(d.Neg())
+}
+
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
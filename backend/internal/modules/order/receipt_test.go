@@ -0,0 +1,119 @@
+package order
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/shopspring/decimal"
+)
+
+func num(s string) pgtype.Numeric {
+	var n pgtype.Numeric
+	_ = n.Scan(s)
+	return n
+}
+
+func receiptFixture() *ReceiptData {
+	kacchi, pizza := uuid.New(), uuid.New()
+	return &ReceiptData{
+		Order: sqlc.Order{
+			OrderNumber:            "ORD-1001",
+			Status:                 sqlc.OrderStatusDelivered,
+			PaymentStatus:          sqlc.PaymentStatusPaid,
+			PaymentMethod:          sqlc.PaymentMethodBkash,
+			DeliveryRecipientName:  "Nusrat",
+			DeliveryRecipientPhone: "+8801711000000",
+			DeliveryAddress:        json.RawMessage(`{"address_line1":"House 12, Road 5","area":"Dhanmondi","city":"Dhaka"}`),
+			Subtotal:               num("1100"),
+			ItemDiscountTotal:      num("50"),
+			PromoDiscountTotal:     num("0"),
+			VatTotal:               num("78"),
+			DeliveryCharge:         num("60"),
+			ServiceFee:             num("10"),
+			RiderTip:               num("20"),
+			TotalAmount:            num("1218"),
+			CreatedAt:              time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC),
+		},
+		Items: []sqlc.OrderItem{
+			{RestaurantID: kacchi, ProductName: "Kacchi Biryani", Quantity: 2, UnitPrice: num("350"), ItemSubtotal: num("700"), ItemDiscount: num("50"), ItemVat: num("48"), ItemTotal: num("698")},
+			{RestaurantID: pizza, ProductName: "Margherita", Quantity: 1, UnitPrice: num("400"), ItemSubtotal: num("400"), ItemDiscount: num("0"), ItemVat: num("30"), ItemTotal: num("430")},
+		},
+		Pickups: []sqlc.OrderPickup{
+			{RestaurantID: kacchi, PickupNumber: "P-1", ItemsSubtotal: num("700"), ItemsDiscount: num("50"), ItemsVat: num("48"), ItemsTotal: num("698")},
+			{RestaurantID: pizza, PickupNumber: "P-2", ItemsSubtotal: num("400"), ItemsDiscount: num("0"), ItemsVat: num("30"), ItemsTotal: num("430")},
+		},
+		Restaurants: map[uuid.UUID]sqlc.Restaurant{
+			kacchi: {ID: kacchi, Name: "Kacchi Bhai", VatRate: num("7.5"), City: "Dhaka"},
+			pizza:  {ID: pizza, Name: "Pizza Hut", VatRate: num("7.5"), City: "Dhaka"},
+		},
+	}
+}
+
+func TestVATChallanLines(t *testing.T) {
+	lines := vatChallanLines(receiptFixture())
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one per restaurant", len(lines))
+	}
+	want := []struct{ name, taxable, vat string }{{"Kacchi Bhai", "650", "48"}, {"Pizza Hut", "400", "30"}}
+	for i, w := range want {
+		l := lines[i]
+		if l.Restaurant.Name != w.name || !l.Taxable.Equal(decimal.RequireFromString(w.taxable)) || !l.VAT.Equal(decimal.RequireFromString(w.vat)) {
+			t.Errorf("line %d = %s %s %s, want %s %s %s", i, l.Restaurant.Name, l.Taxable, l.VAT, w.name, w.taxable, w.vat)
+		}
+		if !l.Rate.Equal(decimal.RequireFromString("7.5")) {
+			t.Errorf("line %d rate = %s", i, l.Rate)
+		}
+	}
+}
+
+func TestRestrictReceipt(t *testing.T) {
+	data := receiptFixture()
+	kacchi := data.Pickups[0].RestaurantID
+	data.Restrict(func(id uuid.UUID) bool { return id == kacchi })
+	if !data.Partial || len(data.Pickups) != 1 || len(data.Items) != 1 || data.Items[0].RestaurantID != kacchi {
+		t.Fatalf("restricted receipt = partial %v, %d pickups, %d items", data.Partial, len(data.Pickups), len(data.Items))
+	}
+	rows := summaryRows(data)
+	if last := rows[len(rows)-1]; last.Cells[0] != "Items total" || last.Cells[1] != "698.00" {
+		t.Errorf("partial total = %q", last.Cells)
+	}
+
+	full := receiptFixture()
+	full.Restrict(func(uuid.UUID) bool { return true })
+	if full.Partial {
+		t.Error("a receipt covering every restaurant is not partial")
+	}
+}
+
+func TestDeliveryAddress(t *testing.T) {
+	if got := deliveryAddress(json.RawMessage(`{"address_line1":"House 12","area":"","city":"Dhaka"}`), "Gulshan"); got != "House 12, Gulshan, Dhaka" {
+		t.Errorf("address = %q", got)
+	}
+	if got := deliveryAddress(json.RawMessage(`{}`), ""); got != "" {
+		t.Errorf("empty address = %q", got)
+	}
+}
+
+func TestGenerateReceiptDocuments(t *testing.T) {
+	kit := documents.New(documents.Config{})
+	tenant := &sqlc.Tenant{Name: "Munchies", PrimaryColor: "#E23744", Currency: "BDT", Timezone: "Asia/Dhaka"}
+	for name, render := range map[string]func(context.Context, *documents.Kit, *sqlc.Tenant, *ReceiptData) ([]byte, error){
+		"receipt":     GenerateReceiptPDF,
+		"vat challan": GenerateVATChallanPDF,
+	} {
+		out, err := render(context.Background(), kit, tenant, receiptFixture())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.HasPrefix(out, []byte("%PDF-")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+			t.Errorf("%s is not a PDF", name)
+		}
+	}
+}
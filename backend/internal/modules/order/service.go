@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/analytics"
 	"github.com/munchies/platform/backend/internal/modules/inventory"
 	"github.com/munchies/platform/backend/internal/modules/promo"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
@@ -59,6 +60,7 @@ type CalculateChargesRequest struct {
 	PromoCode     string
 	DeliveryArea  string
 	PaymentMethod string
+	RiderTip      decimal.Decimal
 }
 
 // ChargeBreakdown is the response for charge pre-calculation.
@@ -66,11 +68,14 @@ type ChargeBreakdown struct {
 	Subtotal           decimal.Decimal          `json:"subtotal"`
 	ItemDiscountTotal  decimal.Decimal          `json:"item_discount_total"`
 	PromoDiscountTotal decimal.Decimal          `json:"promo_discount_total"`
+	DeliveryDiscount   decimal.Decimal          `json:"delivery_discount"`
 	VatTotal           decimal.Decimal          `json:"vat_total"`
 	DeliveryCharge     decimal.Decimal          `json:"delivery_charge"`
 	ServiceFee         decimal.Decimal          `json:"service_fee"`
+	RiderTip           decimal.Decimal          `json:"rider_tip"`
 	TotalAmount        decimal.Decimal          `json:"total_amount"`
 	PromoResult        *promo.PromoValidationResult `json:"promo_result,omitempty"`
+	AppliedPromos      []promo.PromoValidationResult `json:"applied_promos"`
 	Items              []ItemBreakdown          `json:"items"`
 }
 
@@ -108,6 +113,7 @@ type CreateOrderRequest struct {
 	IsReorder              bool
 	AutoConfirmMinutes     *int
 	EstimatedDeliveryMins  *int32
+	RiderTip               decimal.Decimal
 }
 
 // OrderDetail is the full order response including items and pickups.
@@ -123,6 +129,9 @@ func (s *Service) CalculateCharges(ctx context.Context, req CalculateChargesRequ
 	if len(req.Items) == 0 {
 		return nil, apperror.BadRequest("at least one item is required")
 	}
+	if req.RiderTip.IsNegative() {
+		return nil, apperror.BadRequest("rider tip cannot be negative")
+	}
 
 	breakdown := &ChargeBreakdown{
 		Items: make([]ItemBreakdown, 0, len(req.Items)),
@@ -157,39 +166,30 @@ func (s *Service) CalculateCharges(ctx context.Context, req CalculateChargesRequ
 		vatTotal = vatTotal.Add(item.ItemVat)
 	}
 
-	// Apply promo if provided
-	promoDiscountTotal := decimal.Zero
-	if req.PromoCode != "" {
-		cartItems := make([]promo.CartItem, 0, len(req.Items))
-		for _, item := range req.Items {
-			cartItems = append(cartItems, promo.CartItem{
-				ProductID:    item.ProductID,
-				RestaurantID: item.RestaurantID,
-				CategoryID:   item.CategoryID,
-				Quantity:     item.Quantity,
-				UnitPrice:    item.UnitPrice,
-				ItemSubtotal: item.UnitPrice.Add(item.ModifierPrice).Mul(decimal.NewFromInt32(item.Quantity)),
-			})
-		}
-
-		result, err := s.promoSvc.Validate(ctx, req.TenantID, req.UserID, req.PromoCode, subtotal, cartItems)
-		if err != nil {
-			return nil, err
-		}
-		breakdown.PromoResult = result
-		if result.Valid {
-			promoDiscountTotal = result.DiscountAmount
-		}
-	}
-
 	// Calculate delivery charge (simplified zone-based)
 	deliveryCharge := decimal.NewFromInt(60) // default delivery charge in BDT
 	serviceFee := decimal.Zero
 
+	// Apply the entered code and any auto-apply promos
+	offer, err := s.promoSvc.BestOffer(ctx, req.TenantID, req.UserID, req.PromoCode, promoCart(req.Items, subtotal, deliveryCharge))
+	if err != nil {
+		return nil, err
+	}
+	breakdown.PromoResult = offer.CodeResult
+	breakdown.AppliedPromos = offer.Promos
+	promoDiscountTotal := offer.DiscountAmount
+	breakdown.DeliveryDiscount = offer.DeliveryDiscount
+	for idx, disc := range offer.LineDiscounts() {
+		breakdown.Items[idx].PromoDiscount = disc
+		breakdown.Items[idx].ItemTotal = breakdown.Items[idx].ItemTotal.Sub(disc)
+	}
+
 	totalAmount := subtotal.Sub(itemDiscountTotal).Sub(promoDiscountTotal).Add(vatTotal).Add(deliveryCharge).Add(serviceFee)
 	if totalAmount.IsNegative() {
 		totalAmount = decimal.Zero
 	}
+	// The tip goes to the rider in full, so discounts never reduce it.
+	totalAmount = totalAmount.Add(req.RiderTip)
 
 	breakdown.Subtotal = subtotal
 	breakdown.ItemDiscountTotal = itemDiscountTotal
@@ -197,6 +197,7 @@ func (s *Service) CalculateCharges(ctx context.Context, req CalculateChargesRequ
 	breakdown.VatTotal = vatTotal
 	breakdown.DeliveryCharge = deliveryCharge
 	breakdown.ServiceFee = serviceFee
+	breakdown.RiderTip = req.RiderTip
 	breakdown.TotalAmount = totalAmount
 
 	return breakdown, nil
@@ -207,6 +208,9 @@ func (s *Service) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Ord
 	if len(req.Items) == 0 {
 		return nil, apperror.BadRequest("at least one item is required")
 	}
+	if req.RiderTip.IsNegative() {
+		return nil, apperror.BadRequest("rider tip cannot be negative")
+	}
 
 	// Determine initial status based on payment method
 	initialStatus := sqlc.OrderStatusCreated
@@ -282,47 +286,41 @@ func (s *Service) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Ord
 		return nil, err
 	}
 
-	// 4. Validate and apply promo
-	promoDiscountTotal := decimal.Zero
-	var promoID pgtype.UUID
-	var promoCode sql.NullString
-	var promoSnapshot []byte
+	// 4. Delivery charge
+	deliveryCharge := decimal.NewFromInt(60)
+	serviceFee := decimal.Zero
 
-	if req.PromoCode != "" {
-		cartItems := make([]promo.CartItem, 0, len(req.Items))
-		for _, item := range req.Items {
-			cartItems = append(cartItems, promo.CartItem{
-				ProductID:    item.ProductID,
-				RestaurantID: item.RestaurantID,
-				CategoryID:   item.CategoryID,
-				Quantity:     item.Quantity,
-				UnitPrice:    item.UnitPrice,
-				ItemSubtotal: item.UnitPrice.Add(item.ModifierPrice).Mul(decimal.NewFromInt32(item.Quantity)),
-			})
-		}
+	// 5. Pick the best promo combination (entered code plus auto-apply promos)
+	offer, err := s.promoSvc.BestOffer(ctx, req.TenantID, req.CustomerID, req.PromoCode, promoCart(req.Items, subtotal, deliveryCharge))
+	if err != nil {
+		return nil, err
+	}
+	if offer.CodeResult != nil && !offer.CodeResult.Valid {
+		return nil, apperror.BadRequest("promo code invalid: " + offer.CodeResult.ErrorMessage)
+	}
 
-		promoResult, err := s.promoSvc.Validate(ctx, req.TenantID, req.CustomerID, req.PromoCode, subtotal, cartItems)
-		if err != nil {
-			return nil, err
-		}
-		if !promoResult.Valid {
-			return nil, apperror.BadRequest("promo code invalid: " + promoResult.ErrorMessage)
-		}
+	promoDiscountTotal := offer.DiscountAmount
+	for idx, disc := range offer.LineDiscounts() {
+		itemCalcs[idx].PromoDiscount = disc
+		itemCalcs[idx].ItemTotal = itemCalcs[idx].ItemTotal.Sub(disc)
+	}
 
-		promoDiscountTotal = promoResult.DiscountAmount
-		promoID = pgtype.UUID{Bytes: promoResult.PromoID, Valid: true}
-		promoCode = sql.NullString{String: promoResult.Code, Valid: true}
-		snapshot, _ := json.Marshal(promoResult)
+	var promoID pgtype.UUID
+	var promoCode sql.NullString
+	var promoSnapshot []byte
+	if primary := offer.Primary(); primary != nil {
+		promoID = pgtype.UUID{Bytes: primary.PromoID, Valid: true}
+		promoCode = sql.NullString{String: primary.Code, Valid: primary.Code != ""}
+		snapshot, _ := json.Marshal(offer)
 		promoSnapshot = snapshot
 	}
 
-	// 5. Calculate delivery charge and totals
-	deliveryCharge := decimal.NewFromInt(60)
-	serviceFee := decimal.Zero
 	totalAmount := subtotal.Sub(itemDiscountTotal).Sub(promoDiscountTotal).Add(vatTotal).Add(deliveryCharge).Add(serviceFee)
 	if totalAmount.IsNegative() {
 		totalAmount = decimal.Zero
 	}
+	// The tip goes to the rider in full, so discounts never reduce it.
+	totalAmount = totalAmount.Add(req.RiderTip)
 
 	// 6. Auto-confirm timestamp
 	var autoConfirmAt pgtype.Timestamptz
@@ -370,6 +368,8 @@ func (s *Service) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Ord
 	_ = serviceFeePg.Scan(serviceFee.String())
 	totalAmountPg := pgtype.Numeric{Valid: true}
 	_ = totalAmountPg.Scan(totalAmount.String())
+	riderTipPg := pgtype.Numeric{Valid: true}
+	_ = riderTipPg.Scan(req.RiderTip.String())
 
 	order, err := qtx.CreateOrder(ctx, sqlc.CreateOrderParams{
 		TenantID:               req.TenantID,
@@ -401,6 +401,7 @@ func (s *Service) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Ord
 		CustomerNote:           sql.NullString{String: req.CustomerNote, Valid: req.CustomerNote != ""},
 		AutoConfirmAt:          autoConfirmAt,
 		EstimatedDeliveryMinutes: req.EstimatedDeliveryMins,
+		RiderTip:               riderTipPg,
 	})
 	if err != nil {
 		return nil, apperror.Internal("create order", err)
@@ -527,33 +528,9 @@ func (s *Service) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Ord
 		return nil, apperror.Internal("add timeline event", err)
 	}
 
-	// 12. Record promo usage
-	if promoID.Valid {
-		discAmtPg := pgtype.Numeric{Valid: true}
-		_ = discAmtPg.Scan(promoDiscountTotal.String())
-		cbAmtPg := pgtype.Numeric{Valid: true}
-		_ = cbAmtPg.Scan("0")
-
-		_, err = qtx.CreatePromoUsage(ctx, sqlc.CreatePromoUsageParams{
-			PromoID:        promoID.Bytes,
-			UserID:         req.CustomerID,
-			OrderID:        order.ID,
-			TenantID:       req.TenantID,
-			DiscountAmount: discAmtPg,
-			CashbackAmount: cbAmtPg,
-		})
-		if err != nil {
-			return nil, apperror.Internal("create promo usage", err)
-		}
-
-		err = qtx.IncrementPromoUsage(ctx, sqlc.IncrementPromoUsageParams{
-			DiscountAmount: discAmtPg,
-			ID:             promoID.Bytes,
-			TenantID:       req.TenantID,
-		})
-		if err != nil {
-			return nil, apperror.Internal("increment promo usage", err)
-		}
+	// 12. Redeem every applied promo against its usage limits and budget
+	if err := s.promoSvc.Redeem(ctx, qtx, req.TenantID, req.CustomerID, order.ID, offer.Promos); err != nil {
+		return nil, err
 	}
 
 	// 13. Create outbox event for async processing (rider assignment)
@@ -589,6 +566,28 @@ func (s *Service) CreateOrder(ctx context.Context, req CreateOrderRequest) (*Ord
 	return result, nil
 }
 
+// promoCart builds the promo engine's view of the cart. Line order matches
+// items so allocations can be mapped back by index.
+func promoCart(items []CartItemRequest, subtotal, deliveryCharge decimal.Decimal) promo.Cart {
+	cartItems := make([]promo.CartItem, 0, len(items))
+	for _, item := range items {
+		cartItems = append(cartItems, promo.CartItem{
+			ProductID:    item.ProductID,
+			RestaurantID: item.RestaurantID,
+			CategoryID:   item.CategoryID,
+			Quantity:     item.Quantity,
+			UnitPrice:    item.UnitPrice,
+			ItemSubtotal: item.UnitPrice.Add(item.ModifierPrice).Mul(decimal.NewFromInt32(item.Quantity)),
+		})
+	}
+	return promo.Cart{
+		Items:          cartItems,
+		Subtotal:       subtotal,
+		DeliveryCharge: deliveryCharge,
+		At:             time.Now(),
+	}
+}
+
 // GetOrder returns full order details.
 func (s *Service) GetOrder(ctx context.Context, tenantID, orderID uuid.UUID) (*OrderDetail, error) {
 	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{
@@ -713,7 +712,10 @@ func (s *Service) ConfirmOrder(ctx context.Context, tenantID, orderID, restauran
 		RestaurantID:    restaurantID,
 		RejectionReason: sql.NullString{},
 	})
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("pickup for this restaurant")
+	}
+	if err != nil {
 		return nil, apperror.Internal("transition pickup status", err)
 	}
 
@@ -801,7 +803,10 @@ func (s *Service) RejectOrder(ctx context.Context, tenantID, orderID, restaurant
 		RestaurantID:    restaurantID,
 		RejectionReason: sql.NullString{String: reason, Valid: true},
 	})
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("pickup for this restaurant")
+	}
+	if err != nil {
 		return nil, apperror.Internal("transition pickup status", err)
 	}
 
@@ -834,6 +839,9 @@ func (s *Service) RejectOrder(ctx context.Context, tenantID, orderID, restaurant
 		if err != nil {
 			return nil, apperror.Internal("transition order status", err)
 		}
+		if err := analytics.EnqueueOrderFact(ctx, qtx, updated); err != nil {
+			return nil, apperror.Internal("enqueue order fact", err)
+		}
 
 		// Release stock for rejected items
 		items, err := qtx.GetOrderItemsByRestaurant(ctx, sqlc.GetOrderItemsByRestaurantParams{
@@ -854,6 +862,11 @@ func (s *Service) RejectOrder(ctx context.Context, tenantID, orderID, restaurant
 		if err := s.invSvc.ReleaseStockForOrder(ctx, qtx, tenantID, stockReleases); err != nil {
 			return nil, err
 		}
+
+		// Give the promo uses back
+		if err := s.promoSvc.ReleaseOrderPromos(ctx, qtx, tenantID, orderID); err != nil {
+			return nil, err
+		}
 	} else {
 		updated = order
 	}
@@ -910,7 +923,10 @@ func (s *Service) MarkPreparing(ctx context.Context, tenantID, orderID, restaura
 		RestaurantID:    restaurantID,
 		RejectionReason: sql.NullString{},
 	})
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("pickup for this restaurant")
+	}
+	if err != nil {
 		return nil, apperror.Internal("transition pickup status", err)
 	}
 
@@ -983,7 +999,10 @@ func (s *Service) MarkReady(ctx context.Context, tenantID, orderID, restaurantID
 		RestaurantID:    restaurantID,
 		RejectionReason: sql.NullString{},
 	})
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("pickup for this restaurant")
+	}
+	if err != nil {
 		return nil, apperror.Internal("transition pickup status", err)
 	}
 
@@ -1164,6 +1183,11 @@ func (s *Service) CancelOrder(ctx context.Context, tenantID, orderID, customerID
 		return nil, err
 	}
 
+	// Give the promo uses back
+	if err := s.promoSvc.ReleaseOrderPromos(ctx, qtx, tenantID, orderID); err != nil {
+		return nil, err
+	}
+
 	cancelReason := reason
 	if cancelReason == "" {
 		cancelReason = "cancelled by customer"
@@ -1181,6 +1205,9 @@ func (s *Service) CancelOrder(ctx context.Context, tenantID, orderID, customerID
 	if err != nil {
 		return nil, apperror.Internal("cancel order", err)
 	}
+	if err := analytics.EnqueueOrderFact(ctx, qtx, updated); err != nil {
+		return nil, apperror.Internal("enqueue order fact", err)
+	}
 
 	_, err = qtx.AddTimelineEvent(ctx, sqlc.AddTimelineEventParams{
 		OrderID:        orderID,
@@ -1249,6 +1276,11 @@ func (s *Service) ForceCancelOrder(ctx context.Context, tenantID, orderID, admin
 		return nil, err
 	}
 
+	// Give the promo uses back
+	if err := s.promoSvc.ReleaseOrderPromos(ctx, qtx, tenantID, orderID); err != nil {
+		return nil, err
+	}
+
 	updated, err := qtx.TransitionOrderStatus(ctx, sqlc.TransitionOrderStatusParams{
 		NewStatus:          sqlc.OrderStatusCancelled,
 		CancellationReason: sql.NullString{String: reason, Valid: true},
@@ -1261,6 +1293,9 @@ func (s *Service) ForceCancelOrder(ctx context.Context, tenantID, orderID, admin
 	if err != nil {
 		return nil, apperror.Internal("force cancel order", err)
 	}
+	if err := analytics.EnqueueOrderFact(ctx, qtx, updated); err != nil {
+		return nil, apperror.Internal("enqueue order fact", err)
+	}
 
 	_, err = qtx.AddTimelineEvent(ctx, sqlc.AddTimelineEventParams{
 		OrderID:        orderID,
@@ -1464,6 +1499,11 @@ func (s *Service) FailPayment(ctx context.Context, tenantID, orderID uuid.UUID)
 		return err
 	}
 
+	// Give the promo uses back
+	if err := s.promoSvc.ReleaseOrderPromos(ctx, qtx, tenantID, orderID); err != nil {
+		return err
+	}
+
 	// Soft-delete the order
 	if err := qtx.SoftDeleteOrder(ctx, sqlc.SoftDeleteOrderParams{
 		ID:       orderID,
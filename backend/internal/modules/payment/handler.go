@@ -228,8 +228,9 @@ func (h *Handler) ProcessRefund(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Amount float64 `json:"amount"`
-		Reason string  `json:"reason"`
+		Amount             float64 `json:"amount"`
+		Reason             string  `json:"reason"`
+		ChargeRestaurantID *string `json:"charge_restaurant_id"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -244,7 +245,17 @@ func (h *Handler) ProcessRefund(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	refund, err := h.svc.ProcessRefund(r.Context(), orderID, t.ID, req.Amount, req.Reason, u.ID)
+	var chargeRestaurantID *uuid.UUID
+	if req.ChargeRestaurantID != nil && *req.ChargeRestaurantID != "" {
+		id, err := uuid.Parse(*req.ChargeRestaurantID)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid charge_restaurant_id"))
+			return
+		}
+		chargeRestaurantID = &id
+	}
+
+	refund, err := h.svc.ProcessRefund(r.Context(), orderID, t.ID, req.Amount, req.Reason, u.ID, chargeRestaurantID)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,8 +19,10 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// ProcessRefund handles refund logic for an order.
-func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID, amount float64, reason string, triggeredByUserID uuid.UUID) (*sqlc.Refund, error) {
+// ProcessRefund handles refund logic for an order. When chargeRestaurantID is
+// set, the refund is deducted from that restaurant's next invoice, up to what
+// is left of the restaurant's share of the order.
+func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID, amount float64, reason string, triggeredByUserID uuid.UUID, chargeRestaurantID *uuid.UUID) (*sqlc.Refund, error) {
 	if amount <= 0 {
 		return nil, apperror.BadRequest("refund amount must be positive")
 	}
@@ -50,6 +53,20 @@ func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID
 		return nil, apperror.BadRequest("refund amount exceeds order total")
 	}
 
+	// A refund can only be charged to a restaurant that was part of the order
+	if chargeRestaurantID != nil {
+		restaurantIDs, err := s.q.ListOrderRestaurantIDs(ctx, sqlc.ListOrderRestaurantIDsParams{
+			OrderID:  orderID,
+			TenantID: tenantID,
+		})
+		if err != nil {
+			return nil, apperror.Internal("fetch order restaurants", err)
+		}
+		if !slices.Contains(restaurantIDs, *chargeRestaurantID) {
+			return nil, apperror.BadRequest("charge_restaurant_id is not part of this order")
+		}
+	}
+
 	// 2. Find the successful payment transaction
 	txn, err := s.q.GetTransactionByOrderID(ctx, sqlc.GetTransactionByOrderIDParams{
 		OrderID:  orderID,
@@ -95,7 +112,14 @@ func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID
 		processedAt = pgtype.Timestamptz{Time: now, InfinityModifier: pgtype.Finite, Valid: true}
 	}
 
-	refund, err := s.q.CreateRefund(ctx, sqlc.CreateRefundParams{
+	dbTx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin transaction", err)
+	}
+	defer dbTx.Rollback(ctx)
+	qtx := s.q.WithTx(dbTx)
+
+	refund, err := qtx.CreateRefund(ctx, sqlc.CreateRefundParams{
 		TenantID:        tenantID,
 		OrderID:         orderID,
 		TransactionID:   txn.ID,
@@ -111,7 +135,31 @@ func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID
 		return nil, apperror.Internal("create refund record", err)
 	}
 
-	// 5. Update payment transaction status to refunded
+	// 5. Charge the refund to the restaurant's next invoice
+	chargeAmount, err := restaurantRefundCharge(ctx, qtx, orderID, tenantID, amount, chargeRestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if chargeAmount > 0 {
+		_, err = qtx.CreateRefundInvoiceAdjustment(ctx, sqlc.CreateRefundInvoiceAdjustmentParams{
+			TenantID:     tenantID,
+			RestaurantID: *chargeRestaurantID,
+			OrderID:      pgtype.UUID{Bytes: orderID, Valid: true},
+			RefundID:     pgtype.UUID{Bytes: refund.ID, Valid: true},
+			Amount:       float64ToNumeric(chargeAmount),
+			Reason:       "Refund: " + reason,
+			CreatedBy:    pgtype.UUID{Bytes: triggeredByUserID, Valid: true},
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.Internal("charge refund to restaurant", err)
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit transaction", err)
+	}
+
+	// 6. Update payment transaction status to refunded
 	_, err = s.q.UpdateTransactionStatus(ctx, sqlc.UpdateTransactionStatusParams{
 		ID:       txn.ID,
 		TenantID: tenantID,
@@ -121,7 +169,7 @@ func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID
 		log.Error().Err(err).Str("txn_id", txn.ID.String()).Msg("failed to update transaction status to refunded")
 	}
 
-	// 6. Update order payment status
+	// 7. Update order payment status
 	paymentStatus := sqlc.PaymentStatusRefunded
 	if amount < orderTotal.Float64 {
 		paymentStatus = sqlc.PaymentStatusPartiallyRefunded
@@ -135,7 +183,7 @@ func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID
 		log.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to update order payment status")
 	}
 
-	// 7. Create timeline event
+	// 8. Create timeline event
 	metadata, _ := json.Marshal(map[string]interface{}{
 		"refund_id":     refund.ID,
 		"refund_amount": amount,
@@ -158,6 +206,29 @@ func (s *Service) ProcessRefund(ctx context.Context, orderID, tenantID uuid.UUID
 	return &refund, nil
 }
 
+// restaurantRefundCharge returns how much of a refund to deduct from the
+// charged restaurant: the refund, capped at the restaurant's pickup total less
+// refunds already charged to it for the order. It locks the pickup, so it runs
+// on the refund's transaction.
+func restaurantRefundCharge(ctx context.Context, q *sqlc.Queries, orderID, tenantID uuid.UUID, amount float64, restaurantID *uuid.UUID) (float64, error) {
+	if restaurantID == nil {
+		return 0, nil
+	}
+	refundable, err := q.GetPickupRefundableAmount(ctx, sqlc.GetPickupRefundableAmountParams{
+		OrderID:      orderID,
+		RestaurantID: *restaurantID,
+		TenantID:     tenantID,
+	})
+	if err != nil {
+		return 0, apperror.Internal("fetch restaurant refundable amount", err)
+	}
+	left, err := refundable.Float64Value()
+	if err != nil || !left.Valid {
+		return 0, apperror.Internal("parse restaurant refundable amount", err)
+	}
+	return max(0, min(amount, left.Float64)), nil
+}
+
 func (s *Service) processGatewayRefund(ctx context.Context, txn sqlc.PaymentTransaction, amount float64, reason string) (sqlc.RefundStatus, sql.NullString, error) {
 	gw, ok := s.gateways[txn.PaymentMethod]
 	if !ok {
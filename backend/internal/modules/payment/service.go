@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	gateway "github.com/munchies/platform/backend/internal/platform/payment"
@@ -21,12 +22,13 @@ import (
 // Service implements payment business logic.
 type Service struct {
 	q        *sqlc.Queries
+	pool     *pgxpool.Pool
 	gateways map[sqlc.PaymentMethod]gateway.Gateway
 }
 
 // NewService creates a new payment service.
-func NewService(q *sqlc.Queries, gateways map[sqlc.PaymentMethod]gateway.Gateway) *Service {
-	return &Service{q: q, gateways: gateways}
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool, gateways map[sqlc.PaymentMethod]gateway.Gateway) *Service {
+	return &Service{q: q, pool: pool, gateways: gateways}
 }
 
 // InitiatePaymentRequest holds the data needed to start a payment.
@@ -0,0 +1,342 @@
+package promo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// Cart is the priced cart a promo is evaluated against.
+type Cart struct {
+	Items          []CartItem
+	Subtotal       decimal.Decimal
+	DeliveryCharge decimal.Decimal
+	At             time.Time // evaluation time; zero means now
+}
+
+// ItemAllocation is the share of a promo discount attributed to one cart line.
+// Index refers to the line's position in Cart.Items.
+type ItemAllocation struct {
+	Index        int             `json:"index"`
+	ProductID    uuid.UUID       `json:"product_id"`
+	RestaurantID uuid.UUID       `json:"restaurant_id"`
+	Discount     decimal.Decimal `json:"discount"`
+}
+
+// Discount is the outcome of applying a promo's rules to a cart.
+type Discount struct {
+	ItemDiscount     decimal.Decimal
+	DeliveryDiscount decimal.Decimal
+	Allocations      []ItemAllocation
+}
+
+// Total returns the combined item and delivery discount.
+func (d *Discount) Total() decimal.Decimal {
+	return d.ItemDiscount.Add(d.DeliveryDiscount)
+}
+
+// Rules bundles a promo with the restrictions and rule data it is evaluated with.
+type Rules struct {
+	Promo         sqlc.Promo
+	RestaurantIDs map[uuid.UUID]bool
+	CategoryIDs   map[uuid.UUID]bool
+	ProductIDs    map[uuid.UUID]bool
+	Tiers         []sqlc.PromoTier // ascending min_subtotal
+	Windows       []sqlc.PromoTimeWindow
+	Budget        *decimal.Decimal // remaining budget; nil = no budget cap
+}
+
+// Apply evaluates the promo against the cart. When the promo does not apply,
+// it returns a nil Discount and a customer-facing reason.
+func (r *Rules) Apply(cart Cart) (*Discount, string) {
+	at := cart.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if !r.inWindow(at) {
+		return nil, "promo is not available at this time"
+	}
+
+	minOrder := numericToDecimal(r.Promo.MinOrderAmount)
+	if cart.Subtotal.LessThan(minOrder) {
+		return nil, "minimum order amount of " + minOrder.StringFixed(2) + " not met"
+	}
+
+	eligible := make([]int, 0, len(cart.Items))
+	eligibleSubtotal := decimal.Zero
+	for i, item := range cart.Items {
+		if r.eligible(item) {
+			eligible = append(eligible, i)
+			eligibleSubtotal = eligibleSubtotal.Add(item.ItemSubtotal)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, "promo does not apply to any item in your cart"
+	}
+
+	rate := numericToDecimal(r.Promo.DiscountAmount)
+	if r.Promo.RuleType == sqlc.PromoRuleTiered {
+		tier := r.tierFor(eligibleSubtotal)
+		if tier == nil {
+			if len(r.Tiers) == 0 {
+				return nil, "promo has no spend tiers configured"
+			}
+			short := numericToDecimal(r.Tiers[0].MinSubtotal).Sub(eligibleSubtotal)
+			return nil, fmt.Sprintf("add %s more of eligible items to unlock this promo", short.StringFixed(2))
+		}
+		rate = numericToDecimal(tier.DiscountAmount)
+	}
+
+	d := &Discount{ItemDiscount: decimal.Zero, DeliveryDiscount: decimal.Zero}
+
+	if r.Promo.RuleType == sqlc.PromoRuleBogo {
+		perLine := r.bogoDiscounts(cart.Items, eligible)
+		total := decimal.Zero
+		for _, v := range perLine {
+			total = total.Add(v)
+		}
+		if total.IsZero() {
+			return nil, fmt.Sprintf("add %d eligible items to get %d discounted", deref(r.Promo.BuyQty)+deref(r.Promo.GetQty), deref(r.Promo.GetQty))
+		}
+		if cp := r.cap(); cp != nil && total.GreaterThan(*cp) {
+			// Spread the capped amount over the free units' lines.
+			weights := make(map[int]decimal.Decimal, len(perLine))
+			idx := make([]int, 0, len(perLine))
+			for _, i := range eligible {
+				if v, ok := perLine[i]; ok {
+					weights[i] = v
+					idx = append(idx, i)
+				}
+			}
+			perLine = allocate(*cp, idx, weights)
+			total = *cp
+		}
+		d.ItemDiscount = total
+		d.Allocations = allocations(cart.Items, eligible, perLine)
+		return d, ""
+	}
+
+	if r.Promo.AppliesTo == sqlc.PromoApplyOnDeliveryCharge {
+		d.DeliveryDiscount = r.amount(cart.DeliveryCharge, rate)
+		if d.DeliveryDiscount.IsZero() {
+			return nil, "promo does not apply to this delivery"
+		}
+		return d, ""
+	}
+
+	d.ItemDiscount = r.amount(eligibleSubtotal, rate)
+	weights := make(map[int]decimal.Decimal, len(eligible))
+	for _, i := range eligible {
+		weights[i] = cart.Items[i].ItemSubtotal
+	}
+	d.Allocations = allocations(cart.Items, eligible, allocate(d.ItemDiscount, eligible, weights))
+	return d, ""
+}
+
+// eligible reports whether a cart line passes every configured restriction.
+// applies_to only selects the discount target; restrictions always narrow it.
+func (r *Rules) eligible(item CartItem) bool {
+	if len(r.RestaurantIDs) > 0 && !r.RestaurantIDs[item.RestaurantID] {
+		return false
+	}
+	if len(r.CategoryIDs) > 0 && !r.CategoryIDs[item.CategoryID] {
+		return false
+	}
+	if len(r.ProductIDs) > 0 && !r.ProductIDs[item.ProductID] {
+		return false
+	}
+	return true
+}
+
+// inWindow reports whether at falls inside one of the promo's time windows,
+// evaluated in Bangladesh local time. No windows means always available.
+func (r *Rules) inWindow(at time.Time) bool {
+	if len(r.Windows) == 0 {
+		return true
+	}
+	local := timeutil.ToBD(at)
+	minute := int32(local.Hour()*60 + local.Minute())
+	today := int32(local.Weekday())
+	yesterday := (today + 6) % 7
+
+	for _, w := range r.Windows {
+		if w.StartMinute <= w.EndMinute {
+			if (w.DayOfWeek == nil || *w.DayOfWeek == today) && minute >= w.StartMinute && minute < w.EndMinute {
+				return true
+			}
+			continue
+		}
+		// Overnight window: the evening part belongs to day_of_week,
+		// the early-morning part to the following day.
+		if (w.DayOfWeek == nil || *w.DayOfWeek == today) && minute >= w.StartMinute {
+			return true
+		}
+		if (w.DayOfWeek == nil || *w.DayOfWeek == yesterday) && minute < w.EndMinute {
+			return true
+		}
+	}
+	return false
+}
+
+// tierFor returns the highest tier reached by the given spend.
+func (r *Rules) tierFor(spend decimal.Decimal) *sqlc.PromoTier {
+	var best *sqlc.PromoTier
+	for i := range r.Tiers {
+		if spend.GreaterThanOrEqual(numericToDecimal(r.Tiers[i].MinSubtotal)) {
+			best = &r.Tiers[i]
+		}
+	}
+	return best
+}
+
+// amount computes a fixed or percent discount on base, honouring the cap and
+// never exceeding base.
+func (r *Rules) amount(base, rate decimal.Decimal) decimal.Decimal {
+	var v decimal.Decimal
+	if r.Promo.PromoType == sqlc.PromoTypePercent {
+		v = base.Mul(rate).Div(hundred)
+	} else {
+		v = rate
+	}
+	if cp := r.cap(); cp != nil && v.GreaterThan(*cp) {
+		v = *cp
+	}
+	if v.GreaterThan(base) {
+		v = base
+	}
+	return v.Round(2)
+}
+
+// cap returns the most the promo may discount on this cart: the lower of
+// max_discount_cap and the remaining budget.
+func (r *Rules) cap() *decimal.Decimal {
+	var cp *decimal.Decimal
+	if r.Promo.MaxDiscountCap.Valid {
+		v := numericToDecimal(r.Promo.MaxDiscountCap)
+		cp = &v
+	}
+	if r.Budget != nil && (cp == nil || r.Budget.LessThan(*cp)) {
+		v := *r.Budget
+		cp = &v
+	}
+	return cp
+}
+
+// bogoDiscounts applies buy-X-get-Y over the eligible units. Units are ranked
+// by price, highest first, and grouped into buy+get sets; the cheapest get_qty
+// units of each complete set are discounted by discount_amount (percent of the
+// unit price, or a fixed amount per unit).
+func (r *Rules) bogoDiscounts(items []CartItem, eligible []int) map[int]decimal.Decimal {
+	buy, get := deref(r.Promo.BuyQty), deref(r.Promo.GetQty)
+	if buy <= 0 || get <= 0 {
+		return nil
+	}
+
+	type unit struct {
+		line  int
+		price decimal.Decimal
+	}
+	var units []unit
+	for _, i := range eligible {
+		item := items[i]
+		if item.Quantity <= 0 {
+			continue
+		}
+		price := item.ItemSubtotal.Div(decimal.NewFromInt32(item.Quantity))
+		for n := int32(0); n < item.Quantity; n++ {
+			units = append(units, unit{line: i, price: price})
+		}
+	}
+	sort.SliceStable(units, func(a, b int) bool { return units[a].price.GreaterThan(units[b].price) })
+
+	rate := numericToDecimal(r.Promo.DiscountAmount)
+	set := int(buy + get)
+	perLine := make(map[int]decimal.Decimal)
+	for start := 0; start+set <= len(units); start += set {
+		for _, u := range units[start+int(buy) : start+set] {
+			var off decimal.Decimal
+			if r.Promo.PromoType == sqlc.PromoTypePercent {
+				off = u.price.Mul(rate).Div(hundred)
+			} else {
+				off = decimal.Min(rate, u.price)
+			}
+			perLine[u.line] = perLine[u.line].Add(off)
+		}
+	}
+	for i, v := range perLine {
+		perLine[i] = v.Round(2)
+	}
+	return perLine
+}
+
+// allocate splits total across lines in proportion to their weights, rounded
+// to paisa. The rounding remainder lands on the last weighted line.
+func allocate(total decimal.Decimal, lines []int, weights map[int]decimal.Decimal) map[int]decimal.Decimal {
+	out := make(map[int]decimal.Decimal, len(lines))
+	sum := decimal.Zero
+	last := -1
+	for _, i := range lines {
+		if weights[i].IsPositive() {
+			sum = sum.Add(weights[i])
+			last = i
+		}
+	}
+	if last < 0 || total.IsZero() {
+		return out
+	}
+
+	remaining := total
+	for _, i := range lines {
+		w := weights[i]
+		if !w.IsPositive() {
+			continue
+		}
+		if i == last {
+			out[i] = remaining
+			break
+		}
+		share := total.Mul(w).Div(sum).Round(2)
+		out[i] = share
+		remaining = remaining.Sub(share)
+	}
+	return out
+}
+
+func allocations(items []CartItem, eligible []int, perLine map[int]decimal.Decimal) []ItemAllocation {
+	out := make([]ItemAllocation, 0, len(perLine))
+	for _, i := range eligible {
+		v, ok := perLine[i]
+		if !ok || v.IsZero() {
+			continue
+		}
+		out = append(out, ItemAllocation{
+			Index:        i,
+			ProductID:    items[i].ProductID,
+			RestaurantID: items[i].RestaurantID,
+			Discount:     v,
+		})
+	}
+	return out
+}
+
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
+
+func deref(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,170 @@
+package promo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func num(s string) pgtype.Numeric { return toPgNumeric(decimal.RequireFromString(s)) }
+
+func dec(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func int32p(v int32) *int32 { return &v }
+
+var (
+	burgerID = uuid.New()
+	drinkID  = uuid.New()
+)
+
+func testCart() Cart {
+	return Cart{
+		Items: []CartItem{
+			{ProductID: burgerID, Quantity: 3, ItemSubtotal: dec("300")},
+			{ProductID: drinkID, Quantity: 1, ItemSubtotal: dec("150")},
+		},
+		Subtotal:       dec("450"),
+		DeliveryCharge: dec("60"),
+	}
+}
+
+func TestApply_FixedAllocatedProportionally(t *testing.T) {
+	r := Rules{Promo: sqlc.Promo{RuleType: sqlc.PromoRuleStandard, PromoType: sqlc.PromoTypeFixed, DiscountAmount: num("100")}}
+
+	d, reason := r.Apply(testCart())
+	if d == nil {
+		t.Fatalf("expected discount, got %q", reason)
+	}
+	if !d.ItemDiscount.Equal(dec("100")) {
+		t.Errorf("ItemDiscount = %s, want 100", d.ItemDiscount)
+	}
+	if len(d.Allocations) != 2 || !d.Allocations[0].Discount.Equal(dec("66.67")) || !d.Allocations[1].Discount.Equal(dec("33.33")) {
+		t.Errorf("Allocations = %+v, want 66.67 / 33.33", d.Allocations)
+	}
+}
+
+func TestApply_PercentLimitedToEligibleItems(t *testing.T) {
+	r := Rules{
+		Promo:      sqlc.Promo{RuleType: sqlc.PromoRuleStandard, PromoType: sqlc.PromoTypePercent, DiscountAmount: num("10")},
+		ProductIDs: map[uuid.UUID]bool{drinkID: true},
+	}
+
+	d, _ := r.Apply(testCart())
+	if d == nil || !d.ItemDiscount.Equal(dec("15")) {
+		t.Fatalf("discount = %+v, want 15 on the drink only", d)
+	}
+	if len(d.Allocations) != 1 || d.Allocations[0].Index != 1 {
+		t.Errorf("Allocations = %+v, want only line 1", d.Allocations)
+	}
+}
+
+func TestApply_FreeDelivery(t *testing.T) {
+	r := Rules{Promo: sqlc.Promo{
+		RuleType:       sqlc.PromoRuleStandard,
+		PromoType:      sqlc.PromoTypePercent,
+		AppliesTo:      sqlc.PromoApplyOnDeliveryCharge,
+		DiscountAmount: num("100"),
+	}}
+
+	d, _ := r.Apply(testCart())
+	if d == nil || !d.DeliveryDiscount.Equal(dec("60")) || !d.ItemDiscount.IsZero() {
+		t.Fatalf("discount = %+v, want 60 off delivery only", d)
+	}
+	if len(d.Allocations) != 0 {
+		t.Errorf("delivery discount should not be allocated to items: %+v", d.Allocations)
+	}
+}
+
+func TestApply_BuyOneGetOne(t *testing.T) {
+	r := Rules{Promo: sqlc.Promo{
+		RuleType:       sqlc.PromoRuleBogo,
+		PromoType:      sqlc.PromoTypePercent,
+		DiscountAmount: num("100"),
+		BuyQty:         int32p(1),
+		GetQty:         int32p(1),
+	}}
+
+	// Units ranked 150, 100, 100, 100: the cheaper unit of each pair is free.
+	d, _ := r.Apply(testCart())
+	if d == nil || !d.ItemDiscount.Equal(dec("200")) {
+		t.Fatalf("discount = %+v, want 200", d)
+	}
+	if len(d.Allocations) != 1 || d.Allocations[0].Index != 0 {
+		t.Errorf("Allocations = %+v, want both free units on line 0", d.Allocations)
+	}
+}
+
+func TestApply_TieredPicksHighestReachedTier(t *testing.T) {
+	r := Rules{
+		Promo: sqlc.Promo{RuleType: sqlc.PromoRuleTiered, PromoType: sqlc.PromoTypeFixed},
+		Tiers: []sqlc.PromoTier{
+			{MinSubtotal: num("200"), DiscountAmount: num("20")},
+			{MinSubtotal: num("400"), DiscountAmount: num("50")},
+			{MinSubtotal: num("800"), DiscountAmount: num("120")},
+		},
+	}
+
+	d, _ := r.Apply(testCart())
+	if d == nil || !d.ItemDiscount.Equal(dec("50")) {
+		t.Fatalf("discount = %+v, want 50", d)
+	}
+
+	cart := testCart()
+	cart.Items = cart.Items[1:]
+	cart.Subtotal = dec("150")
+	if d, reason := r.Apply(cart); d != nil || reason == "" {
+		t.Errorf("expected no discount below the first tier, got %+v", d)
+	}
+}
+
+func TestApply_MinOrderAmount(t *testing.T) {
+	r := Rules{Promo: sqlc.Promo{RuleType: sqlc.PromoRuleStandard, PromoType: sqlc.PromoTypeFixed, DiscountAmount: num("50"), MinOrderAmount: num("500")}}
+
+	if d, reason := r.Apply(testCart()); d != nil || reason == "" {
+		t.Errorf("expected min order rejection, got %+v", d)
+	}
+}
+
+func TestApply_TimeWindow(t *testing.T) {
+	friday := int32(5)
+	r := Rules{
+		Promo:   sqlc.Promo{RuleType: sqlc.PromoRuleStandard, PromoType: sqlc.PromoTypeFixed, DiscountAmount: num("50")},
+		Windows: []sqlc.PromoTimeWindow{{DayOfWeek: &friday, StartMinute: 22 * 60, EndMinute: 2 * 60}},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"friday 23:00 BD", time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC), true},
+		{"saturday 01:00 BD", time.Date(2026, 10, 16, 19, 0, 0, 0, time.UTC), true},
+		{"saturday 03:00 BD", time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC), false},
+		{"friday 21:00 BD", time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		cart := testCart()
+		cart.At = tt.at
+		d, _ := r.Apply(cart)
+		if (d != nil) != tt.want {
+			t.Errorf("%s: applied = %v, want %v", tt.name, d != nil, tt.want)
+		}
+	}
+}
+
+func TestApply_RemainingBudgetCapsDiscount(t *testing.T) {
+	budget := dec("40")
+	r := Rules{
+		Promo:  sqlc.Promo{RuleType: sqlc.PromoRuleStandard, PromoType: sqlc.PromoTypeFixed, DiscountAmount: num("100")},
+		Budget: &budget,
+	}
+
+	d, _ := r.Apply(testCart())
+	if d == nil || !d.ItemDiscount.Equal(dec("40")) {
+		t.Fatalf("discount = %+v, want 40 (remaining budget)", d)
+	}
+}
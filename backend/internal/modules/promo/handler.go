@@ -14,6 +14,7 @@ import (
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
 	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
 	"github.com/shopspring/decimal"
 )
 
@@ -63,38 +64,64 @@ func (h *Handler) CreatePromo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Code           string   `json:"code"`
-		Title          string   `json:"title"`
-		Description    string   `json:"description"`
-		PromoType      string   `json:"promo_type"`
-		DiscountAmount string   `json:"discount_amount"`
-		MaxDiscountCap *string  `json:"max_discount_cap"`
-		CashbackAmount string   `json:"cashback_amount"`
-		FundedBy       string   `json:"funded_by"`
-		AppliesTo      string   `json:"applies_to"`
-		MinOrderAmount string   `json:"min_order_amount"`
-		MaxTotalUses   *int32   `json:"max_total_uses"`
-		MaxUsesPerUser int32    `json:"max_uses_per_user"`
-		IncludeStores  bool     `json:"include_stores"`
-		StartsAt       string   `json:"starts_at"`
-		EndsAt         *string  `json:"ends_at"`
-		RestaurantIDs  []string `json:"restaurant_ids"`
-		CategoryIDs    []string `json:"category_ids"`
-		UserIDs        []string `json:"user_ids"`
+		Code           string  `json:"code"`
+		Title          string  `json:"title"`
+		Description    string  `json:"description"`
+		PromoType      string  `json:"promo_type"`
+		DiscountAmount string  `json:"discount_amount"`
+		MaxDiscountCap *string `json:"max_discount_cap"`
+		CashbackAmount string  `json:"cashback_amount"`
+		FundedBy       string  `json:"funded_by"`
+		AppliesTo      string  `json:"applies_to"`
+		MinOrderAmount string  `json:"min_order_amount"`
+		MaxTotalUses   *int32  `json:"max_total_uses"`
+		MaxUsesPerUser int32   `json:"max_uses_per_user"`
+		IncludeStores  bool    `json:"include_stores"`
+		StartsAt       string  `json:"starts_at"`
+		EndsAt         *string `json:"ends_at"`
+		targetingBody
+		RuleType        string  `json:"rule_type"`
+		BuyQty          *int32  `json:"buy_qty"`
+		GetQty          *int32  `json:"get_qty"`
+		FirstOrderOnly  bool    `json:"first_order_only"`
+		NewCustomerDays *int32  `json:"new_customer_days"`
+		AutoApply       bool    `json:"auto_apply"`
+		Stacking        string  `json:"stacking"`
+		BudgetCap       *string `json:"budget_cap"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
 		return
 	}
 
-	if req.Code == "" || req.Title == "" {
-		respond.Error(w, apperror.BadRequest("code and title are required"))
+	if req.Title == "" {
+		respond.Error(w, apperror.BadRequest("title is required"))
+		return
+	}
+	if req.Code == "" && !req.AutoApply {
+		respond.Error(w, apperror.BadRequest("code is required unless the promo is auto-applied"))
 		return
 	}
 
-	discountAmt, err := decimal.NewFromString(req.DiscountAmount)
-	if err != nil || discountAmt.LessThanOrEqual(decimal.Zero) {
-		respond.Error(w, apperror.BadRequest("invalid discount_amount"))
+	ruleType := sqlc.PromoRule(req.RuleType)
+	if ruleType == "" {
+		ruleType = sqlc.PromoRuleStandard
+	}
+
+	// Tiered promos take their amounts from the tiers.
+	discountAmt := decimal.Zero
+	if req.DiscountAmount != "" || ruleType != sqlc.PromoRuleTiered {
+		var err error
+		discountAmt, err = decimal.NewFromString(req.DiscountAmount)
+		if err != nil || discountAmt.LessThanOrEqual(decimal.Zero) {
+			respond.Error(w, apperror.BadRequest("invalid discount_amount"))
+			return
+		}
+	}
+
+	targeting, err := req.targetingBody.parse()
+	if err != nil {
+		respond.Error(w, toAppError(err))
 		return
 	}
 
@@ -108,6 +135,16 @@ func (h *Handler) CreatePromo(w http.ResponseWriter, r *http.Request) {
 		maxCap = &v
 	}
 
+	var budgetCap *decimal.Decimal
+	if req.BudgetCap != nil {
+		v, err := decimal.NewFromString(*req.BudgetCap)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid budget_cap"))
+			return
+		}
+		budgetCap = &v
+	}
+
 	cashback := decimal.Zero
 	if req.CashbackAmount != "" {
 		cashback, err = decimal.NewFromString(req.CashbackAmount)
@@ -166,23 +203,32 @@ func (h *Handler) CreatePromo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	p, err := h.svc.CreatePromo(r.Context(), CreatePromoRequest{
-		TenantID:       t.ID,
-		Code:           req.Code,
-		Title:          req.Title,
-		Description:    req.Description,
-		PromoType:      promoType,
-		DiscountAmount: discountAmt,
-		MaxDiscountCap: maxCap,
-		CashbackAmount: cashback,
-		FundedBy:       fundedBy,
-		AppliesTo:      appliesTo,
-		MinOrderAmount: minOrder,
-		MaxTotalUses:   req.MaxTotalUses,
-		MaxUsesPerUser: req.MaxUsesPerUser,
-		IncludeStores:  req.IncludeStores,
-		StartsAt:       startsAt,
-		EndsAt:         endsAt,
-		CreatedBy:      u.ID,
+		TenantID:        t.ID,
+		Code:            req.Code,
+		Title:           req.Title,
+		Description:     req.Description,
+		PromoType:       promoType,
+		DiscountAmount:  discountAmt,
+		MaxDiscountCap:  maxCap,
+		CashbackAmount:  cashback,
+		FundedBy:        fundedBy,
+		AppliesTo:       appliesTo,
+		MinOrderAmount:  minOrder,
+		MaxTotalUses:    req.MaxTotalUses,
+		MaxUsesPerUser:  req.MaxUsesPerUser,
+		IncludeStores:   req.IncludeStores,
+		StartsAt:        startsAt,
+		EndsAt:          endsAt,
+		CreatedBy:       u.ID,
+		RuleType:        ruleType,
+		BuyQty:          req.BuyQty,
+		GetQty:          req.GetQty,
+		FirstOrderOnly:  req.FirstOrderOnly,
+		NewCustomerDays: req.NewCustomerDays,
+		AutoApply:       req.AutoApply,
+		Stacking:        sqlc.PromoStacking(req.Stacking),
+		BudgetCap:       budgetCap,
+		Targeting:       targeting,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -249,17 +295,41 @@ func (h *Handler) UpdatePromo(w http.ResponseWriter, r *http.Request) {
 		MaxUsesPerUser *int32  `json:"max_uses_per_user"`
 		StartsAt       *string `json:"starts_at"`
 		EndsAt         *string `json:"ends_at"`
+		targetingBody
+		BuyQty          *int32  `json:"buy_qty"`
+		GetQty          *int32  `json:"get_qty"`
+		FirstOrderOnly  *bool   `json:"first_order_only"`
+		NewCustomerDays *int32  `json:"new_customer_days"`
+		AutoApply       *bool   `json:"auto_apply"`
+		Stacking        *string `json:"stacking"`
+		BudgetCap       *string `json:"budget_cap"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
 		return
 	}
 
+	targeting, err := req.targetingBody.parse()
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
 	updateReq := UpdatePromoRequest{
-		Title:          req.Title,
-		Description:    req.Description,
-		MaxTotalUses:   req.MaxTotalUses,
-		MaxUsesPerUser: req.MaxUsesPerUser,
+		Title:           req.Title,
+		Description:     req.Description,
+		MaxTotalUses:    req.MaxTotalUses,
+		MaxUsesPerUser:  req.MaxUsesPerUser,
+		BuyQty:          req.BuyQty,
+		GetQty:          req.GetQty,
+		FirstOrderOnly:  req.FirstOrderOnly,
+		NewCustomerDays: req.NewCustomerDays,
+		AutoApply:       req.AutoApply,
+		Targeting:       targeting,
+	}
+	if req.Stacking != nil {
+		stacking := sqlc.PromoStacking(*req.Stacking)
+		updateReq.Stacking = &stacking
 	}
 
 	if req.DiscountAmount != nil {
@@ -278,6 +348,14 @@ func (h *Handler) UpdatePromo(w http.ResponseWriter, r *http.Request) {
 		}
 		updateReq.MaxDiscountCap = &v
 	}
+	if req.BudgetCap != nil {
+		v, err := decimal.NewFromString(*req.BudgetCap)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid budget_cap"))
+			return
+		}
+		updateReq.BudgetCap = &v
+	}
 	if req.CashbackAmount != nil {
 		v, err := decimal.NewFromString(*req.CashbackAmount)
 		if err != nil {
@@ -350,11 +428,148 @@ func (h *Handler) DeactivatePromo(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/", h.ListPromos)
 	r.Post("/", h.CreatePromo)
+	r.Get("/redemptions", h.GetRedemptionReport)
 	r.Get("/{id}", h.GetPromo)
 	r.Put("/{id}", h.UpdatePromo)
 	r.Patch("/{id}/deactivate", h.DeactivatePromo)
 }
 
+// GetRedemptionReport handles GET /partner/promos/redemptions
+// Query params: start_date, end_date (YYYY-MM-DD, Bangladesh time, inclusive;
+// defaults to the last 30 days).
+func (h *Handler) GetRedemptionReport(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	today := timeutil.StartOfDayBD(time.Now())
+	from := today.AddDate(0, 0, -29)
+	to := today
+	if v := r.URL.Query().Get("start_date"); v != "" {
+		d, err := timeutil.ParseBD("2006-01-02", v)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid start_date, use YYYY-MM-DD"))
+			return
+		}
+		from = d
+	}
+	if v := r.URL.Query().Get("end_date"); v != "" {
+		d, err := timeutil.ParseBD("2006-01-02", v)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid end_date, use YYYY-MM-DD"))
+			return
+		}
+		to = d
+	}
+
+	report, err := h.svc.GetRedemptionReport(r.Context(), t.ID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, report)
+}
+
+// targetingBody is the JSON form of Targeting shared by create and update.
+type targetingBody struct {
+	RestaurantIDs []string `json:"restaurant_ids"`
+	CategoryIDs   []string `json:"category_ids"`
+	ProductIDs    []string `json:"product_ids"`
+	UserIDs       []string `json:"user_ids"`
+	Tiers         []struct {
+		MinSubtotal    string `json:"min_subtotal"`
+		DiscountAmount string `json:"discount_amount"`
+	} `json:"tiers"`
+	TimeWindows []struct {
+		DayOfWeek *int32 `json:"day_of_week"`
+		Start     string `json:"start"` // "HH:MM", Bangladesh time
+		End       string `json:"end"`   // "HH:MM"; "24:00" for midnight
+	} `json:"time_windows"`
+}
+
+func (b targetingBody) parse() (Targeting, error) {
+	var t Targeting
+	var err error
+	if t.RestaurantIDs, err = parseUUIDs(b.RestaurantIDs, "restaurant_ids"); err != nil {
+		return t, err
+	}
+	if t.CategoryIDs, err = parseUUIDs(b.CategoryIDs, "category_ids"); err != nil {
+		return t, err
+	}
+	if t.ProductIDs, err = parseUUIDs(b.ProductIDs, "product_ids"); err != nil {
+		return t, err
+	}
+	if t.UserIDs, err = parseUUIDs(b.UserIDs, "user_ids"); err != nil {
+		return t, err
+	}
+
+	if b.Tiers != nil {
+		t.Tiers = make([]TierRequest, 0, len(b.Tiers))
+		for _, tier := range b.Tiers {
+			minSubtotal, err := decimal.NewFromString(tier.MinSubtotal)
+			if err != nil {
+				return t, apperror.BadRequest("invalid tier min_subtotal")
+			}
+			amount, err := decimal.NewFromString(tier.DiscountAmount)
+			if err != nil {
+				return t, apperror.BadRequest("invalid tier discount_amount")
+			}
+			t.Tiers = append(t.Tiers, TierRequest{MinSubtotal: minSubtotal, DiscountAmount: amount})
+		}
+	}
+
+	if b.TimeWindows != nil {
+		t.TimeWindows = make([]TimeWindowRequest, 0, len(b.TimeWindows))
+		for _, w := range b.TimeWindows {
+			start, err := parseClock(w.Start)
+			if err != nil {
+				return t, apperror.BadRequest("invalid time window start, use HH:MM")
+			}
+			end, err := parseClock(w.End)
+			if err != nil {
+				return t, apperror.BadRequest("invalid time window end, use HH:MM")
+			}
+			t.TimeWindows = append(t.TimeWindows, TimeWindowRequest{DayOfWeek: w.DayOfWeek, StartMinute: start, EndMinute: end})
+		}
+	}
+	return t, nil
+}
+
+func parseUUIDs(raw []string, field string) ([]uuid.UUID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid id in " + field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseClock converts "HH:MM" to minutes from midnight. "24:00" is accepted
+// as the end of the day.
+func parseClock(s string) (int32, error) {
+	if s == "24:00" {
+		return 1440, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return int32(t.Hour()*60 + t.Minute()), nil
+}
+
 func parsePagination(r *http.Request) (page, perPage int) {
 	q := r.URL.Query()
 	page, _ = strconv.Atoi(q.Get("page"))
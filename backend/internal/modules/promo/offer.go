@@ -0,0 +1,241 @@
+package promo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/shopspring/decimal"
+)
+
+// maxOfferCandidates bounds the subsets the best-offer solver enumerates.
+const maxOfferCandidates = 12
+
+// Offer is the combination of promotions applied to a cart. It is stored as
+// the order's promo_snapshot.
+type Offer struct {
+	Promos           []PromoValidationResult `json:"promos"`
+	ItemDiscount     decimal.Decimal         `json:"item_discount"`
+	DeliveryDiscount decimal.Decimal         `json:"delivery_discount"`
+	DiscountAmount   decimal.Decimal         `json:"discount_amount"`
+
+	// CodeResult is the outcome for the code the customer entered, valid or
+	// not. Nil when no code was entered.
+	CodeResult *PromoValidationResult `json:"-"`
+}
+
+// LineDiscounts returns the combined promo discount per cart line index.
+func (o *Offer) LineDiscounts() map[int]decimal.Decimal {
+	out := make(map[int]decimal.Decimal)
+	for _, p := range o.Promos {
+		for _, a := range p.Allocations {
+			out[a.Index] = out[a.Index].Add(a.Discount)
+		}
+	}
+	return out
+}
+
+// Primary returns the promo recorded on the order row: the entered code if
+// it was applied, otherwise the largest discount. Nil when nothing applied.
+func (o *Offer) Primary() *PromoValidationResult {
+	if len(o.Promos) == 0 {
+		return nil
+	}
+	if o.CodeResult != nil {
+		for i := range o.Promos {
+			if o.Promos[i].PromoID == o.CodeResult.PromoID {
+				return &o.Promos[i]
+			}
+		}
+	}
+	best := &o.Promos[0]
+	for i := range o.Promos[1:] {
+		if o.Promos[i+1].DiscountAmount.GreaterThan(best.DiscountAmount) {
+			best = &o.Promos[i+1]
+		}
+	}
+	return best
+}
+
+// BestOffer evaluates the entered code (if any) together with the tenant's
+// active auto-apply promos and picks the combination that saves the customer
+// the most under the stacking rules. A valid entered code is always part of
+// the chosen combination.
+func (s *Service) BestOffer(ctx context.Context, tenantID, userID uuid.UUID, code string, cart Cart) (*Offer, error) {
+	var candidates []PromoValidationResult
+	var required *uuid.UUID
+	offer := &Offer{}
+
+	if code != "" {
+		result, err := s.Validate(ctx, tenantID, userID, code, cart)
+		if err != nil {
+			return nil, err
+		}
+		offer.CodeResult = result
+		if result.Valid {
+			candidates = append(candidates, *result)
+			required = &result.PromoID
+		}
+	}
+
+	autos, err := s.q.ListActiveAutoApplyPromos(ctx, tenantID)
+	if err != nil {
+		return nil, apperror.Internal("list auto-apply promos", err)
+	}
+	for _, p := range autos {
+		if required != nil && p.ID == *required {
+			continue
+		}
+		result, err := s.evaluate(ctx, tenantID, userID, p, cart)
+		if err != nil {
+			return nil, err
+		}
+		if result.Valid && result.DiscountAmount.IsPositive() {
+			candidates = append(candidates, *result)
+		}
+	}
+
+	chosen := solve(candidates, required, cart)
+	offer.Promos = chosen
+	offer.ItemDiscount = decimal.Zero
+	offer.DeliveryDiscount = decimal.Zero
+	for _, p := range chosen {
+		offer.ItemDiscount = offer.ItemDiscount.Add(p.ItemDiscount)
+		offer.DeliveryDiscount = offer.DeliveryDiscount.Add(p.DeliveryDiscount)
+	}
+	offer.DiscountAmount = offer.ItemDiscount.Add(offer.DeliveryDiscount)
+	return offer, nil
+}
+
+// solve returns the highest-value compatible combination of candidates, with
+// discounts trimmed so that combined promos never exceed a line's subtotal or
+// the delivery charge. Ties go to the combination with fewer promos.
+func solve(candidates []PromoValidationResult, required *uuid.UUID, cart Cart) []PromoValidationResult {
+	// Keep the required promo plus the most valuable others.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri := required != nil && candidates[i].PromoID == *required
+		rj := required != nil && candidates[j].PromoID == *required
+		if ri != rj {
+			return ri
+		}
+		return candidates[i].DiscountAmount.GreaterThan(candidates[j].DiscountAmount)
+	})
+	if len(candidates) > maxOfferCandidates {
+		candidates = candidates[:maxOfferCandidates]
+	}
+
+	var best []PromoValidationResult
+	bestTotal := decimal.Zero
+	found := false
+
+	for mask := 1; mask < 1<<len(candidates); mask++ {
+		if required != nil && mask&1 == 0 {
+			continue
+		}
+		set := make([]PromoValidationResult, 0, len(candidates))
+		for i := range candidates {
+			if mask&(1<<i) != 0 {
+				set = append(set, candidates[i])
+			}
+		}
+		if !compatible(set) {
+			continue
+		}
+		combined, total := combine(set, cart)
+		if !found || total.GreaterThan(bestTotal) || (total.Equal(bestTotal) && len(combined) < len(best)) {
+			best, bestTotal, found = combined, total, true
+		}
+	}
+	if best == nil {
+		best = []PromoValidationResult{}
+	}
+	return best
+}
+
+// compatible reports whether the promos may be applied together: exclusive
+// promos only apply alone, and stackable promos combine only across different
+// funders with at most one delivery discount.
+func compatible(set []PromoValidationResult) bool {
+	if len(set) <= 1 {
+		return true
+	}
+	funders := make(map[sqlc.PromoFunder]bool, len(set))
+	delivery := false
+	for _, p := range set {
+		if p.Stacking != sqlc.PromoStackingStackable {
+			return false
+		}
+		if funders[p.FundedBy] {
+			return false
+		}
+		funders[p.FundedBy] = true
+		if p.DeliveryDiscount.IsPositive() {
+			if delivery {
+				return false
+			}
+			delivery = true
+		}
+	}
+	return true
+}
+
+// combine applies the promos in funding order (restaurant and vendor offers
+// first, platform top-ups last) and trims later promos where the remaining
+// line or delivery amount runs out.
+func combine(set []PromoValidationResult, cart Cart) ([]PromoValidationResult, decimal.Decimal) {
+	ordered := make([]PromoValidationResult, len(set))
+	copy(ordered, set)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return funderRank(ordered[i].FundedBy) < funderRank(ordered[j].FundedBy)
+	})
+
+	remaining := make([]decimal.Decimal, len(cart.Items))
+	for i, item := range cart.Items {
+		remaining[i] = item.ItemSubtotal
+	}
+	deliveryLeft := cart.DeliveryCharge
+	total := decimal.Zero
+
+	out := make([]PromoValidationResult, 0, len(ordered))
+	for _, p := range ordered {
+		p.ItemDiscount = decimal.Zero
+		allocs := make([]ItemAllocation, 0, len(p.Allocations))
+		for _, a := range p.Allocations {
+			if a.Index < 0 || a.Index >= len(remaining) {
+				continue
+			}
+			a.Discount = decimal.Min(a.Discount, remaining[a.Index])
+			if !a.Discount.IsPositive() {
+				continue
+			}
+			remaining[a.Index] = remaining[a.Index].Sub(a.Discount)
+			p.ItemDiscount = p.ItemDiscount.Add(a.Discount)
+			allocs = append(allocs, a)
+		}
+		p.Allocations = allocs
+
+		p.DeliveryDiscount = decimal.Min(p.DeliveryDiscount, deliveryLeft)
+		deliveryLeft = deliveryLeft.Sub(p.DeliveryDiscount)
+
+		p.DiscountAmount = p.ItemDiscount.Add(p.DeliveryDiscount)
+		if !p.DiscountAmount.IsPositive() {
+			continue
+		}
+		total = total.Add(p.DiscountAmount)
+		out = append(out, p)
+	}
+	return out, total
+}
+
+func funderRank(f sqlc.PromoFunder) int {
+	switch f {
+	case sqlc.PromoFunderRestaurant:
+		return 0
+	case sqlc.PromoFunderVendor:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,95 @@
+package promo
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func candidate(funder sqlc.PromoFunder, stacking sqlc.PromoStacking, line0, line1, delivery string) PromoValidationResult {
+	r := PromoValidationResult{
+		Valid:            true,
+		PromoID:          uuid.New(),
+		FundedBy:         funder,
+		Stacking:         stacking,
+		ItemDiscount:     dec(line0).Add(dec(line1)),
+		DeliveryDiscount: dec(delivery),
+	}
+	r.DiscountAmount = r.ItemDiscount.Add(r.DeliveryDiscount)
+	if d := dec(line0); d.IsPositive() {
+		r.Allocations = append(r.Allocations, ItemAllocation{Index: 0, Discount: d})
+	}
+	if d := dec(line1); d.IsPositive() {
+		r.Allocations = append(r.Allocations, ItemAllocation{Index: 1, Discount: d})
+	}
+	return r
+}
+
+func sum(promos []PromoValidationResult) decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range promos {
+		total = total.Add(p.DiscountAmount)
+	}
+	return total
+}
+
+func TestSolve_StacksAcrossFunders(t *testing.T) {
+	vendor := candidate(sqlc.PromoFunderVendor, sqlc.PromoStackingStackable, "60", "0", "0")
+	platform := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingStackable, "0", "0", "60")
+	exclusive := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingExclusive, "100", "0", "0")
+
+	got := solve([]PromoValidationResult{exclusive, vendor, platform}, nil, testCart())
+	if len(got) != 2 || !sum(got).Equal(dec("120")) {
+		t.Fatalf("got %d promos worth %s, want vendor+platform worth 120", len(got), sum(got))
+	}
+}
+
+func TestSolve_ExclusiveWinsWhenLarger(t *testing.T) {
+	vendor := candidate(sqlc.PromoFunderVendor, sqlc.PromoStackingStackable, "30", "0", "0")
+	platform := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingStackable, "0", "20", "0")
+	exclusive := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingExclusive, "100", "0", "0")
+
+	got := solve([]PromoValidationResult{vendor, platform, exclusive}, nil, testCart())
+	if len(got) != 1 || got[0].PromoID != exclusive.PromoID {
+		t.Fatalf("expected the exclusive promo alone, got %+v", got)
+	}
+}
+
+func TestSolve_SameFunderDoesNotStack(t *testing.T) {
+	a := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingStackable, "40", "0", "0")
+	b := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingStackable, "0", "50", "0")
+
+	got := solve([]PromoValidationResult{a, b}, nil, testCart())
+	if len(got) != 1 || got[0].PromoID != b.PromoID {
+		t.Fatalf("expected only the larger platform promo, got %+v", got)
+	}
+}
+
+func TestSolve_RequiredCodeAlwaysIncluded(t *testing.T) {
+	code := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingExclusive, "10", "0", "0")
+	auto := candidate(sqlc.PromoFunderVendor, sqlc.PromoStackingExclusive, "90", "0", "0")
+
+	got := solve([]PromoValidationResult{auto, code}, &code.PromoID, testCart())
+	if len(got) != 1 || got[0].PromoID != code.PromoID {
+		t.Fatalf("expected the entered code, got %+v", got)
+	}
+}
+
+func TestCombine_TrimsToLineSubtotal(t *testing.T) {
+	// Line 1 (the drink) is 150; both promos want 100 off it.
+	vendor := candidate(sqlc.PromoFunderVendor, sqlc.PromoStackingStackable, "0", "100", "0")
+	platform := candidate(sqlc.PromoFunderPlatform, sqlc.PromoStackingStackable, "0", "100", "0")
+
+	got, total := combine([]PromoValidationResult{platform, vendor}, testCart())
+	if !total.Equal(dec("150")) {
+		t.Fatalf("total = %s, want 150", total)
+	}
+	if got[0].FundedBy != sqlc.PromoFunderVendor || !got[0].DiscountAmount.Equal(dec("100")) {
+		t.Errorf("vendor promo should apply first in full, got %+v", got[0])
+	}
+	if !got[1].DiscountAmount.Equal(dec("50")) {
+		t.Errorf("platform promo should be trimmed to 50, got %s", got[1].DiscountAmount)
+	}
+}
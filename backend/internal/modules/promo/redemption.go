@@ -0,0 +1,154 @@
+package promo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/shopspring/decimal"
+)
+
+// Redeem records the applied promos against an order inside the caller's
+// transaction. Each promo's use and discount are claimed with a conditional
+// update that locks the promo row, so concurrent checkouts cannot overshoot
+// max_total_uses, max_uses_per_user or the budget cap. A promo whose limits
+// were reached since the cart was priced fails the checkout with a conflict.
+func (s *Service) Redeem(ctx context.Context, q *sqlc.Queries, tenantID, userID, orderID uuid.UUID, applied []PromoValidationResult) error {
+	for _, a := range applied {
+		p, err := q.RedeemPromo(ctx, sqlc.RedeemPromoParams{
+			DiscountAmount: toPgNumeric(a.DiscountAmount),
+			ID:             a.PromoID,
+			TenantID:       tenantID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.Conflict("promo " + promoLabel(a) + " is no longer available, please review your cart")
+		}
+		if err != nil {
+			return apperror.Internal("redeem promo", err)
+		}
+
+		// The promo row is locked until commit, so this count cannot race
+		// with another checkout by the same customer.
+		used, err := q.GetUsageCountByUserAndPromo(ctx, sqlc.GetUsageCountByUserAndPromoParams{
+			UserID:  userID,
+			PromoID: p.ID,
+		})
+		if err != nil {
+			return apperror.Internal("get usage count", err)
+		}
+		if used >= int64(p.MaxUsesPerUser) {
+			return apperror.Conflict("you have already used promo " + promoLabel(a) + " the maximum number of times")
+		}
+
+		if _, err := q.CreatePromoUsage(ctx, sqlc.CreatePromoUsageParams{
+			PromoID:        p.ID,
+			UserID:         userID,
+			OrderID:        orderID,
+			TenantID:       tenantID,
+			DiscountAmount: toPgNumeric(a.DiscountAmount),
+			CashbackAmount: toPgNumeric(decimal.Zero),
+			FundedBy:       p.FundedBy,
+		}); err != nil {
+			return apperror.Internal("create promo usage", err)
+		}
+	}
+	return nil
+}
+
+// ReleaseOrderPromos reverses the promo usages of a cancelled or rejected
+// order inside the caller's transaction, returning the uses and discount to
+// each promo's limits. It is a no-op for orders without promos and safe to
+// call more than once.
+func (s *Service) ReleaseOrderPromos(ctx context.Context, q *sqlc.Queries, tenantID, orderID uuid.UUID) error {
+	if err := q.ReleasePromoUsagesForOrder(ctx, sqlc.ReleasePromoUsagesForOrderParams{
+		OrderID:  orderID,
+		TenantID: tenantID,
+	}); err != nil {
+		return apperror.Internal("release promo usages", err)
+	}
+	return nil
+}
+
+func promoLabel(r PromoValidationResult) string {
+	if r.Code != "" {
+		return r.Code
+	}
+	return r.Title
+}
+
+// FunderCost is the promo cost carried by one funder over a period.
+type FunderCost struct {
+	FundedBy      sqlc.PromoFunder `json:"funded_by"`
+	Redemptions   int64            `json:"redemptions"`
+	DiscountTotal decimal.Decimal  `json:"discount_total"`
+	CashbackTotal decimal.Decimal  `json:"cashback_total"`
+}
+
+// RedemptionReport summarises promo redemptions over a period. Usages of
+// cancelled and rejected orders are excluded.
+type RedemptionReport struct {
+	From          time.Time                          `json:"from"`
+	To            time.Time                          `json:"to"`
+	Redemptions   int64                              `json:"redemptions"`
+	DiscountTotal decimal.Decimal                    `json:"discount_total"`
+	CashbackTotal decimal.Decimal                    `json:"cashback_total"`
+	ByFunder      []FunderCost                       `json:"by_funder"`
+	Promos        []sqlc.GetPromoRedemptionReportRow `json:"promos"`
+}
+
+// GetRedemptionReport returns promo redemptions in [from, to) with the cost
+// broken down by funder and by promo.
+func (s *Service) GetRedemptionReport(ctx context.Context, tenantID uuid.UUID, from, to time.Time) (*RedemptionReport, error) {
+	if !to.After(from) {
+		return nil, apperror.BadRequest("end date must be after start date")
+	}
+
+	rows, err := s.q.GetPromoRedemptionReport(ctx, sqlc.GetPromoRedemptionReportParams{
+		TenantID: tenantID,
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return nil, apperror.Internal("get promo redemption report", err)
+	}
+
+	report := &RedemptionReport{
+		From:          from,
+		To:            to,
+		DiscountTotal: decimal.Zero,
+		CashbackTotal: decimal.Zero,
+		Promos:        rows,
+	}
+	byFunder := make(map[sqlc.PromoFunder]*FunderCost)
+	for _, row := range rows {
+		discount := numericToDecimal(row.DiscountTotal)
+		cashback := numericToDecimal(row.CashbackTotal)
+
+		fc, ok := byFunder[row.FundedBy]
+		if !ok {
+			fc = &FunderCost{FundedBy: row.FundedBy, DiscountTotal: decimal.Zero, CashbackTotal: decimal.Zero}
+			byFunder[row.FundedBy] = fc
+		}
+		fc.Redemptions += row.Redemptions
+		fc.DiscountTotal = fc.DiscountTotal.Add(discount)
+		fc.CashbackTotal = fc.CashbackTotal.Add(cashback)
+
+		report.Redemptions += row.Redemptions
+		report.DiscountTotal = report.DiscountTotal.Add(discount)
+		report.CashbackTotal = report.CashbackTotal.Add(cashback)
+	}
+
+	report.ByFunder = make([]FunderCost, 0, len(byFunder))
+	for _, fc := range byFunder {
+		report.ByFunder = append(report.ByFunder, *fc)
+	}
+	sort.Slice(report.ByFunder, func(i, j int) bool {
+		return funderRank(report.ByFunder[i].FundedBy) < funderRank(report.ByFunder[j].FundedBy)
+	})
+	return report, nil
+}
@@ -0,0 +1,243 @@
+package promo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/shopspring/decimal"
+)
+
+// validateRules checks that a promo's rule configuration is coherent.
+func validateRules(rule sqlc.PromoRule, promoType sqlc.PromoType, appliesTo sqlc.PromoApplyOn, discountAmount decimal.Decimal, buyQty, getQty *int32, t Targeting) error {
+	switch rule {
+	case sqlc.PromoRuleStandard:
+		if !discountAmount.IsPositive() {
+			return apperror.BadRequest("discount_amount must be positive")
+		}
+	case sqlc.PromoRuleBogo:
+		if deref(buyQty) <= 0 || deref(getQty) <= 0 {
+			return apperror.BadRequest("buy-x-get-y promos need positive buy_qty and get_qty")
+		}
+		if appliesTo == sqlc.PromoApplyOnDeliveryCharge {
+			return apperror.BadRequest("buy-x-get-y promos cannot apply to the delivery charge")
+		}
+		if !discountAmount.IsPositive() {
+			return apperror.BadRequest("discount_amount must be positive")
+		}
+	case sqlc.PromoRuleTiered:
+	default:
+		return apperror.BadRequest("rule_type must be 'standard', 'bogo' or 'tiered'")
+	}
+
+	if promoType == sqlc.PromoTypePercent && discountAmount.GreaterThan(hundred) {
+		return apperror.BadRequest("percent discount cannot exceed 100")
+	}
+
+	seen := make(map[string]bool, len(t.Tiers))
+	for _, tier := range t.Tiers {
+		key := tier.MinSubtotal.StringFixed(2)
+		if seen[key] {
+			return apperror.BadRequest("duplicate tier min_subtotal " + key)
+		}
+		seen[key] = true
+		if tier.MinSubtotal.IsNegative() || !tier.DiscountAmount.IsPositive() {
+			return apperror.BadRequest("tiers need a non-negative min_subtotal and a positive discount_amount")
+		}
+		if promoType == sqlc.PromoTypePercent && tier.DiscountAmount.GreaterThan(hundred) {
+			return apperror.BadRequest("tier percent discount cannot exceed 100")
+		}
+	}
+
+	for _, w := range t.TimeWindows {
+		if w.DayOfWeek != nil && (*w.DayOfWeek < 0 || *w.DayOfWeek > 6) {
+			return apperror.BadRequest("day_of_week must be between 0 (Sunday) and 6")
+		}
+		if w.StartMinute < 0 || w.StartMinute > 1439 || w.EndMinute < 1 || w.EndMinute > 1440 || w.StartMinute == w.EndMinute {
+			return apperror.BadRequest("invalid time window")
+		}
+	}
+	return nil
+}
+
+// replaceTargeting rewrites every targeting set that is non-nil in t.
+func replaceTargeting(ctx context.Context, q *sqlc.Queries, promoID uuid.UUID, t Targeting) error {
+	if t.RestaurantIDs != nil {
+		if err := q.RemovePromoRestaurantRestrictions(ctx, promoID); err != nil {
+			return apperror.Internal("remove restaurant restrictions", err)
+		}
+		for _, id := range t.RestaurantIDs {
+			if err := q.AddPromoRestaurantRestriction(ctx, sqlc.AddPromoRestaurantRestrictionParams{
+				PromoID:      promoID,
+				RestaurantID: id,
+			}); err != nil {
+				return apperror.Internal("add restaurant restriction", err)
+			}
+		}
+	}
+
+	if t.CategoryIDs != nil {
+		if err := q.RemovePromoCategoryRestrictions(ctx, promoID); err != nil {
+			return apperror.Internal("remove category restrictions", err)
+		}
+		for _, id := range t.CategoryIDs {
+			if err := q.AddPromoCategoryRestriction(ctx, sqlc.AddPromoCategoryRestrictionParams{
+				PromoID:    promoID,
+				CategoryID: id,
+			}); err != nil {
+				return apperror.Internal("add category restriction", err)
+			}
+		}
+	}
+
+	if t.ProductIDs != nil {
+		if err := q.RemovePromoProductRestrictions(ctx, promoID); err != nil {
+			return apperror.Internal("remove product restrictions", err)
+		}
+		for _, id := range t.ProductIDs {
+			if err := q.AddPromoProductRestriction(ctx, sqlc.AddPromoProductRestrictionParams{
+				PromoID:   promoID,
+				ProductID: id,
+			}); err != nil {
+				return apperror.Internal("add product restriction", err)
+			}
+		}
+	}
+
+	if t.UserIDs != nil {
+		if err := q.RemovePromoUserEligibility(ctx, promoID); err != nil {
+			return apperror.Internal("remove user eligibility", err)
+		}
+		for _, id := range t.UserIDs {
+			if err := q.AddPromoUserEligibility(ctx, sqlc.AddPromoUserEligibilityParams{
+				PromoID: promoID,
+				UserID:  id,
+			}); err != nil {
+				return apperror.Internal("add user eligibility", err)
+			}
+		}
+	}
+
+	if t.Tiers != nil {
+		if err := q.RemovePromoTiers(ctx, promoID); err != nil {
+			return apperror.Internal("remove promo tiers", err)
+		}
+		for _, tier := range t.Tiers {
+			if _, err := q.CreatePromoTier(ctx, sqlc.CreatePromoTierParams{
+				PromoID:        promoID,
+				MinSubtotal:    toPgNumeric(tier.MinSubtotal),
+				DiscountAmount: toPgNumeric(tier.DiscountAmount),
+			}); err != nil {
+				return apperror.Internal("create promo tier", err)
+			}
+		}
+	}
+
+	if t.TimeWindows != nil {
+		if err := q.RemovePromoTimeWindows(ctx, promoID); err != nil {
+			return apperror.Internal("remove promo time windows", err)
+		}
+		for _, w := range t.TimeWindows {
+			if _, err := q.CreatePromoTimeWindow(ctx, sqlc.CreatePromoTimeWindowParams{
+				PromoID:     promoID,
+				DayOfWeek:   w.DayOfWeek,
+				StartMinute: w.StartMinute,
+				EndMinute:   w.EndMinute,
+			}); err != nil {
+				return apperror.Internal("create promo time window", err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadRules fetches the restriction sets, tiers and windows a promo is
+// evaluated with.
+func loadRules(ctx context.Context, q *sqlc.Queries, p sqlc.Promo) (*Rules, error) {
+	restaurants, err := q.ListPromoRestaurantRestrictions(ctx, p.ID)
+	if err != nil {
+		return nil, apperror.Internal("list restaurant restrictions", err)
+	}
+	categories, err := q.ListPromoCategoryRestrictions(ctx, p.ID)
+	if err != nil {
+		return nil, apperror.Internal("list category restrictions", err)
+	}
+	products, err := q.ListPromoProductRestrictions(ctx, p.ID)
+	if err != nil {
+		return nil, apperror.Internal("list product restrictions", err)
+	}
+
+	rules := &Rules{
+		Promo:         p,
+		RestaurantIDs: toSet(restaurants),
+		CategoryIDs:   toSet(categories),
+		ProductIDs:    toSet(products),
+	}
+	if p.BudgetCap.Valid {
+		remaining := decimal.Max(numericToDecimal(p.BudgetCap).Sub(numericToDecimal(p.TotalDiscountGiven)), decimal.Zero)
+		rules.Budget = &remaining
+	}
+
+	if p.RuleType == sqlc.PromoRuleTiered {
+		rules.Tiers, err = q.ListPromoTiers(ctx, p.ID)
+		if err != nil {
+			return nil, apperror.Internal("list promo tiers", err)
+		}
+	}
+	rules.Windows, err = q.ListPromoTimeWindows(ctx, p.ID)
+	if err != nil {
+		return nil, apperror.Internal("list promo time windows", err)
+	}
+	return rules, nil
+}
+
+func loadDetail(ctx context.Context, q *sqlc.Queries, p sqlc.Promo) (*PromoDetail, error) {
+	rules, err := loadRules(ctx, q, p)
+	if err != nil {
+		return nil, err
+	}
+	users, err := q.ListPromoUserEligibility(ctx, p.ID)
+	if err != nil {
+		return nil, apperror.Internal("list promo eligibility", err)
+	}
+	tiers := rules.Tiers
+	if tiers == nil {
+		tiers = []sqlc.PromoTier{}
+	}
+
+	return &PromoDetail{
+		Promo:         p,
+		RestaurantIDs: fromSet(rules.RestaurantIDs),
+		CategoryIDs:   fromSet(rules.CategoryIDs),
+		ProductIDs:    fromSet(rules.ProductIDs),
+		UserIDs:       users,
+		Tiers:         tiers,
+		TimeWindows:   rules.Windows,
+	}, nil
+}
+
+func toSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func fromSet(set map[uuid.UUID]bool) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+func toPgNumeric(d decimal.Decimal) pgtype.Numeric {
+	n := pgtype.Numeric{Valid: true}
+	_ = n.Scan(d.String())
+	return n
+}
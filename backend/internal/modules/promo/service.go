@@ -8,7 +8,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
@@ -17,12 +19,13 @@ import (
 
 // Service handles promo business logic.
 type Service struct {
-	q *sqlc.Queries
+	q    *sqlc.Queries
+	pool *pgxpool.Pool
 }
 
 // NewService creates a new promo service.
-func NewService(q *sqlc.Queries) *Service {
-	return &Service{q: q}
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool) *Service {
+	return &Service{q: q, pool: pool}
 }
 
 // CreatePromoRequest holds fields for creating a promo.
@@ -44,10 +47,75 @@ type CreatePromoRequest struct {
 	StartsAt       time.Time
 	EndsAt         *time.Time
 	CreatedBy      uuid.UUID
+
+	RuleType        sqlc.PromoRule
+	BuyQty          *int32
+	GetQty          *int32
+	FirstOrderOnly  bool
+	NewCustomerDays *int32
+	AutoApply       bool
+	Stacking        sqlc.PromoStacking
+	BudgetCap       *decimal.Decimal // total discount budget; nil = unlimited
+	Targeting
+}
+
+// Targeting holds the restriction sets, spend tiers and time windows of a
+// promo. On update a nil slice leaves the stored set untouched and a non-nil
+// slice replaces it.
+type Targeting struct {
+	RestaurantIDs []uuid.UUID
+	CategoryIDs   []uuid.UUID
+	ProductIDs    []uuid.UUID
+	UserIDs       []uuid.UUID
+	Tiers         []TierRequest
+	TimeWindows   []TimeWindowRequest
+}
+
+// TierRequest is one spend threshold of a tiered promo.
+type TierRequest struct {
+	MinSubtotal    decimal.Decimal
+	DiscountAmount decimal.Decimal
 }
 
-// CreatePromo creates a new promotion.
-func (s *Service) CreatePromo(ctx context.Context, req CreatePromoRequest) (*sqlc.Promo, error) {
+// TimeWindowRequest is a daily availability window in Bangladesh local time.
+type TimeWindowRequest struct {
+	DayOfWeek   *int32 // 0 = Sunday; nil = every day
+	StartMinute int32
+	EndMinute   int32
+}
+
+// PromoDetail is a promo together with its targeting rules.
+type PromoDetail struct {
+	sqlc.Promo
+	RestaurantIDs []uuid.UUID            `json:"restaurant_ids"`
+	CategoryIDs   []uuid.UUID            `json:"category_ids"`
+	ProductIDs    []uuid.UUID            `json:"product_ids"`
+	UserIDs       []uuid.UUID            `json:"user_ids"`
+	Tiers         []sqlc.PromoTier       `json:"tiers"`
+	TimeWindows   []sqlc.PromoTimeWindow `json:"time_windows"`
+}
+
+// CreatePromo creates a new promotion with its targeting rules.
+func (s *Service) CreatePromo(ctx context.Context, req CreatePromoRequest) (*PromoDetail, error) {
+	if req.RuleType == "" {
+		req.RuleType = sqlc.PromoRuleStandard
+	}
+	if req.Stacking == "" {
+		req.Stacking = sqlc.PromoStackingExclusive
+	}
+	if req.Stacking != sqlc.PromoStackingExclusive && req.Stacking != sqlc.PromoStackingStackable {
+		return nil, apperror.BadRequest("stacking must be 'exclusive' or 'stackable'")
+	}
+	if err := validateRules(req.RuleType, req.PromoType, req.AppliesTo, req.DiscountAmount, req.BuyQty, req.GetQty, req.Targeting); err != nil {
+		return nil, err
+	}
+	if req.BudgetCap != nil && !req.BudgetCap.IsPositive() {
+		return nil, apperror.BadRequest("budget_cap must be positive")
+	}
+	if req.RuleType == sqlc.PromoRuleTiered && len(req.Tiers) == 0 {
+		return nil, apperror.BadRequest("tiered promos need at least one tier")
+	}
+
 	discountAmt := pgtype.Numeric{Valid: true}
 	_ = discountAmt.Scan(req.DiscountAmount.String())
 
@@ -68,34 +136,70 @@ func (s *Service) CreatePromo(ctx context.Context, req CreatePromoRequest) (*sql
 		endsAt = pgtype.Timestamptz{Time: *req.EndsAt, Valid: true}
 	}
 
-	p, err := s.q.CreatePromo(ctx, sqlc.CreatePromoParams{
-		TenantID:       req.TenantID,
-		Code:           req.Code,
-		Title:          req.Title,
-		Description:    sql.NullString{String: req.Description, Valid: req.Description != ""},
-		PromoType:      req.PromoType,
-		DiscountAmount: discountAmt,
-		MaxDiscountCap: maxCap,
-		CashbackAmount: cashback,
-		FundedBy:       req.FundedBy,
-		AppliesTo:      req.AppliesTo,
-		MinOrderAmount: minOrder,
-		MaxTotalUses:   req.MaxTotalUses,
-		MaxUsesPerUser: req.MaxUsesPerUser,
-		IncludeStores:  req.IncludeStores,
-		IsActive:       true,
-		StartsAt:       req.StartsAt,
-		EndsAt:         endsAt,
-		CreatedBy:      pgtype.UUID{Bytes: req.CreatedBy, Valid: true},
+	var budgetCap pgtype.Numeric
+	if req.BudgetCap != nil {
+		budgetCap = toPgNumeric(*req.BudgetCap)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	p, err := qtx.CreatePromo(ctx, sqlc.CreatePromoParams{
+		TenantID:        req.TenantID,
+		Code:            sql.NullString{String: req.Code, Valid: req.Code != ""},
+		Title:           req.Title,
+		Description:     sql.NullString{String: req.Description, Valid: req.Description != ""},
+		PromoType:       req.PromoType,
+		DiscountAmount:  discountAmt,
+		MaxDiscountCap:  maxCap,
+		CashbackAmount:  cashback,
+		FundedBy:        req.FundedBy,
+		AppliesTo:       req.AppliesTo,
+		MinOrderAmount:  minOrder,
+		MaxTotalUses:    req.MaxTotalUses,
+		MaxUsesPerUser:  req.MaxUsesPerUser,
+		IncludeStores:   req.IncludeStores,
+		IsActive:        true,
+		StartsAt:        req.StartsAt,
+		EndsAt:          endsAt,
+		CreatedBy:       pgtype.UUID{Bytes: req.CreatedBy, Valid: true},
+		RuleType:        req.RuleType,
+		BuyQty:          req.BuyQty,
+		GetQty:          req.GetQty,
+		FirstOrderOnly:  req.FirstOrderOnly,
+		NewCustomerDays: req.NewCustomerDays,
+		AutoApply:       req.AutoApply,
+		Stacking:        req.Stacking,
+		BudgetCap:       budgetCap,
 	})
+	if isUniqueViolation(err) {
+		return nil, apperror.Conflict("a promo with this code already exists")
+	}
 	if err != nil {
 		return nil, apperror.Internal("create promo", err)
 	}
-	return &p, nil
+
+	if err := replaceTargeting(ctx, qtx, p.ID, req.Targeting); err != nil {
+		return nil, err
+	}
+
+	detail, err := loadDetail(ctx, qtx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit transaction", err)
+	}
+	return detail, nil
 }
 
-// GetPromo returns a promo by ID.
-func (s *Service) GetPromo(ctx context.Context, tenantID, promoID uuid.UUID) (*sqlc.Promo, error) {
+// GetPromo returns a promo by ID with its targeting rules.
+func (s *Service) GetPromo(ctx context.Context, tenantID, promoID uuid.UUID) (*PromoDetail, error) {
 	p, err := s.q.GetPromoByID(ctx, sqlc.GetPromoByIDParams{
 		ID:       promoID,
 		TenantID: tenantID,
@@ -106,7 +210,7 @@ func (s *Service) GetPromo(ctx context.Context, tenantID, promoID uuid.UUID) (*s
 	if err != nil {
 		return nil, apperror.Internal("get promo", err)
 	}
-	return &p, nil
+	return loadDetail(ctx, s.q, p)
 }
 
 // ListPromos returns paginated promos for a tenant.
@@ -143,10 +247,26 @@ type UpdatePromoRequest struct {
 	MaxUsesPerUser *int32
 	StartsAt       *time.Time
 	EndsAt         *time.Time
+
+	BuyQty          *int32
+	GetQty          *int32
+	FirstOrderOnly  *bool
+	NewCustomerDays *int32
+	AutoApply       *bool
+	Stacking        *sqlc.PromoStacking
+	BudgetCap       *decimal.Decimal
+	Targeting
 }
 
-// UpdatePromo updates a promo.
-func (s *Service) UpdatePromo(ctx context.Context, tenantID, promoID uuid.UUID, req UpdatePromoRequest) (*sqlc.Promo, error) {
+// UpdatePromo updates a promo and, where given, replaces its targeting rules.
+func (s *Service) UpdatePromo(ctx context.Context, tenantID, promoID uuid.UUID, req UpdatePromoRequest) (*PromoDetail, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin transaction", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
 	params := sqlc.UpdatePromoParams{
 		ID:       promoID,
 		TenantID: tenantID,
@@ -186,15 +306,51 @@ func (s *Service) UpdatePromo(ctx context.Context, tenantID, promoID uuid.UUID,
 	if req.EndsAt != nil {
 		params.EndsAt = pgtype.Timestamptz{Time: *req.EndsAt, Valid: true}
 	}
+	params.BuyQty = req.BuyQty
+	params.GetQty = req.GetQty
+	params.FirstOrderOnly = req.FirstOrderOnly
+	params.NewCustomerDays = req.NewCustomerDays
+	params.AutoApply = req.AutoApply
+	if req.Stacking != nil {
+		if *req.Stacking != sqlc.PromoStackingExclusive && *req.Stacking != sqlc.PromoStackingStackable {
+			return nil, apperror.BadRequest("stacking must be 'exclusive' or 'stackable'")
+		}
+		params.Stacking = sqlc.NullPromoStacking{PromoStacking: *req.Stacking, Valid: true}
+	}
+	if req.BudgetCap != nil {
+		if !req.BudgetCap.IsPositive() {
+			return nil, apperror.BadRequest("budget_cap must be positive")
+		}
+		params.BudgetCap = toPgNumeric(*req.BudgetCap)
+	}
 
-	p, err := s.q.UpdatePromo(ctx, params)
+	p, err := qtx.UpdatePromo(ctx, params)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, apperror.NotFound("promo")
 	}
 	if err != nil {
 		return nil, apperror.Internal("update promo", err)
 	}
-	return &p, nil
+
+	if err := validateRules(p.RuleType, p.PromoType, p.AppliesTo, numericToDecimal(p.DiscountAmount), p.BuyQty, p.GetQty, req.Targeting); err != nil {
+		return nil, err
+	}
+	if p.RuleType == sqlc.PromoRuleTiered && req.Tiers != nil && len(req.Tiers) == 0 {
+		return nil, apperror.BadRequest("tiered promos need at least one tier")
+	}
+	if err := replaceTargeting(ctx, qtx, p.ID, req.Targeting); err != nil {
+		return nil, err
+	}
+
+	detail, err := loadDetail(ctx, qtx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit transaction", err)
+	}
+	return detail, nil
 }
 
 // DeactivatePromo deactivates a promo.
@@ -223,21 +379,31 @@ type CartItem struct {
 }
 
 // PromoValidationResult contains the result of promo validation.
+// DiscountAmount is the combined item and delivery discount; Allocations
+// attribute the item part to individual cart lines.
 type PromoValidationResult struct {
-	Valid          bool            `json:"valid"`
-	PromoID        uuid.UUID       `json:"promo_id"`
-	Code           string          `json:"code"`
-	PromoType      sqlc.PromoType  `json:"promo_type"`
-	DiscountAmount decimal.Decimal `json:"discount_amount"`
-	CashbackAmount decimal.Decimal `json:"cashback_amount"`
-	ErrorMessage   string          `json:"error_message,omitempty"`
+	Valid            bool               `json:"valid"`
+	PromoID          uuid.UUID          `json:"promo_id"`
+	Code             string             `json:"code"`
+	Title            string             `json:"title,omitempty"`
+	PromoType        sqlc.PromoType     `json:"promo_type"`
+	RuleType         sqlc.PromoRule     `json:"rule_type,omitempty"`
+	AppliesTo        sqlc.PromoApplyOn  `json:"applies_to,omitempty"`
+	FundedBy         sqlc.PromoFunder   `json:"funded_by,omitempty"`
+	Stacking         sqlc.PromoStacking `json:"stacking,omitempty"`
+	AutoApplied      bool               `json:"auto_applied"`
+	DiscountAmount   decimal.Decimal    `json:"discount_amount"`
+	ItemDiscount     decimal.Decimal    `json:"item_discount"`
+	DeliveryDiscount decimal.Decimal    `json:"delivery_discount"`
+	CashbackAmount   decimal.Decimal    `json:"cashback_amount"`
+	Allocations      []ItemAllocation   `json:"allocations,omitempty"`
+	ErrorMessage     string             `json:"error_message,omitempty"`
 }
 
 // Validate validates a promo code against the given cart and user context.
-func (s *Service) Validate(ctx context.Context, tenantID, userID uuid.UUID, code string, cartTotal decimal.Decimal, cartItems []CartItem) (*PromoValidationResult, error) {
-	// 1. Get active promo by code
+func (s *Service) Validate(ctx context.Context, tenantID, userID uuid.UUID, code string, cart Cart) (*PromoValidationResult, error) {
 	promo, err := s.q.GetActivePromoByCode(ctx, sqlc.GetActivePromoByCodeParams{
-		Code:     code,
+		Code:     sql.NullString{String: code, Valid: true},
 		TenantID: tenantID,
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -249,18 +415,30 @@ func (s *Service) Validate(ctx context.Context, tenantID, userID uuid.UUID, code
 	if err != nil {
 		return nil, apperror.Internal("get promo", err)
 	}
+	return s.evaluate(ctx, tenantID, userID, promo, cart)
+}
 
-	// 2. Check max_total_uses
-	if promo.MaxTotalUses != nil && promo.TotalUses >= *promo.MaxTotalUses {
+// evaluate checks an active promo's usage limits and customer conditions and
+// applies its rules to the cart.
+func (s *Service) evaluate(ctx context.Context, tenantID, userID uuid.UUID, promo sqlc.Promo, cart Cart) (*PromoValidationResult, error) {
+	invalid := func(msg string) (*PromoValidationResult, error) {
 		return &PromoValidationResult{
 			Valid:        false,
 			PromoID:      promo.ID,
-			Code:         promo.Code,
-			ErrorMessage: "promo usage limit reached",
+			Code:         promo.Code.String,
+			ErrorMessage: msg,
 		}, nil
 	}
 
-	// 3. Check per-user limit
+	// 1. Check max_total_uses and the remaining budget
+	if promo.MaxTotalUses != nil && promo.TotalUses >= *promo.MaxTotalUses {
+		return invalid("promo usage limit reached")
+	}
+	if promo.BudgetCap.Valid && !numericToDecimal(promo.BudgetCap).GreaterThan(numericToDecimal(promo.TotalDiscountGiven)) {
+		return invalid("promo budget has been used up")
+	}
+
+	// 2. Check per-user limit
 	userUsageCount, err := s.q.GetUsageCountByUserAndPromo(ctx, sqlc.GetUsageCountByUserAndPromoParams{
 		UserID:  userID,
 		PromoID: promo.ID,
@@ -269,29 +447,33 @@ func (s *Service) Validate(ctx context.Context, tenantID, userID uuid.UUID, code
 		return nil, apperror.Internal("get usage count", err)
 	}
 	if userUsageCount >= int64(promo.MaxUsesPerUser) {
-		return &PromoValidationResult{
-			Valid:        false,
-			PromoID:      promo.ID,
-			Code:         promo.Code,
-			ErrorMessage: "you have already used this promo code the maximum number of times",
-		}, nil
+		return invalid("you have already used this promo code the maximum number of times")
 	}
 
-	// 4. Check min order amount
-	minOrderAmt := decimal.Zero
-	if promo.MinOrderAmount.Valid {
-		_ = minOrderAmt.Scan(promo.MinOrderAmount.Int.String())
-	}
-	if cartTotal.LessThan(minOrderAmt) {
-		return &PromoValidationResult{
-			Valid:        false,
-			PromoID:      promo.ID,
-			Code:         promo.Code,
-			ErrorMessage: "minimum order amount of " + minOrderAmt.String() + " not met",
-		}, nil
+	// 3. Check first-order and new-customer conditions
+	if promo.FirstOrderOnly || promo.NewCustomerDays != nil {
+		history, err := s.q.GetPromoCustomerHistory(ctx, sqlc.GetPromoCustomerHistoryParams{
+			TenantID: tenantID,
+			UserID:   userID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return invalid("you are not eligible for this promo")
+		}
+		if err != nil {
+			return nil, apperror.Internal("get customer history", err)
+		}
+		if promo.FirstOrderOnly && history.OrderCount > 0 {
+			return invalid("this promo is only valid on your first order")
+		}
+		if promo.NewCustomerDays != nil {
+			cutoff := time.Now().AddDate(0, 0, -int(*promo.NewCustomerDays))
+			if history.RegisteredAt.Before(cutoff) {
+				return invalid("this promo is only for new customers")
+			}
+		}
 	}
 
-	// 5. Check user eligibility (if restricted)
+	// 4. Check user eligibility (if restricted)
 	eligibleUsers, err := s.q.ListPromoUserEligibility(ctx, promo.ID)
 	if err != nil {
 		return nil, apperror.Internal("list promo eligibility", err)
@@ -305,100 +487,40 @@ func (s *Service) Validate(ctx context.Context, tenantID, userID uuid.UUID, code
 			}
 		}
 		if !eligible {
-			return &PromoValidationResult{
-				Valid:        false,
-				PromoID:      promo.ID,
-				Code:         promo.Code,
-				ErrorMessage: "you are not eligible for this promo",
-			}, nil
-		}
-	}
-
-	// 6. Check restaurant restrictions
-	if promo.AppliesTo == sqlc.PromoApplyOnSpecificRestaurant {
-		restrictedRestaurants, err := s.q.ListPromoRestaurantRestrictions(ctx, promo.ID)
-		if err != nil {
-			return nil, apperror.Internal("list restaurant restrictions", err)
-		}
-		if len(restrictedRestaurants) > 0 {
-			restaurantMap := make(map[uuid.UUID]bool)
-			for _, rid := range restrictedRestaurants {
-				restaurantMap[rid] = true
-			}
-			for _, item := range cartItems {
-				if !restaurantMap[item.RestaurantID] {
-					return &PromoValidationResult{
-						Valid:        false,
-						PromoID:      promo.ID,
-						Code:         promo.Code,
-						ErrorMessage: "promo is not valid for all restaurants in your cart",
-					}, nil
-				}
-			}
+			return invalid("you are not eligible for this promo")
 		}
 	}
 
-	// 7. Check category restrictions
-	if promo.AppliesTo == sqlc.PromoApplyOnCategory {
-		restrictedCategories, err := s.q.ListPromoCategoryRestrictions(ctx, promo.ID)
-		if err != nil {
-			return nil, apperror.Internal("list category restrictions", err)
-		}
-		if len(restrictedCategories) > 0 {
-			categoryMap := make(map[uuid.UUID]bool)
-			for _, cid := range restrictedCategories {
-				categoryMap[cid] = true
-			}
-			for _, item := range cartItems {
-				if !categoryMap[item.CategoryID] {
-					return &PromoValidationResult{
-						Valid:        false,
-						PromoID:      promo.ID,
-						Code:         promo.Code,
-						ErrorMessage: "promo is not valid for all categories in your cart",
-					}, nil
-				}
-			}
-		}
-	}
-
-	// 8. Calculate discount
-	discountAmt := decimal.Zero
-	if promo.DiscountAmount.Valid {
-		_ = discountAmt.Scan(promo.DiscountAmount.Int.String())
-	}
-
-	var finalDiscount decimal.Decimal
-	if promo.PromoType == sqlc.PromoTypePercent {
-		finalDiscount = cartTotal.Mul(discountAmt).Div(decimal.NewFromInt(100))
-		// Apply max discount cap
-		if promo.MaxDiscountCap.Valid {
-			maxCap := decimal.Zero
-			_ = maxCap.Scan(promo.MaxDiscountCap.Int.String())
-			if finalDiscount.GreaterThan(maxCap) {
-				finalDiscount = maxCap
-			}
-		}
-	} else {
-		finalDiscount = discountAmt
-	}
-
-	// Don't let discount exceed cart total
-	if finalDiscount.GreaterThan(cartTotal) {
-		finalDiscount = cartTotal
+	// 5. Apply the promo's rules to the cart
+	rules, err := loadRules(ctx, s.q, promo)
+	if err != nil {
+		return nil, err
 	}
-
-	cashback := decimal.Zero
-	if promo.CashbackAmount.Valid {
-		_ = cashback.Scan(promo.CashbackAmount.Int.String())
+	discount, reason := rules.Apply(cart)
+	if discount == nil {
+		return invalid(reason)
 	}
 
 	return &PromoValidationResult{
-		Valid:          true,
-		PromoID:        promo.ID,
-		Code:           promo.Code,
-		PromoType:      promo.PromoType,
-		DiscountAmount: finalDiscount,
-		CashbackAmount: cashback,
+		Valid:            true,
+		PromoID:          promo.ID,
+		Code:             promo.Code.String,
+		Title:            promo.Title,
+		PromoType:        promo.PromoType,
+		RuleType:         promo.RuleType,
+		AppliesTo:        promo.AppliesTo,
+		FundedBy:         promo.FundedBy,
+		Stacking:         promo.Stacking,
+		AutoApplied:      promo.AutoApply,
+		DiscountAmount:   discount.Total(),
+		ItemDiscount:     discount.ItemDiscount,
+		DeliveryDiscount: discount.DeliveryDiscount,
+		CashbackAmount:   numericToDecimal(promo.CashbackAmount),
+		Allocations:      discount.Allocations,
 	}, nil
 }
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/pagination"
@@ -65,8 +66,11 @@ func (s *Service) CreateRating(ctx context.Context, tenantID uuid.UUID, req Crea
 		return nil, err
 	}
 
-	// Update restaurant aggregate rating
+	// Update restaurant and rider aggregate ratings
 	go s.updateRestaurantRating(context.Background(), req.RestaurantID)
+	if req.RiderRating != nil && order.RiderID.Valid {
+		go s.updateRiderRating(context.Background(), order.RiderID.Bytes)
+	}
 
 	return &review, nil
 }
@@ -84,6 +88,19 @@ func (s *Service) updateRestaurantRating(ctx context.Context, restaurantID uuid.
 	})
 }
 
+// updateRiderRating recalculates the rider's aggregate rating.
+func (s *Service) updateRiderRating(ctx context.Context, riderID uuid.UUID) {
+	avg, err := s.q.GetRiderAvgRating(ctx, pgtype.UUID{Bytes: riderID, Valid: true})
+	if err != nil {
+		return
+	}
+	s.q.UpdateRiderRating(ctx, sqlc.UpdateRiderRatingParams{
+		ID:          riderID,
+		RatingAvg:   avg.AvgRating,
+		RatingCount: avg.RatingCount,
+	})
+}
+
 // ListByRestaurant returns paginated public reviews for a restaurant.
 func (s *Service) ListByRestaurant(ctx context.Context, tenantID, restaurantID uuid.UUID, page, perPage int) ([]sqlc.Review, pagination.Meta, error) {
 	limit, offset := pagination.FormatLimitOffset(page, perPage)
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
@@ -33,8 +34,13 @@ func (h *Handler) ListRestaurants(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, apperror.NotFound("tenant"))
 		return
 	}
+	scope := access.FromContext(r.Context())
+	if scope == nil {
+		respond.Error(w, apperror.Forbidden("restaurant access not resolved"))
+		return
+	}
 	page, perPage := parsePagination(r)
-	items, meta, err := h.svc.ListRestaurants(r.Context(), t.ID, page, perPage)
+	items, meta, err := h.svc.ListRestaurants(r.Context(), t.ID, scope.Filter(), page, perPage)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -207,6 +213,7 @@ func (h *Handler) UpdateRestaurant(w http.ResponseWriter, r *http.Request) {
 		IsAvailable        *bool          `json:"is_available"`
 		IsFeatured         *bool          `json:"is_featured"`
 		SortOrder          *int32         `json:"sort_order"`
+		RequirePod         *bool          `json:"require_pod"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -242,6 +249,7 @@ func (h *Handler) UpdateRestaurant(w http.ResponseWriter, r *http.Request) {
 		AutoAcceptOrders:   req.AutoAcceptOrders,
 		IsFeatured:         req.IsFeatured,
 		SortOrder:          req.SortOrder,
+		RequirePod:         req.RequirePod,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -41,16 +41,20 @@ func (r *Repository) GetRestaurantBySlug(ctx context.Context, tenantID uuid.UUID
 	return &res, nil
 }
 
-func (r *Repository) ListRestaurantsByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int32) ([]sqlc.Restaurant, error) {
+func (r *Repository) ListRestaurantsByTenant(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, limit, offset int32) ([]sqlc.Restaurant, error) {
 	return r.q.ListRestaurantsByTenant(ctx, sqlc.ListRestaurantsByTenantParams{
-		TenantID: tenantID,
-		Limit:    limit,
-		Offset:   offset,
+		TenantID:      tenantID,
+		RestaurantIds: restaurantIDs,
+		Limit:         limit,
+		Offset:        offset,
 	})
 }
 
-func (r *Repository) CountRestaurantsByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
-	return r.q.CountRestaurantsByTenant(ctx, tenantID)
+func (r *Repository) CountRestaurantsByTenant(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID) (int64, error) {
+	return r.q.CountRestaurantsByTenant(ctx, sqlc.CountRestaurantsByTenantParams{
+		TenantID:      tenantID,
+		RestaurantIds: restaurantIDs,
+	})
 }
 
 func (r *Repository) ListAvailableByHubAndArea(ctx context.Context, tenantID uuid.UUID, areaSlug string, limit, offset int32) ([]sqlc.Restaurant, error) {
@@ -113,14 +113,15 @@ func (s *Service) GetRestaurantBySlug(ctx context.Context, tenantID uuid.UUID, s
 	return res, err
 }
 
-// ListRestaurants returns paginated restaurants for a tenant.
-func (s *Service) ListRestaurants(ctx context.Context, tenantID uuid.UUID, page, perPage int) ([]sqlc.Restaurant, pagination.Meta, error) {
+// ListRestaurants returns paginated restaurants for a tenant. restaurantIDs
+// limits the list to those restaurants; nil lists all of them.
+func (s *Service) ListRestaurants(ctx context.Context, tenantID uuid.UUID, restaurantIDs []uuid.UUID, page, perPage int) ([]sqlc.Restaurant, pagination.Meta, error) {
 	limit, offset := pagination.FormatLimitOffset(page, perPage)
-	total, err := s.repo.CountRestaurantsByTenant(ctx, tenantID)
+	total, err := s.repo.CountRestaurantsByTenant(ctx, tenantID, restaurantIDs)
 	if err != nil {
 		return nil, pagination.Meta{}, apperror.Internal("count restaurants", err)
 	}
-	items, err := s.repo.ListRestaurantsByTenant(ctx, tenantID, int32(limit), int32(offset))
+	items, err := s.repo.ListRestaurantsByTenant(ctx, tenantID, restaurantIDs, int32(limit), int32(offset))
 	if err != nil {
 		return nil, pagination.Meta{}, apperror.Internal("list restaurants", err)
 	}
@@ -147,6 +148,7 @@ type UpdateRestaurantRequest struct {
 	AutoAcceptOrders   *bool
 	IsFeatured         *bool
 	SortOrder          *int32
+	RequirePod         *bool
 }
 
 // UpdateRestaurant updates a restaurant.
@@ -171,6 +173,7 @@ func (s *Service) UpdateRestaurant(ctx context.Context, id, tenantID uuid.UUID,
 		AutoAcceptOrders:   req.AutoAcceptOrders,
 		IsFeatured:         req.IsFeatured,
 		SortOrder:          req.SortOrder,
+		RequirePod:         req.RequirePod,
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, apperror.NotFound("restaurant")
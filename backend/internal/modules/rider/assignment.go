@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/pkg/geo"
 	redisclient "github.com/munchies/platform/backend/internal/platform/redis"
@@ -18,6 +17,7 @@ const (
 	assignmentBatchSize = 3
 	maxBatches          = 3
 	batchTimeoutSec     = 60
+	offerPollInterval   = 5 * time.Second
 )
 
 // AssignmentService handles auto-assignment of riders to orders.
@@ -34,9 +34,11 @@ func NewAssignmentService(q *sqlc.Queries, redis *redisclient.Client) *Assignmen
 type riderDistance struct {
 	rider    sqlc.Rider
 	distance float64
+	rank     float64
 }
 
-// AutoAssign finds and assigns the nearest available rider to an order.
+// AutoAssign offers an order to the nearest available riders in batches and
+// waits for one of them to accept it.
 func (s *AssignmentService) AutoAssign(ctx context.Context, orderID, tenantID uuid.UUID) error {
 	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{
 		ID: orderID, TenantID: tenantID,
@@ -46,6 +48,10 @@ func (s *AssignmentService) AutoAssign(ctx context.Context, orderID, tenantID uu
 		return err
 	}
 
+	if order.RiderID.Valid {
+		return nil
+	}
+
 	if !order.HubID.Valid {
 		log.Warn().Str("order_id", orderID.String()).Msg("auto-assign: order has no hub_id")
 		return nil
@@ -59,6 +65,20 @@ func (s *AssignmentService) AutoAssign(ctx context.Context, orderID, tenantID uu
 		log.Error().Err(err).Msg("auto-assign: list available riders failed")
 		return err
 	}
+
+	// Riders holding more cash than the hub allows must deposit first.
+	if hub, err := s.q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: order.HubID.Bytes, TenantID: tenantID}); err == nil && hub.RiderCashLimit.Valid {
+		eligible := riders[:0]
+		for _, r := range riders {
+			if cashLimitExceeded(numericToDecimal(r.CashInHand), hub.RiderCashLimit) {
+				log.Info().Str("rider_id", r.ID.String()).Msg("auto-assign: skipping rider over cash limit")
+				continue
+			}
+			eligible = append(eligible, r)
+		}
+		riders = eligible
+	}
+
 	if len(riders) == 0 {
 		log.Warn().Str("order_id", orderID.String()).Msg("auto-assign: no available riders in hub")
 		return nil
@@ -77,11 +97,12 @@ func (s *AssignmentService) AutoAssign(ctx context.Context, orderID, tenantID uu
 		rLat, _ := numericToFloat64(loc.GeoLat)
 		rLng, _ := numericToFloat64(loc.GeoLng)
 		dist := geo.DistanceKm(rLat, rLng, deliveryLat, deliveryLng)
-		ranked = append(ranked, riderDistance{rider: r, distance: dist})
+		ranked = append(ranked, riderDistance{rider: r, distance: dist, rank: dispatchRank(dist, r.Tier)})
 	}
 
+	// Nearest first, with higher tiers allowed a little further away.
 	sort.Slice(ranked, func(i, j int) bool {
-		return ranked[i].distance < ranked[j].distance
+		return ranked[i].rank < ranked[j].rank
 	})
 
 	// Try up to maxBatches batches of assignmentBatchSize riders
@@ -106,48 +127,50 @@ func (s *AssignmentService) AutoAssign(ctx context.Context, orderID, tenantID uu
 			Str("order_id", orderID.String()).
 			Msg("auto-assign: sent batch offers")
 
-		// For simplicity, auto-accept the first rider in the first batch
-		if batch == 0 && len(batchRiders) > 0 {
-			chosen := batchRiders[0].rider
-			_, err := s.q.AssignRiderToOrder(ctx, sqlc.AssignRiderToOrderParams{
-				ID:       orderID,
-				TenantID: tenantID,
-				RiderID:  pgtype.UUID{Bytes: chosen.ID, Valid: true},
-			})
-			if err != nil {
-				log.Error().Err(err).Msg("auto-assign: assign rider failed")
-				return err
-			}
-
-			s.q.CreateTimelineEvent(ctx, sqlc.CreateTimelineEventParams{
-				OrderID:   orderID,
-				TenantID:  tenantID,
-				EventType: "rider_assigned",
-				NewStatus: sqlc.NullOrderStatus{OrderStatus: order.Status, Valid: true},
-				Description: "Rider auto-assigned",
-				ActorID:   pgtype.UUID{Bytes: chosen.ID, Valid: true},
-				ActorType: sqlc.ActorTypeSystem,
-				Metadata:  json.RawMessage(`{}`),
-			})
-
+		// Offers stay open until one of the riders accepts through
+		// AcceptOrder; unanswered offers expire and the next batch is tried.
+		riderID, err := s.awaitAcceptance(ctx, orderID, tenantID, time.Duration(batchTimeoutSec)*time.Second)
+		if err != nil {
+			return err
+		}
+		if riderID != nil {
 			log.Info().
-				Str("rider_id", chosen.ID.String()).
+				Str("rider_id", riderID.String()).
 				Str("order_id", orderID.String()).
-				Float64("distance_km", batchRiders[0].distance).
-				Msg("auto-assign: rider assigned")
+				Int("batch", batch+1).
+				Msg("auto-assign: rider accepted offer")
 			return nil
 		}
+	}
 
-		// In production, wait for acceptance; for now, simulate timeout
+	log.Warn().Str("order_id", orderID.String()).Msg("auto-assign: no rider accepted after all batches")
+	return nil
+}
+
+// awaitAcceptance polls the order until a rider has been assigned to it or
+// the timeout passes. It returns the accepting rider, or nil on timeout.
+func (s *AssignmentService) awaitAcceptance(ctx context.Context, orderID, tenantID uuid.UUID, timeout time.Duration) (*uuid.UUID, error) {
+	ticker := time.NewTicker(offerPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(time.Duration(batchTimeoutSec) * time.Second):
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, nil
+		case <-ticker.C:
+			order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID})
+			if err != nil {
+				log.Error().Err(err).Str("order_id", orderID.String()).Msg("auto-assign: reload order failed")
+				return nil, err
+			}
+			if order.RiderID.Valid {
+				riderID := uuid.UUID(order.RiderID.Bytes)
+				return &riderID, nil
+			}
 		}
 	}
-
-	log.Warn().Str("order_id", orderID.String()).Msg("auto-assign: no rider accepted after all batches")
-	return nil
 }
 
 func (s *AssignmentService) sendAssignmentOffer(ctx context.Context, rider sqlc.Rider, order sqlc.Order) {
@@ -164,6 +187,14 @@ func (s *AssignmentService) sendAssignmentOffer(ctx context.Context, rider sqlc.
 		Str("order_id", order.ID.String()).
 		Msg("auto-assign: sending assignment offer")
 
+	if err := s.q.CreateAssignmentOffer(ctx, sqlc.CreateAssignmentOfferParams{
+		TenantID: order.TenantID,
+		OrderID:  order.ID,
+		RiderID:  rider.ID,
+	}); err != nil {
+		log.Error().Err(err).Str("rider_id", rider.ID.String()).Msg("auto-assign: record offer failed")
+	}
+
 	if s.redis != nil {
 		channel := "rider:" + rider.ID.String() + ":assignment"
 		if err := s.redis.Publish(ctx, channel, string(offerData)); err != nil {
@@ -0,0 +1,613 @@
+package rider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/finance"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
+)
+
+// Cash deposit statuses.
+const (
+	DepositPending   = "pending"
+	DepositConfirmed = "confirmed"
+	DepositRejected  = "rejected"
+)
+
+// CashCollectionInput is what a rider reports collecting on a COD delivery.
+// A nil Collected means the full order total was collected.
+type CashCollectionInput struct {
+	Collected *decimal.Decimal
+	Note      string
+}
+
+// cashLimitExceeded reports whether a rider's cash in hand is above the hub
+// limit. An unset limit never blocks.
+func cashLimitExceeded(cashInHand decimal.Decimal, limit pgtype.Numeric) bool {
+	if !limit.Valid {
+		return false
+	}
+	return cashInHand.GreaterThan(numericToDecimal(limit))
+}
+
+// checkCashLimit refuses new orders for a rider holding more cash than their
+// hub allows.
+func checkCashLimit(ctx context.Context, q *sqlc.Queries, rider sqlc.Rider) error {
+	if !rider.HubID.Valid {
+		return nil
+	}
+	hub, err := q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: rider.HubID.Bytes, TenantID: rider.TenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return apperror.Internal("get hub", err)
+	}
+	cash := numericToDecimal(rider.CashInHand)
+	if cashLimitExceeded(cash, hub.RiderCashLimit) {
+		return apperror.Conflict(fmt.Sprintf("rider holds ৳%s in cash, above the hub limit of ৳%s; deposit at the hub before taking new orders",
+			cash.StringFixed(2), numericToDecimal(hub.RiderCashLimit).StringFixed(2)))
+	}
+	return nil
+}
+
+// postCashTransfer records cash moving from one ledger account to another.
+func postCashTransfer(ctx context.Context, q *sqlc.Queries, tenantID uuid.UUID, entryType sqlc.LedgerEntryType, from, to, refType string, refID uuid.UUID, amount decimal.Decimal, description string, metadata map[string]interface{}) error {
+	ledger := finance.NewLedgerService(q)
+	if _, err := ledger.Record(ctx, &tenantID, to, entryType, refType, refID, amount, decimal.Zero, description, metadata); err != nil {
+		return err
+	}
+	_, err := ledger.Record(ctx, &tenantID, from, entryType, refType, refID, decimal.Zero, amount, description, metadata)
+	return err
+}
+
+// recordCashCollection stores what the rider collected on a delivered COD
+// order, adds it to their cash in hand, records the payment and posts it to
+// the ledger. The order is marked paid once any cash was collected; a
+// shortfall shows up as a negative variance in the hub reconciliation. It runs
+// on the caller's transaction so the collection lands with the delivery.
+func recordCashCollection(ctx context.Context, qtx *sqlc.Queries, order sqlc.Order, riderID uuid.UUID, in CashCollectionInput) (sqlc.Order, error) {
+	expected := numericToDecimal(order.TotalAmount)
+	collected := expected
+	if in.Collected != nil {
+		collected = *in.Collected
+	}
+
+	rider, err := qtx.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: order.TenantID})
+	if err != nil {
+		return sqlc.Order{}, apperror.Internal("get rider", err)
+	}
+	hubID := rider.HubID
+	if !hubID.Valid {
+		hubID = order.HubID
+	}
+
+	collection, err := qtx.CreateCodCollection(ctx, sqlc.CreateCodCollectionParams{
+		TenantID:        order.TenantID,
+		OrderID:         order.ID,
+		RiderID:         riderID,
+		HubID:           hubID,
+		ExpectedAmount:  order.TotalAmount,
+		CollectedAmount: toPgNumeric(collected),
+		Note:            nullString(in.Note),
+	})
+	if err != nil {
+		return sqlc.Order{}, apperror.Internal("create cod collection", err)
+	}
+
+	if !collected.IsPositive() {
+		return order, nil
+	}
+
+	if err := qtx.AddRiderCashInHand(ctx, sqlc.AddRiderCashInHandParams{
+		Amount:   toPgNumeric(collected),
+		ID:       riderID,
+		TenantID: order.TenantID,
+	}); err != nil {
+		return sqlc.Order{}, apperror.Internal("add cash in hand", err)
+	}
+
+	if _, err := qtx.CreateTransaction(ctx, sqlc.CreateTransactionParams{
+		TenantID:        order.TenantID,
+		OrderID:         order.ID,
+		UserID:          order.CustomerID,
+		PaymentMethod:   sqlc.PaymentMethodCod,
+		Status:          sqlc.TxnStatusSuccess,
+		Amount:          toPgNumeric(collected),
+		Currency:        "BDT",
+		GatewayResponse: json.RawMessage("{}"),
+		GatewayFee:      toPgNumeric(decimal.Zero),
+	}); err != nil {
+		return sqlc.Order{}, apperror.Internal("create cod transaction", err)
+	}
+
+	paid, err := qtx.UpdateOrderPaymentStatus(ctx, sqlc.UpdateOrderPaymentStatusParams{
+		PaymentStatus: sqlc.PaymentStatusPaid,
+		ID:            order.ID,
+		TenantID:      order.TenantID,
+	})
+	if err != nil {
+		return sqlc.Order{}, apperror.Internal("update payment status", err)
+	}
+
+	if err := postCashTransfer(ctx, qtx, order.TenantID, sqlc.LedgerEntryTypeCodCollection,
+		finance.AccountCodReceivable, finance.AccountRiderCash, "order", order.ID, collected,
+		"COD collected for order "+order.OrderNumber,
+		map[string]interface{}{
+			"rider_id":      riderID,
+			"collection_id": collection.ID,
+			"expected":      expected.StringFixed(2),
+		}); err != nil {
+		return sqlc.Order{}, apperror.Internal("post cod collection", err)
+	}
+
+	return paid, nil
+}
+
+// ---------- Rider cash ----------
+
+// CashSummary is a rider's current cash position.
+type CashSummary struct {
+	CashInHand      decimal.Decimal         `json:"cash_in_hand"`
+	CashLimit       *decimal.Decimal        `json:"cash_limit"`
+	LimitExceeded   bool                    `json:"limit_exceeded"`
+	PendingDeposits decimal.Decimal         `json:"pending_deposits"`
+	Collections     []sqlc.CodCollection    `json:"collections"`
+	Deposits        []sqlc.RiderCashDeposit `json:"deposits"`
+}
+
+const cashSummaryHistory = 20
+
+// GetCashSummary returns the rider's cash in hand, hub limit and recent
+// collections and deposits.
+func (s *Service) GetCashSummary(ctx context.Context, rider sqlc.Rider) (CashSummary, error) {
+	summary := CashSummary{CashInHand: numericToDecimal(rider.CashInHand)}
+
+	if rider.HubID.Valid {
+		hub, err := s.q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: rider.HubID.Bytes, TenantID: rider.TenantID})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return CashSummary{}, apperror.Internal("get hub", err)
+		}
+		if err == nil && hub.RiderCashLimit.Valid {
+			limit := numericToDecimal(hub.RiderCashLimit)
+			summary.CashLimit = &limit
+			summary.LimitExceeded = cashLimitExceeded(summary.CashInHand, hub.RiderCashLimit)
+		}
+	}
+
+	pending, err := s.q.SumPendingRiderCashDeposits(ctx, sqlc.SumPendingRiderCashDepositsParams{RiderID: rider.ID, TenantID: rider.TenantID})
+	if err != nil {
+		return CashSummary{}, apperror.Internal("sum pending deposits", err)
+	}
+	summary.PendingDeposits = numericToDecimal(pending)
+
+	summary.Collections, err = s.q.ListCodCollectionsByRider(ctx, sqlc.ListCodCollectionsByRiderParams{
+		RiderID: rider.ID, TenantID: rider.TenantID, Limit: cashSummaryHistory,
+	})
+	if err != nil {
+		return CashSummary{}, apperror.Internal("list cod collections", err)
+	}
+	summary.Deposits, err = s.q.ListRiderCashDepositsByRider(ctx, sqlc.ListRiderCashDepositsByRiderParams{
+		RiderID: rider.ID, TenantID: rider.TenantID, Limit: cashSummaryHistory,
+	})
+	if err != nil {
+		return CashSummary{}, apperror.Internal("list cash deposits", err)
+	}
+	return summary, nil
+}
+
+// CashDepositInput is a deposit submitted by a rider.
+type CashDepositInput struct {
+	HubID     *uuid.UUID `json:"hub_id"`
+	Amount    string     `json:"amount"`
+	Reference string     `json:"reference"`
+	Note      string     `json:"note"`
+}
+
+// SubmitCashDeposit records cash a rider hands over at a hub. It stays pending
+// until the hub manager confirms it. Together with deposits still pending it
+// cannot exceed the rider's cash in hand.
+func (s *Service) SubmitCashDeposit(ctx context.Context, rider sqlc.Rider, in CashDepositInput) (sqlc.RiderCashDeposit, error) {
+	amount, err := decimal.NewFromString(in.Amount)
+	if err != nil || !amount.IsPositive() {
+		return sqlc.RiderCashDeposit{}, apperror.BadRequest("amount must be a positive number")
+	}
+	hubID := rider.HubID
+	if in.HubID != nil {
+		hubID = pgtype.UUID{Bytes: *in.HubID, Valid: true}
+	}
+	if !hubID.Valid {
+		return sqlc.RiderCashDeposit{}, apperror.BadRequest("hub_id is required")
+	}
+	if _, err := s.q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: hubID.Bytes, TenantID: rider.TenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.RiderCashDeposit{}, apperror.NotFound("hub")
+		}
+		return sqlc.RiderCashDeposit{}, apperror.Internal("get hub", err)
+	}
+
+	pending, err := s.q.SumPendingRiderCashDeposits(ctx, sqlc.SumPendingRiderCashDepositsParams{RiderID: rider.ID, TenantID: rider.TenantID})
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("sum pending deposits", err)
+	}
+	available := numericToDecimal(rider.CashInHand).Sub(numericToDecimal(pending))
+	if amount.GreaterThan(available) {
+		return sqlc.RiderCashDeposit{}, apperror.BadRequest("amount exceeds cash in hand not already pending deposit (৳" + available.StringFixed(2) + ")")
+	}
+
+	deposit, err := s.q.CreateRiderCashDeposit(ctx, sqlc.CreateRiderCashDepositParams{
+		TenantID:  rider.TenantID,
+		RiderID:   rider.ID,
+		HubID:     uuid.UUID(hubID.Bytes),
+		Amount:    toPgNumeric(amount),
+		Reference: nullString(in.Reference),
+		Note:      nullString(in.Note),
+	})
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("create cash deposit", err)
+	}
+	return deposit, nil
+}
+
+// ---------- Hub cash management ----------
+
+// authorizeHubManager allows tenant owners and admins, and the manager of the
+// given hub.
+func authorizeHubManager(ctx context.Context, q *sqlc.Queries, user *sqlc.User, tenantID, hubID uuid.UUID) error {
+	if user.Role == sqlc.UserRoleTenantOwner || user.Role == sqlc.UserRoleTenantAdmin {
+		return nil
+	}
+	hub, err := q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: hubID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperror.NotFound("hub")
+	}
+	if err != nil {
+		return apperror.Internal("get hub", err)
+	}
+	if !hub.ManagerID.Valid || uuid.UUID(hub.ManagerID.Bytes) != user.ID {
+		return apperror.Forbidden("only the hub manager can manage this hub")
+	}
+	return nil
+}
+
+// authorizeRiderManager allows the manager of the rider's hub, or a tenant
+// owner or admin for riders without a hub.
+func authorizeRiderManager(ctx context.Context, q *sqlc.Queries, user *sqlc.User, rider sqlc.Rider) error {
+	if rider.HubID.Valid {
+		return authorizeHubManager(ctx, q, user, rider.TenantID, uuid.UUID(rider.HubID.Bytes))
+	}
+	if user.Role != sqlc.UserRoleTenantOwner && user.Role != sqlc.UserRoleTenantAdmin {
+		return apperror.Forbidden("only tenant admins can manage this rider")
+	}
+	return nil
+}
+
+// hubScope resolves an optional hub filter for a partner listing. Users other
+// than tenant owners and admins must name a hub they manage.
+func hubScope(ctx context.Context, q *sqlc.Queries, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID) (pgtype.UUID, error) {
+	if hubID != nil {
+		if err := authorizeHubManager(ctx, q, user, tenantID, *hubID); err != nil {
+			return pgtype.UUID{}, err
+		}
+		return pgtype.UUID{Bytes: *hubID, Valid: true}, nil
+	}
+	if user.Role != sqlc.UserRoleTenantOwner && user.Role != sqlc.UserRoleTenantAdmin {
+		return pgtype.UUID{}, apperror.BadRequest("hub_id is required")
+	}
+	return pgtype.UUID{}, nil
+}
+
+// ListCashDeposits returns deposits, optionally for one hub and status. Users
+// other than tenant owners and admins must name a hub they manage.
+func (s *Service) ListCashDeposits(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, status string, limit, offset int32) ([]sqlc.ListRiderCashDepositsRow, error) {
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+
+	deposits, err := s.q.ListRiderCashDeposits(ctx, sqlc.ListRiderCashDepositsParams{
+		TenantID: tenantID,
+		HubID:    hub,
+		Status:   nullString(status),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list cash deposits", err)
+	}
+	return deposits, nil
+}
+
+// lockPendingDeposit locks a deposit that still awaits review.
+func lockPendingDeposit(ctx context.Context, q *sqlc.Queries, depositID, tenantID uuid.UUID) (sqlc.RiderCashDeposit, error) {
+	deposit, err := q.GetPendingRiderCashDepositForUpdate(ctx, sqlc.GetPendingRiderCashDepositForUpdateParams{ID: depositID, TenantID: tenantID})
+	if err == nil {
+		return deposit, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("lock cash deposit", err)
+	}
+	existing, err := q.GetRiderCashDeposit(ctx, sqlc.GetRiderCashDepositParams{ID: depositID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderCashDeposit{}, apperror.NotFound("cash deposit")
+	}
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("get cash deposit", err)
+	}
+	return sqlc.RiderCashDeposit{}, apperror.Conflict("cash deposit is already " + existing.Status)
+}
+
+// ConfirmCashDeposit is the hub manager acknowledging the cash was received.
+// The amount leaves the rider's cash in hand and moves to the hub in the
+// ledger.
+func (s *Service) ConfirmCashDeposit(ctx context.Context, depositID, tenantID uuid.UUID, user *sqlc.User) (sqlc.RiderCashDeposit, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	deposit, err := lockPendingDeposit(ctx, qtx, depositID, tenantID)
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, err
+	}
+	if err := authorizeHubManager(ctx, qtx, user, tenantID, deposit.HubID); err != nil {
+		return sqlc.RiderCashDeposit{}, err
+	}
+
+	rider, err := qtx.GetRiderForUpdate(ctx, sqlc.GetRiderForUpdateParams{ID: deposit.RiderID, TenantID: tenantID})
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("lock rider", err)
+	}
+	amount := numericToDecimal(deposit.Amount)
+	if cash := numericToDecimal(rider.CashInHand); amount.GreaterThan(cash) {
+		return sqlc.RiderCashDeposit{}, apperror.Conflict("deposit exceeds the rider's cash in hand of ৳" + cash.StringFixed(2))
+	}
+	if err := qtx.DeductRiderCashInHand(ctx, sqlc.DeductRiderCashInHandParams{
+		Amount: deposit.Amount, ID: rider.ID, TenantID: tenantID,
+	}); err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("deduct cash in hand", err)
+	}
+
+	confirmed, err := qtx.ReviewRiderCashDeposit(ctx, sqlc.ReviewRiderCashDepositParams{
+		Status:     DepositConfirmed,
+		ReviewedBy: pgtype.UUID{Bytes: user.ID, Valid: true},
+		ID:         depositID,
+		TenantID:   tenantID,
+	})
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("confirm cash deposit", err)
+	}
+
+	if err := postCashTransfer(ctx, qtx, tenantID, sqlc.LedgerEntryTypeCashDeposit,
+		finance.AccountRiderCash, finance.AccountHubCash, "rider_cash_deposit", deposit.ID, amount,
+		"Rider cash deposited at hub",
+		map[string]interface{}{
+			"rider_id":     rider.ID,
+			"hub_id":       deposit.HubID,
+			"confirmed_by": user.ID,
+		}); err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("post cash deposit", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("commit", err)
+	}
+	return confirmed, nil
+}
+
+// RejectCashDeposit marks a deposit the hub did not receive. The rider's cash
+// in hand is unchanged.
+func (s *Service) RejectCashDeposit(ctx context.Context, depositID, tenantID uuid.UUID, user *sqlc.User, reason string) (sqlc.RiderCashDeposit, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	deposit, err := lockPendingDeposit(ctx, qtx, depositID, tenantID)
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, err
+	}
+	if err := authorizeHubManager(ctx, qtx, user, tenantID, deposit.HubID); err != nil {
+		return sqlc.RiderCashDeposit{}, err
+	}
+
+	rejected, err := qtx.ReviewRiderCashDeposit(ctx, sqlc.ReviewRiderCashDepositParams{
+		Status:          DepositRejected,
+		ReviewedBy:      pgtype.UUID{Bytes: user.ID, Valid: true},
+		RejectionReason: nullString(reason),
+		ID:              depositID,
+		TenantID:        tenantID,
+	})
+	if err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("reject cash deposit", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderCashDeposit{}, apperror.Internal("commit", err)
+	}
+	return rejected, nil
+}
+
+// ---------- Daily reconciliation ----------
+
+// RiderCashReconciliation is one rider's line in a hub's daily cash report.
+type RiderCashReconciliation struct {
+	RiderID         uuid.UUID       `json:"rider_id"`
+	RiderName       string          `json:"rider_name"`
+	Orders          int64           `json:"orders"`
+	Expected        decimal.Decimal `json:"expected"`
+	Collected       decimal.Decimal `json:"collected"`
+	Variance        decimal.Decimal `json:"variance"`
+	Deposited       decimal.Decimal `json:"deposited"`
+	PendingDeposits decimal.Decimal `json:"pending_deposits"`
+	CashInHand      decimal.Decimal `json:"cash_in_hand"`
+}
+
+// HubCashReconciliation is a hub's COD cash position for one business day:
+// what riders should have collected, what they collected, what reached the
+// hub and what is still out with riders.
+type HubCashReconciliation struct {
+	HubID           *uuid.UUID                `json:"hub_id"`
+	HubName         string                    `json:"hub_name"`
+	Orders          int64                     `json:"orders"`
+	Expected        decimal.Decimal           `json:"expected"`
+	Collected       decimal.Decimal           `json:"collected"`
+	Variance        decimal.Decimal           `json:"variance"`
+	Deposited       decimal.Decimal           `json:"deposited"`
+	PendingDeposits decimal.Decimal           `json:"pending_deposits"`
+	CashWithRiders  decimal.Decimal           `json:"cash_with_riders"`
+	Riders          []RiderCashReconciliation `json:"riders"`
+}
+
+// CashReconciliationReport is the daily cash report across hubs.
+type CashReconciliationReport struct {
+	Date string                  `json:"date"`
+	Hubs []HubCashReconciliation `json:"hubs"`
+}
+
+// buildCashReconciliation groups the day's collections and deposits and the
+// riders' current balances by hub. Riders with no activity and no cash are
+// left out.
+func buildCashReconciliation(collections []sqlc.SummarizeCodCollectionsRow, deposits []sqlc.SummarizeRiderCashDepositsRow, balances []sqlc.ListRiderCashBalancesRow, hubNames map[uuid.UUID]string) []HubCashReconciliation {
+	type key struct{ hub, rider uuid.UUID }
+	lines := map[key]*RiderCashReconciliation{}
+	names := map[uuid.UUID]string{}
+	for _, b := range balances {
+		names[b.ID] = b.RiderName
+	}
+	line := func(hub, rider uuid.UUID) *RiderCashReconciliation {
+		k := key{hub, rider}
+		if l, ok := lines[k]; ok {
+			return l
+		}
+		l := &RiderCashReconciliation{
+			RiderID:         rider,
+			RiderName:       names[rider],
+			Expected:        decimal.Zero,
+			Collected:       decimal.Zero,
+			Variance:        decimal.Zero,
+			Deposited:       decimal.Zero,
+			PendingDeposits: decimal.Zero,
+			CashInHand:      decimal.Zero,
+		}
+		lines[k] = l
+		return l
+	}
+
+	for _, c := range collections {
+		l := line(uuid.UUID(c.HubID.Bytes), c.RiderID)
+		l.Orders += c.Orders
+		l.Expected = l.Expected.Add(numericToDecimal(c.ExpectedAmount))
+		l.Collected = l.Collected.Add(numericToDecimal(c.CollectedAmount))
+		l.Variance = l.Collected.Sub(l.Expected)
+	}
+	for _, d := range deposits {
+		l := line(d.HubID, d.RiderID)
+		l.Deposited = l.Deposited.Add(numericToDecimal(d.ConfirmedAmount))
+		l.PendingDeposits = l.PendingDeposits.Add(numericToDecimal(d.PendingAmount))
+	}
+	for _, b := range balances {
+		cash := numericToDecimal(b.CashInHand)
+		k := key{uuid.UUID(b.HubID.Bytes), b.ID}
+		if _, ok := lines[k]; !ok && cash.IsZero() {
+			continue
+		}
+		line(k.hub, k.rider).CashInHand = cash
+	}
+
+	hubs := map[uuid.UUID]*HubCashReconciliation{}
+	for k, l := range lines {
+		h, ok := hubs[k.hub]
+		if !ok {
+			h = &HubCashReconciliation{
+				HubName:         hubNames[k.hub],
+				Expected:        decimal.Zero,
+				Collected:       decimal.Zero,
+				Variance:        decimal.Zero,
+				Deposited:       decimal.Zero,
+				PendingDeposits: decimal.Zero,
+				CashWithRiders:  decimal.Zero,
+			}
+			if k.hub != uuid.Nil {
+				id := k.hub
+				h.HubID = &id
+			}
+			hubs[k.hub] = h
+		}
+		h.Orders += l.Orders
+		h.Expected = h.Expected.Add(l.Expected)
+		h.Collected = h.Collected.Add(l.Collected)
+		h.Variance = h.Variance.Add(l.Variance)
+		h.Deposited = h.Deposited.Add(l.Deposited)
+		h.PendingDeposits = h.PendingDeposits.Add(l.PendingDeposits)
+		h.CashWithRiders = h.CashWithRiders.Add(l.CashInHand)
+		h.Riders = append(h.Riders, *l)
+	}
+
+	out := make([]HubCashReconciliation, 0, len(hubs))
+	for _, h := range hubs {
+		sort.Slice(h.Riders, func(i, j int) bool { return h.Riders[i].RiderName < h.Riders[j].RiderName })
+		out = append(out, *h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].HubName < out[j].HubName })
+	return out
+}
+
+// GetCashReconciliation builds the cash report for a Dhaka business day,
+// optionally for one hub. Hub managers may only see the hubs they manage.
+func (s *Service) GetCashReconciliation(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, day time.Time, hubID *uuid.UUID) (CashReconciliationReport, error) {
+	hubFilter, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return CashReconciliationReport{}, err
+	}
+
+	from := timeutil.StartOfDayBD(day)
+	to := from.AddDate(0, 0, 1)
+
+	collections, err := s.q.SummarizeCodCollections(ctx, sqlc.SummarizeCodCollectionsParams{
+		TenantID: tenantID, FromTime: from, ToTime: to, HubID: hubFilter,
+	})
+	if err != nil {
+		return CashReconciliationReport{}, apperror.Internal("summarize cod collections", err)
+	}
+	deposits, err := s.q.SummarizeRiderCashDeposits(ctx, sqlc.SummarizeRiderCashDepositsParams{
+		TenantID: tenantID,
+		HubID:    hubFilter,
+		FromTime: pgtype.Timestamptz{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return CashReconciliationReport{}, apperror.Internal("summarize cash deposits", err)
+	}
+	balances, err := s.q.ListRiderCashBalances(ctx, sqlc.ListRiderCashBalancesParams{TenantID: tenantID, HubID: hubFilter})
+	if err != nil {
+		return CashReconciliationReport{}, apperror.Internal("list rider cash balances", err)
+	}
+	hubs, err := s.q.ListHubsByTenant(ctx, tenantID)
+	if err != nil {
+		return CashReconciliationReport{}, apperror.Internal("list hubs", err)
+	}
+	hubNames := make(map[uuid.UUID]string, len(hubs))
+	for _, h := range hubs {
+		hubNames[h.ID] = h.Name
+	}
+
+	return CashReconciliationReport{
+		Date: timeutil.FormatBD(from, "2006-01-02"),
+		Hubs: buildCashReconciliation(collections, deposits, balances, hubNames),
+	}, nil
+}
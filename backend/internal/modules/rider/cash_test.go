@@ -0,0 +1,61 @@
+package rider
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestCashLimitExceeded(t *testing.T) {
+	limit := toPgNumeric(dec("5000"))
+
+	if cashLimitExceeded(dec("5000"), limit) {
+		t.Error("cash equal to the limit should not block")
+	}
+	if !cashLimitExceeded(dec("5000.01"), limit) {
+		t.Error("cash above the limit should block")
+	}
+	if cashLimitExceeded(dec("999999"), pgtype.Numeric{}) {
+		t.Error("an unset limit should never block")
+	}
+}
+
+func TestBuildCashReconciliation_GroupsByHubAndRider(t *testing.T) {
+	hubA, hubB := uuid.New(), uuid.New()
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	pgHub := func(id uuid.UUID) pgtype.UUID { return pgtype.UUID{Bytes: id, Valid: true} }
+
+	collections := []sqlc.SummarizeCodCollectionsRow{
+		{HubID: pgHub(hubA), RiderID: alice, Orders: 3, ExpectedAmount: toPgNumeric(dec("1500")), CollectedAmount: toPgNumeric(dec("1450"))},
+		{HubID: pgHub(hubA), RiderID: bob, Orders: 1, ExpectedAmount: toPgNumeric(dec("400")), CollectedAmount: toPgNumeric(dec("400"))},
+	}
+	deposits := []sqlc.SummarizeRiderCashDepositsRow{
+		{HubID: hubA, RiderID: alice, ConfirmedAmount: toPgNumeric(dec("1000")), PendingAmount: toPgNumeric(dec("200"))},
+	}
+	balances := []sqlc.ListRiderCashBalancesRow{
+		{ID: alice, HubID: pgHub(hubA), CashInHand: toPgNumeric(dec("450")), RiderName: "Alice"},
+		{ID: bob, HubID: pgHub(hubA), CashInHand: toPgNumeric(dec("400")), RiderName: "Bob"},
+		{ID: carol, HubID: pgHub(hubB), CashInHand: toPgNumeric(dec("0")), RiderName: "Carol"},
+	}
+
+	hubs := buildCashReconciliation(collections, deposits, balances, map[uuid.UUID]string{hubA: "Gulshan", hubB: "Banani"})
+	if len(hubs) != 1 {
+		t.Fatalf("got %d hubs, want only the hub with activity", len(hubs))
+	}
+
+	h := hubs[0]
+	if h.HubName != "Gulshan" || h.Orders != 4 || len(h.Riders) != 2 {
+		t.Fatalf("hub = %s with %d orders and %d riders, want Gulshan with 4 and 2", h.HubName, h.Orders, len(h.Riders))
+	}
+	if !h.Expected.Equal(dec("1900")) || !h.Collected.Equal(dec("1850")) || !h.Variance.Equal(dec("-50")) {
+		t.Errorf("expected/collected/variance = %s/%s/%s, want 1900/1850/-50", h.Expected, h.Collected, h.Variance)
+	}
+	if !h.Deposited.Equal(dec("1000")) || !h.PendingDeposits.Equal(dec("200")) || !h.CashWithRiders.Equal(dec("850")) {
+		t.Errorf("deposited/pending/with riders = %s/%s/%s, want 1000/200/850", h.Deposited, h.PendingDeposits, h.CashWithRiders)
+	}
+	if h.Riders[0].RiderName != "Alice" || !h.Riders[0].Variance.Equal(dec("-50")) {
+		t.Errorf("first rider = %s with variance %s, want Alice with -50", h.Riders[0].RiderName, h.Riders[0].Variance)
+	}
+}
@@ -0,0 +1,759 @@
+package rider
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
+)
+
+// Surge kinds.
+const (
+	SurgeRain  = "rain"
+	SurgeSurge = "surge"
+)
+
+// Where the distance on an earning came from.
+const (
+	distanceFromTrip     = "location_history"
+	distanceStraightLine = "straight_line"
+	// distanceCapped marks a detour paid only up to the longest reasonable
+	// ride.
+	distanceCapped = "capped_detour"
+)
+
+// EarningRule is the pay scheme applied to a delivery.
+type EarningRule struct {
+	ID               *uuid.UUID
+	BasePay          decimal.Decimal
+	PerKmRate        decimal.Decimal
+	IncludedKm       decimal.Decimal
+	MaxDistancePay   *decimal.Decimal
+	ExtraPickupBonus decimal.Decimal
+	PeakWindows      []PeakWindow
+}
+
+// PeakWindow pays a flat bonus for deliveries completed inside it. Minutes are
+// counted from midnight Asia/Dhaka; a nil DayOfWeek matches every day.
+type PeakWindow struct {
+	DayOfWeek   *int32          `json:"day_of_week,omitempty"`
+	StartMinute int32           `json:"start_minute"`
+	EndMinute   int32           `json:"end_minute"`
+	Bonus       decimal.Decimal `json:"bonus"`
+}
+
+// EarningInput is what a single delivery contributes to the calculation.
+type EarningInput struct {
+	DistanceKm      decimal.Decimal
+	Pickups         int
+	At              time.Time
+	RainMultiplier  decimal.Decimal
+	SurgeMultiplier decimal.Decimal
+	Tip             decimal.Decimal
+}
+
+// EarningBreakdown records every component of an earning so riders and ops
+// can audit how the total was reached.
+type EarningBreakdown struct {
+	RuleID          *uuid.UUID      `json:"rule_id,omitempty"`
+	DistanceKm      decimal.Decimal `json:"distance_km"`
+	DistanceSource  string          `json:"distance_source,omitempty"`
+	TrailKm         *float64        `json:"trail_km,omitempty"`
+	RouteKm         *float64        `json:"route_km,omitempty"`
+	Detour          bool            `json:"detour,omitempty"`
+	TripStart       *time.Time      `json:"trip_start,omitempty"`
+	TripEnd         *time.Time      `json:"trip_end,omitempty"`
+	Pickups         int             `json:"pickups"`
+	Base            decimal.Decimal `json:"base"`
+	Distance        decimal.Decimal `json:"distance"`
+	MultiPickup     decimal.Decimal `json:"multi_pickup"`
+	Peak            decimal.Decimal `json:"peak"`
+	PeakWindow      *PeakWindow     `json:"peak_window,omitempty"`
+	Subtotal        decimal.Decimal `json:"subtotal"`
+	RainMultiplier  decimal.Decimal `json:"rain_multiplier"`
+	SurgeMultiplier decimal.Decimal `json:"surge_multiplier"`
+	Multiplier      decimal.Decimal `json:"multiplier"`
+	SurgeBonus      decimal.Decimal `json:"surge_bonus"`
+	Tip             decimal.Decimal `json:"tip"`
+	Total           decimal.Decimal `json:"total"`
+}
+
+// defaultEarningRule is used when a tenant has not configured any rule. It
+// matches the flat scheme riders were paid before rules existed.
+func defaultEarningRule() EarningRule {
+	return EarningRule{
+		BasePay:          decimal.NewFromInt(50),
+		PerKmRate:        decimal.NewFromInt(5),
+		IncludedKm:       decimal.Zero,
+		ExtraPickupBonus: decimal.Zero,
+		PeakWindows: []PeakWindow{
+			{StartMinute: 12 * 60, EndMinute: 14 * 60, Bonus: decimal.NewFromInt(20)},
+			{StartMinute: 18 * 60, EndMinute: 21 * 60, Bonus: decimal.NewFromInt(20)},
+		},
+	}
+}
+
+// computeEarning applies a rule to a delivery. Distance beyond the included
+// kilometres is paid per km (capped when the rule says so), every pickup after
+// the first earns the multi-pickup bonus and the best matching peak window is
+// added. Rain and surge multipliers compound on that subtotal; the tip is added
+// last and is never multiplied.
+func computeEarning(rule EarningRule, in EarningInput) EarningBreakdown {
+	b := EarningBreakdown{
+		RuleID:          rule.ID,
+		DistanceKm:      in.DistanceKm.Round(3),
+		Pickups:         in.Pickups,
+		Base:            rule.BasePay,
+		Distance:        decimal.Zero,
+		MultiPickup:     decimal.Zero,
+		Peak:            decimal.Zero,
+		RainMultiplier:  atLeastOne(in.RainMultiplier),
+		SurgeMultiplier: atLeastOne(in.SurgeMultiplier),
+		Tip:             in.Tip,
+	}
+
+	if paidKm := in.DistanceKm.Sub(rule.IncludedKm); paidKm.IsPositive() {
+		b.Distance = paidKm.Mul(rule.PerKmRate).Round(2)
+		if rule.MaxDistancePay != nil && b.Distance.GreaterThan(*rule.MaxDistancePay) {
+			b.Distance = *rule.MaxDistancePay
+		}
+	}
+
+	if in.Pickups > 1 {
+		b.MultiPickup = rule.ExtraPickupBonus.Mul(decimal.NewFromInt(int64(in.Pickups - 1)))
+	}
+
+	at := timeutil.ToBD(in.At)
+	minute := int32(at.Hour()*60 + at.Minute())
+	day := int32(at.Weekday())
+	for i := range rule.PeakWindows {
+		w := rule.PeakWindows[i]
+		if w.DayOfWeek != nil && *w.DayOfWeek != day {
+			continue
+		}
+		if minute < w.StartMinute || minute >= w.EndMinute {
+			continue
+		}
+		if b.PeakWindow == nil || w.Bonus.GreaterThan(b.Peak) {
+			b.Peak = w.Bonus
+			b.PeakWindow = &w
+		}
+	}
+
+	b.Subtotal = b.Base.Add(b.Distance).Add(b.MultiPickup).Add(b.Peak)
+	b.Multiplier = b.RainMultiplier.Mul(b.SurgeMultiplier)
+	b.SurgeBonus = b.Subtotal.Mul(b.Multiplier.Sub(decimal.NewFromInt(1))).Round(2)
+	b.Total = b.Subtotal.Add(b.SurgeBonus).Add(b.Tip)
+	return b
+}
+
+func atLeastOne(d decimal.Decimal) decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	if d.LessThan(one) {
+		return one
+	}
+	return d
+}
+
+// activeMultipliers picks the highest rain and the highest surge multiplier
+// among the surges running for a delivery.
+func activeMultipliers(surges []sqlc.RiderEarningSurge) (rain, surge decimal.Decimal) {
+	rain, surge = decimal.NewFromInt(1), decimal.NewFromInt(1)
+	for _, s := range surges {
+		m := numericToDecimal(s.Multiplier)
+		switch s.Kind {
+		case SurgeRain:
+			rain = decimal.Max(rain, m)
+		case SurgeSurge:
+			surge = decimal.Max(surge, m)
+		}
+	}
+	return rain, surge
+}
+
+func ruleFromRow(r sqlc.RiderEarningRule, windows []sqlc.RiderEarningPeakWindow) EarningRule {
+	id := r.ID
+	rule := EarningRule{
+		ID:               &id,
+		BasePay:          numericToDecimal(r.BasePay),
+		PerKmRate:        numericToDecimal(r.PerKmRate),
+		IncludedKm:       numericToDecimal(r.IncludedKm),
+		ExtraPickupBonus: numericToDecimal(r.ExtraPickupBonus),
+	}
+	if r.MaxDistancePay.Valid {
+		max := numericToDecimal(r.MaxDistancePay)
+		rule.MaxDistancePay = &max
+	}
+	for _, w := range windows {
+		rule.PeakWindows = append(rule.PeakWindows, PeakWindow{
+			DayOfWeek:   w.DayOfWeek,
+			StartMinute: w.StartMinute,
+			EndMinute:   w.EndMinute,
+			Bonus:       numericToDecimal(w.BonusAmount),
+		})
+	}
+	return rule
+}
+
+// loadEarningRule returns the most specific active rule for the hub and
+// vehicle, falling back to the default scheme.
+func loadEarningRule(ctx context.Context, q *sqlc.Queries, tenantID uuid.UUID, hubID pgtype.UUID, vehicle sqlc.VehicleType) (EarningRule, error) {
+	row, err := q.GetApplicableEarningRule(ctx, sqlc.GetApplicableEarningRuleParams{
+		TenantID:    tenantID,
+		HubID:       hubID,
+		VehicleType: sqlc.NullVehicleType{VehicleType: vehicle, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return defaultEarningRule(), nil
+	}
+	if err != nil {
+		return EarningRule{}, err
+	}
+	windows, err := q.ListEarningPeakWindows(ctx, row.ID)
+	if err != nil {
+		return EarningRule{}, err
+	}
+	return ruleFromRow(row, windows), nil
+}
+
+// recordEarning computes and saves a rider earning for an order, on the
+// delivery's transaction.
+// The distance is the trail ridden with the order on board, checked against
+// the straight-line route from the restaurants to the drop: a sparse trail is
+// raised to the route and a detour is capped (see verifyTripDistance).
+func recordEarning(ctx context.Context, q *sqlc.Queries, riderID, tenantID, orderID uuid.UUID) error {
+	rider, err := q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: tenantID})
+	if err != nil {
+		return apperror.Internal("get rider", err)
+	}
+	order, err := q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID})
+	if err != nil {
+		return apperror.Internal("get order", err)
+	}
+	pickups, err := q.ListPickupsByOrder(ctx, sqlc.ListPickupsByOrderParams{OrderID: orderID, TenantID: tenantID})
+	if err != nil {
+		return apperror.Internal("list pickups", err)
+	}
+
+	deliveredAt := time.Now()
+	if order.DeliveredAt.Valid {
+		deliveredAt = order.DeliveredAt.Time
+	}
+
+	var (
+		pickupCount int
+		tripStart   time.Time
+		picked      []sqlc.OrderPickup
+	)
+	for _, p := range pickups {
+		if p.Status == sqlc.PickupStatusRejected {
+			continue
+		}
+		pickupCount++
+		if !p.PickedAt.Valid {
+			continue
+		}
+		if tripStart.IsZero() || p.PickedAt.Time.Before(tripStart) {
+			tripStart = p.PickedAt.Time
+		}
+		picked = append(picked, p)
+	}
+	if pickupCount == 0 {
+		pickupCount = 1
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i].PickedAt.Time.Before(picked[j].PickedAt.Time) })
+
+	var (
+		trailKm  float64
+		hasTrail bool
+	)
+	if !tripStart.IsZero() {
+		history, err := q.ListLocationHistoryBetween(ctx, sqlc.ListLocationHistoryBetweenParams{
+			RiderID:  riderID,
+			TenantID: tenantID,
+			FromTime: tripStart.Add(-tripEventMargin),
+			ToTime:   deliveredAt.Add(tripEventMargin),
+			Limit:    travelLogMaxPoints,
+		})
+		if err != nil {
+			return apperror.Internal("list trip history", err)
+		}
+		points := trackPointsFromHistory(history)
+		legs, _ := reconstructTravel(points)
+		trailKm, hasTrail = orderTrailKm(legs, orderID)
+		if !hasTrail {
+			trailKm, hasTrail = windowTrailKm(points, orderID, tripStart, deliveredAt)
+		}
+	}
+
+	var route []geoStop
+	for _, p := range picked {
+		restaurant, err := q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: p.RestaurantID, TenantID: tenantID})
+		if err != nil {
+			continue
+		}
+		lat, ok1 := numericToFloat64(restaurant.GeoLat)
+		lng, ok2 := numericToFloat64(restaurant.GeoLng)
+		if ok1 && ok2 {
+			route = append(route, geoStop{Lat: lat, Lng: lng})
+		}
+	}
+	dLat, ok1 := numericToFloat64(order.DeliveryGeoLat)
+	dLng, ok2 := numericToFloat64(order.DeliveryGeoLng)
+	hasRoute := len(route) > 0 && ok1 && ok2
+	var plannedKm float64
+	if hasRoute {
+		plannedKm = routeKm(append(route, geoStop{Lat: dLat, Lng: dLng}))
+	}
+
+	verifiedKm, source, detour := verifyTripDistance(trailKm, hasTrail, plannedKm, hasRoute)
+	distanceKm := decimal.NewFromFloat(verifiedKm)
+
+	hubID := order.HubID
+	if !hubID.Valid {
+		hubID = rider.HubID
+	}
+	rule, err := loadEarningRule(ctx, q, tenantID, hubID, rider.VehicleType)
+	if err != nil {
+		return apperror.Internal("load earning rule", err)
+	}
+	surges, err := q.ListActiveEarningSurges(ctx, sqlc.ListActiveEarningSurgesParams{
+		TenantID: tenantID, HubID: hubID, At: deliveredAt,
+	})
+	if err != nil {
+		return apperror.Internal("list surges", err)
+	}
+	rain, surge := activeMultipliers(surges)
+
+	b := computeEarning(rule, EarningInput{
+		DistanceKm:      distanceKm,
+		Pickups:         pickupCount,
+		At:              deliveredAt,
+		RainMultiplier:  rain,
+		SurgeMultiplier: surge,
+		Tip:             numericToDecimal(order.RiderTip),
+	})
+	b.DistanceSource = source
+	b.Detour = detour
+	if hasTrail {
+		b.TrailKm = &trailKm
+	}
+	if hasRoute {
+		b.RouteKm = &plannedKm
+	}
+	if !tripStart.IsZero() {
+		b.TripStart = &tripStart
+		b.TripEnd = &deliveredAt
+	}
+	breakdown, _ := json.Marshal(b)
+
+	var ruleID pgtype.UUID
+	if rule.ID != nil {
+		ruleID = pgtype.UUID{Bytes: *rule.ID, Valid: true}
+	}
+
+	earning, err := q.CreateRiderEarning(ctx, sqlc.CreateRiderEarningParams{
+		RiderID:          riderID,
+		TenantID:         tenantID,
+		OrderID:          orderID,
+		BaseEarning:      toPgNumeric(b.Base),
+		DistanceBonus:    toPgNumeric(b.Distance),
+		PeakBonus:        toPgNumeric(b.Peak),
+		TipAmount:        toPgNumeric(b.Tip),
+		TotalEarning:     toPgNumeric(b.Total),
+		RuleID:           ruleID,
+		VehicleType:      sqlc.NullVehicleType{VehicleType: rider.VehicleType, Valid: true},
+		DistanceKm:       toPgNumeric(b.DistanceKm),
+		PickupCount:      int32(b.Pickups),
+		MultiPickupBonus: toPgNumeric(b.MultiPickup),
+		SurgeMultiplier:  toPgNumeric(b.Multiplier),
+		SurgeBonus:       toPgNumeric(b.SurgeBonus),
+		Breakdown:        breakdown,
+	})
+	if isUniqueViolation(err) {
+		return apperror.Conflict("earning already recorded for this order")
+	}
+	if err != nil {
+		return apperror.Internal("create earning", err)
+	}
+
+	// Update rider stats
+	err = q.UpdateRiderStats(ctx, sqlc.UpdateRiderStatsParams{
+		ID:             riderID,
+		TenantID:       tenantID,
+		TotalEarnings:  earning.TotalEarning,
+		PendingBalance: earning.TotalEarning,
+	})
+	if err != nil {
+		return apperror.Internal("update rider stats", err)
+	}
+
+	return nil
+}
+
+// ---------- Earning rules ----------
+
+// PeakWindowInput is a peak window as submitted by a partner.
+type PeakWindowInput struct {
+	DayOfWeek   *int32 `json:"day_of_week"`
+	StartMinute int32  `json:"start_minute"`
+	EndMinute   int32  `json:"end_minute"`
+	Bonus       string `json:"bonus"`
+}
+
+// EarningRuleInput holds the fields of an earning rule. On update, nil fields
+// are left unchanged; a non-nil PeakWindows replaces the existing windows.
+type EarningRuleInput struct {
+	HubID            *uuid.UUID         `json:"hub_id"`
+	VehicleType      *string            `json:"vehicle_type"`
+	Name             *string            `json:"name"`
+	BasePay          *string            `json:"base_pay"`
+	PerKmRate        *string            `json:"per_km_rate"`
+	IncludedKm       *string            `json:"included_km"`
+	MaxDistancePay   *string            `json:"max_distance_pay"`
+	ExtraPickupBonus *string            `json:"extra_pickup_bonus"`
+	IsActive         *bool              `json:"is_active"`
+	PeakWindows      *[]PeakWindowInput `json:"peak_windows"`
+}
+
+// EarningRuleDetail is a rule together with its peak windows.
+type EarningRuleDetail struct {
+	sqlc.RiderEarningRule
+	PeakWindows []sqlc.RiderEarningPeakWindow `json:"peak_windows"`
+}
+
+func parseAmount(field string, v *string) (pgtype.Numeric, error) {
+	if v == nil {
+		return pgtype.Numeric{}, nil
+	}
+	d, err := decimal.NewFromString(*v)
+	if err != nil || d.IsNegative() {
+		return pgtype.Numeric{}, apperror.BadRequest(field + " must be a non-negative amount")
+	}
+	return toPgNumeric(d), nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func validatePeakWindows(windows []PeakWindowInput) error {
+	for _, w := range windows {
+		if w.DayOfWeek != nil && (*w.DayOfWeek < 0 || *w.DayOfWeek > 6) {
+			return apperror.BadRequest("day_of_week must be between 0 (Sunday) and 6")
+		}
+		if w.StartMinute < 0 || w.EndMinute > 24*60 || w.StartMinute >= w.EndMinute {
+			return apperror.BadRequest("peak window must satisfy 0 <= start_minute < end_minute <= 1440")
+		}
+		if _, err := parseAmount("bonus", &w.Bonus); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replacePeakWindows(ctx context.Context, q *sqlc.Queries, ruleID uuid.UUID, windows []PeakWindowInput) ([]sqlc.RiderEarningPeakWindow, error) {
+	if err := q.RemoveEarningPeakWindows(ctx, ruleID); err != nil {
+		return nil, err
+	}
+	out := make([]sqlc.RiderEarningPeakWindow, 0, len(windows))
+	for _, w := range windows {
+		bonus, _ := parseAmount("bonus", &w.Bonus)
+		row, err := q.CreateEarningPeakWindow(ctx, sqlc.CreateEarningPeakWindowParams{
+			RuleID:      ruleID,
+			DayOfWeek:   w.DayOfWeek,
+			StartMinute: w.StartMinute,
+			EndMinute:   w.EndMinute,
+			BonusAmount: bonus,
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// CreateEarningRule adds an earning rule scoped to the tenant, optionally
+// narrowed to a hub and/or vehicle type.
+func (s *Service) CreateEarningRule(ctx context.Context, tenantID, actorID uuid.UUID, in EarningRuleInput) (EarningRuleDetail, error) {
+	if in.Name == nil || *in.Name == "" {
+		return EarningRuleDetail{}, apperror.BadRequest("name is required")
+	}
+	if in.BasePay == nil {
+		return EarningRuleDetail{}, apperror.BadRequest("base_pay is required")
+	}
+	params := sqlc.CreateEarningRuleParams{
+		TenantID:  tenantID,
+		Name:      *in.Name,
+		IsActive:  in.IsActive == nil || *in.IsActive,
+		CreatedBy: pgtype.UUID{Bytes: actorID, Valid: true},
+	}
+	if in.HubID != nil {
+		if err := s.checkHub(ctx, *in.HubID, tenantID); err != nil {
+			return EarningRuleDetail{}, err
+		}
+		params.HubID = pgtype.UUID{Bytes: *in.HubID, Valid: true}
+	}
+	if in.VehicleType != nil {
+		vt := sqlc.VehicleType(*in.VehicleType)
+		switch vt {
+		case sqlc.VehicleTypeBicycle, sqlc.VehicleTypeMotorcycle, sqlc.VehicleTypeCar:
+		default:
+			return EarningRuleDetail{}, apperror.BadRequest("vehicle_type must be bicycle, motorcycle or car")
+		}
+		params.VehicleType = sqlc.NullVehicleType{VehicleType: vt, Valid: true}
+	}
+	zero := "0"
+	for _, f := range []struct {
+		name string
+		v    *string
+		dst  *pgtype.Numeric
+		def  *string
+	}{
+		{"base_pay", in.BasePay, &params.BasePay, nil},
+		{"per_km_rate", in.PerKmRate, &params.PerKmRate, &zero},
+		{"included_km", in.IncludedKm, &params.IncludedKm, &zero},
+		{"max_distance_pay", in.MaxDistancePay, &params.MaxDistancePay, nil},
+		{"extra_pickup_bonus", in.ExtraPickupBonus, &params.ExtraPickupBonus, &zero},
+	} {
+		v := f.v
+		if v == nil {
+			v = f.def
+		}
+		n, err := parseAmount(f.name, v)
+		if err != nil {
+			return EarningRuleDetail{}, err
+		}
+		*f.dst = n
+	}
+	var windows []PeakWindowInput
+	if in.PeakWindows != nil {
+		windows = *in.PeakWindows
+	}
+	if err := validatePeakWindows(windows); err != nil {
+		return EarningRuleDetail{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return EarningRuleDetail{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	rule, err := qtx.CreateEarningRule(ctx, params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return EarningRuleDetail{}, apperror.Conflict("an earning rule already exists for this hub and vehicle type")
+		}
+		return EarningRuleDetail{}, apperror.Internal("create earning rule", err)
+	}
+	created, err := replacePeakWindows(ctx, qtx, rule.ID, windows)
+	if err != nil {
+		return EarningRuleDetail{}, apperror.Internal("create peak windows", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return EarningRuleDetail{}, apperror.Internal("commit", err)
+	}
+	return EarningRuleDetail{RiderEarningRule: rule, PeakWindows: created}, nil
+}
+
+// checkHub returns NotFound unless the hub belongs to the tenant.
+func (s *Service) checkHub(ctx context.Context, hubID, tenantID uuid.UUID) error {
+	if _, err := s.q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: hubID, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.NotFound("hub")
+		}
+		return apperror.Internal("get hub", err)
+	}
+	return nil
+}
+
+// ListEarningRules returns every earning rule of the tenant with its windows.
+func (s *Service) ListEarningRules(ctx context.Context, tenantID uuid.UUID) ([]EarningRuleDetail, error) {
+	rules, err := s.q.ListEarningRules(ctx, tenantID)
+	if err != nil {
+		return nil, apperror.Internal("list earning rules", err)
+	}
+	out := make([]EarningRuleDetail, 0, len(rules))
+	for _, r := range rules {
+		windows, err := s.q.ListEarningPeakWindows(ctx, r.ID)
+		if err != nil {
+			return nil, apperror.Internal("list peak windows", err)
+		}
+		out = append(out, EarningRuleDetail{RiderEarningRule: r, PeakWindows: windows})
+	}
+	return out, nil
+}
+
+// UpdateEarningRule changes an earning rule. The hub and vehicle scope are
+// fixed once created.
+func (s *Service) UpdateEarningRule(ctx context.Context, id, tenantID uuid.UUID, in EarningRuleInput) (EarningRuleDetail, error) {
+	params := sqlc.UpdateEarningRuleParams{ID: id, TenantID: tenantID, IsActive: in.IsActive}
+	if in.Name != nil {
+		if *in.Name == "" {
+			return EarningRuleDetail{}, apperror.BadRequest("name cannot be empty")
+		}
+		params.Name = sql.NullString{String: *in.Name, Valid: true}
+	}
+	for _, f := range []struct {
+		name string
+		v    *string
+		dst  *pgtype.Numeric
+	}{
+		{"base_pay", in.BasePay, &params.BasePay},
+		{"per_km_rate", in.PerKmRate, &params.PerKmRate},
+		{"included_km", in.IncludedKm, &params.IncludedKm},
+		{"max_distance_pay", in.MaxDistancePay, &params.MaxDistancePay},
+		{"extra_pickup_bonus", in.ExtraPickupBonus, &params.ExtraPickupBonus},
+	} {
+		n, err := parseAmount(f.name, f.v)
+		if err != nil {
+			return EarningRuleDetail{}, err
+		}
+		*f.dst = n
+	}
+	if in.PeakWindows != nil {
+		if err := validatePeakWindows(*in.PeakWindows); err != nil {
+			return EarningRuleDetail{}, err
+		}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return EarningRuleDetail{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	rule, err := qtx.UpdateEarningRule(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return EarningRuleDetail{}, apperror.NotFound("earning rule")
+	}
+	if err != nil {
+		return EarningRuleDetail{}, apperror.Internal("update earning rule", err)
+	}
+	var windows []sqlc.RiderEarningPeakWindow
+	if in.PeakWindows != nil {
+		windows, err = replacePeakWindows(ctx, qtx, rule.ID, *in.PeakWindows)
+	} else {
+		windows, err = qtx.ListEarningPeakWindows(ctx, rule.ID)
+	}
+	if err != nil {
+		return EarningRuleDetail{}, apperror.Internal("peak windows", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return EarningRuleDetail{}, apperror.Internal("commit", err)
+	}
+	return EarningRuleDetail{RiderEarningRule: rule, PeakWindows: windows}, nil
+}
+
+// DeleteEarningRule removes an earning rule. Earnings already recorded keep
+// their breakdown.
+func (s *Service) DeleteEarningRule(ctx context.Context, id, tenantID uuid.UUID) error {
+	if _, err := s.q.GetEarningRule(ctx, sqlc.GetEarningRuleParams{ID: id, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.NotFound("earning rule")
+		}
+		return apperror.Internal("get earning rule", err)
+	}
+	if err := s.q.DeleteEarningRule(ctx, sqlc.DeleteEarningRuleParams{ID: id, TenantID: tenantID}); err != nil {
+		return apperror.Internal("delete earning rule", err)
+	}
+	return nil
+}
+
+// ---------- Surges ----------
+
+// SurgeInput starts a rain or demand surge, optionally for one hub only.
+type SurgeInput struct {
+	HubID      *uuid.UUID `json:"hub_id"`
+	Kind       string     `json:"kind"`
+	Multiplier string     `json:"multiplier"`
+	Reason     string     `json:"reason"`
+	StartsAt   *time.Time `json:"starts_at"`
+	EndsAt     *time.Time `json:"ends_at"`
+}
+
+// CreateEarningSurge records a surge. Without starts_at it starts now; without
+// ends_at it runs until ended.
+func (s *Service) CreateEarningSurge(ctx context.Context, tenantID, actorID uuid.UUID, in SurgeInput) (sqlc.RiderEarningSurge, error) {
+	if in.Kind != SurgeRain && in.Kind != SurgeSurge {
+		return sqlc.RiderEarningSurge{}, apperror.BadRequest("kind must be rain or surge")
+	}
+	m, err := decimal.NewFromString(in.Multiplier)
+	if err != nil || m.LessThan(decimal.NewFromInt(1)) || m.GreaterThan(decimal.NewFromInt(5)) {
+		return sqlc.RiderEarningSurge{}, apperror.BadRequest("multiplier must be between 1 and 5")
+	}
+	startsAt := time.Now()
+	if in.StartsAt != nil {
+		startsAt = *in.StartsAt
+	}
+	params := sqlc.CreateEarningSurgeParams{
+		TenantID:   tenantID,
+		Kind:       in.Kind,
+		Multiplier: toPgNumeric(m),
+		Reason:     nullString(in.Reason),
+		StartsAt:   startsAt,
+		CreatedBy:  pgtype.UUID{Bytes: actorID, Valid: true},
+	}
+	if in.HubID != nil {
+		if err := s.checkHub(ctx, *in.HubID, tenantID); err != nil {
+			return sqlc.RiderEarningSurge{}, err
+		}
+		params.HubID = pgtype.UUID{Bytes: *in.HubID, Valid: true}
+	}
+	if in.EndsAt != nil {
+		if !in.EndsAt.After(startsAt) {
+			return sqlc.RiderEarningSurge{}, apperror.BadRequest("ends_at must be after starts_at")
+		}
+		params.EndsAt = pgtype.Timestamptz{Time: *in.EndsAt, Valid: true}
+	}
+	surge, err := s.q.CreateEarningSurge(ctx, params)
+	if err != nil {
+		return sqlc.RiderEarningSurge{}, apperror.Internal("create surge", err)
+	}
+	return surge, nil
+}
+
+// ListEarningSurges returns the tenant's surges, newest first.
+func (s *Service) ListEarningSurges(ctx context.Context, tenantID uuid.UUID, limit, offset int32) ([]sqlc.RiderEarningSurge, error) {
+	surges, err := s.q.ListEarningSurges(ctx, sqlc.ListEarningSurgesParams{TenantID: tenantID, Limit: limit, Offset: offset})
+	if err != nil {
+		return nil, apperror.Internal("list surges", err)
+	}
+	return surges, nil
+}
+
+// EndEarningSurge stops a running surge now, or drops one that has not started
+// yet.
+func (s *Service) EndEarningSurge(ctx context.Context, id, tenantID uuid.UUID) error {
+	_, err := s.q.EndEarningSurge(ctx, sqlc.EndEarningSurgeParams{ID: id, TenantID: tenantID})
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return apperror.Internal("end surge", err)
+	}
+	n, err := s.q.DeleteScheduledEarningSurge(ctx, sqlc.DeleteScheduledEarningSurgeParams{ID: id, TenantID: tenantID})
+	if err != nil {
+		return apperror.Internal("delete surge", err)
+	}
+	if n == 0 {
+		return apperror.NotFound("active surge")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package rider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+)
+
+func bdTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	at, err := timeutil.ParseBD("2006-01-02 15:04", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at
+}
+
+func TestComputeEarning_DefaultRuleMatchesFlatScheme(t *testing.T) {
+	b := computeEarning(defaultEarningRule(), EarningInput{
+		DistanceKm: dec("3.2"),
+		Pickups:    1,
+		At:         bdTime(t, "2026-03-04 13:15"),
+	})
+
+	if !b.Distance.Equal(dec("16")) || !b.Peak.Equal(dec("20")) {
+		t.Fatalf("distance %s peak %s, want 16 and 20", b.Distance, b.Peak)
+	}
+	if !b.Total.Equal(dec("86")) {
+		t.Errorf("total = %s, want 86", b.Total)
+	}
+}
+
+func TestComputeEarning_IncludedKmCapAndExtraPickups(t *testing.T) {
+	max := dec("60")
+	rule := EarningRule{
+		BasePay:          dec("40"),
+		PerKmRate:        dec("10"),
+		IncludedKm:       dec("2"),
+		MaxDistancePay:   &max,
+		ExtraPickupBonus: dec("15"),
+	}
+
+	b := computeEarning(rule, EarningInput{DistanceKm: dec("4.5"), Pickups: 3, At: bdTime(t, "2026-03-04 10:00")})
+	if !b.Distance.Equal(dec("25")) || !b.MultiPickup.Equal(dec("30")) {
+		t.Fatalf("distance %s multi-pickup %s, want 25 and 30", b.Distance, b.MultiPickup)
+	}
+
+	b = computeEarning(rule, EarningInput{DistanceKm: dec("12"), Pickups: 1, At: bdTime(t, "2026-03-04 10:00")})
+	if !b.Distance.Equal(dec("60")) || !b.Total.Equal(dec("100")) {
+		t.Errorf("distance %s total %s, want capped 60 and 100", b.Distance, b.Total)
+	}
+}
+
+func TestComputeEarning_PeakWindowUsesDhakaDayAndBestBonus(t *testing.T) {
+	friday := int32(time.Friday)
+	rule := EarningRule{
+		BasePay: dec("50"),
+		PeakWindows: []PeakWindow{
+			{StartMinute: 12 * 60, EndMinute: 15 * 60, Bonus: dec("20")},
+			{DayOfWeek: &friday, StartMinute: 13 * 60, EndMinute: 14 * 60, Bonus: dec("35")},
+		},
+	}
+
+	// 2026-03-06 is a Friday in Dhaka.
+	b := computeEarning(rule, EarningInput{Pickups: 1, At: bdTime(t, "2026-03-06 13:30")})
+	if !b.Peak.Equal(dec("35")) || b.PeakWindow == nil || b.PeakWindow.DayOfWeek == nil {
+		t.Fatalf("peak = %s (%+v), want the Friday window worth 35", b.Peak, b.PeakWindow)
+	}
+
+	b = computeEarning(rule, EarningInput{Pickups: 1, At: bdTime(t, "2026-03-05 13:30")})
+	if !b.Peak.Equal(dec("20")) {
+		t.Errorf("thursday peak = %s, want 20", b.Peak)
+	}
+
+	b = computeEarning(rule, EarningInput{Pickups: 1, At: bdTime(t, "2026-03-05 15:00")})
+	if !b.Peak.IsZero() {
+		t.Errorf("peak at window end = %s, want 0", b.Peak)
+	}
+}
+
+func TestComputeEarning_SurgeCompoundsButSkipsTip(t *testing.T) {
+	rain, surge := activeMultipliers([]sqlc.RiderEarningSurge{
+		{Kind: SurgeRain, Multiplier: toPgNumeric(dec("1.2"))},
+		{Kind: SurgeRain, Multiplier: toPgNumeric(dec("1.5"))},
+		{Kind: SurgeSurge, Multiplier: toPgNumeric(dec("1.2"))},
+	})
+	if !rain.Equal(dec("1.5")) || !surge.Equal(dec("1.2")) {
+		t.Fatalf("multipliers = %s/%s, want 1.5/1.2", rain, surge)
+	}
+
+	rule := EarningRule{BasePay: dec("100")}
+	b := computeEarning(rule, EarningInput{
+		Pickups:         1,
+		At:              bdTime(t, "2026-03-04 10:00"),
+		RainMultiplier:  rain,
+		SurgeMultiplier: surge,
+		Tip:             dec("30"),
+	})
+	if !b.Multiplier.Equal(dec("1.8")) || !b.SurgeBonus.Equal(dec("80")) {
+		t.Fatalf("multiplier %s surge bonus %s, want 1.8 and 80", b.Multiplier, b.SurgeBonus)
+	}
+	if !b.Total.Equal(dec("210")) {
+		t.Errorf("total = %s, want 210", b.Total)
+	}
+}
@@ -1,9 +1,13 @@
 package rider
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,6 +17,8 @@ import (
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/shopspring/decimal"
 )
 
 // Handler handles rider HTTP requests.
@@ -65,7 +71,6 @@ func (h *Handler) CreateRider(w http.ResponseWriter, r *http.Request) {
 		VehicleRegistration string    `json:"vehicle_registration"`
 		LicenseNumber       string    `json:"license_number"`
 		NidNumber           string    `json:"nid_number"`
-		NidVerified         bool      `json:"nid_verified"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -87,7 +92,6 @@ func (h *Handler) CreateRider(w http.ResponseWriter, r *http.Request) {
 		VehicleRegistration: req.VehicleRegistration,
 		LicenseNumber:       req.LicenseNumber,
 		NidNumber:           req.NidNumber,
-		NidVerified:         req.NidVerified,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -140,7 +144,6 @@ func (h *Handler) UpdateRider(w http.ResponseWriter, r *http.Request) {
 		VehicleRegistration string     `json:"vehicle_registration"`
 		LicenseNumber       string     `json:"license_number"`
 		NidNumber           string     `json:"nid_number"`
-		NidVerified         *bool      `json:"nid_verified"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -153,7 +156,6 @@ func (h *Handler) UpdateRider(w http.ResponseWriter, r *http.Request) {
 		VehicleRegistration: req.VehicleRegistration,
 		LicenseNumber:       req.LicenseNumber,
 		NidNumber:           req.NidNumber,
-		NidVerified:         req.NidVerified,
 	})
 	if err != nil {
 		respond.Error(w, toAppError(err))
@@ -243,7 +245,9 @@ func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		HubID uuid.UUID `json:"hub_id"`
+		HubID  uuid.UUID `json:"hub_id"`
+		GeoLat *float64  `json:"geo_lat"`
+		GeoLng *float64  `json:"geo_lng"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respond.Error(w, apperror.BadRequest("invalid request body"))
@@ -253,6 +257,14 @@ func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, apperror.BadRequest("hub_id is required"))
 		return
 	}
+	var pos *GeoPoint
+	if req.GeoLat != nil || req.GeoLng != nil {
+		if req.GeoLat == nil || req.GeoLng == nil {
+			respond.Error(w, apperror.BadRequest("geo_lat and geo_lng must be sent together"))
+			return
+		}
+		pos = &GeoPoint{Lat: *req.GeoLat, Lng: *req.GeoLng}
+	}
 
 	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
 	if err != nil {
@@ -260,7 +272,7 @@ func (h *Handler) CheckIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	att, err := h.svc.CheckIn(r.Context(), rider.ID, t.ID, req.HubID)
+	att, err := h.svc.CheckIn(r.Context(), rider.ID, t.ID, req.HubID, pos)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -380,6 +392,35 @@ func (h *Handler) AcceptOrder(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, order)
 }
 
+// DeclineOrder handles PATCH /api/v1/rider/orders/{id}/decline
+func (h *Handler) DeclineOrder(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order ID"))
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	offer, err := h.svc.DeclineOrder(r.Context(), orderID, rider.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, offer)
+}
+
 // MarkPickupPicked handles PATCH /api/v1/rider/orders/{id}/picked/{restaurant_id}
 func (h *Handler) MarkPickupPicked(w http.ResponseWriter, r *http.Request) {
 	u, t, appErr := requireRider(r)
@@ -428,13 +469,41 @@ func (h *Handler) MarkDelivered(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The body is optional. On COD orders the rider reports the cash actually
+	// collected; without it the full order total is assumed. The delivery
+	// code and photo are checked when the tenant requires proof of delivery.
+	var req struct {
+		CashCollected *string `json:"cash_collected"`
+		CashNote      string  `json:"cash_note"`
+		DeliveryCode  string  `json:"delivery_code"`
+		PhotoURL      string  `json:"photo_url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	cash := CashCollectionInput{Note: strings.TrimSpace(req.CashNote)}
+	if req.CashCollected != nil {
+		collected, err := decimal.NewFromString(*req.CashCollected)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("cash_collected must be a number"))
+			return
+		}
+		cash.Collected = &collected
+	}
+
 	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
 	}
 
-	order, err := h.svc.MarkDelivered(r.Context(), orderID, rider.ID, t.ID)
+	pod := DeliveryProofInput{
+		OTP:      strings.TrimSpace(req.DeliveryCode),
+		PhotoURL: strings.TrimSpace(req.PhotoURL),
+	}
+
+	order, err := h.svc.MarkDelivered(r.Context(), orderID, rider.ID, t.ID, cash, pod)
 	if err != nil {
 		respond.Error(w, toAppError(err))
 		return
@@ -586,36 +655,67 @@ func (h *Handler) ManualAssignRider(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetTravelLog handles GET /partner/riders/{id}/travel-log
+// The window is from/to (RFC3339), or since for the last day of history.
 func (h *Handler) GetTravelLog(w http.ResponseWriter, r *http.Request) {
-	t := tenant.FromContext(r.Context())
-	if t == nil {
-		respond.Error(w, apperror.NotFound("tenant"))
+	travel, appErr := h.loadTravelLog(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
 		return
 	}
 
-	riderID, err := uuid.Parse(chi.URLParam(r, "id"))
-	if err != nil {
-		respond.Error(w, apperror.BadRequest("invalid rider ID"))
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"travel_log": travel.History,
+		"trip":       travel,
+	})
+}
+
+// GetTravelLogGeoJSON handles GET /partner/riders/{id}/travel-log.geojson
+func (h *Handler) GetTravelLogGeoJSON(w http.ResponseWriter, r *http.Request) {
+	travel, appErr := h.loadTravelLog(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
 		return
 	}
 
-	since := time.Now().Add(-24 * time.Hour)
-	if v := r.URL.Query().Get("since"); v != "" {
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(travel.GeoJSON())
+}
+
+func (h *Handler) loadTravelLog(r *http.Request) (TravelLog, *apperror.AppError) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		return TravelLog{}, apperror.NotFound("tenant")
+	}
+
+	riderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return TravelLog{}, apperror.BadRequest("invalid rider ID")
+	}
+
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	for _, param := range []struct {
+		key string
+		dst *time.Time
+	}{{"since", &from}, {"from", &from}, {"to", &to}} {
+		key, dst := param.key, param.dst
+		v := r.URL.Query().Get(key)
+		if v == "" {
+			continue
+		}
 		parsed, err := time.Parse(time.RFC3339, v)
 		if err != nil {
-			respond.Error(w, apperror.BadRequest("invalid since format, use ISO8601"))
-			return
+			return TravelLog{}, apperror.BadRequest("invalid " + key + " format, use ISO8601")
 		}
-		since = parsed
+		*dst = parsed
 	}
 
-	history, err := h.svc.ListLocationHistory(r.Context(), riderID, since, 1000)
+	travel, err := h.svc.GetTravelLog(r.Context(), riderID, t.ID, from, to)
 	if err != nil {
-		respond.Error(w, toAppError(err))
-		return
+		return TravelLog{}, toAppError(err)
 	}
-
-	respond.JSON(w, http.StatusOK, map[string]interface{}{"travel_log": history})
+	return travel, nil
 }
 
 // ListRiderTracking handles GET /partner/riders/tracking
@@ -741,6 +841,1708 @@ func (h *Handler) UpdatePenalty(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, penalty)
 }
 
+// ---------- Rider API – Payouts ----------
+
+// ListMyPayouts handles GET /api/v1/rider/payouts
+func (h *Handler) ListMyPayouts(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	payouts, total, err := h.svc.ListPayouts(r.Context(), rider.ID, t.ID, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"payouts":         payouts,
+		"pending_balance": rider.PendingBalance,
+		"cash_in_hand":    rider.CashInHand,
+		"total":           total,
+		"limit":           limit,
+		"offset":          offset,
+	})
+}
+
+// ---------- Partner API – Payouts ----------
+
+// requirePayoutManager allows only tenant owners and admins to move money.
+func requirePayoutManager(r *http.Request) (*sqlc.User, *sqlc.Tenant, *apperror.AppError) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		return nil, nil, apperror.NotFound("tenant")
+	}
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		return nil, nil, apperror.Unauthorized("authentication required")
+	}
+	if u.Role != sqlc.UserRoleTenantOwner && u.Role != sqlc.UserRoleTenantAdmin {
+		return nil, nil, apperror.Forbidden("tenant owner or admin role required")
+	}
+	return u, t, nil
+}
+
+// CreatePayoutBatch handles POST /partner/riders/payouts
+func (h *Handler) CreatePayoutBatch(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req struct {
+		Until    string      `json:"until"`
+		RiderIDs []uuid.UUID `json:"rider_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	until := timeutil.NowBD()
+	if req.Until != "" {
+		parsed, err := timeutil.ParseBD("2006-01-02", req.Until)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("until must be YYYY-MM-DD"))
+			return
+		}
+		until = parsed
+	}
+
+	batch, err := h.svc.CreateOnDemandBatch(r.Context(), t.ID, u.ID, until, req.RiderIDs)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, batch)
+}
+
+// ListPayoutBatches handles GET /partner/riders/payout-batches
+func (h *Handler) ListPayoutBatches(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	batches, total, err := h.svc.ListPayoutBatches(r.Context(), t.ID, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"batches": batches,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetPayoutBatch handles GET /partner/riders/payout-batches/{id}
+func (h *Handler) GetPayoutBatch(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid batch ID"))
+		return
+	}
+
+	batch, err := h.svc.GetPayoutBatch(r.Context(), batchID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, batch)
+}
+
+// ExportBkashDisbursement handles POST /partner/riders/payout-batches/{id}/bkash.csv
+// The file lists the wallet transfers still to be made; exporting moves
+// those payouts to processing.
+func (h *Handler) ExportBkashDisbursement(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	batchID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid batch ID"))
+		return
+	}
+
+	batch, payouts, err := h.svc.ExportBkashDisbursement(r.Context(), batchID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	filename := "bkash-payouts-" + batch.PeriodTo.Time.Format("2006-01-02") + "-" + batch.ID.String()[:8] + ".csv"
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"wallet_number", "amount", "reference", "name"})
+	for _, p := range payouts {
+		wallet := p.PayoutAccount.String
+		if !p.PayoutAccount.Valid {
+			wallet = p.RiderPhone.String
+		}
+		cw.Write([]string{
+			wallet,
+			numericToDecimal(p.Amount).StringFixed(2),
+			p.ID.String(),
+			p.RiderName,
+		})
+	}
+	cw.Flush()
+}
+
+// CompletePayout handles PATCH /partner/riders/payouts/{id}/complete
+func (h *Handler) CompletePayout(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	payoutID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid payout ID"))
+		return
+	}
+
+	var req struct {
+		PaymentReference string `json:"payment_reference"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	payout, err := h.svc.CompletePayout(r.Context(), payoutID, t.ID, u.ID, strings.TrimSpace(req.PaymentReference))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, payout)
+}
+
+// FailPayout handles PATCH /partner/riders/payouts/{id}/fail
+func (h *Handler) FailPayout(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	payoutID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid payout ID"))
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if strings.TrimSpace(req.Note) == "" {
+		respond.Error(w, apperror.BadRequest("note is required"))
+		return
+	}
+
+	payout, err := h.svc.FailPayout(r.Context(), payoutID, t.ID, u.ID, strings.TrimSpace(req.Note))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, payout)
+}
+
+// ---------- Partner API – Earning rules ----------
+
+// ListEarningRules handles GET /partner/riders/earning-rules
+func (h *Handler) ListEarningRules(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rules, err := h.svc.ListEarningRules(r.Context(), t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"rules": rules})
+}
+
+// CreateEarningRule handles POST /partner/riders/earning-rules
+func (h *Handler) CreateEarningRule(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req EarningRuleInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	rule, err := h.svc.CreateEarningRule(r.Context(), t.ID, u.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, rule)
+}
+
+// UpdateEarningRule handles PUT /partner/riders/earning-rules/{id}
+func (h *Handler) UpdateEarningRule(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid earning rule ID"))
+		return
+	}
+
+	var req EarningRuleInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	rule, err := h.svc.UpdateEarningRule(r.Context(), ruleID, t.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, rule)
+}
+
+// DeleteEarningRule handles DELETE /partner/riders/earning-rules/{id}
+func (h *Handler) DeleteEarningRule(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid earning rule ID"))
+		return
+	}
+
+	if err := h.svc.DeleteEarningRule(r.Context(), ruleID, t.ID); err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// ListEarningSurges handles GET /partner/riders/surges
+func (h *Handler) ListEarningSurges(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	surges, err := h.svc.ListEarningSurges(r.Context(), t.ID, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"surges": surges,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// CreateEarningSurge handles POST /partner/riders/surges
+func (h *Handler) CreateEarningSurge(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req SurgeInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	surge, err := h.svc.CreateEarningSurge(r.Context(), t.ID, u.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, surge)
+}
+
+// EndEarningSurge handles DELETE /partner/riders/surges/{id}
+func (h *Handler) EndEarningSurge(w http.ResponseWriter, r *http.Request) {
+	_, t, appErr := requirePayoutManager(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	surgeID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid surge ID"))
+		return
+	}
+
+	if err := h.svc.EndEarningSurge(r.Context(), surgeID, t.ID); err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]string{"status": "ended"})
+}
+
+// ---------- Rider API – Cash ----------
+
+// GetMyCash handles GET /api/v1/rider/cash
+func (h *Handler) GetMyCash(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	summary, err := h.svc.GetCashSummary(r.Context(), rider)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, summary)
+}
+
+// SubmitCashDeposit handles POST /api/v1/rider/cash/deposits
+func (h *Handler) SubmitCashDeposit(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req CashDepositInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	deposit, err := h.svc.SubmitCashDeposit(r.Context(), rider, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, deposit)
+}
+
+// ---------- Partner API – Hub cash ----------
+
+// requireTenantUser returns the tenant and the authenticated user. Hub-level
+// authorization is left to the service.
+func requireTenantUser(r *http.Request) (*sqlc.User, *sqlc.Tenant, *apperror.AppError) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		return nil, nil, apperror.NotFound("tenant")
+	}
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		return nil, nil, apperror.Unauthorized("authentication required")
+	}
+	return u, t, nil
+}
+
+// parseHubQuery reads the optional hub_id query parameter.
+func parseHubQuery(r *http.Request) (*uuid.UUID, *apperror.AppError) {
+	v := r.URL.Query().Get("hub_id")
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, apperror.BadRequest("invalid hub_id")
+	}
+	return &id, nil
+}
+
+// ListCashDeposits handles GET /partner/riders/cash/deposits
+func (h *Handler) ListCashDeposits(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	if status != "" && status != DepositPending && status != DepositConfirmed && status != DepositRejected {
+		respond.Error(w, apperror.BadRequest("status must be pending, confirmed or rejected"))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	deposits, err := h.svc.ListCashDeposits(r.Context(), u, t.ID, hubID, status, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"deposits": deposits,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// ConfirmCashDeposit handles PATCH /partner/riders/cash/deposits/{id}/confirm
+func (h *Handler) ConfirmCashDeposit(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	depositID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid deposit ID"))
+		return
+	}
+
+	deposit, err := h.svc.ConfirmCashDeposit(r.Context(), depositID, t.ID, u)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, deposit)
+}
+
+// RejectCashDeposit handles PATCH /partner/riders/cash/deposits/{id}/reject
+func (h *Handler) RejectCashDeposit(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	depositID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid deposit ID"))
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		respond.Error(w, apperror.BadRequest("reason is required"))
+		return
+	}
+
+	deposit, err := h.svc.RejectCashDeposit(r.Context(), depositID, t.ID, u, strings.TrimSpace(req.Reason))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, deposit)
+}
+
+// GetCashReconciliation handles GET /partner/riders/cash/reconciliation
+func (h *Handler) GetCashReconciliation(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	day := timeutil.NowBD()
+	if v := r.URL.Query().Get("date"); v != "" {
+		parsed, err := timeutil.ParseBD("2006-01-02", v)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("date must be YYYY-MM-DD"))
+			return
+		}
+		day = parsed
+	}
+
+	report, err := h.svc.GetCashReconciliation(r.Context(), u, t.ID, day, hubID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, report)
+}
+
+// ---------- Proof of delivery ----------
+
+// GetDeliveryCode handles GET /orders/{id}/delivery-code
+func (h *Handler) GetDeliveryCode(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order ID"))
+		return
+	}
+
+	code, err := h.svc.GetDeliveryCode(r.Context(), t.ID, orderID, u.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, code)
+}
+
+// GetDeliveryProof handles GET /partner/orders/{id}/delivery-proof
+func (h *Handler) GetDeliveryProof(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order ID"))
+		return
+	}
+
+	proof, err := h.svc.GetDeliveryProof(r.Context(), t.ID, orderID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, proof)
+}
+
+// ---------- Partner API – Shifts ----------
+
+// parseDateQuery reads an optional YYYY-MM-DD query parameter as a Dhaka date.
+func parseDateQuery(r *http.Request, key string, fallback time.Time) (time.Time, *apperror.AppError) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return fallback, nil
+	}
+	parsed, err := timeutil.ParseBD("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, apperror.BadRequest(key + " must be YYYY-MM-DD")
+	}
+	return parsed, nil
+}
+
+// ListShiftTemplates handles GET /partner/riders/shift-templates
+func (h *Handler) ListShiftTemplates(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	templates, err := h.svc.ListShiftTemplates(r.Context(), u, t.ID, hubID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"templates": templates})
+}
+
+// CreateShiftTemplate handles POST /partner/riders/shift-templates
+func (h *Handler) CreateShiftTemplate(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req ShiftTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	tmpl, err := h.svc.CreateShiftTemplate(r.Context(), u, t.ID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, tmpl)
+}
+
+// UpdateShiftTemplate handles PUT /partner/riders/shift-templates/{id}
+func (h *Handler) UpdateShiftTemplate(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid shift template ID"))
+		return
+	}
+
+	var req ShiftTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	tmpl, err := h.svc.UpdateShiftTemplate(r.Context(), u, t.ID, templateID, req)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, tmpl)
+}
+
+// DeleteShiftTemplate handles DELETE /partner/riders/shift-templates/{id}
+func (h *Handler) DeleteShiftTemplate(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	templateID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid shift template ID"))
+		return
+	}
+
+	if err := h.svc.DeleteShiftTemplate(r.Context(), u, t.ID, templateID); err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// ListShifts handles GET /partner/riders/shifts
+func (h *Handler) ListShifts(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	from, appErr := parseDateQuery(r, "from", timeutil.NowBD())
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	to, appErr := parseDateQuery(r, "to", from.AddDate(0, 0, 6))
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	var riderID *uuid.UUID
+	if v := r.URL.Query().Get("rider_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid rider_id"))
+			return
+		}
+		riderID = &id
+	}
+
+	shifts, err := h.svc.ListShifts(r.Context(), u, t.ID, hubID, riderID, from, to)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"shifts": shifts})
+}
+
+// RosterShifts handles POST /partner/riders/shifts
+func (h *Handler) RosterShifts(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req struct {
+		TemplateID uuid.UUID   `json:"template_id"`
+		RiderIDs   []uuid.UUID `json:"rider_ids"`
+		From       string      `json:"from"`
+		To         string      `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if req.TemplateID == uuid.Nil {
+		respond.Error(w, apperror.BadRequest("template_id is required"))
+		return
+	}
+	from, err := timeutil.ParseBD("2006-01-02", req.From)
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("from must be YYYY-MM-DD"))
+		return
+	}
+	to := from
+	if req.To != "" {
+		if to, err = timeutil.ParseBD("2006-01-02", req.To); err != nil {
+			respond.Error(w, apperror.BadRequest("to must be YYYY-MM-DD"))
+			return
+		}
+	}
+
+	result, err := h.svc.RosterShifts(r.Context(), u, t.ID, RosterInput{
+		TemplateID: req.TemplateID,
+		RiderIDs:   req.RiderIDs,
+		From:       from,
+		To:         to,
+	})
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, result)
+}
+
+// CancelShift handles DELETE /partner/riders/shifts/{id}
+func (h *Handler) CancelShift(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	shiftID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid shift ID"))
+		return
+	}
+
+	shift, err := h.svc.CancelShift(r.Context(), u, t.ID, shiftID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, shift)
+}
+
+// GetShiftCoverage handles GET /partner/riders/shifts/coverage
+func (h *Handler) GetShiftCoverage(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	day, appErr := parseDateQuery(r, "date", timeutil.NowBD())
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	perRider := decimal.NewFromInt(2)
+	if v := r.URL.Query().Get("orders_per_rider"); v != "" {
+		parsed, err := decimal.NewFromString(v)
+		if err != nil {
+			respond.Error(w, apperror.BadRequest("invalid orders_per_rider"))
+			return
+		}
+		perRider = parsed
+	}
+
+	report, err := h.svc.GetShiftCoverage(r.Context(), u, t.ID, hubID, day, perRider)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, report)
+}
+
+// ListShiftSwaps handles GET /partner/riders/shift-swaps
+func (h *Handler) ListShiftSwaps(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", SwapPending, SwapAccepted, SwapDeclined, SwapApproved, SwapRejected, SwapCancelled:
+	default:
+		respond.Error(w, apperror.BadRequest("invalid status"))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	swaps, err := h.svc.ListShiftSwaps(r.Context(), u, t.ID, hubID, status, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"swaps":  swaps,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ApproveShiftSwap handles PATCH /partner/riders/shift-swaps/{id}/approve
+func (h *Handler) ApproveShiftSwap(w http.ResponseWriter, r *http.Request) {
+	h.reviewShiftSwap(w, r, true)
+}
+
+// RejectShiftSwap handles PATCH /partner/riders/shift-swaps/{id}/reject
+func (h *Handler) RejectShiftSwap(w http.ResponseWriter, r *http.Request) {
+	h.reviewShiftSwap(w, r, false)
+}
+
+func (h *Handler) reviewShiftSwap(w http.ResponseWriter, r *http.Request, approve bool) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	swapID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid shift swap ID"))
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respond.Error(w, apperror.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	swap, err := h.svc.ReviewShiftSwap(r.Context(), u, t.ID, swapID, approve, strings.TrimSpace(req.Note))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, swap)
+}
+
+// ---------- Rider API – Shifts ----------
+
+// ListMyShifts handles GET /api/v1/rider/shifts
+func (h *Handler) ListMyShifts(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	shifts, err := h.svc.ListMyShifts(r.Context(), rider.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{"shifts": shifts})
+}
+
+// RequestShiftSwap handles POST /api/v1/rider/shifts/{id}/swap
+func (h *Handler) RequestShiftSwap(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	shiftID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid shift ID"))
+		return
+	}
+
+	var req struct {
+		TargetRiderID uuid.UUID `json:"target_rider_id"`
+		Reason        string    `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if req.TargetRiderID == uuid.Nil {
+		respond.Error(w, apperror.BadRequest("target_rider_id is required"))
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	swap, err := h.svc.RequestShiftSwap(r.Context(), rider.ID, t.ID, shiftID, req.TargetRiderID, strings.TrimSpace(req.Reason))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, swap)
+}
+
+// ListMyShiftSwaps handles GET /api/v1/rider/shift-swaps
+func (h *Handler) ListMyShiftSwaps(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	swaps, err := h.svc.ListMyShiftSwaps(r.Context(), rider.ID, t.ID, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"swaps":  swaps,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// AcceptShiftSwap handles PATCH /api/v1/rider/shift-swaps/{id}/accept
+func (h *Handler) AcceptShiftSwap(w http.ResponseWriter, r *http.Request) {
+	h.updateMyShiftSwap(w, r, func(rider sqlc.Rider, tenantID, swapID uuid.UUID) (sqlc.RiderShiftSwap, error) {
+		return h.svc.RespondShiftSwap(r.Context(), rider.ID, tenantID, swapID, true)
+	})
+}
+
+// DeclineShiftSwap handles PATCH /api/v1/rider/shift-swaps/{id}/decline
+func (h *Handler) DeclineShiftSwap(w http.ResponseWriter, r *http.Request) {
+	h.updateMyShiftSwap(w, r, func(rider sqlc.Rider, tenantID, swapID uuid.UUID) (sqlc.RiderShiftSwap, error) {
+		return h.svc.RespondShiftSwap(r.Context(), rider.ID, tenantID, swapID, false)
+	})
+}
+
+// CancelShiftSwap handles PATCH /api/v1/rider/shift-swaps/{id}/cancel
+func (h *Handler) CancelShiftSwap(w http.ResponseWriter, r *http.Request) {
+	h.updateMyShiftSwap(w, r, func(rider sqlc.Rider, tenantID, swapID uuid.UUID) (sqlc.RiderShiftSwap, error) {
+		return h.svc.CancelShiftSwap(r.Context(), rider.ID, tenantID, swapID)
+	})
+}
+
+func (h *Handler) updateMyShiftSwap(w http.ResponseWriter, r *http.Request, update func(sqlc.Rider, uuid.UUID, uuid.UUID) (sqlc.RiderShiftSwap, error)) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	swapID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid shift swap ID"))
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	swap, err := update(rider, t.ID, swapID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, swap)
+}
+
+// ---------- Rider API – Penalties ----------
+
+// ListMyPenalties handles GET /api/v1/rider/penalties
+func (h *Handler) ListMyPenalties(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	penalties, err := h.svc.ListPenalties(r.Context(), rider.ID, t.ID, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"penalties": penalties,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// AppealPenalty handles POST /api/v1/rider/penalties/{id}/appeal
+func (h *Handler) AppealPenalty(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	penaltyID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid penalty ID"))
+		return
+	}
+
+	var req struct {
+		Note         string   `json:"note"`
+		EvidenceURLs []string `json:"evidence_urls"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	penalty, err := h.svc.AppealPenalty(r.Context(), rider.ID, t.ID, penaltyID, PenaltyAppealInput{
+		Note:         req.Note,
+		EvidenceURLs: req.EvidenceURLs,
+	})
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, penalty)
+}
+
+// ---------- Partner API – Penalty appeals ----------
+
+// ListPenaltyAppeals handles GET /partner/riders/penalty-appeals
+func (h *Handler) ListPenaltyAppeals(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	limit, offset := parsePagination(r)
+	appeals, err := h.svc.ListPenaltyAppeals(r.Context(), u, t.ID, hubID, r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"appeals": appeals,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// UpholdPenaltyAppeal handles PATCH /partner/riders/penalty-appeals/{id}/uphold
+func (h *Handler) UpholdPenaltyAppeal(w http.ResponseWriter, r *http.Request) {
+	h.reviewPenaltyAppeal(w, r, true)
+}
+
+// OverturnPenaltyAppeal handles PATCH /partner/riders/penalty-appeals/{id}/overturn
+func (h *Handler) OverturnPenaltyAppeal(w http.ResponseWriter, r *http.Request) {
+	h.reviewPenaltyAppeal(w, r, false)
+}
+
+func (h *Handler) reviewPenaltyAppeal(w http.ResponseWriter, r *http.Request, uphold bool) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	penaltyID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid penalty ID"))
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respond.Error(w, apperror.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	penalty, err := h.svc.ReviewPenaltyAppeal(r.Context(), u, t.ID, penaltyID, uphold, strings.TrimSpace(req.Note))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, penalty)
+}
+
+// ---------- Scorecards ----------
+
+// parseScorecardRange reads from/to dates, defaulting to the scorecard
+// window ending yesterday.
+func parseScorecardRange(r *http.Request) (time.Time, time.Time, *apperror.AppError) {
+	yesterday := timeutil.StartOfDayBD(time.Now()).AddDate(0, 0, -1)
+	to, appErr := parseDateQuery(r, "to", yesterday)
+	if appErr != nil {
+		return time.Time{}, time.Time{}, appErr
+	}
+	from, appErr := parseDateQuery(r, "from", to.AddDate(0, 0, 1-scorecardWindowDays))
+	if appErr != nil {
+		return time.Time{}, time.Time{}, appErr
+	}
+	return from, to, nil
+}
+
+// GetMyScorecard handles GET /api/v1/rider/scorecard
+func (h *Handler) GetMyScorecard(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	from, to, appErr := parseScorecardRange(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	scorecard, err := h.svc.GetScorecard(r.Context(), rider, from, to)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, scorecard)
+}
+
+// GetRiderScorecard handles GET /partner/riders/{id}/scorecard
+func (h *Handler) GetRiderScorecard(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	riderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid rider ID"))
+		return
+	}
+	from, to, appErr := parseScorecardRange(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	scorecard, err := h.svc.GetRiderScorecard(r.Context(), u, t.ID, riderID, from, to)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, scorecard)
+}
+
+// ListRiderTiers handles GET /partner/riders/tiers
+func (h *Handler) ListRiderTiers(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	riders, err := h.svc.ListRiderTiers(r.Context(), u, t.ID, hubID, r.URL.Query().Get("tier"), limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"riders": riders,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ---------- Onboarding ----------
+
+// documentRequest is one uploaded document in a request body.
+type documentRequest struct {
+	Type      string `json:"type"`
+	FileURL   string `json:"file_url"`
+	Number    string `json:"document_number"`
+	ExpiresOn string `json:"expires_on"`
+}
+
+func (d documentRequest) input() (DocumentInput, *apperror.AppError) {
+	in := DocumentInput{Type: d.Type, FileURL: d.FileURL, Number: d.Number}
+	if d.ExpiresOn != "" {
+		parsed, err := timeutil.ParseBD("2006-01-02", d.ExpiresOn)
+		if err != nil {
+			return DocumentInput{}, apperror.BadRequest("expires_on must be YYYY-MM-DD")
+		}
+		in.ExpiresOn = &parsed
+	}
+	return in, nil
+}
+
+// GetOnboarding handles GET /api/v1/rider/onboarding
+func (h *Handler) GetOnboarding(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	status, err := h.svc.GetOnboardingStatus(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, status)
+}
+
+// SubmitApplication handles POST /api/v1/rider/onboarding/application
+func (h *Handler) SubmitApplication(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req struct {
+		HubID               uuid.UUID         `json:"hub_id"`
+		VehicleType         string            `json:"vehicle_type"`
+		VehicleRegistration string            `json:"vehicle_registration"`
+		LicenseNumber       string            `json:"license_number"`
+		NidNumber           string            `json:"nid_number"`
+		Documents           []documentRequest `json:"documents"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	if req.HubID == uuid.Nil {
+		respond.Error(w, apperror.BadRequest("hub_id is required"))
+		return
+	}
+
+	in := ApplicationInput{
+		HubID:               req.HubID,
+		VehicleType:         sqlc.VehicleType(req.VehicleType),
+		VehicleRegistration: req.VehicleRegistration,
+		LicenseNumber:       req.LicenseNumber,
+		NidNumber:           req.NidNumber,
+	}
+	for _, d := range req.Documents {
+		doc, appErr := d.input()
+		if appErr != nil {
+			respond.Error(w, appErr)
+			return
+		}
+		in.Documents = append(in.Documents, doc)
+	}
+
+	app, err := h.svc.SubmitApplication(r.Context(), u.ID, t.ID, in)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, app)
+}
+
+// UploadDocument handles POST /api/v1/rider/documents
+func (h *Handler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireRider(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	var req documentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	in, appErr := req.input()
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	rider, err := h.svc.GetRiderByUserID(r.Context(), u.ID, t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	doc, err := h.svc.UploadDocument(r.Context(), rider, in)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, doc)
+}
+
+// ---------- Partner API – Onboarding review ----------
+
+// ListApplications handles GET /partner/riders/applications
+func (h *Handler) ListApplications(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", ApplicationSubmitted, ApplicationApproved, ApplicationRejected:
+	default:
+		respond.Error(w, apperror.BadRequest("status must be submitted, approved or rejected"))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	apps, err := h.svc.ListApplications(r.Context(), u, t.ID, hubID, status, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"applications": apps,
+		"limit":        limit,
+		"offset":       offset,
+	})
+}
+
+// GetApplication handles GET /partner/riders/applications/{id}
+func (h *Handler) GetApplication(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid application ID"))
+		return
+	}
+
+	app, err := h.svc.GetApplication(r.Context(), u, t.ID, id)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, app)
+}
+
+// ApproveApplication handles PATCH /partner/riders/applications/{id}/approve
+func (h *Handler) ApproveApplication(w http.ResponseWriter, r *http.Request) {
+	h.reviewApplication(w, r, true)
+}
+
+// RejectApplication handles PATCH /partner/riders/applications/{id}/reject
+func (h *Handler) RejectApplication(w http.ResponseWriter, r *http.Request) {
+	h.reviewApplication(w, r, false)
+}
+
+func (h *Handler) reviewApplication(w http.ResponseWriter, r *http.Request, approve bool) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid application ID"))
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respond.Error(w, apperror.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	app, err := h.svc.ReviewApplication(r.Context(), u, t.ID, id, approve, req.Reason)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, app)
+}
+
+// ListDocumentRenewals handles GET /partner/riders/document-renewals
+func (h *Handler) ListDocumentRenewals(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	hubID, appErr := parseHubQuery(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", DocumentPending, DocumentApproved, DocumentRejected:
+	default:
+		respond.Error(w, apperror.BadRequest("status must be pending, approved or rejected"))
+		return
+	}
+
+	limit, offset := parsePagination(r)
+	docs, err := h.svc.ListDocumentRenewals(r.Context(), u, t.ID, hubID, status, limit, offset)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"documents": docs,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// ApproveDocument handles PATCH /partner/riders/document-renewals/{id}/approve
+func (h *Handler) ApproveDocument(w http.ResponseWriter, r *http.Request) {
+	h.reviewDocument(w, r, true)
+}
+
+// RejectDocument handles PATCH /partner/riders/document-renewals/{id}/reject
+func (h *Handler) RejectDocument(w http.ResponseWriter, r *http.Request) {
+	h.reviewDocument(w, r, false)
+}
+
+func (h *Handler) reviewDocument(w http.ResponseWriter, r *http.Request, approve bool) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid document ID"))
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respond.Error(w, apperror.BadRequest("invalid request body"))
+			return
+		}
+	}
+
+	doc, err := h.svc.ReviewDocument(r.Context(), u, t.ID, id, approve, req.Reason)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, doc)
+}
+
+// ListRiderDocuments handles GET /partner/riders/{id}/documents
+func (h *Handler) ListRiderDocuments(w http.ResponseWriter, r *http.Request) {
+	u, t, appErr := requireTenantUser(r)
+	if appErr != nil {
+		respond.Error(w, appErr)
+		return
+	}
+
+	riderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid rider ID"))
+		return
+	}
+
+	docs, err := h.svc.ListRiderDocuments(r.Context(), u, t.ID, riderID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]interface{}{
+		"documents": docs,
+	})
+}
+
 // ---------- Helpers ----------
 
 func parsePagination(r *http.Request) (limit, offset int32) {
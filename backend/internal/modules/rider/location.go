@@ -0,0 +1,556 @@
+package rider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/geo"
+	redisclient "github.com/munchies/platform/backend/internal/platform/redis"
+	"github.com/rs/zerolog/log"
+)
+
+// LocationConfig tunes the location pipeline. Zero values fall back to the
+// defaults below.
+type LocationConfig struct {
+	FlushInterval       time.Duration
+	HistoryInterval     time.Duration
+	HistoryMinDistanceM float64
+	MaxAccuracyM        float64
+	MaxSpeedKmh         float64
+	StaleAfter          time.Duration
+	HistoryRetention    time.Duration
+}
+
+func (c LocationConfig) withDefaults() LocationConfig {
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = 5 * time.Second
+	}
+	if c.HistoryInterval <= 0 {
+		c.HistoryInterval = 30 * time.Second
+	}
+	if c.HistoryMinDistanceM <= 0 {
+		c.HistoryMinDistanceM = 25
+	}
+	if c.MaxAccuracyM <= 0 {
+		c.MaxAccuracyM = 100
+	}
+	if c.MaxSpeedKmh <= 0 {
+		c.MaxSpeedKmh = 120
+	}
+	if c.StaleAfter <= 0 {
+		c.StaleAfter = 2 * time.Minute
+	}
+	if c.HistoryRetention <= 0 {
+		c.HistoryRetention = 30 * 24 * time.Hour
+	}
+	return c
+}
+
+// Reasons a location fix is dropped.
+const (
+	FixInvalid    = "invalid_coordinates"
+	FixInaccurate = "low_accuracy"
+	FixOutOfOrder = "out_of_order"
+	FixJump       = "impossible_jump"
+)
+
+const (
+	// historyPurgeBatch is how many history rows one delete removes.
+	historyPurgeBatch = 5000
+	// historyPurgeMaxBatches caps the deletes of one retention run.
+	historyPurgeMaxBatches = 20
+)
+
+// locationFix is one GPS reading from the rider app.
+type locationFix struct {
+	Lat       float64
+	Lng       float64
+	Heading   float64
+	SpeedKmh  float64
+	AccuracyM float64
+	At        time.Time
+}
+
+// historyPoint is a fix kept for the rider's trail, with the distance from
+// the previous kept point.
+type historyPoint struct {
+	fix        locationFix
+	distanceKm float64
+}
+
+// checkFix decides whether a fix is plausible given the last accepted one. It
+// returns an empty reason for good fixes.
+func checkFix(prev *locationFix, next locationFix, cfg LocationConfig) string {
+	if next.Lat < -90 || next.Lat > 90 || next.Lng < -180 || next.Lng > 180 || (next.Lat == 0 && next.Lng == 0) {
+		return FixInvalid
+	}
+	if next.AccuracyM > cfg.MaxAccuracyM {
+		return FixInaccurate
+	}
+	if prev == nil {
+		return ""
+	}
+	if next.At.Before(prev.At) {
+		return FixOutOfOrder
+	}
+	// After a long silence the rider may really be far away.
+	elapsed := next.At.Sub(prev.At)
+	if elapsed > cfg.StaleAfter {
+		return ""
+	}
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+	km := geo.DistanceKm(prev.Lat, prev.Lng, next.Lat, next.Lng)
+	if km/elapsed.Hours() > cfg.MaxSpeedKmh {
+		return FixJump
+	}
+	return ""
+}
+
+// nextHistoryPoint downsamples the trail: a fix is kept once the rider has
+// moved further than both the minimum distance and the fix's accuracy, or when
+// the history interval has passed. Distance is only counted for real moves so
+// GPS jitter while parked does not add up.
+func nextHistoryPoint(last *historyPoint, fix locationFix, cfg LocationConfig) (historyPoint, bool) {
+	if last == nil {
+		return historyPoint{fix: fix}, true
+	}
+	km := geo.DistanceKm(last.fix.Lat, last.fix.Lng, fix.Lat, fix.Lng)
+	threshold := math.Max(cfg.HistoryMinDistanceM, fix.AccuracyM) / 1000
+	if km >= threshold {
+		return historyPoint{fix: fix, distanceKm: km}, true
+	}
+	if fix.At.Sub(last.fix.At) >= cfg.HistoryInterval {
+		return historyPoint{fix: historyPointAt(last.fix, fix.At)}, true
+	}
+	return historyPoint{}, false
+}
+
+// historyPointAt repeats a position at a later time, for heartbeats while the
+// rider is not moving.
+func historyPointAt(f locationFix, at time.Time) locationFix {
+	f.At = at
+	return f
+}
+
+// riderTrack is the in-memory state of one connected rider.
+type riderTrack struct {
+	tenantID    uuid.UUID
+	last        *locationFix
+	lastHistory *historyPoint
+	latest      *locationFix // not yet written
+	history     []historyPoint
+}
+
+// LocationPipeline buffers rider locations in memory and writes them in
+// batches. Each flush stores the latest position per rider, the downsampled
+// trail, and publishes live positions and ETAs.
+type LocationPipeline struct {
+	q     *sqlc.Queries
+	pool  *pgxpool.Pool
+	redis *redisclient.Client
+	cfg   LocationConfig
+
+	mu     sync.Mutex
+	tracks map[uuid.UUID]*riderTrack
+}
+
+// NewLocationPipeline creates a location pipeline.
+func NewLocationPipeline(q *sqlc.Queries, pool *pgxpool.Pool, redis *redisclient.Client, cfg LocationConfig) *LocationPipeline {
+	return &LocationPipeline{q: q, pool: pool, redis: redis, cfg: cfg.withDefaults(), tracks: make(map[uuid.UUID]*riderTrack)}
+}
+
+// FlushInterval is how often Flush should run.
+func (p *LocationPipeline) FlushInterval() time.Duration {
+	return p.cfg.FlushInterval
+}
+
+// track returns the rider's state. The first time a rider reports after a
+// reconnect or restart, a recent stored location anchors the trail so the
+// distance between sessions is counted.
+func (p *LocationPipeline) track(ctx context.Context, riderID, tenantID uuid.UUID) *riderTrack {
+	p.mu.Lock()
+	t, ok := p.tracks[riderID]
+	p.mu.Unlock()
+	if ok {
+		return t
+	}
+
+	t = &riderTrack{tenantID: tenantID}
+	if loc, err := p.q.GetRiderLocation(ctx, riderID); err == nil && time.Since(loc.UpdatedAt) < p.cfg.StaleAfter {
+		lat, okLat := numericToFloat64(loc.GeoLat)
+		lng, okLng := numericToFloat64(loc.GeoLng)
+		if okLat && okLng {
+			t.lastHistory = &historyPoint{fix: locationFix{Lat: lat, Lng: lng, At: loc.UpdatedAt}}
+		}
+	} else if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Error().Err(err).Str("rider_id", riderID.String()).Msg("seed rider location failed")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.tracks[riderID]; ok {
+		return existing
+	}
+	p.tracks[riderID] = t
+	return t
+}
+
+// Ingest filters a fix and buffers it for the next flush. It returns the
+// reason the fix was dropped, or an empty string.
+func (p *LocationPipeline) Ingest(ctx context.Context, riderID, tenantID uuid.UUID, fix locationFix) string {
+	t := p.track(ctx, riderID, tenantID)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if reason := checkFix(t.last, fix, p.cfg); reason != "" {
+		return reason
+	}
+	t.last = &fix
+	t.latest = &fix
+	if point, ok := nextHistoryPoint(t.lastHistory, fix, p.cfg); ok {
+		t.lastHistory = &point
+		t.history = append(t.history, point)
+	}
+	return ""
+}
+
+// pendingWrite is what one flush writes for a rider.
+type pendingWrite struct {
+	riderID  uuid.UUID
+	tenantID uuid.UUID
+	latest   locationFix
+	history  []historyPoint
+}
+
+// takePending removes buffered writes, for every rider or just one.
+func (p *LocationPipeline) takePending(only *uuid.UUID) []pendingWrite {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []pendingWrite
+	for id, t := range p.tracks {
+		if only != nil && id != *only {
+			continue
+		}
+		if t.latest == nil {
+			continue
+		}
+		out = append(out, pendingWrite{riderID: id, tenantID: t.tenantID, latest: *t.latest, history: t.history})
+		t.latest = nil
+		t.history = nil
+	}
+	return out
+}
+
+// requeue puts back the trail of a failed write so distance is not lost.
+func (p *LocationPipeline) requeue(w pendingWrite) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t, ok := p.tracks[w.riderID]
+	if !ok {
+		return
+	}
+	t.history = append(w.history, t.history...)
+	if t.latest == nil {
+		latest := w.latest
+		t.latest = &latest
+	}
+}
+
+// Flush writes all buffered locations. It is run by the worker every
+// FlushInterval.
+func (p *LocationPipeline) Flush(ctx context.Context) error {
+	for _, w := range p.takePending(nil) {
+		if err := p.write(ctx, w); err != nil {
+			log.Error().Err(err).Str("rider_id", w.riderID.String()).Msg("flush rider location failed")
+			p.requeue(w)
+		}
+	}
+	return nil
+}
+
+// Disconnect writes whatever the rider still has buffered and forgets them.
+func (p *LocationPipeline) Disconnect(ctx context.Context, riderID uuid.UUID) {
+	for _, w := range p.takePending(&riderID) {
+		if err := p.write(ctx, w); err != nil {
+			log.Error().Err(err).Str("rider_id", w.riderID.String()).Msg("flush rider location failed")
+		}
+	}
+	p.mu.Lock()
+	delete(p.tracks, riderID)
+	p.mu.Unlock()
+}
+
+func (p *LocationPipeline) write(ctx context.Context, w pendingWrite) error {
+	orders, err := p.q.ListActiveOrdersByRider(ctx, sqlc.ListActiveOrdersByRiderParams{
+		RiderID: pgtype.UUID{Bytes: w.riderID, Valid: true}, TenantID: w.tenantID,
+	})
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := p.q.WithTx(tx)
+
+	if _, err := qtx.UpsertRiderLocation(ctx, sqlc.UpsertRiderLocationParams{
+		RiderID:        w.riderID,
+		TenantID:       w.tenantID,
+		GeoLat:         numericFromFloat64(w.latest.Lat),
+		GeoLng:         numericFromFloat64(w.latest.Lng),
+		Heading:        numericFromFloat64(w.latest.Heading),
+		SpeedKmh:       numericFromFloat64(w.latest.SpeedKmh),
+		AccuracyMeters: numericFromFloat64(w.latest.AccuracyM),
+	}); err != nil {
+		return err
+	}
+
+	orderID := trailOrderID(orders)
+	for _, h := range w.history {
+		if _, err := qtx.AppendLocationHistory(ctx, sqlc.AppendLocationHistoryParams{
+			RiderID:            w.riderID,
+			TenantID:           w.tenantID,
+			OrderID:            orderID,
+			GeoLat:             numericFromFloat64(h.fix.Lat),
+			GeoLng:             numericFromFloat64(h.fix.Lng),
+			EventType:          sqlc.RiderSubjectLocationUpdate,
+			DistanceFromPrevKm: numericFromFloat64(h.distanceKm),
+			CreatedAt:          h.fix.At,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	p.publish(ctx, w, orders)
+	return nil
+}
+
+// trailOrderID picks the order a trail point belongs to: the order being
+// carried, otherwise the newest order the rider is heading to collect.
+func trailOrderID(orders []sqlc.Order) pgtype.UUID {
+	for _, o := range orders {
+		if o.Status == sqlc.OrderStatusPicked {
+			return pgtype.UUID{Bytes: o.ID, Valid: true}
+		}
+	}
+	if len(orders) > 0 {
+		return pgtype.UUID{Bytes: orders[0].ID, Valid: true}
+	}
+	return pgtype.UUID{}
+}
+
+func (p *LocationPipeline) publish(ctx context.Context, w pendingWrite, orders []sqlc.Order) {
+	if p.redis == nil {
+		return
+	}
+	locData, _ := json.Marshal(map[string]interface{}{
+		"rider_id": w.riderID,
+		"lat":      w.latest.Lat,
+		"lng":      w.latest.Lng,
+		"heading":  w.latest.Heading,
+		"speed":    w.latest.SpeedKmh,
+	})
+	if err := p.redis.Publish(ctx, "rider:"+w.riderID.String()+":location", string(locData)); err != nil {
+		log.Error().Err(err).Msg("redis publish failed")
+	}
+	if len(orders) == 0 {
+		return
+	}
+
+	pickups, err := p.q.ListRiderPendingPickups(ctx, sqlc.ListRiderPendingPickupsParams{
+		RiderID: pgtype.UUID{Bytes: w.riderID, Valid: true}, TenantID: w.tenantID,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("rider_id", w.riderID.String()).Msg("list pending pickups failed")
+		return
+	}
+	stops := make(map[uuid.UUID][]geoStop, len(orders))
+	for _, pu := range pickups {
+		lat, okLat := numericToFloat64(pu.GeoLat)
+		lng, okLng := numericToFloat64(pu.GeoLng)
+		if okLat && okLng {
+			stops[pu.OrderID] = append(stops[pu.OrderID], geoStop{Lat: lat, Lng: lng})
+		}
+	}
+
+	for _, o := range orders {
+		var drop *geoStop
+		lat, okLat := numericToFloat64(o.DeliveryGeoLat)
+		lng, okLng := numericToFloat64(o.DeliveryGeoLng)
+		if okLat && okLng {
+			drop = &geoStop{Lat: lat, Lng: lng}
+		}
+		eta := estimateOrderEta(geoStop{Lat: w.latest.Lat, Lng: w.latest.Lng}, w.latest.SpeedKmh, o.Status == sqlc.OrderStatusPicked, stops[o.ID], drop)
+
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type":                "rider.location",
+			"order_id":            o.ID,
+			"rider_id":            w.riderID,
+			"lat":                 w.latest.Lat,
+			"lng":                 w.latest.Lng,
+			"heading":             w.latest.Heading,
+			"stage":               eta.Stage,
+			"distance_km":         eta.DistanceKm,
+			"pickup_eta_minutes":  eta.PickupMinutes,
+			"dropoff_eta_minutes": eta.DropoffMinutes,
+			"recorded_at":         w.latest.At,
+		})
+		if err := p.redis.Publish(ctx, "order:"+o.ID.String(), string(payload)); err != nil {
+			log.Error().Err(err).Str("order_id", o.ID.String()).Msg("publish order eta failed")
+		}
+	}
+}
+
+// ---------- ETA ----------
+
+const (
+	// etaRoadFactor converts straight-line distance into road distance.
+	etaRoadFactor = 1.3
+	// etaDefaultSpeedKmh is the average city speed used when the reported
+	// speed is missing or implausible.
+	etaDefaultSpeedKmh = 18.0
+	etaMinSpeedKmh     = 8.0
+	etaMaxSpeedKmh     = 40.0
+)
+
+// ETA stages.
+const (
+	EtaToPickup  = "to_pickup"
+	EtaToDropoff = "to_dropoff"
+)
+
+type geoStop struct {
+	Lat float64
+	Lng float64
+}
+
+// orderEta is the live estimate published to the customer.
+type orderEta struct {
+	Stage          string
+	DistanceKm     float64
+	PickupMinutes  *int
+	DropoffMinutes *int
+}
+
+// travelMinutes estimates travel time over a straight-line distance. Speeds
+// below a crawl (waiting at a signal) or above city traffic are replaced by
+// the average.
+func travelMinutes(distanceKm, speedKmh float64) int {
+	if speedKmh < etaMinSpeedKmh {
+		speedKmh = etaDefaultSpeedKmh
+	}
+	if speedKmh > etaMaxSpeedKmh {
+		speedKmh = etaMaxSpeedKmh
+	}
+	return int(math.Ceil(distanceKm * etaRoadFactor / speedKmh * 60))
+}
+
+// estimateOrderEta routes the rider through the remaining pickups, nearest
+// first, and then to the drop. Before pickup the distance is to the next
+// restaurant; once picked it is to the customer.
+func estimateOrderEta(pos geoStop, speedKmh float64, picked bool, pickups []geoStop, drop *geoStop) orderEta {
+	eta := orderEta{Stage: EtaToDropoff}
+	if !picked {
+		eta.Stage = EtaToPickup
+	}
+
+	here := pos
+	totalKm := 0.0
+	remaining := append([]geoStop(nil), pickups...)
+	if picked {
+		remaining = nil
+	}
+	for i := 0; len(remaining) > 0; i++ {
+		next := 0
+		for j := range remaining {
+			if geo.DistanceKm(here.Lat, here.Lng, remaining[j].Lat, remaining[j].Lng) <
+				geo.DistanceKm(here.Lat, here.Lng, remaining[next].Lat, remaining[next].Lng) {
+				next = j
+			}
+		}
+		km := geo.DistanceKm(here.Lat, here.Lng, remaining[next].Lat, remaining[next].Lng)
+		totalKm += km
+		if i == 0 {
+			minutes := travelMinutes(km, speedKmh)
+			eta.PickupMinutes = &minutes
+			eta.DistanceKm = math.Round(km*100) / 100
+		}
+		here = remaining[next]
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	if drop != nil {
+		km := geo.DistanceKm(here.Lat, here.Lng, drop.Lat, drop.Lng)
+		totalKm += km
+		minutes := travelMinutes(totalKm, speedKmh)
+		eta.DropoffMinutes = &minutes
+		if eta.PickupMinutes == nil {
+			eta.DistanceKm = math.Round(km*100) / 100
+		}
+	}
+	return eta
+}
+
+// ---------- Staleness & retention ----------
+
+// MarkStaleRiders flags on-duty riders whose app stopped reporting, which
+// takes them out of dispatch, and tells the tenant's dashboards.
+func (p *LocationPipeline) MarkStaleRiders(ctx context.Context) error {
+	stale, err := p.q.MarkStaleRiderLocations(ctx, time.Now().Add(-p.cfg.StaleAfter))
+	if err != nil {
+		return err
+	}
+	for _, s := range stale {
+		log.Warn().Str("rider_id", s.RiderID.String()).Time("last_seen_at", s.UpdatedAt).Msg("rider location stale")
+		if p.redis == nil {
+			continue
+		}
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type":         "rider.stale",
+			"rider_id":     s.RiderID,
+			"last_seen_at": s.UpdatedAt,
+		})
+		if err := p.redis.Publish(ctx, "tenant:"+s.TenantID.String(), string(payload)); err != nil {
+			log.Error().Err(err).Msg("redis publish failed")
+		}
+	}
+	return nil
+}
+
+// PurgeLocationHistory deletes trail points older than the retention period.
+func (p *LocationPipeline) PurgeLocationHistory(ctx context.Context) error {
+	cutoff := time.Now().Add(-p.cfg.HistoryRetention)
+	var total int64
+	for i := 0; i < historyPurgeMaxBatches; i++ {
+		n, err := p.q.DeleteLocationHistoryBefore(ctx, sqlc.DeleteLocationHistoryBeforeParams{
+			CreatedAt: cutoff, Limit: historyPurgeBatch,
+		})
+		if err != nil {
+			return err
+		}
+		total += n
+		if n < historyPurgeBatch {
+			break
+		}
+	}
+	if total > 0 {
+		log.Info().Int64("rows", total).Msg("purged rider location history")
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package rider
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckFix(t *testing.T) {
+	cfg := LocationConfig{}.withDefaults()
+	now := time.Now()
+	prev := &locationFix{Lat: 23.7925, Lng: 90.4078, At: now}
+	at := func(lat, lng, accuracy float64, after time.Duration) locationFix {
+		return locationFix{Lat: lat, Lng: lng, AccuracyM: accuracy, At: now.Add(after)}
+	}
+
+	tests := []struct {
+		name string
+		prev *locationFix
+		next locationFix
+		want string
+	}{
+		{"first fix", nil, at(23.7925, 90.4078, 10, 0), ""},
+		{"null island", nil, at(0, 0, 10, 0), FixInvalid},
+		{"out of range", nil, at(95, 90.4078, 10, 0), FixInvalid},
+		{"poor accuracy", prev, at(23.7926, 90.4078, 250, 5*time.Second), FixInaccurate},
+		{"normal riding", prev, at(23.7930, 90.4078, 10, 5*time.Second), ""},
+		// About 1.1km in 5 seconds.
+		{"teleport", prev, at(23.8025, 90.4078, 10, 5*time.Second), FixJump},
+		{"far after a long silence", prev, at(23.8025, 90.4078, 10, 10*time.Minute), ""},
+		{"older than the last fix", prev, at(23.7925, 90.4078, 10, -time.Second), FixOutOfOrder},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := checkFix(tc.prev, tc.next, cfg); got != tc.want {
+				t.Errorf("checkFix = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNextHistoryPoint(t *testing.T) {
+	cfg := LocationConfig{}.withDefaults()
+	now := time.Now()
+	last := &historyPoint{fix: locationFix{Lat: 23.7925, Lng: 90.4078, At: now}}
+
+	// About 11m away: GPS jitter, not worth a point yet.
+	if _, ok := nextHistoryPoint(last, locationFix{Lat: 23.7926, Lng: 90.4078, AccuracyM: 8, At: now.Add(5 * time.Second)}, cfg); ok {
+		t.Error("a small move inside the interval should be skipped")
+	}
+
+	// About 55m away.
+	p, ok := nextHistoryPoint(last, locationFix{Lat: 23.7930, Lng: 90.4078, AccuracyM: 8, At: now.Add(10 * time.Second)}, cfg)
+	if !ok || p.distanceKm < 0.05 || p.distanceKm > 0.06 {
+		t.Errorf("move = %+v (%v), want a point about 55m on", p, ok)
+	}
+
+	// 55m with 80m accuracy is within the error circle.
+	if _, ok := nextHistoryPoint(last, locationFix{Lat: 23.7930, Lng: 90.4078, AccuracyM: 80, At: now.Add(10 * time.Second)}, cfg); ok {
+		t.Error("a move smaller than the fix accuracy should be skipped")
+	}
+
+	// Parked past the interval: a heartbeat at the old position, no distance.
+	p, ok = nextHistoryPoint(last, locationFix{Lat: 23.7926, Lng: 90.4078, At: now.Add(45 * time.Second)}, cfg)
+	if !ok || p.distanceKm != 0 || p.fix.Lat != last.fix.Lat || !p.fix.At.Equal(now.Add(45*time.Second)) {
+		t.Errorf("heartbeat = %+v (%v), want the old position with no distance", p, ok)
+	}
+}
+
+func TestTravelMinutes(t *testing.T) {
+	// 3km straight line is 3.9km by road; at 18km/h that is 13 minutes.
+	if got := travelMinutes(3, 0); got != 13 {
+		t.Errorf("stopped rider = %d, want 13 at the default speed", got)
+	}
+	if got := travelMinutes(3, 30); got != 8 {
+		t.Errorf("30km/h = %d, want 8", got)
+	}
+	if got := travelMinutes(3, 90); got != 6 {
+		t.Errorf("90km/h = %d, want 6 capped at 40km/h", got)
+	}
+}
+
+func TestEstimateOrderEta(t *testing.T) {
+	pos := geoStop{Lat: 23.7800, Lng: 90.4000}
+	near := geoStop{Lat: 23.7850, Lng: 90.4000}
+	far := geoStop{Lat: 23.8000, Lng: 90.4000}
+	drop := &geoStop{Lat: 23.8100, Lng: 90.4000}
+
+	eta := estimateOrderEta(pos, 0, false, []geoStop{far, near}, drop)
+	if eta.Stage != EtaToPickup || eta.PickupMinutes == nil || eta.DropoffMinutes == nil {
+		t.Fatalf("before pickup = %+v, want both ETAs", eta)
+	}
+	// The nearest restaurant is visited first: about 0.56km away.
+	if eta.DistanceKm < 0.5 || eta.DistanceKm > 0.6 || *eta.PickupMinutes != 3 {
+		t.Errorf("pickup leg = %.2fkm in %d min, want the nearer restaurant", eta.DistanceKm, *eta.PickupMinutes)
+	}
+	// 3.34km straight through both restaurants to the drop.
+	if *eta.DropoffMinutes != travelMinutes(3.3358, 0) {
+		t.Errorf("dropoff = %d min, want the whole route", *eta.DropoffMinutes)
+	}
+
+	eta = estimateOrderEta(far, 25, true, []geoStop{near}, drop)
+	if eta.Stage != EtaToDropoff || eta.PickupMinutes != nil || eta.DropoffMinutes == nil {
+		t.Fatalf("after pickup = %+v, want only a dropoff ETA", eta)
+	}
+	if eta.DistanceKm < 1.1 || eta.DistanceKm > 1.12 {
+		t.Errorf("dropoff distance = %.2f, want about 1.11km", eta.DistanceKm)
+	}
+
+	if eta := estimateOrderEta(pos, 0, true, nil, nil); eta.DropoffMinutes != nil {
+		t.Error("an order without coordinates should have no ETA")
+	}
+}
+
+func TestFixTime(t *testing.T) {
+	now := time.Now()
+	ms := func(d time.Duration) *int64 { v := now.Add(d).UnixMilli(); return &v }
+
+	if got := fixTime(nil, now); !got.Equal(now) {
+		t.Errorf("no timestamp = %s, want now", got)
+	}
+	if got := fixTime(ms(-time.Minute), now); got.UnixMilli() != *ms(-time.Minute) {
+		t.Errorf("buffered fix = %s, want its own time", got)
+	}
+	if got := fixTime(ms(time.Minute), now); !got.Equal(now) {
+		t.Errorf("future timestamp = %s, want now", got)
+	}
+	if got := fixTime(ms(-time.Hour), now); !got.Equal(now) {
+		t.Errorf("ancient timestamp = %s, want now", got)
+	}
+}
@@ -0,0 +1,771 @@
+package rider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+)
+
+// Rider verification states. Only verified riders can check in.
+const (
+	VerificationUnverified = "unverified"
+	VerificationVerified   = "verified"
+	VerificationExpired    = "expired"
+)
+
+// Application states.
+const (
+	ApplicationSubmitted = "submitted"
+	ApplicationApproved  = "approved"
+	ApplicationRejected  = "rejected"
+)
+
+// Document states. An approved document is superseded when a renewal of the
+// same type is approved.
+const (
+	DocumentPending    = "pending"
+	DocumentApproved   = "approved"
+	DocumentRejected   = "rejected"
+	DocumentExpired    = "expired"
+	DocumentSuperseded = "superseded"
+)
+
+// Document types a rider can upload.
+const (
+	DocNIDFront            = "nid_front"
+	DocNIDBack             = "nid_back"
+	DocDrivingLicence      = "driving_licence"
+	DocVehicleRegistration = "vehicle_registration"
+)
+
+const (
+	// documentReminderHorizon is how far ahead the expiry job looks.
+	documentReminderHorizon = 30
+	documentExpiryBatch     = 500
+)
+
+// documentReminderDays are the days before expiry on which a rider is
+// reminded to upload a renewal, furthest first.
+var documentReminderDays = []int{30, 7, 1}
+
+// requiredDocuments lists the documents a rider with the given vehicle must
+// hold. Motor vehicles need a licence and a registration on top of the NID.
+func requiredDocuments(vehicle sqlc.VehicleType) []string {
+	docs := []string{DocNIDFront, DocNIDBack}
+	if vehicle == sqlc.VehicleTypeMotorcycle || vehicle == sqlc.VehicleTypeCar {
+		docs = append(docs, DocDrivingLicence, DocVehicleRegistration)
+	}
+	return docs
+}
+
+// documentExpires reports whether a document type carries an expiry date.
+func documentExpires(docType string) bool {
+	return docType == DocDrivingLicence || docType == DocVehicleRegistration
+}
+
+// missingDocuments returns the required types not present in have.
+func missingDocuments(vehicle sqlc.VehicleType, have []string) []string {
+	held := make(map[string]bool, len(have))
+	for _, t := range have {
+		held[t] = true
+	}
+	var missing []string
+	for _, t := range requiredDocuments(vehicle) {
+		if !held[t] {
+			missing = append(missing, t)
+		}
+	}
+	return missing
+}
+
+// reminderDue returns the reminder threshold a document expiring in
+// daysLeft days has reached, and whether a reminder for it is still unsent.
+func reminderDue(daysLeft int, remindedDays *int32) (int, bool) {
+	threshold := -1
+	for _, d := range documentReminderDays {
+		if daysLeft <= d {
+			threshold = d
+		}
+	}
+	if threshold < 0 {
+		return 0, false
+	}
+	if remindedDays != nil && int(*remindedDays) <= threshold {
+		return threshold, false
+	}
+	return threshold, true
+}
+
+// daysUntil counts whole days from today to a date. Both are calendar days
+// in Asia/Dhaka.
+func daysUntil(today time.Time, d pgtype.Date) int {
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(d.Time.Year(), d.Time.Month(), d.Time.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// checkVerified reports why a rider cannot go on duty, if they cannot.
+func checkVerified(rider sqlc.Rider) error {
+	switch rider.VerificationStatus {
+	case VerificationVerified:
+		return nil
+	case VerificationExpired:
+		return apperror.Forbidden("a required document has expired; upload a renewal before checking in")
+	default:
+		return apperror.Forbidden("rider documents have not been verified yet")
+	}
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// DocumentInput is one uploaded document.
+type DocumentInput struct {
+	Type      string
+	FileURL   string
+	Number    string
+	ExpiresOn *time.Time
+}
+
+// ApplicationInput is a rider application submitted from the rider app.
+type ApplicationInput struct {
+	HubID               uuid.UUID
+	VehicleType         sqlc.VehicleType
+	VehicleRegistration string
+	LicenseNumber       string
+	NidNumber           string
+	Documents           []DocumentInput
+}
+
+func validateDocument(d DocumentInput, today time.Time) error {
+	switch d.Type {
+	case DocNIDFront, DocNIDBack, DocDrivingLicence, DocVehicleRegistration:
+	default:
+		return apperror.BadRequest("unknown document type " + d.Type)
+	}
+	if !isHTTPURL(d.FileURL) {
+		return apperror.BadRequest("file_url must be an http(s) link")
+	}
+	if documentExpires(d.Type) {
+		if d.ExpiresOn == nil {
+			return apperror.BadRequest(d.Type + " requires expires_on")
+		}
+		if daysUntil(today, pgDate(*d.ExpiresOn)) < 0 {
+			return apperror.BadRequest(d.Type + " has already expired")
+		}
+	}
+	return nil
+}
+
+func validateApplication(in ApplicationInput, today time.Time) error {
+	switch in.VehicleType {
+	case sqlc.VehicleTypeBicycle, sqlc.VehicleTypeMotorcycle, sqlc.VehicleTypeCar:
+	default:
+		return apperror.BadRequest("vehicle_type must be bicycle, motorcycle or car")
+	}
+	if strings.TrimSpace(in.NidNumber) == "" {
+		return apperror.BadRequest("nid_number is required")
+	}
+	motor := in.VehicleType != sqlc.VehicleTypeBicycle
+	if motor && strings.TrimSpace(in.LicenseNumber) == "" {
+		return apperror.BadRequest("license_number is required for motor vehicles")
+	}
+	if motor && strings.TrimSpace(in.VehicleRegistration) == "" {
+		return apperror.BadRequest("vehicle_registration is required for motor vehicles")
+	}
+
+	types := make([]string, 0, len(in.Documents))
+	seen := make(map[string]bool, len(in.Documents))
+	for _, d := range in.Documents {
+		if err := validateDocument(d, today); err != nil {
+			return err
+		}
+		if seen[d.Type] {
+			return apperror.BadRequest("duplicate document " + d.Type)
+		}
+		seen[d.Type] = true
+		types = append(types, d.Type)
+	}
+	if missing := missingDocuments(in.VehicleType, types); len(missing) > 0 {
+		return apperror.BadRequest("missing documents: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func documentParams(tenantID, userID uuid.UUID, riderID, applicationID pgtype.UUID, d DocumentInput) sqlc.CreateRiderDocumentParams {
+	p := sqlc.CreateRiderDocumentParams{
+		TenantID:       tenantID,
+		UserID:         userID,
+		RiderID:        riderID,
+		ApplicationID:  applicationID,
+		DocType:        d.Type,
+		FileUrl:        d.FileURL,
+		DocumentNumber: nullString(strings.TrimSpace(d.Number)),
+	}
+	if d.ExpiresOn != nil {
+		p.ExpiresOn = pgDate(*d.ExpiresOn)
+	}
+	return p
+}
+
+// ApplicationView is an application with the documents submitted with it.
+type ApplicationView struct {
+	sqlc.RiderApplication
+	Documents []sqlc.RiderDocument `json:"documents"`
+}
+
+// OnboardingStatus is what the rider app shows on the onboarding screen.
+type OnboardingStatus struct {
+	VerificationStatus string               `json:"verification_status"`
+	RiderID            *uuid.UUID           `json:"rider_id,omitempty"`
+	Application        *ApplicationView     `json:"application,omitempty"`
+	Documents          []sqlc.RiderDocument `json:"documents"`
+}
+
+// SubmitApplication files a rider application with its documents for review
+// by the manager of the chosen hub. A user can have one open application.
+func (s *Service) SubmitApplication(ctx context.Context, userID, tenantID uuid.UUID, in ApplicationInput) (ApplicationView, error) {
+	today := timeutil.StartOfDayBD(time.Now())
+	if err := validateApplication(in, today); err != nil {
+		return ApplicationView{}, err
+	}
+
+	hub, err := s.q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: in.HubID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ApplicationView{}, apperror.NotFound("hub")
+	}
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("get hub", err)
+	}
+	if !hub.IsActive {
+		return ApplicationView{}, apperror.BadRequest("hub is not active")
+	}
+
+	riderID := pgtype.UUID{}
+	rider, err := s.q.GetRiderByUserID(ctx, sqlc.GetRiderByUserIDParams{UserID: userID, TenantID: tenantID})
+	switch {
+	case err == nil:
+		if rider.VerificationStatus == VerificationVerified {
+			return ApplicationView{}, apperror.Conflict("rider is already verified")
+		}
+		riderID = pgtype.UUID{Bytes: rider.ID, Valid: true}
+	case !errors.Is(err, pgx.ErrNoRows):
+		return ApplicationView{}, apperror.Internal("get rider by user", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	app, err := qtx.CreateRiderApplication(ctx, sqlc.CreateRiderApplicationParams{
+		TenantID:            tenantID,
+		UserID:              userID,
+		RiderID:             riderID,
+		HubID:               in.HubID,
+		VehicleType:         in.VehicleType,
+		VehicleRegistration: nullString(strings.TrimSpace(in.VehicleRegistration)),
+		LicenseNumber:       nullString(strings.TrimSpace(in.LicenseNumber)),
+		NidNumber:           strings.TrimSpace(in.NidNumber),
+	})
+	if isUniqueViolation(err) {
+		return ApplicationView{}, apperror.Conflict("an application is already under review")
+	}
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("create application", err)
+	}
+
+	view := ApplicationView{RiderApplication: app, Documents: make([]sqlc.RiderDocument, 0, len(in.Documents))}
+	appID := pgtype.UUID{Bytes: app.ID, Valid: true}
+	for _, d := range in.Documents {
+		doc, err := qtx.CreateRiderDocument(ctx, documentParams(tenantID, userID, riderID, appID, d))
+		if err != nil {
+			return ApplicationView{}, apperror.Internal("create document", err)
+		}
+		view.Documents = append(view.Documents, doc)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ApplicationView{}, apperror.Internal("commit tx", err)
+	}
+	return view, nil
+}
+
+// GetOnboardingStatus returns the caller's verification status, latest
+// application and documents.
+func (s *Service) GetOnboardingStatus(ctx context.Context, userID, tenantID uuid.UUID) (OnboardingStatus, error) {
+	status := OnboardingStatus{VerificationStatus: VerificationUnverified}
+
+	rider, err := s.q.GetRiderByUserID(ctx, sqlc.GetRiderByUserIDParams{UserID: userID, TenantID: tenantID})
+	switch {
+	case err == nil:
+		status.VerificationStatus = rider.VerificationStatus
+		status.RiderID = &rider.ID
+	case !errors.Is(err, pgx.ErrNoRows):
+		return OnboardingStatus{}, apperror.Internal("get rider by user", err)
+	}
+
+	app, err := s.q.GetLatestRiderApplication(ctx, sqlc.GetLatestRiderApplicationParams{UserID: userID, TenantID: tenantID})
+	switch {
+	case err == nil:
+		view, err := s.applicationView(ctx, app)
+		if err != nil {
+			return OnboardingStatus{}, err
+		}
+		status.Application = &view
+	case !errors.Is(err, pgx.ErrNoRows):
+		return OnboardingStatus{}, apperror.Internal("get latest application", err)
+	}
+
+	docs, err := s.q.ListUserRiderDocuments(ctx, sqlc.ListUserRiderDocumentsParams{UserID: userID, TenantID: tenantID})
+	if err != nil {
+		return OnboardingStatus{}, apperror.Internal("list documents", err)
+	}
+	status.Documents = docs
+	return status, nil
+}
+
+func (s *Service) applicationView(ctx context.Context, app sqlc.RiderApplication) (ApplicationView, error) {
+	docs, err := s.q.ListApplicationDocuments(ctx, pgtype.UUID{Bytes: app.ID, Valid: true})
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("list application documents", err)
+	}
+	return ApplicationView{RiderApplication: app, Documents: docs}, nil
+}
+
+// ListApplications returns the review queue, oldest first. The status filter
+// defaults to submitted. Users other than tenant owners and admins must name
+// a hub they manage.
+func (s *Service) ListApplications(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, status string, limit, offset int32) ([]sqlc.ListRiderApplicationsRow, error) {
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = ApplicationSubmitted
+	}
+	apps, err := s.q.ListRiderApplications(ctx, sqlc.ListRiderApplicationsParams{
+		TenantID: tenantID,
+		HubID:    hub,
+		Status:   status,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list applications", err)
+	}
+	return apps, nil
+}
+
+func (s *Service) getApplication(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) (sqlc.RiderApplication, error) {
+	app, err := s.q.GetRiderApplication(ctx, sqlc.GetRiderApplicationParams{ID: id, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderApplication{}, apperror.NotFound("application")
+	}
+	if err != nil {
+		return sqlc.RiderApplication{}, apperror.Internal("get application", err)
+	}
+	if err := authorizeHubManager(ctx, s.q, user, tenantID, app.HubID); err != nil {
+		return sqlc.RiderApplication{}, err
+	}
+	return app, nil
+}
+
+// GetApplication returns an application with its documents for review.
+func (s *Service) GetApplication(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) (ApplicationView, error) {
+	app, err := s.getApplication(ctx, user, tenantID, id)
+	if err != nil {
+		return ApplicationView{}, err
+	}
+	return s.applicationView(ctx, app)
+}
+
+// ReviewApplication approves or rejects an application. Approval creates the
+// rider profile, or updates the existing one, from the application and marks
+// the rider verified. A rejection must give a reason.
+func (s *Service) ReviewApplication(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID, approve bool, reason string) (ApplicationView, error) {
+	reason = strings.TrimSpace(reason)
+	if !approve && reason == "" {
+		return ApplicationView{}, apperror.BadRequest("reason is required")
+	}
+	app, err := s.getApplication(ctx, user, tenantID, id)
+	if err != nil {
+		return ApplicationView{}, err
+	}
+	if app.Status != ApplicationSubmitted {
+		return ApplicationView{}, apperror.Conflict("application is already " + app.Status)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	reviewer := pgtype.UUID{Bytes: user.ID, Valid: true}
+	appID := pgtype.UUID{Bytes: app.ID, Valid: true}
+	riderID := app.RiderID
+	status, docStatus := ApplicationRejected, DocumentRejected
+	if approve {
+		status, docStatus = ApplicationApproved, DocumentApproved
+		rider, err := s.upsertApplicantRider(ctx, qtx, app)
+		if err != nil {
+			return ApplicationView{}, err
+		}
+		if _, err := qtx.VerifyRider(ctx, sqlc.VerifyRiderParams{ID: rider.ID, TenantID: tenantID, VerifiedBy: reviewer}); err != nil {
+			return ApplicationView{}, apperror.Internal("verify rider", err)
+		}
+		riderID = pgtype.UUID{Bytes: rider.ID, Valid: true}
+	}
+
+	if err := qtx.ReviewApplicationDocuments(ctx, sqlc.ReviewApplicationDocumentsParams{
+		Status:        docStatus,
+		RiderID:       riderID,
+		ReviewedBy:    reviewer,
+		ApplicationID: appID,
+	}); err != nil {
+		return ApplicationView{}, apperror.Internal("review application documents", err)
+	}
+	updated, err := qtx.ReviewRiderApplication(ctx, sqlc.ReviewRiderApplicationParams{
+		Status:          status,
+		RejectionReason: nullString(reason),
+		RiderID:         riderID,
+		ReviewedBy:      reviewer,
+		ID:              app.ID,
+		TenantID:        tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ApplicationView{}, apperror.Conflict("application has already been reviewed")
+	}
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("review application", err)
+	}
+
+	view := ApplicationView{RiderApplication: updated}
+	view.Documents, err = qtx.ListApplicationDocuments(ctx, appID)
+	if err != nil {
+		return ApplicationView{}, apperror.Internal("list application documents", err)
+	}
+	// A re-application replaces whatever the rider held before.
+	if approve {
+		for _, doc := range view.Documents {
+			if err := qtx.SupersedeRiderDocuments(ctx, sqlc.SupersedeRiderDocumentsParams{
+				RiderID: riderID, DocType: doc.DocType, KeepID: doc.ID,
+			}); err != nil {
+				return ApplicationView{}, apperror.Internal("supersede documents", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ApplicationView{}, apperror.Internal("commit tx", err)
+	}
+
+	title, body := "Application approved", "Your documents are verified. You can now check in for shifts."
+	if !approve {
+		title, body = "Application not approved", reason
+	}
+	s.notify(ctx, tenantID, app.UserID, title, body, map[string]string{
+		"action_type":    "rider_application_" + status,
+		"application_id": app.ID.String(),
+	})
+	return view, nil
+}
+
+// upsertApplicantRider creates the applicant's rider profile, or brings an
+// existing one in line with the approved application.
+func (s *Service) upsertApplicantRider(ctx context.Context, qtx *sqlc.Queries, app sqlc.RiderApplication) (sqlc.Rider, error) {
+	hubID := pgtype.UUID{Bytes: app.HubID, Valid: true}
+	rider, err := qtx.GetRiderByUserID(ctx, sqlc.GetRiderByUserIDParams{UserID: app.UserID, TenantID: app.TenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		rider, err = qtx.CreateRider(ctx, sqlc.CreateRiderParams{
+			TenantID:            app.TenantID,
+			UserID:              app.UserID,
+			HubID:               hubID,
+			VehicleType:         app.VehicleType,
+			VehicleRegistration: app.VehicleRegistration,
+			LicenseNumber:       app.LicenseNumber,
+			NidNumber:           nullString(app.NidNumber),
+		})
+		if err != nil {
+			return sqlc.Rider{}, apperror.Internal("create rider", err)
+		}
+		return rider, nil
+	}
+	if err != nil {
+		return sqlc.Rider{}, apperror.Internal("get rider by user", err)
+	}
+
+	rider, err = qtx.UpdateRider(ctx, sqlc.UpdateRiderParams{
+		ID:                  rider.ID,
+		TenantID:            app.TenantID,
+		HubID:               hubID,
+		VehicleType:         sqlc.NullVehicleType{VehicleType: app.VehicleType, Valid: true},
+		VehicleRegistration: app.VehicleRegistration,
+		LicenseNumber:       app.LicenseNumber,
+		NidNumber:           nullString(app.NidNumber),
+	})
+	if err != nil {
+		return sqlc.Rider{}, apperror.Internal("update rider", err)
+	}
+	return rider, nil
+}
+
+// UploadDocument files a renewal or replacement document for an existing
+// rider. It stays pending until the hub manager approves it.
+func (s *Service) UploadDocument(ctx context.Context, rider sqlc.Rider, in DocumentInput) (sqlc.RiderDocument, error) {
+	if err := validateDocument(in, timeutil.StartOfDayBD(time.Now())); err != nil {
+		return sqlc.RiderDocument{}, err
+	}
+	if rider.VerificationStatus == VerificationUnverified {
+		return sqlc.RiderDocument{}, apperror.BadRequest("submit an onboarding application first")
+	}
+	doc, err := s.q.CreateRiderDocument(ctx, documentParams(rider.TenantID, rider.UserID, pgtype.UUID{Bytes: rider.ID, Valid: true}, pgtype.UUID{}, in))
+	if err != nil {
+		return sqlc.RiderDocument{}, apperror.Internal("create document", err)
+	}
+	return doc, nil
+}
+
+// ListDocumentRenewals returns renewals uploaded outside an application,
+// oldest first. The status filter defaults to pending.
+func (s *Service) ListDocumentRenewals(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, status string, limit, offset int32) ([]sqlc.ListRiderDocumentRenewalsRow, error) {
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = DocumentPending
+	}
+	docs, err := s.q.ListRiderDocumentRenewals(ctx, sqlc.ListRiderDocumentRenewalsParams{
+		TenantID: tenantID,
+		Status:   status,
+		HubID:    hub,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list document renewals", err)
+	}
+	return docs, nil
+}
+
+// ReviewDocument approves or rejects a renewal. An approved renewal replaces
+// the rider's earlier document of the same type, and a rider whose
+// verification lapsed is verified again once every required document is
+// valid.
+func (s *Service) ReviewDocument(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID, approve bool, note string) (sqlc.RiderDocument, error) {
+	note = strings.TrimSpace(note)
+	if !approve && note == "" {
+		return sqlc.RiderDocument{}, apperror.BadRequest("reason is required")
+	}
+	doc, err := s.q.GetRiderDocument(ctx, sqlc.GetRiderDocumentParams{ID: id, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderDocument{}, apperror.NotFound("document")
+	}
+	if err != nil {
+		return sqlc.RiderDocument{}, apperror.Internal("get document", err)
+	}
+	if doc.ApplicationID.Valid || !doc.RiderID.Valid {
+		return sqlc.RiderDocument{}, apperror.BadRequest("document is reviewed with its application")
+	}
+	rider, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: uuid.UUID(doc.RiderID.Bytes), TenantID: tenantID})
+	if err != nil {
+		return sqlc.RiderDocument{}, apperror.Internal("get rider", err)
+	}
+	if err := authorizeRiderManager(ctx, s.q, user, rider); err != nil {
+		return sqlc.RiderDocument{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderDocument{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	status := DocumentRejected
+	if approve {
+		status = DocumentApproved
+	}
+	reviewer := pgtype.UUID{Bytes: user.ID, Valid: true}
+	updated, err := qtx.ReviewRiderDocument(ctx, sqlc.ReviewRiderDocumentParams{
+		Status:     status,
+		ReviewNote: nullString(note),
+		ReviewedBy: reviewer,
+		ID:         id,
+		TenantID:   tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderDocument{}, apperror.Conflict("document is not pending review")
+	}
+	if err != nil {
+		return sqlc.RiderDocument{}, apperror.Internal("review document", err)
+	}
+
+	reverified := false
+	if approve {
+		if err := qtx.SupersedeRiderDocuments(ctx, sqlc.SupersedeRiderDocumentsParams{
+			RiderID: doc.RiderID, DocType: doc.DocType, KeepID: doc.ID,
+		}); err != nil {
+			return sqlc.RiderDocument{}, apperror.Internal("supersede documents", err)
+		}
+		if rider.VerificationStatus == VerificationExpired {
+			valid, err := qtx.ListValidRiderDocumentTypes(ctx, sqlc.ListValidRiderDocumentTypesParams{
+				RiderID: doc.RiderID,
+				Today:   pgDate(timeutil.StartOfDayBD(time.Now())),
+			})
+			if err != nil {
+				return sqlc.RiderDocument{}, apperror.Internal("list valid documents", err)
+			}
+			if len(missingDocuments(rider.VehicleType, valid)) == 0 {
+				if _, err := qtx.VerifyRider(ctx, sqlc.VerifyRiderParams{ID: rider.ID, TenantID: tenantID, VerifiedBy: reviewer}); err != nil {
+					return sqlc.RiderDocument{}, apperror.Internal("verify rider", err)
+				}
+				reverified = true
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderDocument{}, apperror.Internal("commit tx", err)
+	}
+
+	title, body := "Document approved", "Your "+documentLabel(doc.DocType)+" has been approved."
+	if reverified {
+		body += " You can check in again."
+	}
+	if !approve {
+		title, body = "Document rejected", note
+	}
+	s.notify(ctx, tenantID, doc.UserID, title, body, map[string]string{
+		"action_type": "rider_document_" + status,
+		"document_id": doc.ID.String(),
+	})
+	return updated, nil
+}
+
+// ListRiderDocuments returns every document a rider has uploaded, newest
+// first.
+func (s *Service) ListRiderDocuments(ctx context.Context, user *sqlc.User, tenantID, riderID uuid.UUID) ([]sqlc.RiderDocument, error) {
+	rider, err := s.GetRider(ctx, riderID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRiderManager(ctx, s.q, user, rider); err != nil {
+		return nil, err
+	}
+	docs, err := s.q.ListUserRiderDocuments(ctx, sqlc.ListUserRiderDocumentsParams{UserID: rider.UserID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list documents", err)
+	}
+	return docs, nil
+}
+
+// RunDocumentExpiry reminds riders of documents nearing expiry and expires
+// lapsed ones. A rider whose required document lapses can no longer check in
+// until a renewal is approved. Reminders are recorded per threshold, so the
+// job is safe to re-run.
+func (s *Service) RunDocumentExpiry(ctx context.Context) error {
+	today := timeutil.StartOfDayBD(time.Now())
+	docs, err := s.q.ListExpiringRiderDocuments(ctx, sqlc.ListExpiringRiderDocumentsParams{
+		Until: pgDate(today.AddDate(0, 0, documentReminderHorizon)),
+		Limit: documentExpiryBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("list expiring documents: %w", err)
+	}
+
+	for _, doc := range docs {
+		data := map[string]string{
+			"action_type": "rider_document_expiry",
+			"document_id": doc.ID.String(),
+			"doc_type":    doc.DocType,
+		}
+		left := daysUntil(today, doc.ExpiresOn)
+		if left >= 0 {
+			threshold, due := reminderDue(left, doc.RemindedDays)
+			if !due {
+				continue
+			}
+			days := int32(threshold)
+			if err := s.q.MarkRiderDocumentReminded(ctx, sqlc.MarkRiderDocumentRemindedParams{ID: doc.ID, RemindedDays: &days}); err != nil {
+				log.Error().Err(err).Str("document_id", doc.ID.String()).Msg("mark document reminded failed")
+				continue
+			}
+			s.notify(ctx, doc.TenantID, doc.UserID, "Document expiring soon",
+				fmt.Sprintf("Your %s expires on %s. Upload a renewal to keep riding.", documentLabel(doc.DocType), doc.ExpiresOn.Time.Format("2 Jan 2006")), data)
+			continue
+		}
+
+		if err := s.expireDocument(ctx, doc, data); err != nil {
+			log.Error().Err(err).Str("document_id", doc.ID.String()).Msg("expire rider document failed")
+		}
+	}
+	return nil
+}
+
+func (s *Service) expireDocument(ctx context.Context, doc sqlc.RiderDocument, data map[string]string) error {
+	n, err := s.q.ExpireRiderDocument(ctx, doc.ID)
+	if err != nil {
+		return fmt.Errorf("expire document: %w", err)
+	}
+	if n == 0 {
+		return nil
+	}
+	rider, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: uuid.UUID(doc.RiderID.Bytes), TenantID: doc.TenantID})
+	if err != nil {
+		return fmt.Errorf("get rider: %w", err)
+	}
+	required := false
+	for _, t := range requiredDocuments(rider.VehicleType) {
+		required = required || t == doc.DocType
+	}
+	if required {
+		if _, err := s.q.ExpireRiderVerification(ctx, rider.ID); err != nil {
+			return fmt.Errorf("expire rider verification: %w", err)
+		}
+	}
+	s.notify(ctx, doc.TenantID, doc.UserID, "Document expired",
+		fmt.Sprintf("Your %s has expired. Upload a renewal before your next shift.", documentLabel(doc.DocType)), data)
+	return nil
+}
+
+func documentLabel(docType string) string {
+	switch docType {
+	case DocNIDFront:
+		return "NID (front)"
+	case DocNIDBack:
+		return "NID (back)"
+	case DocDrivingLicence:
+		return "driving licence"
+	case DocVehicleRegistration:
+		return "vehicle registration"
+	}
+	return docType
+}
+
+func (s *Service) notify(ctx context.Context, tenantID, userID uuid.UUID, title, body string, data map[string]string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendPush(ctx, &tenantID, userID, title, body, data); err != nil {
+		log.Warn().Err(err).Str("user_id", userID.String()).Msg("rider push failed")
+	}
+}
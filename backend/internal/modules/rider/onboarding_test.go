@@ -0,0 +1,103 @@
+package rider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestMissingDocuments(t *testing.T) {
+	if got := missingDocuments(sqlc.VehicleTypeBicycle, []string{DocNIDFront, DocNIDBack}); len(got) != 0 {
+		t.Errorf("bicycle with NID = %v, want nothing missing", got)
+	}
+	got := missingDocuments(sqlc.VehicleTypeMotorcycle, []string{DocNIDFront, DocNIDBack, DocDrivingLicence})
+	if len(got) != 1 || got[0] != DocVehicleRegistration {
+		t.Errorf("motorcycle without registration = %v, want the registration", got)
+	}
+}
+
+func TestReminderDue(t *testing.T) {
+	i32 := func(v int32) *int32 { return &v }
+	tests := []struct {
+		name     string
+		daysLeft int
+		reminded *int32
+		want     int
+		wantDue  bool
+	}{
+		{"too far out", 45, nil, 0, false},
+		{"first reminder", 20, nil, 30, true},
+		{"already reminded at 30", 20, i32(30), 30, false},
+		{"week out after the first", 7, i32(30), 7, true},
+		{"reminder missed while the job was down", 3, nil, 7, true},
+		{"expires today", 0, i32(7), 1, true},
+		{"last reminder sent", 1, i32(1), 1, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, due := reminderDue(tc.daysLeft, tc.reminded)
+			if got != tc.want || due != tc.wantDue {
+				t.Errorf("reminderDue = %d %v, want %d %v", got, due, tc.want, tc.wantDue)
+			}
+		})
+	}
+}
+
+func TestValidateApplication(t *testing.T) {
+	today := bdTime(t, "2026-03-02 00:00")
+	expiry := today.AddDate(1, 0, 0)
+	lapsed := today.AddDate(0, 0, -1)
+	docs := func(extra ...DocumentInput) []DocumentInput {
+		return append([]DocumentInput{
+			{Type: DocNIDFront, FileURL: "https://cdn.example.com/front.jpg"},
+			{Type: DocNIDBack, FileURL: "https://cdn.example.com/back.jpg"},
+		}, extra...)
+	}
+	motorDocs := docs(
+		DocumentInput{Type: DocDrivingLicence, FileURL: "https://cdn.example.com/dl.jpg", ExpiresOn: &expiry},
+		DocumentInput{Type: DocVehicleRegistration, FileURL: "https://cdn.example.com/reg.jpg", ExpiresOn: &expiry},
+	)
+
+	tests := []struct {
+		name    string
+		in      ApplicationInput
+		wantErr bool
+	}{
+		{"bicycle", ApplicationInput{VehicleType: sqlc.VehicleTypeBicycle, NidNumber: "123", Documents: docs()}, false},
+		{"motorcycle", ApplicationInput{VehicleType: sqlc.VehicleTypeMotorcycle, NidNumber: "123", LicenseNumber: "DL1", VehicleRegistration: "DHAKA-1", Documents: motorDocs}, false},
+		{"missing NID number", ApplicationInput{VehicleType: sqlc.VehicleTypeBicycle, Documents: docs()}, true},
+		{"motorcycle without licence", ApplicationInput{VehicleType: sqlc.VehicleTypeMotorcycle, NidNumber: "123", LicenseNumber: "DL1", VehicleRegistration: "DHAKA-1", Documents: docs()}, true},
+		{"licence without expiry", ApplicationInput{VehicleType: sqlc.VehicleTypeBicycle, NidNumber: "123", Documents: docs(DocumentInput{Type: DocDrivingLicence, FileURL: "https://cdn.example.com/dl.jpg"})}, true},
+		{"lapsed licence", ApplicationInput{VehicleType: sqlc.VehicleTypeBicycle, NidNumber: "123", Documents: docs(DocumentInput{Type: DocDrivingLicence, FileURL: "https://cdn.example.com/dl.jpg", ExpiresOn: &lapsed})}, true},
+		{"duplicate document", ApplicationInput{VehicleType: sqlc.VehicleTypeBicycle, NidNumber: "123", Documents: docs(DocumentInput{Type: DocNIDBack, FileURL: "https://cdn.example.com/b2.jpg"})}, true},
+		{"not a link", ApplicationInput{VehicleType: sqlc.VehicleTypeBicycle, NidNumber: "123", Documents: docs(DocumentInput{Type: DocNIDFront, FileURL: "front.jpg"})[1:]}, true},
+		{"unknown vehicle", ApplicationInput{VehicleType: "truck", NidNumber: "123", Documents: docs()}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateApplication(tc.in, today); (err != nil) != tc.wantErr {
+				t.Errorf("validateApplication = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckVerified(t *testing.T) {
+	for status, allowed := range map[string]bool{
+		VerificationVerified:   true,
+		VerificationUnverified: false,
+		VerificationExpired:    false,
+	} {
+		if err := checkVerified(sqlc.Rider{VerificationStatus: status}); (err == nil) != allowed {
+			t.Errorf("%s: err = %v, want allowed %v", status, err, allowed)
+		}
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	today := bdTime(t, "2026-03-02 00:00")
+	if got := daysUntil(today, pgDate(today.Add(36*time.Hour))); got != 1 {
+		t.Errorf("daysUntil = %d, want 1", got)
+	}
+}
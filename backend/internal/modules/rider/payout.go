@@ -0,0 +1,577 @@
+package rider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/finance"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// Payout batch kinds.
+const (
+	BatchWeekly   = "weekly"
+	BatchOnDemand = "on_demand"
+)
+
+const defaultPayoutMethod = "bkash"
+
+// netting is the result of settling a rider's earnings against what they owe.
+type netting struct {
+	Penalties         []int // indexes of the penalties deducted
+	PenaltyDeductions decimal.Decimal
+	CodOffset         decimal.Decimal
+	Amount            decimal.Decimal
+}
+
+// netPayout deducts pending penalties (oldest first) and then the COD cash the
+// rider still holds from their gross earnings. A penalty larger than what is
+// left is skipped and stays pending for a later payout, so the amount paid out
+// never goes negative.
+func netPayout(gross, cashInHand decimal.Decimal, penalties []decimal.Decimal) netting {
+	n := netting{PenaltyDeductions: decimal.Zero, CodOffset: decimal.Zero}
+	remaining := gross
+	for i, p := range penalties {
+		if !p.IsPositive() || p.GreaterThan(remaining) {
+			continue
+		}
+		n.Penalties = append(n.Penalties, i)
+		n.PenaltyDeductions = n.PenaltyDeductions.Add(p)
+		remaining = remaining.Sub(p)
+	}
+	if cashInHand.IsPositive() {
+		n.CodOffset = decimal.Min(cashInHand, remaining)
+		remaining = remaining.Sub(n.CodOffset)
+	}
+	n.Amount = remaining
+	return n
+}
+
+// PayoutBatchDetail is a batch with its payouts.
+type PayoutBatchDetail struct {
+	Batch   sqlc.RiderPayoutBatch             `json:"batch"`
+	Payouts []sqlc.ListRiderPayoutsByBatchRow `json:"payouts"`
+}
+
+// CreateOnDemandBatch settles earnings recorded up to and including the BD
+// date until. With riderIDs empty every rider with unsettled earnings is paid.
+func (s *Service) CreateOnDemandBatch(ctx context.Context, tenantID, actorID uuid.UUID, until time.Time, riderIDs []uuid.UUID) (*PayoutBatchDetail, error) {
+	until = timeutil.StartOfDayBD(until)
+	if until.After(timeutil.NowBD()) {
+		return nil, apperror.BadRequest("until date cannot be in the future")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	batch, err := s.buildBatch(ctx, qtx, tenantID, BatchOnDemand, until, until, riderIDs, &actorID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.PayoutCount == 0 {
+		return nil, apperror.BadRequest("no unsettled earnings to pay out")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.Internal("commit tx", err)
+	}
+	return s.GetPayoutBatch(ctx, batch.ID, tenantID)
+}
+
+// RunWeeklyPayouts creates the batch for the last full Monday–Sunday week
+// (Asia/Dhaka) of every active tenant that does not have one yet. It is safe
+// to run repeatedly.
+func (s *Service) RunWeeklyPayouts(ctx context.Context) error {
+	today := timeutil.StartOfDayBD(time.Now())
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	from := weekStart.AddDate(0, 0, -7)
+	to := weekStart.AddDate(0, 0, -1)
+
+	tenantIDs, err := s.q.ListActiveTenantIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	created := 0
+	for _, tenantID := range tenantIDs {
+		_, err := s.q.GetWeeklyRiderPayoutBatch(ctx, sqlc.GetWeeklyRiderPayoutBatchParams{
+			TenantID:   tenantID,
+			PeriodFrom: pgDate(from),
+		})
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("failed to look up weekly payout batch")
+			continue
+		}
+		if err := s.createWeeklyBatch(ctx, tenantID, from, to); err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("failed to create weekly payout batch")
+			continue
+		}
+		created++
+	}
+
+	if created > 0 {
+		log.Info().Int("count", created).Str("week", from.Format("2006-01-02")).Msg("created weekly rider payout batches")
+	}
+	return nil
+}
+
+func (s *Service) createWeeklyBatch(ctx context.Context, tenantID uuid.UUID, from, to time.Time) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := s.buildBatch(ctx, s.q.WithTx(tx), tenantID, BatchWeekly, from, to, nil, nil); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// buildBatch creates a batch and one payout per rider for earnings recorded
+// before the end of periodTo. Unsettled earnings from earlier periods are
+// carried into the batch.
+func (s *Service) buildBatch(ctx context.Context, q *sqlc.Queries, tenantID uuid.UUID, kind string, periodFrom, periodTo time.Time, riderIDs []uuid.UUID, actorID *uuid.UUID) (sqlc.RiderPayoutBatch, error) {
+	createdBy := pgtype.UUID{}
+	if actorID != nil {
+		createdBy = pgtype.UUID{Bytes: *actorID, Valid: true}
+	}
+	batch, err := q.CreateRiderPayoutBatch(ctx, sqlc.CreateRiderPayoutBatchParams{
+		TenantID:   tenantID,
+		Kind:       kind,
+		PeriodFrom: pgDate(periodFrom),
+		PeriodTo:   pgDate(periodTo),
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		return sqlc.RiderPayoutBatch{}, apperror.Internal("create payout batch", err)
+	}
+
+	before := periodTo.AddDate(0, 0, 1)
+	if len(riderIDs) == 0 {
+		riderIDs, err = q.ListRidersWithUnsettledEarnings(ctx, sqlc.ListRidersWithUnsettledEarningsParams{
+			TenantID: tenantID,
+			Before:   before,
+		})
+		if err != nil {
+			return sqlc.RiderPayoutBatch{}, apperror.Internal("list riders with unsettled earnings", err)
+		}
+	}
+
+	var count int32
+	total := decimal.Zero
+	earliest := pgDate(periodFrom)
+	for _, riderID := range riderIDs {
+		payout, ok, err := s.buildPayout(ctx, q, tenantID, batch.ID, riderID, before)
+		if err != nil {
+			return sqlc.RiderPayoutBatch{}, err
+		}
+		if !ok {
+			continue
+		}
+		count++
+		total = total.Add(numericToDecimal(payout.Amount))
+		if payout.EarningsFrom.Time.Before(earliest.Time) {
+			earliest = payout.EarningsFrom
+		}
+	}
+
+	// The weekly batch keeps its calendar week so the schedule can find it;
+	// an on-demand batch covers whatever it actually settled.
+	if kind == BatchWeekly {
+		earliest = pgDate(periodFrom)
+	}
+	batch, err = q.UpdateRiderPayoutBatchTotals(ctx, sqlc.UpdateRiderPayoutBatchTotalsParams{
+		PeriodFrom:  earliest,
+		PayoutCount: count,
+		TotalAmount: toPgNumeric(total),
+		ID:          batch.ID,
+	})
+	if err != nil {
+		return sqlc.RiderPayoutBatch{}, apperror.Internal("update payout batch", err)
+	}
+	return batch, nil
+}
+
+// buildPayout attaches a rider's unsettled earnings to a new payout and nets
+// out their pending penalties and COD cash in hand. It reports false when the
+// rider has nothing to settle.
+func (s *Service) buildPayout(ctx context.Context, q *sqlc.Queries, tenantID, batchID, riderID uuid.UUID, before time.Time) (sqlc.RiderPayout, bool, error) {
+	rider, err := q.GetRiderForUpdate(ctx, sqlc.GetRiderForUpdateParams{ID: riderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderPayout{}, false, apperror.NotFound("rider")
+	}
+	if err != nil {
+		return sqlc.RiderPayout{}, false, apperror.Internal("get rider", err)
+	}
+
+	account := ""
+	if user, err := q.GetUserByID(ctx, rider.UserID); err == nil && user.Phone.Valid {
+		account = user.Phone.String
+	}
+
+	day := pgDate(timeutil.StartOfDayBD(before.AddDate(0, 0, -1)))
+	payout, err := q.CreateRiderPayout(ctx, sqlc.CreateRiderPayoutParams{
+		RiderID:       riderID,
+		TenantID:      tenantID,
+		BatchID:       pgtype.UUID{Bytes: batchID, Valid: true},
+		EarningsFrom:  day,
+		EarningsTo:    day,
+		PaymentMethod: defaultPayoutMethod,
+		PayoutAccount: nullString(account),
+	})
+	if err != nil {
+		return sqlc.RiderPayout{}, false, apperror.Internal("create payout", err)
+	}
+	payoutID := pgtype.UUID{Bytes: payout.ID, Valid: true}
+
+	earnings, err := q.AttachEarningsToPayout(ctx, sqlc.AttachEarningsToPayoutParams{
+		PayoutID: payoutID,
+		RiderID:  riderID,
+		TenantID: tenantID,
+		Before:   before,
+	})
+	if err != nil {
+		return sqlc.RiderPayout{}, false, apperror.Internal("attach earnings", err)
+	}
+	if len(earnings) == 0 {
+		if err := q.DeleteRiderPayout(ctx, payout.ID); err != nil {
+			return sqlc.RiderPayout{}, false, apperror.Internal("delete empty payout", err)
+		}
+		return sqlc.RiderPayout{}, false, nil
+	}
+
+	gross := decimal.Zero
+	first, last := earnings[0].CreatedAt, earnings[0].CreatedAt
+	for _, e := range earnings {
+		gross = gross.Add(numericToDecimal(e.TotalEarning))
+		if e.CreatedAt.Before(first) {
+			first = e.CreatedAt
+		}
+		if e.CreatedAt.After(last) {
+			last = e.CreatedAt
+		}
+	}
+
+	penalties, err := q.ListUnsettledPenaltiesForRider(ctx, sqlc.ListUnsettledPenaltiesForRiderParams{
+		RiderID:  riderID,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return sqlc.RiderPayout{}, false, apperror.Internal("list unsettled penalties", err)
+	}
+	amounts := make([]decimal.Decimal, len(penalties))
+	for i, p := range penalties {
+		amounts[i] = numericToDecimal(p.Amount)
+	}
+
+	n := netPayout(gross, numericToDecimal(rider.CashInHand), amounts)
+	for _, i := range n.Penalties {
+		if err := q.AttachPenaltyToPayout(ctx, sqlc.AttachPenaltyToPayoutParams{
+			ID:       penalties[i].ID,
+			PayoutID: payoutID,
+		}); err != nil {
+			return sqlc.RiderPayout{}, false, apperror.Internal("attach penalty", err)
+		}
+	}
+	if n.CodOffset.IsPositive() {
+		if err := q.DeductRiderCashInHand(ctx, sqlc.DeductRiderCashInHandParams{
+			Amount:   toPgNumeric(n.CodOffset),
+			ID:       riderID,
+			TenantID: tenantID,
+		}); err != nil {
+			return sqlc.RiderPayout{}, false, apperror.Internal("deduct cash in hand", err)
+		}
+		// The rider keeps the cash as part of their pay.
+		if err := postCashTransfer(ctx, q, tenantID, sqlc.LedgerEntryTypeRiderPayout,
+			finance.AccountRiderCash, finance.AccountRiderPayable, "rider_payout", payout.ID, n.CodOffset,
+			"COD cash in hand netted against rider payout",
+			map[string]interface{}{"rider_id": riderID}); err != nil {
+			return sqlc.RiderPayout{}, false, apperror.Internal("post cod offset", err)
+		}
+	}
+
+	payout, err = q.SetRiderPayoutBreakdown(ctx, sqlc.SetRiderPayoutBreakdownParams{
+		Amount:            toPgNumeric(n.Amount),
+		GrossEarnings:     toPgNumeric(gross),
+		PenaltyDeductions: toPgNumeric(n.PenaltyDeductions),
+		CodOffset:         toPgNumeric(n.CodOffset),
+		EarningsFrom:      pgDate(timeutil.StartOfDayBD(first)),
+		EarningsTo:        pgDate(timeutil.StartOfDayBD(last)),
+		ID:                payout.ID,
+	})
+	if err != nil {
+		return sqlc.RiderPayout{}, false, apperror.Internal("set payout breakdown", err)
+	}
+
+	// Nothing to transfer: penalties and cash in hand absorbed the earnings.
+	if !n.Amount.IsPositive() {
+		payout, err = s.settlePayout(ctx, q, payout, "", nil, "settled against penalties and COD cash in hand")
+		if err != nil {
+			return sqlc.RiderPayout{}, false, err
+		}
+	}
+	return payout, true, nil
+}
+
+// GetPayoutBatch returns a batch with its payouts.
+func (s *Service) GetPayoutBatch(ctx context.Context, id, tenantID uuid.UUID) (*PayoutBatchDetail, error) {
+	return loadPayoutBatch(ctx, s.q, id, tenantID)
+}
+
+func loadPayoutBatch(ctx context.Context, q *sqlc.Queries, id, tenantID uuid.UUID) (*PayoutBatchDetail, error) {
+	batch, err := q.GetRiderPayoutBatch(ctx, sqlc.GetRiderPayoutBatchParams{ID: id, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("payout batch")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get payout batch", err)
+	}
+
+	payouts, err := q.ListRiderPayoutsByBatch(ctx, sqlc.ListRiderPayoutsByBatchParams{
+		BatchID:  pgtype.UUID{Bytes: id, Valid: true},
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list batch payouts", err)
+	}
+	return &PayoutBatchDetail{Batch: batch, Payouts: payouts}, nil
+}
+
+// ListPayoutBatches returns paginated payout batches for a tenant.
+func (s *Service) ListPayoutBatches(ctx context.Context, tenantID uuid.UUID, limit, offset int32) ([]sqlc.RiderPayoutBatch, int64, error) {
+	batches, err := s.q.ListRiderPayoutBatches(ctx, sqlc.ListRiderPayoutBatchesParams{
+		TenantID: tenantID, Limit: limit, Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, apperror.Internal("list payout batches", err)
+	}
+	total, err := s.q.CountRiderPayoutBatches(ctx, tenantID)
+	if err != nil {
+		return nil, 0, apperror.Internal("count payout batches", err)
+	}
+	return batches, total, nil
+}
+
+// ListPayouts returns a rider's paginated payout history.
+func (s *Service) ListPayouts(ctx context.Context, riderID, tenantID uuid.UUID, limit, offset int32) ([]sqlc.RiderPayout, int64, error) {
+	payouts, err := s.q.ListRiderPayoutsByRider(ctx, sqlc.ListRiderPayoutsByRiderParams{
+		RiderID: riderID, TenantID: tenantID, Limit: limit, Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, apperror.Internal("list payouts", err)
+	}
+	total, err := s.q.CountRiderPayoutsByRider(ctx, sqlc.CountRiderPayoutsByRiderParams{
+		RiderID: riderID, TenantID: tenantID,
+	})
+	if err != nil {
+		return nil, 0, apperror.Internal("count payouts", err)
+	}
+	return payouts, total, nil
+}
+
+// ExportBkashDisbursement moves the payouts of a batch that still need a
+// transfer to processing and returns them, in one transaction.
+func (s *Service) ExportBkashDisbursement(ctx context.Context, batchID, tenantID uuid.UUID) (sqlc.RiderPayoutBatch, []sqlc.ListRiderPayoutsByBatchRow, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderPayoutBatch{}, nil, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	if err := qtx.MarkBatchPayoutsProcessing(ctx, sqlc.MarkBatchPayoutsProcessingParams{
+		BatchID:  pgtype.UUID{Bytes: batchID, Valid: true},
+		TenantID: tenantID,
+	}); err != nil {
+		return sqlc.RiderPayoutBatch{}, nil, apperror.Internal("mark payouts processing", err)
+	}
+	detail, err := loadPayoutBatch(ctx, qtx, batchID, tenantID)
+	if err != nil {
+		return sqlc.RiderPayoutBatch{}, nil, err
+	}
+
+	var rows []sqlc.ListRiderPayoutsByBatchRow
+	for _, p := range detail.Payouts {
+		if p.PaymentMethod != defaultPayoutMethod || !numericToDecimal(p.Amount).IsPositive() {
+			continue
+		}
+		if p.Status != sqlc.PayoutStatusPending && p.Status != sqlc.PayoutStatusProcessing {
+			continue
+		}
+		rows = append(rows, p)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderPayoutBatch{}, nil, apperror.Internal("commit tx", err)
+	}
+	return detail.Batch, rows, nil
+}
+
+// CompletePayout records a transfer and settles the payout: its earnings are
+// marked paid, its penalties cleared and the rider's pending balance reduced
+// by the gross earnings, all in one transaction.
+func (s *Service) CompletePayout(ctx context.Context, payoutID, tenantID, actorID uuid.UUID, reference string) (sqlc.RiderPayout, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	payout, err := lockOpenPayout(ctx, qtx, payoutID, tenantID)
+	if err != nil {
+		return sqlc.RiderPayout{}, err
+	}
+	if reference == "" && numericToDecimal(payout.Amount).IsPositive() {
+		return sqlc.RiderPayout{}, apperror.BadRequest("payment_reference is required")
+	}
+
+	payout, err = s.settlePayout(ctx, qtx, payout, reference, &actorID, "")
+	if err != nil {
+		return sqlc.RiderPayout{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("commit tx", err)
+	}
+	return payout, nil
+}
+
+func (s *Service) settlePayout(ctx context.Context, q *sqlc.Queries, payout sqlc.RiderPayout, reference string, actorID *uuid.UUID, note string) (sqlc.RiderPayout, error) {
+	processedBy := pgtype.UUID{}
+	if actorID != nil {
+		processedBy = pgtype.UUID{Bytes: *actorID, Valid: true}
+	}
+	payoutID := pgtype.UUID{Bytes: payout.ID, Valid: true}
+
+	completed, err := q.CompleteRiderPayout(ctx, sqlc.CompleteRiderPayoutParams{
+		PaymentReference: nullString(reference),
+		ProcessedBy:      processedBy,
+		Note:             nullString(note),
+		ID:               payout.ID,
+	})
+	if err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("complete payout", err)
+	}
+	if err := q.MarkPayoutEarningsPaid(ctx, payoutID); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("mark earnings paid", err)
+	}
+	if err := q.ClearPayoutPenalties(ctx, sqlc.ClearPayoutPenaltiesParams{
+		ClearedBy: processedBy,
+		PayoutID:  payoutID,
+	}); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("clear payout penalties", err)
+	}
+	if err := q.DeductRiderPendingBalance(ctx, sqlc.DeductRiderPendingBalanceParams{
+		Amount:   completed.GrossEarnings,
+		ID:       completed.RiderID,
+		TenantID: completed.TenantID,
+	}); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("deduct pending balance", err)
+	}
+	return completed, nil
+}
+
+// FailPayout marks a transfer as failed and releases what the payout had
+// claimed: earnings and penalties return to the rider's unsettled pool and
+// the COD offset goes back to their cash in hand, ready for the next batch.
+func (s *Service) FailPayout(ctx context.Context, payoutID, tenantID, actorID uuid.UUID, note string) (sqlc.RiderPayout, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	payout, err := lockOpenPayout(ctx, qtx, payoutID, tenantID)
+	if err != nil {
+		return sqlc.RiderPayout{}, err
+	}
+
+	failed, err := qtx.FailRiderPayout(ctx, sqlc.FailRiderPayoutParams{
+		ProcessedBy: pgtype.UUID{Bytes: actorID, Valid: true},
+		Note:        nullString(note),
+		ID:          payout.ID,
+	})
+	if err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("fail payout", err)
+	}
+	pid := pgtype.UUID{Bytes: payout.ID, Valid: true}
+	if err := qtx.DetachPayoutEarnings(ctx, pid); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("detach earnings", err)
+	}
+	if err := qtx.DetachPayoutPenalties(ctx, pid); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("detach penalties", err)
+	}
+	if numericToDecimal(payout.CodOffset).IsPositive() {
+		if err := qtx.AddRiderCashInHand(ctx, sqlc.AddRiderCashInHandParams{
+			Amount:   payout.CodOffset,
+			ID:       payout.RiderID,
+			TenantID: tenantID,
+		}); err != nil {
+			return sqlc.RiderPayout{}, apperror.Internal("restore cash in hand", err)
+		}
+		if err := postCashTransfer(ctx, qtx, tenantID, sqlc.LedgerEntryTypeRiderPayout,
+			finance.AccountRiderPayable, finance.AccountRiderCash, "rider_payout", payout.ID, numericToDecimal(payout.CodOffset),
+			"COD offset returned to rider cash in hand on failed payout",
+			map[string]interface{}{"rider_id": payout.RiderID}); err != nil {
+			return sqlc.RiderPayout{}, apperror.Internal("reverse cod offset", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("commit tx", err)
+	}
+	return failed, nil
+}
+
+func lockOpenPayout(ctx context.Context, q *sqlc.Queries, payoutID, tenantID uuid.UUID) (sqlc.RiderPayout, error) {
+	payout, err := q.GetRiderPayoutForUpdate(ctx, sqlc.GetRiderPayoutForUpdateParams{ID: payoutID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderPayout{}, apperror.NotFound("payout")
+	}
+	if err != nil {
+		return sqlc.RiderPayout{}, apperror.Internal("get payout", err)
+	}
+	if payout.Status != sqlc.PayoutStatusPending && payout.Status != sqlc.PayoutStatusProcessing {
+		return sqlc.RiderPayout{}, apperror.Conflict("payout is already " + string(payout.Status))
+	}
+	return payout, nil
+}
+
+func pgDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
+
+func toPgNumeric(d decimal.Decimal) pgtype.Numeric {
+	n := pgtype.Numeric{Valid: true}
+	_ = n.Scan(d.String())
+	return n
+}
@@ -0,0 +1,44 @@
+package rider
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestNetPayout_DeductsPenaltiesThenCash(t *testing.T) {
+	n := netPayout(dec("1000"), dec("300"), []decimal.Decimal{dec("100"), dec("50")})
+
+	if len(n.Penalties) != 2 || !n.PenaltyDeductions.Equal(dec("150")) {
+		t.Fatalf("penalties = %v (%s), want both worth 150", n.Penalties, n.PenaltyDeductions)
+	}
+	if !n.CodOffset.Equal(dec("300")) {
+		t.Errorf("cod offset = %s, want 300", n.CodOffset)
+	}
+	if !n.Amount.Equal(dec("550")) {
+		t.Errorf("amount = %s, want 550", n.Amount)
+	}
+}
+
+func TestNetPayout_SkipsPenaltyLargerThanRemainder(t *testing.T) {
+	n := netPayout(dec("200"), decimal.Zero, []decimal.Decimal{dec("150"), dec("80"), dec("40")})
+
+	if len(n.Penalties) != 2 || n.Penalties[0] != 0 || n.Penalties[1] != 2 {
+		t.Fatalf("penalties = %v, want [0 2]", n.Penalties)
+	}
+	if !n.Amount.Equal(dec("10")) {
+		t.Errorf("amount = %s, want 10", n.Amount)
+	}
+}
+
+func TestNetPayout_CashInHandCanAbsorbEverything(t *testing.T) {
+	n := netPayout(dec("400"), dec("2500"), nil)
+
+	if !n.CodOffset.Equal(dec("400")) || !n.Amount.IsZero() {
+		t.Errorf("got cod offset %s and amount %s, want 400 and 0", n.CodOffset, n.Amount)
+	}
+}
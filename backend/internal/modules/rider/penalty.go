@@ -0,0 +1,175 @@
+package rider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+)
+
+// Appeal outcomes. An upheld penalty goes back to pending and is deducted
+// from the next payout; an overturned one is cleared.
+const (
+	AppealUpheld     = "upheld"
+	AppealOverturned = "overturned"
+)
+
+const (
+	// penaltyAppealWindow is how long after a penalty a rider can appeal it.
+	penaltyAppealWindow = 7 * 24 * time.Hour
+	maxAppealEvidence   = 5
+)
+
+// PenaltyAppealInput is a rider's case against a penalty.
+type PenaltyAppealInput struct {
+	Note         string
+	EvidenceURLs []string
+}
+
+func validatePenaltyAppeal(in PenaltyAppealInput) error {
+	if strings.TrimSpace(in.Note) == "" {
+		return apperror.BadRequest("note is required")
+	}
+	if len(in.EvidenceURLs) > maxAppealEvidence {
+		return apperror.BadRequest("at most 5 evidence files can be attached")
+	}
+	for _, raw := range in.EvidenceURLs {
+		if !isHTTPURL(raw) {
+			return apperror.BadRequest("evidence_urls must be http(s) links")
+		}
+	}
+	return nil
+}
+
+// checkAppealable reports why a penalty cannot be appealed, if it cannot.
+// Penalties already taken by a payout are settled and can only be refunded
+// by hand.
+func checkAppealable(p sqlc.RiderPenalty, now time.Time) error {
+	if p.AppealedAt.Valid {
+		return apperror.Conflict("penalty has already been appealed")
+	}
+	if p.Status != sqlc.PenaltyStatusPending {
+		return apperror.Conflict("only pending penalties can be appealed")
+	}
+	if p.PayoutID.Valid {
+		return apperror.Conflict("penalty has already been deducted from a payout")
+	}
+	if now.Sub(p.CreatedAt) > penaltyAppealWindow {
+		return apperror.BadRequest("the appeal window for this penalty has closed")
+	}
+	return nil
+}
+
+// AppealPenalty puts a rider's penalty under appeal, which holds it out of
+// payouts until a partner reviews it.
+func (s *Service) AppealPenalty(ctx context.Context, riderID, tenantID, penaltyID uuid.UUID, in PenaltyAppealInput) (sqlc.RiderPenalty, error) {
+	if err := validatePenaltyAppeal(in); err != nil {
+		return sqlc.RiderPenalty{}, err
+	}
+
+	penalty, err := s.q.GetPenaltyByID(ctx, sqlc.GetPenaltyByIDParams{ID: penaltyID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && penalty.RiderID != riderID) {
+		return sqlc.RiderPenalty{}, apperror.NotFound("penalty")
+	}
+	if err != nil {
+		return sqlc.RiderPenalty{}, apperror.Internal("get penalty", err)
+	}
+	if err := checkAppealable(penalty, time.Now()); err != nil {
+		return sqlc.RiderPenalty{}, err
+	}
+
+	evidence := in.EvidenceURLs
+	if evidence == nil {
+		evidence = []string{}
+	}
+	updated, err := s.q.AppealPenalty(ctx, sqlc.AppealPenaltyParams{
+		AppealNote:         nullString(strings.TrimSpace(in.Note)),
+		AppealEvidenceUrls: evidence,
+		ID:                 penaltyID,
+		RiderID:            riderID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Taken by a payout or appealed between the read and the update.
+		return sqlc.RiderPenalty{}, apperror.Conflict("penalty can no longer be appealed")
+	}
+	if err != nil {
+		return sqlc.RiderPenalty{}, apperror.Internal("appeal penalty", err)
+	}
+	return updated, nil
+}
+
+// ListPenaltyAppeals returns appealed penalties, oldest appeal first. The
+// status filter defaults to the open queue. Users other than tenant owners and
+// admins must name a hub they manage.
+func (s *Service) ListPenaltyAppeals(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, status string, limit, offset int32) ([]sqlc.ListPenaltyAppealsRow, error) {
+	switch sqlc.PenaltyStatus(status) {
+	case "", sqlc.PenaltyStatusAppealed, sqlc.PenaltyStatusPending, sqlc.PenaltyStatusCleared:
+	default:
+		return nil, apperror.BadRequest("status must be appealed, pending or cleared")
+	}
+
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = string(sqlc.PenaltyStatusAppealed)
+	}
+
+	appeals, err := s.q.ListPenaltyAppeals(ctx, sqlc.ListPenaltyAppealsParams{
+		TenantID: tenantID,
+		HubID:    hub,
+		Status:   sqlc.NullPenaltyStatus{PenaltyStatus: sqlc.PenaltyStatus(status), Valid: true},
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list penalty appeals", err)
+	}
+	return appeals, nil
+}
+
+// ReviewPenaltyAppeal decides an appeal. Only the manager of the rider's hub,
+// or a tenant owner or admin, can decide it.
+func (s *Service) ReviewPenaltyAppeal(ctx context.Context, user *sqlc.User, tenantID, penaltyID uuid.UUID, uphold bool, note string) (sqlc.RiderPenalty, error) {
+	penalty, err := s.q.GetPenaltyByID(ctx, sqlc.GetPenaltyByIDParams{ID: penaltyID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderPenalty{}, apperror.NotFound("penalty")
+	}
+	if err != nil {
+		return sqlc.RiderPenalty{}, apperror.Internal("get penalty", err)
+	}
+	rider, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: penalty.RiderID, TenantID: tenantID})
+	if err != nil {
+		return sqlc.RiderPenalty{}, apperror.Internal("get rider", err)
+	}
+	if err := authorizeRiderManager(ctx, s.q, user, rider); err != nil {
+		return sqlc.RiderPenalty{}, err
+	}
+
+	status, outcome := sqlc.PenaltyStatusCleared, AppealOverturned
+	if uphold {
+		status, outcome = sqlc.PenaltyStatusPending, AppealUpheld
+	}
+	updated, err := s.q.ReviewPenaltyAppeal(ctx, sqlc.ReviewPenaltyAppealParams{
+		Status:           status,
+		AppealOutcome:    nullString(outcome),
+		AppealReviewNote: nullString(note),
+		ReviewedBy:       pgtype.UUID{Bytes: user.ID, Valid: true},
+		ID:               penaltyID,
+		TenantID:         tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderPenalty{}, apperror.Conflict("penalty is not under appeal")
+	}
+	if err != nil {
+		return sqlc.RiderPenalty{}, apperror.Internal("review penalty appeal", err)
+	}
+	return updated, nil
+}
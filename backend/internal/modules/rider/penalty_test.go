@@ -0,0 +1,80 @@
+package rider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+)
+
+func TestCheckAppealable(t *testing.T) {
+	now := time.Now()
+	pending := sqlc.RiderPenalty{Status: sqlc.PenaltyStatusPending, CreatedAt: now.Add(-24 * time.Hour)}
+
+	if err := checkAppealable(pending, now); err != nil {
+		t.Errorf("fresh pending penalty: %v", err)
+	}
+
+	appealed := pending
+	appealed.AppealedAt = pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+	appealed.Status = sqlc.PenaltyStatusPending // upheld after an earlier appeal
+	if err := checkAppealable(appealed, now); err == nil {
+		t.Error("a penalty can only be appealed once")
+	}
+
+	cleared := pending
+	cleared.Status = sqlc.PenaltyStatusCleared
+	if err := checkAppealable(cleared, now); err == nil {
+		t.Error("a cleared penalty cannot be appealed")
+	}
+
+	deducted := pending
+	deducted.PayoutID = pgtype.UUID{Valid: true}
+	if err := checkAppealable(deducted, now); err == nil {
+		t.Error("a penalty taken by a payout cannot be appealed")
+	}
+
+	old := pending
+	old.CreatedAt = now.Add(-8 * 24 * time.Hour)
+	if err := checkAppealable(old, now); err == nil {
+		t.Error("the appeal window should close after 7 days")
+	}
+}
+
+func TestValidatePenaltyAppeal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   PenaltyAppealInput
+		ok   bool
+	}{
+		{"note only", PenaltyAppealInput{Note: "The customer was not home"}, true},
+		{"with evidence", PenaltyAppealInput{Note: "Photo attached", EvidenceURLs: []string{"https://cdn.example.com/a.jpg"}}, true},
+		{"blank note", PenaltyAppealInput{Note: "  "}, false},
+		{"not a link", PenaltyAppealInput{Note: "x", EvidenceURLs: []string{"a.jpg"}}, false},
+		{"too much evidence", PenaltyAppealInput{Note: "x", EvidenceURLs: []string{
+			"https://a/1", "https://a/2", "https://a/3", "https://a/4", "https://a/5", "https://a/6",
+		}}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validatePenaltyAppeal(tc.in); (err == nil) != tc.ok {
+				t.Errorf("validatePenaltyAppeal = %v, want ok=%v", err, tc.ok)
+			}
+		})
+	}
+}
+
+func TestListPenaltyAppealsRejectsUnknownStatus(t *testing.T) {
+	svc := &Service{}
+	_, err := svc.ListPenaltyAppeals(context.Background(), nil, uuid.New(), nil, "overturned", 20, 0)
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.HTTPStatus() != http.StatusBadRequest {
+		t.Fatalf("ListPenaltyAppeals(status=overturned) = %v, want a 400", err)
+	}
+}
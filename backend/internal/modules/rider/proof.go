@@ -0,0 +1,315 @@
+package rider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/geo"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// deliveryOtpMaxAttempts locks the delivery code after this many wrong
+	// entries; support has to close the order from there.
+	deliveryOtpMaxAttempts = 5
+	// podLocationMaxAge is how old the rider's last known location may be and
+	// still count for the geofence check.
+	podLocationMaxAge = 5 * time.Minute
+)
+
+// DeliveryProofInput is what the rider app submits when marking an order
+// delivered.
+type DeliveryProofInput struct {
+	OTP      string
+	PhotoURL string
+}
+
+// podRequirement is the proof of delivery an order needs under the tenant's
+// policy.
+type podRequirement struct {
+	Required bool
+	OTP      bool
+	Photo    bool
+	RadiusM  *int32
+}
+
+// podRequirementFor resolves the tenant policy for one order. Proof is
+// required when a restaurant on the order demands it or the order total
+// reaches the configured minimum; otherwise nothing is enforced.
+func podRequirementFor(cfg sqlc.DeliveryZoneConfig, restaurantRequires bool, total decimal.Decimal) podRequirement {
+	required := restaurantRequires
+	if cfg.PodMinOrderValue.Valid && total.GreaterThanOrEqual(numericToDecimal(cfg.PodMinOrderValue)) {
+		required = true
+	}
+	if !required {
+		return podRequirement{RadiusM: cfg.PodGeofenceRadiusM}
+	}
+	return podRequirement{
+		Required: true,
+		OTP:      cfg.PodOtpEnabled,
+		Photo:    cfg.PodPhotoRequired,
+		RadiusM:  cfg.PodGeofenceRadiusM,
+	}
+}
+
+// loadPodRequirement reads the tenant policy and restaurant flags for an order.
+// Tenants without a delivery config never require proof.
+func loadPodRequirement(ctx context.Context, q *sqlc.Queries, order sqlc.Order) (podRequirement, error) {
+	cfg, err := q.GetDeliveryZoneConfig(ctx, order.TenantID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return podRequirement{}, apperror.Internal("get delivery config", err)
+	}
+	restaurantRequires, err := q.OrderRestaurantsRequirePod(ctx, sqlc.OrderRestaurantsRequirePodParams{
+		OrderID: order.ID, TenantID: order.TenantID,
+	})
+	if err != nil {
+		return podRequirement{}, apperror.Internal("check restaurant pod", err)
+	}
+	return podRequirementFor(cfg, restaurantRequires, numericToDecimal(order.TotalAmount)), nil
+}
+
+func generateDeliveryOtp() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%04d", n.Int64()), nil
+}
+
+// otpMatches compares a submitted delivery code with the issued one in
+// constant time.
+func otpMatches(issued sql.NullString, submitted string) bool {
+	if !issued.Valid || submitted == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(issued.String), []byte(submitted)) == 1
+}
+
+// geofenceCheck is the outcome of comparing the rider's last location with
+// the delivery address.
+type geofenceCheck struct {
+	Checked   bool
+	Within    bool
+	DistanceM decimal.Decimal
+}
+
+// evaluateGeofence measures how far the rider's last location is from the
+// delivery address. The check is skipped when no radius is configured, the
+// order has no coordinates, or the location is missing or stale.
+func evaluateGeofence(loc *sqlc.RiderLocation, destLat, destLng pgtype.Numeric, radiusM *int32, now time.Time) geofenceCheck {
+	if radiusM == nil || loc == nil || now.Sub(loc.UpdatedAt) > podLocationMaxAge {
+		return geofenceCheck{}
+	}
+	lat, okLat := numericToFloat64(destLat)
+	lng, okLng := numericToFloat64(destLng)
+	riderLat, okRiderLat := numericToFloat64(loc.GeoLat)
+	riderLng, okRiderLng := numericToFloat64(loc.GeoLng)
+	if !okLat || !okLng || !okRiderLat || !okRiderLng {
+		return geofenceCheck{}
+	}
+
+	distance := decimal.NewFromFloat(geo.DistanceKm(riderLat, riderLng, lat, lng) * 1000).Round(2)
+	return geofenceCheck{
+		Checked:   true,
+		Within:    distance.LessThanOrEqual(decimal.NewFromInt32(*radiusM)),
+		DistanceM: distance,
+	}
+}
+
+// verifiedProof is the evidence gathered for a delivery that passed the
+// policy checks.
+type verifiedProof struct {
+	Requirement podRequirement
+	OtpVerified bool
+	Location    *sqlc.RiderLocation
+	Geofence    geofenceCheck
+}
+
+// verifyDeliveryProof enforces the tenant's proof of delivery policy for an
+// order. Wrong delivery codes are counted even though the delivery is
+// rejected.
+func (s *Service) verifyDeliveryProof(ctx context.Context, order sqlc.Order, riderID uuid.UUID, in DeliveryProofInput) (verifiedProof, error) {
+	req, err := loadPodRequirement(ctx, s.q, order)
+	if err != nil {
+		return verifiedProof{}, err
+	}
+	out := verifiedProof{Requirement: req}
+
+	proof, err := s.q.GetDeliveryProofByOrder(ctx, sqlc.GetDeliveryProofByOrderParams{
+		OrderID: order.ID, TenantID: order.TenantID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return verifiedProof{}, apperror.Internal("get delivery proof", err)
+	}
+	issued := proof.OtpCode
+
+	if req.OTP {
+		switch {
+		case !issued.Valid:
+			return verifiedProof{}, apperror.Conflict("no delivery code was issued for this order")
+		case proof.OtpAttempts >= deliveryOtpMaxAttempts:
+			return verifiedProof{}, apperror.Conflict("too many incorrect delivery codes; contact support")
+		case in.OTP == "":
+			return verifiedProof{}, apperror.BadRequest("delivery code is required")
+		case !otpMatches(issued, in.OTP):
+			if _, err := s.q.IncrementDeliveryProofOtpAttempts(ctx, sqlc.IncrementDeliveryProofOtpAttemptsParams{
+				OrderID: order.ID, TenantID: order.TenantID,
+			}); err != nil {
+				return verifiedProof{}, apperror.Internal("record delivery code attempt", err)
+			}
+			return verifiedProof{}, apperror.BadRequest("incorrect delivery code")
+		}
+		out.OtpVerified = true
+	} else if in.OTP != "" {
+		out.OtpVerified = otpMatches(issued, in.OTP)
+	}
+
+	if req.Photo && in.PhotoURL == "" {
+		return verifiedProof{}, apperror.BadRequest("delivery photo is required")
+	}
+
+	if req.RadiusM != nil {
+		loc, err := s.q.GetRiderLocation(ctx, riderID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return verifiedProof{}, apperror.Internal("get rider location", err)
+		}
+		if err == nil {
+			out.Location = &loc
+		}
+		out.Geofence = evaluateGeofence(out.Location, order.DeliveryGeoLat, order.DeliveryGeoLng, req.RadiusM, time.Now())
+
+		if req.Required {
+			_, hasLat := numericToFloat64(order.DeliveryGeoLat)
+			_, hasLng := numericToFloat64(order.DeliveryGeoLng)
+			switch {
+			case !hasLat || !hasLng:
+				// Nothing to measure against; the OTP and photo stand alone.
+			case !out.Geofence.Checked:
+				return verifiedProof{}, apperror.BadRequest("no recent location from your device; turn on GPS and try again")
+			case !out.Geofence.Within:
+				return verifiedProof{}, apperror.BadRequest(fmt.Sprintf(
+					"you are %sm from the delivery address; deliveries must be confirmed within %dm",
+					out.Geofence.DistanceM.Round(0), *req.RadiusM))
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// recordDeliveryProof stores the completed proof record for a delivered order.
+func recordDeliveryProof(ctx context.Context, q *sqlc.Queries, order sqlc.Order, riderID uuid.UUID, in DeliveryProofInput, v verifiedProof) (sqlc.DeliveryProof, error) {
+	params := sqlc.CompleteDeliveryProofParams{
+		TenantID:        order.TenantID,
+		OrderID:         order.ID,
+		RiderID:         pgtype.UUID{Bytes: riderID, Valid: true},
+		PodRequired:     v.Requirement.Required,
+		PhotoUrl:        nullString(in.PhotoURL),
+		GeofenceRadiusM: v.Requirement.RadiusM,
+	}
+	if v.OtpVerified {
+		params.OtpVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+	if v.Location != nil {
+		params.RiderGeoLat = v.Location.GeoLat
+		params.RiderGeoLng = v.Location.GeoLng
+		params.LocationRecordedAt = pgtype.Timestamptz{Time: v.Location.UpdatedAt, Valid: true}
+	}
+	if v.Geofence.Checked {
+		within := v.Geofence.Within
+		params.DistanceMeters = toPgNumeric(v.Geofence.DistanceM)
+		params.WithinGeofence = &within
+	}
+	proof, err := q.CompleteDeliveryProof(ctx, params)
+	if err != nil {
+		return sqlc.DeliveryProof{}, apperror.Internal("record delivery proof", err)
+	}
+	return proof, nil
+}
+
+// openDeliveryProof starts the proof record for an order and issues its
+// delivery code when the policy asks for one. Calling it again keeps the
+// existing code.
+func openDeliveryProof(ctx context.Context, q *sqlc.Queries, order sqlc.Order, riderID pgtype.UUID) (sqlc.DeliveryProof, podRequirement, error) {
+	req, err := loadPodRequirement(ctx, q, order)
+	if err != nil {
+		return sqlc.DeliveryProof{}, podRequirement{}, err
+	}
+	var code sql.NullString
+	if req.OTP {
+		otp, err := generateDeliveryOtp()
+		if err != nil {
+			return sqlc.DeliveryProof{}, podRequirement{}, apperror.Internal("generate delivery code", err)
+		}
+		code = sql.NullString{String: otp, Valid: true}
+	}
+	proof, err := q.OpenDeliveryProof(ctx, sqlc.OpenDeliveryProofParams{
+		TenantID:    order.TenantID,
+		OrderID:     order.ID,
+		RiderID:     riderID,
+		PodRequired: req.Required,
+		OtpCode:     code,
+	})
+	if err != nil {
+		return sqlc.DeliveryProof{}, podRequirement{}, apperror.Internal("open delivery proof", err)
+	}
+	return proof, req, nil
+}
+
+// DeliveryCode is the handover code shown to the customer.
+type DeliveryCode struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Code    string    `json:"code"`
+}
+
+// GetDeliveryCode returns the delivery code for a customer's order that is on
+// its way. Orders that don't need a code return not found.
+func (s *Service) GetDeliveryCode(ctx context.Context, tenantID, orderID, customerID uuid.UUID) (DeliveryCode, error) {
+	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && order.CustomerID != customerID) {
+		return DeliveryCode{}, apperror.NotFound("order")
+	}
+	if err != nil {
+		return DeliveryCode{}, apperror.Internal("get order", err)
+	}
+	switch order.Status {
+	case sqlc.OrderStatusDelivered, sqlc.OrderStatusCancelled, sqlc.OrderStatusRejected:
+		return DeliveryCode{}, apperror.NotFound("delivery code")
+	}
+
+	proof, req, err := openDeliveryProof(ctx, s.q, order, pgtype.UUID{})
+	if err != nil {
+		return DeliveryCode{}, err
+	}
+	if !req.OTP || !proof.OtpCode.Valid {
+		return DeliveryCode{}, apperror.NotFound("delivery code")
+	}
+	return DeliveryCode{OrderID: order.ID, Code: proof.OtpCode.String}, nil
+}
+
+// GetDeliveryProof returns the proof of delivery record for an order. The
+// delivery code itself is withheld.
+func (s *Service) GetDeliveryProof(ctx context.Context, tenantID, orderID uuid.UUID) (sqlc.DeliveryProof, error) {
+	proof, err := s.q.GetDeliveryProofByOrder(ctx, sqlc.GetDeliveryProofByOrderParams{
+		OrderID: orderID, TenantID: tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.DeliveryProof{}, apperror.NotFound("delivery proof")
+	}
+	if err != nil {
+		return sqlc.DeliveryProof{}, apperror.Internal("get delivery proof", err)
+	}
+	proof.OtpCode = sql.NullString{}
+	return proof, nil
+}
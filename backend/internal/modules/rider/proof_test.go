@@ -0,0 +1,80 @@
+package rider
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestPodRequirementFor(t *testing.T) {
+	radius := int32(150)
+	cfg := sqlc.DeliveryZoneConfig{
+		PodOtpEnabled:      true,
+		PodPhotoRequired:   true,
+		PodGeofenceRadiusM: &radius,
+		PodMinOrderValue:   toPgNumeric(dec("2000")),
+	}
+
+	if req := podRequirementFor(cfg, false, dec("1999.99")); req.Required || req.OTP || req.Photo {
+		t.Errorf("below the threshold = %+v, want nothing enforced", req)
+	}
+	if req := podRequirementFor(cfg, false, dec("2000")); !req.Required || !req.OTP || !req.Photo || *req.RadiusM != 150 {
+		t.Errorf("at the threshold = %+v, want OTP, photo and a 150m geofence", req)
+	}
+	if req := podRequirementFor(cfg, true, dec("300")); !req.Required {
+		t.Error("a restaurant that requires POD should require it regardless of value")
+	}
+
+	cfg.PodMinOrderValue = pgtype.Numeric{}
+	if req := podRequirementFor(cfg, false, dec("99999")); req.Required {
+		t.Error("without a threshold only restaurants can require POD")
+	}
+}
+
+func TestOtpMatches(t *testing.T) {
+	issued := sql.NullString{String: "0427", Valid: true}
+
+	if !otpMatches(issued, "0427") {
+		t.Error("the issued code should match")
+	}
+	if otpMatches(issued, "427") || otpMatches(issued, "") {
+		t.Error("a different or empty code should not match")
+	}
+	if otpMatches(sql.NullString{}, "") {
+		t.Error("nothing should match when no code was issued")
+	}
+}
+
+func TestEvaluateGeofence(t *testing.T) {
+	now := time.Now()
+	radius := int32(100)
+	destLat, destLng := toPgNumeric(dec("23.7925")), toPgNumeric(dec("90.4078"))
+	loc := func(lat, lng string, age time.Duration) *sqlc.RiderLocation {
+		return &sqlc.RiderLocation{GeoLat: toPgNumeric(dec(lat)), GeoLng: toPgNumeric(dec(lng)), UpdatedAt: now.Add(-age)}
+	}
+
+	// About 55m north of the address.
+	g := evaluateGeofence(loc("23.7930", "90.4078", time.Minute), destLat, destLng, &radius, now)
+	if !g.Checked || !g.Within || g.DistanceM.IntPart() != 55 {
+		t.Errorf("near = %+v, want within at about 55m", g)
+	}
+
+	// About 1.1km away.
+	g = evaluateGeofence(loc("23.8025", "90.4078", time.Minute), destLat, destLng, &radius, now)
+	if !g.Checked || g.Within {
+		t.Errorf("far = %+v, want checked and outside", g)
+	}
+
+	if g := evaluateGeofence(loc("23.7925", "90.4078", 10*time.Minute), destLat, destLng, &radius, now); g.Checked {
+		t.Error("a stale location should not be checked")
+	}
+	if g := evaluateGeofence(nil, destLat, destLng, &radius, now); g.Checked {
+		t.Error("a missing location should not be checked")
+	}
+	if g := evaluateGeofence(loc("23.7925", "90.4078", 0), pgtype.Numeric{}, pgtype.Numeric{}, &radius, now); g.Checked {
+		t.Error("an order without coordinates should not be checked")
+	}
+}
@@ -0,0 +1,579 @@
+package rider
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// Rider tiers, lowest first. Riders with too few recent deliveries stay new.
+const (
+	TierNew      = "new"
+	TierBronze   = "bronze"
+	TierSilver   = "silver"
+	TierGold     = "gold"
+	TierPlatinum = "platinum"
+)
+
+const (
+	// scorecardWindowDays is the rolling window tiers are computed over.
+	scorecardWindowDays = 30
+	// scorecardRecomputeDays days are recomputed on every run so late ratings
+	// and decided penalty appeals reach the scorecard.
+	scorecardRecomputeDays = 7
+	minTierDeliveries      = 20
+	maxScorecardRangeDays  = 92
+
+	pickupGrace   = 10 * time.Minute
+	deliveryGrace = 5 * time.Minute
+	// defaultDeliveryMinutes applies to orders placed without an estimate;
+	// it matches the delivery area default.
+	defaultDeliveryMinutes = 45
+
+	penaltyPoints    = 5.0
+	maxPenaltyPoints = 25.0
+)
+
+// tierAllowanceKm is how much further a rider of each tier may be than the
+// nearest rider and still be offered an order first.
+var tierAllowanceKm = map[string]float64{
+	TierPlatinum: 1.5,
+	TierGold:     1.0,
+	TierSilver:   0.5,
+}
+
+// Score weights. A metric without data that day is left out and the rest
+// are reweighted.
+const (
+	weightAcceptance     = 0.20
+	weightOnTimePickup   = 0.15
+	weightOnTimeDelivery = 0.25
+	weightRating         = 0.20
+	weightAttendance     = 0.10
+	weightCancellations  = 0.10
+)
+
+// scorecardCounts are the raw counts behind a scorecard. They add up across
+// days, so a window is summarised by summing its days.
+type scorecardCounts struct {
+	OffersReceived     int
+	OffersAccepted     int
+	OffersDeclined     int
+	OffersExpired      int
+	Pickups            int
+	PickupsOnTime      int
+	Deliveries         int
+	DeliveriesOnTime   int
+	CancelledOrders    int
+	RiderCancellations int
+	RatingsCount       int
+	RatingsSum         int
+	PenaltiesCount     int
+	PenaltiesAmount    decimal.Decimal
+	ShiftsRostered     int
+	ShiftsAttended     int
+	ShiftsOnTime       int
+}
+
+func (c *scorecardCounts) add(o scorecardCounts) {
+	c.OffersReceived += o.OffersReceived
+	c.OffersAccepted += o.OffersAccepted
+	c.OffersDeclined += o.OffersDeclined
+	c.OffersExpired += o.OffersExpired
+	c.Pickups += o.Pickups
+	c.PickupsOnTime += o.PickupsOnTime
+	c.Deliveries += o.Deliveries
+	c.DeliveriesOnTime += o.DeliveriesOnTime
+	c.CancelledOrders += o.CancelledOrders
+	c.RiderCancellations += o.RiderCancellations
+	c.RatingsCount += o.RatingsCount
+	c.RatingsSum += o.RatingsSum
+	c.PenaltiesCount += o.PenaltiesCount
+	c.PenaltiesAmount = c.PenaltiesAmount.Add(o.PenaltiesAmount)
+	c.ShiftsRostered += o.ShiftsRostered
+	c.ShiftsAttended += o.ShiftsAttended
+	c.ShiftsOnTime += o.ShiftsOnTime
+}
+
+func countsFromScorecard(sc sqlc.RiderScorecard) scorecardCounts {
+	return scorecardCounts{
+		OffersReceived:     int(sc.OffersReceived),
+		OffersAccepted:     int(sc.OffersAccepted),
+		OffersDeclined:     int(sc.OffersDeclined),
+		OffersExpired:      int(sc.OffersExpired),
+		Pickups:            int(sc.Pickups),
+		PickupsOnTime:      int(sc.PickupsOnTime),
+		Deliveries:         int(sc.Deliveries),
+		DeliveriesOnTime:   int(sc.DeliveriesOnTime),
+		CancelledOrders:    int(sc.CancelledOrders),
+		RiderCancellations: int(sc.RiderCancellations),
+		RatingsCount:       int(sc.RatingsCount),
+		RatingsSum:         int(sc.RatingsSum),
+		PenaltiesCount:     int(sc.PenaltiesCount),
+		PenaltiesAmount:    numericToDecimal(sc.PenaltiesAmount),
+		ShiftsRostered:     int(sc.ShiftsRostered),
+		ShiftsAttended:     int(sc.ShiftsAttended),
+		ShiftsOnTime:       int(sc.ShiftsOnTime),
+	}
+}
+
+// ScorecardRates are the derived rates, as fractions between 0 and 1. A nil
+// rate had nothing to measure.
+type ScorecardRates struct {
+	AcceptanceRate     *float64 `json:"acceptance_rate"`
+	OnTimePickupRate   *float64 `json:"on_time_pickup_rate"`
+	OnTimeDeliveryRate *float64 `json:"on_time_delivery_rate"`
+	CancellationRate   *float64 `json:"cancellation_rate"`
+	AverageRating      *float64 `json:"average_rating"`
+	AttendanceRate     *float64 `json:"attendance_rate"`
+}
+
+func ratio(n, d int) *float64 {
+	if d == 0 {
+		return nil
+	}
+	r := math.Round(float64(n)/float64(d)*1000) / 1000
+	return &r
+}
+
+func (c scorecardCounts) rates() ScorecardRates {
+	r := ScorecardRates{
+		AcceptanceRate:     ratio(c.OffersAccepted, c.OffersReceived),
+		OnTimePickupRate:   ratio(c.PickupsOnTime, c.Pickups),
+		OnTimeDeliveryRate: ratio(c.DeliveriesOnTime, c.Deliveries),
+		CancellationRate:   ratio(c.RiderCancellations, c.Deliveries+c.CancelledOrders),
+	}
+	if c.RatingsCount > 0 {
+		avg := math.Round(float64(c.RatingsSum)/float64(c.RatingsCount)*100) / 100
+		r.AverageRating = &avg
+	}
+	if c.ShiftsRostered > 0 {
+		// A late check-in counts for half a shift.
+		adherence := (float64(c.ShiftsOnTime) + float64(c.ShiftsAttended-c.ShiftsOnTime)/2) / float64(c.ShiftsRostered)
+		adherence = math.Round(adherence*1000) / 1000
+		r.AttendanceRate = &adherence
+	}
+	return r
+}
+
+// score rates the counts from 0 to 100. It reports false when there is
+// nothing to rate.
+func (c scorecardCounts) score() (float64, bool) {
+	r := c.rates()
+	var sum, weights float64
+	addMetric := func(v *float64, weight float64) {
+		if v == nil {
+			return
+		}
+		sum += *v * weight
+		weights += weight
+	}
+	addMetric(r.AcceptanceRate, weightAcceptance)
+	addMetric(r.OnTimePickupRate, weightOnTimePickup)
+	addMetric(r.OnTimeDeliveryRate, weightOnTimeDelivery)
+	if r.CancellationRate != nil {
+		kept := 1 - *r.CancellationRate
+		addMetric(&kept, weightCancellations)
+	}
+	if r.AverageRating != nil {
+		rating := (*r.AverageRating - 1) / 4
+		addMetric(&rating, weightRating)
+	}
+	addMetric(r.AttendanceRate, weightAttendance)
+	if weights == 0 {
+		return 0, false
+	}
+
+	score := sum / weights * 100
+	score -= math.Min(float64(c.PenaltiesCount)*penaltyPoints, maxPenaltyPoints)
+	if score < 0 {
+		score = 0
+	}
+	return math.Round(score*100) / 100, true
+}
+
+// tierFor places a rider by their rolling scorecard.
+func tierFor(c scorecardCounts) (string, *float64) {
+	score, ok := c.score()
+	if !ok {
+		return TierNew, nil
+	}
+	if c.Deliveries < minTierDeliveries {
+		return TierNew, &score
+	}
+	switch {
+	case score >= 90:
+		return TierPlatinum, &score
+	case score >= 80:
+		return TierGold, &score
+	case score >= 65:
+		return TierSilver, &score
+	default:
+		return TierBronze, &score
+	}
+}
+
+// dispatchRank orders riders for an offer: distance, less the allowance
+// their tier earns.
+func dispatchRank(distanceKm float64, tier string) float64 {
+	return distanceKm - tierAllowanceKm[tier]
+}
+
+// pickupOnTime reports whether the rider collected the food within the grace
+// period of it being ready. Without a ready mark the restaurant's average
+// preparation time from confirmation stands in.
+func pickupOnTime(p sqlc.ListPickupTimingsForDayRow) bool {
+	due := p.CreatedAt
+	if p.ConfirmedAt.Valid {
+		due = p.ConfirmedAt.Time
+	}
+	due = due.Add(time.Duration(p.AvgPrepTimeMinutes) * time.Minute)
+	if p.ReadyAt.Valid {
+		due = p.ReadyAt.Time
+	}
+	return !p.PickedAt.Time.After(due.Add(pickupGrace))
+}
+
+// deliveryOnTime compares delivery with the estimate quoted at checkout.
+func deliveryOnTime(d sqlc.ListDeliveryTimingsForDayRow) bool {
+	minutes := int32(defaultDeliveryMinutes)
+	if d.EstimatedDeliveryMinutes != nil && *d.EstimatedDeliveryMinutes > 0 {
+		minutes = *d.EstimatedDeliveryMinutes
+	}
+	due := d.CreatedAt.Add(time.Duration(minutes) * time.Minute)
+	return !d.DeliveredAt.Time.After(due.Add(deliveryGrace))
+}
+
+// ---------- Daily job ----------
+
+// RunScorecards recomputes the scorecards of the last few Dhaka days for
+// every active tenant and re-tiers its riders. It is safe to run repeatedly.
+func (s *Service) RunScorecards(ctx context.Context) error {
+	today := timeutil.StartOfDayBD(time.Now())
+	tenantIDs, err := s.q.ListActiveTenantIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, tenantID := range tenantIDs {
+		for d := scorecardRecomputeDays; d >= 1; d-- {
+			if err := s.computeScorecards(ctx, tenantID, today.AddDate(0, 0, -d)); err != nil {
+				log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("failed to compute rider scorecards")
+				break
+			}
+		}
+		if err := s.updateTiers(ctx, tenantID, today); err != nil {
+			log.Error().Err(err).Str("tenant_id", tenantID.String()).Msg("failed to update rider tiers")
+		}
+	}
+	return nil
+}
+
+// ExpireAssignmentOffers closes offers nobody answered in time.
+func (s *Service) ExpireAssignmentOffers(ctx context.Context) error {
+	n, err := s.q.ExpireAssignmentOffers(ctx, time.Now().Add(-batchTimeoutSec*time.Second))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Info().Int64("offers", n).Msg("expired unanswered assignment offers")
+	}
+	return nil
+}
+
+// computeScorecards writes the scorecard of every rider with activity on the
+// Dhaka day starting at day.
+func (s *Service) computeScorecards(ctx context.Context, tenantID uuid.UUID, day time.Time) error {
+	from, to := day, day.AddDate(0, 0, 1)
+	counts := map[uuid.UUID]*scorecardCounts{}
+	get := func(id uuid.UUID) *scorecardCounts {
+		c, ok := counts[id]
+		if !ok {
+			c = &scorecardCounts{}
+			counts[id] = c
+		}
+		return c
+	}
+
+	offers, err := s.q.ListOfferCountsForDay(ctx, sqlc.ListOfferCountsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, o := range offers {
+		c := get(o.RiderID)
+		c.OffersReceived, c.OffersAccepted = int(o.Received), int(o.Accepted)
+		c.OffersDeclined, c.OffersExpired = int(o.Declined), int(o.Expired)
+	}
+
+	pickups, err := s.q.ListPickupTimingsForDay(ctx, sqlc.ListPickupTimingsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, p := range pickups {
+		c := get(p.RiderID)
+		c.Pickups++
+		if pickupOnTime(p) {
+			c.PickupsOnTime++
+		}
+	}
+
+	deliveries, err := s.q.ListDeliveryTimingsForDay(ctx, sqlc.ListDeliveryTimingsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		c := get(d.RiderID)
+		c.Deliveries++
+		if deliveryOnTime(d) {
+			c.DeliveriesOnTime++
+		}
+	}
+
+	cancellations, err := s.q.ListCancellationCountsForDay(ctx, sqlc.ListCancellationCountsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, x := range cancellations {
+		c := get(x.RiderID)
+		c.CancelledOrders, c.RiderCancellations = int(x.Cancelled), int(x.ByRider)
+	}
+
+	ratings, err := s.q.ListRiderRatingCountsForDay(ctx, sqlc.ListRiderRatingCountsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, r := range ratings {
+		c := get(r.RiderID)
+		c.RatingsCount, c.RatingsSum = int(r.Ratings), int(r.RatingSum)
+	}
+
+	penalties, err := s.q.ListPenaltyCountsForDay(ctx, sqlc.ListPenaltyCountsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, p := range penalties {
+		c := get(p.RiderID)
+		c.PenaltiesCount, c.PenaltiesAmount = int(p.Penalties), numericToDecimal(p.Amount)
+	}
+
+	shifts, err := s.q.ListShiftCountsForDay(ctx, sqlc.ListShiftCountsForDayParams{TenantID: tenantID, FromTime: from, ToTime: to})
+	if err != nil {
+		return err
+	}
+	for _, sh := range shifts {
+		c := get(sh.RiderID)
+		c.ShiftsRostered, c.ShiftsAttended, c.ShiftsOnTime = int(sh.Rostered), int(sh.Attended), int(sh.OnTime)
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+	riders, err := s.q.ListTenantRiderHubs(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	hubs := make(map[uuid.UUID]pgtype.UUID, len(riders))
+	for _, r := range riders {
+		hubs[r.ID] = r.HubID
+	}
+
+	for riderID, c := range counts {
+		if _, ok := hubs[riderID]; !ok {
+			continue
+		}
+		score := pgtype.Numeric{}
+		if v, ok := c.score(); ok {
+			score = toPgNumeric(decimal.NewFromFloat(v))
+		}
+		if _, err := s.q.UpsertRiderScorecard(ctx, sqlc.UpsertRiderScorecardParams{
+			TenantID:           tenantID,
+			RiderID:            riderID,
+			HubID:              hubs[riderID],
+			ScorecardDate:      pgDate(day),
+			OffersReceived:     int32(c.OffersReceived),
+			OffersAccepted:     int32(c.OffersAccepted),
+			OffersDeclined:     int32(c.OffersDeclined),
+			OffersExpired:      int32(c.OffersExpired),
+			Pickups:            int32(c.Pickups),
+			PickupsOnTime:      int32(c.PickupsOnTime),
+			Deliveries:         int32(c.Deliveries),
+			DeliveriesOnTime:   int32(c.DeliveriesOnTime),
+			CancelledOrders:    int32(c.CancelledOrders),
+			RiderCancellations: int32(c.RiderCancellations),
+			RatingsCount:       int32(c.RatingsCount),
+			RatingsSum:         int32(c.RatingsSum),
+			PenaltiesCount:     int32(c.PenaltiesCount),
+			PenaltiesAmount:    toPgNumeric(c.PenaltiesAmount),
+			ShiftsRostered:     int32(c.ShiftsRostered),
+			ShiftsAttended:     int32(c.ShiftsAttended),
+			ShiftsOnTime:       int32(c.ShiftsOnTime),
+			Score:              score,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateTiers re-tiers every rider of the tenant from the rolling window
+// ending yesterday. Riders without scorecards in the window fall back to new.
+func (s *Service) updateTiers(ctx context.Context, tenantID uuid.UUID, today time.Time) error {
+	cards, err := s.q.ListTenantScorecardsSince(ctx, sqlc.ListTenantScorecardsSinceParams{
+		TenantID: tenantID,
+		FromDate: pgDate(today.AddDate(0, 0, -scorecardWindowDays)),
+	})
+	if err != nil {
+		return err
+	}
+	window := map[uuid.UUID]*scorecardCounts{}
+	for _, sc := range cards {
+		c, ok := window[sc.RiderID]
+		if !ok {
+			c = &scorecardCounts{}
+			window[sc.RiderID] = c
+		}
+		c.add(countsFromScorecard(sc))
+	}
+
+	riders, err := s.q.ListTenantRiderHubs(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, r := range riders {
+		var c scorecardCounts
+		if w, ok := window[r.ID]; ok {
+			c = *w
+		}
+		tier, score := tierFor(c)
+		params := sqlc.UpdateRiderTierParams{ID: r.ID, TenantID: tenantID, Tier: tier}
+		if score != nil {
+			params.PerformanceScore = toPgNumeric(decimal.NewFromFloat(*score))
+		}
+		if err := s.q.UpdateRiderTier(ctx, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ---------- Reading scorecards ----------
+
+// Scorecard summarises a rider's daily scorecards over a date range.
+type Scorecard struct {
+	RiderID            uuid.UUID             `json:"rider_id"`
+	From               string                `json:"from"`
+	To                 string                `json:"to"`
+	Tier               string                `json:"tier"`
+	TierScore          *float64              `json:"tier_score"`
+	Score              *float64              `json:"score"`
+	Rates              ScorecardRates        `json:"rates"`
+	OffersReceived     int                   `json:"offers_received"`
+	Deliveries         int                   `json:"deliveries"`
+	CancelledOrders    int                   `json:"cancelled_orders"`
+	RiderCancellations int                   `json:"rider_cancellations"`
+	RatingsCount       int                   `json:"ratings_count"`
+	LifetimeRating     float64               `json:"lifetime_rating"`
+	LifetimeRatings    int32                 `json:"lifetime_ratings"`
+	PenaltiesCount     int                   `json:"penalties_count"`
+	PenaltiesAmount    decimal.Decimal       `json:"penalties_amount"`
+	ShiftsRostered     int                   `json:"shifts_rostered"`
+	Days               []sqlc.RiderScorecard `json:"days"`
+}
+
+// GetScorecard returns a rider's scorecard between two Dhaka dates.
+func (s *Service) GetScorecard(ctx context.Context, rider sqlc.Rider, from, to time.Time) (Scorecard, error) {
+	if to.Before(from) {
+		return Scorecard{}, apperror.BadRequest("to must not be before from")
+	}
+	if to.Sub(from) > maxScorecardRangeDays*24*time.Hour {
+		return Scorecard{}, apperror.BadRequest("date range is limited to 92 days")
+	}
+	days, err := s.q.ListRiderScorecards(ctx, sqlc.ListRiderScorecardsParams{
+		RiderID: rider.ID, TenantID: rider.TenantID, FromDate: pgDate(from), ToDate: pgDate(to),
+	})
+	if err != nil {
+		return Scorecard{}, apperror.Internal("list scorecards", err)
+	}
+
+	var c scorecardCounts
+	for _, d := range days {
+		c.add(countsFromScorecard(d))
+	}
+	sc := Scorecard{
+		RiderID:            rider.ID,
+		From:               from.Format("2006-01-02"),
+		To:                 to.Format("2006-01-02"),
+		Tier:               rider.Tier,
+		Rates:              c.rates(),
+		OffersReceived:     c.OffersReceived,
+		Deliveries:         c.Deliveries,
+		CancelledOrders:    c.CancelledOrders,
+		RiderCancellations: c.RiderCancellations,
+		RatingsCount:       c.RatingsCount,
+		LifetimeRatings:    rider.RatingCount,
+		PenaltiesCount:     c.PenaltiesCount,
+		PenaltiesAmount:    c.PenaltiesAmount,
+		ShiftsRostered:     c.ShiftsRostered,
+		Days:               days,
+	}
+	if v, ok := c.score(); ok {
+		sc.Score = &v
+	}
+	if v, ok := numericToFloat64(rider.PerformanceScore); ok {
+		sc.TierScore = &v
+	}
+	sc.LifetimeRating, _ = numericToFloat64(rider.RatingAvg)
+	if sc.Days == nil {
+		sc.Days = []sqlc.RiderScorecard{}
+	}
+	return sc, nil
+}
+
+// GetRiderScorecard is the partner view of a rider's scorecard, limited to
+// the manager of the rider's hub and tenant owners and admins.
+func (s *Service) GetRiderScorecard(ctx context.Context, user *sqlc.User, tenantID, riderID uuid.UUID, from, to time.Time) (Scorecard, error) {
+	rider, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Scorecard{}, apperror.NotFound("rider")
+	}
+	if err != nil {
+		return Scorecard{}, apperror.Internal("get rider", err)
+	}
+	if err := authorizeRiderManager(ctx, s.q, user, rider); err != nil {
+		return Scorecard{}, err
+	}
+	return s.GetScorecard(ctx, rider, from, to)
+}
+
+// ListRiderTiers lists riders by performance score, best first.
+func (s *Service) ListRiderTiers(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, tier string, limit, offset int32) ([]sqlc.ListRiderTiersRow, error) {
+	switch tier {
+	case "", TierNew, TierBronze, TierSilver, TierGold, TierPlatinum:
+	default:
+		return nil, apperror.BadRequest("unknown tier")
+	}
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	riders, err := s.q.ListRiderTiers(ctx, sqlc.ListRiderTiersParams{
+		TenantID: tenantID,
+		HubID:    hub,
+		Tier:     nullString(tier),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list rider tiers", err)
+	}
+	return riders, nil
+}
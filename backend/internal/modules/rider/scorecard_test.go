@@ -0,0 +1,148 @@
+package rider
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestScorecardScore(t *testing.T) {
+	if _, ok := (scorecardCounts{}).score(); ok {
+		t.Error("a day without activity should have no score")
+	}
+
+	perfect := scorecardCounts{
+		OffersReceived: 10, OffersAccepted: 10,
+		Pickups: 10, PickupsOnTime: 10,
+		Deliveries: 10, DeliveriesOnTime: 10,
+		RatingsCount: 4, RatingsSum: 20,
+		ShiftsRostered: 1, ShiftsAttended: 1, ShiftsOnTime: 1,
+	}
+	if got, ok := perfect.score(); !ok || got != 100 {
+		t.Errorf("perfect day = %v (%v), want 100", got, ok)
+	}
+
+	penalised := perfect
+	penalised.PenaltiesCount = 2
+	if got, _ := penalised.score(); got != 90 {
+		t.Errorf("two penalties = %v, want 90", got)
+	}
+	penalised.PenaltiesCount = 10
+	if got, _ := penalised.score(); got != 75 {
+		t.Errorf("ten penalties = %v, want 75 with the deduction capped", got)
+	}
+
+	// Only deliveries were measured: half on time and none cancelled. The
+	// other metrics are left out rather than counted as zero.
+	partial := scorecardCounts{Deliveries: 4, DeliveriesOnTime: 2}
+	if got, _ := partial.score(); got != 64.29 {
+		t.Errorf("partial day = %v, want 64.29", got)
+	}
+}
+
+func TestScorecardRates(t *testing.T) {
+	c := scorecardCounts{
+		OffersReceived: 4, OffersAccepted: 3,
+		Deliveries: 8, CancelledOrders: 2, RiderCancellations: 1,
+		ShiftsRostered: 4, ShiftsAttended: 3, ShiftsOnTime: 2,
+	}
+	r := c.rates()
+	if r.AcceptanceRate == nil || *r.AcceptanceRate != 0.75 {
+		t.Errorf("acceptance = %v, want 0.75", r.AcceptanceRate)
+	}
+	if r.CancellationRate == nil || *r.CancellationRate != 0.1 {
+		t.Errorf("cancellation = %v, want 0.1", r.CancellationRate)
+	}
+	// Two on time, one late at half credit, one absent.
+	if r.AttendanceRate == nil || *r.AttendanceRate != 0.625 {
+		t.Errorf("attendance = %v, want 0.625", r.AttendanceRate)
+	}
+	if r.OnTimePickupRate != nil || r.AverageRating != nil {
+		t.Error("unmeasured rates should be nil")
+	}
+}
+
+func TestTierFor(t *testing.T) {
+	day := scorecardCounts{Deliveries: 10, DeliveriesOnTime: 10, OffersReceived: 10, OffersAccepted: 10}
+	if tier, score := tierFor(day); tier != TierNew || score == nil {
+		t.Errorf("ten deliveries = %s, want new until the minimum is reached", tier)
+	}
+	if tier, score := tierFor(scorecardCounts{}); tier != TierNew || score != nil {
+		t.Errorf("no activity = %s %v, want new without a score", tier, score)
+	}
+
+	var month scorecardCounts
+	for i := 0; i < 3; i++ {
+		month.add(day)
+	}
+	if tier, _ := tierFor(month); tier != TierPlatinum {
+		t.Errorf("spotless month = %s, want platinum", tier)
+	}
+	month.PenaltiesCount = 3
+	if tier, _ := tierFor(month); tier != TierGold {
+		t.Errorf("three penalties = %s, want gold", tier)
+	}
+	month.DeliveriesOnTime = 10
+	if tier, _ := tierFor(month); tier != TierBronze {
+		t.Errorf("mostly late = %s, want bronze", tier)
+	}
+}
+
+func TestDispatchRank(t *testing.T) {
+	riders := []riderDistance{
+		{rider: sqlc.Rider{Tier: TierBronze}, distance: 1.0},
+		{rider: sqlc.Rider{Tier: TierPlatinum}, distance: 2.0},
+		{rider: sqlc.Rider{Tier: TierGold}, distance: 3.0},
+	}
+	for i := range riders {
+		riders[i].rank = dispatchRank(riders[i].distance, riders[i].rider.Tier)
+	}
+	sort.Slice(riders, func(i, j int) bool { return riders[i].rank < riders[j].rank })
+	if riders[0].rider.Tier != TierPlatinum || riders[1].rider.Tier != TierBronze {
+		t.Errorf("order = %s, %s, %s; want the platinum rider 1km further away first",
+			riders[0].rider.Tier, riders[1].rider.Tier, riders[2].rider.Tier)
+	}
+}
+
+func TestPickupOnTime(t *testing.T) {
+	created := bdTime(t, "2026-03-02 12:00")
+	ts := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: created.Add(d), Valid: true} }
+
+	tests := []struct {
+		name string
+		p    sqlc.ListPickupTimingsForDayRow
+		want bool
+	}{
+		{"picked soon after ready", sqlc.ListPickupTimingsForDayRow{ReadyAt: ts(20 * time.Minute), PickedAt: ts(28 * time.Minute)}, true},
+		{"left waiting", sqlc.ListPickupTimingsForDayRow{ReadyAt: ts(20 * time.Minute), PickedAt: ts(35 * time.Minute)}, false},
+		{"no ready mark, within prep time", sqlc.ListPickupTimingsForDayRow{ConfirmedAt: ts(5 * time.Minute), AvgPrepTimeMinutes: 20, PickedAt: ts(30 * time.Minute)}, true},
+		{"no ready mark, late", sqlc.ListPickupTimingsForDayRow{AvgPrepTimeMinutes: 20, PickedAt: ts(31 * time.Minute)}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.p.CreatedAt = created
+			if got := pickupOnTime(tc.p); got != tc.want {
+				t.Errorf("pickupOnTime = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeliveryOnTime(t *testing.T) {
+	created := bdTime(t, "2026-03-02 12:00")
+	at := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: created.Add(d), Valid: true} }
+	thirty := int32(30)
+
+	if !deliveryOnTime(sqlc.ListDeliveryTimingsForDayRow{CreatedAt: created, EstimatedDeliveryMinutes: &thirty, DeliveredAt: at(34 * time.Minute)}) {
+		t.Error("within the grace period should be on time")
+	}
+	if deliveryOnTime(sqlc.ListDeliveryTimingsForDayRow{CreatedAt: created, EstimatedDeliveryMinutes: &thirty, DeliveredAt: at(36 * time.Minute)}) {
+		t.Error("past the estimate and grace should be late")
+	}
+	if !deliveryOnTime(sqlc.ListDeliveryTimingsForDayRow{CreatedAt: created, DeliveredAt: at(45 * time.Minute)}) {
+		t.Error("an order without an estimate should use the default")
+	}
+}
@@ -11,19 +11,29 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/analytics"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
 	"github.com/rs/zerolog/log"
 )
 
 // Service implements rider business logic.
 type Service struct {
-	q *sqlc.Queries
+	q        *sqlc.Queries
+	pool     *pgxpool.Pool
+	notifier Notifier
 }
 
-// NewService creates a new rider service.
-func NewService(q *sqlc.Queries) *Service {
-	return &Service{q: q}
+// Notifier delivers push notifications to riders.
+type Notifier interface {
+	SendPush(ctx context.Context, tenantID *uuid.UUID, userID uuid.UUID, title, body string, data map[string]string) error
+}
+
+// NewService creates a new rider service. notifier may be nil, in which case
+// riders are not notified.
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool, notifier Notifier) *Service {
+	return &Service{q: q, pool: pool, notifier: notifier}
 }
 
 // CreateRiderParams holds input for rider creation.
@@ -34,7 +44,6 @@ type CreateRiderParams struct {
 	VehicleRegistration string
 	LicenseNumber       string
 	NidNumber           string
-	NidVerified         bool
 }
 
 // CreateRider creates a new rider profile.
@@ -52,7 +61,6 @@ func (s *Service) CreateRider(ctx context.Context, tenantID uuid.UUID, p CreateR
 		VehicleRegistration: sql.NullString{String: p.VehicleRegistration, Valid: p.VehicleRegistration != ""},
 		LicenseNumber:       sql.NullString{String: p.LicenseNumber, Valid: p.LicenseNumber != ""},
 		NidNumber:           sql.NullString{String: p.NidNumber, Valid: p.NidNumber != ""},
-		NidVerified:         p.NidVerified,
 	})
 	if err != nil {
 		return sqlc.Rider{}, apperror.Internal("create rider", err)
@@ -108,7 +116,6 @@ type UpdateRiderParams struct {
 	VehicleRegistration string
 	LicenseNumber       string
 	NidNumber           string
-	NidVerified         *bool
 }
 
 // UpdateRider updates a rider profile.
@@ -131,7 +138,6 @@ func (s *Service) UpdateRider(ctx context.Context, id, tenantID uuid.UUID, p Upd
 		VehicleRegistration: sql.NullString{String: p.VehicleRegistration, Valid: p.VehicleRegistration != ""},
 		LicenseNumber:       sql.NullString{String: p.LicenseNumber, Valid: p.LicenseNumber != ""},
 		NidNumber:           sql.NullString{String: p.NidNumber, Valid: p.NidNumber != ""},
-		NidVerified:         p.NidVerified,
 	})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return sqlc.Rider{}, apperror.NotFound("rider")
@@ -150,12 +156,48 @@ func (s *Service) DeleteRider(ctx context.Context, id, tenantID uuid.UUID) error
 	return nil
 }
 
-// CheckIn creates an attendance record and sets the rider on duty.
-func (s *Service) CheckIn(ctx context.Context, riderID, tenantID uuid.UUID, hubID uuid.UUID) (sqlc.RiderAttendance, error) {
+// CheckIn creates an attendance record and sets the rider on duty. Only
+// verified riders can check in. Hubs with
+// a check-in radius require the rider to be at the hub, and hubs with shift
+// templates require a rostered shift; late arrivals are fined.
+func (s *Service) CheckIn(ctx context.Context, riderID, tenantID uuid.UUID, hubID uuid.UUID, pos *GeoPoint) (sqlc.RiderAttendance, error) {
 	now := time.Now()
 
+	rider, err := s.GetRider(ctx, riderID, tenantID)
+	if err != nil {
+		return sqlc.RiderAttendance{}, err
+	}
+	if err := checkVerified(rider); err != nil {
+		return sqlc.RiderAttendance{}, err
+	}
+
+	hub, err := s.q.GetHubByID(ctx, sqlc.GetHubByIDParams{ID: hubID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderAttendance{}, apperror.NotFound("hub")
+	}
+	if err != nil {
+		return sqlc.RiderAttendance{}, apperror.Internal("get hub", err)
+	}
+	if !hub.IsActive {
+		return sqlc.RiderAttendance{}, apperror.BadRequest("hub is not active")
+	}
+	if err := s.checkHubGeofence(ctx, hub, riderID, pos, now); err != nil {
+		return sqlc.RiderAttendance{}, err
+	}
+	shift, err := s.resolveCheckInShift(ctx, riderID, tenantID, hubID, now)
+	if err != nil {
+		return sqlc.RiderAttendance{}, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderAttendance{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
 	// Update rider hub and set on duty
-	_, err := s.q.UpdateRider(ctx, sqlc.UpdateRiderParams{
+	_, err = qtx.UpdateRider(ctx, sqlc.UpdateRiderParams{
 		ID:       riderID,
 		TenantID: tenantID,
 		HubID:    pgtype.UUID{Bytes: hubID, Valid: true},
@@ -167,14 +209,14 @@ func (s *Service) CheckIn(ctx context.Context, riderID, tenantID uuid.UUID, hubI
 		return sqlc.RiderAttendance{}, apperror.Internal("update rider hub", err)
 	}
 
-	_, err = s.q.UpdateRiderDutyStatus(ctx, sqlc.UpdateRiderDutyStatusParams{
+	_, err = qtx.UpdateRiderDutyStatus(ctx, sqlc.UpdateRiderDutyStatusParams{
 		ID: riderID, TenantID: tenantID, IsOnDuty: true,
 	})
 	if err != nil {
 		return sqlc.RiderAttendance{}, apperror.Internal("set on duty", err)
 	}
 
-	att, err := s.q.CreateAttendance(ctx, sqlc.CreateAttendanceParams{
+	att, err := qtx.CreateAttendance(ctx, sqlc.CreateAttendanceParams{
 		RiderID:  riderID,
 		TenantID: tenantID,
 		WorkDate: pgtype.Date{
@@ -191,6 +233,16 @@ func (s *Service) CheckIn(ctx context.Context, riderID, tenantID uuid.UUID, hubI
 	if err != nil {
 		return sqlc.RiderAttendance{}, apperror.Internal("create attendance", err)
 	}
+	if shift != nil {
+		if err := recordShiftCheckIn(ctx, qtx, *shift, att, now); err != nil {
+			return sqlc.RiderAttendance{}, apperror.Internal("record shift check-in", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderAttendance{}, apperror.Internal("commit", err)
+	}
+	s.recordRiderEvent(ctx, riderID, tenantID, sqlc.RiderSubjectAttendanceIn, nil)
 	return att, nil
 }
 
@@ -207,6 +259,18 @@ func (s *Service) CheckOut(ctx context.Context, riderID, tenantID uuid.UUID) (sq
 	now := time.Now()
 	hours := now.Sub(att.CheckedInAt.Time).Hours()
 
+	// Distance ridden over the shift, from the recorded trail.
+	s.recordRiderEvent(ctx, riderID, tenantID, sqlc.RiderSubjectAttendanceOut, nil)
+	distance, err := s.q.SumLocationDistance(ctx, sqlc.SumLocationDistanceParams{
+		RiderID:  riderID,
+		TenantID: tenantID,
+		FromTime: att.CheckedInAt.Time,
+		ToTime:   now,
+	})
+	if err != nil {
+		return sqlc.RiderAttendance{}, apperror.Internal("sum shift distance", err)
+	}
+
 	updated, err := s.q.UpdateAttendanceCheckout(ctx, sqlc.UpdateAttendanceCheckoutParams{
 		ID: att.ID,
 		CheckedOutAt: pgtype.Timestamptz{
@@ -215,12 +279,18 @@ func (s *Service) CheckOut(ctx context.Context, riderID, tenantID uuid.UUID) (sq
 			Valid:            true,
 		},
 		TotalHours:      numericFromFloat(hours),
-		TotalDistanceKm: pgtype.Numeric{Int: big.NewInt(0), Exp: 0, Valid: true},
+		TotalDistanceKm: distance,
 	})
 	if err != nil {
 		return sqlc.RiderAttendance{}, apperror.Internal("checkout attendance", err)
 	}
 
+	if err := s.q.CompleteCheckedInRiderShifts(ctx, sqlc.CompleteCheckedInRiderShiftsParams{
+		RiderID: riderID, TenantID: tenantID,
+	}); err != nil {
+		log.Error().Err(err).Str("rider_id", riderID.String()).Msg("failed to complete rider shifts")
+	}
+
 	// Set off duty and unavailable
 	if _, err := s.q.UpdateRiderDutyStatus(ctx, sqlc.UpdateRiderDutyStatusParams{
 		ID: riderID, TenantID: tenantID, IsOnDuty: false,
@@ -290,12 +360,24 @@ func (s *Service) AcceptOrder(ctx context.Context, orderID, riderID, tenantID uu
 		return sqlc.Order{}, apperror.Internal("get order", err)
 	}
 
-	// Assign rider
-	_, err = s.q.AssignRiderToOrder(ctx, sqlc.AssignRiderToOrderParams{
+	rider, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: tenantID})
+	if err != nil {
+		return sqlc.Order{}, apperror.Internal("get rider", err)
+	}
+	if err := checkCashLimit(ctx, s.q, rider); err != nil {
+		return sqlc.Order{}, err
+	}
+
+	// Assign rider; a rider who answers after another one has accepted
+	// loses the order.
+	_, err = s.q.ClaimOrderForRider(ctx, sqlc.ClaimOrderForRiderParams{
 		ID:       orderID,
 		TenantID: tenantID,
 		RiderID:  pgtype.UUID{Bytes: riderID, Valid: true},
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Order{}, apperror.Conflict("order has already been accepted by another rider")
+	}
 	if err != nil {
 		return sqlc.Order{}, apperror.Internal("assign rider", err)
 	}
@@ -308,6 +390,9 @@ func (s *Service) AcceptOrder(ctx context.Context, orderID, riderID, tenantID uu
 		return sqlc.Order{}, apperror.Internal("update order status", err)
 	}
 
+	s.q.AcceptAssignmentOffer(ctx, sqlc.AcceptAssignmentOfferParams{OrderID: orderID, RiderID: riderID})
+	s.q.WithdrawAssignmentOffers(ctx, orderID)
+
 	s.q.CreateTimelineEvent(ctx, sqlc.CreateTimelineEventParams{
 		OrderID:        orderID,
 		TenantID:       tenantID,
@@ -323,6 +408,21 @@ func (s *Service) AcceptOrder(ctx context.Context, orderID, riderID, tenantID uu
 	return updated, nil
 }
 
+// DeclineOrder turns down an assignment offer. Declines count against the
+// rider's acceptance rate.
+func (s *Service) DeclineOrder(ctx context.Context, orderID, riderID, tenantID uuid.UUID) (sqlc.RiderAssignmentOffer, error) {
+	offer, err := s.q.DeclineAssignmentOffer(ctx, sqlc.DeclineAssignmentOfferParams{
+		OrderID: orderID, RiderID: riderID, TenantID: tenantID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderAssignmentOffer{}, apperror.NotFound("open offer")
+	}
+	if err != nil {
+		return sqlc.RiderAssignmentOffer{}, apperror.Internal("decline offer", err)
+	}
+	return offer, nil
+}
+
 // MarkPickupPicked marks a per-restaurant pickup as picked and transitions parent order if all picked.
 func (s *Service) MarkPickupPicked(ctx context.Context, orderID, restaurantID, riderID, tenantID uuid.UUID) (sqlc.OrderPickup, error) {
 	pickup, err := s.q.GetPickupByOrderAndRestaurant(ctx, sqlc.GetPickupByOrderAndRestaurantParams{
@@ -341,6 +441,7 @@ func (s *Service) MarkPickupPicked(ctx context.Context, orderID, restaurantID, r
 	if err != nil {
 		return sqlc.OrderPickup{}, apperror.Internal("update pickup status", err)
 	}
+	s.recordRiderEvent(ctx, riderID, tenantID, sqlc.RiderSubjectPicked, &orderID)
 
 	s.q.CreateTimelineEvent(ctx, sqlc.CreateTimelineEventParams{
 		OrderID:     orderID,
@@ -378,31 +479,66 @@ func (s *Service) MarkPickupPicked(ctx context.Context, orderID, restaurantID, r
 				ActorType: sqlc.ActorTypeRider,
 				Metadata:  json.RawMessage(`{}`),
 			})
+
+			// The customer gets the delivery code once the food is on its way.
+			if order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID}); err == nil {
+				if _, _, err := openDeliveryProof(ctx, s.q, order, pgtype.UUID{Bytes: riderID, Valid: true}); err != nil {
+					log.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to open delivery proof")
+				}
+			}
 		}
 	}
 
 	return updated, nil
 }
 
-// MarkDelivered marks an order as delivered and updates rider stats.
-func (s *Service) MarkDelivered(ctx context.Context, orderID, riderID, tenantID uuid.UUID) (sqlc.Order, error) {
-	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID})
+// MarkDelivered marks an order as delivered and updates rider stats. Only the
+// assigned rider can deliver a picked-up order. The tenant's proof of delivery
+// policy is checked first and the proof is kept on record. For COD orders it
+// also records the cash the rider collected.
+func (s *Service) MarkDelivered(ctx context.Context, orderID, riderID, tenantID uuid.UUID, cash CashCollectionInput, pod DeliveryProofInput) (sqlc.Order, error) {
+	if cash.Collected != nil && cash.Collected.IsNegative() {
+		return sqlc.Order{}, apperror.BadRequest("cash collected cannot be negative")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.Order{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	order, err := qtx.GetOrderForUpdate(ctx, sqlc.GetOrderForUpdateParams{ID: orderID, TenantID: tenantID})
 	if errors.Is(err, pgx.ErrNoRows) {
 		return sqlc.Order{}, apperror.NotFound("order")
 	}
 	if err != nil {
 		return sqlc.Order{}, apperror.Internal("get order", err)
 	}
+	if !order.RiderID.Valid || uuid.UUID(order.RiderID.Bytes) != riderID {
+		return sqlc.Order{}, apperror.Forbidden("order is not assigned to you")
+	}
+	if order.Status != sqlc.OrderStatusPicked {
+		return sqlc.Order{}, apperror.Conflict("order can only be delivered once it is picked up")
+	}
+
+	proof, err := s.verifyDeliveryProof(ctx, order, riderID, pod)
+	if err != nil {
+		return sqlc.Order{}, err
+	}
 
 	prevStatus := order.Status
-	updated, err := s.q.UpdateOrderStatus(ctx, sqlc.UpdateOrderStatusParams{
+	updated, err := qtx.UpdateOrderStatus(ctx, sqlc.UpdateOrderStatusParams{
 		ID: orderID, TenantID: tenantID, Status: sqlc.OrderStatusDelivered,
 	})
 	if err != nil {
 		return sqlc.Order{}, apperror.Internal("update order status", err)
 	}
+	if err := analytics.EnqueueOrderFact(ctx, qtx, updated); err != nil {
+		return sqlc.Order{}, apperror.Internal("enqueue order fact", err)
+	}
 
-	s.q.CreateTimelineEvent(ctx, sqlc.CreateTimelineEventParams{
+	qtx.CreateTimelineEvent(ctx, sqlc.CreateTimelineEventParams{
 		OrderID:        orderID,
 		TenantID:       tenantID,
 		EventType:      "status_changed",
@@ -414,11 +550,29 @@ func (s *Service) MarkDelivered(ctx context.Context, orderID, riderID, tenantID
 		Metadata:       json.RawMessage(`{}`),
 	})
 
-	// Calculate and record earnings
-	if err := s.CalculateAndRecordEarning(ctx, riderID, tenantID, orderID); err != nil {
-		log.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to record earnings")
+	if _, err := recordDeliveryProof(ctx, qtx, updated, riderID, pod, proof); err != nil {
+		return sqlc.Order{}, err
 	}
 
+	// COD cash stays with the rider until it is deposited at the hub or
+	// netted against a payout.
+	if order.PaymentMethod == sqlc.PaymentMethodCod {
+		paid, err := recordCashCollection(ctx, qtx, updated, riderID, cash)
+		if err != nil {
+			return sqlc.Order{}, err
+		}
+		updated = paid
+	}
+
+	if err := recordEarning(ctx, qtx, riderID, tenantID, orderID); err != nil {
+		return sqlc.Order{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.Order{}, apperror.Internal("commit", err)
+	}
+
+	s.recordRiderEvent(ctx, riderID, tenantID, sqlc.RiderSubjectDelivered, &orderID)
 	return updated, nil
 }
 
@@ -451,6 +605,17 @@ func (s *Service) ReportIssue(ctx context.Context, orderID, riderID, tenantID uu
 
 // ManualAssignRider assigns a specific rider to an order (partner action).
 func (s *Service) ManualAssignRider(ctx context.Context, orderID, riderID, tenantID, actorID uuid.UUID) (sqlc.Order, error) {
+	rider, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Order{}, apperror.NotFound("rider")
+	}
+	if err != nil {
+		return sqlc.Order{}, apperror.Internal("get rider", err)
+	}
+	if err := checkCashLimit(ctx, s.q, rider); err != nil {
+		return sqlc.Order{}, err
+	}
+
 	order, err := s.q.AssignRiderToOrder(ctx, sqlc.AssignRiderToOrderParams{
 		ID:       orderID,
 		TenantID: tenantID,
@@ -462,6 +627,7 @@ func (s *Service) ManualAssignRider(ctx context.Context, orderID, riderID, tenan
 	if err != nil {
 		return sqlc.Order{}, apperror.Internal("assign rider", err)
 	}
+	s.q.WithdrawAssignmentOffers(ctx, orderID)
 
 	s.q.CreateTimelineEvent(ctx, sqlc.CreateTimelineEventParams{
 		OrderID:     orderID,
@@ -476,66 +642,6 @@ func (s *Service) ManualAssignRider(ctx context.Context, orderID, riderID, tenan
 	return order, nil
 }
 
-// CalculateAndRecordEarning computes and saves a rider earning for an order.
-func (s *Service) CalculateAndRecordEarning(ctx context.Context, riderID, tenantID, orderID uuid.UUID) error {
-	baseEarning := int64(5000) // 50.00 BDT in cents
-
-	// Distance bonus from location history
-	var distanceBonus int64
-	history, err := s.q.ListLocationHistoryByRider(ctx, sqlc.ListLocationHistoryByRiderParams{
-		RiderID: riderID,
-		Limit:   1000,
-		Since:   time.Now().Add(-4 * time.Hour),
-	})
-	if err == nil && len(history) > 0 {
-		var totalKm float64
-		for _, h := range history {
-			d, ok := numericToFloat64(h.DistanceFromPrevKm)
-			if ok {
-				totalKm += d
-			}
-		}
-		// 5 BDT per km
-		distanceBonus = int64(totalKm * 500)
-	}
-
-	// Peak hour bonus
-	var peakBonus int64
-	hour := time.Now().Hour()
-	if (hour >= 12 && hour < 14) || (hour >= 18 && hour < 21) {
-		peakBonus = 2000 // 20.00 BDT
-	}
-
-	totalEarning := baseEarning + distanceBonus + peakBonus
-
-	earning, err := s.q.CreateRiderEarning(ctx, sqlc.CreateRiderEarningParams{
-		RiderID:       riderID,
-		TenantID:      tenantID,
-		OrderID:       orderID,
-		BaseEarning:   pgtype.Numeric{Int: big.NewInt(baseEarning), Exp: -2, Valid: true},
-		DistanceBonus: pgtype.Numeric{Int: big.NewInt(distanceBonus), Exp: -2, Valid: true},
-		PeakBonus:     pgtype.Numeric{Int: big.NewInt(peakBonus), Exp: -2, Valid: true},
-		TipAmount:     pgtype.Numeric{Int: big.NewInt(0), Exp: -2, Valid: true},
-		TotalEarning:  pgtype.Numeric{Int: big.NewInt(totalEarning), Exp: -2, Valid: true},
-	})
-	if err != nil {
-		return apperror.Internal("create earning", err)
-	}
-
-	// Update rider stats
-	err = s.q.UpdateRiderStats(ctx, sqlc.UpdateRiderStatsParams{
-		ID:             riderID,
-		TenantID:       tenantID,
-		TotalEarnings:  earning.TotalEarning,
-		PendingBalance: earning.TotalEarning,
-	})
-	if err != nil {
-		log.Error().Err(err).Msg("update rider stats failed")
-	}
-
-	return nil
-}
-
 // ListEarnings returns paginated rider earnings.
 func (s *Service) ListEarnings(ctx context.Context, riderID uuid.UUID, limit, offset int32) ([]sqlc.RiderEarning, error) {
 	earnings, err := s.q.ListEarningsByRider(ctx, sqlc.ListEarningsByRiderParams{
@@ -561,17 +667,6 @@ func (s *Service) ListDeliveryHistory(ctx context.Context, riderID, tenantID uui
 	return orders, nil
 }
 
-// ListLocationHistory returns location history for a rider since a given time.
-func (s *Service) ListLocationHistory(ctx context.Context, riderID uuid.UUID, since time.Time, limit int32) ([]sqlc.RiderLocationHistory, error) {
-	history, err := s.q.ListLocationHistoryByRider(ctx, sqlc.ListLocationHistoryByRiderParams{
-		RiderID: riderID, Limit: limit, Since: since,
-	})
-	if err != nil {
-		return nil, apperror.Internal("list location history", err)
-	}
-	return history, nil
-}
-
 // ListRiderLocations returns live locations of all riders for a tenant.
 func (s *Service) ListRiderLocations(ctx context.Context, tenantID uuid.UUID) ([]sqlc.RiderLocation, error) {
 	locs, err := s.q.ListRiderLocationsByTenant(ctx, tenantID)
@@ -0,0 +1,954 @@
+package rider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/geo"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// Shift statuses.
+const (
+	ShiftScheduled = "scheduled"
+	ShiftCheckedIn = "checked_in"
+	ShiftCompleted = "completed"
+	ShiftAbsent    = "absent"
+	ShiftCancelled = "cancelled"
+)
+
+// Shift swap statuses.
+const (
+	SwapPending   = "pending"
+	SwapAccepted  = "accepted"
+	SwapDeclined  = "declined"
+	SwapApproved  = "approved"
+	SwapRejected  = "rejected"
+	SwapCancelled = "cancelled"
+)
+
+const (
+	// shiftCheckInOpensBefore is how early a rider may check in for a shift.
+	shiftCheckInOpensBefore = 30 * time.Minute
+	// maxRosterDays caps how many days one roster request may cover.
+	maxRosterDays = 31
+	// missedShiftBatchSize is how many missed shifts one sweep handles.
+	missedShiftBatchSize = 200
+)
+
+// ---------- Shift templates ----------
+
+// ShiftTemplateInput holds the fields of a shift template. On update, nil
+// fields are left unchanged and the hub is fixed once created.
+type ShiftTemplateInput struct {
+	HubID          *uuid.UUID `json:"hub_id"`
+	Name           *string    `json:"name"`
+	StartMinute    *int32     `json:"start_minute"`
+	EndMinute      *int32     `json:"end_minute"`
+	DaysOfWeek     *[]int32   `json:"days_of_week"`
+	RequiredRiders *int32     `json:"required_riders"`
+	GraceMinutes   *int32     `json:"grace_minutes"`
+	LatePenalty    *string    `json:"late_penalty"`
+	AbsencePenalty *string    `json:"absence_penalty"`
+	IsActive       *bool      `json:"is_active"`
+}
+
+func validateShiftWindow(start, end int32) error {
+	if start < 0 || end > 24*60 || start >= end {
+		return apperror.BadRequest("shift must satisfy 0 <= start_minute < end_minute <= 1440")
+	}
+	return nil
+}
+
+func validateDaysOfWeek(days []int32) error {
+	if len(days) == 0 {
+		return apperror.BadRequest("days_of_week must name at least one day")
+	}
+	for _, d := range days {
+		if d < 0 || d > 6 {
+			return apperror.BadRequest("days_of_week values must be between 0 (Sunday) and 6")
+		}
+	}
+	return nil
+}
+
+func validateShiftCounts(in ShiftTemplateInput) error {
+	if in.RequiredRiders != nil && *in.RequiredRiders < 0 {
+		return apperror.BadRequest("required_riders cannot be negative")
+	}
+	if in.GraceMinutes != nil && *in.GraceMinutes < 0 {
+		return apperror.BadRequest("grace_minutes cannot be negative")
+	}
+	return nil
+}
+
+// CreateShiftTemplate adds a recurring shift to a hub.
+func (s *Service) CreateShiftTemplate(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, in ShiftTemplateInput) (sqlc.RiderShiftTemplate, error) {
+	if in.HubID == nil {
+		return sqlc.RiderShiftTemplate{}, apperror.BadRequest("hub_id is required")
+	}
+	if in.Name == nil || *in.Name == "" {
+		return sqlc.RiderShiftTemplate{}, apperror.BadRequest("name is required")
+	}
+	if in.StartMinute == nil || in.EndMinute == nil {
+		return sqlc.RiderShiftTemplate{}, apperror.BadRequest("start_minute and end_minute are required")
+	}
+	if err := validateShiftWindow(*in.StartMinute, *in.EndMinute); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	days := []int32{0, 1, 2, 3, 4, 5, 6}
+	if in.DaysOfWeek != nil {
+		days = *in.DaysOfWeek
+	}
+	if err := validateDaysOfWeek(days); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	if err := validateShiftCounts(in); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	if err := authorizeHubManager(ctx, s.q, user, tenantID, *in.HubID); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+
+	params := sqlc.CreateShiftTemplateParams{
+		TenantID:       tenantID,
+		HubID:          *in.HubID,
+		Name:           *in.Name,
+		StartMinute:    *in.StartMinute,
+		EndMinute:      *in.EndMinute,
+		DaysOfWeek:     days,
+		RequiredRiders: 1,
+		GraceMinutes:   10,
+		IsActive:       in.IsActive == nil || *in.IsActive,
+		CreatedBy:      pgtype.UUID{Bytes: user.ID, Valid: true},
+	}
+	if in.RequiredRiders != nil {
+		params.RequiredRiders = *in.RequiredRiders
+	}
+	if in.GraceMinutes != nil {
+		params.GraceMinutes = *in.GraceMinutes
+	}
+	zero := "0"
+	for _, f := range []struct {
+		name string
+		v    *string
+		dst  *pgtype.Numeric
+	}{
+		{"late_penalty", in.LatePenalty, &params.LatePenalty},
+		{"absence_penalty", in.AbsencePenalty, &params.AbsencePenalty},
+	} {
+		v := f.v
+		if v == nil {
+			v = &zero
+		}
+		n, err := parseAmount(f.name, v)
+		if err != nil {
+			return sqlc.RiderShiftTemplate{}, err
+		}
+		*f.dst = n
+	}
+
+	tmpl, err := s.q.CreateShiftTemplate(ctx, params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return sqlc.RiderShiftTemplate{}, apperror.Conflict("a shift with this name already exists at the hub")
+		}
+		return sqlc.RiderShiftTemplate{}, apperror.Internal("create shift template", err)
+	}
+	return tmpl, nil
+}
+
+// ListShiftTemplates returns shift templates, optionally for one hub.
+func (s *Service) ListShiftTemplates(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID) ([]sqlc.RiderShiftTemplate, error) {
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := s.q.ListShiftTemplates(ctx, sqlc.ListShiftTemplatesParams{TenantID: tenantID, HubID: hub})
+	if err != nil {
+		return nil, apperror.Internal("list shift templates", err)
+	}
+	return templates, nil
+}
+
+func (s *Service) getShiftTemplate(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) (sqlc.RiderShiftTemplate, error) {
+	tmpl, err := s.q.GetShiftTemplate(ctx, sqlc.GetShiftTemplateParams{ID: id, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderShiftTemplate{}, apperror.NotFound("shift template")
+	}
+	if err != nil {
+		return sqlc.RiderShiftTemplate{}, apperror.Internal("get shift template", err)
+	}
+	if err := authorizeHubManager(ctx, s.q, user, tenantID, tmpl.HubID); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	return tmpl, nil
+}
+
+// UpdateShiftTemplate changes a shift template. Shifts already rostered keep
+// their times and penalties.
+func (s *Service) UpdateShiftTemplate(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID, in ShiftTemplateInput) (sqlc.RiderShiftTemplate, error) {
+	existing, err := s.getShiftTemplate(ctx, user, tenantID, id)
+	if err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	if in.HubID != nil && *in.HubID != existing.HubID {
+		return sqlc.RiderShiftTemplate{}, apperror.BadRequest("hub_id cannot be changed")
+	}
+	if in.Name != nil && *in.Name == "" {
+		return sqlc.RiderShiftTemplate{}, apperror.BadRequest("name cannot be empty")
+	}
+	start, end := existing.StartMinute, existing.EndMinute
+	if in.StartMinute != nil {
+		start = *in.StartMinute
+	}
+	if in.EndMinute != nil {
+		end = *in.EndMinute
+	}
+	if err := validateShiftWindow(start, end); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	params := sqlc.UpdateShiftTemplateParams{
+		ID:             id,
+		TenantID:       tenantID,
+		StartMinute:    in.StartMinute,
+		EndMinute:      in.EndMinute,
+		RequiredRiders: in.RequiredRiders,
+		GraceMinutes:   in.GraceMinutes,
+		IsActive:       in.IsActive,
+	}
+	if in.Name != nil {
+		params.Name = nullString(*in.Name)
+	}
+	if in.DaysOfWeek != nil {
+		if err := validateDaysOfWeek(*in.DaysOfWeek); err != nil {
+			return sqlc.RiderShiftTemplate{}, err
+		}
+		params.DaysOfWeek = *in.DaysOfWeek
+	}
+	if err := validateShiftCounts(in); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	if params.LatePenalty, err = parseAmount("late_penalty", in.LatePenalty); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+	if params.AbsencePenalty, err = parseAmount("absence_penalty", in.AbsencePenalty); err != nil {
+		return sqlc.RiderShiftTemplate{}, err
+	}
+
+	tmpl, err := s.q.UpdateShiftTemplate(ctx, params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return sqlc.RiderShiftTemplate{}, apperror.Conflict("a shift with this name already exists at the hub")
+		}
+		return sqlc.RiderShiftTemplate{}, apperror.Internal("update shift template", err)
+	}
+	return tmpl, nil
+}
+
+// DeleteShiftTemplate removes a shift template. Rostered shifts stay in place.
+func (s *Service) DeleteShiftTemplate(ctx context.Context, user *sqlc.User, tenantID, id uuid.UUID) error {
+	if _, err := s.getShiftTemplate(ctx, user, tenantID, id); err != nil {
+		return err
+	}
+	if _, err := s.q.DeleteShiftTemplate(ctx, sqlc.DeleteShiftTemplateParams{ID: id, TenantID: tenantID}); err != nil {
+		return apperror.Internal("delete shift template", err)
+	}
+	return nil
+}
+
+// ---------- Roster ----------
+
+// templateRunsOn reports whether a template is worked on the given Dhaka day.
+func templateRunsOn(tmpl sqlc.RiderShiftTemplate, day time.Time) bool {
+	weekday := int32(timeutil.ToBD(day).Weekday())
+	for _, d := range tmpl.DaysOfWeek {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// shiftBounds returns when a template's shift starts and ends on a Dhaka day.
+func shiftBounds(day time.Time, startMinute, endMinute int32) (time.Time, time.Time) {
+	midnight := timeutil.StartOfDayBD(day)
+	return midnight.Add(time.Duration(startMinute) * time.Minute), midnight.Add(time.Duration(endMinute) * time.Minute)
+}
+
+// RosterInput assigns riders to a template's shifts over a date range.
+type RosterInput struct {
+	TemplateID uuid.UUID
+	RiderIDs   []uuid.UUID
+	From       time.Time
+	To         time.Time
+}
+
+// RosterSkip explains why a rider was not rostered on a day.
+type RosterSkip struct {
+	RiderID uuid.UUID `json:"rider_id"`
+	Date    string    `json:"date"`
+	Reason  string    `json:"reason"`
+}
+
+// RosterResult lists the shifts created by a roster request and the ones that
+// were skipped.
+type RosterResult struct {
+	Created []sqlc.RiderShift `json:"created"`
+	Skipped []RosterSkip      `json:"skipped"`
+}
+
+// RosterShifts creates a shift for every rider on every day in the range that
+// the template runs. Riders who already work an overlapping shift are skipped.
+func (s *Service) RosterShifts(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, in RosterInput) (RosterResult, error) {
+	if len(in.RiderIDs) == 0 {
+		return RosterResult{}, apperror.BadRequest("rider_ids is required")
+	}
+	from, to := timeutil.StartOfDayBD(in.From), timeutil.StartOfDayBD(in.To)
+	if to.Before(from) {
+		return RosterResult{}, apperror.BadRequest("to must not be before from")
+	}
+	if to.Sub(from) >= maxRosterDays*24*time.Hour {
+		return RosterResult{}, apperror.BadRequest(fmt.Sprintf("a roster may cover at most %d days", maxRosterDays))
+	}
+	if from.Before(timeutil.StartOfDayBD(time.Now())) {
+		return RosterResult{}, apperror.BadRequest("shifts cannot be rostered in the past")
+	}
+
+	tmpl, err := s.getShiftTemplate(ctx, user, tenantID, in.TemplateID)
+	if err != nil {
+		return RosterResult{}, err
+	}
+	if !tmpl.IsActive {
+		return RosterResult{}, apperror.BadRequest("shift template is inactive")
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return RosterResult{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	for _, riderID := range in.RiderIDs {
+		if _, err := qtx.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: tenantID}); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return RosterResult{}, apperror.BadRequest("unknown rider " + riderID.String())
+			}
+			return RosterResult{}, apperror.Internal("get rider", err)
+		}
+	}
+
+	result := RosterResult{Created: []sqlc.RiderShift{}, Skipped: []RosterSkip{}}
+	now := time.Now()
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !templateRunsOn(tmpl, day) {
+			continue
+		}
+		startsAt, endsAt := shiftBounds(day, tmpl.StartMinute, tmpl.EndMinute)
+		date := day.Format("2006-01-02")
+		for _, riderID := range in.RiderIDs {
+			if !endsAt.After(now) {
+				result.Skipped = append(result.Skipped, RosterSkip{RiderID: riderID, Date: date, Reason: "shift has already ended"})
+				continue
+			}
+			overlapping, err := qtx.CountOverlappingRiderShifts(ctx, sqlc.CountOverlappingRiderShiftsParams{
+				RiderID: riderID, StartsAt: startsAt, EndsAt: endsAt,
+			})
+			if err != nil {
+				return RosterResult{}, apperror.Internal("check overlapping shifts", err)
+			}
+			if overlapping > 0 {
+				result.Skipped = append(result.Skipped, RosterSkip{RiderID: riderID, Date: date, Reason: "overlaps another shift"})
+				continue
+			}
+			shift, err := qtx.CreateRiderShift(ctx, sqlc.CreateRiderShiftParams{
+				TenantID:       tenantID,
+				HubID:          tmpl.HubID,
+				TemplateID:     pgtype.UUID{Bytes: tmpl.ID, Valid: true},
+				RiderID:        riderID,
+				ShiftDate:      pgDate(day),
+				StartsAt:       startsAt,
+				EndsAt:         endsAt,
+				GraceMinutes:   tmpl.GraceMinutes,
+				LatePenalty:    tmpl.LatePenalty,
+				AbsencePenalty: tmpl.AbsencePenalty,
+				CreatedBy:      pgtype.UUID{Bytes: user.ID, Valid: true},
+			})
+			if err != nil {
+				return RosterResult{}, apperror.Internal("create shift", err)
+			}
+			result.Created = append(result.Created, shift)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return RosterResult{}, apperror.Internal("commit", err)
+	}
+	return result, nil
+}
+
+// ListShifts returns rostered shifts between two Dhaka dates, optionally for
+// one hub or rider.
+func (s *Service) ListShifts(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID, riderID *uuid.UUID, from, to time.Time) ([]sqlc.ListRiderShiftsRow, error) {
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	params := sqlc.ListRiderShiftsParams{
+		TenantID: tenantID,
+		FromDate: pgDate(from),
+		ToDate:   pgDate(to),
+		HubID:    hub,
+	}
+	if riderID != nil {
+		params.RiderID = pgtype.UUID{Bytes: *riderID, Valid: true}
+	}
+	shifts, err := s.q.ListRiderShifts(ctx, params)
+	if err != nil {
+		return nil, apperror.Internal("list shifts", err)
+	}
+	return shifts, nil
+}
+
+// CancelShift removes a rider from a shift that has not been worked yet.
+func (s *Service) CancelShift(ctx context.Context, user *sqlc.User, tenantID, shiftID uuid.UUID) (sqlc.RiderShift, error) {
+	shift, err := s.q.GetRiderShift(ctx, sqlc.GetRiderShiftParams{ID: shiftID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderShift{}, apperror.NotFound("shift")
+	}
+	if err != nil {
+		return sqlc.RiderShift{}, apperror.Internal("get shift", err)
+	}
+	if err := authorizeHubManager(ctx, s.q, user, tenantID, shift.HubID); err != nil {
+		return sqlc.RiderShift{}, err
+	}
+	cancelled, err := s.q.CancelRiderShift(ctx, sqlc.CancelRiderShiftParams{ID: shiftID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderShift{}, apperror.Conflict("shift is already " + shift.Status)
+	}
+	if err != nil {
+		return sqlc.RiderShift{}, apperror.Internal("cancel shift", err)
+	}
+	return cancelled, nil
+}
+
+// ListMyShifts returns a rider's current and upcoming shifts.
+func (s *Service) ListMyShifts(ctx context.Context, riderID, tenantID uuid.UUID) ([]sqlc.RiderShift, error) {
+	shifts, err := s.q.ListUpcomingRiderShifts(ctx, sqlc.ListUpcomingRiderShiftsParams{
+		RiderID: riderID, TenantID: tenantID, EndsAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperror.Internal("list shifts", err)
+	}
+	return shifts, nil
+}
+
+// ---------- Shift swaps ----------
+
+// RequestShiftSwap offers one of the rider's upcoming shifts to a colleague.
+func (s *Service) RequestShiftSwap(ctx context.Context, riderID, tenantID, shiftID, targetRiderID uuid.UUID, reason string) (sqlc.RiderShiftSwap, error) {
+	if targetRiderID == riderID {
+		return sqlc.RiderShiftSwap{}, apperror.BadRequest("cannot swap a shift with yourself")
+	}
+	shift, err := s.q.GetRiderShift(ctx, sqlc.GetRiderShiftParams{ID: shiftID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && shift.RiderID != riderID) {
+		return sqlc.RiderShiftSwap{}, apperror.NotFound("shift")
+	}
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("get shift", err)
+	}
+	if shift.Status != ShiftScheduled || !shift.StartsAt.After(time.Now()) {
+		return sqlc.RiderShiftSwap{}, apperror.Conflict("only upcoming scheduled shifts can be swapped")
+	}
+	if _, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: targetRiderID, TenantID: tenantID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.RiderShiftSwap{}, apperror.NotFound("rider")
+		}
+		return sqlc.RiderShiftSwap{}, apperror.Internal("get rider", err)
+	}
+	if err := s.checkSwapTargetFree(ctx, s.q, targetRiderID, shift); err != nil {
+		return sqlc.RiderShiftSwap{}, err
+	}
+
+	swap, err := s.q.CreateShiftSwap(ctx, sqlc.CreateShiftSwapParams{
+		TenantID:      tenantID,
+		ShiftID:       shiftID,
+		RequesterID:   riderID,
+		TargetRiderID: targetRiderID,
+		Reason:        nullString(reason),
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return sqlc.RiderShiftSwap{}, apperror.Conflict("a swap is already open for this shift")
+		}
+		return sqlc.RiderShiftSwap{}, apperror.Internal("create shift swap", err)
+	}
+	return swap, nil
+}
+
+func (s *Service) checkSwapTargetFree(ctx context.Context, q *sqlc.Queries, targetRiderID uuid.UUID, shift sqlc.RiderShift) error {
+	overlapping, err := q.CountOverlappingRiderShifts(ctx, sqlc.CountOverlappingRiderShiftsParams{
+		RiderID: targetRiderID, StartsAt: shift.StartsAt, EndsAt: shift.EndsAt,
+	})
+	if err != nil {
+		return apperror.Internal("check overlapping shifts", err)
+	}
+	if overlapping > 0 {
+		return apperror.Conflict("the other rider already works an overlapping shift")
+	}
+	return nil
+}
+
+// lockShiftSwap locks a swap for a status change.
+func lockShiftSwap(ctx context.Context, q *sqlc.Queries, swapID, tenantID uuid.UUID) (sqlc.RiderShiftSwap, error) {
+	swap, err := q.GetShiftSwapForUpdate(ctx, sqlc.GetShiftSwapForUpdateParams{ID: swapID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RiderShiftSwap{}, apperror.NotFound("shift swap")
+	}
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("lock shift swap", err)
+	}
+	return swap, nil
+}
+
+// RespondShiftSwap lets the colleague accept or decline a swap offered to
+// them. Accepted swaps still need the hub manager's approval.
+func (s *Service) RespondShiftSwap(ctx context.Context, riderID, tenantID, swapID uuid.UUID, accept bool) (sqlc.RiderShiftSwap, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	swap, err := lockShiftSwap(ctx, qtx, swapID, tenantID)
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, err
+	}
+	if swap.TargetRiderID != riderID {
+		return sqlc.RiderShiftSwap{}, apperror.NotFound("shift swap")
+	}
+	if swap.Status != SwapPending {
+		return sqlc.RiderShiftSwap{}, apperror.Conflict("shift swap is already " + swap.Status)
+	}
+
+	status := SwapDeclined
+	if accept {
+		status = SwapAccepted
+	}
+	updated, err := qtx.UpdateShiftSwapStatus(ctx, sqlc.UpdateShiftSwapStatusParams{ID: swapID, Status: status})
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("update shift swap", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("commit", err)
+	}
+	return updated, nil
+}
+
+// CancelShiftSwap withdraws a swap the rider asked for before it is approved.
+func (s *Service) CancelShiftSwap(ctx context.Context, riderID, tenantID, swapID uuid.UUID) (sqlc.RiderShiftSwap, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	swap, err := lockShiftSwap(ctx, qtx, swapID, tenantID)
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, err
+	}
+	if swap.RequesterID != riderID {
+		return sqlc.RiderShiftSwap{}, apperror.NotFound("shift swap")
+	}
+	if swap.Status != SwapPending && swap.Status != SwapAccepted {
+		return sqlc.RiderShiftSwap{}, apperror.Conflict("shift swap is already " + swap.Status)
+	}
+	updated, err := qtx.UpdateShiftSwapStatus(ctx, sqlc.UpdateShiftSwapStatusParams{ID: swapID, Status: SwapCancelled})
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("update shift swap", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("commit", err)
+	}
+	return updated, nil
+}
+
+// ListMyShiftSwaps returns swaps the rider asked for or was offered.
+func (s *Service) ListMyShiftSwaps(ctx context.Context, riderID, tenantID uuid.UUID, limit, offset int32) ([]sqlc.ListShiftSwapsForRiderRow, error) {
+	swaps, err := s.q.ListShiftSwapsForRider(ctx, sqlc.ListShiftSwapsForRiderParams{
+		RequesterID: riderID, TenantID: tenantID, Limit: limit, Offset: offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list shift swaps", err)
+	}
+	return swaps, nil
+}
+
+// ListShiftSwaps returns swaps for the partner review queue, optionally for
+// one hub and status.
+func (s *Service) ListShiftSwaps(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, status string, limit, offset int32) ([]sqlc.ListShiftSwapsRow, error) {
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return nil, err
+	}
+	swaps, err := s.q.ListShiftSwaps(ctx, sqlc.ListShiftSwapsParams{
+		TenantID: tenantID,
+		HubID:    hub,
+		Status:   nullString(status),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list shift swaps", err)
+	}
+	return swaps, nil
+}
+
+// ReviewShiftSwap approves or rejects a swap both riders agreed to. On
+// approval the shift moves to the other rider.
+func (s *Service) ReviewShiftSwap(ctx context.Context, user *sqlc.User, tenantID, swapID uuid.UUID, approve bool, note string) (sqlc.RiderShiftSwap, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("begin tx", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	swap, err := lockShiftSwap(ctx, qtx, swapID, tenantID)
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, err
+	}
+	shift, err := qtx.GetRiderShiftForUpdate(ctx, sqlc.GetRiderShiftForUpdateParams{ID: swap.ShiftID, TenantID: tenantID})
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("lock shift", err)
+	}
+	if err := authorizeHubManager(ctx, qtx, user, tenantID, shift.HubID); err != nil {
+		return sqlc.RiderShiftSwap{}, err
+	}
+	if swap.Status != SwapAccepted {
+		if swap.Status == SwapPending {
+			return sqlc.RiderShiftSwap{}, apperror.Conflict("the other rider has not accepted this swap yet")
+		}
+		return sqlc.RiderShiftSwap{}, apperror.Conflict("shift swap is already " + swap.Status)
+	}
+
+	status := SwapRejected
+	if approve {
+		if shift.Status != ShiftScheduled || shift.RiderID != swap.RequesterID || !shift.StartsAt.After(time.Now()) {
+			return sqlc.RiderShiftSwap{}, apperror.Conflict("the shift can no longer be swapped")
+		}
+		if err := s.checkSwapTargetFree(ctx, qtx, swap.TargetRiderID, shift); err != nil {
+			return sqlc.RiderShiftSwap{}, err
+		}
+		if _, err := qtx.ReassignRiderShift(ctx, sqlc.ReassignRiderShiftParams{
+			ID: shift.ID, TenantID: tenantID, RiderID: swap.TargetRiderID,
+		}); err != nil {
+			return sqlc.RiderShiftSwap{}, apperror.Internal("reassign shift", err)
+		}
+		status = SwapApproved
+	}
+
+	updated, err := qtx.UpdateShiftSwapStatus(ctx, sqlc.UpdateShiftSwapStatusParams{
+		ID:         swapID,
+		Status:     status,
+		ReviewNote: nullString(note),
+		ReviewedBy: pgtype.UUID{Bytes: user.ID, Valid: true},
+	})
+	if err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("update shift swap", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return sqlc.RiderShiftSwap{}, apperror.Internal("commit", err)
+	}
+	return updated, nil
+}
+
+// ---------- Check-in validation ----------
+
+// GeoPoint is a position reported by the rider app.
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// checkHubGeofence rejects a check-in made too far from the hub. The position
+// sent with the check-in is preferred; otherwise the rider's last location is
+// used if it is recent.
+func (s *Service) checkHubGeofence(ctx context.Context, hub sqlc.Hub, riderID uuid.UUID, pos *GeoPoint, now time.Time) error {
+	if hub.CheckinRadiusM == nil {
+		return nil
+	}
+	hubLat, okLat := numericToFloat64(hub.GeoLat)
+	hubLng, okLng := numericToFloat64(hub.GeoLng)
+	if !okLat || !okLng {
+		return nil
+	}
+
+	if pos == nil {
+		loc, err := s.q.GetRiderLocation(ctx, riderID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return apperror.Internal("get rider location", err)
+		}
+		lat, okLat := numericToFloat64(loc.GeoLat)
+		lng, okLng := numericToFloat64(loc.GeoLng)
+		if err != nil || !okLat || !okLng || now.Sub(loc.UpdatedAt) > podLocationMaxAge {
+			return apperror.BadRequest("your location is required to check in at this hub")
+		}
+		pos = &GeoPoint{Lat: lat, Lng: lng}
+	}
+
+	meters := geo.DistanceKm(pos.Lat, pos.Lng, hubLat, hubLng) * 1000
+	if meters > float64(*hub.CheckinRadiusM) {
+		return apperror.BadRequest(fmt.Sprintf("you are %.0fm from %s; check in within %dm of the hub", meters, hub.Name, *hub.CheckinRadiusM))
+	}
+	return nil
+}
+
+// resolveCheckInShift finds the rostered shift a check-in belongs to. Hubs
+// with active shift templates only accept riders who are on the roster now;
+// other hubs accept walk-in check-ins and return nil.
+func (s *Service) resolveCheckInShift(ctx context.Context, riderID, tenantID, hubID uuid.UUID, now time.Time) (*sqlc.RiderShift, error) {
+	shift, err := s.q.GetCheckInRiderShift(ctx, sqlc.GetCheckInRiderShiftParams{
+		RiderID:     riderID,
+		TenantID:    tenantID,
+		OpensBefore: now.Add(shiftCheckInOpensBefore),
+		Now:         now,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.Internal("get shift", err)
+	}
+	if err == nil {
+		if shift.HubID != hubID {
+			return nil, apperror.BadRequest("your current shift is at another hub")
+		}
+		return &shift, nil
+	}
+
+	templates, err := s.q.CountActiveShiftTemplates(ctx, sqlc.CountActiveShiftTemplatesParams{HubID: hubID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("count shift templates", err)
+	}
+	if templates > 0 {
+		return nil, apperror.BadRequest("you have no shift at this hub right now")
+	}
+	return nil, nil
+}
+
+// lateMinutes returns how late a check-in was, or zero within the grace
+// period.
+func lateMinutes(startsAt time.Time, graceMinutes int32, checkedInAt time.Time) int32 {
+	if !checkedInAt.After(startsAt.Add(time.Duration(graceMinutes) * time.Minute)) {
+		return 0
+	}
+	return int32(checkedInAt.Sub(startsAt) / time.Minute)
+}
+
+// recordShiftCheckIn marks the shift worked and fines a late arrival, on the
+// check-in's transaction.
+func recordShiftCheckIn(ctx context.Context, qtx *sqlc.Queries, shift sqlc.RiderShift, att sqlc.RiderAttendance, now time.Time) error {
+	late := lateMinutes(shift.StartsAt, shift.GraceMinutes, now)
+	var penaltyID pgtype.UUID
+	if late > 0 && numericToDecimal(shift.LatePenalty).IsPositive() {
+		penalty, err := qtx.CreateRiderPenalty(ctx, sqlc.CreateRiderPenaltyParams{
+			RiderID:  shift.RiderID,
+			TenantID: shift.TenantID,
+			Reason:   fmt.Sprintf("Late check-in for %s shift (%d min)", timeutil.FormatBD(shift.StartsAt, "2006-01-02 15:04"), late),
+			Amount:   shift.LatePenalty,
+		})
+		if err != nil {
+			return fmt.Errorf("create late penalty: %w", err)
+		}
+		penaltyID = pgtype.UUID{Bytes: penalty.ID, Valid: true}
+	}
+	_, err := qtx.MarkRiderShiftCheckedIn(ctx, sqlc.MarkRiderShiftCheckedInParams{
+		ID:           shift.ID,
+		CheckedInAt:  pgtype.Timestamptz{Time: now, Valid: true},
+		LateMinutes:  late,
+		AttendanceID: pgtype.UUID{Bytes: att.ID, Valid: true},
+		PenaltyID:    penaltyID,
+	})
+	return err
+}
+
+// RunShiftAbsenceSweep marks shifts that ended without a check-in as absent
+// and fines the rider when the shift carries an absence penalty.
+func (s *Service) RunShiftAbsenceSweep(ctx context.Context) error {
+	missed, err := s.q.ListMissedRiderShifts(ctx, sqlc.ListMissedRiderShiftsParams{
+		EndsAt: time.Now(), Limit: missedShiftBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	marked := 0
+	for _, shift := range missed {
+		if err := s.markShiftAbsent(ctx, shift.ID); err != nil {
+			log.Error().Err(err).Str("shift_id", shift.ID.String()).Msg("failed to mark shift absent")
+			continue
+		}
+		marked++
+	}
+	if marked > 0 {
+		log.Info().Int("shifts", marked).Msg("marked missed shifts absent")
+	}
+	return nil
+}
+
+func (s *Service) markShiftAbsent(ctx context.Context, shiftID uuid.UUID) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.q.WithTx(tx)
+
+	shift, err := qtx.MarkRiderShiftAbsent(ctx, shiftID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if numericToDecimal(shift.AbsencePenalty).IsPositive() {
+		penalty, err := qtx.CreateRiderPenalty(ctx, sqlc.CreateRiderPenaltyParams{
+			RiderID:  shift.RiderID,
+			TenantID: shift.TenantID,
+			Reason:   fmt.Sprintf("Absent from %s shift", timeutil.FormatBD(shift.StartsAt, "2006-01-02 15:04")),
+			Amount:   shift.AbsencePenalty,
+		})
+		if err != nil {
+			return err
+		}
+		if err := qtx.SetRiderShiftPenalty(ctx, sqlc.SetRiderShiftPenaltyParams{
+			ID: shift.ID, PenaltyID: pgtype.UUID{Bytes: penalty.ID, Valid: true},
+		}); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ---------- Coverage ----------
+
+// coverageLookbackDays is how many past days of orders set the demand
+// baseline.
+const coverageLookbackDays = 28
+
+// ShiftCoverageHour compares staffing with demand for one hour of the day.
+type ShiftCoverageHour struct {
+	Hour              int             `json:"hour"`
+	PlannedRiders     int             `json:"planned_riders"`
+	RequiredRiders    int             `json:"required_riders"`
+	AvgOrders         decimal.Decimal `json:"avg_orders"`
+	RecommendedRiders int             `json:"recommended_riders"`
+	Gap               int             `json:"gap"`
+}
+
+// ShiftCoverageReport is the staffing plan of a day against order demand.
+// Demand is tenant-wide because order analytics are not split by hub.
+type ShiftCoverageReport struct {
+	Date               string              `json:"date"`
+	HubID              *uuid.UUID          `json:"hub_id,omitempty"`
+	LookbackDays       int                 `json:"lookback_days"`
+	OrdersPerRiderHour decimal.Decimal     `json:"orders_per_rider_hour"`
+	Hours              []ShiftCoverageHour `json:"hours"`
+}
+
+// buildShiftCoverage counts, for each hour of a Dhaka day, the riders
+// rostered, the riders the shift templates ask for, and the riders the
+// average order volume calls for. Gap is planned minus the larger of the two
+// targets, so a negative gap is a shortfall.
+func buildShiftCoverage(day time.Time, shifts []sqlc.ListRiderShiftsRow, templates []sqlc.RiderShiftTemplate, peaks []sqlc.GetPeakHoursRow, lookbackDays int, ordersPerRiderHour decimal.Decimal) []ShiftCoverageHour {
+	midnight := timeutil.StartOfDayBD(day)
+	orders := make(map[int]int32, len(peaks))
+	for _, p := range peaks {
+		orders[int(p.OrderHour)] = p.OrderCount
+	}
+
+	hours := make([]ShiftCoverageHour, 24)
+	for h := range hours {
+		from := midnight.Add(time.Duration(h) * time.Hour)
+		to := from.Add(time.Hour)
+		row := ShiftCoverageHour{Hour: h, AvgOrders: decimal.Zero}
+
+		for _, sh := range shifts {
+			if sh.Status != ShiftCancelled && sh.StartsAt.Before(to) && sh.EndsAt.After(from) {
+				row.PlannedRiders++
+			}
+		}
+		for _, t := range templates {
+			if !t.IsActive || !templateRunsOn(t, day) {
+				continue
+			}
+			start, end := shiftBounds(day, t.StartMinute, t.EndMinute)
+			if start.Before(to) && end.After(from) {
+				row.RequiredRiders += int(t.RequiredRiders)
+			}
+		}
+		if lookbackDays > 0 {
+			row.AvgOrders = decimal.NewFromInt32(orders[h]).Div(decimal.NewFromInt(int64(lookbackDays))).Round(2)
+		}
+		if ordersPerRiderHour.IsPositive() {
+			row.RecommendedRiders = int(row.AvgOrders.Div(ordersPerRiderHour).Ceil().IntPart())
+		}
+
+		target := row.RequiredRiders
+		if row.RecommendedRiders > target {
+			target = row.RecommendedRiders
+		}
+		row.Gap = row.PlannedRiders - target
+		hours[h] = row
+	}
+	return hours
+}
+
+// GetShiftCoverage compares the roster of a Dhaka day with the average hourly
+// order volume of the preceding four weeks.
+func (s *Service) GetShiftCoverage(ctx context.Context, user *sqlc.User, tenantID uuid.UUID, hubID *uuid.UUID, day time.Time, ordersPerRiderHour decimal.Decimal) (ShiftCoverageReport, error) {
+	if !ordersPerRiderHour.IsPositive() {
+		return ShiftCoverageReport{}, apperror.BadRequest("orders_per_rider must be positive")
+	}
+	hub, err := hubScope(ctx, s.q, user, tenantID, hubID)
+	if err != nil {
+		return ShiftCoverageReport{}, err
+	}
+	day = timeutil.StartOfDayBD(day)
+
+	shifts, err := s.q.ListRiderShifts(ctx, sqlc.ListRiderShiftsParams{
+		TenantID: tenantID, FromDate: pgDate(day), ToDate: pgDate(day), HubID: hub,
+	})
+	if err != nil {
+		return ShiftCoverageReport{}, apperror.Internal("list shifts", err)
+	}
+	templates, err := s.q.ListShiftTemplates(ctx, sqlc.ListShiftTemplatesParams{TenantID: tenantID, HubID: hub})
+	if err != nil {
+		return ShiftCoverageReport{}, apperror.Internal("list shift templates", err)
+	}
+	peaks, err := s.q.GetPeakHours(ctx, sqlc.GetPeakHoursParams{
+		TenantID:  tenantID,
+		StartDate: pgDate(day.AddDate(0, 0, -coverageLookbackDays)),
+		EndDate:   pgDate(day.AddDate(0, 0, -1)),
+	})
+	if err != nil {
+		return ShiftCoverageReport{}, apperror.Internal("get peak hours", err)
+	}
+
+	return ShiftCoverageReport{
+		Date:               day.Format("2006-01-02"),
+		HubID:              hubID,
+		LookbackDays:       coverageLookbackDays,
+		OrdersPerRiderHour: ordersPerRiderHour,
+		Hours:              buildShiftCoverage(day, shifts, templates, peaks, coverageLookbackDays, ordersPerRiderHour),
+	}, nil
+}
@@ -0,0 +1,76 @@
+package rider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+)
+
+func TestShiftBounds(t *testing.T) {
+	day := bdTime(t, "2026-03-02 17:45")
+
+	start, end := shiftBounds(day, 11*60, 15*60+30)
+	if !start.Equal(bdTime(t, "2026-03-02 11:00")) || !end.Equal(bdTime(t, "2026-03-02 15:30")) {
+		t.Errorf("bounds = %s – %s, want 11:00 – 15:30 Dhaka", start, end)
+	}
+}
+
+func TestTemplateRunsOn(t *testing.T) {
+	weekdays := sqlc.RiderShiftTemplate{DaysOfWeek: []int32{1, 2, 3, 4, 5}}
+
+	// 2026-03-02 is a Monday; in UTC it is still Sunday evening at 02:00 Dhaka.
+	if !templateRunsOn(weekdays, bdTime(t, "2026-03-02 02:00")) {
+		t.Error("a weekday template should run on Monday in Dhaka")
+	}
+	if templateRunsOn(weekdays, bdTime(t, "2026-03-01 12:00")) {
+		t.Error("a weekday template should not run on Sunday")
+	}
+}
+
+func TestLateMinutes(t *testing.T) {
+	start := bdTime(t, "2026-03-02 11:00")
+
+	if got := lateMinutes(start, 10, start.Add(-20*time.Minute)); got != 0 {
+		t.Errorf("early check-in = %d, want 0", got)
+	}
+	if got := lateMinutes(start, 10, start.Add(10*time.Minute)); got != 0 {
+		t.Errorf("check-in at the end of the grace period = %d, want 0", got)
+	}
+	if got := lateMinutes(start, 10, start.Add(25*time.Minute+30*time.Second)); got != 25 {
+		t.Errorf("check-in after the grace period = %d, want 25 minutes from the start", got)
+	}
+}
+
+func TestBuildShiftCoverage(t *testing.T) {
+	day := bdTime(t, "2026-03-02 00:00")
+	templates := []sqlc.RiderShiftTemplate{
+		{StartMinute: 12 * 60, EndMinute: 15 * 60, DaysOfWeek: []int32{1}, RequiredRiders: 3, IsActive: true},
+		{StartMinute: 12 * 60, EndMinute: 13 * 60, DaysOfWeek: []int32{0}, RequiredRiders: 5, IsActive: true},
+		{StartMinute: 12 * 60, EndMinute: 13 * 60, DaysOfWeek: []int32{1}, RequiredRiders: 7, IsActive: false},
+	}
+	shifts := []sqlc.ListRiderShiftsRow{
+		{StartsAt: bdTime(t, "2026-03-02 12:00"), EndsAt: bdTime(t, "2026-03-02 15:00"), Status: ShiftScheduled},
+		{StartsAt: bdTime(t, "2026-03-02 12:30"), EndsAt: bdTime(t, "2026-03-02 14:00"), Status: ShiftCheckedIn},
+		{StartsAt: bdTime(t, "2026-03-02 12:00"), EndsAt: bdTime(t, "2026-03-02 15:00"), Status: ShiftCancelled},
+	}
+	// 28 days of history: 224 orders at 12:00 is 8 an hour, 14 at 14:00 is 0.5.
+	peaks := []sqlc.GetPeakHoursRow{{OrderHour: 12, OrderCount: 224}, {OrderHour: 14, OrderCount: 14}}
+
+	hours := buildShiftCoverage(day, shifts, templates, peaks, 28, dec("2"))
+	if len(hours) != 24 {
+		t.Fatalf("len = %d, want 24", len(hours))
+	}
+
+	noon := hours[12]
+	if noon.PlannedRiders != 2 || noon.RequiredRiders != 3 || !noon.AvgOrders.Equal(dec("8")) || noon.RecommendedRiders != 4 || noon.Gap != -2 {
+		t.Errorf("12:00 = %+v, want 2 planned, 3 required, 4 recommended, gap -2", noon)
+	}
+	two := hours[14]
+	if two.PlannedRiders != 1 || two.RecommendedRiders != 1 || two.Gap != -2 {
+		t.Errorf("14:00 = %+v, want 1 planned against 3 required", two)
+	}
+	if h := hours[15]; h.PlannedRiders != 0 || h.RequiredRiders != 0 || h.Gap != 0 {
+		t.Errorf("15:00 = %+v, want an empty hour", h)
+	}
+}
@@ -0,0 +1,462 @@
+package rider
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/geo"
+	"github.com/rs/zerolog/log"
+)
+
+// Leg kinds.
+const (
+	// LegToPickup is the ride to a restaurant, ending at a picked event.
+	LegToPickup = "to_pickup"
+	// LegDelivery is any stretch ridden with food on board.
+	LegDelivery = "delivery"
+	// LegFree is riding without an order: returning to the hub, waiting.
+	LegFree = "free"
+)
+
+const (
+	// A leg is a detour when the trail is longer than the straight line
+	// times detourRatio plus detourSlackKm.
+	detourRatio   = 1.6
+	detourSlackKm = 0.5
+	// A rider staying within idleStopRadiusKm for idleStopMinDuration is
+	// reported as an idle stop.
+	idleStopRadiusKm    = 0.05
+	idleStopMinDuration = 10 * time.Minute
+
+	travelLogMaxPoints = 20000
+	travelLogMaxWindow = 7 * 24 * time.Hour
+	// tripEventMargin widens the history read for an order's earnings so the
+	// picked and delivered events fall inside it.
+	tripEventMargin = 5 * time.Minute
+)
+
+// trackPoint is a location history row in plain coordinates.
+type trackPoint struct {
+	At         time.Time
+	Lat        float64
+	Lng        float64
+	Event      sqlc.RiderSubject
+	OrderID    *uuid.UUID
+	DistanceKm float64
+}
+
+// TravelLeg is one stretch of a rider's trail between two events.
+type TravelLeg struct {
+	Kind       string      `json:"kind"`
+	OrderIDs   []uuid.UUID `json:"order_ids"`
+	StartedAt  time.Time   `json:"started_at"`
+	EndedAt    time.Time   `json:"ended_at"`
+	DistanceKm float64     `json:"distance_km"`
+	StraightKm float64     `json:"straight_line_km"`
+	Detour     bool        `json:"detour"`
+	Points     int         `json:"points"`
+
+	path [][2]float64
+}
+
+// IdleStop is a period the rider did not move. OrderIDs are the orders on
+// board at the time.
+type IdleStop struct {
+	Lat       float64     `json:"lat"`
+	Lng       float64     `json:"lng"`
+	StartedAt time.Time   `json:"started_at"`
+	EndedAt   time.Time   `json:"ended_at"`
+	Minutes   int         `json:"minutes"`
+	OrderIDs  []uuid.UUID `json:"order_ids"`
+}
+
+// ShiftDistance is the distance ridden during one attendance record.
+type ShiftDistance struct {
+	AttendanceID uuid.UUID  `json:"attendance_id"`
+	CheckedInAt  time.Time  `json:"checked_in_at"`
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+	DistanceKm   float64    `json:"distance_km"`
+	Deliveries   int        `json:"deliveries"`
+}
+
+// TravelLog is a rider's reconstructed trail over a time window.
+type TravelLog struct {
+	RiderID    uuid.UUID       `json:"rider_id"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	DistanceKm float64         `json:"distance_km"`
+	Legs       []TravelLeg     `json:"legs"`
+	IdleStops  []IdleStop      `json:"idle_stops"`
+	Shifts     []ShiftDistance `json:"shifts"`
+	Points     int             `json:"points"`
+	// Truncated is set when the window held more points than are read.
+	Truncated bool `json:"truncated"`
+
+	History []sqlc.RiderLocationHistory `json:"-"`
+}
+
+// GetTravelLog reconstructs a rider's trail between from and to.
+func (s *Service) GetTravelLog(ctx context.Context, riderID, tenantID uuid.UUID, from, to time.Time) (TravelLog, error) {
+	if !to.After(from) {
+		return TravelLog{}, apperror.BadRequest("to must be after from")
+	}
+	if to.Sub(from) > travelLogMaxWindow {
+		return TravelLog{}, apperror.BadRequest("travel log window cannot exceed 7 days")
+	}
+	if _, err := s.q.GetRiderByID(ctx, sqlc.GetRiderByIDParams{ID: riderID, TenantID: tenantID}); errors.Is(err, pgx.ErrNoRows) {
+		return TravelLog{}, apperror.NotFound("rider")
+	} else if err != nil {
+		return TravelLog{}, apperror.Internal("get rider", err)
+	}
+
+	history, err := s.q.ListLocationHistoryBetween(ctx, sqlc.ListLocationHistoryBetweenParams{
+		RiderID: riderID, TenantID: tenantID, FromTime: from, ToTime: to, Limit: travelLogMaxPoints + 1,
+	})
+	if err != nil {
+		return TravelLog{}, apperror.Internal("list location history", err)
+	}
+	attendance, err := s.q.ListAttendanceInRange(ctx, sqlc.ListAttendanceInRangeParams{
+		RiderID: riderID, TenantID: tenantID, FromTime: from, ToTime: to,
+	})
+	if err != nil {
+		return TravelLog{}, apperror.Internal("list attendance", err)
+	}
+
+	travel := TravelLog{RiderID: riderID, From: from, To: to}
+	if len(history) > travelLogMaxPoints {
+		history = history[:travelLogMaxPoints]
+		travel.Truncated = true
+	}
+	points := trackPointsFromHistory(history)
+	travel.History = history
+	travel.Points = len(points)
+	travel.Legs, travel.IdleStops = reconstructTravel(points)
+	travel.Shifts = shiftDistances(attendance, points, time.Now())
+	for _, p := range points[min(1, len(points)):] {
+		travel.DistanceKm += p.DistanceKm
+	}
+	travel.DistanceKm = roundKm(travel.DistanceKm)
+	return travel, nil
+}
+
+func trackPointsFromHistory(rows []sqlc.RiderLocationHistory) []trackPoint {
+	points := make([]trackPoint, 0, len(rows))
+	for _, row := range rows {
+		lat, ok1 := numericToFloat64(row.GeoLat)
+		lng, ok2 := numericToFloat64(row.GeoLng)
+		if !ok1 || !ok2 {
+			continue
+		}
+		km, _ := numericToFloat64(row.DistanceFromPrevKm)
+		p := trackPoint{At: row.CreatedAt, Lat: lat, Lng: lng, Event: row.EventType, DistanceKm: km}
+		if row.OrderID.Valid {
+			id := uuid.UUID(row.OrderID.Bytes)
+			p.OrderID = &id
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// reconstructTravel splits a trail into legs at every event and finds the
+// places the rider stood still. Points must be in time order. Orders are on
+// board from their first picked event until their delivered event; legs
+// ridden with food on board are delivery legs, a leg ending at a pickup with
+// nothing on board is the approach to that restaurant.
+func reconstructTravel(points []trackPoint) ([]TravelLeg, []IdleStop) {
+	legs, stops := []TravelLeg{}, []IdleStop{}
+	var (
+		onBoard  []uuid.UUID
+		leg      *TravelLeg
+		legOrder []uuid.UUID
+		anchor   *trackPoint
+		anchorTo time.Time
+		anchorOn []uuid.UUID
+	)
+
+	closeStop := func() {
+		if anchor != nil && anchorTo.Sub(anchor.At) >= idleStopMinDuration {
+			stops = append(stops, IdleStop{
+				Lat:       anchor.Lat,
+				Lng:       anchor.Lng,
+				StartedAt: anchor.At,
+				EndedAt:   anchorTo,
+				Minutes:   int(anchorTo.Sub(anchor.At).Minutes()),
+				OrderIDs:  anchorOn,
+			})
+		}
+	}
+	closeLeg := func(end trackPoint) {
+		if leg == nil || leg.Points < 2 {
+			return
+		}
+		leg.EndedAt = end.At
+		switch {
+		case len(legOrder) > 0:
+			leg.Kind = LegDelivery
+			leg.OrderIDs = legOrder
+		case end.Event == sqlc.RiderSubjectPicked && end.OrderID != nil:
+			leg.Kind = LegToPickup
+			leg.OrderIDs = []uuid.UUID{*end.OrderID}
+		default:
+			leg.Kind = LegFree
+			leg.OrderIDs = []uuid.UUID{}
+		}
+		first := leg.path[0]
+		leg.StraightKm = roundKm(geo.DistanceKm(first[1], first[0], end.Lat, end.Lng))
+		leg.DistanceKm = roundKm(leg.DistanceKm)
+		leg.Detour = leg.Kind != LegFree && isDetour(leg.DistanceKm, leg.StraightKm)
+		legs = append(legs, *leg)
+	}
+	startLeg := func(p trackPoint) {
+		leg = &TravelLeg{StartedAt: p.At, Points: 1, path: [][2]float64{{p.Lng, p.Lat}}}
+		legOrder = append([]uuid.UUID{}, onBoard...)
+	}
+
+	for i := range points {
+		p := points[i]
+
+		if anchor != nil && geo.DistanceKm(anchor.Lat, anchor.Lng, p.Lat, p.Lng) <= idleStopRadiusKm {
+			anchorTo = p.At
+		} else {
+			closeStop()
+			anchor, anchorTo, anchorOn = &points[i], p.At, append([]uuid.UUID{}, onBoard...)
+		}
+
+		if leg != nil {
+			leg.Points++
+			leg.DistanceKm += p.DistanceKm
+			leg.path = append(leg.path, [2]float64{p.Lng, p.Lat})
+			if p.Event == sqlc.RiderSubjectLocationUpdate {
+				continue
+			}
+			closeLeg(p)
+		}
+
+		if p.OrderID != nil {
+			switch p.Event {
+			case sqlc.RiderSubjectPicked:
+				if !containsUUID(onBoard, *p.OrderID) {
+					onBoard = append(onBoard, *p.OrderID)
+				}
+			case sqlc.RiderSubjectDelivered:
+				onBoard = removeUUID(onBoard, *p.OrderID)
+			}
+		}
+		startLeg(p)
+	}
+	if len(points) > 0 {
+		closeLeg(points[len(points)-1])
+	}
+	closeStop()
+	return legs, stops
+}
+
+// isDetour reports whether a trail is much longer than the straight line
+// between its ends.
+func isDetour(trailKm, straightKm float64) bool {
+	return trailKm > straightKm*detourRatio+detourSlackKm
+}
+
+// shiftDistances totals the distance and deliveries of each attendance
+// record. Open records run until now.
+func shiftDistances(attendance []sqlc.RiderAttendance, points []trackPoint, now time.Time) []ShiftDistance {
+	shifts := make([]ShiftDistance, 0, len(attendance))
+	for _, a := range attendance {
+		if !a.CheckedInAt.Valid {
+			continue
+		}
+		sd := ShiftDistance{AttendanceID: a.ID, CheckedInAt: a.CheckedInAt.Time}
+		end := now
+		if a.CheckedOutAt.Valid {
+			end = a.CheckedOutAt.Time
+			sd.CheckedOutAt = &end
+		}
+		for _, p := range points {
+			if !p.At.After(sd.CheckedInAt) || p.At.After(end) {
+				continue
+			}
+			sd.DistanceKm += p.DistanceKm
+			if p.Event == sqlc.RiderSubjectDelivered {
+				sd.Deliveries++
+			}
+		}
+		sd.DistanceKm = roundKm(sd.DistanceKm)
+		shifts = append(shifts, sd)
+	}
+	return shifts
+}
+
+// orderTrailKm is the distance ridden with an order on board. ok is false
+// when the trail has no legs for the order, as happens with history written
+// before pickup events were recorded.
+func orderTrailKm(legs []TravelLeg, orderID uuid.UUID) (float64, bool) {
+	km, found := 0.0, false
+	for _, l := range legs {
+		if l.Kind == LegDelivery && containsUUID(l.OrderIDs, orderID) {
+			km += l.DistanceKm
+			found = true
+		}
+	}
+	return roundKm(km), found
+}
+
+// windowTrailKm sums the points between from and to that are not tagged with
+// another order.
+func windowTrailKm(points []trackPoint, orderID uuid.UUID, from, to time.Time) (float64, bool) {
+	km, found := 0.0, false
+	for _, p := range points {
+		if !p.At.After(from) || p.At.After(to) {
+			continue
+		}
+		if p.OrderID != nil && *p.OrderID != orderID {
+			continue
+		}
+		km += p.DistanceKm
+		found = true
+	}
+	return roundKm(km), found
+}
+
+// verifyTripDistance picks the distance an order is paid for. The recorded
+// trail is trusted unless it is shorter than the straight-line route, which
+// means the GPS dropped out, or a detour, in which case it is capped at the
+// longest reasonable ride.
+func verifyTripDistance(trailKm float64, hasTrail bool, routeKm float64, hasRoute bool) (km float64, source string, detour bool) {
+	switch {
+	case !hasTrail && !hasRoute:
+		return 0, "", false
+	case !hasTrail:
+		return routeKm, distanceStraightLine, false
+	case !hasRoute:
+		return trailKm, distanceFromTrip, false
+	case isDetour(trailKm, routeKm):
+		return roundKm(routeKm*detourRatio + detourSlackKm), distanceCapped, true
+	case trailKm < routeKm:
+		return routeKm, distanceStraightLine, false
+	}
+	return trailKm, distanceFromTrip, false
+}
+
+// routeKm is the straight-line length of a route through the stops in order.
+func routeKm(stops []geoStop) float64 {
+	km := 0.0
+	for i := 1; i < len(stops); i++ {
+		km += geo.DistanceKm(stops[i-1].Lat, stops[i-1].Lng, stops[i].Lat, stops[i].Lng)
+	}
+	return roundKm(km)
+}
+
+// recordRiderEvent adds an event to the rider's trail at their last known
+// position, which is what legs are split on. Riders that never reported a
+// location have no trail to mark.
+func (s *Service) recordRiderEvent(ctx context.Context, riderID, tenantID uuid.UUID, event sqlc.RiderSubject, orderID *uuid.UUID) {
+	loc, err := s.q.GetRiderLocation(ctx, riderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("rider_id", riderID.String()).Msg("failed to get rider location")
+		return
+	}
+	var order pgtype.UUID
+	if orderID != nil {
+		order = pgtype.UUID{Bytes: *orderID, Valid: true}
+	}
+	if _, err := s.q.AppendLocationHistory(ctx, sqlc.AppendLocationHistoryParams{
+		RiderID:            riderID,
+		TenantID:           tenantID,
+		OrderID:            order,
+		GeoLat:             loc.GeoLat,
+		GeoLng:             loc.GeoLng,
+		EventType:          event,
+		DistanceFromPrevKm: numericFromFloat64(0),
+		CreatedAt:          time.Now(),
+	}); err != nil {
+		log.Error().Err(err).Str("rider_id", riderID.String()).Str("event", string(event)).Msg("failed to record rider event")
+	}
+}
+
+// ---------- GeoJSON ----------
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSONFeatureCollection is the travel log as drawn on the tracking map.
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+// GeoJSON renders each leg as a LineString and each idle stop as a Point.
+// Coordinates are longitude first, as GeoJSON requires.
+func (t TravelLog) GeoJSON() GeoJSONFeatureCollection {
+	fc := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+	for _, l := range t.Legs {
+		fc.Features = append(fc.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: "LineString", Coordinates: l.path},
+			Properties: map[string]interface{}{
+				"kind":             l.Kind,
+				"order_ids":        l.OrderIDs,
+				"started_at":       l.StartedAt,
+				"ended_at":         l.EndedAt,
+				"distance_km":      l.DistanceKm,
+				"straight_line_km": l.StraightKm,
+				"detour":           l.Detour,
+			},
+		})
+	}
+	for _, s := range t.IdleStops {
+		fc.Features = append(fc.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: "Point", Coordinates: [2]float64{s.Lng, s.Lat}},
+			Properties: map[string]interface{}{
+				"kind":       "idle_stop",
+				"order_ids":  s.OrderIDs,
+				"started_at": s.StartedAt,
+				"ended_at":   s.EndedAt,
+				"minutes":    s.Minutes,
+			},
+		})
+	}
+	return fc
+}
+
+func roundKm(km float64) float64 {
+	return math.Round(km*1000) / 1000
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func removeUUID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	out := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
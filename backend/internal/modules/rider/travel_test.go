@@ -0,0 +1,186 @@
+package rider
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/geo"
+)
+
+// trail builds points along a meridian, one every minute, with distances
+// filled in from the previous point.
+type trail struct {
+	start  time.Time
+	points []trackPoint
+}
+
+func (tr *trail) add(lat float64, event sqlc.RiderSubject, order *uuid.UUID) {
+	p := trackPoint{At: tr.start.Add(time.Duration(len(tr.points)) * time.Minute), Lat: lat, Lng: 90.4, Event: event, OrderID: order}
+	if n := len(tr.points); n > 0 {
+		prev := tr.points[n-1]
+		p.DistanceKm = geo.DistanceKm(prev.Lat, prev.Lng, p.Lat, p.Lng)
+	}
+	tr.points = append(tr.points, p)
+}
+
+func TestReconstructTravel(t *testing.T) {
+	orderID := uuid.New()
+	tr := &trail{start: bdTime(t, "2026-03-02 12:00")}
+	tr.add(23.780, sqlc.RiderSubjectAttendanceIn, nil)
+	tr.add(23.785, sqlc.RiderSubjectLocationUpdate, nil)
+	tr.add(23.790, sqlc.RiderSubjectPicked, &orderID)
+	tr.add(23.800, sqlc.RiderSubjectLocationUpdate, &orderID)
+	tr.add(23.810, sqlc.RiderSubjectDelivered, &orderID)
+	tr.add(23.805, sqlc.RiderSubjectLocationUpdate, nil)
+
+	legs, stops := reconstructTravel(tr.points)
+	if len(legs) != 3 || len(stops) != 0 {
+		t.Fatalf("got %d legs and %d stops, want 3 legs and no stops", len(legs), len(stops))
+	}
+	if l := legs[0]; l.Kind != LegToPickup || len(l.OrderIDs) != 1 || l.OrderIDs[0] != orderID || l.Points != 3 {
+		t.Errorf("first leg = %+v, want the approach to the restaurant", l)
+	}
+	if l := legs[1]; l.Kind != LegDelivery || l.OrderIDs[0] != orderID || l.DistanceKm < 2.2 || l.DistanceKm > 2.25 || l.Detour {
+		t.Errorf("second leg = %+v, want a 2.2km delivery", l)
+	}
+	if l := legs[2]; l.Kind != LegFree || len(l.OrderIDs) != 0 {
+		t.Errorf("last leg = %+v, want a free ride", l)
+	}
+
+	km, ok := orderTrailKm(legs, orderID)
+	if !ok || km != legs[1].DistanceKm {
+		t.Errorf("order trail = %v (%v), want the delivery leg", km, ok)
+	}
+	if _, ok := orderTrailKm(legs, uuid.New()); ok {
+		t.Error("an order the rider never carried should have no trail")
+	}
+}
+
+func TestReconstructTravelBatchedOrders(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	tr := &trail{start: bdTime(t, "2026-03-02 12:00")}
+	tr.add(23.780, sqlc.RiderSubjectPicked, &first)
+	tr.add(23.781, sqlc.RiderSubjectPicked, &second)
+	tr.add(23.790, sqlc.RiderSubjectDelivered, &first)
+	tr.add(23.800, sqlc.RiderSubjectDelivered, &second)
+
+	legs, _ := reconstructTravel(tr.points)
+	if len(legs) != 3 {
+		t.Fatalf("len = %d, want 3", len(legs))
+	}
+	if l := legs[1]; len(l.OrderIDs) != 2 {
+		t.Errorf("shared leg carries %v, want both orders", l.OrderIDs)
+	}
+	if l := legs[2]; len(l.OrderIDs) != 1 || l.OrderIDs[0] != second {
+		t.Errorf("last leg carries %v, want only the second order", l.OrderIDs)
+	}
+}
+
+func TestReconstructTravelDetourAndIdleStop(t *testing.T) {
+	orderID := uuid.New()
+	tr := &trail{start: bdTime(t, "2026-03-02 12:00")}
+	tr.add(23.780, sqlc.RiderSubjectPicked, &orderID)
+	// Rides 2.2km away and waits there for 12 minutes with the food.
+	tr.add(23.800, sqlc.RiderSubjectLocationUpdate, &orderID)
+	for i := 0; i < 12; i++ {
+		tr.add(23.8001, sqlc.RiderSubjectLocationUpdate, &orderID)
+	}
+	tr.add(23.785, sqlc.RiderSubjectDelivered, &orderID)
+
+	legs, stops := reconstructTravel(tr.points)
+	if len(legs) != 1 || !legs[0].Detour {
+		t.Fatalf("legs = %+v, want one detour", legs)
+	}
+	if len(stops) != 1 || stops[0].Minutes != 12 || len(stops[0].OrderIDs) != 1 {
+		t.Errorf("stops = %+v, want a 12 minute stop with the order on board", stops)
+	}
+}
+
+func TestShiftDistances(t *testing.T) {
+	tr := &trail{start: bdTime(t, "2026-03-02 12:00")}
+	orderID := uuid.New()
+	tr.add(23.780, sqlc.RiderSubjectAttendanceIn, nil)
+	tr.add(23.790, sqlc.RiderSubjectDelivered, &orderID)
+	tr.add(23.800, sqlc.RiderSubjectAttendanceOut, nil)
+	tr.add(23.810, sqlc.RiderSubjectLocationUpdate, nil)
+
+	in := tr.points[0].At
+	out := tr.points[2].At
+	shifts := shiftDistances([]sqlc.RiderAttendance{{
+		ID:           uuid.New(),
+		CheckedInAt:  pgtype.Timestamptz{Time: in, Valid: true},
+		CheckedOutAt: pgtype.Timestamptz{Time: out, Valid: true},
+	}}, tr.points, time.Now())
+	if len(shifts) != 1 || shifts[0].Deliveries != 1 || shifts[0].DistanceKm < 2.2 || shifts[0].DistanceKm > 2.25 {
+		t.Errorf("shifts = %+v, want 2.2km and one delivery before checkout", shifts)
+	}
+}
+
+func TestVerifyTripDistance(t *testing.T) {
+	tests := []struct {
+		name       string
+		trail      float64
+		hasTrail   bool
+		route      float64
+		hasRoute   bool
+		wantKm     float64
+		wantSource string
+		wantDetour bool
+	}{
+		{"nothing recorded", 0, false, 0, false, 0, "", false},
+		{"route only", 0, false, 3, true, 3, distanceStraightLine, false},
+		{"trail only", 4, true, 0, false, 4, distanceFromTrip, false},
+		{"normal ride", 3.8, true, 3, true, 3.8, distanceFromTrip, false},
+		{"gps gap", 1.2, true, 3, true, 3, distanceStraightLine, false},
+		{"detour", 9, true, 3, true, 5.3, distanceCapped, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			km, source, detour := verifyTripDistance(tc.trail, tc.hasTrail, tc.route, tc.hasRoute)
+			if km != tc.wantKm || source != tc.wantSource || detour != tc.wantDetour {
+				t.Errorf("got %v %q %v, want %v %q %v", km, source, detour, tc.wantKm, tc.wantSource, tc.wantDetour)
+			}
+		})
+	}
+}
+
+func TestTravelLogGeoJSON(t *testing.T) {
+	orderID := uuid.New()
+	tr := &trail{start: bdTime(t, "2026-03-02 12:00")}
+	tr.add(23.780, sqlc.RiderSubjectPicked, &orderID)
+	tr.add(23.790, sqlc.RiderSubjectDelivered, &orderID)
+
+	var travel TravelLog
+	travel.Legs, travel.IdleStops = reconstructTravel(tr.points)
+	raw, err := json.Marshal(travel.GeoJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string       `json:"type"`
+				Coordinates [][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(raw, &fc); err != nil {
+		t.Fatal(err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 1 {
+		t.Fatalf("collection = %s, want one feature", raw)
+	}
+	f := fc.Features[0]
+	if f.Geometry.Type != "LineString" || len(f.Geometry.Coordinates) != 2 || f.Properties["kind"] != LegDelivery {
+		t.Errorf("feature = %+v, want a delivery LineString", f)
+	}
+	if c := f.Geometry.Coordinates[0]; c[0] != 90.4 || c[1] != 23.780 {
+		t.Errorf("first coordinate = %v, want longitude first", c)
+	}
+}
@@ -1,10 +1,12 @@
 package rider
 
 import (
+	"context"
 	"encoding/json"
 	"math/big"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -12,8 +14,6 @@ import (
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
-	"github.com/munchies/platform/backend/internal/pkg/geo"
-	redisclient "github.com/munchies/platform/backend/internal/platform/redis"
 	"github.com/rs/zerolog/log"
 )
 
@@ -25,24 +25,34 @@ var upgrader = websocket.Upgrader{
 
 // WSHandler handles WebSocket connections for rider location tracking.
 type WSHandler struct {
-	q      *sqlc.Queries
-	tokens auth.TokenConfig
-	redis  *redisclient.Client
+	q         *sqlc.Queries
+	tokens    auth.TokenConfig
+	locations *LocationPipeline
 }
 
 // NewWSHandler creates a new WebSocket handler.
-func NewWSHandler(q *sqlc.Queries, tokens auth.TokenConfig, redis *redisclient.Client) *WSHandler {
-	return &WSHandler{q: q, tokens: tokens, redis: redis}
+func NewWSHandler(q *sqlc.Queries, tokens auth.TokenConfig, locations *LocationPipeline) *WSHandler {
+	return &WSHandler{q: q, tokens: tokens, locations: locations}
+}
+
+// maxFixAge is how old a fix recorded by the app may be. Apps send buffered
+// fixes in a batch after losing connectivity.
+const maxFixAge = 10 * time.Minute
+
+type wsPoint struct {
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
+	Heading    float64 `json:"heading,omitempty"`
+	Speed      float64 `json:"speed,omitempty"`
+	Accuracy   float64 `json:"accuracy,omitempty"`
+	RecordedAt *int64  `json:"recorded_at,omitempty"` // unix milliseconds
 }
 
 type wsMessage struct {
-	Type        string  `json:"type"`
-	Lat         float64 `json:"lat,omitempty"`
-	Lng         float64 `json:"lng,omitempty"`
-	Heading     float64 `json:"heading,omitempty"`
-	Speed       float64 `json:"speed,omitempty"`
-	Accuracy    float64 `json:"accuracy,omitempty"`
-	IsAvailable *bool   `json:"is_available,omitempty"`
+	wsPoint
+	Type        string    `json:"type"`
+	Points      []wsPoint `json:"points,omitempty"`
+	IsAvailable *bool     `json:"is_available,omitempty"`
 }
 
 // HandleWS handles WS /api/v1/rider/ws
@@ -125,65 +135,53 @@ func (h *WSHandler) HandleWS(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	h.locations.Disconnect(context.WithoutCancel(r.Context()), rider.ID)
 	log.Info().Str("rider_id", rider.ID.String()).Msg("rider websocket disconnected")
 }
 
+// handleLocation feeds a fix, or a batch of buffered fixes, into the location
+// pipeline and tells the app about fixes that were dropped.
 func (h *WSHandler) handleLocation(r *http.Request, conn *websocket.Conn, rider sqlc.Rider, tenantID uuid.UUID, msg wsMessage) {
-	ctx := r.Context()
-	latNum := numericFromFloat64(msg.Lat)
-	lngNum := numericFromFloat64(msg.Lng)
-
-	// Get previous location for distance calculation
-	var distKm float64
-	prevLoc, err := h.q.GetRiderLocation(ctx, rider.ID)
-	if err == nil {
-		prevLat, _ := numericToFloat64(prevLoc.GeoLat)
-		prevLng, _ := numericToFloat64(prevLoc.GeoLng)
-		distKm = geo.DistanceKm(prevLat, prevLng, msg.Lat, msg.Lng)
-	}
-
-	// Upsert current location
-	_, err = h.q.UpsertRiderLocation(ctx, sqlc.UpsertRiderLocationParams{
-		RiderID:        rider.ID,
-		TenantID:       tenantID,
-		GeoLat:         latNum,
-		GeoLng:         lngNum,
-		Heading:        numericFromFloat64(msg.Heading),
-		SpeedKmh:       numericFromFloat64(msg.Speed),
-		AccuracyMeters: numericFromFloat64(msg.Accuracy),
-	})
-	if err != nil {
-		log.Error().Err(err).Msg("upsert rider location failed")
-		return
+	points := msg.Points
+	if len(points) == 0 {
+		points = []wsPoint{msg.wsPoint}
+	}
+
+	now := time.Now()
+	for _, pt := range points {
+		fix := locationFix{
+			Lat:       pt.Lat,
+			Lng:       pt.Lng,
+			Heading:   pt.Heading,
+			SpeedKmh:  pt.Speed,
+			AccuracyM: pt.Accuracy,
+			At:        fixTime(pt.RecordedAt, now),
+		}
+		reason := h.locations.Ingest(r.Context(), rider.ID, tenantID, fix)
+		if reason == "" {
+			continue
+		}
+		if err := conn.WriteJSON(map[string]interface{}{
+			"type":        "location_rejected",
+			"reason":      reason,
+			"recorded_at": fix.At,
+		}); err != nil {
+			log.Warn().Err(err).Str("rider_id", rider.ID.String()).Msg("websocket write failed")
+			return
+		}
 	}
+}
 
-	// Append to location history
-	_, err = h.q.AppendLocationHistory(ctx, sqlc.AppendLocationHistoryParams{
-		RiderID:            rider.ID,
-		TenantID:           tenantID,
-		GeoLat:             latNum,
-		GeoLng:             lngNum,
-		EventType:          sqlc.RiderSubjectLocationUpdate,
-		DistanceFromPrevKm: numericFromFloat64(distKm),
-	})
-	if err != nil {
-		log.Error().Err(err).Msg("append location history failed")
+// fixTime trusts the app's timestamp unless it is in the future or too old.
+func fixTime(recordedAtMs *int64, now time.Time) time.Time {
+	if recordedAtMs == nil {
+		return now
 	}
-
-	// Publish to Redis
-	locData, _ := json.Marshal(map[string]interface{}{
-		"rider_id": rider.ID,
-		"lat":      msg.Lat,
-		"lng":      msg.Lng,
-		"heading":  msg.Heading,
-		"speed":    msg.Speed,
-	})
-	if h.redis != nil {
-		channel := "rider:" + rider.ID.String() + ":location"
-		if err := h.redis.Publish(ctx, channel, string(locData)); err != nil {
-			log.Error().Err(err).Msg("redis publish failed")
-		}
+	at := time.UnixMilli(*recordedAtMs)
+	if at.After(now) || now.Sub(at) > maxFixAge {
+		return now
 	}
+	return at
 }
 
 func (h *WSHandler) handleStatus(r *http.Request, rider sqlc.Rider, tenantID uuid.UUID, msg wsMessage) {
@@ -2,10 +2,14 @@ package sse
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/modules/auth"
 	"github.com/munchies/platform/backend/internal/modules/tenant"
 	"github.com/munchies/platform/backend/internal/pkg/apperror"
@@ -17,14 +21,17 @@ import (
 // Handler handles SSE connections.
 type Handler struct {
 	redis *redisclient.Client
+	q     *sqlc.Queries
 }
 
 // NewHandler creates a new SSE handler.
-func NewHandler(redis *redisclient.Client) *Handler {
-	return &Handler{redis: redis}
+func NewHandler(redis *redisclient.Client, q *sqlc.Queries) *Handler {
+	return &Handler{redis: redis, q: q}
 }
 
-// Subscribe handles GET /api/v1/events/subscribe
+// Subscribe handles GET /api/v1/events/subscribe. Passing order_id also
+// subscribes to that order's channel, which carries live rider positions and
+// ETAs.
 func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 	u := auth.UserFromContext(r.Context())
 	if u == nil {
@@ -33,6 +40,16 @@ func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 	}
 	t := tenant.FromContext(r.Context())
 
+	var orderChannel string
+	if v := r.URL.Query().Get("order_id"); v != "" {
+		channel, appErr := h.orderChannel(r.Context(), u, t, v)
+		if appErr != nil {
+			respond.Error(w, appErr)
+			return
+		}
+		orderChannel = channel
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -52,6 +69,9 @@ func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 	if t != nil {
 		channels = append(channels, fmt.Sprintf("tenant:%s", t.ID.String()))
 	}
+	if orderChannel != "" {
+		channels = append(channels, orderChannel)
+	}
 
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
@@ -106,4 +126,31 @@ func (h *Handler) Subscribe(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// orderChannel checks that the user may follow an order: customers their own
+// orders, tenant owners and admins any order of the tenant.
+func (h *Handler) orderChannel(ctx context.Context, u *sqlc.User, t *sqlc.Tenant, rawID string) (string, *apperror.AppError) {
+	orderID, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", apperror.BadRequest("invalid order_id")
+	}
+	if t == nil {
+		return "", apperror.NotFound("tenant")
+	}
+	order, err := h.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: t.ID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", apperror.NotFound("order")
+	}
+	if err != nil {
+		return "", apperror.Internal("get order", err)
+	}
+	switch u.Role {
+	case sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin:
+	default:
+		if order.CustomerID != u.ID {
+			return "", apperror.NotFound("order")
+		}
+	}
+	return fmt.Sprintf("order:%s", order.ID.String()), nil
+}
+
 var _ = log.Logger // ensure zerolog import
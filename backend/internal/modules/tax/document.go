@@ -0,0 +1,238 @@
+package tax
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/pdf"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/shopspring/decimal"
+)
+
+// categoryLabels names Mushak-9.1 supply categories.
+var categoryLabels = map[string]string{
+	CategoryExempt:   "Exempted goods/services",
+	CategoryStandard: "Standard rated goods/services",
+	CategoryReduced:  "Goods/services other than standard rate",
+}
+
+// GenerateOrderInvoicesPDF renders an order's Mushak-6.3 tax invoices, one
+// seller per page.
+func GenerateOrderInvoicesPDF(ctx context.Context, kit *documents.Kit, t *sqlc.Tenant, data *OrderTaxInvoices) ([]byte, error) {
+	brand := kit.Brand(ctx, t)
+	loc := documents.Location(t)
+	currency := t.Currency
+
+	d := kit.Document("Tax invoices "+data.Order.OrderNumber, t.Name)
+	for i, inv := range data.Invoices {
+		lines, err := DecodeLines(inv)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			d.AddPage()
+		}
+		supplied := inv.SuppliedAt.In(loc)
+		d.Letterhead(brand, "TAX INVOICE", []pdf.Field{
+			{Label: "Form", Value: "Mushak-6.3"},
+			{Label: "Invoice no", Value: inv.InvoiceNumber},
+			{Label: "Order no", Value: data.Order.OrderNumber},
+			{Label: "Date of issue", Value: supplied.Format("2 Jan 2006")},
+			{Label: "Time of issue", Value: supplied.Format("3:04 PM")},
+		})
+
+		d.Heading("Seller", brand.Color)
+		sellerFields := []pdf.Field{{Label: "Name", Value: inv.SellerName}, {Label: "BIN", Value: orNotRegistered(inv.SellerBin.String)}}
+		if inv.SellerAddress.Valid {
+			sellerFields = append(sellerFields, pdf.Field{Label: "Address", Value: inv.SellerAddress.String})
+		}
+		d.Fields(sellerFields)
+		d.Space(6)
+
+		d.Heading("Buyer", brand.Color)
+		buyerFields := []pdf.Field{{Label: "Name", Value: inv.BuyerName}}
+		if inv.BuyerPhone.Valid {
+			buyerFields = append(buyerFields, pdf.Field{Label: "Phone", Value: inv.BuyerPhone.String})
+		}
+		if inv.BuyerAddress.Valid {
+			buyerFields = append(buyerFields, pdf.Field{Label: "Delivery address", Value: inv.BuyerAddress.String})
+		}
+		d.Fields(buyerFields)
+		d.Space(6)
+
+		d.Heading("Supplies", brand.Color)
+		rows := make([]pdf.Row, 0, len(lines)+1)
+		for n, l := range lines {
+			rows = append(rows, pdf.Row{Cells: []string{
+				fmt.Sprint(n + 1),
+				l.Description,
+				fmt.Sprint(l.Quantity),
+				documents.Money(l.TaxableValue),
+				l.VatRate.StringFixed(2) + "%",
+				documents.Money(l.VatAmount),
+				documents.Money(l.Total),
+			}})
+		}
+		rows = append(rows, pdf.Row{Cells: []string{"", "Total", "", formatNumeric(inv.TaxableValue), "", formatNumeric(inv.VatAmount), formatNumeric(inv.TotalAmount)}, Bold: true})
+		d.Table(pdf.Table{
+			Columns: []pdf.Column{
+				{Title: "#", Width: 0.4},
+				{Title: "Description", Width: 3},
+				{Title: "Qty", Width: 0.6, Align: pdf.AlignRight},
+				{Title: "Taxable value (" + currency + ")", Width: 1.8, Align: pdf.AlignRight},
+				{Title: "VAT rate", Width: 1, Align: pdf.AlignRight},
+				{Title: "VAT (" + currency + ")", Width: 1.4, Align: pdf.AlignRight},
+				{Title: "Total (" + currency + ")", Width: 1.6, Align: pdf.AlignRight},
+			},
+			Rows:     rows,
+			Accent:   brand.Color,
+			FontSize: 8.5,
+		})
+		d.Space(10)
+		if inv.RestaurantID.Valid {
+			d.Paragraph("Item prices are after item discounts. Platform promotions, delivery charges, service fees and rider tips are not part of this supply.", pdf.Style{Size: 8.5, Color: pdf.Gray}, pdf.AlignLeft)
+		} else {
+			d.Paragraph("Delivery charges and service fees include VAT. Rider tips are passed to the rider and are not a taxable supply.", pdf.Style{Size: 8.5, Color: pdf.Gray}, pdf.AlignLeft)
+		}
+	}
+	return d.Bytes()
+}
+
+// GenerateReturnPDF renders a Mushak-9.1 style monthly VAT return.
+func GenerateReturnPDF(ctx context.Context, kit *documents.Kit, t *sqlc.Tenant, ret *Return) ([]byte, error) {
+	brand := kit.Brand(ctx, t)
+	currency := t.Currency
+
+	d := kit.Document("VAT return "+ret.Period, t.Name)
+	d.Letterhead(brand, "VAT RETURN", []pdf.Field{
+		{Label: "Form", Value: "Mushak-9.1"},
+		{Label: "Tax period", Value: ret.PeriodStart.Format("January 2006")},
+		{Label: "Invoices", Value: fmt.Sprint(ret.Invoices)},
+	})
+
+	d.Heading("Part 1: Taxpayer", brand.Color)
+	taxpayer := []pdf.Field{{Label: "Name", Value: ret.SellerName}, {Label: "BIN", Value: orNotRegistered(ret.SellerBin)}}
+	if ret.SellerAddress != "" {
+		taxpayer = append(taxpayer, pdf.Field{Label: "Address", Value: ret.SellerAddress})
+	}
+	d.Fields(taxpayer)
+	d.Space(6)
+
+	d.Heading("Part 3: Supply (output tax)", brand.Color)
+	rows := make([]pdf.Row, 0, len(ret.Supplies)+1)
+	for _, s := range ret.Supplies {
+		rows = append(rows, pdf.Row{Cells: []string{
+			fmt.Sprint(s.Note),
+			categoryLabels[s.Category],
+			s.VatRate.StringFixed(2) + "%",
+			documents.Money(s.TaxableValue),
+			documents.Money(s.VatAmount),
+		}})
+	}
+	rows = append(rows, pdf.Row{Cells: []string{"", "Total", "", documents.Money(ret.TaxableValue), documents.Money(ret.OutputVat)}, Bold: true})
+	d.Table(pdf.Table{
+		Columns: []pdf.Column{
+			{Title: "Note", Width: 0.6},
+			{Title: "Nature of supply", Width: 3.4},
+			{Title: "VAT rate", Width: 1, Align: pdf.AlignRight},
+			{Title: "Value (" + currency + ")", Width: 1.8, Align: pdf.AlignRight},
+			{Title: "VAT (" + currency + ")", Width: 1.6, Align: pdf.AlignRight},
+		},
+		Rows:   rows,
+		Accent: brand.Color,
+	})
+	d.Space(8)
+
+	d.Heading("Part 5: Net tax", brand.Color)
+	d.Table(pdf.Table{
+		Columns: []pdf.Column{{Title: "Description", Width: 3}, {Title: "Amount (" + currency + ")", Width: 1, Align: pdf.AlignRight}},
+		Rows: []pdf.Row{
+			{Cells: []string{"Output tax", documents.Money(ret.OutputVat)}},
+			{Cells: []string{"Input tax credit", documents.Money(ret.InputVat)}},
+			{Cells: []string{"Net tax payable", documents.Money(ret.NetPayable)}, Bold: true},
+		},
+		Accent: brand.Color,
+	})
+	if len(ret.Uninvoiced) > 0 {
+		d.Space(8)
+		d.Heading("Orders without tax invoices", brand.Color)
+		loc := documents.Location(t)
+		missing := make([]pdf.Row, 0, len(ret.Uninvoiced))
+		for _, o := range ret.Uninvoiced {
+			status := fmt.Sprintf("Retrying (%d attempts)", o.Attempts)
+			if o.Permanent {
+				status = "Issue by hand"
+			}
+			missing = append(missing, pdf.Row{Cells: []string{o.OrderNumber, o.DeliveredAt.In(loc).Format("02 Jan 2006 15:04"), status}})
+		}
+		d.Table(pdf.Table{
+			Columns: []pdf.Column{{Title: "Order", Width: 1.6}, {Title: "Delivered", Width: 1.6}, {Title: "Status", Width: 2}},
+			Rows:    missing,
+			Accent:  brand.Color,
+		})
+	}
+	d.Space(10)
+	d.Paragraph("Prepared from the Mushak-6.3 tax invoices issued on the platform for the period. Purchases are not recorded on the platform; add input tax credit from purchase records (Part 4) before filing.", pdf.Style{Size: 8.5, Color: pdf.Gray}, pdf.AlignLeft)
+	return d.Bytes()
+}
+
+// WriteReturnCSV writes a monthly VAT return as CSV: the supplies by note
+// and rate followed by the net tax lines.
+func WriteReturnCSV(w io.Writer, ret *Return) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"period", "seller_name", "seller_bin", "note", "nature_of_supply", "vat_rate", "taxable_value", "vat_amount"})
+	for _, s := range ret.Supplies {
+		cw.Write([]string{ret.Period, ret.SellerName, ret.SellerBin, fmt.Sprint(s.Note), categoryLabels[s.Category], s.VatRate.StringFixed(2), s.TaxableValue.StringFixed(2), s.VatAmount.StringFixed(2)})
+	}
+	for _, total := range []struct {
+		label  string
+		value  decimal.Decimal
+		amount decimal.Decimal
+	}{
+		{"Output tax", ret.TaxableValue, ret.OutputVat},
+		{"Input tax credit", decimal.Zero, ret.InputVat},
+		{"Net tax payable", decimal.Zero, ret.NetPayable},
+	} {
+		cw.Write([]string{ret.Period, ret.SellerName, ret.SellerBin, "", total.label, "", total.value.StringFixed(2), total.amount.StringFixed(2)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteInvoiceRegisterCSV writes one row per tax invoice, for the sales
+// register kept alongside the return.
+func WriteInvoiceRegisterCSV(w io.Writer, invoices []sqlc.TaxInvoice, t *sqlc.Tenant) error {
+	loc := documents.Location(t)
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"invoice_number", "supplied_at", "order_id", "seller_name", "seller_bin", "buyer_name", "taxable_value", "vat_amount", "total_amount"})
+	for _, inv := range invoices {
+		cw.Write([]string{
+			inv.InvoiceNumber,
+			inv.SuppliedAt.In(loc).Format("2006-01-02 15:04"),
+			inv.OrderID.String(),
+			inv.SellerName,
+			inv.SellerBin.String,
+			inv.BuyerName,
+			numericToDecimal(inv.TaxableValue).StringFixed(2),
+			numericToDecimal(inv.VatAmount).StringFixed(2),
+			numericToDecimal(inv.TotalAmount).StringFixed(2),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func orNotRegistered(bin string) string {
+	if bin == "" {
+		return "Not registered"
+	}
+	return bin
+}
+
+func formatNumeric(n pgtype.Numeric) string {
+	return documents.Money(numericToDecimal(n))
+}
@@ -0,0 +1,380 @@
+package tax
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/access"
+	"github.com/munchies/platform/backend/internal/modules/auth"
+	"github.com/munchies/platform/backend/internal/modules/tenant"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/pkg/respond"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/shopspring/decimal"
+)
+
+// Handler handles VAT HTTP requests.
+type Handler struct {
+	svc  *Service
+	docs *documents.Kit
+}
+
+// NewHandler creates a new tax handler.
+func NewHandler(svc *Service, docs *documents.Kit) *Handler {
+	return &Handler{svc: svc, docs: docs}
+}
+
+// GetSettings handles GET /partner/tax/settings
+func (h *Handler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	settings, err := h.svc.GetSettings(r.Context(), t.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /partner/tax/settings
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+
+	var req SettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	settings, err := h.svc.UpdateSettings(r.Context(), t.ID, req, u.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "tax_settings.updated", "tenant", t.ID, settings.Bin.String)
+	respond.JSON(w, http.StatusOK, settings)
+}
+
+// GetRegistration handles GET /partner/tax/restaurants/:id/registration
+func (h *Handler) GetRegistration(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
+	reg, err := h.svc.GetRegistration(r.Context(), t.ID, restaurantID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	respond.JSON(w, http.StatusOK, reg)
+}
+
+// UpdateRegistration handles PUT /partner/tax/restaurants/:id/registration
+func (h *Handler) UpdateRegistration(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
+
+	var req RegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond.Error(w, apperror.BadRequest("invalid request body"))
+		return
+	}
+	reg, err := h.svc.UpdateRegistration(r.Context(), t.ID, restaurantID, req, u.ID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	h.svc.CreateAuditLog(r.Context(), t.ID, u.ID, "vat_registration.updated", "restaurant", restaurantID, reg.Bin)
+	respond.JSON(w, http.StatusOK, reg)
+}
+
+// GetRestaurantReturn handles GET /partner/tax/restaurants/:id/returns?month=YYYY-MM&format=json|csv|pdf
+func (h *Handler) GetRestaurantReturn(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
+	period, err := ParsePeriod(t, r.URL.Query().Get("month"))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	ret, err := h.svc.RestaurantReturn(r.Context(), t.ID, restaurantID, period)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	h.writeReturn(w, r, t, ret, "vat-return-"+restaurantID.String()[:8]+"-"+period.Month)
+}
+
+// GetFeeReturn handles GET /partner/tax/returns?month=YYYY-MM&format=json|csv|pdf
+// for the tenant's own delivery and service fee supplies.
+func (h *Handler) GetFeeReturn(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	period, err := ParsePeriod(t, r.URL.Query().Get("month"))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	ret, err := h.svc.FeeReturn(r.Context(), t.ID, period)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	h.writeReturn(w, r, t, ret, "vat-return-fees-"+period.Month)
+}
+
+// ListRestaurantInvoices handles GET /partner/tax/restaurants/:id/invoices?month=YYYY-MM&format=json|csv
+func (h *Handler) ListRestaurantInvoices(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	restaurantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid restaurant id"))
+		return
+	}
+	period, err := ParsePeriod(t, r.URL.Query().Get("month"))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	invoices, err := h.svc.RestaurantInvoices(r.Context(), t.ID, restaurantID, period)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	h.writeRegister(w, r, t, invoices, "tax-invoices-"+restaurantID.String()[:8]+"-"+period.Month)
+}
+
+// ListFeeInvoices handles GET /partner/tax/invoices?month=YYYY-MM&format=json|csv
+func (h *Handler) ListFeeInvoices(w http.ResponseWriter, r *http.Request) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	period, err := ParsePeriod(t, r.URL.Query().Get("month"))
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	invoices, err := h.svc.FeeInvoices(r.Context(), t.ID, period)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+	h.writeRegister(w, r, t, invoices, "tax-invoices-fees-"+period.Month)
+}
+
+// GetOrderInvoicesPartner handles GET /partner/orders/:id/tax-invoices. Staff
+// scoped to some restaurants only see those restaurants' invoices.
+func (h *Handler) GetOrderInvoicesPartner(w http.ResponseWriter, r *http.Request) {
+	data, ok := h.partnerOrderInvoices(w, r)
+	if !ok {
+		return
+	}
+	respond.JSON(w, http.StatusOK, data)
+}
+
+// GetOrderInvoicesPartnerPDF handles GET /partner/orders/:id/tax-invoices/pdf
+func (h *Handler) GetOrderInvoicesPartnerPDF(w http.ResponseWriter, r *http.Request) {
+	data, ok := h.partnerOrderInvoices(w, r)
+	if !ok {
+		return
+	}
+	h.writeOrderInvoicesPDF(w, r, data)
+}
+
+func (h *Handler) partnerOrderInvoices(w http.ResponseWriter, r *http.Request) (*OrderTaxInvoices, bool) {
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return nil, false
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order id"))
+		return nil, false
+	}
+	data, err := h.svc.OrderInvoices(r.Context(), t.ID, orderID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return nil, false
+	}
+	if scope := access.FromContext(r.Context()); scope != nil && !scope.TenantWide {
+		data.Restrict(scope.Allows)
+	}
+	return data, true
+}
+
+// GetOrderInvoicesPDF handles GET /api/v1/orders/:id/tax-invoice
+func (h *Handler) GetOrderInvoicesPDF(w http.ResponseWriter, r *http.Request) {
+	u := auth.UserFromContext(r.Context())
+	if u == nil {
+		respond.Error(w, apperror.Unauthorized("authentication required"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	if t == nil {
+		respond.Error(w, apperror.NotFound("tenant"))
+		return
+	}
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, apperror.BadRequest("invalid order id"))
+		return
+	}
+
+	data, err := h.svc.OrderInvoices(r.Context(), t.ID, orderID)
+	if err != nil {
+		respond.Error(w, toAppError(err))
+		return
+	}
+
+	// Ensure customer can only download their own tax invoices
+	if data.Order.CustomerID != u.ID && u.Role == sqlc.UserRoleCustomer {
+		respond.Error(w, apperror.Forbidden("access denied"))
+		return
+	}
+	h.writeOrderInvoicesPDF(w, r, data)
+}
+
+// GetPriceBreakdown handles GET /partner/tax/price-breakdown?amount=&vat_rate=&inclusive=true|false
+func (h *Handler) GetPriceBreakdown(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	amount, err := decimal.NewFromString(q.Get("amount"))
+	if err != nil || amount.IsNegative() {
+		respond.Error(w, apperror.BadRequest("amount must be a non-negative number"))
+		return
+	}
+	rate := StandardRate
+	if v := q.Get("vat_rate"); v != "" {
+		if rate, err = decimal.NewFromString(v); err != nil || rate.IsNegative() || rate.GreaterThan(hundred) {
+			respond.Error(w, apperror.BadRequest("vat_rate must be between 0 and 100"))
+			return
+		}
+	}
+	inclusive := true
+	if v := q.Get("inclusive"); v != "" {
+		if inclusive, err = strconv.ParseBool(v); err != nil {
+			respond.Error(w, apperror.BadRequest("inclusive must be true or false"))
+			return
+		}
+	}
+	respond.JSON(w, http.StatusOK, Split(amount, rate, inclusive))
+}
+
+// writeOrderInvoicesPDF renders an order's tax invoices as a PDF download.
+func (h *Handler) writeOrderInvoicesPDF(w http.ResponseWriter, r *http.Request, data *OrderTaxInvoices) {
+	if len(data.Invoices) == 0 {
+		respond.Error(w, apperror.NotFound("tax invoice"))
+		return
+	}
+	t := tenant.FromContext(r.Context())
+	pdfBytes, err := GenerateOrderInvoicesPDF(r.Context(), h.docs, t, data)
+	if err != nil {
+		respond.Error(w, apperror.Internal("failed to generate PDF", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\""+data.Order.OrderNumber+"-tax-invoice.pdf\"")
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdfBytes)
+}
+
+// writeReturn serves a VAT return in the ?format= asked for.
+func (h *Handler) writeReturn(w http.ResponseWriter, r *http.Request, t *sqlc.Tenant, ret *Return, name string) {
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		respond.JSON(w, http.StatusOK, ret)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".csv\"")
+		w.WriteHeader(http.StatusOK)
+		WriteReturnCSV(w, ret)
+	case "pdf":
+		pdfBytes, err := GenerateReturnPDF(r.Context(), h.docs, t, ret)
+		if err != nil {
+			respond.Error(w, apperror.Internal("failed to generate PDF", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".pdf\"")
+		w.WriteHeader(http.StatusOK)
+		w.Write(pdfBytes)
+	default:
+		respond.Error(w, apperror.BadRequest("format must be json, csv or pdf"))
+	}
+}
+
+// writeRegister serves a tax invoice register in the ?format= asked for.
+func (h *Handler) writeRegister(w http.ResponseWriter, r *http.Request, t *sqlc.Tenant, invoices []sqlc.TaxInvoice, name string) {
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		respond.JSON(w, http.StatusOK, invoices)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\""+name+".csv\"")
+		w.WriteHeader(http.StatusOK)
+		WriteInvoiceRegisterCSV(w, invoices, t)
+	default:
+		respond.Error(w, apperror.BadRequest("format must be json or csv"))
+	}
+}
+
+func toAppError(err error) *apperror.AppError {
+	if e, ok := err.(*apperror.AppError); ok {
+		return e
+	}
+	return apperror.Internal("unexpected error", err)
+}
@@ -0,0 +1,633 @@
+package tax
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/apperror"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// issueBatchSize caps the orders the worker issues tax invoices for per run.
+const issueBatchSize = 200
+
+// issueLookback is how far back the worker looks for delivered orders.
+// Older orders, such as those delivered before tax invoicing went live, are
+// only issued on demand.
+const issueLookback = 7 * 24 * time.Hour
+
+// issueMaxAttempts is how many times the worker tries an order before it
+// gives up on it. Retries back off from 5 minutes, doubling each time.
+const issueMaxAttempts = 5
+
+// binPattern matches a Business Identification Number: 9 digits, or 13 with
+// the branch suffix.
+var binPattern = regexp.MustCompile(`^(\d{9}|\d{13})$`)
+
+// Service implements VAT registration, tax invoice and return logic.
+type Service struct {
+	q    *sqlc.Queries
+	pool *pgxpool.Pool
+}
+
+// NewService creates a new tax service.
+func NewService(q *sqlc.Queries, pool *pgxpool.Pool) *Service {
+	return &Service{q: q, pool: pool}
+}
+
+// ---------- Settings and registrations ----------
+
+// SettingsRequest updates a tenant's VAT registration and fee VAT rates.
+// Omitted rates default to the standard rate.
+type SettingsRequest struct {
+	Bin                string           `json:"bin"`
+	RegisteredName     string           `json:"registered_name"`
+	RegisteredAddress  string           `json:"registered_address"`
+	DeliveryFeeVatRate *decimal.Decimal `json:"delivery_fee_vat_rate"`
+	ServiceFeeVatRate  *decimal.Decimal `json:"service_fee_vat_rate"`
+}
+
+func (r *SettingsRequest) validate() error {
+	r.Bin = strings.TrimSpace(r.Bin)
+	if r.Bin != "" && !binPattern.MatchString(r.Bin) {
+		return apperror.BadRequest("bin must be 9 or 13 digits")
+	}
+	if r.DeliveryFeeVatRate == nil {
+		r.DeliveryFeeVatRate = &StandardRate
+	}
+	if r.ServiceFeeVatRate == nil {
+		r.ServiceFeeVatRate = &StandardRate
+	}
+	for _, rate := range []decimal.Decimal{*r.DeliveryFeeVatRate, *r.ServiceFeeVatRate} {
+		if rate.IsNegative() || rate.GreaterThan(hundred) {
+			return apperror.BadRequest("VAT rates must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// GetSettings returns a tenant's tax settings, or the defaults (no BIN,
+// standard rate on fees) when none are saved.
+func (s *Service) GetSettings(ctx context.Context, tenantID uuid.UUID) (sqlc.TaxSetting, error) {
+	return getSettings(ctx, s.q, tenantID)
+}
+
+func getSettings(ctx context.Context, q *sqlc.Queries, tenantID uuid.UUID) (sqlc.TaxSetting, error) {
+	settings, err := q.GetTaxSettings(ctx, tenantID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.TaxSetting{
+			TenantID:           tenantID,
+			DeliveryFeeVatRate: toPgNumeric(StandardRate),
+			ServiceFeeVatRate:  toPgNumeric(StandardRate),
+		}, nil
+	}
+	if err != nil {
+		return sqlc.TaxSetting{}, apperror.Internal("get tax settings", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings saves a tenant's tax settings.
+func (s *Service) UpdateSettings(ctx context.Context, tenantID uuid.UUID, req SettingsRequest, updatedBy uuid.UUID) (sqlc.TaxSetting, error) {
+	if err := req.validate(); err != nil {
+		return sqlc.TaxSetting{}, err
+	}
+	settings, err := s.q.UpsertTaxSettings(ctx, sqlc.UpsertTaxSettingsParams{
+		TenantID:           tenantID,
+		Bin:                toNullString(req.Bin),
+		RegisteredName:     toNullString(strings.TrimSpace(req.RegisteredName)),
+		RegisteredAddress:  toNullString(strings.TrimSpace(req.RegisteredAddress)),
+		DeliveryFeeVatRate: toPgNumeric(*req.DeliveryFeeVatRate),
+		ServiceFeeVatRate:  toPgNumeric(*req.ServiceFeeVatRate),
+		UpdatedBy:          pgtype.UUID{Bytes: updatedBy, Valid: true},
+	})
+	if err != nil {
+		return sqlc.TaxSetting{}, apperror.Internal("update tax settings", err)
+	}
+	return settings, nil
+}
+
+// RegistrationRequest sets a restaurant's VAT registration.
+type RegistrationRequest struct {
+	Bin               string `json:"bin"`
+	RegisteredName    string `json:"registered_name"`
+	RegisteredAddress string `json:"registered_address"`
+}
+
+func (r *RegistrationRequest) validate() error {
+	r.Bin = strings.TrimSpace(r.Bin)
+	r.RegisteredName = strings.TrimSpace(r.RegisteredName)
+	if !binPattern.MatchString(r.Bin) {
+		return apperror.BadRequest("bin must be 9 or 13 digits")
+	}
+	if r.RegisteredName == "" {
+		return apperror.BadRequest("registered_name is required")
+	}
+	return nil
+}
+
+// GetRegistration returns a restaurant's VAT registration.
+func (s *Service) GetRegistration(ctx context.Context, tenantID, restaurantID uuid.UUID) (sqlc.RestaurantVatRegistration, error) {
+	reg, err := s.q.GetRestaurantVatRegistration(ctx, sqlc.GetRestaurantVatRegistrationParams{RestaurantID: restaurantID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.RestaurantVatRegistration{}, apperror.NotFound("VAT registration")
+	}
+	if err != nil {
+		return sqlc.RestaurantVatRegistration{}, apperror.Internal("get VAT registration", err)
+	}
+	return reg, nil
+}
+
+// UpdateRegistration saves a restaurant's VAT registration. It applies to
+// tax invoices issued from then on.
+func (s *Service) UpdateRegistration(ctx context.Context, tenantID, restaurantID uuid.UUID, req RegistrationRequest, updatedBy uuid.UUID) (sqlc.RestaurantVatRegistration, error) {
+	if err := req.validate(); err != nil {
+		return sqlc.RestaurantVatRegistration{}, err
+	}
+	if _, err := s.getRestaurant(ctx, tenantID, restaurantID); err != nil {
+		return sqlc.RestaurantVatRegistration{}, err
+	}
+	reg, err := s.q.UpsertRestaurantVatRegistration(ctx, sqlc.UpsertRestaurantVatRegistrationParams{
+		RestaurantID:      restaurantID,
+		TenantID:          tenantID,
+		Bin:               req.Bin,
+		RegisteredName:    req.RegisteredName,
+		RegisteredAddress: toNullString(strings.TrimSpace(req.RegisteredAddress)),
+		UpdatedBy:         pgtype.UUID{Bytes: updatedBy, Valid: true},
+	})
+	if err != nil {
+		return sqlc.RestaurantVatRegistration{}, apperror.Internal("update VAT registration", err)
+	}
+	return reg, nil
+}
+
+func (s *Service) getRestaurant(ctx context.Context, tenantID, restaurantID uuid.UUID) (sqlc.Restaurant, error) {
+	rest, err := s.q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: restaurantID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Restaurant{}, apperror.NotFound("restaurant")
+	}
+	if err != nil {
+		return sqlc.Restaurant{}, apperror.Internal("get restaurant", err)
+	}
+	return rest, nil
+}
+
+// ---------- Tax invoices (Mushak-6.3) ----------
+
+// seller is who a tax invoice is issued by.
+type seller struct {
+	ID      uuid.UUID
+	Name    string
+	Bin     string
+	Address string
+}
+
+// IssueForOrder issues the Mushak-6.3 tax invoices of a delivered order: one
+// per restaurant for its food and one by the tenant for delivery and service
+// fees. It is idempotent: an order that already has tax invoices returns
+// them. Serials are allocated per seller in the same transaction.
+func (s *Service) IssueForOrder(ctx context.Context, tenantID, orderID uuid.UUID) ([]sqlc.TaxInvoice, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	q := s.q.WithTx(tx)
+
+	order, err := q.GetOrderForUpdate(ctx, sqlc.GetOrderForUpdateParams{ID: orderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("order")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get order", err)
+	}
+	if order.Status != sqlc.OrderStatusDelivered {
+		return nil, apperror.BadRequest("tax invoices are issued once the order is delivered")
+	}
+
+	existing, err := q.ListTaxInvoicesByOrder(ctx, sqlc.ListTaxInvoicesByOrderParams{OrderID: orderID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list tax invoices", err)
+	}
+	if len(existing) > 0 {
+		return existing, nil
+	}
+
+	items, err := q.GetOrderItemsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, apperror.Internal("get order items", err)
+	}
+	pickups, err := q.GetOrderPickupsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, apperror.Internal("get order pickups", err)
+	}
+	restaurants := make(map[uuid.UUID]sqlc.Restaurant, len(pickups))
+	for _, p := range pickups {
+		rest, err := q.GetRestaurantByID(ctx, sqlc.GetRestaurantByIDParams{ID: p.RestaurantID, TenantID: tenantID})
+		if err != nil {
+			return nil, apperror.Internal("get restaurant", err)
+		}
+		restaurants[p.RestaurantID] = rest
+	}
+	settings, err := getSettings(ctx, q, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	supplied := order.UpdatedAt
+	if order.DeliveredAt.Valid {
+		supplied = order.DeliveredAt.Time
+	}
+	buyerAddress := deliveryAddress(order.DeliveryAddress, order.DeliveryArea)
+
+	var invoices []sqlc.TaxInvoice
+	for _, d := range draftInvoices(order, items, pickups, restaurants, settings) {
+		var sel seller
+		if d.RestaurantID != nil {
+			sel, err = restaurantSeller(ctx, q, restaurants[*d.RestaurantID])
+		} else {
+			sel, err = tenantSeller(ctx, q, settings)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		serial, err := q.NextTaxInvoiceSerial(ctx, sqlc.NextTaxInvoiceSerialParams{TenantID: tenantID, SellerID: sel.ID})
+		if err != nil {
+			return nil, apperror.Internal("allocate tax invoice number", err)
+		}
+		lines, err := json.Marshal(d.Lines)
+		if err != nil {
+			return nil, apperror.Internal("encode tax invoice lines", err)
+		}
+		taxable, vat, total := d.totals()
+		restaurantID := pgtype.UUID{}
+		if d.RestaurantID != nil {
+			restaurantID = pgtype.UUID{Bytes: *d.RestaurantID, Valid: true}
+		}
+		inv, err := q.CreateTaxInvoice(ctx, sqlc.CreateTaxInvoiceParams{
+			TenantID:      tenantID,
+			OrderID:       orderID,
+			RestaurantID:  restaurantID,
+			InvoiceNumber: invoiceNumber(sel.ID, serial),
+			SellerName:    sel.Name,
+			SellerBin:     toNullString(sel.Bin),
+			SellerAddress: toNullString(sel.Address),
+			BuyerName:     order.DeliveryRecipientName,
+			BuyerPhone:    toNullString(order.DeliveryRecipientPhone),
+			BuyerAddress:  toNullString(buyerAddress),
+			TaxableValue:  toPgNumeric(taxable),
+			VatAmount:     toPgNumeric(vat),
+			TotalAmount:   toPgNumeric(total),
+			Lines:         lines,
+			SuppliedAt:    supplied,
+		})
+		if err != nil {
+			return nil, apperror.Internal("create tax invoice", err)
+		}
+		invoices = append(invoices, inv)
+	}
+	if err := q.ClearTaxInvoiceFailure(ctx, orderID); err != nil {
+		return nil, apperror.Internal("clear tax invoice failure", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return invoices, nil
+}
+
+// invoiceNumber formats a seller's tax invoice serial, e.g. M63-1A2B3C4D-000042.
+func invoiceNumber(sellerID uuid.UUID, serial int32) string {
+	return fmt.Sprintf("M63-%s-%06d", strings.ToUpper(sellerID.String()[:8]), serial)
+}
+
+// restaurantSeller identifies a restaurant by its VAT registration, falling
+// back to its trading name and address when it has not registered a BIN.
+func restaurantSeller(ctx context.Context, q *sqlc.Queries, rest sqlc.Restaurant) (seller, error) {
+	sel := seller{ID: rest.ID, Name: rest.Name, Address: restaurantAddress(rest)}
+	reg, err := q.GetRestaurantVatRegistration(ctx, sqlc.GetRestaurantVatRegistrationParams{RestaurantID: rest.ID, TenantID: rest.TenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return sel, nil
+	}
+	if err != nil {
+		return seller{}, apperror.Internal("get VAT registration", err)
+	}
+	sel.Name, sel.Bin = reg.RegisteredName, reg.Bin
+	if reg.RegisteredAddress.Valid && reg.RegisteredAddress.String != "" {
+		sel.Address = reg.RegisteredAddress.String
+	}
+	return sel, nil
+}
+
+// tenantSeller identifies the tenant for its fee invoices.
+func tenantSeller(ctx context.Context, q *sqlc.Queries, settings sqlc.TaxSetting) (seller, error) {
+	t, err := q.GetTenantByID(ctx, settings.TenantID)
+	if err != nil {
+		return seller{}, apperror.Internal("get tenant", err)
+	}
+	sel := seller{ID: t.ID, Name: t.Name, Bin: settings.Bin.String, Address: settings.RegisteredAddress.String}
+	if settings.RegisteredName.Valid && settings.RegisteredName.String != "" {
+		sel.Name = settings.RegisteredName.String
+	}
+	return sel, nil
+}
+
+// OrderTaxInvoices is an order with the tax invoices issued for it.
+type OrderTaxInvoices struct {
+	Order    sqlc.Order        `json:"-"`
+	Invoices []sqlc.TaxInvoice `json:"invoices"`
+}
+
+// Restrict drops the invoices of restaurants not allowed, and the tenant's
+// fee invoice, for staff who only see their own restaurants' part of an order.
+func (o *OrderTaxInvoices) Restrict(allowed func(uuid.UUID) bool) {
+	var invoices []sqlc.TaxInvoice
+	for _, inv := range o.Invoices {
+		if inv.RestaurantID.Valid && allowed(inv.RestaurantID.Bytes) {
+			invoices = append(invoices, inv)
+		}
+	}
+	o.Invoices = invoices
+}
+
+// OrderInvoices returns the tax invoices of an order, issuing them first if
+// the order was delivered before the worker got to it.
+func (s *Service) OrderInvoices(ctx context.Context, tenantID, orderID uuid.UUID) (*OrderTaxInvoices, error) {
+	order, err := s.q.GetOrderByID(ctx, sqlc.GetOrderByIDParams{ID: orderID, TenantID: tenantID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.NotFound("order")
+	}
+	if err != nil {
+		return nil, apperror.Internal("get order", err)
+	}
+	invoices, err := s.q.ListTaxInvoicesByOrder(ctx, sqlc.ListTaxInvoicesByOrderParams{OrderID: orderID, TenantID: tenantID})
+	if err != nil {
+		return nil, apperror.Internal("list tax invoices", err)
+	}
+	if len(invoices) == 0 {
+		if invoices, err = s.IssueForOrder(ctx, tenantID, orderID); err != nil {
+			return nil, err
+		}
+	}
+	return &OrderTaxInvoices{Order: order, Invoices: invoices}, nil
+}
+
+// IssuePendingInvoices issues tax invoices for recently delivered orders
+// that have none yet, across tenants. It runs as a background job. An order
+// that fails is retried with backoff; the job gives up on it after
+// issueMaxAttempts attempts, or at once if the order cannot be invoiced as
+// it stands, e.g. it is no longer delivered.
+func (s *Service) IssuePendingInvoices(ctx context.Context) error {
+	orders, err := s.q.ListOrdersAwaitingTaxInvoices(ctx, sqlc.ListOrdersAwaitingTaxInvoicesParams{
+		DeliveredSince: time.Now().Add(-issueLookback),
+		Limit:          issueBatchSize,
+	})
+	if err != nil {
+		return err
+	}
+	issued := 0
+	for _, o := range orders {
+		invoices, err := s.IssueForOrder(ctx, o.TenantID, o.ID)
+		if err != nil {
+			failure, ferr := s.q.RecordTaxInvoiceFailure(ctx, sqlc.RecordTaxInvoiceFailureParams{
+				OrderID:     o.ID,
+				TenantID:    o.TenantID,
+				Error:       err.Error(),
+				Permanent:   isValidationError(err),
+				MaxAttempts: issueMaxAttempts,
+			})
+			if ferr != nil {
+				return ferr
+			}
+			log.Error().Err(err).
+				Str("tenant_id", o.TenantID.String()).
+				Str("order_id", o.ID.String()).
+				Int32("attempts", failure.Attempts).
+				Bool("permanent", failure.Permanent).
+				Msg("tax invoice issue failed")
+			continue
+		}
+		issued += len(invoices)
+	}
+	if issued > 0 {
+		log.Info().Int("orders", len(orders)).Int("invoices", issued).Msg("tax invoices issued")
+	}
+	return nil
+}
+
+// ---------- Registers and returns (Mushak-9.1) ----------
+
+// Period is a month of tax invoices for one seller.
+type Period struct {
+	Month string
+	Start time.Time
+	End   time.Time
+}
+
+// ParsePeriod resolves a YYYY-MM month in the tenant's timezone; an empty
+// month is the previous calendar month.
+func ParsePeriod(t *sqlc.Tenant, month string) (Period, error) {
+	m, start, end, err := monthWindow(month, documents.Location(t), time.Now())
+	if err != nil {
+		return Period{}, apperror.BadRequest(err.Error())
+	}
+	return Period{Month: m, Start: start, End: end}, nil
+}
+
+// RestaurantInvoices lists a restaurant's tax invoices for a period.
+func (s *Service) RestaurantInvoices(ctx context.Context, tenantID, restaurantID uuid.UUID, p Period) ([]sqlc.TaxInvoice, error) {
+	if _, err := s.getRestaurant(ctx, tenantID, restaurantID); err != nil {
+		return nil, err
+	}
+	invoices, err := s.q.ListRestaurantTaxInvoices(ctx, sqlc.ListRestaurantTaxInvoicesParams{
+		TenantID:     tenantID,
+		RestaurantID: pgtype.UUID{Bytes: restaurantID, Valid: true},
+		PeriodStart:  p.Start,
+		PeriodEnd:    p.End,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list tax invoices", err)
+	}
+	return invoices, nil
+}
+
+// FeeInvoices lists the tenant's fee tax invoices for a period.
+func (s *Service) FeeInvoices(ctx context.Context, tenantID uuid.UUID, p Period) ([]sqlc.TaxInvoice, error) {
+	invoices, err := s.q.ListFeeTaxInvoices(ctx, sqlc.ListFeeTaxInvoicesParams{TenantID: tenantID, PeriodStart: p.Start, PeriodEnd: p.End})
+	if err != nil {
+		return nil, apperror.Internal("list tax invoices", err)
+	}
+	return invoices, nil
+}
+
+// RestaurantReturn builds a restaurant's monthly VAT return from the tax
+// invoices it issued in the period.
+func (s *Service) RestaurantReturn(ctx context.Context, tenantID, restaurantID uuid.UUID, p Period) (*Return, error) {
+	rest, err := s.getRestaurant(ctx, tenantID, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	invoices, err := s.RestaurantInvoices(ctx, tenantID, restaurantID, p)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := restaurantSeller(ctx, s.q, rest)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := newReturn(sel, p, invoices)
+	if err != nil {
+		return nil, err
+	}
+	ret.Uninvoiced, err = s.uninvoicedOrders(ctx, tenantID, pgtype.UUID{Bytes: restaurantID, Valid: true}, p)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// FeeReturn builds the tenant's monthly VAT return for delivery and service
+// fees.
+func (s *Service) FeeReturn(ctx context.Context, tenantID uuid.UUID, p Period) (*Return, error) {
+	invoices, err := s.FeeInvoices(ctx, tenantID, p)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.GetSettings(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	sel, err := tenantSeller(ctx, s.q, settings)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := newReturn(sel, p, invoices)
+	if err != nil {
+		return nil, err
+	}
+	ret.Uninvoiced, err = s.uninvoicedOrders(ctx, tenantID, pgtype.UUID{}, p)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// uninvoicedOrders lists the period's delivered orders whose tax invoices
+// could not be issued, so the return shows what it is missing. A valid
+// restaurantID limits them to that restaurant's orders.
+func (s *Service) uninvoicedOrders(ctx context.Context, tenantID uuid.UUID, restaurantID pgtype.UUID, p Period) ([]UninvoicedOrder, error) {
+	rows, err := s.q.ListTaxInvoiceFailuresForPeriod(ctx, sqlc.ListTaxInvoiceFailuresForPeriodParams{
+		TenantID:     tenantID,
+		PeriodStart:  p.Start,
+		PeriodEnd:    p.End,
+		RestaurantID: restaurantID,
+	})
+	if err != nil {
+		return nil, apperror.Internal("list tax invoice failures", err)
+	}
+	orders := make([]UninvoicedOrder, 0, len(rows))
+	for _, r := range rows {
+		orders = append(orders, UninvoicedOrder{
+			OrderID:     r.OrderID,
+			OrderNumber: r.OrderNumber,
+			DeliveredAt: r.DeliveredAt.Time,
+			Error:       r.Error,
+			Attempts:    r.Attempts,
+			Permanent:   r.Permanent,
+		})
+	}
+	return orders, nil
+}
+
+// isValidationError reports whether err is a client error that retrying
+// will not fix.
+func isValidationError(err error) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.HTTPStatus() < http.StatusInternalServerError
+}
+
+func newReturn(sel seller, p Period, invoices []sqlc.TaxInvoice) (*Return, error) {
+	ret := &Return{
+		Period:        p.Month,
+		PeriodStart:   p.Start,
+		PeriodEnd:     p.End,
+		SellerName:    sel.Name,
+		SellerBin:     sel.Bin,
+		SellerAddress: sel.Address,
+	}
+	if err := buildReturn(ret, invoices); err != nil {
+		return nil, apperror.Internal("build VAT return", err)
+	}
+	return ret, nil
+}
+
+// CreateAuditLog creates an audit log entry for a partner user's change.
+func (s *Service) CreateAuditLog(ctx context.Context, tenantID, actorID uuid.UUID, action, resourceType string, resourceID uuid.UUID, reason string) {
+	changes, _ := json.Marshal(map[string]interface{}{})
+	s.q.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		TenantID:     pgtype.UUID{Bytes: tenantID, Valid: true},
+		ActorID:      pgtype.UUID{Bytes: actorID, Valid: true},
+		ActorType:    sqlc.ActorTypeRestaurant,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   pgtype.UUID{Bytes: resourceID, Valid: true},
+		Changes:      changes,
+		Reason:       toNullString(reason),
+	})
+}
+
+// ---------- Helpers ----------
+
+// deliveryAddress formats the address snapshot stored on an order.
+func deliveryAddress(raw json.RawMessage, area string) string {
+	var a struct {
+		AddressLine1 string `json:"address_line1"`
+		AddressLine2 string `json:"address_line2"`
+		Area         string `json:"area"`
+		City         string `json:"city"`
+	}
+	_ = json.Unmarshal(raw, &a)
+	if a.Area == "" {
+		a.Area = area
+	}
+	var parts []string
+	for _, p := range []string{a.AddressLine1, a.AddressLine2, a.Area, a.City} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// restaurantAddress joins the non-empty parts of a restaurant's address.
+func restaurantAddress(r sqlc.Restaurant) string {
+	var parts []string
+	for _, p := range []string{r.AddressLine1.String, r.AddressLine2.String, r.Area.String, r.City} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func toNullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
@@ -0,0 +1,303 @@
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/finance"
+	"github.com/shopspring/decimal"
+)
+
+// StandardRate is the standard VAT rate under the VAT and Supplementary Duty
+// Act 2012, used for fees when a tenant has no tax settings.
+var StandardRate = decimal.NewFromInt(15)
+
+var hundred = decimal.NewFromInt(100)
+
+// Breakdown splits a price into its taxable value and VAT.
+type Breakdown struct {
+	Amount       decimal.Decimal `json:"amount"`
+	VatRate      decimal.Decimal `json:"vat_rate"`
+	VatInclusive bool            `json:"vat_inclusive"`
+	TaxableValue decimal.Decimal `json:"taxable_value"`
+	VatAmount    decimal.Decimal `json:"vat_amount"`
+	Total        decimal.Decimal `json:"total"`
+}
+
+// Split decomposes amount at rate percent. A VAT-inclusive amount already
+// contains the VAT (VAT = amount × rate / (100 + rate)); an exclusive amount
+// is the taxable value and the VAT is charged on top. VAT is rounded to the
+// poisha.
+func Split(amount, rate decimal.Decimal, inclusive bool) Breakdown {
+	b := Breakdown{Amount: amount, VatRate: rate, VatInclusive: inclusive}
+	if inclusive {
+		b.VatAmount = amount.Mul(rate).Div(hundred.Add(rate)).Round(2)
+		b.TaxableValue = amount.Sub(b.VatAmount)
+		b.Total = amount
+		return b
+	}
+	b.TaxableValue = amount
+	b.VatAmount = amount.Mul(rate).Div(hundred).Round(2)
+	b.Total = amount.Add(b.VatAmount)
+	return b
+}
+
+// Line is one supply on a tax invoice, stored in tax_invoices.lines.
+type Line struct {
+	Description  string          `json:"description"`
+	Quantity     int32           `json:"quantity"`
+	VatRate      decimal.Decimal `json:"vat_rate"`
+	VatInclusive bool            `json:"vat_inclusive"`
+	TaxableValue decimal.Decimal `json:"taxable_value"`
+	VatAmount    decimal.Decimal `json:"vat_amount"`
+	Total        decimal.Decimal `json:"total"`
+}
+
+// draft is a tax invoice before it is numbered. A nil RestaurantID is the
+// tenant's own invoice for delivery and service fees.
+type draft struct {
+	RestaurantID *uuid.UUID
+	Lines        []Line
+}
+
+func (d draft) totals() (taxable, vat, total decimal.Decimal) {
+	for _, l := range d.Lines {
+		taxable = taxable.Add(l.TaxableValue)
+		vat = vat.Add(l.VatAmount)
+		total = total.Add(l.Total)
+	}
+	return taxable, vat, total
+}
+
+// itemLines builds a restaurant's invoice lines. The taxable value is the
+// item subtotal after item discounts and the promo discount the restaurant
+// funds, as settled on its invoice; platform promos are a discount funded
+// outside the restaurant's supply and do not reduce it. Orders record the
+// VAT charged per item; when a VAT-inclusive restaurant's item carries none,
+// the VAT is decomposed out of the price instead.
+func itemLines(items []sqlc.OrderItem, rest sqlc.Restaurant, promoSnapshot []byte) []Line {
+	rate := numericToDecimal(rest.VatRate)
+	promo := finance.VendorPromoDiscountByProduct(promoSnapshot, rest.ID)
+	var lines []Line
+	for _, it := range items {
+		if it.RestaurantID != rest.ID {
+			continue
+		}
+		net := numericToDecimal(it.ItemSubtotal).Sub(numericToDecimal(it.ItemDiscount))
+		// A product on several lines takes its discount line by line.
+		if d := decimal.Min(promo[it.ProductID], net); d.IsPositive() {
+			net = net.Sub(d)
+			promo[it.ProductID] = promo[it.ProductID].Sub(d)
+		}
+		vat := numericToDecimal(it.ItemVat)
+		line := Line{Description: it.ProductName, Quantity: it.Quantity, VatRate: rate, VatInclusive: rest.IsVatInclusive}
+		if rest.IsVatInclusive && vat.IsZero() {
+			b := Split(net, rate, true)
+			line.TaxableValue, line.VatAmount, line.Total = b.TaxableValue, b.VatAmount, b.Total
+		} else {
+			line.TaxableValue, line.VatAmount, line.Total = net, vat, net.Add(vat)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// feeLines builds the tenant's invoice lines for the delivery charge and
+// service fee, which are VAT-inclusive prices. Rider tips pass through to the
+// rider and are not a taxable supply.
+func feeLines(o sqlc.Order, deliveryRate, serviceRate decimal.Decimal) []Line {
+	var lines []Line
+	for _, fee := range []struct {
+		desc   string
+		amount decimal.Decimal
+		rate   decimal.Decimal
+	}{
+		{"Delivery charge", numericToDecimal(o.DeliveryCharge), deliveryRate},
+		{"Service fee", numericToDecimal(o.ServiceFee), serviceRate},
+	} {
+		if !fee.amount.IsPositive() {
+			continue
+		}
+		b := Split(fee.amount, fee.rate, true)
+		lines = append(lines, Line{
+			Description:  fee.desc,
+			Quantity:     1,
+			VatRate:      fee.rate,
+			VatInclusive: true,
+			TaxableValue: b.TaxableValue,
+			VatAmount:    b.VatAmount,
+			Total:        b.Total,
+		})
+	}
+	return lines
+}
+
+// draftInvoices splits an order into one tax invoice per restaurant, in
+// pickup order, and one for the tenant's fees when any were charged.
+func draftInvoices(o sqlc.Order, items []sqlc.OrderItem, pickups []sqlc.OrderPickup, restaurants map[uuid.UUID]sqlc.Restaurant, settings sqlc.TaxSetting) []draft {
+	var drafts []draft
+	for _, p := range pickups {
+		rest, ok := restaurants[p.RestaurantID]
+		if !ok {
+			continue
+		}
+		if lines := itemLines(items, rest, o.PromoSnapshot); len(lines) > 0 {
+			id := p.RestaurantID
+			drafts = append(drafts, draft{RestaurantID: &id, Lines: lines})
+		}
+	}
+	if lines := feeLines(o, numericToDecimal(settings.DeliveryFeeVatRate), numericToDecimal(settings.ServiceFeeVatRate)); len(lines) > 0 {
+		drafts = append(drafts, draft{Lines: lines})
+	}
+	return drafts
+}
+
+// Mushak-9.1 supply categories (Part 3 of the return).
+const (
+	CategoryExempt   = "exempt"
+	CategoryStandard = "standard"
+	CategoryReduced  = "reduced"
+)
+
+// ReturnLine is one row of the supplies section of a monthly return.
+type ReturnLine struct {
+	Note         int             `json:"note"`
+	Category     string          `json:"category"`
+	VatRate      decimal.Decimal `json:"vat_rate"`
+	TaxableValue decimal.Decimal `json:"taxable_value"`
+	VatAmount    decimal.Decimal `json:"vat_amount"`
+}
+
+// Return is a Mushak-9.1 style monthly VAT return for one seller: the
+// restaurant for its food supplies, or the tenant for delivery and service
+// fees.
+type Return struct {
+	Period        string          `json:"period"`
+	PeriodStart   time.Time       `json:"period_start"`
+	PeriodEnd     time.Time       `json:"period_end"`
+	SellerName    string          `json:"seller_name"`
+	SellerBin     string          `json:"seller_bin,omitempty"`
+	SellerAddress string          `json:"seller_address,omitempty"`
+	Invoices      int             `json:"invoices"`
+	Supplies      []ReturnLine    `json:"supplies"`
+	TaxableValue  decimal.Decimal `json:"taxable_value"`
+	OutputVat     decimal.Decimal `json:"output_vat"`
+	InputVat      decimal.Decimal `json:"input_vat"`
+	NetPayable    decimal.Decimal `json:"net_payable"`
+
+	// Uninvoiced are delivered orders of the period missing from the
+	// return because their tax invoices could not be issued.
+	Uninvoiced []UninvoicedOrder `json:"uninvoiced_orders"`
+}
+
+// UninvoicedOrder is a delivered order the tax invoice job failed on.
+// Permanent failures are no longer retried and need issuing by hand.
+type UninvoicedOrder struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	OrderNumber string    `json:"order_number"`
+	DeliveredAt time.Time `json:"delivered_at"`
+	Error       string    `json:"error"`
+	Attempts    int32     `json:"attempts"`
+	Permanent   bool      `json:"permanent"`
+}
+
+// categorize places a VAT rate in its Mushak-9.1 supply note.
+func categorize(rate decimal.Decimal) (note int, category string) {
+	switch {
+	case rate.IsZero():
+		return 3, CategoryExempt
+	case rate.Equal(StandardRate):
+		return 4, CategoryStandard
+	default:
+		return 7, CategoryReduced
+	}
+}
+
+// buildReturn totals the lines of a period's tax invoices by VAT rate.
+// Purchases are not recorded on the platform, so input VAT is zero and the
+// net payable is the output VAT; sellers claim input credit when filing.
+func buildReturn(ret *Return, invoices []sqlc.TaxInvoice) error {
+	byRate := map[string]*ReturnLine{}
+	for _, inv := range invoices {
+		lines, err := DecodeLines(inv)
+		if err != nil {
+			return err
+		}
+		for _, l := range lines {
+			key := l.VatRate.StringFixed(2)
+			rl, ok := byRate[key]
+			if !ok {
+				note, category := categorize(l.VatRate)
+				rl = &ReturnLine{Note: note, Category: category, VatRate: l.VatRate}
+				byRate[key] = rl
+			}
+			rl.TaxableValue = rl.TaxableValue.Add(l.TaxableValue)
+			rl.VatAmount = rl.VatAmount.Add(l.VatAmount)
+		}
+	}
+
+	ret.Invoices = len(invoices)
+	ret.Supplies = make([]ReturnLine, 0, len(byRate))
+	for _, rl := range byRate {
+		ret.Supplies = append(ret.Supplies, *rl)
+		ret.TaxableValue = ret.TaxableValue.Add(rl.TaxableValue)
+		ret.OutputVat = ret.OutputVat.Add(rl.VatAmount)
+	}
+	sort.Slice(ret.Supplies, func(i, j int) bool {
+		a, b := ret.Supplies[i], ret.Supplies[j]
+		if a.Note != b.Note {
+			return a.Note < b.Note
+		}
+		return a.VatRate.LessThan(b.VatRate)
+	})
+	ret.InputVat = decimal.Zero
+	ret.NetPayable = ret.OutputVat.Sub(ret.InputVat)
+	return nil
+}
+
+// DecodeLines reads the lines stored on a tax invoice.
+func DecodeLines(inv sqlc.TaxInvoice) ([]Line, error) {
+	var lines []Line
+	if len(inv.Lines) == 0 {
+		return lines, nil
+	}
+	if err := json.Unmarshal(inv.Lines, &lines); err != nil {
+		return nil, fmt.Errorf("decode lines of tax invoice %s: %w", inv.InvoiceNumber, err)
+	}
+	return lines, nil
+}
+
+// monthWindow parses a YYYY-MM month in loc into [start of month, start of
+// next month). An empty month is the previous calendar month.
+func monthWindow(month string, loc *time.Location, now time.Time) (string, time.Time, time.Time, error) {
+	var start time.Time
+	if month == "" {
+		n := now.In(loc)
+		start = time.Date(n.Year(), n.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -1, 0)
+	} else {
+		t, err := time.ParseInLocation("2006-01", month, loc)
+		if err != nil {
+			return "", time.Time{}, time.Time{}, fmt.Errorf("month must be YYYY-MM")
+		}
+		start = t
+	}
+	return start.Format("2006-01"), start, start.AddDate(0, 1, 0), nil
+}
+
+func numericToDecimal(n pgtype.Numeric) decimal.Decimal {
+	if !n.Valid || n.Int == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(n.Int, n.Exp)
+}
+
+func toPgNumeric(d decimal.Decimal) pgtype.Numeric {
+	n := pgtype.Numeric{}
+	_ = n.Scan(d.String())
+	return n
+}
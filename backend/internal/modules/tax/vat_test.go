@@ -0,0 +1,188 @@
+package tax
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		name         string
+		amount, rate string
+		inclusive    bool
+		taxable, vat string
+		total        string
+	}{
+		{"inclusive standard", "115", "15", true, "100", "15", "115"},
+		{"inclusive rounds to poisha", "100", "15", true, "86.96", "13.04", "100"},
+		{"inclusive reduced", "210", "5", true, "200", "10", "210"},
+		{"exclusive standard", "200", "15", false, "200", "30", "230"},
+		{"exclusive rounds to poisha", "33.33", "7.5", false, "33.33", "2.5", "35.83"},
+		{"zero rated", "80", "0", true, "80", "0", "80"},
+	}
+	for _, c := range cases {
+		b := Split(dec(c.amount), dec(c.rate), c.inclusive)
+		if !b.TaxableValue.Equal(dec(c.taxable)) || !b.VatAmount.Equal(dec(c.vat)) || !b.Total.Equal(dec(c.total)) {
+			t.Errorf("%s: got taxable %s vat %s total %s, want %s %s %s", c.name, b.TaxableValue, b.VatAmount, b.Total, c.taxable, c.vat, c.total)
+		}
+	}
+}
+
+func TestDraftInvoices(t *testing.T) {
+	exclusive := sqlc.Restaurant{ID: uuid.New(), Name: "Kacchi House", VatRate: toPgNumeric(dec("15"))}
+	inclusive := sqlc.Restaurant{ID: uuid.New(), Name: "Cha Corner", VatRate: toPgNumeric(dec("5")), IsVatInclusive: true}
+	items := []sqlc.OrderItem{
+		{RestaurantID: exclusive.ID, ProductName: "Kacchi", Quantity: 2, ItemSubtotal: toPgNumeric(dec("500")), ItemDiscount: toPgNumeric(dec("50")), ItemVat: toPgNumeric(dec("67.50"))},
+		{RestaurantID: inclusive.ID, ProductName: "Tea", Quantity: 3, ItemSubtotal: toPgNumeric(dec("105")), ItemDiscount: toPgNumeric(dec("0")), ItemVat: toPgNumeric(dec("0"))},
+	}
+	pickups := []sqlc.OrderPickup{{RestaurantID: exclusive.ID}, {RestaurantID: inclusive.ID}}
+	restaurants := map[uuid.UUID]sqlc.Restaurant{exclusive.ID: exclusive, inclusive.ID: inclusive}
+	order := sqlc.Order{DeliveryCharge: toPgNumeric(dec("60")), ServiceFee: toPgNumeric(dec("0")), RiderTip: toPgNumeric(dec("20"))}
+	settings := sqlc.TaxSetting{DeliveryFeeVatRate: toPgNumeric(dec("15")), ServiceFeeVatRate: toPgNumeric(dec("15"))}
+
+	drafts := draftInvoices(order, items, pickups, restaurants, settings)
+	if len(drafts) != 3 {
+		t.Fatalf("got %d drafts, want 3", len(drafts))
+	}
+
+	if *drafts[0].RestaurantID != exclusive.ID {
+		t.Errorf("first draft is not the first pickup's restaurant")
+	}
+	taxable, vat, total := drafts[0].totals()
+	if !taxable.Equal(dec("450")) || !vat.Equal(dec("67.5")) || !total.Equal(dec("517.5")) {
+		t.Errorf("exclusive restaurant: got %s %s %s", taxable, vat, total)
+	}
+
+	taxable, vat, total = drafts[1].totals()
+	if !taxable.Equal(dec("100")) || !vat.Equal(dec("5")) || !total.Equal(dec("105")) {
+		t.Errorf("inclusive restaurant: got %s %s %s", taxable, vat, total)
+	}
+
+	fees := drafts[2]
+	if fees.RestaurantID != nil {
+		t.Errorf("fee draft has a restaurant")
+	}
+	if len(fees.Lines) != 1 || fees.Lines[0].Description != "Delivery charge" {
+		t.Fatalf("fee lines = %+v, want only the delivery charge", fees.Lines)
+	}
+	taxable, vat, total = fees.totals()
+	if !taxable.Equal(dec("52.17")) || !vat.Equal(dec("7.83")) || !total.Equal(dec("60")) {
+		t.Errorf("fees: got %s %s %s", taxable, vat, total)
+	}
+}
+
+func TestDraftInvoicesWithoutFees(t *testing.T) {
+	rest := sqlc.Restaurant{ID: uuid.New(), VatRate: toPgNumeric(dec("15"))}
+	items := []sqlc.OrderItem{{RestaurantID: rest.ID, ProductName: "Burger", Quantity: 1, ItemSubtotal: toPgNumeric(dec("300")), ItemVat: toPgNumeric(dec("45"))}}
+	drafts := draftInvoices(sqlc.Order{}, items, []sqlc.OrderPickup{{RestaurantID: rest.ID}}, map[uuid.UUID]sqlc.Restaurant{rest.ID: rest}, sqlc.TaxSetting{})
+	if len(drafts) != 1 || drafts[0].RestaurantID == nil {
+		t.Fatalf("got %d drafts, want only the restaurant's", len(drafts))
+	}
+}
+
+func TestItemLinesRestaurantPromo(t *testing.T) {
+	rest := sqlc.Restaurant{ID: uuid.New(), VatRate: toPgNumeric(dec("5")), IsVatInclusive: true}
+	burger, fries := uuid.New(), uuid.New()
+	items := []sqlc.OrderItem{
+		{RestaurantID: rest.ID, ProductID: burger, ProductName: "Burger", Quantity: 1, ItemSubtotal: toPgNumeric(dec("210")), ItemVat: toPgNumeric(dec("0"))},
+		{RestaurantID: rest.ID, ProductID: fries, ProductName: "Fries", Quantity: 1, ItemSubtotal: toPgNumeric(dec("105")), ItemVat: toPgNumeric(dec("0"))},
+	}
+	snapshot := []byte(`{"promos":[
+		{"funded_by":"restaurant","allocations":[{"product_id":"` + burger.String() + `","restaurant_id":"` + rest.ID.String() + `","discount":"42"}]},
+		{"funded_by":"platform","allocations":[{"product_id":"` + fries.String() + `","restaurant_id":"` + rest.ID.String() + `","discount":"21"}]}
+	]}`)
+
+	lines := itemLines(items, rest, snapshot)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if !lines[0].Total.Equal(dec("168")) || !lines[0].TaxableValue.Equal(dec("160")) || !lines[0].VatAmount.Equal(dec("8")) {
+		t.Errorf("restaurant-funded promo: got %s %s %s, want 160 8 168", lines[0].TaxableValue, lines[0].VatAmount, lines[0].Total)
+	}
+	if !lines[1].Total.Equal(dec("105")) {
+		t.Errorf("platform promo reduced the supply to %s", lines[1].Total)
+	}
+}
+
+func taxInvoice(t *testing.T, number string, lines ...Line) sqlc.TaxInvoice {
+	raw, err := json.Marshal(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sqlc.TaxInvoice{InvoiceNumber: number, Lines: raw}
+}
+
+func TestBuildReturn(t *testing.T) {
+	invoices := []sqlc.TaxInvoice{
+		taxInvoice(t, "M63-1",
+			Line{VatRate: dec("15"), TaxableValue: dec("100"), VatAmount: dec("15")},
+			Line{VatRate: dec("5"), TaxableValue: dec("200"), VatAmount: dec("10")},
+		),
+		taxInvoice(t, "M63-2",
+			Line{VatRate: dec("15.00"), TaxableValue: dec("50"), VatAmount: dec("7.5")},
+			Line{VatRate: dec("0"), TaxableValue: dec("40"), VatAmount: dec("0")},
+		),
+	}
+	ret := &Return{}
+	if err := buildReturn(ret, invoices); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		note         int
+		category     string
+		taxable, vat string
+	}{
+		{3, CategoryExempt, "40", "0"},
+		{4, CategoryStandard, "150", "22.5"},
+		{7, CategoryReduced, "200", "10"},
+	}
+	if len(ret.Supplies) != len(want) {
+		t.Fatalf("got %d supply lines, want %d", len(ret.Supplies), len(want))
+	}
+	for i, w := range want {
+		s := ret.Supplies[i]
+		if s.Note != w.note || s.Category != w.category || !s.TaxableValue.Equal(dec(w.taxable)) || !s.VatAmount.Equal(dec(w.vat)) {
+			t.Errorf("supply %d = %+v, want %+v", i, s, w)
+		}
+	}
+	if ret.Invoices != 2 || !ret.TaxableValue.Equal(dec("390")) || !ret.OutputVat.Equal(dec("32.5")) || !ret.NetPayable.Equal(dec("32.5")) {
+		t.Errorf("totals: invoices %d taxable %s output %s net %s", ret.Invoices, ret.TaxableValue, ret.OutputVat, ret.NetPayable)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReturnCSV(&buf, ret); err != nil {
+		t.Fatal(err)
+	}
+	if rows := strings.Count(buf.String(), "\n"); rows != 1+3+3 {
+		t.Errorf("CSV has %d rows, want 7", rows)
+	}
+}
+
+func TestMonthWindow(t *testing.T) {
+	dhaka := time.FixedZone("BDT", 6*3600)
+	now := time.Date(2026, 3, 31, 20, 0, 0, 0, time.UTC) // 1 April in Dhaka
+
+	month, start, end, err := monthWindow("", dhaka, now)
+	if err != nil || month != "2026-03" || !start.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, dhaka)) || !end.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, dhaka)) {
+		t.Errorf("default month = %s [%s, %s) %v", month, start, end, err)
+	}
+	month, _, end, err = monthWindow("2026-12", dhaka, now)
+	if err != nil || month != "2026-12" || !end.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, dhaka)) {
+		t.Errorf("December = %s ending %s %v", month, end, err)
+	}
+	if _, _, _, err := monthWindow("2026-13", dhaka, now); err == nil {
+		t.Errorf("invalid month accepted")
+	}
+}
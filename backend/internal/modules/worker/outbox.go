@@ -43,6 +43,25 @@ func (w *Worker) ProcessOutboxEvents(ctx context.Context) error {
 			}
 		}
 
+		// Dispatch to in-process handlers (notifications, etc.)
+		if h, ok := w.handlers[event.EventType]; ok {
+			if err := h(ctx, event); err != nil {
+				log.Error().Err(err).
+					Str("event_id", event.ID.String()).
+					Str("event_type", event.EventType).
+					Msg("outbox event handler failed")
+
+				if err := w.q.MarkOutboxEventFailed(ctx, sqlc.MarkOutboxEventFailedParams{
+					ID:        event.ID,
+					LastError: sql.NullString{String: err.Error(), Valid: true},
+				}); err != nil {
+					log.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to mark outbox event as failed")
+				}
+				failed++
+				continue
+			}
+		}
+
 		// Mark as processed
 		if err := w.q.MarkOutboxEventProcessed(ctx, event.ID); err != nil {
 			log.Error().Err(err).Str("event_id", event.ID.String()).Msg("failed to mark outbox event as processed")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	redisclient "github.com/munchies/platform/backend/internal/platform/redis"
 	"github.com/rs/zerolog/log"
@@ -11,20 +12,47 @@ import (
 
 // Worker manages background job processing.
 type Worker struct {
-	q     *sqlc.Queries
-	redis *redisclient.Client
-	stop  chan struct{}
+	q        *sqlc.Queries
+	pool     *pgxpool.Pool
+	redis    *redisclient.Client
+	handlers map[string]EventHandler
+	jobs     []scheduledJob
+	stop     chan struct{}
 }
 
+// scheduledJob is a periodic job registered by another module.
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	fn       func(context.Context) error
+}
+
+// EventHandler processes an outbox event after it has been published.
+// Returning an error leaves the event for retry.
+type EventHandler func(ctx context.Context, event sqlc.OutboxEvent) error
+
 // NewWorker creates a new background worker.
-func NewWorker(q *sqlc.Queries, redis *redisclient.Client) *Worker {
+func NewWorker(q *sqlc.Queries, pool *pgxpool.Pool, redis *redisclient.Client) *Worker {
 	return &Worker{
-		q:     q,
-		redis: redis,
-		stop:  make(chan struct{}),
+		q:        q,
+		pool:     pool,
+		redis:    redis,
+		handlers: make(map[string]EventHandler),
+		stop:     make(chan struct{}),
 	}
 }
 
+// Handle registers a handler for an outbox event type. Must be called before Start.
+func (w *Worker) Handle(eventType string, h EventHandler) {
+	w.handlers[eventType] = h
+}
+
+// Schedule registers a periodic job owned by another module. Must be called
+// before Start.
+func (w *Worker) Schedule(name string, interval time.Duration, fn func(context.Context) error) {
+	w.jobs = append(w.jobs, scheduledJob{name: name, interval: interval, fn: fn})
+}
+
 // Start starts all background job processing.
 func (w *Worker) Start(ctx context.Context) {
 	log.Info().Msg("starting background workers")
@@ -34,6 +62,9 @@ func (w *Worker) Start(ctx context.Context) {
 	go w.runPeriodic(ctx, "order:auto_cancel", 5*time.Minute, w.AutoCancelOrders)
 	go w.runPeriodic(ctx, "notifications:cleanup", 24*time.Hour, w.CleanupNotifications)
 	go w.runPeriodic(ctx, "outbox:process", 10*time.Second, w.ProcessOutboxEvents)
+	for _, j := range w.jobs {
+		go w.runPeriodic(ctx, j.name, j.interval, j.fn)
+	}
 
 	log.Info().Msg("all background workers started")
 }
@@ -2,9 +2,11 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/modules/analytics"
 	"github.com/rs/zerolog/log"
 )
 
@@ -48,12 +50,7 @@ func (w *Worker) AutoCancelOrders(ctx context.Context) error {
 
 	cancelled := 0
 	for _, order := range orders {
-		_, err := w.q.UpdateOrderStatus(ctx, sqlc.UpdateOrderStatusParams{
-			ID:       order.ID,
-			TenantID: order.TenantID,
-			Status:   sqlc.OrderStatusCancelled,
-		})
-		if err != nil {
+		if err := w.autoCancelOrder(ctx, order); err != nil {
 			log.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to auto-cancel order")
 			continue
 		}
@@ -66,6 +63,36 @@ func (w *Worker) AutoCancelOrders(ctx context.Context) error {
 	return nil
 }
 
+// autoCancelOrder cancels a timed-out order and releases its promo usages
+// in one transaction, so a cancelled order never keeps a promo redemption.
+func (w *Worker) autoCancelOrder(ctx context.Context, order sqlc.Order) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := w.q.WithTx(tx)
+
+	updated, err := qtx.UpdateOrderStatus(ctx, sqlc.UpdateOrderStatusParams{
+		ID:       order.ID,
+		TenantID: order.TenantID,
+		Status:   sqlc.OrderStatusCancelled,
+	})
+	if err != nil {
+		return fmt.Errorf("cancel order: %w", err)
+	}
+	if err := analytics.EnqueueOrderFact(ctx, qtx, updated); err != nil {
+		return err
+	}
+	if err := qtx.ReleasePromoUsagesForOrder(ctx, sqlc.ReleasePromoUsagesForOrderParams{
+		OrderID:  order.ID,
+		TenantID: order.TenantID,
+	}); err != nil {
+		return fmt.Errorf("release promo usages: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
 // CleanupNotifications purges notifications older than 90 days.
 func (w *Worker) CleanupNotifications(ctx context.Context) error {
 	before := time.Now().AddDate(0, 0, -90)
@@ -5,4 +5,5 @@ type contextKey string
 const (
 	TenantKey contextKey = "tenant"
 	UserKey   contextKey = "user"
+	ScopeKey  contextKey = "access_scope"
 )
@@ -0,0 +1,272 @@
+package pdf
+
+// Bengali is written in syllables that do not map one-to-one onto glyphs:
+// pre-base vowel signs are drawn before the consonant they follow, a leading
+// র্ becomes a reph mark drawn over the end of the cluster, and consonant
+// clusters join into conjuncts through the font's GSUB features. This is a
+// compact version of the OpenType Indic shaping model, enough for names,
+// addresses and menu items; glyph positioning (GPOS) is not applied, so
+// marks use the default placement designed into the font.
+
+const (
+	bnRa     = 'র'
+	bnYa     = 'য'
+	bnBa     = 'ব'
+	bnHalant = '্'
+	bnI      = 'ি'
+	bnE      = 'ে'
+	bnAi     = 'ৈ'
+	bnO      = 'ো'
+	bnAu     = 'ৌ'
+	bnAa     = 'া'
+	bnAuMark = 'ৗ'
+	zwnj     = '\u200c'
+	zwj      = '\u200d'
+)
+
+type bnCategory int
+
+const (
+	bnOther bnCategory = iota
+	bnConsonant
+	bnVowel
+	bnNukta
+	bnVirama
+	bnMatra
+	bnModifier
+	bnJoiner
+)
+
+func bengaliCategory(r rune) bnCategory {
+	switch {
+	case r >= 0x0995 && r <= 0x09A8, r >= 0x09AA && r <= 0x09B0, r == 0x09B2,
+		r >= 0x09B6 && r <= 0x09B9, r == 0x09DC, r == 0x09DD, r == 0x09DF,
+		r == 0x09F0, r == 0x09F1:
+		return bnConsonant
+	case r >= 0x0985 && r <= 0x098C, r == 0x098F, r == 0x0990, r == 0x0993,
+		r == 0x0994, r == 0x09E0, r == 0x09E1:
+		return bnVowel
+	case r == 0x09BC:
+		return bnNukta
+	case r == bnHalant:
+		return bnVirama
+	case r >= 0x09BE && r <= 0x09C4, r == 0x09C7, r == 0x09C8, r == 0x09CB,
+		r == 0x09CC, r == 0x09D7, r == 0x09E2, r == 0x09E3:
+		return bnMatra
+	case r >= 0x0981 && r <= 0x0983:
+		return bnModifier
+	case r == zwnj, r == zwj:
+		return bnJoiner
+	}
+	return bnOther
+}
+
+// isBengali reports whether r is shaped as Bengali.
+func isBengali(r rune) bool {
+	return r >= 0x0980 && r <= 0x09FF
+}
+
+// bengaliSyllable returns the end of the syllable starting at i:
+//
+//	C N? (H J? C N?)* (H J? | M*) SM*
+//	V N? M* SM*
+func bengaliSyllable(rs []rune, i int) int {
+	cat := func(j int) bnCategory {
+		if j >= len(rs) {
+			return bnOther
+		}
+		return bengaliCategory(rs[j])
+	}
+
+	j := i + 1
+	switch cat(i) {
+	case bnConsonant:
+		for {
+			if cat(j) == bnNukta {
+				j++
+			}
+			if cat(j) != bnVirama {
+				break
+			}
+			k := j + 1
+			if cat(k) == bnJoiner {
+				k++
+			}
+			if cat(k) != bnConsonant {
+				j = k
+				break
+			}
+			j = k + 1
+		}
+		if j > 0 && cat(j-1) != bnVirama && cat(j-1) != bnJoiner {
+			for cat(j) == bnMatra {
+				j++
+			}
+		}
+	case bnVowel:
+		if cat(j) == bnNukta {
+			j++
+		}
+		for cat(j) == bnMatra {
+			j++
+		}
+	}
+	for cat(j) == bnModifier {
+		j++
+	}
+	return j
+}
+
+// Feature masks restricting where the basic shaping features apply.
+const (
+	maskRphf uint32 = 1 << iota
+	maskHalf
+	maskBlwf
+	maskPstf
+)
+
+var bengaliBasicFeatures = []struct {
+	tag  string
+	mask uint32
+}{
+	{"locl", 0}, {"ccmp", 0}, {"nukt", 0}, {"akhn", 0}, {"rphf", maskRphf},
+	{"blwf", maskBlwf}, {"half", maskHalf}, {"pstf", maskPstf}, {"vatu", 0}, {"cjct", 0},
+}
+
+var bengaliPresentationFeatures = []string{"pres", "abvs", "blws", "psts", "haln", "calt"}
+
+// isPostBase reports whether consonant r takes a below- or post-base form
+// after a virama (র-ফলা, ব-ফলা, য-ফলা).
+func isPostBase(r rune) bool { return r == bnRa || r == bnBa || r == bnYa }
+
+// reorderBengali puts a syllable's characters in visual order and marks which
+// basic features may act on each. Two-part vowel signs are split, pre-base
+// vowel signs move to the front and a reph moves after the consonants.
+func reorderBengali(syl []rune) ([]rune, []uint32) {
+	var rs []rune
+	for _, r := range syl {
+		switch r {
+		case bnO:
+			rs = append(rs, bnE, bnAa)
+		case bnAu:
+			rs = append(rs, bnE, bnAuMark)
+		default:
+			rs = append(rs, r)
+		}
+	}
+
+	start := 0
+	reph := len(rs) >= 3 && rs[0] == bnRa && rs[1] == bnHalant && bengaliCategory(rs[2]) == bnConsonant
+	if reph {
+		start = 2
+	}
+
+	// The consonant cluster runs up to the first vowel sign or modifier.
+	end := start
+	for end < len(rs) {
+		if c := bengaliCategory(rs[end]); c == bnMatra || c == bnModifier {
+			break
+		}
+		end++
+	}
+	cluster := rs[start:end]
+
+	// The base is the last consonant that is not a post-base form.
+	base := -1
+	for k := len(cluster) - 1; k >= 0; k-- {
+		if bengaliCategory(cluster[k]) != bnConsonant {
+			continue
+		}
+		base = k
+		if k == 0 || cluster[k-1] != bnHalant || !isPostBase(cluster[k]) {
+			break
+		}
+	}
+
+	var pre, post []rune
+	for _, r := range rs[end:] {
+		if r == bnI || r == bnE || r == bnAi {
+			pre = append(pre, r)
+		} else {
+			post = append(post, r)
+		}
+	}
+
+	out := make([]rune, 0, len(rs))
+	masks := make([]uint32, 0, len(rs))
+	for _, r := range pre {
+		out, masks = append(out, r), append(masks, 0)
+	}
+	for k, r := range cluster {
+		var m uint32
+		switch {
+		case base < 0:
+		case k < base:
+			m = maskHalf
+		case k > base && r == bnHalant && k+1 < len(cluster) && cluster[k+1] == bnYa,
+			k > base && r == bnYa:
+			m = maskPstf
+		case k > base:
+			m = maskBlwf
+		}
+		out, masks = append(out, r), append(masks, m)
+	}
+	if reph {
+		out, masks = append(out, bnRa, bnHalant), append(masks, maskRphf, maskRphf)
+	}
+	for _, r := range post {
+		out, masks = append(out, r), append(masks, 0)
+	}
+	return out, masks
+}
+
+// bengaliScript returns the font's Bengali GSUB script tag, preferring the
+// newer bng2 shaping model.
+func (f *Font) bengaliScript() string {
+	if f.gsub == nil {
+		return ""
+	}
+	for _, s := range []string{"bng2", "beng"} {
+		if _, ok := f.gsub.scripts[s]; ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// shape converts text to glyphs, shaping Bengali syllables.
+func (f *Font) shape(text []rune) []glyphInfo {
+	script := f.bengaliScript()
+	var out []glyphInfo
+	for i := 0; i < len(text); {
+		if !isBengali(text[i]) {
+			out = append(out, glyphInfo{id: f.glyph(text[i]), text: text[i : i+1]})
+			i++
+			continue
+		}
+		end := bengaliSyllable(text, i)
+		out = append(out, f.shapeSyllable(text[i:end], script)...)
+		i = end
+	}
+	return out
+}
+
+func (f *Font) shapeSyllable(syl []rune, script string) []glyphInfo {
+	rs, masks := reorderBengali(syl)
+	buf := make([]glyphInfo, len(rs))
+	for k, r := range rs {
+		buf[k] = glyphInfo{id: f.glyph(r), text: []rune{r}, mask: masks[k]}
+	}
+	if script == "" {
+		return buf
+	}
+	for _, feat := range bengaliBasicFeatures {
+		for _, l := range f.gsub.lookupsFor(script, feat.tag) {
+			buf = f.gsub.apply(buf, l, feat.mask)
+		}
+	}
+	for _, l := range f.gsub.lookupsFor(script, bengaliPresentationFeatures...) {
+		buf = f.gsub.apply(buf, l, 0)
+	}
+	return buf
+}
@@ -0,0 +1,102 @@
+package pdf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBengaliSyllables(t *testing.T) {
+	cases := map[string][]string{
+		"বাংলা":   {"বাং", "লা"},
+		"স্কুল":   {"স্কু", "ল"},
+		"কর্ম":    {"ক", "র্ম"},
+		"আমি":     {"আ", "মি"},
+		"বিক্ষোভ": {"বি", "ক্ষো", "ভ"},
+		"ক্":      {"ক্"},
+	}
+	for text, want := range cases {
+		rs := []rune(text)
+		var got []string
+		for i := 0; i < len(rs); {
+			end := bengaliSyllable(rs, i)
+			got = append(got, string(rs[i:end]))
+			i = end
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: syllables = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestReorderBengali(t *testing.T) {
+	cases := []struct {
+		syl   string
+		order string
+		masks []uint32
+	}{
+		{"কি", "িক", []uint32{0, 0}},
+		{"কো", "েকা", []uint32{0, 0, 0}},
+		{"কৌ", "েকৗ", []uint32{0, 0, 0}},
+		{"ক্ত", "ক্ত", []uint32{maskHalf, maskHalf, 0}},
+		{"প্র", "প্র", []uint32{0, maskBlwf, maskBlwf}},
+		{"ক্য", "ক্য", []uint32{0, maskPstf, maskPstf}},
+		{"র্মি", "িমর্", []uint32{0, 0, maskRphf, maskRphf}},
+		{"র্ধ্বে", "েধ্বর্", []uint32{0, 0, maskBlwf, maskBlwf, maskRphf, maskRphf}},
+	}
+	for _, c := range cases {
+		got, masks := reorderBengali([]rune(c.syl))
+		if string(got) != c.order {
+			t.Errorf("%s: order = %q, want %q", c.syl, string(got), c.order)
+		}
+		if !slices.Equal(masks, c.masks) {
+			t.Errorf("%s: masks = %v, want %v", c.syl, masks, c.masks)
+		}
+	}
+}
+
+func TestChainedContextSubstitution(t *testing.T) {
+	// Lookup 0 swaps ি for a narrow form when ক follows; lookup 1 is the
+	// single substitution it invokes.
+	table := &gsubTable{
+		scripts: map[string]map[string][]int{"bng2": {"pres": {0}}},
+		lookups: []gsubLookup{
+			{subtables: []gsubSubtable{contextSubst{
+				format: 3,
+				sets: [][]contextRule{{{
+					input:     []glyphMatcher{coverage{gI: 0}},
+					lookahead: []glyphMatcher{coverage{gKa: 0}},
+					records:   []substRecord{{seq: 0, lookup: 1}},
+				}}},
+			}}},
+			{subtables: []gsubSubtable{singleSubst{gI: 20}}},
+		},
+		classes: classDef{},
+	}
+	f := &Font{unitsPerEm: 1000, cmap: map[rune]uint16{'ক': gKa, 'ি': gI, 'র': gRa}, gsub: table}
+
+	if got := glyphIDs(f.shape([]rune("কি"))); !slices.Equal(got, []uint16{20, gKa}) {
+		t.Errorf("কি = %v, want [20 %d]", got, gKa)
+	}
+	if got := glyphIDs(f.shape([]rune("রি"))); !slices.Equal(got, []uint16{gI, gRa}) {
+		t.Errorf("রি = %v, want the default ি", got)
+	}
+}
+
+func TestLigatureSkipsMarks(t *testing.T) {
+	const mark = 30
+	table := &gsubTable{
+		lookups: []gsubLookup{{
+			flag:      ignoreMarks,
+			subtables: []gsubSubtable{ligatureSubst{cov: coverage{1: 0}, sets: [][]ligature{{{glyph: 9, components: []uint16{2}}}}}},
+		}},
+		classes: classDef{mark: 3},
+	}
+	buf := []glyphInfo{{id: 1, text: []rune("a")}, {id: mark, text: []rune("'")}, {id: 2, text: []rune("b")}}
+	got := table.apply(buf, 0, 0)
+	if ids := glyphIDs(got); !slices.Equal(ids, []uint16{9, mark}) {
+		t.Fatalf("glyphs = %v, want [9 %d]", ids, mark)
+	}
+	if string(got[0].text) != "ab" {
+		t.Errorf("ligature text = %q, want ab", string(got[0].text))
+	}
+}
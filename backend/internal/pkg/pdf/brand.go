@@ -0,0 +1,106 @@
+package pdf
+
+// Brand is the identity a document is issued under.
+type Brand struct {
+	Name  string
+	Color Color
+	Logo  *Image   // optional
+	Lines []string // address and contact lines under the name
+}
+
+// Field is a labelled value in a document heading.
+type Field struct {
+	Label string
+	Value string
+}
+
+const logoBox = 54.0
+
+// Letterhead draws the brand and the document title at the top of the
+// current page, with fields such as the document number on the right, and
+// sets a slim running header for later pages.
+func (d *Document) Letterhead(b Brand, title string, fields []Field) {
+	d.FillRect(0, 0, PageWidth, 6, b.Color)
+	top := d.y
+
+	x := Margin
+	if b.Logo != nil {
+		w, h := b.Logo.Fit(logoBox, logoBox)
+		d.DrawImage(b.Logo, x, top, w, h)
+		x += w + 10
+	}
+	left := top
+	d.Text(x, left, d.Width()/2, b.Name, Style{Size: 16, Bold: true, Color: b.Color}, AlignLeft)
+	left += LineHeight(Style{Size: 16})
+	small := Style{Size: 8.5, Color: Gray}
+	for _, line := range b.Lines {
+		d.Text(x, left, d.Width()/2, line, small, AlignLeft)
+		left += LineHeight(small)
+	}
+	if b.Logo != nil {
+		left = max(left, top+logoBox)
+	}
+
+	right := top
+	titleStyle := Style{Size: 18, Bold: true}
+	d.Text(Margin, right, d.Width(), title, titleStyle, AlignRight)
+	right += LineHeight(titleStyle)
+	for _, f := range fields {
+		line := f.Label + ": " + f.Value
+		if f.Label == "" {
+			line = f.Value
+		}
+		d.Text(Margin, right, d.Width(), line, Style{Size: 9}, AlignRight)
+		right += LineHeight(Style{Size: 9})
+	}
+
+	d.y = max(left, right) + 8
+	d.Line(Margin, d.y, PageWidth-Margin, d.y, 1.5, b.Color)
+	d.y += 14
+
+	if d.header == nil {
+		d.SetHeader(func(d *Document) {
+			d.FillRect(0, 0, PageWidth, 6, b.Color)
+			st := Style{Size: 9, Color: Gray}
+			d.Text(Margin, d.y, d.Width(), b.Name, Style{Size: 9, Bold: true, Color: b.Color}, AlignLeft)
+			d.Text(Margin, d.y, d.Width(), title, st, AlignRight)
+			d.y += LineHeight(st) + 4
+			d.Rule(0.5, LightGray)
+			d.y += 10
+		})
+	}
+}
+
+// Heading draws a section heading at the cursor.
+func (d *Document) Heading(text string, c Color) {
+	st := Style{Size: 11, Bold: true, Color: c}
+	d.EnsureSpace(3 * LineHeight(st))
+	d.y += 4
+	d.Text(Margin, d.y, d.Width(), text, st, AlignLeft)
+	d.y += LineHeight(st) + 2
+}
+
+// Fields draws labelled values in two columns at the cursor.
+func (d *Document) Fields(fields []Field) {
+	label := Style{Size: 9, Color: Gray}
+	value := Style{Size: 9}
+	half := d.Width() / 2
+	for i := 0; i < len(fields); i += 2 {
+		h := LineHeight(value)
+		for j := i; j < i+2 && j < len(fields); j++ {
+			lines := d.WrapText(fields[j].Value, value, half-100)
+			h = max(h, float64(len(lines))*LineHeight(value))
+		}
+		d.EnsureSpace(h)
+		for j := i; j < i+2 && j < len(fields); j++ {
+			x := Margin + float64(j-i)*half
+			d.Text(x, d.y, 95, fields[j].Label, label, AlignLeft)
+			y := d.y
+			for _, line := range d.WrapText(fields[j].Value, value, half-100) {
+				d.Text(x+100, y, half-100, line, value, AlignLeft)
+				y += LineHeight(value)
+			}
+		}
+		d.y += h + 2
+	}
+}
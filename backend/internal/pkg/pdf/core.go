@@ -0,0 +1,73 @@
+package pdf
+
+// The standard Helvetica fonts need no embedding and cover Latin text in
+// WinAnsiEncoding. Widths are in thousandths of the font size, from the
+// Adobe font metrics.
+
+// helveticaWidths covers ' ' through '~'.
+var helveticaWidths = [95]uint16{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]uint16{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+// winAnsiExtras maps the characters of WinAnsiEncoding outside Latin-1 to
+// their codes and Helvetica widths.
+var winAnsiExtras = map[rune]struct {
+	code  byte
+	width uint16
+}{
+	'€': {0x80, 556}, '‚': {0x82, 222}, '„': {0x84, 333}, '…': {0x85, 1000},
+	'†': {0x86, 556}, '‡': {0x87, 556}, '‰': {0x89, 1000}, '‹': {0x8B, 333},
+	'‘': {0x91, 222}, '’': {0x92, 222}, '“': {0x93, 333}, '”': {0x94, 333},
+	'•': {0x95, 350}, '–': {0x96, 556}, '—': {0x97, 1000}, '™': {0x99, 1000},
+	'›': {0x9B, 333},
+}
+
+// winAnsi returns the WinAnsiEncoding code of r.
+func winAnsi(r rune) (byte, bool) {
+	switch {
+	case r >= ' ' && r <= '~', r >= 0xA0 && r <= 0xFF:
+		return byte(r), true
+	}
+	if e, ok := winAnsiExtras[r]; ok {
+		return e.code, true
+	}
+	return 0, false
+}
+
+// coreWidth returns the width of an encodable rune in Helvetica.
+func coreWidth(r rune, bold bool) float64 {
+	switch {
+	case r >= ' ' && r <= '~':
+		if bold {
+			return float64(helveticaBoldWidths[r-' '])
+		}
+		return float64(helveticaWidths[r-' '])
+	case r == 0xA0:
+		return 278
+	}
+	if e, ok := winAnsiExtras[r]; ok {
+		return float64(e.width)
+	}
+	// Latin-1 letters and symbols are close to a digit's width.
+	return 556
+}
+
+// Helvetica ascent and descent in thousandths of the font size.
+const (
+	coreAscent  = 718
+	coreDescent = -207
+)
@@ -0,0 +1,657 @@
+package pdf
+
+import "sort"
+
+// glyphInfo is a glyph in a shaping buffer with the text it stands for and
+// the features allowed to act on it.
+type glyphInfo struct {
+	id   uint16
+	text []rune
+	mask uint32
+}
+
+// gsubTable holds the glyph substitutions of a font: which lookups each
+// feature of each script uses and the lookups themselves.
+type gsubTable struct {
+	scripts map[string]map[string][]int
+	lookups []gsubLookup
+	classes classDef // GDEF glyph classes: 1 base, 2 ligature, 3 mark
+}
+
+type gsubLookup struct {
+	flag      uint16
+	subtables []gsubSubtable
+}
+
+// gsubSubtable applies at buf[i] if it matches there, returning the new
+// buffer and the index to continue from.
+type gsubSubtable interface {
+	apply(c *gsubContext, buf []glyphInfo, i int) ([]glyphInfo, int, bool)
+}
+
+// Lookup flags that skip glyphs by GDEF class.
+const (
+	ignoreBaseGlyphs = 0x2
+	ignoreLigatures  = 0x4
+	ignoreMarks      = 0x8
+)
+
+type coverage map[uint16]int
+
+type classDef map[uint16]uint16
+
+// glyphMatcher matches one position of a contextual rule: a glyph, a glyph
+// class or a coverage.
+type glyphMatcher interface{ match(g uint16) bool }
+
+type glyphID uint16
+
+func (m glyphID) match(g uint16) bool { return uint16(m) == g }
+
+type classMatch struct {
+	def   classDef
+	class uint16
+}
+
+func (m classMatch) match(g uint16) bool { return m.def[g] == m.class }
+
+func (m coverage) match(g uint16) bool {
+	_, ok := m[g]
+	return ok
+}
+
+// maxContextDepth bounds contextual lookups invoking each other.
+const maxContextDepth = 8
+
+// gsubContext carries what matching needs: the table for nested lookups, the
+// current lookup's flag, the feature mask glyphs must have (0 for any) and
+// how deeply contextual lookups are nested.
+type gsubContext struct {
+	t     *gsubTable
+	flag  uint16
+	mask  uint32
+	depth int
+}
+
+func (c *gsubContext) ignored(g uint16) bool {
+	switch c.t.classes[g] {
+	case 1:
+		return c.flag&ignoreBaseGlyphs != 0
+	case 2:
+		return c.flag&ignoreLigatures != 0
+	case 3:
+		return c.flag&ignoreMarks != 0
+	}
+	return false
+}
+
+func (c *gsubContext) eligible(gi glyphInfo) bool {
+	return c.mask == 0 || gi.mask&c.mask != 0
+}
+
+// next returns the first index after i that the lookup does not skip, or -1.
+func (c *gsubContext) next(buf []glyphInfo, i int) int {
+	for j := i + 1; j < len(buf); j++ {
+		if !c.ignored(buf[j].id) {
+			return j
+		}
+	}
+	return -1
+}
+
+func (c *gsubContext) prev(buf []glyphInfo, i int) int {
+	for j := i - 1; j >= 0; j-- {
+		if !c.ignored(buf[j].id) {
+			return j
+		}
+	}
+	return -1
+}
+
+// lookupsFor returns the lookups of a script's features in the order the
+// font lists them.
+func (t *gsubTable) lookupsFor(script string, features ...string) []int {
+	if t == nil {
+		return nil
+	}
+	feats := t.scripts[script]
+	seen := map[int]bool{}
+	var out []int
+	for _, f := range features {
+		for _, l := range feats[f] {
+			if !seen[l] {
+				seen[l] = true
+				out = append(out, l)
+			}
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// apply runs a lookup over the whole buffer on glyphs carrying mask.
+func (t *gsubTable) apply(buf []glyphInfo, lookup int, mask uint32) []glyphInfo {
+	if lookup < 0 || lookup >= len(t.lookups) {
+		return buf
+	}
+	l := t.lookups[lookup]
+	c := &gsubContext{t: t, flag: l.flag, mask: mask}
+	for i := 0; i < len(buf); {
+		if !c.eligible(buf[i]) || c.ignored(buf[i].id) {
+			i++
+			continue
+		}
+		applied := false
+		for _, st := range l.subtables {
+			var next int
+			var ok bool
+			if buf, next, ok = st.apply(c, buf, i); ok {
+				i, applied = next, true
+				break
+			}
+		}
+		if !applied {
+			i++
+		}
+	}
+	return buf
+}
+
+// applyAt runs a lookup once at position i, as a contextual rule does.
+func (t *gsubTable) applyAt(buf []glyphInfo, lookup, i, depth int) []glyphInfo {
+	if lookup < 0 || lookup >= len(t.lookups) || i < 0 || i >= len(buf) || depth > maxContextDepth {
+		return buf
+	}
+	l := t.lookups[lookup]
+	c := &gsubContext{t: t, flag: l.flag, depth: depth}
+	for _, st := range l.subtables {
+		if out, _, ok := st.apply(c, buf, i); ok {
+			return out
+		}
+	}
+	return buf
+}
+
+// ---- Substitution subtables ----
+
+type singleSubst map[uint16]uint16
+
+func (s singleSubst) apply(_ *gsubContext, buf []glyphInfo, i int) ([]glyphInfo, int, bool) {
+	g, ok := s[buf[i].id]
+	if !ok {
+		return buf, i, false
+	}
+	buf[i].id = g
+	return buf, i + 1, true
+}
+
+type multipleSubst struct {
+	cov       coverage
+	sequences [][]uint16
+}
+
+func (s multipleSubst) apply(_ *gsubContext, buf []glyphInfo, i int) ([]glyphInfo, int, bool) {
+	idx, ok := s.cov[buf[i].id]
+	if !ok || idx >= len(s.sequences) || len(s.sequences[idx]) == 0 {
+		return buf, i, false
+	}
+	seq := s.sequences[idx]
+	out := make([]glyphInfo, 0, len(buf)+len(seq)-1)
+	out = append(out, buf[:i]...)
+	for k, g := range seq {
+		gi := glyphInfo{id: g, mask: buf[i].mask}
+		if k == 0 {
+			gi.text = buf[i].text
+		}
+		out = append(out, gi)
+	}
+	out = append(out, buf[i+1:]...)
+	return out, i + len(seq), true
+}
+
+type ligature struct {
+	glyph      uint16
+	components []uint16 // after the first
+}
+
+type ligatureSubst struct {
+	cov  coverage
+	sets [][]ligature
+}
+
+func (s ligatureSubst) apply(c *gsubContext, buf []glyphInfo, i int) ([]glyphInfo, int, bool) {
+	idx, ok := s.cov[buf[i].id]
+	if !ok || idx >= len(s.sets) {
+		return buf, i, false
+	}
+next:
+	for _, lig := range s.sets[idx] {
+		positions := make([]int, 0, len(lig.components))
+		at := i
+		for _, comp := range lig.components {
+			if at = c.next(buf, at); at < 0 || buf[at].id != comp || !c.eligible(buf[at]) {
+				continue next
+			}
+			positions = append(positions, at)
+		}
+
+		text := append([]rune(nil), buf[i].text...)
+		remove := map[int]bool{}
+		for _, p := range positions {
+			text = append(text, buf[p].text...)
+			remove[p] = true
+		}
+		out := make([]glyphInfo, 0, len(buf)-len(positions))
+		for j, gi := range buf {
+			switch {
+			case j == i:
+				out = append(out, glyphInfo{id: lig.glyph, text: text, mask: gi.mask})
+			case !remove[j]:
+				out = append(out, gi)
+			}
+		}
+		return out, i + 1, true
+	}
+	return buf, i, false
+}
+
+type substRecord struct {
+	seq    int
+	lookup int
+}
+
+type contextRule struct {
+	backtrack []glyphMatcher // nearest first
+	input     []glyphMatcher // including the first glyph
+	lookahead []glyphMatcher
+	records   []substRecord
+}
+
+// contextSubst covers contextual and chained contextual substitution. Rules
+// are picked by the first glyph's coverage index (format 1), its input
+// class (format 2) or are all tried (format 3).
+type contextSubst struct {
+	cov     coverage
+	classes classDef
+	format  int
+	sets    [][]contextRule
+}
+
+func (s contextSubst) apply(c *gsubContext, buf []glyphInfo, i int) ([]glyphInfo, int, bool) {
+	first := buf[i].id
+	var rules []contextRule
+	switch s.format {
+	case 1:
+		idx, ok := s.cov[first]
+		if !ok || idx >= len(s.sets) {
+			return buf, i, false
+		}
+		rules = s.sets[idx]
+	case 2:
+		if _, ok := s.cov[first]; !ok {
+			return buf, i, false
+		}
+		if cls := int(s.classes[first]); cls < len(s.sets) {
+			rules = s.sets[cls]
+		}
+	default:
+		if len(s.sets) > 0 {
+			rules = s.sets[0]
+		}
+	}
+
+	for _, r := range rules {
+		positions, ok := matchContext(c, buf, i, r)
+		if !ok {
+			continue
+		}
+		for _, rec := range r.records {
+			if rec.seq >= len(positions) {
+				continue
+			}
+			before := len(buf)
+			buf = c.t.applyAt(buf, rec.lookup, positions[rec.seq], c.depth+1)
+			if delta := len(buf) - before; delta != 0 {
+				for k := rec.seq + 1; k < len(positions); k++ {
+					positions[k] += delta
+				}
+			}
+		}
+		end := positions[len(positions)-1] + 1
+		if end > len(buf) {
+			end = len(buf)
+		}
+		if end <= i {
+			end = i + 1
+		}
+		return buf, end, true
+	}
+	return buf, i, false
+}
+
+// matchContext matches a rule at i and returns the input positions.
+func matchContext(c *gsubContext, buf []glyphInfo, i int, r contextRule) ([]int, bool) {
+	if len(r.input) == 0 || !r.input[0].match(buf[i].id) {
+		return nil, false
+	}
+	positions := []int{i}
+	at := i
+	for _, m := range r.input[1:] {
+		if at = c.next(buf, at); at < 0 || !m.match(buf[at].id) || !c.eligible(buf[at]) {
+			return nil, false
+		}
+		positions = append(positions, at)
+	}
+	for k, b := 0, i; k < len(r.backtrack); k++ {
+		if b = c.prev(buf, b); b < 0 || !r.backtrack[k].match(buf[b].id) {
+			return nil, false
+		}
+	}
+	for k, a := 0, at; k < len(r.lookahead); k++ {
+		if a = c.next(buf, a); a < 0 || !r.lookahead[k].match(buf[a].id) {
+			return nil, false
+		}
+	}
+	return positions, true
+}
+
+// ---- Parsing ----
+
+func parseGSUB(t sfnt, classes classDef) *gsubTable {
+	g := &gsubTable{scripts: map[string]map[string][]int{}, classes: classes}
+	scriptList, featureList, lookupList := int(t.u16(4)), int(t.u16(6)), int(t.u16(8))
+
+	type feature struct {
+		tag     string
+		lookups []int
+	}
+	var features []feature
+	for i, n := 0, int(t.u16(featureList)); i < n; i++ {
+		rec := featureList + 2 + 6*i
+		off := featureList + int(t.u16(rec+4))
+		f := feature{tag: tag(t, rec)}
+		for j, m := 0, int(t.u16(off+2)); j < m; j++ {
+			f.lookups = append(f.lookups, int(t.u16(off+4+2*j)))
+		}
+		features = append(features, f)
+	}
+
+	for i, n := 0, int(t.u16(scriptList)); i < n; i++ {
+		rec := scriptList + 2 + 6*i
+		script := scriptList + int(t.u16(rec+4))
+		langSys := int(t.u16(script))
+		if langSys == 0 {
+			if t.u16(script+2) == 0 {
+				continue
+			}
+			langSys = int(t.u16(script + 4 + 4))
+		}
+		langSys += script
+
+		feats := map[string][]int{}
+		add := func(idx int) {
+			if idx < len(features) {
+				f := features[idx]
+				feats[f.tag] = append(feats[f.tag], f.lookups...)
+			}
+		}
+		if req := t.u16(langSys + 2); req != 0xFFFF {
+			add(int(req))
+		}
+		for j, m := 0, int(t.u16(langSys+4)); j < m; j++ {
+			add(int(t.u16(langSys + 6 + 2*j)))
+		}
+		g.scripts[tag(t, rec)] = feats
+	}
+
+	for i, n := 0, int(t.u16(lookupList)); i < n; i++ {
+		off := lookupList + int(t.u16(lookupList+2+2*i))
+		typ, flag := t.u16(off), t.u16(off+2)
+		l := gsubLookup{flag: flag}
+		for j, m := 0, int(t.u16(off+4)); j < m; j++ {
+			sub := off + int(t.u16(off+6+2*j))
+			subType := typ
+			if subType == 7 { // extension
+				subType = t.u16(sub + 2)
+				sub += int(t.u32(sub + 4))
+			}
+			if st := parseSubtable(t, subType, sub); st != nil {
+				l.subtables = append(l.subtables, st)
+			}
+		}
+		g.lookups = append(g.lookups, l)
+	}
+	return g
+}
+
+func tag(t sfnt, off int) string {
+	return string([]byte{t.u8(off), t.u8(off + 1), t.u8(off + 2), t.u8(off + 3)})
+}
+
+func parseSubtable(t sfnt, typ uint16, off int) gsubSubtable {
+	format := t.u16(off)
+	switch typ {
+	case 1:
+		cov := parseCoverage(t, off+int(t.u16(off+2)))
+		s := singleSubst{}
+		for g, idx := range cov {
+			if format == 1 {
+				s[g] = g + t.u16(off+4)
+			} else if idx < int(t.u16(off+4)) {
+				s[g] = t.u16(off + 6 + 2*idx)
+			}
+		}
+		return s
+	case 2:
+		s := multipleSubst{cov: parseCoverage(t, off+int(t.u16(off+2)))}
+		for i, n := 0, int(t.u16(off+4)); i < n; i++ {
+			seq := off + int(t.u16(off+6+2*i))
+			s.sequences = append(s.sequences, glyphArray(t, seq+2, int(t.u16(seq))))
+		}
+		return s
+	case 4:
+		s := ligatureSubst{cov: parseCoverage(t, off+int(t.u16(off+2)))}
+		for i, n := 0, int(t.u16(off+4)); i < n; i++ {
+			set := off + int(t.u16(off+6+2*i))
+			var ligs []ligature
+			for j, m := 0, int(t.u16(set)); j < m; j++ {
+				lig := set + int(t.u16(set+2+2*j))
+				count := int(t.u16(lig + 2))
+				if count == 0 {
+					continue
+				}
+				ligs = append(ligs, ligature{glyph: t.u16(lig), components: glyphArray(t, lig+4, count-1)})
+			}
+			s.sets = append(s.sets, ligs)
+		}
+		return s
+	case 5:
+		return parseContext(t, off, false)
+	case 6:
+		return parseContext(t, off, true)
+	}
+	return nil
+}
+
+// parseContext reads a contextual (type 5) or chained contextual (type 6)
+// substitution subtable.
+func parseContext(t sfnt, off int, chained bool) gsubSubtable {
+	format := int(t.u16(off))
+	s := contextSubst{format: format}
+
+	switch format {
+	case 1, 2:
+		s.cov = parseCoverage(t, off+int(t.u16(off+2)))
+		var back, input, ahead classDef
+		setsAt := off + 4
+		if format == 2 {
+			if chained {
+				back = parseClassDef(t, off+int(t.u16(off+4)))
+				input = parseClassDef(t, off+int(t.u16(off+6)))
+				ahead = parseClassDef(t, off+int(t.u16(off+8)))
+				setsAt = off + 10
+			} else {
+				input = parseClassDef(t, off+int(t.u16(off+4)))
+				setsAt = off + 6
+			}
+			s.classes = input
+		}
+		matcher := func(def classDef) func(v uint16) glyphMatcher {
+			if format == 1 {
+				return func(v uint16) glyphMatcher { return glyphID(v) }
+			}
+			return func(v uint16) glyphMatcher { return classMatch{def: def, class: v} }
+		}
+		for i, n := 0, int(t.u16(setsAt)); i < n; i++ {
+			setOff := int(t.u16(setsAt + 2 + 2*i))
+			var rules []contextRule
+			if setOff != 0 {
+				set := off + setOff
+				for j, m := 0, int(t.u16(set)); j < m; j++ {
+					rule := set + int(t.u16(set+2+2*j))
+					r := readRule(t, rule, chained, matcher(back), matcher(input), matcher(ahead))
+					// The first glyph is selected by the coverage.
+					r.input = append([]glyphMatcher{s.cov}, r.input...)
+					rules = append(rules, r)
+				}
+			}
+			s.sets = append(s.sets, rules)
+		}
+	case 3:
+		var r contextRule
+		at := off + 2
+		coverages := func(count int) []glyphMatcher {
+			out := make([]glyphMatcher, count)
+			for k := range out {
+				out[k] = parseCoverage(t, off+int(t.u16(at+2*k)))
+			}
+			at += 2 * count
+			return out
+		}
+		if chained {
+			n := int(t.u16(at))
+			at += 2
+			r.backtrack = coverages(n)
+			n = int(t.u16(at))
+			at += 2
+			r.input = coverages(n)
+			n = int(t.u16(at))
+			at += 2
+			r.lookahead = coverages(n)
+			r.records = readRecords(t, at+2, int(t.u16(at)))
+		} else {
+			n, substs := int(t.u16(at)), int(t.u16(at+2))
+			at += 4
+			r.input = coverages(n)
+			r.records = readRecords(t, at, substs)
+		}
+		if len(r.input) == 0 {
+			return nil
+		}
+		s.sets = [][]contextRule{{r}}
+	default:
+		return nil
+	}
+	return s
+}
+
+// readRule reads a rule of a format 1 or 2 (chained) context subtable. The
+// input sequence excludes the first glyph.
+func readRule(t sfnt, at int, chained bool, back, input, ahead func(uint16) glyphMatcher) contextRule {
+	var r contextRule
+	read := func(count int, m func(uint16) glyphMatcher) []glyphMatcher {
+		out := make([]glyphMatcher, count)
+		for k := range out {
+			out[k] = m(t.u16(at + 2*k))
+		}
+		at += 2 * count
+		return out
+	}
+	if !chained {
+		inputCount, substs := int(t.u16(at)), int(t.u16(at+2))
+		at += 4
+		if inputCount > 0 {
+			r.input = read(inputCount-1, input)
+		}
+		r.records = readRecords(t, at, substs)
+		return r
+	}
+	n := int(t.u16(at))
+	at += 2
+	r.backtrack = read(n, back)
+	n = int(t.u16(at))
+	at += 2
+	if n > 0 {
+		r.input = read(n-1, input)
+	}
+	n = int(t.u16(at))
+	at += 2
+	r.lookahead = read(n, ahead)
+	r.records = readRecords(t, at+2, int(t.u16(at)))
+	return r
+}
+
+func readRecords(t sfnt, at, count int) []substRecord {
+	out := make([]substRecord, count)
+	for k := range out {
+		out[k] = substRecord{seq: int(t.u16(at + 4*k)), lookup: int(t.u16(at + 4*k + 2))}
+	}
+	return out
+}
+
+func glyphArray(t sfnt, at, count int) []uint16 {
+	out := make([]uint16, count)
+	for k := range out {
+		out[k] = t.u16(at + 2*k)
+	}
+	return out
+}
+
+func parseCoverage(t sfnt, off int) coverage {
+	c := coverage{}
+	switch t.u16(off) {
+	case 1:
+		for i, n := 0, int(t.u16(off+2)); i < n; i++ {
+			c[t.u16(off+4+2*i)] = i
+		}
+	case 2:
+		for i, n := 0, int(t.u16(off+2)); i < n; i++ {
+			rec := off + 4 + 6*i
+			start, end, idx := t.u16(rec), t.u16(rec+2), int(t.u16(rec+4))
+			for g := int(start); g <= int(end); g++ {
+				c[uint16(g)] = idx + g - int(start)
+			}
+		}
+	}
+	return c
+}
+
+func parseClassDef(t sfnt, off int) classDef {
+	c := classDef{}
+	switch t.u16(off) {
+	case 1:
+		start := int(t.u16(off + 2))
+		for i, n := 0, int(t.u16(off+4)); i < n; i++ {
+			if cls := t.u16(off + 6 + 2*i); cls != 0 {
+				c[uint16(start+i)] = cls
+			}
+		}
+	case 2:
+		for i, n := 0, int(t.u16(off+2)); i < n; i++ {
+			rec := off + 4 + 6*i
+			start, end, cls := t.u16(rec), t.u16(rec+2), t.u16(rec+4)
+			for g := int(start); g <= int(end); g++ {
+				c[uint16(g)] = cls
+			}
+		}
+	}
+	return c
+}
+
+// parseGlyphClasses reads the glyph class definitions from a GDEF table.
+func parseGlyphClasses(t sfnt) classDef {
+	if t == nil || t.u16(4) == 0 {
+		return classDef{}
+	}
+	return parseClassDef(t, int(t.u16(4)))
+}
@@ -0,0 +1,104 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // logos may be GIFs
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Image is a raster image that can be drawn in documents. It is immutable
+// and can be shared by concurrent documents.
+type Image struct {
+	width, height int
+	colorSpace    string
+	filter        string
+	decode        string
+	data          []byte
+	alpha         []byte // zlib-compressed 8-bit soft mask, if any
+}
+
+// ParseImage reads a JPEG, PNG or GIF image. JPEGs are embedded as they
+// are; other formats are decompressed and stored losslessly.
+func ParseImage(data []byte) (*Image, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("pdf: unsupported image format")
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return nil, errors.New("pdf: empty image")
+	}
+
+	if format == "jpeg" {
+		img := &Image{width: cfg.Width, height: cfg.Height, filter: "DCTDecode", data: data}
+		switch cfg.ColorModel {
+		case color.GrayModel:
+			img.colorSpace = "DeviceGray"
+		case color.CMYKModel:
+			// Adobe CMYK JPEGs store inverted values.
+			img.colorSpace, img.decode = "DeviceCMYK", "[1 0 1 0 1 0 1 0]"
+		default:
+			img.colorSpace = "DeviceRGB"
+		}
+		return img, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("pdf: cannot decode image")
+	}
+	b := src.Bounds()
+	rgb := make([]byte, 0, b.Dx()*b.Dy()*3)
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	opaque := true
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(src.At(x, y)).(color.NRGBA)
+			rgb = append(rgb, c.R, c.G, c.B)
+			alpha = append(alpha, c.A)
+			if c.A != 0xFF {
+				opaque = false
+			}
+		}
+	}
+	img := &Image{width: b.Dx(), height: b.Dy(), colorSpace: "DeviceRGB", filter: "FlateDecode"}
+	if img.data, err = deflate(rgb); err != nil {
+		return nil, err
+	}
+	if !opaque {
+		if img.alpha, err = deflate(alpha); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+// Size returns the image's size in pixels.
+func (img *Image) Size() (width, height int) { return img.width, img.height }
+
+// Fit returns the largest size with the image's aspect ratio that fits in a
+// w by h box.
+func (img *Image) Fit(w, h float64) (float64, float64) {
+	iw, ih := float64(img.width), float64(img.height)
+	scale := w / iw
+	if ih*scale > h {
+		scale = h / ih
+	}
+	return iw * scale, ih * scale
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
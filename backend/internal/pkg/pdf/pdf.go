@@ -0,0 +1,318 @@
+// Package pdf lays out and writes simple business documents such as
+// invoices and receipts as PDF, without external tools. Latin text uses the
+// standard Helvetica fonts; Bangla and other text they cannot show uses an
+// embedded TrueType font. Content flows down the page from a cursor and
+// breaks onto new pages as it fills up.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// A4 portrait in points, and the page margins.
+const (
+	PageWidth    = 595.28
+	PageHeight   = 841.89
+	Margin       = 40.0
+	bottomMargin = 60.0
+)
+
+// Color is an RGB colour.
+type Color struct{ R, G, B uint8 }
+
+// Colours used by the built-in layouts.
+var (
+	Black     = Color{0, 0, 0}
+	White     = Color{255, 255, 255}
+	Gray      = Color{110, 110, 110}
+	LightGray = Color{225, 225, 225}
+	Shade     = Color{246, 246, 246}
+)
+
+// ParseHexColor parses "#RRGGBB" or "#RGB".
+func ParseHexColor(s string) (Color, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	var c Color
+	if len(s) != 6 {
+		return c, false
+	}
+	if _, err := fmt.Sscanf(s, "%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return Color{}, false
+	}
+	return c, true
+}
+
+// dark reports whether white text reads better than black on c.
+func (c Color) dark() bool {
+	return 0.299*float64(c.R)+0.587*float64(c.G)+0.114*float64(c.B) < 160
+}
+
+func (c Color) op(fill bool) string {
+	verb := "RG"
+	if fill {
+		verb = "rg"
+	}
+	return fmt.Sprintf("%s %s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255), verb)
+}
+
+// Align is the horizontal alignment of text in its box.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Style is how text is drawn. A zero size means 10pt.
+type Style struct {
+	Size  float64
+	Bold  bool
+	Color Color
+}
+
+func (s Style) size() float64 {
+	if s.Size <= 0 {
+		return 10
+	}
+	return s.Size
+}
+
+// LineHeight returns the distance between lines of text in style s.
+func LineHeight(s Style) float64 { return s.size() * 1.35 }
+
+// Options configures a document.
+type Options struct {
+	Title  string
+	Author string
+	// Font is embedded for text Helvetica cannot show, such as Bangla.
+	// BoldFont is optional; without it bold text in Font is emboldened.
+	Font     *Font
+	BoldFont *Font
+}
+
+// Document is a PDF being laid out. It is not safe for concurrent use.
+type Document struct {
+	opts     Options
+	pages    []*bytes.Buffer
+	page     *bytes.Buffer
+	y        float64
+	coreUsed [2]bool
+	glyphs   map[*Font]map[uint16][]rune
+	images   []*Image
+	header   func(d *Document)
+	footer   func(page, pages int) string
+	created  time.Time
+	finished bool
+}
+
+// New starts an empty document; content starts on the first page.
+func New(opts Options) *Document {
+	d := &Document{opts: opts, glyphs: map[*Font]map[uint16][]rune{}, created: time.Now()}
+	d.AddPage()
+	return d
+}
+
+// SetHeader sets what is drawn at the top of every later page, such as a
+// running title. It is called with the cursor at the top margin.
+func (d *Document) SetHeader(fn func(d *Document)) { d.header = fn }
+
+// SetFooter sets the footer text of each page, given the page number and
+// page count.
+func (d *Document) SetFooter(fn func(page, pages int) string) { d.footer = fn }
+
+// AddPage starts a new page.
+func (d *Document) AddPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = Margin
+	if d.header != nil && len(d.pages) > 1 {
+		d.header(d)
+	}
+}
+
+// PageCount returns the number of pages so far.
+func (d *Document) PageCount() int { return len(d.pages) }
+
+// Y returns the cursor's distance from the top of the page.
+func (d *Document) Y() float64 { return d.y }
+
+// SetY moves the cursor.
+func (d *Document) SetY(y float64) { d.y = y }
+
+// Width returns the width between the side margins.
+func (d *Document) Width() float64 { return PageWidth - 2*Margin }
+
+// Space moves the cursor down by h.
+func (d *Document) Space(h float64) { d.y += h }
+
+// EnsureSpace starts a new page unless h fits above the bottom margin, and
+// reports whether it did.
+func (d *Document) EnsureSpace(h float64) bool {
+	if d.y+h <= PageHeight-bottomMargin || d.y <= Margin {
+		return false
+	}
+	d.AddPage()
+	return true
+}
+
+// FillRect fills a rectangle whose top-left corner is at (x, y).
+func (d *Document) FillRect(x, y, w, h float64, c Color) {
+	fmt.Fprintf(d.page, "%s %s %s %s %s re f\n", c.op(true), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Line draws a straight line.
+func (d *Document) Line(x1, y1, x2, y2, width float64, c Color) {
+	fmt.Fprintf(d.page, "%s %s w %s %s m %s %s l S\n", c.op(false), num(width),
+		num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Rule draws a horizontal line across the page at the cursor.
+func (d *Document) Rule(width float64, c Color) {
+	d.Line(Margin, d.y, PageWidth-Margin, d.y, width, c)
+}
+
+// DrawImage draws img scaled into the box at (x, y).
+func (d *Document) DrawImage(img *Image, x, y, w, h float64) {
+	idx := -1
+	for i, im := range d.images {
+		if im == img {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		d.images = append(d.images, img)
+		idx = len(d.images) - 1
+	}
+	fmt.Fprintf(d.page, "q %s 0 0 %s %s %s cm /Im%d Do Q\n", num(w), num(h), num(x), num(PageHeight-y-h), idx+1)
+}
+
+// Text draws one line of text in a box of width w whose top is at y. Text
+// that does not fit is not wrapped; see Paragraph and WrapText.
+func (d *Document) Text(x, y, w float64, s string, st Style, align Align) {
+	runs := d.runs(s, st)
+	width := 0.0
+	for _, r := range runs {
+		width += r.width
+	}
+	switch align {
+	case AlignRight:
+		x += w - width
+	case AlignCenter:
+		x += (w - width) / 2
+	}
+	baseline := PageHeight - y - st.size()*0.95
+	for _, r := range runs {
+		d.drawRun(r, x, baseline, st)
+		x += r.width
+	}
+}
+
+// Paragraph draws wrapped text across the page at the cursor and moves the
+// cursor below it.
+func (d *Document) Paragraph(s string, st Style, align Align) {
+	for _, line := range d.WrapText(s, st, d.Width()) {
+		d.EnsureSpace(LineHeight(st))
+		d.Text(Margin, d.y, d.Width(), line, st, align)
+		d.y += LineHeight(st)
+	}
+}
+
+// MeasureText returns the width of s in style st.
+func (d *Document) MeasureText(s string, st Style) float64 {
+	w := 0.0
+	for _, r := range d.runs(s, st) {
+		w += r.width
+	}
+	return w
+}
+
+// WrapText breaks s into lines no wider than w, at spaces where possible.
+// Newlines in s start new lines.
+func (d *Document) WrapText(s string, st Style, w float64) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := ""
+		for _, word := range words {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if d.MeasureText(candidate, st) <= w {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+			// Break words longer than a whole line.
+			for d.MeasureText(line, st) > w {
+				rs := []rune(line)
+				cut := len(rs) - 1
+				for cut > 1 && d.MeasureText(string(rs[:cut]), st) > w {
+					cut--
+				}
+				lines = append(lines, string(rs[:cut]))
+				line = string(rs[cut:])
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Write finishes the document and writes it to w. The document cannot be
+// changed afterwards.
+func (d *Document) Write(w io.Writer) error {
+	d.finish()
+	return d.write(w)
+}
+
+// Bytes finishes the document and returns it.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// finish draws the page footers once the page count is known.
+func (d *Document) finish() {
+	if d.finished {
+		return
+	}
+	d.finished = true
+	if d.footer == nil {
+		return
+	}
+	st := Style{Size: 8, Color: Gray}
+	for i, p := range d.pages {
+		d.page = p
+		d.Line(Margin, PageHeight-40, PageWidth-Margin, PageHeight-40, 0.5, LightGray)
+		d.Text(Margin, PageHeight-34, d.Width(), d.footer(i+1, len(d.pages)), st, AlignCenter)
+	}
+}
+
+// num formats a coordinate compactly.
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
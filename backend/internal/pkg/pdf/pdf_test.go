@@ -0,0 +1,233 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// checkXref verifies that every cross-reference entry points at its object.
+func checkXref(t *testing.T, out []byte) {
+	t.Helper()
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("no startxref trailer")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	lines := strings.Split(string(out[xref:]), "\n")
+	var count int
+	fmt.Sscanf(lines[1], "0 %d", &count)
+	for id := 1; id < count; id++ {
+		entry := lines[2+id]
+		if len(entry) != 19 {
+			t.Fatalf("xref entry %d = %q, want 20 bytes with EOL", id, entry)
+		}
+		off, _ := strconv.Atoi(entry[:10])
+		if want := fmt.Sprintf("%d 0 obj", id); !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("object %d: offset %d does not start %q", id, off, want)
+		}
+	}
+}
+
+// flateStreams returns the decompressed page contents and ToUnicode maps.
+func flateStreams(t *testing.T, out []byte) []string {
+	t.Helper()
+	var streams []string
+	re := regexp.MustCompile(`<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`)
+	for _, loc := range re.FindAllSubmatchIndex(out, -1) {
+		n, _ := strconv.Atoi(string(out[loc[2]:loc[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(out[loc[1] : loc[1]+n]))
+		if err != nil {
+			t.Fatalf("content stream: %v", err)
+		}
+		data, _ := io.ReadAll(zr)
+		streams = append(streams, string(data))
+	}
+	return streams
+}
+
+// pageContents returns the decompressed page content streams.
+func pageContents(t *testing.T, out []byte) []string {
+	t.Helper()
+	var pages []string
+	for _, s := range flateStreams(t, out) {
+		if !strings.Contains(s, "begincmap") {
+			pages = append(pages, s)
+		}
+	}
+	return pages
+}
+
+func TestDocumentPagination(t *testing.T) {
+	d := New(Options{Title: "Invoice (test)"})
+	d.SetFooter(func(page, pages int) string { return fmt.Sprintf("Page %d of %d", page, pages) })
+	d.Letterhead(Brand{Name: "Kacchi Bhai", Color: Color{226, 55, 68}}, "INVOICE", []Field{{"Number", "INV-1"}})
+
+	rows := make([]Row, 120)
+	for i := range rows {
+		rows[i] = Row{Cells: []string{fmt.Sprintf("Order %d", i+1), "1,250.00"}}
+	}
+	rows = append(rows, Row{Cells: []string{"Total", "150,000.00"}, Bold: true})
+	d.Table(Table{Columns: []Column{{Title: "Item", Width: 3}, {Title: "Amount", Width: 1, Align: AlignRight}}, Rows: rows, Accent: Color{226, 55, 68}})
+
+	if d.PageCount() < 3 {
+		t.Fatalf("page count = %d, want the table to run over several pages", d.PageCount())
+	}
+	out, err := d.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.7\n")) {
+		t.Error("missing PDF header")
+	}
+	checkXref(t, out)
+	if !bytes.Contains(out, []byte(fmt.Sprintf("/Count %d", d.PageCount()))) {
+		t.Errorf("page tree does not count %d pages", d.PageCount())
+	}
+	if !bytes.Contains(out, []byte("/Title (Invoice \\(test\\))")) {
+		t.Error("title not escaped in the document info")
+	}
+
+	pages := pageContents(t, out)
+	if len(pages) != d.PageCount() {
+		t.Fatalf("found %d content streams, want %d", len(pages), d.PageCount())
+	}
+	last := pages[len(pages)-1]
+	if !strings.Contains(last, fmt.Sprintf("(Page %d of %d) Tj", d.PageCount(), d.PageCount())) {
+		t.Error("last page has no page-count footer")
+	}
+	// The table header is repeated on every continuation page.
+	for i, p := range pages[1:] {
+		if !strings.Contains(p, "(Amount) Tj") {
+			t.Errorf("page %d has no table header", i+2)
+		}
+	}
+}
+
+func TestEmbeddedFontText(t *testing.T) {
+	f := testFont(t)
+	d := New(Options{Title: "রসিদ", Font: f})
+	d.Paragraph("Total ক্ষি", Style{Size: 12}, AlignLeft)
+	d.Paragraph("ক", Style{Size: 12, Bold: true}, AlignLeft)
+	out, err := d.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	checkXref(t, out)
+
+	for _, want := range []string{"/Subtype /Type0", "/Encoding /Identity-H", "/CIDToGIDMap /Identity", "/FontFile2", "/Title <FEFF"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("output lacks %q", want)
+		}
+	}
+	pages := pageContents(t, out)
+	content := pages[0]
+	if !strings.Contains(content, "/F1 12 Tf") || !strings.Contains(content, "(Total ) Tj") {
+		t.Errorf("Latin text should use Helvetica:\n%s", content)
+	}
+	if !strings.Contains(content, fmt.Sprintf("<%04X%04X> Tj", gI, gKssa)) {
+		t.Errorf("Bangla text should be shaped glyph IDs:\n%s", content)
+	}
+	if !strings.Contains(content, "2 Tr") {
+		t.Error("bold Bangla without a bold face should be emboldened")
+	}
+
+	var cmap string
+	for _, s := range flateStreams(t, out) {
+		if strings.Contains(s, "beginbfchar") {
+			cmap = s
+		}
+	}
+	if !strings.Contains(cmap, fmt.Sprintf("<%04X> <099509CD09B7>", gKssa)) {
+		t.Errorf("ToUnicode does not map the conjunct back to its text:\n%s", cmap)
+	}
+}
+
+func TestUnencodableTextWithoutFont(t *testing.T) {
+	d := New(Options{})
+	d.Text(Margin, Margin, d.Width(), "৳ 100 – café", Style{}, AlignLeft)
+	out, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content := pageContents(t, out)[0]; !strings.Contains(content, `(? 100 \226 caf\351) Tj`) {
+		t.Errorf("content = %s", content)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	d := New(Options{})
+	st := Style{Size: 10}
+	lines := d.WrapText("Packaging credit for the week of the fourteenth\nsecond line", st, 120)
+	if len(lines) < 3 || lines[len(lines)-1] != "second line" {
+		t.Fatalf("lines = %q", lines)
+	}
+	for _, l := range lines {
+		if w := d.MeasureText(l, st); w > 120 {
+			t.Errorf("line %q is %.1f wide", l, w)
+		}
+	}
+	long := d.WrapText(strings.Repeat("W", 40), st, 100)
+	if len(long) < 2 {
+		t.Errorf("an overlong word should be broken, got %q", long)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	cases := map[string]Color{"#E23744": {0xE2, 0x37, 0x44}, "fff": {255, 255, 255}, " #000000 ": {0, 0, 0}}
+	for in, want := range cases {
+		if got, ok := ParseHexColor(in); !ok || got != want {
+			t.Errorf("ParseHexColor(%q) = %v, %v", in, got, ok)
+		}
+	}
+	for _, bad := range []string{"", "#12345", "#GGGGGG"} {
+		if _, ok := ParseHexColor(bad); ok {
+			t.Errorf("ParseHexColor(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	src.Set(0, 0, color.NRGBA{255, 0, 0, 128})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	img, err := ParseImage(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseImage: %v", err)
+	}
+	if w, h := img.Size(); w != 4 || h != 2 || img.alpha == nil || img.filter != "FlateDecode" {
+		t.Errorf("image = %dx%d alpha=%v filter=%s", w, h, img.alpha != nil, img.filter)
+	}
+	if w, h := img.Fit(54, 54); w != 54 || h != 27 {
+		t.Errorf("Fit = %v x %v, want 54 x 27", w, h)
+	}
+
+	d := New(Options{})
+	d.DrawImage(img, Margin, Margin, 54, 27)
+	out, err := d.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkXref(t, out)
+	if !bytes.Contains(out, []byte("/SMask")) {
+		t.Error("transparent image has no soft mask")
+	}
+
+	if _, err := ParseImage([]byte("<svg/>")); err == nil {
+		t.Error("SVG should be rejected")
+	}
+}
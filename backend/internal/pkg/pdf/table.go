@@ -0,0 +1,110 @@
+package pdf
+
+// Column is a table column. Width is its share of the table width relative
+// to the other columns.
+type Column struct {
+	Title string
+	Width float64
+	Align Align
+}
+
+// Row is a table row. Bold rows, such as totals, are ruled off from the
+// rows above.
+type Row struct {
+	Cells []string
+	Bold  bool
+}
+
+// Table is a grid of wrapped text spanning the page. The header row is
+// repeated when the table continues on a new page.
+type Table struct {
+	Columns  []Column
+	Rows     []Row
+	Accent   Color // header background
+	FontSize float64
+}
+
+const cellPadding = 4.0
+
+// Table draws t at the cursor and moves the cursor below it.
+func (d *Document) Table(t Table) {
+	if len(t.Columns) == 0 {
+		return
+	}
+	size := t.FontSize
+	if size <= 0 {
+		size = 9
+	}
+
+	total := 0.0
+	for _, c := range t.Columns {
+		total += c.Width
+	}
+	widths := make([]float64, len(t.Columns))
+	for i, c := range t.Columns {
+		if total > 0 {
+			widths[i] = d.Width() * c.Width / total
+		} else {
+			widths[i] = d.Width() / float64(len(t.Columns))
+		}
+	}
+
+	headerText := Black
+	if t.Accent.dark() {
+		headerText = White
+	}
+	header := Row{Bold: true}
+	for _, c := range t.Columns {
+		header.Cells = append(header.Cells, c.Title)
+	}
+	drawHeader := func() {
+		st := Style{Size: size, Bold: true, Color: headerText}
+		h := d.rowHeight(header, widths, st)
+		d.FillRect(Margin, d.y, d.Width(), h, t.Accent)
+		d.drawRow(header, t.Columns, widths, st)
+	}
+
+	d.EnsureSpace(4 * LineHeight(Style{Size: size}))
+	drawHeader()
+	for i, row := range t.Rows {
+		st := Style{Size: size, Bold: row.Bold}
+		h := d.rowHeight(row, widths, st)
+		if d.EnsureSpace(h) {
+			drawHeader()
+		}
+		if row.Bold {
+			d.Rule(0.8, Gray)
+		} else if i%2 == 1 {
+			d.FillRect(Margin, d.y, d.Width(), h, Shade)
+		}
+		d.drawRow(row, t.Columns, widths, st)
+	}
+	d.Rule(0.5, LightGray)
+}
+
+func (d *Document) rowHeight(row Row, widths []float64, st Style) float64 {
+	lines := 1
+	for i, cell := range row.Cells {
+		if i < len(widths) {
+			lines = max(lines, len(d.WrapText(cell, st, widths[i]-2*cellPadding)))
+		}
+	}
+	return float64(lines)*LineHeight(st) + 2*cellPadding
+}
+
+// drawRow draws a row's cells at the cursor and moves the cursor below it.
+func (d *Document) drawRow(row Row, cols []Column, widths []float64, st Style) {
+	h := d.rowHeight(row, widths, st)
+	x := Margin
+	for i, w := range widths {
+		if i < len(row.Cells) {
+			y := d.y + cellPadding
+			for _, line := range d.WrapText(row.Cells[i], st, w-2*cellPadding) {
+				d.Text(x+cellPadding, y, w-2*cellPadding, line, st, cols[i].Align)
+				y += LineHeight(st)
+			}
+		}
+		x += w
+	}
+	d.y += h
+}
@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// run is a stretch of text drawn in one font: Helvetica when font is nil,
+// otherwise shaped glyphs of an embedded font.
+type run struct {
+	text   []rune
+	font   *Font
+	glyphs []glyphInfo
+	width  float64
+}
+
+// fontFor picks the embedded font for r, or nil for Helvetica. Bangla always
+// uses the embedded font; other text does when Helvetica cannot encode it.
+func (d *Document) fontFor(r rune, bold bool) *Font {
+	f := d.opts.Font
+	if bold && d.opts.BoldFont != nil {
+		f = d.opts.BoldFont
+	}
+	if f == nil {
+		return nil
+	}
+	if isBengali(r) || r == zwj || r == zwnj {
+		return f
+	}
+	if _, ok := winAnsi(r); ok {
+		return nil
+	}
+	if f.HasRune(r) {
+		return f
+	}
+	return nil
+}
+
+// runs splits s into font runs and measures them.
+func (d *Document) runs(s string, st Style) []run {
+	var out []run
+	for _, r := range s {
+		f := d.fontFor(r, st.Bold)
+		if n := len(out); n > 0 && out[n-1].font == f {
+			out[n-1].text = append(out[n-1].text, r)
+			continue
+		}
+		out = append(out, run{text: []rune{r}, font: f})
+	}
+
+	size := st.size()
+	for i := range out {
+		r := &out[i]
+		if r.font == nil {
+			for _, c := range r.text {
+				if _, ok := winAnsi(c); !ok {
+					c = '?'
+				}
+				r.width += coreWidth(c, st.Bold) * size / 1000
+			}
+			continue
+		}
+		r.glyphs = r.font.shape(r.text)
+		for _, g := range r.glyphs {
+			r.width += r.font.advance(g.id) * size / 1000
+		}
+	}
+	return out
+}
+
+// drawRun writes a run at a baseline given in PDF coordinates.
+func (d *Document) drawRun(r run, x, baseline float64, st Style) {
+	size := st.size()
+	if r.font == nil {
+		name := "F1"
+		if st.Bold {
+			name = "F2"
+			d.coreUsed[1] = true
+		} else {
+			d.coreUsed[0] = true
+		}
+		fmt.Fprintf(d.page, "BT %s /%s %s Tf %s %s Td (%s) Tj ET\n",
+			st.Color.op(true), name, num(size), num(x), num(baseline), escapeWinAnsi(r.text))
+		return
+	}
+
+	used := d.glyphs[r.font]
+	if used == nil {
+		used = map[uint16][]rune{}
+		d.glyphs[r.font] = used
+	}
+	var hex strings.Builder
+	for _, g := range r.glyphs {
+		if _, ok := used[g.id]; !ok {
+			used[g.id] = g.text
+		}
+		fmt.Fprintf(&hex, "%04X", g.id)
+	}
+
+	// Without a bold face, bold is drawn by also stroking the outline.
+	// The render mode is graphics state, so it is saved and restored.
+	if st.Bold && r.font != d.opts.BoldFont {
+		fmt.Fprintf(d.page, "q %s %s w BT 2 Tr %s /%s %s Tf %s %s Td <%s> Tj ET Q\n",
+			st.Color.op(false), num(size*0.03), st.Color.op(true), d.fontName(r.font),
+			num(size), num(x), num(baseline), hex.String())
+		return
+	}
+	fmt.Fprintf(d.page, "BT %s /%s %s Tf %s %s Td <%s> Tj ET\n",
+		st.Color.op(true), d.fontName(r.font), num(size), num(x), num(baseline), hex.String())
+}
+
+// fontName returns the resource name of an embedded font.
+func (d *Document) fontName(f *Font) string {
+	if f == d.opts.BoldFont && f != d.opts.Font {
+		return "F4"
+	}
+	return "F3"
+}
+
+// escapeWinAnsi encodes text as a PDF literal string in WinAnsiEncoding.
+func escapeWinAnsi(text []rune) string {
+	var sb strings.Builder
+	for _, r := range text {
+		c, ok := winAnsi(r)
+		if !ok {
+			c = '?'
+		}
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x80:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "\\%03o", c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,261 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sfnt is raw font data. Reads past the end return zero so a truncated or
+// malformed font degrades instead of panicking.
+type sfnt []byte
+
+func (b sfnt) u8(off int) uint8 {
+	if off < 0 || off >= len(b) {
+		return 0
+	}
+	return b[off]
+}
+
+func (b sfnt) u16(off int) uint16 {
+	if off < 0 || off+2 > len(b) {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b[off:])
+}
+
+func (b sfnt) i16(off int) int16 { return int16(b.u16(off)) }
+
+func (b sfnt) u32(off int) uint32 {
+	if off < 0 || off+4 > len(b) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b[off:])
+}
+
+// Font is a TrueType font embedded in documents for text the standard PDF
+// fonts cannot show, such as Bangla. It is immutable once loaded and can be
+// shared by concurrent documents.
+type Font struct {
+	name       string
+	data       []byte
+	unitsPerEm float64
+	bbox       [4]int16
+	ascent     int16
+	descent    int16
+	capHeight  int16
+	advances   []uint16
+	cmap       map[rune]uint16
+	gsub       *gsubTable
+}
+
+// LoadFont reads a TrueType (.ttf) font file.
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pdf: read font: %w", err)
+	}
+	return ParseFont(data)
+}
+
+// ParseFont parses TrueType font data. CFF-flavoured OpenType fonts are not
+// supported.
+func ParseFont(data []byte) (*Font, error) {
+	b := sfnt(data)
+	switch b.u32(0) {
+	case 0x00010000, 0x74727565: // 1.0, 'true'
+	case 0x4F54544F: // 'OTTO'
+		return nil, errors.New("pdf: CFF (OTTO) fonts are not supported, use a TrueType font")
+	default:
+		return nil, errors.New("pdf: not a TrueType font")
+	}
+
+	tables := map[string]sfnt{}
+	n := int(b.u16(4))
+	if 12+16*n > len(data) {
+		return nil, errors.New("pdf: font table directory is truncated")
+	}
+	for i := 0; i < n; i++ {
+		rec := 12 + 16*i
+		tag := string(data[rec : rec+4])
+		off, length := int(b.u32(rec+8)), int(b.u32(rec+12))
+		if off+length > len(data) {
+			return nil, fmt.Errorf("pdf: font table %q is truncated", tag)
+		}
+		tables[tag] = b[off : off+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "cmap"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("pdf: font has no %s table", tag)
+		}
+	}
+
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	f := &Font{
+		data:       data,
+		unitsPerEm: float64(head.u16(18)),
+		bbox:       [4]int16{head.i16(36), head.i16(38), head.i16(40), head.i16(42)},
+		ascent:     hhea.i16(4),
+		descent:    hhea.i16(6),
+	}
+	if f.unitsPerEm == 0 {
+		return nil, errors.New("pdf: font has no units per em")
+	}
+	f.capHeight = f.ascent
+	if os2 := tables["OS/2"]; os2 != nil && os2.u16(0) >= 2 {
+		f.capHeight = os2.i16(88)
+	}
+
+	numGlyphs := int(maxp.u16(4))
+	numMetrics := int(hhea.u16(34))
+	hmtx := tables["hmtx"]
+	f.advances = make([]uint16, numGlyphs)
+	var last uint16
+	for g := 0; g < numGlyphs; g++ {
+		if g < numMetrics {
+			last = hmtx.u16(4 * g)
+		}
+		f.advances[g] = last
+	}
+
+	cmap, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.cmap = cmap
+	f.name = postScriptName(tables["name"])
+	if gsub := tables["GSUB"]; gsub != nil {
+		f.gsub = parseGSUB(gsub, parseGlyphClasses(tables["GDEF"]))
+	}
+	return f, nil
+}
+
+// Name returns the font's PostScript name.
+func (f *Font) Name() string { return f.name }
+
+// HasRune reports whether the font has a glyph for r.
+func (f *Font) HasRune(r rune) bool {
+	_, ok := f.cmap[r]
+	return ok
+}
+
+// glyph returns the glyph for r, or the .notdef glyph.
+func (f *Font) glyph(r rune) uint16 { return f.cmap[r] }
+
+// advance returns a glyph's width in thousandths of the font size.
+func (f *Font) advance(g uint16) float64 {
+	if int(g) >= len(f.advances) {
+		return 0
+	}
+	return float64(f.advances[g]) * 1000 / f.unitsPerEm
+}
+
+// scaled converts font units to thousandths of the font size.
+func (f *Font) scaled(v int16) int {
+	return int(float64(v) * 1000 / f.unitsPerEm)
+}
+
+// parseCmap reads the Unicode mapping, preferring the full-repertoire
+// format 12 subtable over the BMP-only format 4 one.
+func parseCmap(t sfnt) (map[rune]uint16, error) {
+	best, bestScore := -1, 0
+	n := int(t.u16(2))
+	for i := 0; i < n; i++ {
+		rec := 4 + 8*i
+		platform, encoding := t.u16(rec), t.u16(rec+2)
+		off := int(t.u32(rec + 4))
+		score := 0
+		switch format := t.u16(off); {
+		case format == 12 && (platform == 3 && encoding == 10 || platform == 0):
+			score = 3
+		case format == 4 && platform == 3 && encoding == 1:
+			score = 2
+		case format == 4 && platform == 0:
+			score = 1
+		}
+		if score > bestScore {
+			best, bestScore = off, score
+		}
+	}
+	if best < 0 {
+		return nil, errors.New("pdf: font has no Unicode cmap")
+	}
+
+	m := map[rune]uint16{}
+	if t.u16(best) == 12 {
+		groups := int(t.u32(best + 12))
+		for i := 0; i < groups; i++ {
+			g := best + 16 + 12*i
+			start, end, gid := t.u32(g), t.u32(g+4), t.u32(g+8)
+			for c := start; c <= end && end-start < 0x110000; c++ {
+				m[rune(c)] = uint16(gid + c - start)
+			}
+		}
+		return m, nil
+	}
+
+	segs := int(t.u16(best+6)) / 2
+	ends := best + 14
+	starts := ends + 2*segs + 2
+	deltas := starts + 2*segs
+	ranges := deltas + 2*segs
+	for s := 0; s < segs; s++ {
+		start, end := t.u16(starts+2*s), t.u16(ends+2*s)
+		delta, rangeOff := t.u16(deltas+2*s), t.u16(ranges+2*s)
+		for c := uint32(start); c <= uint32(end) && c != 0xFFFF; c++ {
+			var gid uint16
+			if rangeOff == 0 {
+				gid = uint16(c) + delta
+			} else {
+				at := ranges + 2*s + int(rangeOff) + 2*int(c-uint32(start))
+				if gid = t.u16(at); gid != 0 {
+					gid += delta
+				}
+			}
+			if gid != 0 {
+				m[rune(c)] = gid
+			}
+		}
+	}
+	return m, nil
+}
+
+// postScriptName returns name ID 6, falling back to a generic name.
+func postScriptName(t sfnt) string {
+	n := int(t.u16(2))
+	strOff := int(t.u16(4))
+	for i := 0; i < n; i++ {
+		rec := 6 + 12*i
+		platform, nameID := t.u16(rec), t.u16(rec+6)
+		length, off := int(t.u16(rec+8)), int(t.u16(rec+10))
+		if nameID != 6 || strOff+off+length > len(t) {
+			continue
+		}
+		raw := t[strOff+off : strOff+off+length]
+		var sb strings.Builder
+		if platform == 3 || platform == 0 {
+			for j := 0; j+1 < len(raw); j += 2 {
+				sb.WriteByte(raw[j+1])
+			}
+		} else {
+			sb.Write(raw)
+		}
+		if name := sanitizeName(sb.String()); name != "" {
+			return name
+		}
+	}
+	return "EmbeddedFont"
+}
+
+// sanitizeName keeps the characters allowed in a PDF name without escaping.
+func sanitizeName(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		if c > ' ' && c < 0x7F && !strings.ContainsRune("()<>[]{}/%#", c) {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
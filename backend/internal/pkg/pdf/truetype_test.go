@@ -0,0 +1,197 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+// fontBuilder assembles big-endian font tables for tests.
+type fontBuilder []byte
+
+func (b *fontBuilder) u16(vs ...int) *fontBuilder {
+	for _, v := range vs {
+		*b = binary.BigEndian.AppendUint16(*b, uint16(v))
+	}
+	return b
+}
+
+func (b *fontBuilder) u32(v uint32) *fontBuilder {
+	*b = binary.BigEndian.AppendUint32(*b, v)
+	return b
+}
+
+func (b *fontBuilder) tag(s string) *fontBuilder {
+	*b = append(*b, s...)
+	return b
+}
+
+// Glyphs of the test font.
+const (
+	gKa = 1 + iota
+	gHalant
+	gSsa
+	gI
+	gLatinA
+	gRa
+	gKssa = 10
+)
+
+// testFont builds a TrueType font mapping ক ্ ষ ি A র, with a GSUB akhn
+// ligature ক্ষ and a rphf ligature র্ for Bengali.
+func testFont(t *testing.T) *Font {
+	t.Helper()
+	const numGlyphs = 12
+
+	var head fontBuilder
+	head.u32(0x00010000).u32(0).u32(0).u32(0x5F0F3CF5).u16(0, 1000)
+	head = append(head, make([]byte, 16)...) // created, modified
+	head.u16(0xFFCE, 0xFF38, 1000, 900)      // bbox -50 -200 1000 900
+	head.u16(0, 8, 2, 0, 0)
+
+	var hhea fontBuilder
+	hhea.u32(0x00010000).u16(800, 0xFF38, 0)
+	hhea = append(hhea, make([]byte, 24)...)
+	hhea.u16(numGlyphs)
+
+	var maxp fontBuilder
+	maxp.u32(0x00005000).u16(numGlyphs)
+
+	var hmtx fontBuilder
+	for g := 0; g < numGlyphs; g++ {
+		adv := 500
+		switch g {
+		case gHalant:
+			adv = 0
+		case gI:
+			adv = 250
+		case gKssa:
+			adv = 900
+		}
+		hmtx.u16(adv, 0)
+	}
+
+	chars := []struct{ r, g int }{{'A', gLatinA}, {'ক', gKa}, {'র', gRa}, {'ষ', gSsa}, {'ি', gI}, {'্', gHalant}}
+	segs := len(chars) + 1
+	var sub fontBuilder
+	sub.u16(4, 16+8*segs, 0, 2*segs, 0, 0, 0)
+	for _, c := range chars {
+		sub.u16(c.r)
+	}
+	sub.u16(0xFFFF, 0)
+	for _, c := range chars {
+		sub.u16(c.r)
+	}
+	sub.u16(0xFFFF)
+	for _, c := range chars {
+		sub.u16((c.g - c.r) & 0xFFFF)
+	}
+	sub.u16(1)
+	for range segs {
+		sub.u16(0)
+	}
+	var cmap fontBuilder
+	cmap.u16(0, 1, 3, 1).u32(12)
+	cmap = append(cmap, sub...)
+
+	// GSUB: bng2 → features akhn (lookup 0) and rphf (lookup 1).
+	var gsub fontBuilder
+	gsub.u32(0x00010000).u16(10, 32, 58)
+	// ScriptList at 10: one script, default LangSys with features 0 and 1.
+	gsub.u16(1).tag("bng2").u16(8)
+	gsub.u16(4, 0)               // Script
+	gsub.u16(0, 0xFFFF, 2, 0, 1) // LangSys
+	// FeatureList at 32.
+	gsub.u16(2).tag("akhn").u16(14).tag("rphf").u16(20)
+	gsub.u16(0, 1, 0)
+	gsub.u16(0, 1, 1)
+	// LookupList at 58.
+	gsub.u16(2, 6, 40)
+	// Lookup 0 at 64: ligature ক ্ ষ → ক্ষ.
+	gsub.u16(4, 0, 1, 8)
+	gsub.u16(1, 8, 1, 14) // LigatureSubst
+	gsub.u16(1, 1, gKa)   // Coverage
+	gsub.u16(1, 4)        // LigatureSet
+	gsub.u16(gKssa, 3, gHalant, gSsa)
+	// Lookup 1 at 98: ligature র ্ → reph.
+	gsub.u16(4, 0, 1, 8)
+	gsub.u16(1, 8, 1, 14)
+	gsub.u16(1, 1, gRa)
+	gsub.u16(1, 4)
+	gsub.u16(11, 2, gHalant)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"GSUB", gsub}, {"cmap", cmap}, {"head", head}, {"hhea", hhea}, {"hmtx", hmtx}, {"maxp", maxp}}
+	var out fontBuilder
+	out.u32(0x00010000).u16(len(tables), 0, 0, 0)
+	off := 12 + 16*len(tables)
+	for _, tb := range tables {
+		out.tag(tb.tag).u32(0).u32(uint32(off)).u32(uint32(len(tb.data)))
+		off += len(tb.data)
+	}
+	for _, tb := range tables {
+		out = append(out, tb.data...)
+	}
+
+	f, err := ParseFont(out)
+	if err != nil {
+		t.Fatalf("ParseFont: %v", err)
+	}
+	return f
+}
+
+func glyphIDs(gs []glyphInfo) []uint16 {
+	ids := make([]uint16, len(gs))
+	for i, g := range gs {
+		ids[i] = g.id
+	}
+	return ids
+}
+
+func TestParseFont(t *testing.T) {
+	f := testFont(t)
+	if f.unitsPerEm != 1000 || f.ascent != 800 || f.descent != -200 {
+		t.Errorf("metrics = %v/%d/%d", f.unitsPerEm, f.ascent, f.descent)
+	}
+	if !f.HasRune('ক') || f.glyph('A') != gLatinA || f.HasRune('B') {
+		t.Errorf("cmap = %v", f.cmap)
+	}
+	if got := f.advance(gKssa); got != 900 {
+		t.Errorf("advance(ক্ষ) = %v, want 900", got)
+	}
+	if f.bengaliScript() != "bng2" {
+		t.Errorf("script = %q, want bng2", f.bengaliScript())
+	}
+
+	if _, err := ParseFont([]byte("OTTO....")); err == nil {
+		t.Error("CFF fonts should be rejected")
+	}
+	if _, err := ParseFont([]byte("not a font")); err == nil {
+		t.Error("garbage should be rejected")
+	}
+}
+
+func TestShapeConjunctAndPreBaseVowel(t *testing.T) {
+	f := testFont(t)
+	got := f.shape([]rune("Aক্ষি"))
+	if want := []uint16{gLatinA, gI, gKssa}; !slices.Equal(glyphIDs(got), want) {
+		t.Fatalf("glyphs = %v, want %v", glyphIDs(got), want)
+	}
+	if string(got[2].text) != "ক্ষ" {
+		t.Errorf("ligature text = %q, want ক্ষ", string(got[2].text))
+	}
+}
+
+func TestShapeReph(t *testing.T) {
+	f := testFont(t)
+	// র্ক: the reph follows the base and only the leading র্ forms it.
+	if got := glyphIDs(f.shape([]rune("র্ক"))); !slices.Equal(got, []uint16{gKa, 11}) {
+		t.Errorf("র্ক = %v, want [%d 11]", got, gKa)
+	}
+	// ক্র: র after a virama is a below-base form, not a reph.
+	if got := glyphIDs(f.shape([]rune("ক্র"))); !slices.Equal(got, []uint16{gKa, gHalant, gRa}) {
+		t.Errorf("ক্র = %v", got)
+	}
+}
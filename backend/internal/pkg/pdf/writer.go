@@ -0,0 +1,239 @@
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfWriter writes numbered objects and remembers their offsets for the
+// cross-reference table.
+type pdfWriter struct {
+	w       *bufio.Writer
+	n       int64
+	offsets map[int]int64
+	next    int
+	err     error
+}
+
+func (p *pdfWriter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(p.w, format, args...)
+	p.n += int64(n)
+	p.err = err
+}
+
+func (p *pdfWriter) raw(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	p.err = err
+}
+
+// alloc reserves an object number.
+func (p *pdfWriter) alloc() int {
+	p.next++
+	return p.next
+}
+
+func (p *pdfWriter) object(id int, body string) {
+	p.offsets[id] = p.n
+	p.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (p *pdfWriter) stream(id int, dict string, data []byte) {
+	p.offsets[id] = p.n
+	p.printf("%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+	p.raw(data)
+	p.printf("\nendstream\nendobj\n")
+}
+
+func (d *Document) write(out io.Writer) error {
+	p := &pdfWriter{w: bufio.NewWriter(out), offsets: map[int]int64{}}
+	p.printf("%%PDF-1.7\n%%\xe2\xe3\xcf\xd3\n")
+
+	catalog, pagesID, info, resources := p.alloc(), p.alloc(), p.alloc(), p.alloc()
+
+	// Fonts.
+	var fonts []string
+	for i, name := range []string{"Helvetica", "Helvetica-Bold"} {
+		if !d.coreUsed[i] {
+			continue
+		}
+		id := p.alloc()
+		p.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fonts = append(fonts, fmt.Sprintf("/F%d %d 0 R", i+1, id))
+	}
+	embedded := []*Font{d.opts.Font}
+	if d.opts.BoldFont != d.opts.Font {
+		embedded = append(embedded, d.opts.BoldFont)
+	}
+	for _, f := range embedded {
+		used := d.glyphs[f]
+		if f == nil || len(used) == 0 {
+			continue
+		}
+		id, err := d.writeFont(p, f, used)
+		if err != nil {
+			return err
+		}
+		fonts = append(fonts, fmt.Sprintf("/%s %d 0 R", d.fontName(f), id))
+	}
+
+	// Images.
+	var xobjects []string
+	for i, img := range d.images {
+		id := p.alloc()
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s",
+			img.width, img.height, img.colorSpace, img.filter)
+		if img.decode != "" {
+			dict += " /Decode " + img.decode
+		}
+		if img.alpha != nil {
+			mask := p.alloc()
+			p.stream(mask, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode",
+				img.width, img.height), img.alpha)
+			dict += fmt.Sprintf(" /SMask %d 0 R", mask)
+		}
+		p.stream(id, dict, img.data)
+		xobjects = append(xobjects, fmt.Sprintf("/Im%d %d 0 R", i+1, id))
+	}
+
+	p.object(resources, fmt.Sprintf("<< /ProcSet [/PDF /Text /ImageB /ImageC] /Font << %s >> /XObject << %s >> >>",
+		strings.Join(fonts, " "), strings.Join(xobjects, " ")))
+
+	// Pages.
+	var kids []string
+	for _, content := range d.pages {
+		pageID, contentID := p.alloc(), p.alloc()
+		data, err := deflate(content.Bytes())
+		if err != nil {
+			return err
+		}
+		p.stream(contentID, "/Filter /FlateDecode", data)
+		p.object(pageID, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %d 0 R /Contents %d 0 R >>",
+			pagesID, num(PageWidth), num(PageHeight), resources, contentID))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+	p.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	p.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+	p.object(info, fmt.Sprintf("<< /Title %s /Author %s /CreationDate (D:%s) >>",
+		textString(d.opts.Title), textString(d.opts.Author), d.created.UTC().Format("20060102150405Z")))
+
+	// Cross-reference table.
+	xref := p.n
+	p.printf("xref\n0 %d\n0000000000 65535 f \n", p.next+1)
+	for id := 1; id <= p.next; id++ {
+		p.printf("%010d 00000 n \n", p.offsets[id])
+	}
+	p.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.next+1, catalog, info, xref)
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+// writeFont embeds a TrueType font as a CID-keyed Type 0 font addressed by
+// glyph ID, with widths and a ToUnicode map for the glyphs used so text can
+// be searched and copied.
+func (d *Document) writeFont(p *pdfWriter, f *Font, used map[uint16][]rune) (int, error) {
+	font, cid, descriptor, file, toUnicode := p.alloc(), p.alloc(), p.alloc(), p.alloc(), p.alloc()
+
+	gids := make([]int, 0, len(used))
+	for g := range used {
+		gids = append(gids, int(g))
+	}
+	sort.Ints(gids)
+
+	var widths strings.Builder
+	for _, g := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", g, int(f.advance(uint16(g))+0.5))
+	}
+
+	data, err := deflate(f.data)
+	if err != nil {
+		return 0, err
+	}
+	p.stream(file, fmt.Sprintf("/Filter /FlateDecode /Length1 %d", len(f.data)), data)
+	p.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.name, f.scaled(f.bbox[0]), f.scaled(f.bbox[1]), f.scaled(f.bbox[2]), f.scaled(f.bbox[3]),
+		f.scaled(f.ascent), f.scaled(f.descent), f.scaled(f.capHeight), file))
+	p.object(cid, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW 1000 /W [%s] /CIDToGIDMap /Identity >>",
+		f.name, descriptor, strings.TrimSpace(widths.String())))
+
+	cmap := toUnicodeCMap(gids, used)
+	cmapData, err := deflate([]byte(cmap))
+	if err != nil {
+		return 0, err
+	}
+	p.stream(toUnicode, "/Filter /FlateDecode", cmapData)
+	p.object(font, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		f.name, cid, toUnicode))
+	return font, nil
+}
+
+func toUnicodeCMap(gids []int, used map[uint16][]rune) string {
+	var sb strings.Builder
+	sb.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	var entries []string
+	for _, g := range gids {
+		text := used[uint16(g)]
+		if len(text) == 0 {
+			continue
+		}
+		var hex strings.Builder
+		for _, u := range utf16.Encode(text) {
+			fmt.Fprintf(&hex, "%04X", u)
+		}
+		entries = append(entries, fmt.Sprintf("<%04X> <%s>", g, hex.String()))
+	}
+	for len(entries) > 0 {
+		n := min(len(entries), 100)
+		fmt.Fprintf(&sb, "%d beginbfchar\n%s\nendbfchar\n", n, strings.Join(entries[:n], "\n"))
+		entries = entries[n:]
+	}
+	sb.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return sb.String()
+}
+
+// textString encodes s as a PDF text string, in UTF-16 when it is not ASCII.
+func textString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		var sb bytes.Buffer
+		sb.WriteByte('(')
+		for i := 0; i < len(s); i++ {
+			if c := s[i]; c == '(' || c == ')' || c == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteByte(s[i])
+		}
+		sb.WriteByte(')')
+		return sb.String()
+	}
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
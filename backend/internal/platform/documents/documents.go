@@ -0,0 +1,236 @@
+// Package documents prepares tenant-branded PDF documents: it loads the
+// fonts for Bangla text once and fetches and caches tenant logos.
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/pdf"
+	"github.com/munchies/platform/backend/internal/pkg/timeutil"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// Config holds the font files used for text Helvetica cannot show.
+type Config struct {
+	BanglaFont     string
+	BanglaBoldFont string
+}
+
+const (
+	maxLogoBytes = 2 << 20
+	logoTTL      = time.Hour
+	logoRetry    = 10 * time.Minute // how long a failed logo is not refetched
+)
+
+// defaultColor brands documents of tenants without a usable primary colour.
+var defaultColor = pdf.Color{R: 0xE2, G: 0x37, B: 0x44}
+
+// Kit creates branded documents. It is safe for concurrent use.
+type Kit struct {
+	font   *pdf.Font
+	bold   *pdf.Font
+	client *http.Client
+
+	mu    sync.Mutex
+	logos map[string]cachedLogo
+}
+
+type cachedLogo struct {
+	img     *pdf.Image // nil if the logo could not be used
+	expires time.Time
+}
+
+// New loads the configured fonts. A missing font is logged and Bangla text
+// is then rendered as placeholders rather than failing documents; callers
+// that cannot accept that check HasBanglaFont.
+func New(cfg Config) *Kit {
+	k := &Kit{
+		client: newLogoClient(),
+		logos:  map[string]cachedLogo{},
+	}
+	k.font = loadFont(cfg.BanglaFont)
+	if k.font != nil {
+		k.bold = loadFont(cfg.BanglaBoldFont)
+	}
+	return k
+}
+
+// newLogoClient returns the client logos are fetched with. Logo URLs are set
+// by tenants, so it only speaks HTTPS, ignores proxy settings and refuses to
+// connect to loopback, private or link-local addresses, checked after DNS
+// resolution and again on every redirect.
+func newLogoClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: refuseInternal}
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return errors.New("logo redirected off https")
+			}
+			if len(via) >= 3 {
+				return errors.New("too many logo redirects")
+			}
+			return nil
+		},
+	}
+}
+
+// refuseInternal is a net.Dialer Control hook that fails connections to
+// addresses outside the public internet.
+func refuseInternal(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(ap.Addr()) {
+		return fmt.Errorf("logo host %s is not a public address", ap.Addr())
+	}
+	return nil
+}
+
+// publicAddr reports whether ip is a public unicast address.
+func publicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598).
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// HasBanglaFont reports whether the regular Bangla font was loaded.
+func (k *Kit) HasBanglaFont() bool {
+	return k.font != nil
+}
+
+func loadFont(path string) *pdf.Font {
+	if path == "" {
+		return nil
+	}
+	f, err := pdf.LoadFont(path)
+	if err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("documents: font not loaded, Bangla text will not render")
+		return nil
+	}
+	return f
+}
+
+// Document starts a document with the kit's fonts and a page-count footer.
+func (k *Kit) Document(title, author string) *pdf.Document {
+	d := pdf.New(pdf.Options{Title: title, Author: author, Font: k.font, BoldFont: k.bold})
+	d.SetFooter(func(page, pages int) string {
+		return fmt.Sprintf("%s  ·  Page %d of %d", author, page, pages)
+	})
+	return d
+}
+
+// Brand returns the letterhead identity of a tenant: its name, primary
+// colour, logo and contact lines. A logo that cannot be fetched is left out.
+func (k *Kit) Brand(ctx context.Context, t *sqlc.Tenant) pdf.Brand {
+	b := pdf.Brand{Name: t.Name, Color: defaultColor}
+	if c, ok := pdf.ParseHexColor(t.PrimaryColor); ok {
+		b.Color = c
+	}
+	if t.LogoUrl.Valid {
+		b.Logo = k.logo(ctx, t.LogoUrl.String)
+	}
+	var contact []string
+	if t.ContactPhone.Valid && t.ContactPhone.String != "" {
+		contact = append(contact, t.ContactPhone.String)
+	}
+	if t.ContactEmail != "" {
+		contact = append(contact, t.ContactEmail)
+	}
+	if len(contact) > 0 {
+		b.Lines = append(b.Lines, strings.Join(contact, "  ·  "))
+	}
+	return b
+}
+
+func (k *Kit) logo(ctx context.Context, url string) *pdf.Image {
+	k.mu.Lock()
+	c, ok := k.logos[url]
+	k.mu.Unlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.img
+	}
+
+	img, err := k.fetchLogo(ctx, url)
+	c = cachedLogo{img: img, expires: time.Now().Add(logoTTL)}
+	if err != nil {
+		log.Warn().Err(err).Str("url", url).Msg("documents: logo not used")
+		c.expires = time.Now().Add(logoRetry)
+	}
+	k.mu.Lock()
+	k.logos[url] = c
+	k.mu.Unlock()
+	return img
+}
+
+func (k *Kit) fetchLogo(ctx context.Context, url string) (*pdf.Image, error) {
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("unsupported logo URL")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxLogoBytes {
+		return nil, fmt.Errorf("logo larger than %d bytes", maxLogoBytes)
+	}
+	// ParseImage rejects formats PDF cannot embed, such as SVG.
+	return pdf.ParseImage(data)
+}
+
+// Location returns the tenant's timezone, falling back to Dhaka.
+func Location(t *sqlc.Tenant) *time.Location {
+	if loc, err := time.LoadLocation(t.Timezone); err == nil && t.Timezone != "" {
+		return loc
+	}
+	return timeutil.BangladeshLocation
+}
+
+// Money renders an amount with thousands separators, e.g. "12,345.50".
+func Money(d decimal.Decimal) string {
+	s := d.Abs().StringFixed(2)
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if d.IsNegative() && !d.Round(2).IsZero() {
+		b.WriteByte('-')
+	}
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String() + "." + frac
+}
@@ -0,0 +1,74 @@
+package documents
+
+import (
+	"context"
+	"database/sql"
+	"net/netip"
+	"testing"
+
+	"github.com/munchies/platform/backend/internal/db/sqlc"
+	"github.com/munchies/platform/backend/internal/pkg/pdf"
+	"github.com/shopspring/decimal"
+)
+
+func TestMoney(t *testing.T) {
+	cases := map[string]string{
+		"0":           "0.00",
+		"999.5":       "999.50",
+		"1234567.891": "1,234,567.89",
+		"-45210":      "-45,210.00",
+		"-0.001":      "0.00",
+	}
+	for in, want := range cases {
+		if got := Money(decimal.RequireFromString(in)); got != want {
+			t.Errorf("Money(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBrand(t *testing.T) {
+	k := New(Config{})
+	b := k.Brand(context.Background(), &sqlc.Tenant{
+		Name:         "Kacchi Bhai",
+		PrimaryColor: "#1A73E8",
+		LogoUrl:      sql.NullString{String: "data:image/svg+xml;base64,PHN2Zy8+", Valid: true},
+		ContactEmail: "billing@kacchibhai.com",
+		ContactPhone: sql.NullString{String: "+8801700000000", Valid: true},
+	})
+	if b.Color != (pdf.Color{R: 0x1A, G: 0x73, B: 0xE8}) {
+		t.Errorf("color = %v", b.Color)
+	}
+	if b.Logo != nil {
+		t.Error("an unsupported logo URL should be left out")
+	}
+	if len(b.Lines) != 1 || b.Lines[0] != "+8801700000000  ·  billing@kacchibhai.com" {
+		t.Errorf("lines = %q", b.Lines)
+	}
+
+	if b := k.Brand(context.Background(), &sqlc.Tenant{Name: "X", PrimaryColor: "teal"}); b.Color != defaultColor {
+		t.Errorf("invalid colour should fall back to the default, got %v", b.Color)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	cases := map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.0.0.5":         false,
+		"172.16.3.4":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::ffff:127.0.0.1": false,
+	}
+	for in, want := range cases {
+		if got := publicAddr(netip.MustParseAddr(in)); got != want {
+			t.Errorf("publicAddr(%s) = %v, want %v", in, got, want)
+		}
+	}
+}
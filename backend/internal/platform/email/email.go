@@ -8,6 +8,11 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// Sender is the interface for email providers.
+type Sender interface {
+	Send(ctx context.Context, to, subject, htmlBody string) error
+}
+
 // Client implements email sending.
 type Client struct {
 	provider string
@@ -49,6 +54,7 @@ var Templates = map[string]string{
 	"password_reset":     passwordResetTemplate,
 	"vendor_invitation":  vendorInvitationTemplate,
 	"tenant_suspended":   tenantSuspendedTemplate,
+	"scheduled_report":   scheduledReportTemplate,
 }
 
 // RenderTemplate renders an email template with variables.
@@ -76,6 +82,13 @@ const passwordResetTemplate = `<h1>Password Reset</h1><p>Click <a href="{{.Reset
 const vendorInvitationTemplate = `<h1>Vendor Invitation</h1><p>You've been invited to join {{.PlatformName}} as a vendor.</p>`
 const tenantSuspendedTemplate = `<h1>Account Suspended</h1><p>Your account has been suspended. Please contact support.</p>`
 
+// scheduledReportTemplate expects Title, TenantName, Period, Scope and
+// Sections, each with a Heading and Lines of Label, Value and Total.
+const scheduledReportTemplate = `<h1>{{.Title}}</h1><p>{{.TenantName}} &middot; {{.Period}}{{if .Scope}} &middot; {{.Scope}}{{end}}</p>` +
+	`{{range .Sections}}<h2>{{.Heading}}</h2><table cellpadding="4">` +
+	`{{range .Lines}}<tr><td>{{.Label}}</td><td align="right">{{if .Total}}<strong>{{.Value}}</strong>{{else}}{{.Value}}{{end}}</td></tr>{{end}}` +
+	`</table>{{end}}<p>You receive this because you are on the report list. Manage schedules in the partner portal.</p>`
+
 // NoopClient is a no-op email client for development.
 type NoopClient struct{}
 
@@ -13,16 +13,19 @@ import (
 	"github.com/munchies/platform/backend/internal/config"
 	"github.com/munchies/platform/backend/internal/db/sqlc"
 	"github.com/munchies/platform/backend/internal/middleware"
+	accessmod "github.com/munchies/platform/backend/internal/modules/access"
 	analyticsmod "github.com/munchies/platform/backend/internal/modules/analytics"
 	authmod "github.com/munchies/platform/backend/internal/modules/auth"
 	catalogmod "github.com/munchies/platform/backend/internal/modules/catalog"
 	contentmod "github.com/munchies/platform/backend/internal/modules/content"
 	deliverymod "github.com/munchies/platform/backend/internal/modules/delivery"
+	exportmod "github.com/munchies/platform/backend/internal/modules/export"
 	financemod "github.com/munchies/platform/backend/internal/modules/finance"
 	hubmod "github.com/munchies/platform/backend/internal/modules/hub"
 	inventorymod "github.com/munchies/platform/backend/internal/modules/inventory"
 	issuemod "github.com/munchies/platform/backend/internal/modules/issue"
 	mediamod "github.com/munchies/platform/backend/internal/modules/media"
+	notificationmod "github.com/munchies/platform/backend/internal/modules/notification"
 	ordermod "github.com/munchies/platform/backend/internal/modules/order"
 	paymentmod "github.com/munchies/platform/backend/internal/modules/payment"
 	promomod "github.com/munchies/platform/backend/internal/modules/promo"
@@ -32,14 +35,19 @@ import (
 	searchmod "github.com/munchies/platform/backend/internal/modules/search"
 	ssemod "github.com/munchies/platform/backend/internal/modules/sse"
 	storefrontmod "github.com/munchies/platform/backend/internal/modules/storefront"
+	taxmod "github.com/munchies/platform/backend/internal/modules/tax"
 	tenantmod "github.com/munchies/platform/backend/internal/modules/tenant"
 	usermod "github.com/munchies/platform/backend/internal/modules/user"
 	workermod "github.com/munchies/platform/backend/internal/modules/worker"
+	"github.com/munchies/platform/backend/internal/platform/documents"
+	"github.com/munchies/platform/backend/internal/platform/email"
+	"github.com/munchies/platform/backend/internal/platform/fcm"
 	gatewaypkg "github.com/munchies/platform/backend/internal/platform/payment"
 	"github.com/munchies/platform/backend/internal/platform/payment/aamarpay"
 	"github.com/munchies/platform/backend/internal/platform/payment/bkash"
 	redisclient "github.com/munchies/platform/backend/internal/platform/redis"
 	"github.com/munchies/platform/backend/internal/platform/sms"
+	"github.com/munchies/platform/backend/internal/platform/storage"
 	"github.com/rs/zerolog/log"
 )
 
@@ -49,6 +57,7 @@ type Deps struct {
 	Pool    *pgxpool.Pool
 	Redis   *redisclient.Client
 	SMS     sms.Sender
+	Email   email.Sender
 }
 
 // Server holds the HTTP router and dependencies.
@@ -139,17 +148,34 @@ func (s *Server) registerRoutes(deps Deps) {
 
 	mediaHandler := mediamod.NewHandler()
 
+	// Notification service
+	pushClient := fcm.New(fcm.Config{
+		ProjectID:   s.cfg.Services.FirebaseProject,
+		PrivateKey:  s.cfg.Services.FirebaseKey,
+		ClientEmail: s.cfg.Services.FirebaseEmail,
+	})
+	notificationSvc := notificationmod.NewService(deps.Queries, pushClient, deps.SMS, deps.Email)
+
 	// Inventory module
-	inventorySvc := inventorymod.NewService(deps.Queries)
+	inventorySvc := inventorymod.NewService(deps.Queries, deps.Pool, notificationSvc)
 	inventoryHandler := inventorymod.NewHandler(inventorySvc)
 
 	// Promo module
-	promoSvc := promomod.NewService(deps.Queries)
+	promoSvc := promomod.NewService(deps.Queries, deps.Pool)
 	promoHandler := promomod.NewHandler(promoSvc)
 
+	// Branded PDF documents (invoices, receipts, VAT challans)
+	docKit := documents.New(documents.Config{
+		BanglaFont:     s.cfg.PDF.BanglaFont,
+		BanglaBoldFont: s.cfg.PDF.BanglaBoldFont,
+	})
+	if !docKit.HasBanglaFont() && s.cfg.Server.Environment.IsProduction() {
+		log.Fatal().Str("path", s.cfg.PDF.BanglaFont).Msg("Bangla PDF font not loaded; invoices and VAT challans would not render")
+	}
+
 	// Order module
 	orderSvc := ordermod.NewService(deps.Queries, deps.Pool, inventorySvc, promoSvc)
-	orderHandler := ordermod.NewHandler(orderSvc)
+	orderHandler := ordermod.NewHandler(orderSvc, docKit)
 
 	// Payment gateways
 	paymentGateways := map[sqlc.PaymentMethod]gatewaypkg.Gateway{
@@ -164,7 +190,7 @@ func (s *Server) registerRoutes(deps Deps) {
 			BaseURL:      s.cfg.Services.AamarPayBaseURL,
 		}),
 	}
-	paymentSvc := paymentmod.NewService(deps.Queries, paymentGateways)
+	paymentSvc := paymentmod.NewService(deps.Queries, deps.Pool, paymentGateways)
 	callbackBaseURL := s.cfg.Server.PublicBaseURL
 	if callbackBaseURL == "" {
 		callbackBaseURL = fmt.Sprintf("http://localhost:%d", s.cfg.Server.Port)
@@ -172,19 +198,32 @@ func (s *Server) registerRoutes(deps Deps) {
 	paymentHandler := paymentmod.NewHandler(paymentSvc, callbackBaseURL)
 
 	// Rider module
-	riderSvc := ridermod.NewService(deps.Queries)
+	riderSvc := ridermod.NewService(deps.Queries, deps.Pool, notificationSvc)
 	riderHandler := ridermod.NewHandler(riderSvc)
-	riderWSHandler := ridermod.NewWSHandler(deps.Queries, tokenCfg, deps.Redis)
+	locationPipeline := ridermod.NewLocationPipeline(deps.Queries, deps.Pool, deps.Redis, ridermod.LocationConfig{
+		FlushInterval:       s.cfg.Tracking.FlushInterval,
+		HistoryInterval:     s.cfg.Tracking.HistoryInterval,
+		HistoryMinDistanceM: s.cfg.Tracking.HistoryMinDistance,
+		MaxAccuracyM:        s.cfg.Tracking.MaxAccuracy,
+		MaxSpeedKmh:         s.cfg.Tracking.MaxSpeedKmh,
+		StaleAfter:          s.cfg.Tracking.StaleAfter,
+		HistoryRetention:    s.cfg.Tracking.HistoryRetention,
+	})
+	riderWSHandler := ridermod.NewWSHandler(deps.Queries, tokenCfg, locationPipeline)
 
 	// Reconciliation job
 	s.reconciliationJob = paymentmod.NewReconciliationJob(deps.Queries, paymentGateways)
 
 	// Finance module
-	financeSvc := financemod.NewService(deps.Queries)
-	financeHandler := financemod.NewHandler(financeSvc)
+	financeSvc := financemod.NewService(deps.Queries, deps.Pool)
+	financeHandler := financemod.NewHandler(financeSvc, docKit)
+
+	// Tax module
+	taxSvc := taxmod.NewService(deps.Queries, deps.Pool)
+	taxHandler := taxmod.NewHandler(taxSvc, docKit)
 
 	// Issue module
-	issueSvc := issuemod.NewService(deps.Queries)
+	issueSvc := issuemod.NewService(deps.Queries, deps.Pool)
 	issueHandler := issuemod.NewHandler(issueSvc)
 
 	// Rating module
@@ -200,14 +239,40 @@ func (s *Server) registerRoutes(deps Deps) {
 	contentHandler := contentmod.NewHandler(contentSvc)
 
 	// Analytics module
-	analyticsSvc := analyticsmod.NewService(deps.Queries)
+	analyticsSvc := analyticsmod.NewService(deps.Queries, deps.Pool)
 	analyticsHandler := analyticsmod.NewHandler(analyticsSvc)
 
+	// Export module
+	fileStore, err := storage.NewLocal(s.cfg.Storage.LocalDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialise file storage")
+	}
+	exportSvc := exportmod.NewService(deps.Queries, fileStore, notificationSvc, callbackBaseURL)
+	exportHandler := exportmod.NewHandler(exportSvc)
+
 	// SSE module
-	sseHandler := ssemod.NewHandler(deps.Redis)
+	sseHandler := ssemod.NewHandler(deps.Redis, deps.Queries)
 
 	// Background worker
-	s.worker = workermod.NewWorker(deps.Queries, deps.Redis)
+	s.worker = workermod.NewWorker(deps.Queries, deps.Pool, deps.Redis)
+	s.worker.Handle(inventorymod.EventLowStock, inventorySvc.HandleStockEvent)
+	s.worker.Handle(inventorymod.EventOutOfStock, inventorySvc.HandleStockEvent)
+	s.worker.Handle(inventorymod.EventRestocked, inventorySvc.HandleStockEvent)
+	s.worker.Handle(analyticsmod.EventOrderCompleted, analyticsSvc.HandleOrderCompleted)
+	s.worker.Schedule("rider:weekly_payouts", 1*time.Hour, riderSvc.RunWeeklyPayouts)
+	s.worker.Schedule("rider:shift_absences", 10*time.Minute, riderSvc.RunShiftAbsenceSweep)
+	s.worker.Schedule("rider:location_flush", locationPipeline.FlushInterval(), locationPipeline.Flush)
+	s.worker.Schedule("rider:location_stale", 1*time.Minute, locationPipeline.MarkStaleRiders)
+	s.worker.Schedule("rider:location_retention", 1*time.Hour, locationPipeline.PurgeLocationHistory)
+	s.worker.Schedule("rider:offer_expiry", 1*time.Minute, riderSvc.ExpireAssignmentOffers)
+	s.worker.Schedule("rider:scorecards", 1*time.Hour, riderSvc.RunScorecards)
+	s.worker.Schedule("rider:document_expiry", 1*time.Hour, riderSvc.RunDocumentExpiry)
+	s.worker.Schedule("analytics:order_facts", 15*time.Minute, analyticsSvc.CatchUpOrderFacts)
+	s.worker.Schedule("export:process", 15*time.Second, exportSvc.ProcessExports)
+	s.worker.Schedule("export:cleanup", 1*time.Hour, exportSvc.CleanupExports)
+	s.worker.Schedule("reports:scheduled_emails", 5*time.Minute, exportSvc.SendScheduledReports)
+	s.worker.Schedule("finance:settlement_runs", 1*time.Hour, financeSvc.RunInvoiceCycles)
+	s.worker.Schedule("tax:issue_invoices", 10*time.Minute, taxSvc.IssuePendingInvoices)
 
 	// Ledger service (seed platform accounts)
 	ledgerSvc := financemod.NewLedgerService(deps.Queries)
@@ -219,6 +284,15 @@ func (s *Server) registerRoutes(deps Deps) {
 		sqlc.UserRoleRestaurantManager,
 		sqlc.UserRoleRestaurantStaff,
 	)
+	// Riders, finance, hubs, promos and content are tenant-wide; restaurant
+	// managers and staff are further limited to their assigned restaurants.
+	tenantAdminRoles := authmod.RequireRoles(sqlc.UserRoleTenantOwner, sqlc.UserRoleTenantAdmin)
+	managerRoles := authmod.RequireRoles(
+		sqlc.UserRoleTenantOwner,
+		sqlc.UserRoleTenantAdmin,
+		sqlc.UserRoleRestaurantManager,
+	)
+	accessPolicy := accessmod.NewPolicy(deps.Queries)
 
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
@@ -268,6 +342,9 @@ func (s *Server) registerRoutes(deps Deps) {
 				r.Post("/", orderHandler.CreateOrder)
 				r.Get("/{id}", orderHandler.GetOrder)
 				r.Get("/{id}/tracking", orderHandler.TrackOrder)
+				r.Get("/{id}/receipt", orderHandler.GetReceipt)
+				r.Get("/{id}/tax-invoice", taxHandler.GetOrderInvoicesPDF)
+				r.Get("/{id}/delivery-code", riderHandler.GetDeliveryCode)
 				r.Patch("/{id}/cancel", orderHandler.CancelOrder)
 			})
 
@@ -321,6 +398,7 @@ func (s *Server) registerRoutes(deps Deps) {
 			// Order flow
 			r.Get("/orders/active", riderHandler.ListActiveOrders)
 			r.Patch("/orders/{id}/accept", riderHandler.AcceptOrder)
+			r.Patch("/orders/{id}/decline", riderHandler.DeclineOrder)
 			r.Patch("/orders/{id}/picked/{restaurant_id}", riderHandler.MarkPickupPicked)
 			r.Patch("/orders/{id}/delivered", riderHandler.MarkDelivered)
 			r.Patch("/orders/{id}/issue", riderHandler.ReportIssue)
@@ -328,6 +406,31 @@ func (s *Server) registerRoutes(deps Deps) {
 			// Earnings & history
 			r.Get("/earnings", riderHandler.ListEarnings)
 			r.Get("/history", riderHandler.ListDeliveryHistory)
+			r.Get("/payouts", riderHandler.ListMyPayouts)
+
+			// COD cash
+			r.Get("/cash", riderHandler.GetMyCash)
+			r.Post("/cash/deposits", riderHandler.SubmitCashDeposit)
+
+			// Shifts
+			r.Get("/shifts", riderHandler.ListMyShifts)
+			r.Post("/shifts/{id}/swap", riderHandler.RequestShiftSwap)
+			r.Get("/shift-swaps", riderHandler.ListMyShiftSwaps)
+			r.Patch("/shift-swaps/{id}/accept", riderHandler.AcceptShiftSwap)
+			r.Patch("/shift-swaps/{id}/decline", riderHandler.DeclineShiftSwap)
+			r.Patch("/shift-swaps/{id}/cancel", riderHandler.CancelShiftSwap)
+
+			// Penalties
+			r.Get("/penalties", riderHandler.ListMyPenalties)
+			r.Post("/penalties/{id}/appeal", riderHandler.AppealPenalty)
+
+			// Performance
+			r.Get("/scorecard", riderHandler.GetMyScorecard)
+
+			// Onboarding
+			r.Get("/onboarding", riderHandler.GetOnboarding)
+			r.Post("/onboarding/application", riderHandler.SubmitApplication)
+			r.Post("/documents", riderHandler.UploadDocument)
 
 			// Order module rider routes
 			r.Route("/orders", func(r chi.Router) {
@@ -344,123 +447,249 @@ func (s *Server) registerRoutes(deps Deps) {
 		r.Use(tenantmod.NewResolver(tenantRepo, deps.Redis).Middleware)
 		r.Use(authMiddleware.Authenticate)
 		r.Use(partnerRoles)
+		r.Use(accessPolicy.LoadScope)
 
-		// Hub management
-		r.Get("/hubs", hubHandler.ListHubs)
-		r.Post("/hubs", hubHandler.CreateHub)
-		r.Get("/hubs/{id}", hubHandler.GetHub)
-		r.Put("/hubs/{id}", hubHandler.UpdateHub)
-		r.Delete("/hubs/{id}", hubHandler.DeleteHub)
-		r.Get("/hubs/{id}/areas", hubHandler.ListHubAreas)
-		r.Post("/hubs/{id}/areas", hubHandler.CreateHubArea)
-		r.Put("/hubs/{id}/areas/{area_id}", hubHandler.UpdateHubArea)
-		r.Delete("/hubs/{id}/areas/{area_id}", hubHandler.DeleteHubArea)
-		r.Get("/delivery/config", hubHandler.GetDeliveryZoneConfig)
-		r.Put("/delivery/config", hubHandler.UpsertDeliveryZoneConfig)
-
-		// Restaurant management
-		r.Get("/restaurants", restaurantHandler.ListRestaurants)
-		r.Post("/restaurants", restaurantHandler.CreateRestaurant)
-		r.Get("/restaurants/{id}", restaurantHandler.GetRestaurant)
-		r.Put("/restaurants/{id}", restaurantHandler.UpdateRestaurant)
-		r.Delete("/restaurants/{id}", restaurantHandler.DeleteRestaurant)
-		r.Patch("/restaurants/{id}/availability", restaurantHandler.UpdateAvailability)
-		r.Get("/restaurants/{id}/hours", restaurantHandler.GetOperatingHours)
-		r.Put("/restaurants/{id}/hours", restaurantHandler.UpsertOperatingHours)
-
-		// Category management
-		r.Get("/restaurants/{id}/categories", catalogHandler.ListCategories)
-		r.Post("/restaurants/{id}/categories", catalogHandler.CreateCategory)
-		r.Put("/restaurants/{id}/categories/{cat_id}", catalogHandler.UpdateCategory)
-		r.Delete("/restaurants/{id}/categories/{cat_id}", catalogHandler.DeleteCategory)
-		r.Patch("/restaurants/{id}/categories/reorder", catalogHandler.ReorderCategories)
-
-		// Product management
-		r.Get("/restaurants/{id}/products", catalogHandler.ListProducts)
-		r.Post("/restaurants/{id}/products", catalogHandler.CreateProduct)
-		r.Get("/products/{id}", catalogHandler.GetProduct)
-		r.Put("/products/{id}", catalogHandler.UpdateProduct)
-		r.Delete("/products/{id}", catalogHandler.DeleteProduct)
-		r.Patch("/products/{id}/availability", catalogHandler.UpdateProductAvailability)
-		r.Post("/products/{id}/discount", catalogHandler.UpsertDiscount)
-		r.Delete("/products/{id}/discount", catalogHandler.DeactivateDiscount)
-		r.Post("/products/bulk-upload", catalogHandler.BulkUpload)
-
-		// Menu duplication
-		r.Post("/restaurants/{id}/menu/duplicate", catalogHandler.DuplicateMenu)
-
-		// Order refund
-		r.Post("/orders/{id}/refund", paymentHandler.ProcessRefund)
-
-		// Order rider assignment
-		r.Post("/orders/{id}/assign-rider", riderHandler.ManualAssignRider)
-
-		// Rider management
-		r.Get("/riders", riderHandler.ListRiders)
-		r.Post("/riders", riderHandler.CreateRider)
-		r.Get("/riders/attendance", riderHandler.ListAttendance)
-		r.Get("/riders/tracking", riderHandler.ListRiderTracking)
-		r.Get("/riders/{id}", riderHandler.GetRider)
-		r.Put("/riders/{id}", riderHandler.UpdateRider)
-		r.Delete("/riders/{id}", riderHandler.DeleteRider)
-		r.Get("/riders/{id}/travel-log", riderHandler.GetTravelLog)
-		r.Get("/riders/{id}/penalties", riderHandler.ListPenalties)
-		r.Post("/riders/{id}/penalties", riderHandler.CreatePenalty)
-		r.Patch("/riders/{id}/penalties/{penalty_id}", riderHandler.UpdatePenalty)
-
-		// Finance management (partner)
-		r.Get("/finance/summary", financeHandler.GetSummary)
-		r.Get("/finance/invoices", financeHandler.ListInvoices)
-		r.Get("/finance/invoices/{id}", financeHandler.GetInvoice)
-		r.Get("/finance/invoices/{id}/pdf", financeHandler.GetInvoicePDF)
-
-		// Issue management (partner)
-		r.Get("/issues", issueHandler.ListIssues)
-		r.Get("/issues/{id}", issueHandler.GetIssue)
-		r.Post("/issues/{id}/message", issueHandler.AddMessage)
-		r.Get("/issues/{id}/messages", issueHandler.ListMessages)
-
-		// Review management (partner respond)
-		r.Post("/reviews/{id}/respond", ratingHandler.RespondToReview)
-
-		// Content management (partner)
-		r.Get("/content/banners", contentHandler.ListBanners)
-		r.Post("/content/banners", contentHandler.CreateBanner)
-		r.Put("/content/banners/{id}", contentHandler.UpdateBanner)
-		r.Delete("/content/banners/{id}", contentHandler.DeleteBanner)
-		r.Get("/content/stories", contentHandler.ListStories)
-		r.Post("/content/stories", contentHandler.CreateStory)
-		r.Delete("/content/stories/{id}", contentHandler.DeleteStory)
-		r.Get("/content/sections", contentHandler.ListSections)
-		r.Put("/content/sections/{id}", contentHandler.UpdateSection)
-
-		// Inventory management
-		r.Route("/inventory", func(r chi.Router) {
-			inventoryHandler.RegisterRoutes(r)
+		// Tenant-wide management (owners and admins only)
+		r.Group(func(r chi.Router) {
+			r.Use(tenantAdminRoles)
+
+			// Hub management
+			r.Get("/hubs", hubHandler.ListHubs)
+			r.Post("/hubs", hubHandler.CreateHub)
+			r.Get("/hubs/{id}", hubHandler.GetHub)
+			r.Put("/hubs/{id}", hubHandler.UpdateHub)
+			r.Delete("/hubs/{id}", hubHandler.DeleteHub)
+			r.Get("/hubs/{id}/areas", hubHandler.ListHubAreas)
+			r.Post("/hubs/{id}/areas", hubHandler.CreateHubArea)
+			r.Put("/hubs/{id}/areas/{area_id}", hubHandler.UpdateHubArea)
+			r.Delete("/hubs/{id}/areas/{area_id}", hubHandler.DeleteHubArea)
+			r.Get("/delivery/config", hubHandler.GetDeliveryZoneConfig)
+			r.Put("/delivery/config", hubHandler.UpsertDeliveryZoneConfig)
+
+			// Restaurant lifecycle
+			r.Post("/restaurants", restaurantHandler.CreateRestaurant)
+			r.Delete("/restaurants/{id}", restaurantHandler.DeleteRestaurant)
+
+			// Order refund
+			r.Post("/orders/{id}/refund", paymentHandler.ProcessRefund)
+
+			// Order rider assignment
+			r.Post("/orders/{id}/assign-rider", riderHandler.ManualAssignRider)
+
+			// Rider management
+			r.Get("/riders", riderHandler.ListRiders)
+			r.Post("/riders", riderHandler.CreateRider)
+			r.Get("/riders/attendance", riderHandler.ListAttendance)
+			r.Get("/riders/tracking", riderHandler.ListRiderTracking)
+			r.Post("/riders/payouts", riderHandler.CreatePayoutBatch)
+			r.Patch("/riders/payouts/{id}/complete", riderHandler.CompletePayout)
+			r.Patch("/riders/payouts/{id}/fail", riderHandler.FailPayout)
+			r.Get("/riders/payout-batches", riderHandler.ListPayoutBatches)
+			r.Get("/riders/payout-batches/{id}", riderHandler.GetPayoutBatch)
+			r.Post("/riders/payout-batches/{id}/bkash.csv", riderHandler.ExportBkashDisbursement)
+			r.Get("/riders/earning-rules", riderHandler.ListEarningRules)
+			r.Post("/riders/earning-rules", riderHandler.CreateEarningRule)
+			r.Put("/riders/earning-rules/{id}", riderHandler.UpdateEarningRule)
+			r.Delete("/riders/earning-rules/{id}", riderHandler.DeleteEarningRule)
+			r.Get("/riders/surges", riderHandler.ListEarningSurges)
+			r.Post("/riders/surges", riderHandler.CreateEarningSurge)
+			r.Delete("/riders/surges/{id}", riderHandler.EndEarningSurge)
+			r.Get("/riders/cash/deposits", riderHandler.ListCashDeposits)
+			r.Patch("/riders/cash/deposits/{id}/confirm", riderHandler.ConfirmCashDeposit)
+			r.Patch("/riders/cash/deposits/{id}/reject", riderHandler.RejectCashDeposit)
+			r.Get("/riders/cash/reconciliation", riderHandler.GetCashReconciliation)
+			r.Get("/riders/shift-templates", riderHandler.ListShiftTemplates)
+			r.Post("/riders/shift-templates", riderHandler.CreateShiftTemplate)
+			r.Put("/riders/shift-templates/{id}", riderHandler.UpdateShiftTemplate)
+			r.Delete("/riders/shift-templates/{id}", riderHandler.DeleteShiftTemplate)
+			r.Get("/riders/shifts", riderHandler.ListShifts)
+			r.Post("/riders/shifts", riderHandler.RosterShifts)
+			r.Get("/riders/shifts/coverage", riderHandler.GetShiftCoverage)
+			r.Delete("/riders/shifts/{id}", riderHandler.CancelShift)
+			r.Get("/riders/shift-swaps", riderHandler.ListShiftSwaps)
+			r.Patch("/riders/shift-swaps/{id}/approve", riderHandler.ApproveShiftSwap)
+			r.Patch("/riders/shift-swaps/{id}/reject", riderHandler.RejectShiftSwap)
+			r.Get("/riders/penalty-appeals", riderHandler.ListPenaltyAppeals)
+			r.Patch("/riders/penalty-appeals/{id}/uphold", riderHandler.UpholdPenaltyAppeal)
+			r.Patch("/riders/penalty-appeals/{id}/overturn", riderHandler.OverturnPenaltyAppeal)
+			r.Get("/riders/tiers", riderHandler.ListRiderTiers)
+			r.Get("/riders/applications", riderHandler.ListApplications)
+			r.Get("/riders/applications/{id}", riderHandler.GetApplication)
+			r.Patch("/riders/applications/{id}/approve", riderHandler.ApproveApplication)
+			r.Patch("/riders/applications/{id}/reject", riderHandler.RejectApplication)
+			r.Get("/riders/document-renewals", riderHandler.ListDocumentRenewals)
+			r.Patch("/riders/document-renewals/{id}/approve", riderHandler.ApproveDocument)
+			r.Patch("/riders/document-renewals/{id}/reject", riderHandler.RejectDocument)
+			r.Get("/riders/{id}", riderHandler.GetRider)
+			r.Put("/riders/{id}", riderHandler.UpdateRider)
+			r.Delete("/riders/{id}", riderHandler.DeleteRider)
+			r.Get("/riders/{id}/travel-log", riderHandler.GetTravelLog)
+			r.Get("/riders/{id}/travel-log.geojson", riderHandler.GetTravelLogGeoJSON)
+			r.Get("/riders/{id}/scorecard", riderHandler.GetRiderScorecard)
+			r.Get("/riders/{id}/documents", riderHandler.ListRiderDocuments)
+			r.Get("/riders/{id}/penalties", riderHandler.ListPenalties)
+			r.Post("/riders/{id}/penalties", riderHandler.CreatePenalty)
+			r.Patch("/riders/{id}/penalties/{penalty_id}", riderHandler.UpdatePenalty)
+
+			// Finance management (partner)
+			r.Get("/finance/summary", financeHandler.GetSummary)
+			r.Get("/finance/invoices", financeHandler.ListInvoices)
+			r.Get("/finance/invoices/{id}", financeHandler.GetInvoice)
+			r.Get("/finance/invoices/{id}/pdf", financeHandler.GetInvoicePDF)
+			r.Get("/finance/invoices/{id}/adjustments", financeHandler.ListInvoiceAdjustments)
+			r.Get("/finance/invoices/{id}/line-items", financeHandler.ListInvoiceLineItems)
+			r.Get("/finance/adjustments", financeHandler.ListPendingAdjustments)
+			r.Get("/finance/restaurants/{id}/payout-account", financeHandler.GetPayoutAccount)
+			r.Put("/finance/restaurants/{id}/payout-account", financeHandler.SetPayoutAccount)
+
+			// VAT (tenant registration and fee supplies)
+			r.Get("/tax/settings", taxHandler.GetSettings)
+			r.Put("/tax/settings", taxHandler.UpdateSettings)
+			r.Get("/tax/returns", taxHandler.GetFeeReturn)
+			r.Get("/tax/invoices", taxHandler.ListFeeInvoices)
+
+			// Content management (partner)
+			r.Get("/content/banners", contentHandler.ListBanners)
+			r.Post("/content/banners", contentHandler.CreateBanner)
+			r.Put("/content/banners/{id}", contentHandler.UpdateBanner)
+			r.Delete("/content/banners/{id}", contentHandler.DeleteBanner)
+			r.Get("/content/stories", contentHandler.ListStories)
+			r.Post("/content/stories", contentHandler.CreateStory)
+			r.Delete("/content/stories/{id}", contentHandler.DeleteStory)
+			r.Get("/content/sections", contentHandler.ListSections)
+			r.Put("/content/sections/{id}", contentHandler.UpdateSection)
+
+			// Promo management
+			r.Route("/promos", func(r chi.Router) {
+				promoHandler.RegisterRoutes(r)
+			})
+
+			// Tenant-wide reports
+			r.Get("/reports/riders", analyticsHandler.GetRiderAnalytics)
+			r.Get("/reports/searches", searchHandler.TopSearchTerms)
+			r.Get("/reports/customers/summary", analyticsHandler.GetCustomerSummary)
+			r.Get("/reports/customers/cohorts", analyticsHandler.GetCustomerCohorts)
+			r.Get("/reports/customers/rfm", analyticsHandler.GetRFMSegments)
+			r.Get("/reports/customers/segments/{segment}", analyticsHandler.ListSegmentCustomers)
+			r.Post("/reports/customers/segments/{segment}/promo-eligibility", analyticsHandler.ExportSegmentToPromo)
+			r.Get("/reports/customers/ltv", analyticsHandler.GetLTVByAcquisitionPromo)
 		})
 
-		// Promo management
-		r.Route("/promos", func(r chi.Router) {
-			promoHandler.RegisterRoutes(r)
+		// Restaurant management (managers, limited to their assigned restaurants)
+		r.Group(func(r chi.Router) {
+			r.Use(managerRoles)
+			byRestaurant := accessPolicy.RequireRestaurant(accessmod.URLParam("id"))
+			byProduct := accessPolicy.RequireRestaurant(accessPolicy.Product("id"))
+			byCategory := accessPolicy.RequireRestaurant(accessmod.URLParam("id"), accessPolicy.Category("cat_id"))
+			byIssue := accessPolicy.RequireRestaurant(accessPolicy.Issue("id"))
+
+			r.With(byRestaurant).Get("/restaurants/{id}", restaurantHandler.GetRestaurant)
+			r.With(byRestaurant).Put("/restaurants/{id}", restaurantHandler.UpdateRestaurant)
+			r.With(byRestaurant).Patch("/restaurants/{id}/availability", restaurantHandler.UpdateAvailability)
+			r.With(byRestaurant).Get("/restaurants/{id}/hours", restaurantHandler.GetOperatingHours)
+			r.With(byRestaurant).Put("/restaurants/{id}/hours", restaurantHandler.UpsertOperatingHours)
+
+			// Category management
+			r.With(byRestaurant).Get("/restaurants/{id}/categories", catalogHandler.ListCategories)
+			r.With(byRestaurant).Post("/restaurants/{id}/categories", catalogHandler.CreateCategory)
+			r.With(byCategory).Put("/restaurants/{id}/categories/{cat_id}", catalogHandler.UpdateCategory)
+			r.With(byCategory).Delete("/restaurants/{id}/categories/{cat_id}", catalogHandler.DeleteCategory)
+			r.With(byRestaurant).Patch("/restaurants/{id}/categories/reorder", catalogHandler.ReorderCategories)
+
+			// Product management
+			r.With(byRestaurant).Get("/restaurants/{id}/products", catalogHandler.ListProducts)
+			r.With(byRestaurant).Post("/restaurants/{id}/products", catalogHandler.CreateProduct)
+			r.With(byProduct).Get("/products/{id}", catalogHandler.GetProduct)
+			r.With(byProduct).Put("/products/{id}", catalogHandler.UpdateProduct)
+			r.With(byProduct).Delete("/products/{id}", catalogHandler.DeleteProduct)
+			r.With(byProduct).Patch("/products/{id}/availability", catalogHandler.UpdateProductAvailability)
+			r.With(byProduct).Post("/products/{id}/discount", catalogHandler.UpsertDiscount)
+			r.With(byProduct).Delete("/products/{id}/discount", catalogHandler.DeactivateDiscount)
+			r.With(accessPolicy.RequireRestaurant(accessmod.Form("restaurant_id"))).Post("/products/bulk-upload", catalogHandler.BulkUpload)
+
+			// Menu duplication (both restaurants must be in scope)
+			r.With(accessPolicy.RequireRestaurant(accessmod.URLParam("id"), accessmod.Body("target_restaurant_id"))).
+				Post("/restaurants/{id}/menu/duplicate", catalogHandler.DuplicateMenu)
+
+			// Issue management (partner); the list is filtered to the caller's restaurants
+			r.Get("/issues", issueHandler.ListIssues)
+			r.With(byIssue).Get("/issues/{id}", issueHandler.GetIssue)
+			r.With(byIssue).Post("/issues/{id}/message", issueHandler.AddMessage)
+			r.With(byIssue).Get("/issues/{id}/messages", issueHandler.ListMessages)
+
+			// Review management (partner respond)
+			r.With(accessPolicy.RequireRestaurant(accessPolicy.Review("id"))).Post("/reviews/{id}/respond", ratingHandler.RespondToReview)
+
+			// Inventory management
+			r.Route("/inventory", func(r chi.Router) {
+				inventoryHandler.RegisterRoutes(r, accessPolicy)
+			})
+
+			// Dashboard & analytics (partner), filtered to the caller's restaurants
+			r.Get("/dashboard", analyticsHandler.GetDashboard)
+			r.Get("/reports/sales", analyticsHandler.GetSalesReport)
+			r.Get("/reports/products/top-selling", analyticsHandler.GetTopProducts)
+			r.Get("/reports/orders/breakdown", analyticsHandler.GetOrderBreakdown)
+			r.Get("/reports/peak-hours", analyticsHandler.GetPeakHours)
+
+			// VAT registration, tax invoice register and monthly returns per restaurant
+			r.With(byRestaurant).Get("/tax/restaurants/{id}/registration", taxHandler.GetRegistration)
+			r.With(byRestaurant).Put("/tax/restaurants/{id}/registration", taxHandler.UpdateRegistration)
+			r.With(byRestaurant).Get("/tax/restaurants/{id}/invoices", taxHandler.ListRestaurantInvoices)
+			r.With(byRestaurant).Get("/tax/restaurants/{id}/returns", taxHandler.GetRestaurantReturn)
+			r.Get("/tax/price-breakdown", taxHandler.GetPriceBreakdown)
+
+			// Invoice disputes and credit/debit notes; the list is filtered to the caller's restaurants
+			byInvoice := accessPolicy.RequireRestaurant(accessPolicy.Invoice("id"))
+			byDispute := accessPolicy.RequireRestaurant(accessPolicy.InvoiceDispute("id"))
+			r.With(byInvoice).Post("/finance/invoices/{id}/disputes", financeHandler.RaiseDispute)
+			r.With(byInvoice).Get("/finance/invoices/{id}/disputes", financeHandler.ListInvoiceDisputes)
+			r.With(byInvoice).Get("/finance/invoices/{id}/notes", financeHandler.ListInvoiceNotes)
+			r.Get("/finance/disputes", financeHandler.ListDisputes)
+			r.With(byDispute).Get("/finance/disputes/{id}", financeHandler.GetDispute)
+			r.With(byDispute).Post("/finance/disputes/{id}/comments", financeHandler.AddDisputeComment)
+			r.With(accessPolicy.RequireRestaurant(accessPolicy.InvoiceNote("id"))).Get("/finance/notes/{id}/pdf", financeHandler.GetNotePDF)
 		})
 
+		// Restaurants the caller can act on
+		r.Get("/restaurants", restaurantHandler.ListRestaurants)
+
+		// Proof of delivery
+		r.With(accessPolicy.RequireRestaurant(accessPolicy.Order("id"))).Get("/orders/{id}/delivery-proof", riderHandler.GetDeliveryProof)
+
+		// Receipts and VAT challans (PDF)
+		r.With(accessPolicy.RequireRestaurant(accessPolicy.Order("id"))).Get("/orders/{id}/receipt", orderHandler.GetReceiptPartner)
+
+		// Mushak-6.3 tax invoices
+		r.With(accessPolicy.RequireRestaurant(accessPolicy.Order("id"))).Get("/orders/{id}/tax-invoices", taxHandler.GetOrderInvoicesPartner)
+		r.With(accessPolicy.RequireRestaurant(accessPolicy.Order("id"))).Get("/orders/{id}/tax-invoices/pdf", taxHandler.GetOrderInvoicesPartnerPDF)
+
 		// Order management (partner)
 		r.Route("/orders", func(r chi.Router) {
-			r.Get("/", orderHandler.ListPartnerOrders)
-			r.Patch("/{id}/confirm", orderHandler.ConfirmOrderPartner)
-			r.Patch("/{id}/reject", orderHandler.RejectOrderPartner)
-			r.Patch("/{id}/preparing", orderHandler.PreparingOrderPartner)
-			r.Patch("/{id}/ready", orderHandler.ReadyOrderPartner)
+			byRestaurant := accessPolicy.RequireRestaurant(accessmod.Body("restaurant_id"), accessPolicy.Order("id"))
+			r.With(accessPolicy.RequireRestaurant(accessmod.Query("restaurant_id"))).Get("/", orderHandler.ListPartnerOrders)
+			r.With(byRestaurant).Patch("/{id}/confirm", orderHandler.ConfirmOrderPartner)
+			r.With(byRestaurant).Patch("/{id}/reject", orderHandler.RejectOrderPartner)
+			r.With(byRestaurant).Patch("/{id}/preparing", orderHandler.PreparingOrderPartner)
+			r.With(byRestaurant).Patch("/{id}/ready", orderHandler.ReadyOrderPartner)
+		})
+
+		// Report exports (CSV/XLSX, generated in the background)
+		r.Route("/exports", func(r chi.Router) {
+			r.Post("/", exportHandler.CreateExport)
+			r.Get("/", exportHandler.ListExports)
+			r.Get("/{id}", exportHandler.GetExport)
+			r.Get("/{id}/download", exportHandler.DownloadExport)
 		})
 
-		// Dashboard & analytics (partner)
-		r.Get("/dashboard", analyticsHandler.GetDashboard)
-		r.Get("/reports/sales", analyticsHandler.GetSalesReport)
-		r.Get("/reports/products/top-selling", analyticsHandler.GetTopProducts)
-		r.Get("/reports/orders/breakdown", analyticsHandler.GetOrderBreakdown)
-		r.Get("/reports/peak-hours", analyticsHandler.GetPeakHours)
-		r.Get("/reports/riders", analyticsHandler.GetRiderAnalytics)
-		r.Get("/reports/searches", searchHandler.TopSearchTerms)
+		// Scheduled email reports
+		r.Route("/report-schedules", func(r chi.Router) {
+			r.Post("/", exportHandler.CreateSubscription)
+			r.Get("/", exportHandler.ListSubscriptions)
+			r.Put("/{id}", exportHandler.UpdateSubscription)
+			r.Delete("/{id}", exportHandler.DeleteSubscription)
+			r.Get("/{id}/deliveries", exportHandler.ListDeliveries)
+		})
 	})
 
 	// Admin routes (authenticated, admin role required)
@@ -473,6 +702,26 @@ func (s *Server) registerRoutes(deps Deps) {
 		r.Post("/finance/invoices/generate", financeHandler.GenerateInvoice)
 		r.Patch("/finance/invoices/{id}/finalize", financeHandler.FinalizeInvoice)
 		r.Patch("/finance/invoices/{id}/mark-paid", financeHandler.MarkInvoicePaid)
+		r.Post("/finance/adjustments", financeHandler.CreateAdjustment)
+		r.Get("/finance/disputes", financeHandler.ListDisputes)
+		r.Get("/finance/disputes/{id}", financeHandler.GetDispute)
+		r.Post("/finance/disputes/{id}/comments", financeHandler.AddDisputeComment)
+		r.Patch("/finance/disputes/{id}/status", financeHandler.UpdateDisputeStatus)
+		r.Post("/finance/invoices/{id}/notes", financeHandler.IssueNote)
+		r.Get("/finance/invoices/{id}/notes", financeHandler.ListInvoiceNotes)
+		r.Get("/finance/notes/{id}/pdf", financeHandler.GetNotePDF)
+
+		// Settlement runs and vendor payouts (admin)
+		r.Get("/finance/settlement-schedule", financeHandler.GetSettlementSchedule)
+		r.Put("/finance/settlement-schedule", financeHandler.UpdateSettlementSchedule)
+		r.Post("/finance/invoice-runs", financeHandler.RunInvoiceCycle)
+		r.Post("/finance/payout-batches", financeHandler.CreatePayoutBatch)
+		r.Get("/finance/payout-batches", financeHandler.ListPayoutBatches)
+		r.Get("/finance/payout-batches/{id}", financeHandler.GetPayoutBatch)
+		r.Post("/finance/payout-batches/{id}/disbursement.csv", financeHandler.ExportDisbursement)
+		r.Post("/finance/payout-batches/{id}/reconcile", financeHandler.ReconcileStatement)
+		r.Patch("/finance/payouts/{id}/complete", financeHandler.CompletePayout)
+		r.Patch("/finance/payouts/{id}/fail", financeHandler.FailPayout)
 
 		// Issue resolution (admin)
 		r.Patch("/issues/{id}/resolve", issueHandler.ResolveIssue)